@@ -11,6 +11,7 @@ import (
 	_ "github.com/thebtf/engram/docs"
 	"github.com/thebtf/engram/internal/config"
 	"github.com/thebtf/engram/internal/logbuf"
+	"github.com/thebtf/engram/internal/tracing"
 	"github.com/thebtf/engram/internal/worker"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -18,6 +19,9 @@ import (
 
 var Version = "dev"
 
+// restartExitCode is the exit status after a config-triggered restart.
+const restartExitCode = 75 // EX_TEMPFAIL
+
 // @title Engram API
 // @version 1.0.0
 // @description Persistent shared memory infrastructure for AI agents. Stores memories + behavioral rules + credentials in PostgreSQL. REST API over HTTP; MCP tools are served via stdio client proxy only (server-side MCP HTTP transports removed in v5). Note: the host below is the default for local development; set ENGRAM_LISTEN_ADDR to change the listen address in production.
@@ -43,6 +47,14 @@ func main() {
 		Str("version", Version).
 		Msg("Starting engram server")
 
+	shutdownTracing, err := tracing.Init(context.Background(), "engram-server", Version)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize tracing")
+	}
+	if tracing.Enabled() {
+		log.Info().Msg("OpenTelemetry trace export enabled")
+	}
+
 	// Create service with version and log buffer
 	svc, err := worker.NewService(Version, logRing)
 	if err != nil {
@@ -54,12 +66,17 @@ func main() {
 		log.Fatal().Err(err).Msg("Failed to start service")
 	}
 
-	// Wait for shutdown signal
+	// Wait for shutdown signal, or for a settings change that needs a restart
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	log.Info().Msg("Received shutdown signal")
+	restart := false
+	select {
+	case <-quit:
+		log.Info().Msg("Received shutdown signal")
+	case <-svc.RestartRequested():
+		log.Info().Msg("Restart requested by config change")
+		restart = true
+	}
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -68,6 +85,15 @@ func main() {
 	if err := svc.Shutdown(ctx); err != nil {
 		log.Error().Err(err).Msg("Shutdown error")
 	}
+	if err := shutdownTracing(ctx); err != nil {
+		log.Error().Err(err).Msg("Trace flush error")
+	}
 
 	log.Info().Msg("Worker shutdown complete")
+
+	// Exit non-zero so the supervisor (Docker restart policy, systemd
+	// Restart=on-failure, launchd KeepAlive) starts the new configuration.
+	if restart {
+		os.Exit(restartExitCode)
+	}
 }
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/thebtf/engram/pkg/client"
+)
+
+// postCommitMarker identifies the lines `engram capture --install` adds to a
+// post-commit hook, so installing twice is a no-op.
+const postCommitMarker = "# engram: record each commit as a memory"
+
+func runCapture(c *apiClient, args []string) error {
+	fs := newFlagSet("capture")
+	project := fs.String("project", "", "Project ID (default: current directory's project)")
+	session := fs.String("session", "", "Link the commit to this session instead of the project's latest active one")
+	install := fs.Bool("install", false, "Install a post-commit hook in this repository that runs `engram capture` after every commit")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return fmt.Errorf("expected at most one revision")
+	}
+	if *install {
+		return installPostCommitHook()
+	}
+	rev := "HEAD"
+	if len(positional) == 1 {
+		rev = positional[0]
+	}
+
+	p, err := projectOrDefault(*project)
+	if err != nil {
+		return err
+	}
+	req, err := commitFromGit(rev)
+	if err != nil {
+		return err
+	}
+	req.Project = p
+	req.SessionID = *session
+
+	mem, err := c.CaptureCommit(context.Background(), req)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Recorded commit %.7s as memory %d.\n", req.SHA, mem.ID)
+	return nil
+}
+
+// git runs a git command in the current directory and returns its stdout.
+func git(args ...string) (string, error) {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
+
+// commitFromGit reads a commit's hash, message, changed files and diff stat.
+// Merges are diffed against their first parent. The branch is only set for
+// HEAD, the commit a post-commit hook reports.
+func commitFromGit(rev string) (client.CaptureCommitRequest, error) {
+	var req client.CaptureCommitRequest
+	sha, err := git("rev-parse", "--verify", rev+"^{commit}")
+	if err != nil {
+		return req, err
+	}
+	req.SHA = strings.TrimSpace(sha)
+	if req.Message, err = git("log", "-1", "--format=%B", req.SHA); err != nil {
+		return req, err
+	}
+	if rev == "HEAD" {
+		if branch, err := git("branch", "--show-current"); err == nil {
+			req.Branch = strings.TrimSpace(branch)
+		}
+	}
+
+	numstat, err := git("diff-tree", "-r", "--root", "-m", "--first-parent", "--no-commit-id", "--numstat", "--no-renames", "-z", req.SHA)
+	if err != nil {
+		return req, err
+	}
+	for _, line := range strings.Split(numstat, "\x00") {
+		// "<added>\t<deleted>\t<path>", with "-" counts for binary files.
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		added, _ := strconv.Atoi(fields[0])
+		deleted, _ := strconv.Atoi(fields[1])
+		req.Insertions += added
+		req.Deletions += deleted
+		req.Files = append(req.Files, fields[2])
+	}
+	return req, nil
+}
+
+// installPostCommitHook adds `engram capture` to the repository's
+// post-commit hook (creating it if needed), run in the background so
+// commits never wait on the server.
+func installPostCommitHook() error {
+	dir, err := git("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return err
+	}
+	dir = strings.TrimSpace(dir)
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, "post-commit")
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if bytes.Contains(existing, []byte(postCommitMarker)) {
+		fmt.Printf("The post-commit hook in %s already runs engram capture.\n", path)
+		return nil
+	}
+
+	hook := existing
+	if len(hook) == 0 {
+		hook = []byte("#!/bin/sh\n")
+	} else if hook[len(hook)-1] != '\n' {
+		hook = append(hook, '\n')
+	}
+	hook = append(hook, fmt.Sprintf("%s\n%s capture >/dev/null 2>&1 &\n", postCommitMarker, strconv.Quote(filepath.ToSlash(exe)))...)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, hook, 0o755); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file; hooks must be executable.
+	if err := os.Chmod(path, 0o755); err != nil {
+		return err
+	}
+	fmt.Printf("Installed the post-commit hook in %s.\n", path)
+	return nil
+}
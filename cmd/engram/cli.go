@@ -0,0 +1,921 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/thebtf/engram/internal/benchmark"
+	"github.com/thebtf/engram/internal/brief"
+	"github.com/thebtf/engram/internal/config"
+	"github.com/thebtf/engram/internal/memimport"
+	"github.com/thebtf/engram/internal/obsidian"
+	"github.com/thebtf/engram/internal/proxy"
+	"github.com/thebtf/engram/pkg/client"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// cliCommand is a memory-management subcommand. The daemon is the default
+// mode; `engram <subcommand>` runs one of these against the server HTTP API
+// (the same ENGRAM_URL / ENGRAM_TOKEN the daemon uses) and exits.
+type cliCommand struct {
+	run     func(c *apiClient, args []string) error
+	usage   string
+	summary string
+}
+
+// cliCommands is the subcommand table consulted by main before the daemon starts.
+// Populated in init because the handlers refer back to it for usage text.
+var cliCommands map[string]cliCommand
+
+func init() {
+	cliCommands = map[string]cliCommand{
+		"search":  {runSearch, "search <query> [--project P] [--limit N] [--json]", "Search memories relevant to a query"},
+		"show":    {runShow, "show <id> [--json]", "Show a single memory"},
+		"edit":    {runEdit, "edit <id> [--content TEXT | --file PATH] [--tags a,b]", "Edit a memory (opens $EDITOR when no content is given)"},
+		"delete":  {runDelete, "delete <id> [--yes]", "Delete a memory"},
+		"export":  {runExport, "export [--project P] [--limit N] [--out FILE] [--format json|obsidian] [--sync]", "Export a project's memories as JSON or as an Obsidian vault"},
+		"brief":   {runBrief, "brief [--project P] [--limit N] [--per-section N] [--out FILE]", "Write a Markdown onboarding brief of a project's memories"},
+		"import":  {runImport, "import <file|-> [--project P] [--format engram|mem0|letta|claude-memory|json]", "Import memories from an export file of engram or another memory tool"},
+		"capture": {runCapture, "capture [REV] [--project P] [--session ID] | capture --install", "Record a git commit (default HEAD) as a memory, or install a post-commit hook that does"},
+		"stats":   {runStats, "stats [--project P] [--json]", "Show server statistics"},
+		"backup":  {runBackup, "backup [--list] [--json]", "Back up the server to its remote backup target now (admin)"},
+		"restore": {runRestore, "restore <name|latest> [--yes] [--json]", "Restore the server from a remote backup (admin)"},
+		"doctor":  {runDoctor, "doctor", "Check server connectivity, auth and project identity"},
+		"bench":   {runBench, "bench [--memories N] [--queries N] [--concurrency N] [--project P] [--keep] [--json] [--baseline FILE] [--tolerance F]", "Measure server latency percentiles on a synthetic dataset"},
+	}
+}
+
+// runCLI executes the named subcommand and returns the process exit code.
+func runCLI(name string, args []string) int {
+	cmd := cliCommands[name]
+	if err := cmd.run(newAPIClientFromEnv(), args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "engram %s: %v\n", name, err)
+		return 1
+	}
+	return 0
+}
+
+// printCLIUsage lists the subcommands for --help.
+func printCLIUsage() {
+	names := make([]string, 0, len(cliCommands))
+	for name := range cliCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Println("Commands:")
+	for _, name := range names {
+		fmt.Printf("  %-8s  %s\n", name, cliCommands[name].summary)
+	}
+}
+
+// apiClient is the typed server client plus the keycard it was built with,
+// which doctor reports on.
+type apiClient struct {
+	*client.Client
+	token string
+}
+
+// newAPIClientFromEnv resolves the server URL and keycard the same way the
+// daemon does (EnvServerURL, falling back to EnvServerURLAlt).
+func newAPIClientFromEnv() *apiClient {
+	base := os.Getenv(config.EnvServerURL)
+	if base == "" {
+		base = os.Getenv(config.EnvServerURLAlt)
+	}
+	token := os.Getenv(config.EnvWorkstationToken)
+	return &apiClient{
+		Client: client.New(base, client.WithToken(token)),
+		token:  token,
+	}
+}
+
+// parseFlags parses fs allowing flags before, between and after positional
+// arguments (the standard library stops at the first positional).
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// newFlagSet returns a FlagSet whose usage line comes from cliCommands.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: engram %s\n", cliCommands[name].usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseID parses the single positional memory ID argument.
+func parseID(positional []string) (int64, error) {
+	if len(positional) != 1 {
+		return 0, fmt.Errorf("expected exactly one memory id")
+	}
+	id, err := strconv.ParseInt(positional[0], 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid memory id %q", positional[0])
+	}
+	return id, nil
+}
+
+// defaultProject resolves the project of the current directory exactly like
+// the daemon and hooks do, so CLI results match what the agent sees.
+func defaultProject() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	id, _, _, err := proxy.ResolveProjectSlug(cwd)
+	return id, err
+}
+
+// projectOrDefault returns p when set, otherwise the current directory's project.
+func projectOrDefault(p string) (string, error) {
+	if p != "" {
+		return p, nil
+	}
+	project, err := defaultProject()
+	if err != nil {
+		return "", fmt.Errorf("resolve project (pass --project): %w", err)
+	}
+	return project, nil
+}
+
+// printJSON writes v as indented JSON to stdout.
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// truncate shortens s to its first line and at most n runes.
+func truncate(s string, n int) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i] + " …"
+	}
+	r := []rune(s)
+	if len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+func runSearch(c *apiClient, args []string) error {
+	fs := newFlagSet("search")
+	project := fs.String("project", "", "Project ID (default: current directory's project)")
+	limit := fs.Int("limit", 20, "Maximum number of results")
+	asJSON := fs.Bool("json", false, "Print the raw JSON response")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	query := strings.TrimSpace(strings.Join(positional, " "))
+	if query == "" {
+		return fmt.Errorf("query is required")
+	}
+	p, err := projectOrDefault(*project)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if *asJSON {
+		var raw json.RawMessage
+		body := client.SearchRequest{Project: p, Query: query}
+		if err := c.Do(ctx, http.MethodPost, "/api/context/search?limit="+strconv.Itoa(*limit), body, &raw); err != nil {
+			return err
+		}
+		return printJSON(raw)
+	}
+	resp, err := c.Search(ctx, client.SearchRequest{Project: p, Query: query, Limit: *limit})
+	if err != nil {
+		return err
+	}
+	if len(resp.Observations) == 0 {
+		fmt.Println("No results.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTYPE\tSCORE\tTITLE")
+	for _, obs := range resp.Observations {
+		title := obs.Title
+		if title == "" {
+			title = obs.Narrative
+		}
+		score := ""
+		if obs.Similarity != 0 {
+			score = fmt.Sprintf("%.2f", obs.Similarity)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", obs.ID, obs.Type, score, truncate(title, 80))
+	}
+	return tw.Flush()
+}
+
+func runShow(c *apiClient, args []string) error {
+	fs := newFlagSet("show")
+	asJSON := fs.Bool("json", false, "Print JSON")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(positional)
+	if err != nil {
+		return err
+	}
+
+	mem, err := c.GetMemory(context.Background(), id)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(mem)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID:\t%d\n", mem.ID)
+	fmt.Fprintf(tw, "Project:\t%s\n", mem.Project)
+	fmt.Fprintf(tw, "Version:\t%d\n", mem.Version)
+	fmt.Fprintf(tw, "Tags:\t%s\n", strings.Join(mem.Tags, ", "))
+	if mem.SourceAgent != "" {
+		fmt.Fprintf(tw, "Source:\t%s\n", mem.SourceAgent)
+	}
+	if mem.EditedBy != "" {
+		fmt.Fprintf(tw, "Edited by:\t%s\n", mem.EditedBy)
+	}
+	fmt.Fprintf(tw, "Created:\t%s\n", mem.CreatedAt.Local().Format(time.RFC3339))
+	fmt.Fprintf(tw, "Updated:\t%s\n", mem.UpdatedAt.Local().Format(time.RFC3339))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Println(mem.Content)
+	return nil
+}
+
+func runEdit(c *apiClient, args []string) error {
+	fs := newFlagSet("edit")
+	content := fs.String("content", "", "New content")
+	file := fs.String("file", "", "Read new content from FILE (- for stdin)")
+	tags := fs.String("tags", "", "Replace tags with this comma-separated list")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(positional)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	update := client.UpdateMemoryRequest{EditedBy: "cli"}
+	switch {
+	case *content != "":
+		update.Content = content
+	case *file != "":
+		data, err := readInput(*file)
+		if err != nil {
+			return err
+		}
+		text := string(data)
+		update.Content = &text
+	case *tags == "":
+		// Nothing on the command line: edit the current content interactively.
+		mem, err := c.GetMemory(ctx, id)
+		if err != nil {
+			return err
+		}
+		edited, err := editInEditor(mem.Content)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(edited) == strings.TrimSpace(mem.Content) {
+			fmt.Println("No changes.")
+			return nil
+		}
+		update.Content = &edited
+	}
+	if *tags != "" {
+		list := []string{}
+		for _, t := range strings.Split(*tags, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				list = append(list, t)
+			}
+		}
+		update.Tags = &list
+	}
+
+	mem, err := c.UpdateMemory(ctx, id, update)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Memory %d updated (version %d).\n", mem.ID, mem.Version)
+	return nil
+}
+
+// editInEditor opens content in $VISUAL / $EDITOR and returns the saved text.
+func editInEditor(content string) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		return "", fmt.Errorf("no --content/--file given and $EDITOR is not set")
+	}
+
+	f, err := os.CreateTemp("", "engram-memory-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	// $EDITOR may carry arguments (e.g. "code --wait").
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], f.Name())...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor: %w", err)
+	}
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// readInput reads a whole file, or stdin when path is "-".
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func runDelete(c *apiClient, args []string) error {
+	fs := newFlagSet("delete")
+	yes := fs.Bool("yes", false, "Do not ask for confirmation")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(positional)
+	if err != nil {
+		return err
+	}
+
+	if !*yes {
+		fmt.Printf("Delete memory %d? [y/N] ", id)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	if err := c.DeleteMemory(context.Background(), id); err != nil {
+		return err
+	}
+	fmt.Printf("Memory %d deleted.\n", id)
+	return nil
+}
+
+func runExport(c *apiClient, args []string) error {
+	fs := newFlagSet("export")
+	project := fs.String("project", "", "Project ID (default: current directory's project)")
+	limit := fs.Int("limit", 500, "Maximum number of memories (server max 500)")
+	out := fs.String("out", "", "Write to FILE instead of stdout (obsidian: the vault directory)")
+	format := fs.String("format", "json", "Output format: json, or obsidian for one Markdown note per memory")
+	sync := fs.Bool("sync", false, "obsidian: remove notes of memories that no longer exist")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *format != "json" && *format != "obsidian" {
+		return fmt.Errorf("unknown format %q (want json or obsidian)", *format)
+	}
+	if *format == "obsidian" && *out == "" {
+		return fmt.Errorf("--format obsidian needs --out DIR")
+	}
+	p, err := projectOrDefault(*project)
+	if err != nil {
+		return err
+	}
+
+	mems, err := c.ListMemories(context.Background(), p, *limit)
+	if err != nil {
+		return err
+	}
+	if *format == "obsidian" {
+		return exportObsidian(c, p, mems, *out, *sync)
+	}
+
+	data, err := json.MarshalIndent(mems, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if *out == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*out, data, 0o600); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d memories from %s to %s\n", len(mems), p, *out)
+	return nil
+}
+
+// exportObsidian writes mems as an Obsidian vault into dir, linking notes
+// through the relations between the project's memories.
+func exportObsidian(c *apiClient, project string, mems []models.Memory, dir string, sync bool) error {
+	relations, err := c.ListMemoryRelations(context.Background(), project)
+	if err != nil {
+		return err
+	}
+	ptrs := make([]*models.Memory, len(mems))
+	for i := range mems {
+		ptrs[i] = &mems[i]
+	}
+	res, err := obsidian.Export(dir, ptrs, relations, obsidian.Options{Sync: sync})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d memories from %s to %s: %d written, %d unchanged, %d removed\n",
+		len(mems), project, dir, res.Written, res.Unchanged, res.Removed)
+	return nil
+}
+
+func runBrief(c *apiClient, args []string) error {
+	fs := newFlagSet("brief")
+	project := fs.String("project", "", "Project ID (default: current directory's project)")
+	limit := fs.Int("limit", 500, "Most recent memories to draw from (server max 500)")
+	perSection := fs.Int("per-section", brief.DefaultMaxPerSection, "Memories listed per section")
+	out := fs.String("out", "", "Write to FILE instead of stdout")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	p, err := projectOrDefault(*project)
+	if err != nil {
+		return err
+	}
+
+	mems, err := c.ListMemories(context.Background(), p, *limit)
+	if err != nil {
+		return err
+	}
+	ptrs := make([]*models.Memory, len(mems))
+	for i := range mems {
+		ptrs[i] = &mems[i]
+	}
+	doc := brief.Build(p, ptrs, brief.Options{MaxPerSection: *perSection})
+	if *out == "" {
+		_, err = io.WriteString(os.Stdout, doc)
+		return err
+	}
+	if err := os.WriteFile(*out, []byte(doc), 0o600); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote the brief of %s (%d memories) to %s\n", p, len(mems), *out)
+	return nil
+}
+
+func runImport(c *apiClient, args []string) error {
+	fs := newFlagSet("import")
+	project := fs.String("project", "", "Import into this project instead of each memory's own (default for other tools: current directory's project)")
+	format := fs.String("format", string(memimport.FormatEngram), "Input format: engram, mem0, letta, claude-memory or json")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected exactly one input file (- for stdin)")
+	}
+	data, err := readInput(positional[0])
+	if err != nil {
+		return err
+	}
+
+	mems, err := memimport.Parse(memimport.Format(*format), data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", positional[0], err)
+	}
+
+	var fallback string
+	for _, mem := range mems {
+		if mem.Project == "" && *project == "" {
+			if fallback, err = projectOrDefault(""); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	imported, failed := 0, 0
+	for i, mem := range mems {
+		p := mem.Project
+		if *project != "" {
+			p = *project
+		}
+		if p == "" {
+			p = fallback
+		}
+		req := client.StoreMemoryRequest{
+			Project:     p,
+			Content:     mem.Content,
+			Tags:        mem.Tags,
+			SourceAgent: mem.SourceAgent,
+		}
+		if _, err := c.StoreMemory(context.Background(), req); err != nil {
+			fmt.Fprintf(os.Stderr, "  memory %d: %v\n", importedID(mem, i), err)
+			failed++
+			continue
+		}
+		imported++
+	}
+	fmt.Printf("Imported %d memories (%d failed).\n", imported, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d memories failed to import", failed)
+	}
+	return nil
+}
+
+// importedID names a memory in import errors: its exported ID, or its
+// 1-based position when the source tool has no numeric IDs.
+func importedID(mem models.Memory, i int) int64 {
+	if mem.ID != 0 {
+		return mem.ID
+	}
+	return int64(i + 1)
+}
+
+func runStats(c *apiClient, args []string) error {
+	fs := newFlagSet("stats")
+	project := fs.String("project", "", "Include the count for this project")
+	asJSON := fs.Bool("json", false, "Print the raw JSON response")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	stats, err := c.Stats(context.Background(), *project)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(stats)
+	}
+
+	keys := make([]string, 0, len(stats))
+	for k, v := range stats {
+		switch v.(type) {
+		case map[string]any, []any:
+			continue // nested sections are only shown with --json
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, k := range keys {
+		fmt.Fprintf(tw, "%s\t%v\n", k, stats[k])
+	}
+	if db, ok := stats["database"].(map[string]any); ok {
+		fmt.Fprintf(tw, "database\t%v\n", db["status"])
+	}
+	return tw.Flush()
+}
+
+// backupTimeout bounds backup and restore requests, which move the whole
+// database and outlast client.DefaultTimeout.
+const backupTimeout = 30 * time.Minute
+
+// slowClient returns a copy of c whose requests may take up to timeout.
+func (c *apiClient) slowClient(timeout time.Duration) *client.Client {
+	return client.New(c.BaseURL(), client.WithToken(c.token), client.WithHTTPClient(&http.Client{Timeout: timeout}))
+}
+
+// backupInfo is one entry of GET /api/admin/backups.
+type backupInfo struct {
+	Modified time.Time `json:"modified"`
+	Name     string    `json:"name"`
+	Size     int64     `json:"size"`
+}
+
+// backupTables is the per-table summary of a backup or restore.
+type backupTables map[string]struct {
+	Rows     int64 `json:"rows"`
+	Restored int64 `json:"restored"`
+	Skipped  int64 `json:"skipped"`
+}
+
+func runBackup(c *apiClient, args []string) error {
+	fs := newFlagSet("backup")
+	list := fs.Bool("list", false, "List the backups on the target instead of taking one")
+	asJSON := fs.Bool("json", false, "Print the raw JSON response")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if *list {
+		var resp struct {
+			Target  string       `json:"target"`
+			Backups []backupInfo `json:"backups"`
+		}
+		var raw json.RawMessage
+		if err := c.Do(ctx, http.MethodGet, "/api/admin/backups", nil, &raw); err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(raw)
+		}
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			return err
+		}
+		if len(resp.Backups) == 0 {
+			fmt.Printf("No backups on %s.\n", resp.Target)
+			return nil
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tSIZE\tMODIFIED")
+		for _, b := range resp.Backups {
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", b.Name, b.Size, b.Modified.Local().Format(time.DateTime))
+		}
+		return tw.Flush()
+	}
+
+	var raw json.RawMessage
+	if err := c.slowClient(backupTimeout).Do(ctx, http.MethodPost, "/api/admin/backups", nil, &raw); err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(raw)
+	}
+	var res struct {
+		Tables    backupTables `json:"tables"`
+		Name      string       `json:"name"`
+		Target    string       `json:"target"`
+		Deleted   []string     `json:"deleted"`
+		Size      int64        `json:"size"`
+		Encrypted bool         `json:"encrypted"`
+	}
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return err
+	}
+	var rows int64
+	for _, t := range res.Tables {
+		rows += t.Rows
+	}
+	note := ""
+	if !res.Encrypted {
+		note = " (unencrypted: set ENGRAM_BACKUP_PASSPHRASE on the server)"
+	}
+	fmt.Printf("Backed up %d rows from %d tables to %s/%s, %d bytes%s.\n",
+		rows, len(res.Tables), res.Target, res.Name, res.Size, note)
+	for _, name := range res.Deleted {
+		fmt.Printf("Deleted old backup %s.\n", name)
+	}
+	return nil
+}
+
+func runRestore(c *apiClient, args []string) error {
+	fs := newFlagSet("restore")
+	yes := fs.Bool("yes", false, "Do not ask for confirmation")
+	asJSON := fs.Bool("json", false, "Print the raw JSON response")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return fmt.Errorf("exactly one backup name (or latest) is required")
+	}
+	name := positional[0]
+
+	if !*yes {
+		fmt.Printf("Restore backup %s into %s? Existing rows are kept. [y/N] ", name, c.BaseURL())
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	var raw json.RawMessage
+	body := map[string]string{"name": name}
+	if err := c.slowClient(backupTimeout).Do(context.Background(), http.MethodPost, "/api/admin/backups/restore", body, &raw); err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(raw)
+	}
+	var res struct {
+		Tables backupTables `json:"tables"`
+		Name   string       `json:"name"`
+	}
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return err
+	}
+	tables := make([]string, 0, len(res.Tables))
+	for t := range res.Tables {
+		tables = append(tables, t)
+	}
+	sort.Strings(tables)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TABLE\tROWS\tRESTORED\tKEPT")
+	for _, t := range tables {
+		st := res.Tables[t]
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\n", t, st.Rows, st.Restored, st.Skipped)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("Restored %s.\n", res.Name)
+	return nil
+}
+
+func runDoctor(c *apiClient, args []string) error {
+	fs := newFlagSet("doctor")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	failures := 0
+	check := func(name string, err error, detail string) {
+		if err != nil {
+			failures++
+			fmt.Printf("✗ %-14s %v\n", name, err)
+			return
+		}
+		fmt.Printf("✓ %-14s %s\n", name, detail)
+	}
+
+	ctx := context.Background()
+	check("server url", nil, c.BaseURL())
+
+	var tokenErr error
+	if !c.HasToken() {
+		tokenErr = fmt.Errorf("%s is not set (issue a keycard at %s/tokens)", config.EnvWorkstationToken, c.BaseURL())
+	}
+	check("token", tokenErr, "set")
+
+	health, err := c.Health(ctx)
+	if err != nil {
+		health = &client.Health{}
+	}
+	check("reachable", err, fmt.Sprintf("status=%s version=%s (client %s)", health.Status, health.Version, daemonVersion))
+
+	if err == nil {
+		check("ready", c.Ready(ctx), "database initialised")
+
+		me, authErr := c.Me(ctx)
+		role := ""
+		if me != nil {
+			role = me.Role
+		}
+		check("auth", authErr, "role="+role)
+	}
+
+	project, err := defaultProject()
+	check("project", err, project)
+
+	if failures > 0 {
+		return fmt.Errorf("%d check(s) failed", failures)
+	}
+	return nil
+}
+
+func runBench(c *apiClient, args []string) error {
+	fs := newFlagSet("bench")
+	memories := fs.Int("memories", 10000, "Synthetic memories to seed into a throwaway project (e.g. 10000, 100000, 1000000)")
+	queries := fs.Int("queries", 200, "Measured requests per read scenario")
+	concurrency := fs.Int("concurrency", 8, "Parallel requests while seeding and cleaning up")
+	project := fs.String("project", "", "Benchmark this existing project instead of seeding one")
+	keep := fs.Bool("keep", false, "Keep the seeded memories")
+	asJSON := fs.Bool("json", false, "Print the results as JSON (usable as a --baseline)")
+	baseline := fs.String("baseline", "", "Fail when a p95 regresses against this --json output")
+	tolerance := fs.Float64("tolerance", 0.2, "Allowed p95 regression against the baseline (0.2 = 20%)")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	var base []benchmark.Result
+	if *baseline != "" {
+		data, err := os.ReadFile(*baseline)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &base); err != nil {
+			return fmt.Errorf("parse %s: expected the output of `engram bench --json`: %w", *baseline, err)
+		}
+	}
+
+	opts := benchmark.Options{
+		Project:     *project,
+		Memories:    *memories,
+		Queries:     *queries,
+		Concurrency: *concurrency,
+		Seed:        time.Now().UnixNano(),
+		Keep:        *keep,
+	}
+	results, err := benchmark.Run(context.Background(), benchTarget{c}, opts, func(msg string) {
+		fmt.Fprintln(os.Stderr, msg)
+	})
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		if err := printJSON(results); err != nil {
+			return err
+		}
+	} else {
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "SCENARIO\tN\tERRORS\tP50\tP95\tP99\tMAX\tMEAN")
+		for _, r := range results {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\n", r.Scenario, r.Count, r.Errors,
+				r.P50.Round(time.Microsecond), r.P95.Round(time.Microsecond), r.P99.Round(time.Microsecond),
+				r.Max.Round(time.Microsecond), r.Mean.Round(time.Microsecond))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if regressions := benchmark.Compare(base, results, *tolerance); len(regressions) > 0 {
+		for _, msg := range regressions {
+			fmt.Fprintln(os.Stderr, "regression: "+msg)
+		}
+		return fmt.Errorf("%d scenario(s) regressed against %s", len(regressions), *baseline)
+	}
+	return nil
+}
+
+// benchTarget runs benchmark scenarios against the server HTTP API. Seeded
+// memories are private, so neither other agents nor outbound webhooks see
+// them.
+type benchTarget struct {
+	c *apiClient
+}
+
+func (t benchTarget) StoreMemory(ctx context.Context, project, content string, tags []string) (int64, error) {
+	mem, err := t.c.StoreMemory(ctx, client.StoreMemoryRequest{
+		Project:     project,
+		Content:     content,
+		Tags:        tags,
+		SourceAgent: "engram-bench",
+		Visibility:  models.VisibilityPrivate,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return mem.ID, nil
+}
+
+func (t benchTarget) DeleteMemory(ctx context.Context, id int64) error {
+	return t.c.DeleteMemory(ctx, id)
+}
+
+func (t benchTarget) Search(ctx context.Context, project, query string) error {
+	_, err := t.c.Search(ctx, client.SearchRequest{Project: project, Query: query})
+	return err
+}
+
+func (t benchTarget) ListMemories(ctx context.Context, project string) error {
+	_, err := t.c.ListMemories(ctx, project, 0)
+	return err
+}
+
+func (t benchTarget) SessionStart(ctx context.Context, project string) error {
+	_, err := t.c.SessionStartContext(ctx, project)
+	return err
+}
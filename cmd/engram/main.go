@@ -67,8 +67,11 @@ func main() {
 	if len(os.Args) > 1 && (os.Args[1] == "--help" || os.Args[1] == "-h" || os.Args[1] == "--version" || os.Args[1] == "-v") {
 		fmt.Printf("engram %s — stdio MCP daemon for Claude Code\n", daemonVersion)
 		fmt.Println()
-		fmt.Println("This binary is invoked automatically by the engram plugin.")
-		fmt.Println("It is not intended to be run directly.")
+		fmt.Println("Without arguments this binary runs the stdio MCP daemon; it is invoked")
+		fmt.Println("automatically by the engram plugin. With a command it manages memories")
+		fmt.Println("through the server HTTP API:  engram <command> [flags]")
+		fmt.Println()
+		printCLIUsage()
 		fmt.Println()
 		fmt.Println("Environment:")
 		fmt.Printf("  %-28s  Server URL (e.g. http://host:37777)\n", config.EnvServerURL)
@@ -76,6 +79,14 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Memory-management subcommands run against the server and exit; they
+	// never start the daemon (and so skip the daemon's startup gate).
+	if len(os.Args) > 1 {
+		if _, ok := cliCommands[os.Args[1]]; ok {
+			os.Exit(runCLI(os.Args[1], os.Args[2:]))
+		}
+	}
+
 	// FR-4 / ADR-005: fail-fast on missing workstation credential BEFORE
 	// any heavy initialisation. Loud failure beats silent loom_*-only
 	// graceful degradation that masked PR #203's regression for days.
@@ -1,6 +1,10 @@
 package auth
 
-import "context"
+import (
+	"context"
+
+	"github.com/thebtf/engram/internal/tenant"
+)
 
 // Context-key types. Both are unexported as type definitions but exported as
 // VALUE singletons (RoleKey, SourceKey) so other packages can read context
@@ -34,8 +38,12 @@ var IdentityKey = identityKeyType{}
 // to be called once per request after Validator.Validate (or after a
 // session-cookie path resolves the user role). The full Identity (including
 // KeycardID for SourceClient) is preserved so issuance audit / stats handlers
-// can attribute requests to a specific keycard.
+// can attribute requests to a specific keycard. The identity's tenant and
+// actor are stamped alongside it (tenant.WithTenant, tenant.WithOwner) so
+// stores scope user data by them.
 func WithIdentity(ctx context.Context, id Identity) context.Context {
+	ctx = tenant.WithTenant(ctx, id.Tenant)
+	ctx = tenant.WithOwner(ctx, id.Actor())
 	return context.WithValue(ctx, IdentityKey, id)
 }
 
@@ -65,3 +73,15 @@ func SourceFrom(ctx context.Context) string {
 	}
 	return ""
 }
+
+// ActorFrom returns a stable label for the principal behind ctx, for audit
+// trails: "operator" for the operator key (as bearer or browser login),
+// "keycard:<id>" for a client keycard, "user:<id>" for a logged-in user, and
+// "anonymous" when no Identity is set (authentication disabled).
+func ActorFrom(ctx context.Context) string {
+	id, ok := IdentityFrom(ctx)
+	if !ok {
+		return "anonymous"
+	}
+	return id.Actor()
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/thebtf/engram/internal/auth"
+	"github.com/thebtf/engram/internal/tenant"
 )
 
 func TestWithIdentity_RoundTrip(t *testing.T) {
@@ -20,6 +21,29 @@ func TestWithIdentity_RoundTrip(t *testing.T) {
 	assert.Equal(t, auth.SourceClient, got.Source)
 }
 
+func TestWithIdentity_StampsTenant(t *testing.T) {
+	t.Parallel()
+	ctx := auth.WithIdentity(context.Background(), auth.Client("read-write", "uuid-1").InTenant("team-a"))
+
+	got, ok := auth.IdentityFrom(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "team-a", got.Tenant)
+	assert.Equal(t, "team-a", tenant.FromContext(ctx))
+
+	// Operator-key identities act in the default tenant.
+	assert.Equal(t, tenant.Default, tenant.FromContext(auth.WithIdentity(context.Background(), auth.Admin())))
+}
+
+func TestWithIdentity_StampsOwner(t *testing.T) {
+	t.Parallel()
+	owner, ok := tenant.OwnerFromContext(auth.WithIdentity(context.Background(), auth.Client("read-write", "uuid-1")))
+	assert.True(t, ok)
+	assert.Equal(t, "keycard:uuid-1", owner)
+
+	owner, _ = tenant.OwnerFromContext(auth.WithIdentity(context.Background(), auth.Admin()))
+	assert.Equal(t, "operator", owner)
+}
+
 func TestIdentityFrom_NoIdentity(t *testing.T) {
 	t.Parallel()
 
@@ -80,3 +104,14 @@ func TestIdentity_IsSessionAdmin_RejectsSessionNonAdmin(t *testing.T) {
 
 	assert.False(t, id.IsSessionAdmin())
 }
+
+func TestActorFrom(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	assert.Equal(t, "anonymous", auth.ActorFrom(ctx))
+	assert.Equal(t, "operator", auth.ActorFrom(auth.WithIdentity(ctx, auth.Admin())))
+	assert.Equal(t, "keycard:uuid-1", auth.ActorFrom(auth.WithIdentity(ctx, auth.Client("read-write", "uuid-1"))))
+	assert.Equal(t, "operator", auth.ActorFrom(auth.WithIdentity(ctx, auth.Session("admin"))))
+	assert.Equal(t, "user:42", auth.ActorFrom(auth.WithIdentity(ctx, auth.Session("operator").ForUser(42))))
+}
@@ -3,6 +3,8 @@
 // source of truth for token-based authentication across HTTP and gRPC transports.
 package auth
 
+import "strconv"
+
 // Source identifies the authentication path that produced an Identity. It is set
 // by the validator (for header/Bearer paths) or by middleware directly (for
 // session-cookie / forward-auth paths).
@@ -57,6 +59,17 @@ type Identity struct {
 	// KeycardID is the api_tokens.id (UUID) when Source == SourceClient.
 	// Empty string for SourceMaster and SourceSession.
 	KeycardID string
+
+	// UserID is the users.id of the logged-in user when Source ==
+	// SourceSession and the login was by email/password or forward-auth.
+	// Zero for the operator-key (engram_session) login and other sources.
+	UserID int64
+
+	// Tenant is the api_tokens.tenant of the keycard when Source ==
+	// SourceClient. Operator-key and session identities act in
+	// tenant.Default. WithIdentity stamps it on the context so stores scope
+	// user data by it.
+	Tenant string
 }
 
 // Admin returns an Identity for a successful master-token match.
@@ -71,6 +84,12 @@ func Client(scope string, keycardID string) Identity {
 	return Identity{Role: Role(scope), Source: SourceClient, KeycardID: keycardID}
 }
 
+// InTenant returns a copy of i scoped to tenant t.
+func (i Identity) InTenant(t string) Identity {
+	i.Tenant = t
+	return i
+}
+
 // Session returns an Identity for a successful session-cookie authentication.
 // role is the resolved role string ("admin" for HMAC cookie, or the user role
 // for engram_auth cookie).
@@ -78,6 +97,13 @@ func Session(role string) Identity {
 	return Identity{Role: Role(role), Source: SourceSession}
 }
 
+// ForUser returns a copy of i attributed to the logged-in user with users.id
+// userID.
+func (i Identity) ForUser(userID int64) Identity {
+	i.UserID = userID
+	return i
+}
+
 // IsAdmin reports whether the bearer holds admin role regardless of source.
 // Issuance endpoints MUST additionally check Source == SourceSession (use
 // IsSessionAdmin), not this method.
@@ -85,6 +111,24 @@ func (i Identity) IsAdmin() bool {
 	return i.Role == RoleAdmin
 }
 
+// Actor returns the stable label of the principal behind the identity (see
+// ActorFrom).
+func (i Identity) Actor() string {
+	switch i.Source {
+	case SourceMaster:
+		return "operator"
+	case SourceClient:
+		return "keycard:" + i.KeycardID
+	case SourceSession:
+		if i.UserID != 0 {
+			return "user:" + strconv.FormatInt(i.UserID, 10)
+		}
+		return "operator" // logged in with the operator key
+	default:
+		return string(i.Source)
+	}
+}
+
 // IsSessionAdmin is the gate for issuance/revocation endpoints (FR-6 / C4).
 // True only when both Role is admin AND Source is a browser session.
 func (i Identity) IsSessionAdmin() bool {
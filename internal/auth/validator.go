@@ -170,7 +170,7 @@ func (v *Validator) Validate(ctx context.Context, raw string) (Identity, error)
 			// Whitelist the two values issuance is allowed to write.
 			switch Role(candidates[i].Scope) {
 			case RoleReadWrite, RoleReadOnly:
-				return Client(candidates[i].Scope, candidates[i].ID), nil
+				return Client(candidates[i].Scope, candidates[i].ID).InTenant(candidates[i].Tenant), nil
 			default:
 				return Identity{}, fmt.Errorf(
 					"auth: keycard %s has unexpected scope %q (allowed: %q, %q)",
@@ -1,5 +1,3 @@
-//go:build benchmark
-
 package benchmark
 
 import (
@@ -9,6 +7,7 @@ import (
 	"time"
 )
 
+// Histogram collects latency samples and reports their distribution.
 type Histogram struct {
 	samples []time.Duration
 }
@@ -92,6 +91,11 @@ func (h *Histogram) Print(label string) {
 	fmt.Printf("%-30s p50=%8s p95=%8s p99=%8s max=%8s mean=%8s n=%d\n", label, p50, p95, p99, max, mean, len(h.samples))
 }
 
+// Len returns the number of samples.
+func (h *Histogram) Len() int {
+	return len(h.samples)
+}
+
 func (h *Histogram) sortedSamples() []time.Duration {
 	copySamples := append([]time.Duration(nil), h.samples...)
 	sort.Slice(copySamples, func(i, j int) bool {
@@ -0,0 +1,266 @@
+// Package benchmark measures engram's latency. Run drives the v5 read paths
+// (search, memory listing and session-start context) and memory storage of a
+// running server against a synthetic dataset; the benchmark-tagged suite in
+// this package measures raw PostgreSQL query shapes.
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scenario names, in the order Run reports them.
+const (
+	ScenarioStore        = "store"
+	ScenarioSearch       = "search"
+	ScenarioList         = "list"
+	ScenarioSessionStart = "session_start"
+)
+
+// warmupQueries are run per read scenario before measuring.
+const warmupQueries = 10
+
+// Target is the server API a benchmark drives.
+type Target interface {
+	StoreMemory(ctx context.Context, project, content string, tags []string) (int64, error)
+	DeleteMemory(ctx context.Context, id int64) error
+	Search(ctx context.Context, project, query string) error
+	ListMemories(ctx context.Context, project string) error
+	SessionStart(ctx context.Context, project string) error
+}
+
+// Options controls Run.
+type Options struct {
+	// Project is benchmarked as is, without seeding. When empty, Memories
+	// synthetic memories are stored in a throwaway project that is deleted
+	// afterwards unless Keep is set.
+	Project string
+	// Memories is the synthetic dataset size (e.g. 10k, 100k or 1M).
+	Memories int
+	// Queries is the number of measured requests per read scenario.
+	Queries int
+	// Concurrency is the number of parallel writers while seeding and
+	// cleaning up.
+	Concurrency int
+	// Seed makes the dataset and query mix reproducible.
+	Seed int64
+	Keep bool
+}
+
+// Result is the latency distribution of one scenario.
+type Result struct {
+	Scenario string        `json:"scenario"`
+	Count    int           `json:"count"`
+	Errors   int           `json:"errors"`
+	P50      time.Duration `json:"p50_ns"`
+	P95      time.Duration `json:"p95_ns"`
+	P99      time.Duration `json:"p99_ns"`
+	Max      time.Duration `json:"max_ns"`
+	Mean     time.Duration `json:"mean_ns"`
+}
+
+func newResult(scenario string, h *Histogram, errors int) Result {
+	return Result{
+		Scenario: scenario,
+		Count:    h.Len(),
+		Errors:   errors,
+		P50:      h.Percentile(0.50),
+		P95:      h.Percentile(0.95),
+		P99:      h.Percentile(0.99),
+		Max:      h.Max(),
+		Mean:     h.Mean(),
+	}
+}
+
+// Run seeds the dataset (unless opts.Project is set), measures every
+// scenario and removes the seeded memories. progress, when non-nil,
+// receives one line per phase.
+func Run(ctx context.Context, t Target, opts Options, progress func(string)) ([]Result, error) {
+	if progress == nil {
+		progress = func(string) {}
+	}
+	if opts.Queries <= 0 {
+		opts.Queries = 200
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	var results []Result
+	project := opts.Project
+	if project == "" {
+		project = fmt.Sprintf("engram-bench-%d", time.Now().Unix())
+		progress(fmt.Sprintf("seeding %d memories into %s", opts.Memories, project))
+		ids, store, err := seed(ctx, t, project, opts, rng)
+		if !opts.Keep {
+			defer func() {
+				progress(fmt.Sprintf("deleting %d seeded memories", len(ids)))
+				cleanup(context.WithoutCancel(ctx), t, ids, opts.Concurrency)
+			}()
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, store)
+	}
+
+	reads := []struct {
+		name string
+		call func() error
+	}{
+		{ScenarioSearch, func() error { return t.Search(ctx, project, SyntheticQuery(rng)) }},
+		{ScenarioList, func() error { return t.ListMemories(ctx, project) }},
+		{ScenarioSessionStart, func() error { return t.SessionStart(ctx, project) }},
+	}
+	for _, read := range reads {
+		progress(fmt.Sprintf("measuring %s (%d queries)", read.name, opts.Queries))
+		for i := 0; i < warmupQueries; i++ {
+			_ = read.call()
+		}
+		h, errors := NewHistogram(), 0
+		for i := 0; i < opts.Queries; i++ {
+			if err := ctx.Err(); err != nil {
+				return results, err
+			}
+			start := time.Now()
+			if err := read.call(); err != nil {
+				errors++
+				continue
+			}
+			h.Add(time.Since(start))
+		}
+		results = append(results, newResult(read.name, h, errors))
+	}
+	return results, nil
+}
+
+// seed stores opts.Memories synthetic memories and returns their IDs with
+// the latency of the stores. It fails fast when the first store fails, which
+// is almost always a configuration problem.
+func seed(ctx context.Context, t Target, project string, opts Options, rng *rand.Rand) ([]int64, Result, error) {
+	contents := make([]string, opts.Memories)
+	tags := make([][]string, opts.Memories)
+	for i := range contents {
+		contents[i], tags[i] = SyntheticMemory(rng, i)
+	}
+
+	var (
+		mu     sync.Mutex
+		ids    = make([]int64, 0, opts.Memories)
+		h      = NewHistogram()
+		errors int
+		wg     sync.WaitGroup
+	)
+	if opts.Memories > 0 {
+		id, err := t.StoreMemory(ctx, project, contents[0], tags[0])
+		if err != nil {
+			return nil, Result{}, fmt.Errorf("store first memory: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	next := make(chan int)
+	for w := 0; w < opts.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				start := time.Now()
+				id, err := t.StoreMemory(ctx, project, contents[i], tags[i])
+				elapsed := time.Since(start)
+				mu.Lock()
+				if err != nil {
+					errors++
+				} else {
+					ids = append(ids, id)
+					h.Add(elapsed)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := 1; i < opts.Memories && ctx.Err() == nil; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return ids, newResult(ScenarioStore, h, errors), ctx.Err()
+}
+
+// cleanup deletes the seeded memories, ignoring failures.
+func cleanup(ctx context.Context, t Target, ids []int64, concurrency int) {
+	next := make(chan int64)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range next {
+				_ = t.DeleteMemory(ctx, id)
+			}
+		}()
+	}
+	for _, id := range ids {
+		next <- id
+	}
+	close(next)
+	wg.Wait()
+}
+
+// Compare returns one message per scenario whose p95 is more than tolerance
+// (0.2 = 20%) above its baseline. Scenarios missing from baseline are not
+// compared.
+func Compare(baseline, current []Result, tolerance float64) []string {
+	base := make(map[string]Result, len(baseline))
+	for _, r := range baseline {
+		base[r.Scenario] = r
+	}
+	var regressions []string
+	for _, r := range current {
+		b, ok := base[r.Scenario]
+		if !ok || b.P95 <= 0 {
+			continue
+		}
+		if limit := time.Duration(float64(b.P95) * (1 + tolerance)); r.P95 > limit {
+			regressions = append(regressions, fmt.Sprintf("%s: p95 %s exceeds baseline %s by more than %.0f%%",
+				r.Scenario, r.P95.Round(time.Microsecond), b.P95.Round(time.Microsecond), tolerance*100))
+		}
+	}
+	return regressions
+}
+
+// Vocabulary of the synthetic dataset. Queries draw from the same topics so
+// that searches have matches.
+var (
+	benchTopics     = []string{"auth", "cache", "migration", "retry", "webhook", "session", "index", "deploy", "config", "logging", "tenant", "rate limit"}
+	benchComponents = []string{"worker", "mcp server", "hooks", "dashboard", "gorm store", "proxy", "cli", "grpc server"}
+	benchVerbs      = []string{"uses", "must not use", "was changed to use", "depends on", "breaks without", "was fixed by switching to"}
+	benchTypes      = []string{"decision", "bugfix", "discovery", "change", "insight"}
+)
+
+// SyntheticMemory returns the content and tags of the i-th synthetic memory.
+func SyntheticMemory(rng *rand.Rand, i int) (string, []string) {
+	topic := benchTopics[rng.Intn(len(benchTopics))]
+	other := benchTopics[rng.Intn(len(benchTopics))]
+	component := benchComponents[rng.Intn(len(benchComponents))]
+	verb := benchVerbs[rng.Intn(len(benchVerbs))]
+	typ := benchTypes[rng.Intn(len(benchTypes))]
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "The %s %s %s %s (benchmark memory %d).\n", component, verb, topic, other, i)
+	for s := 0; s < 1+rng.Intn(4); s++ {
+		fmt.Fprintf(&b, "- %s: %s handling in the %s, see case %d.\n",
+			topic, other, benchComponents[rng.Intn(len(benchComponents))], rng.Intn(10000))
+	}
+	return b.String(), []string{"type:" + typ, strings.ReplaceAll(topic, " ", "-"), "bench"}
+}
+
+// SyntheticQuery returns a search query over the synthetic dataset.
+func SyntheticQuery(rng *rand.Rand) string {
+	return benchTopics[rng.Intn(len(benchTopics))] + " " + benchComponents[rng.Intn(len(benchComponents))]
+}
@@ -0,0 +1,105 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTarget records the calls of a benchmark run.
+type fakeTarget struct {
+	mu       sync.Mutex
+	stored   map[int64]string
+	deleted  int
+	searches int
+	failList bool
+	nextID   int64
+}
+
+func (f *fakeTarget) StoreMemory(_ context.Context, project, content string, _ []string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	f.stored[f.nextID] = project
+	return f.nextID, nil
+}
+
+func (f *fakeTarget) DeleteMemory(_ context.Context, id int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.stored, id)
+	f.deleted++
+	return nil
+}
+
+func (f *fakeTarget) Search(context.Context, string, string) error {
+	f.searches++
+	return nil
+}
+
+func (f *fakeTarget) ListMemories(context.Context, string) error {
+	if f.failList {
+		return errors.New("boom")
+	}
+	return nil
+}
+
+func (f *fakeTarget) SessionStart(context.Context, string) error { return nil }
+
+func TestRun_SeedsMeasuresAndCleansUp(t *testing.T) {
+	target := &fakeTarget{stored: map[int64]string{}, failList: true}
+	results, err := Run(context.Background(), target, Options{Memories: 50, Queries: 20, Concurrency: 4}, nil)
+	require.NoError(t, err)
+
+	require.Len(t, results, 4)
+	assert.Equal(t, ScenarioStore, results[0].Scenario)
+	assert.Equal(t, 49, results[0].Count, "the first store is a connectivity check, not measured")
+	assert.Equal(t, ScenarioSearch, results[1].Scenario)
+	assert.Equal(t, 20, results[1].Count)
+	assert.Equal(t, warmupQueries+20, target.searches)
+	assert.Equal(t, Result{Scenario: ScenarioList, Errors: 20}, results[2])
+	assert.Equal(t, ScenarioSessionStart, results[3].Scenario)
+
+	assert.Equal(t, 50, target.deleted)
+	assert.Empty(t, target.stored)
+}
+
+func TestRun_ExistingProjectIsNotSeeded(t *testing.T) {
+	target := &fakeTarget{stored: map[int64]string{}}
+	results, err := Run(context.Background(), target, Options{Project: "engram", Memories: 50, Queries: 5}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, ScenarioSearch, results[0].Scenario)
+	assert.Zero(t, target.nextID)
+	assert.Zero(t, target.deleted)
+}
+
+func TestCompare(t *testing.T) {
+	baseline := []Result{
+		{Scenario: ScenarioSearch, P95: 10 * time.Millisecond},
+		{Scenario: ScenarioList, P95: 4 * time.Millisecond},
+	}
+	current := []Result{
+		{Scenario: ScenarioSearch, P95: 11 * time.Millisecond},
+		{Scenario: ScenarioList, P95: 6 * time.Millisecond},
+		{Scenario: ScenarioSessionStart, P95: time.Second},
+	}
+	regressions := Compare(baseline, current, 0.2)
+	require.Len(t, regressions, 1)
+	assert.Contains(t, regressions[0], "list: p95 6ms exceeds baseline 4ms")
+}
+
+func TestSyntheticMemory_Reproducible(t *testing.T) {
+	a, tagsA := SyntheticMemory(rand.New(rand.NewSource(1)), 3)
+	b, tagsB := SyntheticMemory(rand.New(rand.NewSource(1)), 3)
+	assert.Equal(t, a, b)
+	assert.Equal(t, tagsA, tagsB)
+	assert.Contains(t, a, "benchmark memory 3")
+	assert.Contains(t, tagsA, "bench")
+}
@@ -0,0 +1,218 @@
+// Package brief assembles a project's memories into a Markdown onboarding
+// brief: architecture, key decisions, conventions, gotchas, operations and
+// recent changes, so a new teammate or a fresh session gets up to speed
+// without searching.
+package brief
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+const (
+	// DefaultMaxPerSection is the number of memories listed per section.
+	DefaultMaxPerSection = 10
+	// maxTopics is the number of concept tags listed under Topics.
+	maxTopics  = 8
+	titleRunes = 80
+	factRunes  = 120
+)
+
+// Options controls Build.
+type Options struct {
+	// Now dates the brief; the zero value means time.Now.
+	Now time.Time
+	// MaxPerSection caps the memories listed per section (pinned ones
+	// first, then newest); the rest are counted. <= 0 means
+	// DefaultMaxPerSection.
+	MaxPerSection int
+}
+
+// section is one heading of the brief. A memory goes into the first
+// section it matches.
+type section struct {
+	match func(typ string, mem *models.Memory) bool
+	title string
+}
+
+var sections = []section{
+	{title: "Architecture", match: func(typ string, mem *models.Memory) bool {
+		return typ == "wiki" || typ == "entity" || hasTag(mem, "architecture")
+	}},
+	{title: "Key decisions", match: func(typ string, _ *models.Memory) bool { return typ == "decision" }},
+	{title: "Conventions", match: func(typ string, _ *models.Memory) bool { return typ == "guidance" }},
+	{title: "Gotchas", match: func(typ string, mem *models.Memory) bool {
+		return typ == "pitfall" || typ == "bugfix" || models.PolarityFromTags(mem.Tags).IsWarning()
+	}},
+	{title: "Operations", match: func(typ string, _ *models.Memory) bool { return typ == "operational" }},
+	{title: "Discoveries", match: func(typ string, _ *models.Memory) bool { return typ == "discovery" }},
+	{title: "Recent changes", match: func(typ string, _ *models.Memory) bool {
+		return typ == "change" || typ == "feature" || typ == "refactor"
+	}},
+}
+
+// Build renders the brief of project from its memories. Credentials and
+// timeline entries are left out, as are memories no section takes; both are
+// counted in the header.
+func Build(project string, memories []*models.Memory, opts Options) string {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	perSection := opts.MaxPerSection
+	if perSection <= 0 {
+		perSection = DefaultMaxPerSection
+	}
+
+	grouped := make([][]*models.Memory, len(sections))
+	unsorted := 0
+	for _, mem := range memories {
+		typ := string(models.TypeFromTags(mem.Tags))
+		if typ == "credential" || typ == "timeline" {
+			unsorted++
+			continue
+		}
+		placed := false
+		for i, sec := range sections {
+			if sec.match(typ, mem) {
+				grouped[i] = append(grouped[i], mem)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			unsorted++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s — project brief\n\n", project)
+	fmt.Fprintf(&b, "Generated %s from %d memories", now.UTC().Format(time.DateOnly), len(memories))
+	if unsorted > 0 {
+		fmt.Fprintf(&b, " (%d not covered by any section)", unsorted)
+	}
+	b.WriteString(". Pinned memories come first in each section; `#id` refers to the memory.\n")
+
+	if topics := topConcepts(memories, maxTopics); len(topics) > 0 {
+		b.WriteString("\n## Topics\n\n")
+		b.WriteString(strings.Join(topics, ", "))
+		b.WriteString("\n")
+	}
+
+	empty := true
+	for i, sec := range sections {
+		mems := grouped[i]
+		if len(mems) == 0 {
+			continue
+		}
+		empty = false
+		sort.SliceStable(mems, func(a, c int) bool {
+			if mems[a].Pinned != mems[c].Pinned {
+				return mems[a].Pinned
+			}
+			return mems[a].CreatedAt.After(mems[c].CreatedAt)
+		})
+		fmt.Fprintf(&b, "\n## %s\n\n", sec.title)
+		for _, mem := range mems[:min(len(mems), perSection)] {
+			writeItem(&b, mem)
+		}
+		if more := len(mems) - perSection; more > 0 {
+			fmt.Fprintf(&b, "- …and %d more\n", more)
+		}
+	}
+	if empty {
+		b.WriteString("\nNo memories to brief yet: store decisions, conventions and gotchas as they come up.\n")
+	}
+	return b.String()
+}
+
+// writeItem writes one memory as a list item: its title in bold, its top
+// fact, then its ID and date.
+func writeItem(b *strings.Builder, mem *models.Memory) {
+	title, fact := titleAndFact(mem.Content)
+	fmt.Fprintf(b, "- **%s**", title)
+	if fact != "" {
+		fmt.Fprintf(b, " — %s", fact)
+	}
+	fmt.Fprintf(b, " _(#%d, %s)_\n", mem.ID, mem.CreatedAt.UTC().Format(time.DateOnly))
+}
+
+// titleAndFact returns the first non-empty line of content and the next
+// one, with Markdown list and heading markers stripped and both truncated.
+func titleAndFact(content string) (string, string) {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*•#>"))
+		if line != "" {
+			lines = append(lines, line)
+			if len(lines) == 2 {
+				break
+			}
+		}
+	}
+	switch len(lines) {
+	case 0:
+		return "(empty)", ""
+	case 1:
+		return truncate(lines[0], titleRunes), ""
+	default:
+		return truncate(lines[0], titleRunes), truncate(lines[1], factRunes)
+	}
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
+
+// hasTag reports whether mem carries tag or concept:tag.
+func hasTag(mem *models.Memory, tag string) bool {
+	for _, t := range mem.Tags {
+		t = strings.ToLower(t)
+		if t == tag || t == "concept:"+tag {
+			return true
+		}
+	}
+	return false
+}
+
+// topConcepts returns the n most frequent concept tags, skipping the
+// bookkeeping tags (type:, scope:, session:, subagent: and the like).
+func topConcepts(memories []*models.Memory, n int) []string {
+	counts := make(map[string]int)
+	for _, mem := range memories {
+		for _, tag := range mem.Tags {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if c, ok := strings.CutPrefix(tag, "concept:"); ok {
+				tag = c
+			} else if tag == "" || strings.Contains(tag, ":") {
+				continue
+			}
+			counts[tag]++
+		}
+	}
+	topics := make([]string, 0, len(counts))
+	for tag := range counts {
+		topics = append(topics, tag)
+	}
+	sort.Slice(topics, func(i, j int) bool {
+		if counts[topics[i]] != counts[topics[j]] {
+			return counts[topics[i]] > counts[topics[j]]
+		}
+		return topics[i] < topics[j]
+	})
+	if len(topics) > n {
+		topics = topics[:n]
+	}
+	for i, tag := range topics {
+		topics[i] = fmt.Sprintf("%s (%d)", tag, counts[tag])
+	}
+	return topics
+}
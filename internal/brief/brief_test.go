@@ -0,0 +1,46 @@
+package brief
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestBuild(t *testing.T) {
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	memories := []*models.Memory{
+		{ID: 1, Content: "Use pgx instead of lib/pq\n- pgx supports COPY", Tags: []string{"type:decision", "db"}, CreatedAt: day},
+		{ID: 2, Content: "Old decision", Tags: []string{"type:decision", "db"}, CreatedAt: day.Add(-48 * time.Hour), Pinned: true},
+		{ID: 3, Content: "Migrations run before the HTTP server starts", Tags: []string{"type:discovery", "architecture"}, CreatedAt: day},
+		{ID: 4, Content: "Never call Close twice on the pool", Tags: []string{"type:change", "polarity:do_not"}, CreatedAt: day},
+		{ID: 5, Content: "API key for staging", Tags: []string{"type:credential"}, CreatedAt: day},
+	}
+	got := Build("engram", memories, Options{Now: day})
+
+	assert.True(t, strings.HasPrefix(got, "# engram — project brief\n\nGenerated 2026-03-01 from 5 memories (1 not covered by any section)."))
+	assert.Contains(t, got, "## Topics\n\ndb (2), architecture (1)\n")
+	assert.Contains(t, got, "## Architecture\n\n- **Migrations run before the HTTP server starts** _(#3, 2026-03-01)_\n")
+	assert.Contains(t, got, "## Key decisions\n\n- **Old decision** _(#2, 2026-02-27)_\n- **Use pgx instead of lib/pq** — pgx supports COPY _(#1, 2026-03-01)_\n",
+		"pinned memories come first")
+	assert.Contains(t, got, "## Gotchas\n\n- **Never call Close twice on the pool**", "warnings are gotchas whatever their type")
+	assert.NotContains(t, got, "API key")
+	assert.NotContains(t, got, "## Recent changes")
+}
+
+func TestBuild_CapsSections(t *testing.T) {
+	var memories []*models.Memory
+	for i := 0; i < 4; i++ {
+		memories = append(memories, &models.Memory{ID: int64(i + 1), Content: "gotcha", Tags: []string{"type:pitfall"}})
+	}
+	got := Build("engram", memories, Options{MaxPerSection: 3})
+	assert.Equal(t, 3, strings.Count(got, "- **gotcha**"))
+	assert.Contains(t, got, "- …and 1 more\n")
+}
+
+func TestBuild_Empty(t *testing.T) {
+	assert.Contains(t, Build("engram", nil, Options{}), "No memories to brief yet")
+}
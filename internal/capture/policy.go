@@ -0,0 +1,171 @@
+// Package capture evaluates the capture policy (config.CapturePolicy) that
+// decides which tool events and memories may be persisted, so secrets-adjacent
+// files and noisy tools never become memories.
+package capture
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/thebtf/engram/internal/config"
+)
+
+// Rule names reported in a Decision.
+const (
+	RuleExcludeFiles    = "exclude_files"
+	RuleExcludeTools    = "exclude_tools"
+	RuleExcludeConcepts = "exclude_concepts"
+)
+
+// Event is what a capture decision is made about. Empty fields are not
+// checked.
+type Event struct {
+	ToolName string   `json:"tool_name,omitempty"`
+	Files    []string `json:"files,omitempty"`
+	Concepts []string `json:"concepts,omitempty"`
+}
+
+// Decision is the outcome of evaluating an Event. When Allowed is false, Rule
+// names the rule list, Pattern the pattern that matched and Match the file,
+// tool or concept it matched.
+type Decision struct {
+	Rule    string `json:"rule,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+	Match   string `json:"match,omitempty"`
+	Allowed bool   `json:"allowed"`
+}
+
+// Reason describes a blocking decision for logs and error messages.
+func (d Decision) Reason() string {
+	if d.Allowed {
+		return ""
+	}
+	return fmt.Sprintf("%s pattern %q matches %q", d.Rule, d.Pattern, d.Match)
+}
+
+// Evaluate applies the effective rules of project to ev. Tools are checked
+// first, then files, then concepts; the first match blocks the event.
+func Evaluate(policy config.CapturePolicy, project string, ev Event) Decision {
+	rules := policy.RulesFor(project)
+
+	if ev.ToolName != "" {
+		for _, pattern := range rules.ExcludeTools {
+			if matchName(pattern, ev.ToolName) {
+				return Decision{Rule: RuleExcludeTools, Pattern: pattern, Match: ev.ToolName}
+			}
+		}
+	}
+
+	for _, file := range ev.Files {
+		if file == "" || matchesAny(rules.IncludeFiles, file) {
+			continue
+		}
+		for _, pattern := range rules.ExcludeFiles {
+			if MatchFile(pattern, file) {
+				return Decision{Rule: RuleExcludeFiles, Pattern: pattern, Match: file}
+			}
+		}
+	}
+
+	for _, concept := range ev.Concepts {
+		for _, pattern := range rules.ExcludeConcepts {
+			if matchName(pattern, concept) {
+				return Decision{Rule: RuleExcludeConcepts, Pattern: pattern, Match: concept}
+			}
+		}
+	}
+
+	return Decision{Allowed: true}
+}
+
+func matchesAny(patterns []string, file string) bool {
+	for _, pattern := range patterns {
+		if MatchFile(pattern, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchName matches a tool or concept name against a glob, ignoring case.
+func matchName(pattern, name string) bool {
+	pattern, name = strings.ToLower(strings.TrimSpace(pattern)), strings.ToLower(strings.TrimSpace(name))
+	if pattern == "" {
+		return false
+	}
+	ok, err := path.Match(pattern, name)
+	return ok || (err != nil && pattern == name)
+}
+
+// MatchFile reports whether file matches the glob pattern. Windows separators
+// are normalised. A pattern without "/" matches the base name; a pattern with
+// "/" matches the trailing segments of the path (a leading "**/" is
+// redundant), and a trailing "/**" matches everything below the directory.
+func MatchFile(pattern, file string) bool {
+	pattern = strings.TrimPrefix(strings.TrimSpace(filepathToSlash(pattern)), "**/")
+	file = strings.TrimPrefix(filepathToSlash(file), "./")
+	if pattern == "" || file == "" {
+		return false
+	}
+
+	segments := strings.Split(strings.Trim(file, "/"), "/")
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, segments[len(segments)-1])
+		return ok
+	}
+
+	subtree := strings.HasSuffix(pattern, "/**")
+	if subtree {
+		pattern = strings.TrimSuffix(pattern, "/**")
+	}
+	patternLen := len(strings.Split(pattern, "/"))
+	for start := 0; start+patternLen <= len(segments); start++ {
+		end := len(segments)
+		if subtree {
+			// The directory must have something below it.
+			end = start + patternLen
+			if end == len(segments) {
+				continue
+			}
+		} else if len(segments)-start != patternLen {
+			continue
+		}
+		if ok, _ := path.Match(pattern, strings.Join(segments[start:end], "/")); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func filepathToSlash(p string) string {
+	return strings.ReplaceAll(p, `\`, "/")
+}
+
+// toolInputFileKeys are the tool input fields that name files.
+var toolInputFileKeys = []string{"file_path", "path", "notebook_path", "filePath"}
+
+// FilesFromToolInput returns the file paths named in a tool input object
+// (file_path, path, notebook_path, and the paths/files arrays).
+func FilesFromToolInput(input any) []string {
+	m, ok := input.(map[string]any)
+	if !ok {
+		return nil
+	}
+	var files []string
+	for _, key := range toolInputFileKeys {
+		if v, ok := m[key].(string); ok && v != "" {
+			files = append(files, v)
+		}
+	}
+	for _, key := range []string{"paths", "files"} {
+		if list, ok := m[key].([]any); ok {
+			for _, item := range list {
+				if v, ok := item.(string); ok && v != "" {
+					files = append(files, v)
+				}
+			}
+		}
+	}
+	return files
+}
@@ -0,0 +1,78 @@
+package capture
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thebtf/engram/internal/config"
+)
+
+func TestMatchFile(t *testing.T) {
+	tests := []struct {
+		pattern string
+		file    string
+		want    bool
+	}{
+		{".env", "/repo/.env", true},
+		{".env.*", "/repo/app/.env.local", true},
+		{".env.*", "/repo/.envrc", false},
+		{"*.tfstate", `C:\infra\prod\terraform.tfstate`, true},
+		{"terraform/*.tfstate", "/repo/terraform/prod.tfstate", true},
+		{"terraform/*.tfstate", "/repo/terraform/modules/prod.tfstate", false},
+		{"**/secrets/*.yaml", "deploy/secrets/db.yaml", true},
+		{"terraform/**", "/repo/terraform/modules/vpc/main.tf", true},
+		{"terraform/**", "/repo/terraform", false},
+		{"id_rsa*", "/home/u/.ssh/id_rsa.pub", true},
+		{"*.key", "/repo/keyboard.go", false},
+		{"", "/repo/.env", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MatchFile(tt.pattern, tt.file), "%s vs %s", tt.pattern, tt.file)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	policy := config.CapturePolicy{
+		Default: config.CaptureRules{
+			ExcludeFiles: config.DefaultCaptureExcludeFiles,
+			IncludeFiles: config.DefaultCaptureIncludeFiles,
+			ExcludeTools: []string{"TodoWrite"},
+		},
+		Projects: map[string]config.CaptureRules{
+			"infra": {ExcludeFiles: []string{"terraform/**"}, ExcludeTools: []string{"mcp__aws__*"}, ExcludeConcepts: []string{"credentials"}},
+		},
+	}
+
+	d := Evaluate(policy, "app", Event{ToolName: "todowrite"})
+	assert.False(t, d.Allowed)
+	assert.Equal(t, RuleExcludeTools, d.Rule)
+
+	d = Evaluate(policy, "app", Event{ToolName: "Read", Files: []string{"/repo/.env.production"}})
+	assert.False(t, d.Allowed)
+	assert.Equal(t, RuleExcludeFiles, d.Rule)
+	assert.Equal(t, ".env.*", d.Pattern)
+	assert.Contains(t, d.Reason(), "/repo/.env.production")
+
+	assert.True(t, Evaluate(policy, "app", Event{ToolName: "Read", Files: []string{"/repo/.env.example"}}).Allowed,
+		"include patterns are exceptions to excludes")
+
+	// Project rules add to the defaults and do not leak into other projects.
+	assert.False(t, Evaluate(policy, "infra", Event{Files: []string{"/src/terraform/main.tf"}}).Allowed)
+	assert.True(t, Evaluate(policy, "app", Event{Files: []string{"/src/terraform/main.tf"}}).Allowed)
+	assert.False(t, Evaluate(policy, "infra", Event{ToolName: "mcp__aws__list_buckets"}).Allowed)
+	assert.False(t, Evaluate(policy, "infra", Event{Concepts: []string{"Credentials"}}).Allowed)
+	assert.True(t, Evaluate(policy, "app", Event{Concepts: []string{"credentials"}}).Allowed)
+
+	assert.Empty(t, Decision{Allowed: true}.Reason())
+}
+
+func TestFilesFromToolInput(t *testing.T) {
+	input := map[string]any{
+		"file_path": "/repo/main.go",
+		"paths":     []any{"/repo/a.go", 3, ""},
+		"command":   "cat .env",
+	}
+	assert.Equal(t, []string{"/repo/main.go", "/repo/a.go"}, FilesFromToolInput(input))
+	assert.Nil(t, FilesFromToolInput("not an object"))
+}
@@ -0,0 +1,192 @@
+// Package concepts proposes concept tags for memories stored without any, so
+// concept filters and concept-weighted scoring work for them too.
+//
+// Extraction is a keyword pipeline: the text is tokenized, each concept of the
+// taxonomy (the keys of models.DefaultConceptWeights) has a list of cue words
+// and phrases, and a concept's confidence grows with every distinct cue found.
+// Only concepts at or above the confidence threshold are proposed.
+package concepts
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+const (
+	// DefaultMinConfidence is the confidence a concept needs to be proposed:
+	// one strong cue, or two weak ones.
+	DefaultMinConfidence = 0.5
+
+	// MaxConcepts caps the concepts proposed for one text.
+	MaxConcepts = 3
+
+	strongCue = 0.6
+	weakCue   = 0.3
+)
+
+// Concept is a proposed concept tag.
+type Concept struct {
+	Name       string   `json:"name"`
+	Cues       []string `json:"cues"`
+	Confidence float64  `json:"confidence"`
+}
+
+// cue is a word or phrase indicating a concept. A trailing "*" matches any
+// word with that prefix ("vulnerab*" matches "vulnerability").
+type cue struct {
+	text   string
+	weight float64
+}
+
+func strong(texts ...string) []cue { return cues(strongCue, texts) }
+func weak(texts ...string) []cue   { return cues(weakCue, texts) }
+
+func cues(weight float64, texts []string) []cue {
+	out := make([]cue, len(texts))
+	for i, t := range texts {
+		out[i] = cue{text: t, weight: weight}
+	}
+	return out
+}
+
+// lexicon maps every concept of the taxonomy to its cues.
+var lexicon = map[string][]cue{
+	"security": append(strong("security", "vulnerab*", "xss", "csrf", "sql injection", "exploit*", "cve"),
+		weak("auth*", "token", "tokens", "secret*", "permission*", "sanitiz*", "encrypt*", "password*")...),
+	"gotcha": append(strong("gotcha", "gotchas", "pitfall*", "watch out", "caveat*", "footgun*", "be careful"),
+		weak("unexpected*", "surprising*", "silently", "note that", "trap*")...),
+	"best-practice": append(strong("best practice", "best practices", "best-practice", "always use", "should always"),
+		weak("convention*", "prefer", "recommended", "idiomatic")...),
+	"anti-pattern": append(strong("anti-pattern", "anti-patterns", "antipattern*", "code smell", "never use", "do not use", "don't use"),
+		weak("avoid*", "discouraged")...),
+	"architecture": append(strong("architecture", "architectural"),
+		weak("layer*", "module*", "component*", "boundary", "boundaries", "interface*", "design")...),
+	"performance": append(strong("performance", "latency", "throughput", "bottleneck*", "n+1"),
+		weak("slow*", "faster", "cache*", "caching", "optimiz*", "allocation*", "benchmark*")...),
+	"error-handling": append(strong("error handling", "error-handling"),
+		weak("retry*", "retries", "timeout*", "panic*", "fallback*", "recover*")...),
+	"pattern": append(strong("pattern", "patterns"),
+		weak("idiom*", "approach")...),
+	"testing": append(strong("test", "tests", "testing", "unit test", "integration test"),
+		weak("mock*", "fixture*", "assert*", "coverage", "flaky")...),
+	"debugging": append(strong("debug*", "root cause", "stack trace", "stacktrace"),
+		weak("logs", "logging", "reproduc*", "bisect*", "investigat*")...),
+	"problem-solution": append(strong("fixed by", "solved by", "resolved by", "the fix", "workaround*"),
+		weak("fix", "fixed", "fixes", "solution*", "solve*")...),
+	"trade-off": append(strong("trade-off", "trade-offs", "tradeoff*", "at the cost of", "downside*"),
+		weak("versus", "vs", "pros", "cons")...),
+	"workflow": append(strong("workflow*"),
+		weak("ci", "pipeline*", "release process", "deploy*", "commit*")...),
+	"tooling": append(strong("tooling"),
+		weak("linter*", "makefile", "cli", "gofmt", "go vet", "script*")...),
+	"how-it-works": append(strong("how it works", "under the hood", "internally"),
+		weak("works by", "mechanism*")...),
+	"why-it-exists": append(strong("exists because", "the reason", "rationale"),
+		weak("because", "purpose", "so that")...),
+	"what-changed": append(strong("changed from", "renamed", "now uses", "migrated"),
+		weak("no longer", "replaced*", "removed")...),
+}
+
+// IsConcept reports whether tag names a concept of the taxonomy, with or
+// without a "concept:" prefix.
+func IsConcept(tag string) bool {
+	tag = strings.TrimPrefix(strings.ToLower(tag), "concept:")
+	_, ok := models.DefaultConceptWeights[tag]
+	return ok
+}
+
+// HasConcept reports whether any tag names a concept of the taxonomy.
+func HasConcept(tags []string) bool {
+	for _, tag := range tags {
+		if IsConcept(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extract proposes up to MaxConcepts concepts for text whose confidence is at
+// least minConfidence, highest confidence first.
+func Extract(text string, minConfidence float64) []Concept {
+	words := tokenize(text)
+	if len(words) == 0 {
+		return nil
+	}
+	// Phrases are matched against the space-joined words, padded so every
+	// match starts and ends on a word boundary.
+	joined := " " + strings.Join(words, " ") + " "
+
+	var found []Concept
+	for name, list := range lexicon {
+		miss := 1.0
+		var matched []string
+		for _, c := range list {
+			if matches(c.text, words, joined) {
+				miss *= 1 - c.weight
+				matched = append(matched, strings.TrimSuffix(c.text, "*"))
+			}
+		}
+		confidence := 1 - miss
+		if len(matched) > 0 && confidence >= minConfidence {
+			found = append(found, Concept{Name: name, Cues: matched, Confidence: confidence})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Confidence != found[j].Confidence {
+			return found[i].Confidence > found[j].Confidence
+		}
+		return found[i].Name < found[j].Name
+	})
+	if len(found) > MaxConcepts {
+		found = found[:MaxConcepts]
+	}
+	return found
+}
+
+// Augment returns tags with the concepts extracted from text appended, when
+// tags name no concept yet, and the concepts added. Tags that already carry a
+// concept are returned unchanged.
+func Augment(tags []string, text string, minConfidence float64) ([]string, []Concept) {
+	if HasConcept(tags) {
+		return tags, nil
+	}
+	found := Extract(text, minConfidence)
+	if len(found) == 0 {
+		return tags, nil
+	}
+	out := make([]string, 0, len(tags)+len(found))
+	out = append(out, tags...)
+	return append(out, Names(found)...), found
+}
+
+// Names returns the names of the concepts.
+func Names(found []Concept) []string {
+	names := make([]string, len(found))
+	for i, c := range found {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func matches(text string, words []string, joined string) bool {
+	if prefix, ok := strings.CutSuffix(text, "*"); ok {
+		for _, w := range words {
+			if strings.HasPrefix(w, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	return strings.Contains(joined, " "+text+" ")
+}
+
+// tokenize lowercases text and splits it into words. Hyphens, apostrophes and
+// "+" stay inside words so "anti-pattern", "don't" and "n+1" survive.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-'+", r)
+	})
+}
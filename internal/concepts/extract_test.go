@@ -0,0 +1,52 @@
+package concepts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestLexiconCoversTaxonomy(t *testing.T) {
+	for name := range models.DefaultConceptWeights {
+		assert.NotEmpty(t, lexicon[name], "concept %q has no cues", name)
+	}
+	for name := range lexicon {
+		assert.Contains(t, models.DefaultConceptWeights, name, "lexicon concept %q is not in the taxonomy", name)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	found := Extract("Gotcha: the session token is compared without constant time, a security vulnerability.", DefaultMinConfidence)
+	require.NotEmpty(t, found)
+	assert.Equal(t, "security", found[0].Name)
+	assert.Contains(t, Names(found), "gotcha")
+	assert.InDelta(t, 1-0.4*0.4*0.7, found[0].Confidence, 1e-9)
+
+	// One weak cue is below the threshold; two reach it.
+	assert.Empty(t, Extract("We prefer short names.", DefaultMinConfidence))
+	assert.Equal(t, []string{"best-practice"}, Names(Extract("By convention we prefer short names.", DefaultMinConfidence)))
+
+	// Cues match whole words only.
+	assert.Empty(t, Extract("The contest attestation passed.", DefaultMinConfidence))
+	assert.Equal(t, []string{"anti-pattern"}, Names(Extract("Calling panic in a library is an anti-pattern.", 0.6)))
+	assert.Empty(t, Extract("", DefaultMinConfidence))
+}
+
+func TestExtractCapsResults(t *testing.T) {
+	text := "Security gotcha: this anti-pattern hurts performance and testing, see the architecture notes."
+	assert.Len(t, Extract(text, DefaultMinConfidence), MaxConcepts)
+}
+
+func TestAugment(t *testing.T) {
+	tags, found := Augment([]string{"type:bugfix"}, "The root cause was a stale cache; fixed by clearing it on deploy.", DefaultMinConfidence)
+	assert.NotEmpty(t, found)
+	assert.Equal(t, "type:bugfix", tags[0])
+	assert.Subset(t, tags, []string{"debugging", "problem-solution"})
+
+	tags, found = Augment([]string{"concept:gotcha"}, "A security vulnerability.", DefaultMinConfidence)
+	assert.Nil(t, found)
+	assert.Equal(t, []string{"concept:gotcha"}, tags)
+}
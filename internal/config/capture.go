@@ -0,0 +1,71 @@
+package config
+
+// DefaultCaptureExcludeFiles keeps secrets-adjacent files out of memory unless
+// a settings file replaces the default capture rules.
+var DefaultCaptureExcludeFiles = []string{
+	".env", ".env.*", "*.tfstate", "*.tfstate.*", "*.pem", "*.key", "*.p12", "*.pfx",
+	"id_rsa*", "id_ecdsa*", "id_ed25519*", ".npmrc", ".pypirc", ".netrc",
+}
+
+// DefaultCaptureIncludeFiles are the templates that stay capturable despite
+// matching DefaultCaptureExcludeFiles.
+var DefaultCaptureIncludeFiles = []string{
+	".env.example", ".env.sample", ".env.template",
+}
+
+// CaptureRules decides which events may become memories. File patterns are
+// globs: a pattern without "/" matches the base name, one with "/" matches
+// the trailing path segments, and "dir/**" matches everything under dir.
+// IncludeFiles are exceptions to ExcludeFiles. Tool and concept patterns
+// match case-insensitively and may also be globs (e.g. "mcp__*").
+type CaptureRules struct {
+	ExcludeFiles    []string `json:"exclude_files,omitempty"`
+	IncludeFiles    []string `json:"include_files,omitempty"`
+	ExcludeTools    []string `json:"exclude_tools,omitempty"`
+	ExcludeConcepts []string `json:"exclude_concepts,omitempty"`
+}
+
+// CapturePolicy holds the capture rules applied to every project and the
+// extra rules of individual projects, which add to the defaults.
+//
+// Settings file key: ENGRAM_CAPTURE_POLICY, e.g.
+//
+//	"ENGRAM_CAPTURE_POLICY": {
+//	  "projects": {"infra": {"exclude_files": ["terraform/**"], "exclude_tools": ["TodoWrite"]}}
+//	}
+//
+// A non-empty "default" replaces the built-in secrets file rules, so repeat
+// those patterns there when extending them.
+type CapturePolicy struct {
+	Projects map[string]CaptureRules `json:"projects,omitempty"`
+	Default  CaptureRules            `json:"default"`
+}
+
+// RulesFor returns the effective rules of project: the defaults followed by
+// the project's own rules.
+func (p CapturePolicy) RulesFor(project string) CaptureRules {
+	rules := CaptureRules{
+		ExcludeFiles:    append([]string(nil), p.Default.ExcludeFiles...),
+		IncludeFiles:    append([]string(nil), p.Default.IncludeFiles...),
+		ExcludeTools:    append([]string(nil), p.Default.ExcludeTools...),
+		ExcludeConcepts: append([]string(nil), p.Default.ExcludeConcepts...),
+	}
+	if extra, ok := p.Projects[project]; ok {
+		rules.ExcludeFiles = append(rules.ExcludeFiles, extra.ExcludeFiles...)
+		rules.IncludeFiles = append(rules.IncludeFiles, extra.IncludeFiles...)
+		rules.ExcludeTools = append(rules.ExcludeTools, extra.ExcludeTools...)
+		rules.ExcludeConcepts = append(rules.ExcludeConcepts, extra.ExcludeConcepts...)
+	}
+	return rules
+}
+
+// defaultCapturePolicy returns the built-in policy: secrets files excluded,
+// their templates allowed, no tool or concept rules.
+func defaultCapturePolicy() CapturePolicy {
+	return CapturePolicy{
+		Default: CaptureRules{
+			ExcludeFiles: append([]string(nil), DefaultCaptureExcludeFiles...),
+			IncludeFiles: append([]string(nil), DefaultCaptureIncludeFiles...),
+		},
+	}
+}
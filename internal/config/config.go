@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
+	"unicode"
 )
 
 const (
@@ -45,6 +47,7 @@ type Config struct {
 	VectorStorageStrategy     string `json:"vector_storage_strategy"`
 	DatabaseDSN               string   `json:"-"`                  // env-only: DATABASE_DSN (contains password, never JSON)
 	DatabaseMaxConns          int      `json:"database_max_conns"` // PostgreSQL pool size (default: 10)
+	DatabaseReplicaDSNs       []string `json:"-"`                  // env-only: DATABASE_REPLICA_DSNS, comma-separated read replicas
 	ContextObsConcepts        []string `json:"context_obs_concepts"`
 	ContextObsTypes           []string `json:"context_obs_types"`
 	ContextFullCount          int      `json:"context_full_count"`
@@ -55,7 +58,7 @@ type Config struct {
 	ContextSessionCount       int      `json:"context_session_count"`
 	MaxConns                  int      `json:"max_conns"`
 	HubThreshold              int      `json:"hub_threshold"`
-	WorkerHost                string   // env-only
+	WorkerHost                string   // ENGRAM_WORKER_HOST (env or settings file; default: 127.0.0.1)
 	WorkerToken               string   // env-only
 	CollectionConfigPath      string   // env-only
 	WorkstationID             string   // env-only: WORKSTATION_ID
@@ -69,8 +72,10 @@ type Config struct {
 	EncryptionKey             string   `json:"-"`                            // env-only: ENGRAM_ENCRYPTION_KEY (hex-encoded 256-bit key)
 	AlwaysInjectLimit         int      `json:"always_inject_limit"` // ENGRAM_ALWAYS_INJECT_LIMIT (default: 20)
 	ProjectInjectLimit        int      `json:"project_inject_limit"` // ENGRAM_PROJECT_INJECT_LIMIT (default: 15)
+	PinnedInjectLimit         int      `json:"pinned_inject_limit"`  // ENGRAM_PINNED_INJECT_LIMIT (default: 10) — cap on pinned memories injected ahead of scored results
 	InjectUnified             bool     `json:"inject_unified"`      // ENGRAM_INJECT_UNIFIED (default: true) — emergency rollback flag; removed after two release cycles
 	EnforceSourceProject      bool     `json:"enforce_source_project"` // ENGRAM_ENFORCE_SOURCE_PROJECT (default: true)
+	MCPReadOnly               bool     `json:"mcp_read_only"`          // ENGRAM_MCP_READ_ONLY (default: false) — expose only read tools over MCP
 	AuthSkipLocal             bool     `json:"auth_skip_local"`
 	AuthTrustedProxy          string   `json:"auth_trusted_proxy"`
 
@@ -82,6 +87,51 @@ type Config struct {
 	// Env: ENGRAM_OUTCOME_RECORDER_INTERVAL_MINUTES (default: 15)
 	OutcomeRecorderIntervalMinutes int `json:"outcome_recorder_interval_minutes"`
 
+	// Concept extraction (internal/concepts) tags memories stored without any
+	// concept of the taxonomy with the concepts their content suggests.
+	// ENGRAM_CONCEPT_EXTRACTION (default: true)
+	// ENGRAM_CONCEPT_MIN_CONFIDENCE: 0-1, confidence a concept needs (default: 0.5)
+	ConceptExtraction    bool    `json:"concept_extraction"`
+	ConceptMinConfidence float64 `json:"concept_min_confidence"`
+
+	// Observation processing pool (internal/worker/pool).
+	// ENGRAM_PROCESSING_WORKERS: max concurrently processed messages (default: 4)
+	// ENGRAM_PROCESSING_QUEUE_SIZE: pending messages before intake blocks (default: 1000)
+	// ENGRAM_PROCESSING_SLOW_MS: average latency that halves concurrency, 0 = off (default: 2000)
+	ProcessingWorkers   int `json:"processing_workers"`
+	ProcessingQueueSize int `json:"processing_queue_size"`
+	ProcessingSlowMs    int `json:"processing_slow_ms"`
+
+	// Worker request limits (internal/worker/validation.go).
+	// ENGRAM_MAX_REQUEST_BODY_BYTES: body limit of every request (default: 10 MiB)
+	// ENGRAM_MAX_HOOK_BODY_BYTES: body limit of the validated hook endpoints (default: 1 MiB)
+	// ENGRAM_MAX_FIELD_BYTES: limit of one field in a hook request body (default: 256 KiB)
+	MaxRequestBodyBytes int `json:"max_request_body_bytes"`
+	MaxHookBodyBytes    int `json:"max_hook_body_bytes"`
+	MaxFieldBytes       int `json:"max_field_bytes"`
+
+	// ObservationSchemaStrict rejects store_memory calls with an explicit type
+	// whose required schema sections (pkg/models.ObservationSchemas) are missing.
+	// When false, missing sections are reported as warnings only.
+	// Env: ENGRAM_OBSERVATION_SCHEMA_STRICT (default: false)
+	ObservationSchemaStrict bool `json:"observation_schema_strict"`
+
+	// QualityGateFloor is the get_observation_quality score (0-1) a memory
+	// stored through store_memory needs to enter lists, search and injection.
+	// Lower-scoring memories are held for review (review_observation).
+	// Env: ENGRAM_QUALITY_GATE_FLOOR, 0 = off (default: 0)
+	QualityGateFloor float64 `json:"quality_gate_floor"`
+
+	// Worker memory caps; /api/admin/gc trims the same caches on demand.
+	// ENGRAM_MAX_ACTIVE_SESSIONS: in-memory sessions; past it the oldest idle ones are evicted, 0 = unlimited (default: 500)
+	// ENGRAM_PROMPT_CACHE_MAX: last-prompt cache entries, 0 = unlimited (default: 1000)
+	// ENGRAM_SSE_MAX_CLIENTS: concurrent SSE connections, 0 = unlimited (default: 100)
+	// ENGRAM_SSE_REPLAY_BUFFER: events kept for Last-Event-ID replay (default: 256)
+	MaxActiveSessions int `json:"max_active_sessions"`
+	PromptCacheMax    int `json:"prompt_cache_max"`
+	SSEMaxClients     int `json:"sse_max_clients"`
+	SSEReplayBuffer   int `json:"sse_replay_buffer"`
+
 	// Authentik SSO forward-auth integration
 	// ENGRAM_AUTHENTIK_ENABLED: enable Authentik header detection (default: false)
 	// ENGRAM_AUTHENTIK_AUTO_PROVISION: auto-create users from Authentik headers (default: false)
@@ -89,6 +139,46 @@ type Config struct {
 	AuthentikEnabled        bool     `json:"authentik_enabled"`
 	AuthentikAutoProvision  bool     `json:"authentik_auto_provision"`
 	AuthentikTrustedProxies []string `json:"authentik_trusted_proxies"`
+
+	// CapturePolicy decides which files, tools and concepts may become
+	// memories (see capture.go). Settings file only: ENGRAM_CAPTURE_POLICY.
+	CapturePolicy CapturePolicy `json:"capture_policy"`
+
+	// Redaction configures the pre-storage redaction stage (see redaction.go).
+	// Settings file: ENGRAM_REDACTION. Env: ENGRAM_REDACT_EMAILS.
+	Redaction RedactionPolicy `json:"redaction"`
+
+	// ContextTemplate formats the pre-rendered block returned by
+	// /api/context/inject (see context_template.go).
+	// Settings file only: ENGRAM_CONTEXT_TEMPLATE.
+	ContextTemplate ContextTemplate `json:"context_template"`
+
+	// ContextTrustLine annotates each memory in the rendered context with a
+	// compact trust line (age, confirmations, score) so the model can weigh
+	// memories instead of treating them as equally authoritative. The trust
+	// parameter of /api/context/inject overrides it per request.
+	// Env: ENGRAM_CONTEXT_TRUST_LINE (default: false)
+	ContextTrustLine bool `json:"context_trust_line"`
+
+	// InjectionExperiment assigns sessions to competing injection strategies
+	// (see experiment.go). Settings file only: ENGRAM_INJECTION_EXPERIMENT.
+	InjectionExperiment InjectionExperiment `json:"injection_experiment"`
+
+	// Webhooks are the inbound webhook sources by name (see webhooks.go).
+	// Settings file: ENGRAM_WEBHOOKS. Env: ENGRAM_WEBHOOK_SECRET_<NAME>.
+	Webhooks map[string]WebhookSource `json:"webhooks"`
+
+	// OutboundWebhooks receive memory change events (see webhooks.go).
+	// Settings file only: ENGRAM_OUTBOUND_WEBHOOKS.
+	OutboundWebhooks []OutboundWebhook `json:"outbound_webhooks"`
+
+	// Worker TLS (internal/worker/listen.go). Settings file or env:
+	// ENGRAM_TLS_CERT / ENGRAM_TLS_KEY: PEM certificate and key paths
+	// ENGRAM_TLS_SELF_SIGNED: without a cert/key, generate a self-signed
+	// certificate under the data dir and reuse it across restarts (default: false)
+	TLSCert       string `json:"tls_cert"`
+	TLSKey        string `json:"tls_key"`
+	TLSSelfSigned bool   `json:"tls_self_signed"`
 }
 
 var (
@@ -180,9 +270,24 @@ func Default() *Config {
 		StoreMemoryDedupThreshold:      0.92,
 		AlwaysInjectLimit:              20,   // Inject up to 20 always-inject observations per session
 		ProjectInjectLimit:             15,   // Inject up to 15 project-scoped observations per session
+		PinnedInjectLimit:              10,   // Inject up to 10 pinned memories per session
 		InjectUnified:                  true, // Use unified RetrieveRelevant path for inject (FR-3). Set ENGRAM_INJECT_UNIFIED=false for emergency rollback.
 		EnforceSourceProject:           true, // Enforce source/project scoping on store/recall (T010)
 		OutcomeRecorderIntervalMinutes: 15,
+		ConceptExtraction:              true,
+		ConceptMinConfidence:           0.5,
+		ProcessingWorkers:              4,
+		ProcessingQueueSize:            1000,
+		ProcessingSlowMs:               2000,
+		MaxRequestBodyBytes:            10 << 20,
+		MaxHookBodyBytes:               1 << 20,
+		MaxFieldBytes:                  256 << 10,
+		MaxActiveSessions:              500,
+		PromptCacheMax:                 1000,
+		SSEMaxClients:                  100,
+		SSEReplayBuffer:                256,
+		CapturePolicy:                  defaultCapturePolicy(),
+		Redaction:                      RedactionPolicy{Emails: true},
 		SignalWeights: map[string]float64{
 			"git_commit":   1.0,
 			"pr_created":   2.0,
@@ -202,6 +307,14 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	// In strict mode a settings file with errors fails instead of falling
+	// back to defaults for the values it got wrong (see Validate).
+	if err == nil && Strict() {
+		if issues := Validate(data); (&Report{Issues: issues}).HasErrors() {
+			return nil, &ValidationError{Issues: issues}
+		}
+	}
+
 	// Load settings from JSON file (skip if file doesn't exist)
 	if err == nil {
 		var settings map[string]interface{}
@@ -209,7 +322,19 @@ func Load() (*Config, error) {
 			if v, ok := settings["ENGRAM_WORKER_PORT"].(float64); ok {
 				cfg.WorkerPort = int(v)
 			}
-			// WorkerHost and WorkerToken are env-only (not settable via JSON file).
+			// WorkerToken is env-only (not settable via JSON file).
+			if v, ok := settings["ENGRAM_WORKER_HOST"].(string); ok && strings.TrimSpace(v) != "" {
+				cfg.WorkerHost = strings.TrimSpace(v)
+			}
+			if v, ok := settings["ENGRAM_TLS_CERT"].(string); ok {
+				cfg.TLSCert = strings.TrimSpace(v)
+			}
+			if v, ok := settings["ENGRAM_TLS_KEY"].(string); ok {
+				cfg.TLSKey = strings.TrimSpace(v)
+			}
+			if v, ok := settings["ENGRAM_TLS_SELF_SIGNED"].(bool); ok {
+				cfg.TLSSelfSigned = v
+			}
 			if v, ok := settings["ENGRAM_DB_PATH"].(string); ok && v != "" {
 				cfg.DBPath = v
 			}
@@ -234,6 +359,48 @@ func Load() (*Config, error) {
 			if v, ok := settings["ENGRAM_CONTEXT_RELEVANCE_THRESHOLD"].(float64); ok && v >= 0 && v <= 1 {
 				cfg.ContextRelevanceThreshold = v
 			}
+			if v, ok := settings["ENGRAM_CONCEPT_EXTRACTION"].(bool); ok {
+				cfg.ConceptExtraction = v
+			}
+			if v, ok := settings["ENGRAM_CONCEPT_MIN_CONFIDENCE"].(float64); ok && v >= 0 && v <= 1 {
+				cfg.ConceptMinConfidence = v
+			}
+			if v, ok := settings["ENGRAM_PROCESSING_WORKERS"].(float64); ok && v > 0 {
+				cfg.ProcessingWorkers = int(v)
+			}
+			if v, ok := settings["ENGRAM_PROCESSING_QUEUE_SIZE"].(float64); ok && v > 0 {
+				cfg.ProcessingQueueSize = int(v)
+			}
+			if v, ok := settings["ENGRAM_PROCESSING_SLOW_MS"].(float64); ok && v >= 0 {
+				cfg.ProcessingSlowMs = int(v)
+			}
+			if v, ok := settings["ENGRAM_MAX_REQUEST_BODY_BYTES"].(float64); ok && v > 0 {
+				cfg.MaxRequestBodyBytes = int(v)
+			}
+			if v, ok := settings["ENGRAM_MAX_HOOK_BODY_BYTES"].(float64); ok && v > 0 {
+				cfg.MaxHookBodyBytes = int(v)
+			}
+			if v, ok := settings["ENGRAM_MAX_FIELD_BYTES"].(float64); ok && v > 0 {
+				cfg.MaxFieldBytes = int(v)
+			}
+			if v, ok := settings["ENGRAM_OBSERVATION_SCHEMA_STRICT"].(bool); ok {
+				cfg.ObservationSchemaStrict = v
+			}
+			if v, ok := settings["ENGRAM_QUALITY_GATE_FLOOR"].(float64); ok && v >= 0 && v <= 1 {
+				cfg.QualityGateFloor = v
+			}
+			if v, ok := settings["ENGRAM_MAX_ACTIVE_SESSIONS"].(float64); ok && v >= 0 {
+				cfg.MaxActiveSessions = int(v)
+			}
+			if v, ok := settings["ENGRAM_PROMPT_CACHE_MAX"].(float64); ok && v >= 0 {
+				cfg.PromptCacheMax = int(v)
+			}
+			if v, ok := settings["ENGRAM_SSE_MAX_CLIENTS"].(float64); ok && v >= 0 {
+				cfg.SSEMaxClients = int(v)
+			}
+			if v, ok := settings["ENGRAM_SSE_REPLAY_BUFFER"].(float64); ok && v > 0 {
+				cfg.SSEReplayBuffer = int(v)
+			}
 			if v, ok := settings["ENGRAM_CONTEXT_MAX_PROMPT_RESULTS"].(float64); ok && v >= 0 {
 				cfg.ContextMaxPromptResults = int(v)
 			}
@@ -246,10 +413,72 @@ func Load() (*Config, error) {
 			if v, ok := settings["ENGRAM_ENFORCE_SOURCE_PROJECT"].(bool); ok {
 				cfg.EnforceSourceProject = v
 			}
+			if v, ok := settings["ENGRAM_MCP_READ_ONLY"].(bool); ok {
+				cfg.MCPReadOnly = v
+			}
+			if v, ok := settings["ENGRAM_CONTEXT_TRUST_LINE"].(bool); ok {
+				cfg.ContextTrustLine = v
+			}
+		}
+
+		// The capture and redaction policies, the context template and the
+		// injection experiment are nested objects, so decode them separately.
+		// Each is decoded on its own: one invalid object does not drop the others.
+		var nested struct {
+			CapturePolicy *CapturePolicy `json:"ENGRAM_CAPTURE_POLICY"`
+			Redaction     *struct {
+				Emails   *bool               `json:"emails"`
+				Projects map[string][]string `json:"projects"`
+				Patterns []string            `json:"patterns"`
+			} `json:"ENGRAM_REDACTION"`
+			ContextTemplate     *ContextTemplate         `json:"ENGRAM_CONTEXT_TEMPLATE"`
+			InjectionExperiment *InjectionExperiment     `json:"ENGRAM_INJECTION_EXPERIMENT"`
+			Webhooks            map[string]WebhookSource `json:"ENGRAM_WEBHOOKS"`
+			OutboundWebhooks    []OutboundWebhook        `json:"ENGRAM_OUTBOUND_WEBHOOKS"`
+		}
+		var raw map[string]json.RawMessage
+		if json.Unmarshal(data, &raw) == nil {
+			nv := reflect.ValueOf(&nested).Elem()
+			for i := 0; i < nv.NumField(); i++ {
+				if v, ok := raw[nv.Type().Field(i).Tag.Get("json")]; ok {
+					field := reflect.New(nv.Field(i).Type())
+					if json.Unmarshal(v, field.Interface()) == nil {
+						nv.Field(i).Set(field.Elem())
+					}
+				}
+			}
+		}
+		if nested.CapturePolicy != nil {
+			if nested.CapturePolicy.Default.ExcludeFiles == nil && nested.CapturePolicy.Default.IncludeFiles == nil &&
+				nested.CapturePolicy.Default.ExcludeTools == nil && nested.CapturePolicy.Default.ExcludeConcepts == nil {
+				nested.CapturePolicy.Default = cfg.CapturePolicy.Default
+			}
+			cfg.CapturePolicy = *nested.CapturePolicy
+		}
+		if r := nested.Redaction; r != nil {
+			cfg.Redaction.Patterns = r.Patterns
+			cfg.Redaction.Projects = r.Projects
+			if r.Emails != nil {
+				cfg.Redaction.Emails = *r.Emails
+			}
+		}
+		if t := nested.ContextTemplate; t != nil {
+			cfg.ContextTemplate = *t
+		}
+		if e := nested.InjectionExperiment; e != nil {
+			cfg.InjectionExperiment = *e
+		}
+		if nested.Webhooks != nil {
+			cfg.Webhooks = nested.Webhooks
+		}
+		if nested.OutboundWebhooks != nil {
+			cfg.OutboundWebhooks = nested.OutboundWebhooks
 		}
 	}
 
 	// Environment variable overrides (take precedence over JSON settings)
+	applyWebhookSecretEnv(cfg.Webhooks)
+	dropInvalidWebhookSources(cfg.Webhooks)
 	if v := strings.TrimSpace(os.Getenv("ENGRAM_DB_PATH")); v != "" {
 		cfg.DBPath = v
 	}
@@ -259,6 +488,17 @@ func Load() (*Config, error) {
 	if v := strings.TrimSpace(os.Getenv("ENGRAM_AUTH_ADMIN_TOKEN")); v != "" {
 		cfg.WorkerToken = v
 	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_TLS_CERT")); v != "" {
+		cfg.TLSCert = v
+	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_TLS_KEY")); v != "" {
+		cfg.TLSKey = v
+	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_TLS_SELF_SIGNED")); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.TLSSelfSigned = b
+		}
+	}
 	if v := strings.TrimSpace(os.Getenv("ENGRAM_CONTEXT_MAX_TOKENS")); v != "" {
 		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
 			cfg.ContextMaxTokens = n
@@ -267,6 +507,9 @@ func Load() (*Config, error) {
 	if v := strings.TrimSpace(os.Getenv("DATABASE_DSN")); v != "" {
 		cfg.DatabaseDSN = v
 	}
+	if v := strings.TrimSpace(os.Getenv("DATABASE_REPLICA_DSNS")); v != "" {
+		cfg.DatabaseReplicaDSNs = splitTrim(v)
+	}
 	if v := strings.TrimSpace(os.Getenv("DATABASE_MAX_CONNS")); v != "" {
 		if n, err := strconv.Atoi(v); err == nil && n > 0 {
 			cfg.DatabaseMaxConns = n
@@ -300,6 +543,11 @@ func Load() (*Config, error) {
 			cfg.AlwaysInjectLimit = n
 		}
 	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_PINNED_INJECT_LIMIT")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.PinnedInjectLimit = n
+		}
+	}
 	if v := strings.TrimSpace(os.Getenv("ENGRAM_PROJECT_INJECT_LIMIT")); v != "" {
 		if n, err := strconv.Atoi(v); err == nil && n > 0 {
 			cfg.ProjectInjectLimit = n
@@ -315,11 +563,96 @@ func Load() (*Config, error) {
 			cfg.EnforceSourceProject = b
 		}
 	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_MCP_READ_ONLY")); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.MCPReadOnly = b
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_CONTEXT_TRUST_LINE")); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.ContextTrustLine = b
+		}
+	}
 	if v := strings.TrimSpace(os.Getenv("ENGRAM_OUTCOME_RECORDER_INTERVAL_MINUTES")); v != "" {
 		if n, err := strconv.Atoi(v); err == nil && n > 0 {
 			cfg.OutcomeRecorderIntervalMinutes = n
 		}
 	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_PROCESSING_WORKERS")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.ProcessingWorkers = n
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_PROCESSING_QUEUE_SIZE")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.ProcessingQueueSize = n
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_PROCESSING_SLOW_MS")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.ProcessingSlowMs = n
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_MAX_REQUEST_BODY_BYTES")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.MaxRequestBodyBytes = n
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_MAX_HOOK_BODY_BYTES")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.MaxHookBodyBytes = n
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_MAX_FIELD_BYTES")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.MaxFieldBytes = n
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_OBSERVATION_SCHEMA_STRICT")); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.ObservationSchemaStrict = b
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_QUALITY_GATE_FLOOR")); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f >= 0 && f <= 1 {
+			cfg.QualityGateFloor = f
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_MAX_ACTIVE_SESSIONS")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.MaxActiveSessions = n
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_PROMPT_CACHE_MAX")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.PromptCacheMax = n
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_SSE_MAX_CLIENTS")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.SSEMaxClients = n
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_SSE_REPLAY_BUFFER")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.SSEReplayBuffer = n
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_REDACT_EMAILS")); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.Redaction.Emails = b
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_CONCEPT_EXTRACTION")); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.ConceptExtraction = b
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv("ENGRAM_CONCEPT_MIN_CONFIDENCE")); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f >= 0 && f <= 1 {
+			cfg.ConceptMinConfidence = f
+		}
+	}
 
 	// Authentik SSO forward-auth integration
 	if v := strings.TrimSpace(os.Getenv("ENGRAM_AUTHENTIK_ENABLED")); v == "true" || v == "1" {
@@ -369,8 +702,24 @@ func Get() *Config {
 	return globalConfig
 }
 
+// restartFields are the settings a running worker cannot apply in place:
+// the database connection, the listen address, its TLS certificate, the
+// operator token and the request limits are bound at startup.
+var restartFields = map[string]bool{
+	"database_dsn":           true,
+	"worker_port":            true,
+	"worker_host":            true,
+	"worker_token":           true,
+	"tls_cert":               true,
+	"tls_key":                true,
+	"tls_self_signed":        true,
+	"max_request_body_bytes": true,
+	"max_hook_body_bytes":    true,
+	"max_field_bytes":        true,
+}
+
 // Reload re-reads configuration from disk and updates the global config atomically.
-// Returns the new config and any fields that changed.
+// Returns the new config and the names of the settings that changed (see Diff).
 func Reload() (*Config, []string, error) {
 	newCfg, err := Load()
 	if err != nil {
@@ -382,33 +731,57 @@ func Reload() (*Config, []string, error) {
 	globalConfig = newCfg
 	configMu.Unlock()
 
-	// Detect changed fields for logging
+	if old == nil {
+		return newCfg, nil, nil
+	}
+	return newCfg, Diff(old, newCfg), nil
+}
+
+// Diff returns the names of the settings that differ between old and next:
+// the JSON name of each field, or its snake_case name for env-only fields.
+func Diff(old, next *Config) []string {
+	ov, nv := reflect.ValueOf(old).Elem(), reflect.ValueOf(next).Elem()
+	t := ov.Type()
+
 	var changed []string
-	if old != nil {
-		if old.Model != newCfg.Model {
-			changed = append(changed, "model")
-		}
-		if old.ContextMaxTokens != newCfg.ContextMaxTokens {
-			changed = append(changed, "context_max_tokens")
-		}
-		if old.ContextObservations != newCfg.ContextObservations {
-			changed = append(changed, "context_observations")
-		}
-		if old.WorkerPort != newCfg.WorkerPort {
-			changed = append(changed, "worker_port (requires restart)")
-		}
-		if old.WorkerToken != newCfg.WorkerToken {
-			changed = append(changed, "worker_token (requires restart)")
-		}
-		if old.AuthSkipLocal != newCfg.AuthSkipLocal {
-			changed = append(changed, "auth_skip_local")
+	for i := 0; i < t.NumField(); i++ {
+		if !reflect.DeepEqual(ov.Field(i).Interface(), nv.Field(i).Interface()) {
+			changed = append(changed, settingName(t.Field(i)))
 		}
-		if old.AuthTrustedProxy != newCfg.AuthTrustedProxy {
-			changed = append(changed, "auth_trusted_proxy")
+	}
+	return changed
+}
+
+// RequiresRestart reports whether any of the changed settings (as returned by
+// Diff) can only take effect after the worker restarts.
+func RequiresRestart(changed []string) bool {
+	for _, name := range changed {
+		if restartFields[name] {
+			return true
 		}
 	}
+	return false
+}
 
-	return newCfg, changed, nil
+func settingName(f reflect.StructField) string {
+	if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	var b strings.Builder
+	for i, r := range f.Name {
+		if unicode.IsUpper(r) {
+			// Start a new word at an upper-case letter that follows a
+			// lower-case one or begins a word after an acronym (DSNPath).
+			prevLower := i > 0 && unicode.IsLower(rune(f.Name[i-1]))
+			nextLower := i+1 < len(f.Name) && unicode.IsLower(rune(f.Name[i+1]))
+			if i > 0 && (prevLower || (nextLower && unicode.IsUpper(rune(f.Name[i-1])))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
 }
 
 // GetWorkerPort returns the worker port from environment or config.
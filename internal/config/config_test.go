@@ -69,6 +69,161 @@ func (s *ConfigSuite) TestInjectUnifiedEnvOverride() {
 	s.False(cfg.InjectUnified, "ENGRAM_INJECT_UNIFIED=false must activate the legacy inject path")
 }
 
+// TestContextTrustLineEnv verifies that trust lines are off by default and
+// ENGRAM_CONTEXT_TRUST_LINE turns them on.
+func (s *ConfigSuite) TestContextTrustLineEnv() {
+	cfg, err := Load()
+	s.Require().NoError(err)
+	s.False(cfg.ContextTrustLine)
+
+	s.T().Setenv("ENGRAM_CONTEXT_TRUST_LINE", "true")
+	cfg, err = Load()
+	s.Require().NoError(err)
+	s.True(cfg.ContextTrustLine)
+}
+
+// TestCapturePolicyFromSettings verifies that ENGRAM_CAPTURE_POLICY project
+// rules load while the built-in default rules are kept.
+func (s *ConfigSuite) TestCapturePolicyFromSettings() {
+	s.Require().NoError(EnsureDataDir())
+	settings := `{"ENGRAM_CAPTURE_POLICY": {"projects": {"infra": {"exclude_files": ["terraform/**"]}}}}`
+	s.Require().NoError(os.WriteFile(SettingsPath(), []byte(settings), 0600))
+
+	cfg, err := Load()
+	s.Require().NoError(err)
+	s.Equal(DefaultCaptureExcludeFiles, cfg.CapturePolicy.Default.ExcludeFiles)
+	rules := cfg.CapturePolicy.RulesFor("infra")
+	s.Contains(rules.ExcludeFiles, "terraform/**")
+	s.Contains(rules.ExcludeFiles, ".env")
+	s.NotContains(cfg.CapturePolicy.RulesFor("app").ExcludeFiles, "terraform/**")
+}
+
+// TestRedactionFromSettings verifies that ENGRAM_REDACTION patterns load and
+// that e-mail redaction stays on unless disabled.
+func (s *ConfigSuite) TestRedactionFromSettings() {
+	s.Require().NoError(EnsureDataDir())
+	settings := `{"ENGRAM_REDACTION": {"patterns": ["ACME-[0-9]+"], "projects": {"billing": ["cust_[a-z0-9]+"]}}}`
+	s.Require().NoError(os.WriteFile(SettingsPath(), []byte(settings), 0600))
+
+	cfg, err := Load()
+	s.Require().NoError(err)
+	s.True(cfg.Redaction.Emails)
+	s.Equal([]string{"ACME-[0-9]+", "cust_[a-z0-9]+"}, cfg.Redaction.PatternsFor("billing"))
+	s.Equal([]string{"ACME-[0-9]+"}, cfg.Redaction.PatternsFor("app"))
+
+	s.T().Setenv("ENGRAM_REDACT_EMAILS", "false")
+	cfg, err = Load()
+	s.Require().NoError(err)
+	s.False(cfg.Redaction.Emails)
+}
+
+// TestContextTemplateFromSettings verifies that ENGRAM_CONTEXT_TEMPLATE loads
+// and that project overrides fall back field by field to the defaults.
+func (s *ConfigSuite) TestContextTemplateFromSettings() {
+	s.Require().NoError(EnsureDataDir())
+	settings := `{"ENGRAM_CONTEXT_TEMPLATE": {"default": {"fact": "* {{.Fact}}\n"}, "projects": {"docs": {"header": "Docs\n"}}}}`
+	s.Require().NoError(os.WriteFile(SettingsPath(), []byte(settings), 0600))
+
+	cfg, err := Load()
+	s.Require().NoError(err)
+	docs := cfg.ContextTemplate.LayoutFor("docs")
+	s.Equal("Docs\n", docs.Header)
+	s.Equal("* {{.Fact}}\n", docs.Fact)
+	s.Equal(DefaultContextItem, docs.Item)
+	s.Equal(DefaultContextHeader, cfg.ContextTemplate.LayoutFor("app").Header)
+}
+
+// TestInjectionExperimentFromSettings verifies that ENGRAM_INJECTION_EXPERIMENT
+// loads with its arms.
+func (s *ConfigSuite) TestInjectionExperimentFromSettings() {
+	s.Require().NoError(EnsureDataDir())
+	settings := `{"ENGRAM_INJECTION_EXPERIMENT": {"name": "digest-vs-full", "arms": [{"name": "full"}, {"name": "digest", "format": "digest", "max_results": 5}]}}`
+	s.Require().NoError(os.WriteFile(SettingsPath(), []byte(settings), 0600))
+
+	cfg, err := Load()
+	s.Require().NoError(err)
+	exp := cfg.InjectionExperiment
+	s.Require().NoError(exp.Validate())
+	s.Equal("digest-vs-full", exp.Name)
+	s.Require().Len(exp.Arms, 2)
+	s.Equal(ExperimentArm{Name: "digest", Format: ExperimentFormatDigest, MaxResults: 5}, exp.Arms[1])
+}
+
+// TestListenerFromSettings verifies that the bind address and TLS settings
+// load from the settings file and that the environment overrides them.
+func (s *ConfigSuite) TestListenerFromSettings() {
+	s.Require().NoError(EnsureDataDir())
+	settings := `{"ENGRAM_WORKER_HOST": "192.168.1.20", "ENGRAM_TLS_SELF_SIGNED": true}`
+	s.Require().NoError(os.WriteFile(SettingsPath(), []byte(settings), 0600))
+
+	cfg, err := Load()
+	s.Require().NoError(err)
+	s.Equal("192.168.1.20", cfg.WorkerHost)
+	s.True(cfg.TLSSelfSigned)
+	s.Empty(cfg.TLSCert)
+
+	s.T().Setenv("ENGRAM_WORKER_HOST", "0.0.0.0")
+	s.T().Setenv("ENGRAM_TLS_CERT", "/etc/engram/tls.crt")
+	s.T().Setenv("ENGRAM_TLS_SELF_SIGNED", "false")
+	cfg, err = Load()
+	s.Require().NoError(err)
+	s.Equal("0.0.0.0", cfg.WorkerHost)
+	s.Equal("/etc/engram/tls.crt", cfg.TLSCert)
+	s.False(cfg.TLSSelfSigned)
+}
+
+// TestRequestLimitsFromSettings verifies the request size limits: defaults,
+// settings file and environment override.
+func (s *ConfigSuite) TestRequestLimitsFromSettings() {
+	s.Require().NoError(EnsureDataDir())
+	s.Require().NoError(os.WriteFile(SettingsPath(), []byte(`{"ENGRAM_MAX_HOOK_BODY_BYTES": 65536}`), 0600))
+
+	cfg, err := Load()
+	s.Require().NoError(err)
+	s.Equal(10<<20, cfg.MaxRequestBodyBytes)
+	s.Equal(65536, cfg.MaxHookBodyBytes)
+	s.Equal(256<<10, cfg.MaxFieldBytes)
+
+	s.T().Setenv("ENGRAM_MAX_FIELD_BYTES", "4096")
+	cfg, err = Load()
+	s.Require().NoError(err)
+	s.Equal(4096, cfg.MaxFieldBytes)
+}
+
+// TestWebhooksFromSettings verifies webhook sources are read from the
+// settings file and their secrets overridden from the environment.
+func (s *ConfigSuite) TestWebhooksFromSettings() {
+	s.Require().NoError(EnsureDataDir())
+	s.Require().NoError(os.WriteFile(SettingsPath(), []byte(`{"ENGRAM_WEBHOOKS": {"github": {"project": "engram", "secret": "from-file"}, "build-ci": {"project": "engram", "templates": {"*": "{{.status}}"}}}}`), 0600))
+	s.T().Setenv("ENGRAM_WEBHOOK_SECRET_BUILD_CI", "from-env")
+
+	cfg, err := Load()
+	s.Require().NoError(err)
+	s.Require().Len(cfg.Webhooks, 2)
+	s.Equal("from-file", cfg.Webhooks["github"].Secret)
+	s.Equal(WebhookKindGitHub, cfg.Webhooks["github"].KindOf("github"))
+	s.Equal("from-env", cfg.Webhooks["build-ci"].Secret)
+	s.Equal(WebhookKindGeneric, cfg.Webhooks["build-ci"].KindOf("build-ci"))
+	s.Equal("{{.status}}", cfg.Webhooks["build-ci"].Templates["*"])
+}
+
+// TestWebhooksInvalidTenant verifies that a source with an invalid tenant
+// name is dropped at load.
+func (s *ConfigSuite) TestWebhooksInvalidTenant() {
+	s.Require().NoError(EnsureDataDir())
+	s.Require().NoError(os.WriteFile(SettingsPath(), []byte(`{"ENGRAM_WEBHOOKS": {"ok": {"project": "p", "tenant": "acme"}, "bad": {"project": "p", "tenant": "Acme Corp"}}}`), 0600))
+
+	cfg, err := Load()
+	s.Require().NoError(err)
+	s.Require().Len(cfg.Webhooks, 1)
+	s.Equal("acme", cfg.Webhooks["ok"].Tenant)
+
+	issues := Validate([]byte(`{"ENGRAM_WEBHOOKS": {"bad": {"project": "p", "tenant": "Acme Corp"}}}`))
+	s.Require().Len(issues, 1)
+	s.Equal(SeverityError, issues[0].Severity)
+	s.Contains(issues[0].Message, `source "bad": tenant "Acme Corp"`)
+}
+
 // TestDataDir tests data directory path.
 func (s *ConfigSuite) TestDataDir() {
 	dir := DataDir()
@@ -426,3 +581,24 @@ func TestLoad_ContextSettings(t *testing.T) {
 	assert.Equal(t, []string{"bugfix", "feature"}, cfg.ContextObsTypes)
 	assert.Equal(t, []string{"security", "performance"}, cfg.ContextObsConcepts)
 }
+
+// TestDiffAndRequiresRestart verifies setting names reported by Diff and
+// which of them need a restart.
+func (s *ConfigSuite) TestDiffAndRequiresRestart() {
+	old := Default()
+	next := Default()
+	s.Empty(Diff(old, next))
+
+	next.ContextMaxTokens++
+	next.CapturePolicy.Default.ExcludeTools = []string{"TodoWrite"}
+	changed := Diff(old, next)
+	s.ElementsMatch([]string{"context_max_tokens", "capture_policy"}, changed)
+	s.False(RequiresRestart(changed))
+
+	next.WorkerHost = "0.0.0.0"
+	next.DatabaseDSN = "postgres://other"
+	changed = Diff(old, next)
+	s.Contains(changed, "worker_host")
+	s.Contains(changed, "database_dsn")
+	s.True(RequiresRestart(changed))
+}
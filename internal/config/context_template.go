@@ -0,0 +1,53 @@
+package config
+
+// Default layouts of the context block rendered for /api/context/inject.
+// They are Go text/template strings (see internal/worker/context_render.go
+// for the fields available to each).
+const (
+	DefaultContextHeader = "# Engram memory for {{.Project}}\nPrefer these memories before rediscovering context.\n"
+	DefaultContextItem   = "- [{{.Type}}] {{.Marker}}{{.Title}}{{if .Trust}} ({{.Trust}}){{end}}{{if .Narrative}}\n  {{.Narrative}}{{end}}\n{{.Facts}}"
+	DefaultContextFact   = "  - {{.Fact}}\n"
+)
+
+// ContextLayout is one set of context templates. Header is rendered once at
+// the top of the block, Item once per memory and Fact once per key fact of a
+// memory. Empty fields fall back to the defaults.
+type ContextLayout struct {
+	Header string `json:"header,omitempty"`
+	Item   string `json:"item,omitempty"`
+	Fact   string `json:"fact,omitempty"`
+}
+
+// ContextTemplate configures how injected context is formatted server-side.
+// Default applies to every project, Projects overrides individual fields for
+// single projects.
+//
+// Settings file key: ENGRAM_CONTEXT_TEMPLATE, e.g.
+//
+//	"ENGRAM_CONTEXT_TEMPLATE": {"default": {"fact": "    * {{.Fact}}\n"}, "projects": {"docs": {"header": "# Docs memory\n"}}}
+type ContextTemplate struct {
+	Projects map[string]ContextLayout `json:"projects,omitempty"`
+	Default  ContextLayout            `json:"default"`
+}
+
+// LayoutFor returns the templates applied to project: its overrides, then the
+// configured default, then the built-in defaults.
+func (t ContextTemplate) LayoutFor(project string) ContextLayout {
+	layout := ContextLayout{
+		Header: DefaultContextHeader,
+		Item:   DefaultContextItem,
+		Fact:   DefaultContextFact,
+	}
+	for _, l := range []ContextLayout{t.Default, t.Projects[project]} {
+		if l.Header != "" {
+			layout.Header = l.Header
+		}
+		if l.Item != "" {
+			layout.Item = l.Item
+		}
+		if l.Fact != "" {
+			layout.Fact = l.Fact
+		}
+	}
+	return layout
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// Formats of the context block an experiment arm can render.
+const (
+	ExperimentFormatFull   = "full"
+	ExperimentFormatDigest = "digest"
+)
+
+// DigestContextItem is the per-memory template of the digest format: titles
+// only, without narrative or facts.
+const DigestContextItem = "- [{{.Type}}] {{.Marker}}{{.Title}}\n"
+
+// ExperimentArm is one injection strategy of an experiment. Zero fields keep
+// the configured defaults.
+type ExperimentArm struct {
+	// Name identifies the arm in session assignments and the report.
+	Name string `json:"name"`
+	// MaxResults caps the relevant section of /api/context/inject and the
+	// results of /api/context/search.
+	MaxResults int `json:"max_results,omitempty"`
+	// FullCount is the number of memories given full detail in the compact
+	// inject format.
+	FullCount int `json:"full_count,omitempty"`
+	// Format is "full" (the default) or "digest".
+	Format string `json:"format,omitempty"`
+}
+
+// InjectionExperiment compares context injection strategies. Every session
+// is assigned one arm, deterministically from its ID, and keeps it for its
+// whole lifetime; outcomes are reported per arm by /api/experiments/report.
+//
+// Settings file key: ENGRAM_INJECTION_EXPERIMENT, e.g.
+//
+//	"ENGRAM_INJECTION_EXPERIMENT": {"name": "digest-vs-full", "arms": [{"name": "full"}, {"name": "digest", "format": "digest"}]}
+type InjectionExperiment struct {
+	Name string          `json:"name"`
+	Arms []ExperimentArm `json:"arms"`
+}
+
+// Enabled reports whether the experiment is configured.
+func (e InjectionExperiment) Enabled() bool {
+	return e.Name != "" && len(e.Arms) > 0
+}
+
+// Validate checks that the experiment has a name, at least two uniquely
+// named arms and known formats.
+func (e InjectionExperiment) Validate() error {
+	if e.Name == "" {
+		return fmt.Errorf("experiment name required")
+	}
+	if len(e.Arms) < 2 {
+		return fmt.Errorf("experiment %q needs at least two arms", e.Name)
+	}
+	seen := make(map[string]bool, len(e.Arms))
+	for _, arm := range e.Arms {
+		if arm.Name == "" {
+			return fmt.Errorf("experiment %q: arm name required", e.Name)
+		}
+		if seen[arm.Name] {
+			return fmt.Errorf("experiment %q: duplicate arm %q", e.Name, arm.Name)
+		}
+		seen[arm.Name] = true
+		if arm.MaxResults < 0 || arm.FullCount < 0 {
+			return fmt.Errorf("experiment %q: arm %q: limits must not be negative", e.Name, arm.Name)
+		}
+		switch arm.Format {
+		case "", ExperimentFormatFull, ExperimentFormatDigest:
+		default:
+			return fmt.Errorf("experiment %q: arm %q: unknown format %q", e.Name, arm.Name, arm.Format)
+		}
+	}
+	return nil
+}
+
+// ArmFor returns the arm assigned to sessionID. The assignment hashes the
+// experiment name with the session ID, so it is stable across requests and
+// restarts and independent between experiments.
+func (e InjectionExperiment) ArmFor(sessionID string) (ExperimentArm, bool) {
+	if !e.Enabled() || sessionID == "" {
+		return ExperimentArm{}, false
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(e.Name))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(sessionID))
+	return e.Arms[h.Sum32()%uint32(len(e.Arms))], true
+}
+
+// Layout applies the arm's format to layout.
+func (a ExperimentArm) Layout(layout ContextLayout) ContextLayout {
+	if a.Format == ExperimentFormatDigest {
+		layout.Item = DigestContextItem
+	}
+	return layout
+}
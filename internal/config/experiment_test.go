@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInjectionExperiment_Validate(t *testing.T) {
+	valid := InjectionExperiment{Name: "digest", Arms: []ExperimentArm{{Name: "full"}, {Name: "digest", Format: ExperimentFormatDigest}}}
+	assert.NoError(t, valid.Validate())
+
+	for name, exp := range map[string]InjectionExperiment{
+		"no name":        {Arms: valid.Arms},
+		"one arm":        {Name: "x", Arms: []ExperimentArm{{Name: "a"}}},
+		"unnamed arm":    {Name: "x", Arms: []ExperimentArm{{Name: "a"}, {}}},
+		"duplicate arm":  {Name: "x", Arms: []ExperimentArm{{Name: "a"}, {Name: "a"}}},
+		"unknown format": {Name: "x", Arms: []ExperimentArm{{Name: "a"}, {Name: "b", Format: "terse"}}},
+		"negative limit": {Name: "x", Arms: []ExperimentArm{{Name: "a"}, {Name: "b", MaxResults: -1}}},
+	} {
+		assert.Error(t, exp.Validate(), name)
+	}
+}
+
+// TestInjectionExperiment_ArmFor checks that assignment is stable per session
+// and spreads sessions over every arm.
+func TestInjectionExperiment_ArmFor(t *testing.T) {
+	exp := InjectionExperiment{Name: "limits", Arms: []ExperimentArm{{Name: "a", MaxResults: 5}, {Name: "b", MaxResults: 15}}}
+
+	_, ok := exp.ArmFor("")
+	assert.False(t, ok, "sessions without an ID are not assigned")
+	_, ok = InjectionExperiment{}.ArmFor("sess-1")
+	assert.False(t, ok, "no experiment configured")
+
+	counts := map[string]int{}
+	for i := 0; i < 200; i++ {
+		session := fmt.Sprintf("sess-%d", i)
+		arm, ok := exp.ArmFor(session)
+		assert.True(t, ok)
+		again, _ := exp.ArmFor(session)
+		assert.Equal(t, arm, again, "assignment must be stable")
+		counts[arm.Name]++
+	}
+	assert.Greater(t, counts["a"], 50)
+	assert.Greater(t, counts["b"], 50)
+}
+
+func TestExperimentArm_Layout(t *testing.T) {
+	layout := ContextTemplate{}.LayoutFor("app")
+	assert.Equal(t, layout, ExperimentArm{Name: "full"}.Layout(layout))
+
+	digest := ExperimentArm{Name: "digest", Format: ExperimentFormatDigest}.Layout(layout)
+	assert.Equal(t, DigestContextItem, digest.Item)
+	assert.Equal(t, layout.Header, digest.Header)
+}
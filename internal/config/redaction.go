@@ -0,0 +1,24 @@
+package config
+
+// RedactionPolicy configures the redaction stage that scrubs memories,
+// prompts and indexed transcripts before they are stored (internal/privacy).
+// Secrets, API keys and JWTs are always redacted; e-mail addresses unless
+// Emails is false. Patterns are extra regular expressions applied to every
+// project, Projects the extra patterns of individual projects.
+//
+// Settings file key: ENGRAM_REDACTION, e.g.
+//
+//	"ENGRAM_REDACTION": {"patterns": ["ACME-[0-9]{6}"], "projects": {"billing": ["cust_[a-z0-9]{14}"]}}
+//
+// Env: ENGRAM_REDACT_EMAILS (default: true)
+type RedactionPolicy struct {
+	Projects map[string][]string `json:"projects,omitempty"`
+	Patterns []string            `json:"patterns,omitempty"`
+	Emails   bool                `json:"emails"`
+}
+
+// PatternsFor returns the custom patterns applied to project.
+func (p RedactionPolicy) PatternsFor(project string) []string {
+	patterns := append([]string(nil), p.Patterns...)
+	return append(patterns, p.Projects[project]...)
+}
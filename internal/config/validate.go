@@ -0,0 +1,550 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Issue severities. An error means the setting (or, for invalid JSON, the
+// whole file) is ignored; a warning means it is ignored or suspicious but
+// harmless.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Issue is one problem found in the settings file.
+type Issue struct {
+	// Key is the settings key, empty for problems with the file as a whole.
+	Key      string `json:"key,omitempty"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+	// Line and Column locate the key (or the syntax error), 1-based.
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+}
+
+// String formats the issue as settings.json:LINE:COL: KEY: MESSAGE.
+func (i Issue) String() string {
+	var b strings.Builder
+	b.WriteString(filepath.Base(SettingsPath()))
+	if i.Line > 0 {
+		fmt.Fprintf(&b, ":%d:%d", i.Line, i.Column)
+	}
+	b.WriteString(": ")
+	if i.Key != "" {
+		b.WriteString(i.Key + ": ")
+	}
+	b.WriteString(i.Message)
+	return b.String()
+}
+
+// Report is the result of checking the settings file.
+type Report struct {
+	Path   string  `json:"path"`
+	Issues []Issue `json:"issues"`
+}
+
+// HasErrors reports whether any issue is an error.
+func (r *Report) HasErrors() bool {
+	for _, i := range r.Issues {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidationError is returned by Load in strict mode (ENGRAM_CONFIG_STRICT)
+// when the settings file has errors.
+type ValidationError struct {
+	Issues []Issue
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Issues))
+	for _, i := range e.Issues {
+		if i.Severity == SeverityError {
+			msgs = append(msgs, i.String())
+		}
+	}
+	return "invalid settings: " + strings.Join(msgs, "; ")
+}
+
+// Strict reports whether ENGRAM_CONFIG_STRICT is set: settings file errors
+// then fail Load (and startup) instead of falling back to defaults.
+func Strict() bool {
+	v := strings.TrimSpace(os.Getenv("ENGRAM_CONFIG_STRICT"))
+	return v == "true" || v == "1"
+}
+
+// settingKind is the JSON type a setting takes in the settings file.
+type settingKind string
+
+const (
+	kindInteger settingKind = "integer"
+	kindNumber  settingKind = "number"
+	kindBool    settingKind = "boolean"
+	kindString  settingKind = "string"
+	kindObject  settingKind = "object"
+	kindArray   settingKind = "array"
+)
+
+// settingSpec describes one setting: where Load reads it from and which
+// values it accepts. The ranges mirror the checks in Load.
+type settingSpec struct {
+	// decode strictly decodes an object or array setting into its type.
+	decode func(dec *json.Decoder) error
+	// check reports values that decode but that Load rejects.
+	check func(raw []byte) []Issue
+	min   *float64
+	max   *float64
+	// Key is the settings file key (and, for env-only settings, the env var).
+	Key string
+	// Field is the Config field, by settingName.
+	Field string
+	Kind  settingKind
+	// Env lists the environment variables that override the setting.
+	Env []string
+	// File is false for settings read from the environment only.
+	File bool
+	// Secret values are never shown by Effective.
+	Secret bool
+}
+
+func bound(v float64) *float64 { return &v }
+
+func decodeInto[T any]() func(dec *json.Decoder) error {
+	return func(dec *json.Decoder) error {
+		var v T
+		return dec.Decode(&v)
+	}
+}
+
+func fileSetting(key, field string, kind settingKind, min, max *float64, env ...string) settingSpec {
+	return settingSpec{Key: key, Field: field, Kind: kind, min: min, max: max, Env: env, File: true}
+}
+
+func envSetting(key, field string, secret bool, env ...string) settingSpec {
+	if len(env) == 0 {
+		env = []string{key}
+	}
+	return settingSpec{Key: key, Field: field, Env: env, Secret: secret}
+}
+
+// settingSpecs lists every setting Load reads: settings file keys first, then
+// the environment-only ones.
+var settingSpecs = []settingSpec{
+	fileSetting("ENGRAM_WORKER_PORT", "worker_port", kindInteger, nil, nil),
+	fileSetting("ENGRAM_WORKER_HOST", "worker_host", kindString, nil, nil, "ENGRAM_WORKER_HOST"),
+	fileSetting("ENGRAM_TLS_CERT", "tls_cert", kindString, nil, nil, "ENGRAM_TLS_CERT"),
+	fileSetting("ENGRAM_TLS_KEY", "tls_key", kindString, nil, nil, "ENGRAM_TLS_KEY"),
+	fileSetting("ENGRAM_TLS_SELF_SIGNED", "tls_self_signed", kindBool, nil, nil, "ENGRAM_TLS_SELF_SIGNED"),
+	fileSetting("ENGRAM_DB_PATH", "db_path", kindString, nil, nil, "ENGRAM_DB_PATH"),
+	fileSetting("ENGRAM_MODEL", "model", kindString, nil, nil),
+	fileSetting("ENGRAM_CONTEXT_OBSERVATIONS", "context_observations", kindInteger, nil, nil),
+	fileSetting("ENGRAM_CONTEXT_FULL_COUNT", "context_full_count", kindInteger, nil, nil),
+	fileSetting("ENGRAM_CONTEXT_SESSION_COUNT", "context_session_count", kindInteger, nil, nil),
+	fileSetting("ENGRAM_CONTEXT_OBS_TYPES", "context_obs_types", kindString, nil, nil),
+	fileSetting("ENGRAM_CONTEXT_OBS_CONCEPTS", "context_obs_concepts", kindString, nil, nil),
+	fileSetting("ENGRAM_CONTEXT_RELEVANCE_THRESHOLD", "context_relevance_threshold", kindNumber, bound(0), bound(1)),
+	fileSetting("ENGRAM_CONCEPT_EXTRACTION", "concept_extraction", kindBool, nil, nil, "ENGRAM_CONCEPT_EXTRACTION"),
+	fileSetting("ENGRAM_CONCEPT_MIN_CONFIDENCE", "concept_min_confidence", kindNumber, bound(0), bound(1), "ENGRAM_CONCEPT_MIN_CONFIDENCE"),
+	fileSetting("ENGRAM_PROCESSING_WORKERS", "processing_workers", kindInteger, bound(1), nil, "ENGRAM_PROCESSING_WORKERS"),
+	fileSetting("ENGRAM_PROCESSING_QUEUE_SIZE", "processing_queue_size", kindInteger, bound(1), nil, "ENGRAM_PROCESSING_QUEUE_SIZE"),
+	fileSetting("ENGRAM_PROCESSING_SLOW_MS", "processing_slow_ms", kindInteger, bound(0), nil, "ENGRAM_PROCESSING_SLOW_MS"),
+	fileSetting("ENGRAM_MAX_REQUEST_BODY_BYTES", "max_request_body_bytes", kindInteger, bound(1), nil, "ENGRAM_MAX_REQUEST_BODY_BYTES"),
+	fileSetting("ENGRAM_MAX_HOOK_BODY_BYTES", "max_hook_body_bytes", kindInteger, bound(1), nil, "ENGRAM_MAX_HOOK_BODY_BYTES"),
+	fileSetting("ENGRAM_MAX_FIELD_BYTES", "max_field_bytes", kindInteger, bound(1), nil, "ENGRAM_MAX_FIELD_BYTES"),
+	fileSetting("ENGRAM_OBSERVATION_SCHEMA_STRICT", "observation_schema_strict", kindBool, nil, nil, "ENGRAM_OBSERVATION_SCHEMA_STRICT"),
+	fileSetting("ENGRAM_QUALITY_GATE_FLOOR", "quality_gate_floor", kindNumber, bound(0), bound(1), "ENGRAM_QUALITY_GATE_FLOOR"),
+	fileSetting("ENGRAM_MAX_ACTIVE_SESSIONS", "max_active_sessions", kindInteger, bound(0), nil, "ENGRAM_MAX_ACTIVE_SESSIONS"),
+	fileSetting("ENGRAM_PROMPT_CACHE_MAX", "prompt_cache_max", kindInteger, bound(0), nil, "ENGRAM_PROMPT_CACHE_MAX"),
+	fileSetting("ENGRAM_SSE_MAX_CLIENTS", "sse_max_clients", kindInteger, bound(0), nil, "ENGRAM_SSE_MAX_CLIENTS"),
+	fileSetting("ENGRAM_SSE_REPLAY_BUFFER", "sse_replay_buffer", kindInteger, bound(1), nil, "ENGRAM_SSE_REPLAY_BUFFER"),
+	fileSetting("ENGRAM_CONTEXT_MAX_PROMPT_RESULTS", "context_max_prompt_results", kindInteger, bound(0), nil),
+	fileSetting("ENGRAM_VECTOR_STORAGE_STRATEGY", "vector_storage_strategy", kindString, nil, nil),
+	fileSetting("ENGRAM_HUB_THRESHOLD", "hub_threshold", kindInteger, bound(1), nil),
+	fileSetting("ENGRAM_ENFORCE_SOURCE_PROJECT", "enforce_source_project", kindBool, nil, nil, "ENGRAM_ENFORCE_SOURCE_PROJECT"),
+	fileSetting("ENGRAM_MCP_READ_ONLY", "mcp_read_only", kindBool, nil, nil, "ENGRAM_MCP_READ_ONLY"),
+	fileSetting("ENGRAM_CONTEXT_TRUST_LINE", "context_trust_line", kindBool, nil, nil, "ENGRAM_CONTEXT_TRUST_LINE"),
+	{Key: "ENGRAM_CAPTURE_POLICY", Field: "capture_policy", Kind: kindObject, File: true, decode: decodeInto[CapturePolicy]()},
+	{Key: "ENGRAM_REDACTION", Field: "redaction", Kind: kindObject, File: true, Env: []string{"ENGRAM_REDACT_EMAILS"}, decode: decodeInto[RedactionPolicy]()},
+	{Key: "ENGRAM_CONTEXT_TEMPLATE", Field: "context_template", Kind: kindObject, File: true, decode: decodeInto[ContextTemplate]()},
+	{Key: "ENGRAM_INJECTION_EXPERIMENT", Field: "injection_experiment", Kind: kindObject, File: true, decode: decodeInto[InjectionExperiment]()},
+	{Key: "ENGRAM_WEBHOOKS", Field: "webhooks", Kind: kindObject, File: true, decode: decodeInto[map[string]WebhookSource](), check: checkWebhookSources},
+	{Key: "ENGRAM_OUTBOUND_WEBHOOKS", Field: "outbound_webhooks", Kind: kindArray, File: true, decode: decodeInto[[]OutboundWebhook]()},
+
+	envSetting("ENGRAM_AUTH_ADMIN_TOKEN", "worker_token", true),
+	envSetting("DATABASE_DSN", "database_dsn", true),
+	envSetting("DATABASE_REPLICA_DSNS", "database_replica_dsns", true),
+	envSetting("DATABASE_MAX_CONNS", "database_max_conns", false),
+	envSetting("COLLECTION_CONFIG", "collection_config_path", false),
+	envSetting("WORKSTATION_ID", "workstation_id", false),
+	envSetting("ENGRAM_TELEMETRY_ENABLED", "telemetry_enabled", false),
+	envSetting("ENGRAM_LOG_BUFFER_SIZE", "log_buffer_size", false),
+	envSetting("ENGRAM_ENCRYPTION_KEY_FILE", "encryption_key_file", false),
+	envSetting("ENGRAM_VAULT_KEY", "encryption_key", true, "ENGRAM_VAULT_KEY", "ENGRAM_ENCRYPTION_KEY"),
+	envSetting("ENGRAM_CONTEXT_MAX_TOKENS", "context_max_tokens", false),
+	envSetting("ENGRAM_ALWAYS_INJECT_LIMIT", "always_inject_limit", false),
+	envSetting("ENGRAM_PROJECT_INJECT_LIMIT", "project_inject_limit", false),
+	envSetting("ENGRAM_PINNED_INJECT_LIMIT", "pinned_inject_limit", false),
+	envSetting("ENGRAM_INJECT_UNIFIED", "inject_unified", false),
+	envSetting("ENGRAM_OUTCOME_RECORDER_INTERVAL_MINUTES", "outcome_recorder_interval_minutes", false),
+	envSetting("ENGRAM_AUTHENTIK_ENABLED", "authentik_enabled", false),
+	envSetting("ENGRAM_AUTHENTIK_AUTO_PROVISION", "authentik_auto_provision", false),
+	envSetting("ENGRAM_AUTHENTIK_TRUSTED_PROXIES", "authentik_trusted_proxies", false),
+	envSetting("ENGRAM_AUTH_SKIP_LOCAL", "auth_skip_local", false),
+	envSetting("ENGRAM_AUTH_TRUSTED_PROXY", "auth_trusted_proxy", false),
+	envSetting("ENGRAM_REDACT_EMAILS", "redaction", false),
+	envSetting("ENGRAM_CONFIG_STRICT", "", false),
+}
+
+// specByKey returns the spec of a settings key.
+func specByKey(key string) (settingSpec, bool) {
+	for _, s := range settingSpecs {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return settingSpec{}, false
+}
+
+// Check validates the settings file. A missing file has no issues.
+func Check() (*Report, error) {
+	report := &Report{Path: SettingsPath()}
+	data, err := os.ReadFile(report.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return report, nil
+		}
+		return nil, err
+	}
+	report.Issues = Validate(data)
+	return report, nil
+}
+
+// Validate checks settings file contents against the settings Load reads:
+// JSON syntax, unknown keys (with a suggestion for likely typos), value
+// types and ranges, and the fields of nested objects. Load ignores every
+// value reported as an error and keeps its default.
+func Validate(data []byte) []Issue {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	var settings map[string]json.RawMessage
+	if err := json.Unmarshal(data, &settings); err != nil {
+		issue := Issue{Severity: SeverityError}
+		var syntax *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntax):
+			issue.Line, issue.Column = position(data, syntax.Offset)
+			issue.Message = "invalid JSON: " + syntax.Error() + "; the whole file is ignored and defaults are used"
+		case errors.As(err, &typeErr):
+			issue.Message = "the settings file must be a JSON object; the whole file is ignored and defaults are used"
+		default:
+			issue.Message = "invalid JSON: " + err.Error() + "; the whole file is ignored and defaults are used"
+		}
+		return []Issue{issue}
+	}
+
+	offsets := keyOffsets(data)
+	keys := make([]string, 0, len(settings))
+	for k := range settings {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return offsets[keys[i]] < offsets[keys[j]] })
+
+	var issues []Issue
+	for _, key := range keys {
+		for _, issue := range checkSetting(key, settings[key]) {
+			issue.Key = key
+			issue.Line, issue.Column = position(data, offsets[key])
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// checkSetting validates one settings file entry.
+func checkSetting(key string, raw json.RawMessage) []Issue {
+	spec, ok := specByKey(key)
+	if !ok {
+		msg := "unknown setting; ignored"
+		if s := suggestKey(key); s != "" {
+			msg = fmt.Sprintf("unknown setting; did you mean %s? ignored", s)
+		}
+		return []Issue{{Severity: SeverityWarning, Message: msg}}
+	}
+	if !spec.File {
+		return []Issue{{Severity: SeverityWarning, Message: fmt.Sprintf("read from the environment only (%s); ignored in the settings file", strings.Join(spec.Env, " or "))}}
+	}
+
+	got := jsonKind(raw)
+	want := spec.Kind
+	if want == kindInteger {
+		want = kindNumber
+	}
+	if got != want {
+		return []Issue{{Severity: SeverityError, Message: fmt.Sprintf("expected %s, got %s; ignored", spec.Kind, got)}}
+	}
+
+	switch spec.Kind {
+	case kindInteger, kindNumber:
+		var v float64
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return []Issue{{Severity: SeverityError, Message: err.Error() + "; ignored"}}
+		}
+		if spec.min != nil && v < *spec.min || spec.max != nil && v > *spec.max {
+			return []Issue{{Severity: SeverityError, Message: "must be " + describeRange(spec.min, spec.max) + "; ignored"}}
+		}
+		if spec.Kind == kindInteger && v != math.Trunc(v) {
+			return []Issue{{Severity: SeverityWarning, Message: fmt.Sprintf("expected a whole number; %v is truncated to %d", v, int(v))}}
+		}
+	case kindObject, kindArray:
+		if spec.decode == nil {
+			return nil
+		}
+		if err := spec.decode(json.NewDecoder(bytes.NewReader(raw))); err != nil {
+			return []Issue{{Severity: SeverityError, Message: strings.TrimPrefix(err.Error(), "json: ") + "; ignored"}}
+		}
+		if spec.check != nil {
+			if issues := spec.check(raw); len(issues) > 0 {
+				return issues
+			}
+		}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := spec.decode(dec); err != nil {
+			return []Issue{{Severity: SeverityWarning, Message: strings.TrimPrefix(err.Error(), "json: ") + "; ignored"}}
+		}
+	}
+	return nil
+}
+
+// jsonKind names the JSON type of a raw value.
+func jsonKind(raw json.RawMessage) settingKind {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return "nothing"
+	}
+	switch raw[0] {
+	case '{':
+		return kindObject
+	case '[':
+		return kindArray
+	case '"':
+		return kindString
+	case 't', 'f':
+		return kindBool
+	case 'n':
+		return "null"
+	default:
+		return kindNumber
+	}
+}
+
+func describeRange(min, max *float64) string {
+	switch {
+	case min != nil && max != nil:
+		return fmt.Sprintf("between %v and %v", *min, *max)
+	case min != nil:
+		return fmt.Sprintf("at least %v", *min)
+	default:
+		return fmt.Sprintf("at most %v", *max)
+	}
+}
+
+// suggestKey returns the known settings key closest to key, or "" when none
+// is close. Keys compare without case and the ENGRAM_ prefix, so the field
+// name "contex_observations" suggests ENGRAM_CONTEXT_OBSERVATIONS.
+func suggestKey(key string) string {
+	norm := func(s string) string {
+		s = strings.ToLower(strings.ReplaceAll(s, "-", "_"))
+		return strings.TrimPrefix(s, "engram_")
+	}
+	want := norm(key)
+	best, bestDist := "", max(2, len(want)/4)+1
+	for _, s := range settingSpecs {
+		for _, candidate := range []string{norm(s.Key), s.Field} {
+			if candidate == "" {
+				continue
+			}
+			if d := editDistance(want, candidate); d < bestDist {
+				best, bestDist = s.Key, d
+			}
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// keyOffsets returns the byte offset of each top-level key in a JSON object.
+func keyOffsets(data []byte) map[string]int64 {
+	offsets := map[string]int64{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return offsets
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return offsets
+		}
+		key, _ := tok.(string)
+		// The offset is just past the key; step back to its opening quote.
+		end := dec.InputOffset()
+		start := int64(bytes.LastIndexByte(data[:max(end-1, 0)], '"'))
+		if _, seen := offsets[key]; !seen {
+			offsets[key] = max(start, 0)
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return offsets
+		}
+	}
+	return offsets
+}
+
+// position converts a byte offset into a 1-based line and column.
+func position(data []byte, offset int64) (line, col int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	col = int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
+
+// Setting sources reported by Effective.
+const (
+	SourceDefault  = "default"
+	SourceSettings = "settings"
+	SourceEnv      = "env"
+)
+
+// EffectiveSetting is one resolved setting and where its value came from.
+type EffectiveSetting struct {
+	Value any `json:"value"`
+	// Name is the setting's JSON name (see Diff).
+	Name string `json:"name"`
+	// Key is the settings file key or environment variable that sets it.
+	Key    string `json:"key,omitempty"`
+	Source string `json:"source"`
+	// Default is shown when the value differs from it.
+	Default any `json:"default,omitempty"`
+}
+
+// Effective lists every setting of cfg with its source: the environment,
+// the settings file, or the built-in default. Secrets (DSNs, tokens, keys,
+// webhook secrets) are shown only as "(set)".
+func Effective(cfg *Config) []EffectiveSetting {
+	var settings map[string]json.RawMessage
+	var issues []Issue
+	if data, err := os.ReadFile(SettingsPath()); err == nil {
+		if json.Unmarshal(data, &settings) == nil {
+			issues = Validate(data)
+		}
+	}
+	rejected := map[string]bool{}
+	for _, i := range issues {
+		if i.Severity == SeverityError {
+			rejected[i.Key] = true
+		}
+	}
+
+	specs := map[string]settingSpec{}
+	for _, s := range settingSpecs {
+		if _, ok := specs[s.Field]; !ok || s.File {
+			specs[s.Field] = s
+		}
+	}
+
+	def := reflect.ValueOf(Default()).Elem()
+	v := reflect.ValueOf(redacted(cfg)).Elem()
+	t := v.Type()
+	out := make([]EffectiveSetting, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := settingName(t.Field(i))
+		spec := specs[name]
+		e := EffectiveSetting{Name: name, Key: spec.Key, Value: v.Field(i).Interface(), Source: SourceDefault}
+		for _, env := range spec.Env {
+			if strings.TrimSpace(os.Getenv(env)) != "" {
+				e.Source, e.Key = SourceEnv, env
+				break
+			}
+		}
+		if _, inFile := settings[spec.Key]; e.Source == SourceDefault && spec.File && inFile && !rejected[spec.Key] {
+			e.Source = SourceSettings
+		}
+		if d := def.Field(i).Interface(); !spec.Secret && !reflect.DeepEqual(d, e.Value) {
+			e.Default = d
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// redacted returns a copy of cfg with its secrets replaced by "(set)".
+func redacted(cfg *Config) *Config {
+	c := *cfg
+	mask := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		return "(set)"
+	}
+	c.DatabaseDSN = mask(c.DatabaseDSN)
+	c.WorkerToken = mask(c.WorkerToken)
+	c.EncryptionKey = mask(c.EncryptionKey)
+	if len(c.DatabaseReplicaDSNs) > 0 {
+		c.DatabaseReplicaDSNs = []string{fmt.Sprintf("(%d set)", len(c.DatabaseReplicaDSNs))}
+	}
+	if c.Webhooks != nil {
+		hooks := make(map[string]WebhookSource, len(c.Webhooks))
+		for name, w := range c.Webhooks {
+			w.Secret = mask(w.Secret)
+			hooks[name] = w
+		}
+		c.Webhooks = hooks
+	}
+	if c.OutboundWebhooks != nil {
+		hooks := make([]OutboundWebhook, len(c.OutboundWebhooks))
+		for i, w := range c.OutboundWebhooks {
+			w.Secret = mask(w.Secret)
+			hooks[i] = w
+		}
+		c.OutboundWebhooks = hooks
+	}
+	return &c
+}
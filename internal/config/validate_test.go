@@ -0,0 +1,106 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	settings := `{
+  "ENGRAM_WORKER_PORT": 37777,
+  "contex_observations": 50,
+  "ENGRAM_CONTEXT_RELEVANCE_THRESHOLD": 1.5,
+  "ENGRAM_MCP_READ_ONLY": "yes",
+  "ENGRAM_AUTH_ADMIN_TOKEN": "secret",
+  "ENGRAM_REDACTION": {"patterns": ["x"], "emials": false},
+  "ENGRAM_CONTEXT_TEMPLATE": {"default": "oops"},
+  "SOMETHING_ELSE": 1
+}`
+	issues := Validate([]byte(settings))
+	byKey := map[string]Issue{}
+	for _, i := range issues {
+		byKey[i.Key] = i
+	}
+	require.Len(t, issues, 7)
+	assert.NotContains(t, byKey, "ENGRAM_WORKER_PORT")
+
+	typo := byKey["contex_observations"]
+	assert.Equal(t, SeverityWarning, typo.Severity)
+	assert.Contains(t, typo.Message, "did you mean ENGRAM_CONTEXT_OBSERVATIONS?")
+	assert.Equal(t, 3, typo.Line)
+	assert.Equal(t, 3, typo.Column)
+
+	assert.Equal(t, SeverityError, byKey["ENGRAM_CONTEXT_RELEVANCE_THRESHOLD"].Severity)
+	assert.Contains(t, byKey["ENGRAM_CONTEXT_RELEVANCE_THRESHOLD"].Message, "between 0 and 1")
+	assert.Equal(t, "expected boolean, got string; ignored", byKey["ENGRAM_MCP_READ_ONLY"].Message)
+	assert.Contains(t, byKey["ENGRAM_AUTH_ADMIN_TOKEN"].Message, "environment only")
+	assert.Equal(t, SeverityWarning, byKey["ENGRAM_REDACTION"].Severity)
+	assert.Contains(t, byKey["ENGRAM_REDACTION"].Message, `unknown field "emials"`)
+	assert.Equal(t, SeverityError, byKey["ENGRAM_CONTEXT_TEMPLATE"].Severity)
+	assert.Equal(t, "unknown setting; ignored", byKey["SOMETHING_ELSE"].Message)
+}
+
+func TestValidate_SyntaxError(t *testing.T) {
+	issues := Validate([]byte("{\n  \"ENGRAM_MODEL\": \"haiku\",\n}"))
+	require.Len(t, issues, 1)
+	assert.Equal(t, SeverityError, issues[0].Severity)
+	assert.Equal(t, 3, issues[0].Line)
+	assert.Contains(t, issues[0].Message, "defaults are used")
+
+	assert.Empty(t, Validate(nil))
+	assert.Len(t, Validate([]byte(`[1]`)), 1)
+}
+
+// TestLoad_StrictAndNested verifies that one invalid nested object no longer
+// drops the others, and that strict mode turns errors into a Load failure.
+func (s *ConfigSuite) TestLoad_StrictAndNested() {
+	s.Require().NoError(EnsureDataDir())
+	settings := `{"ENGRAM_CONTEXT_TEMPLATE": {"default": "oops"}, "ENGRAM_REDACTION": {"patterns": ["ACME-[0-9]+"]}}`
+	s.Require().NoError(os.WriteFile(SettingsPath(), []byte(settings), 0600))
+
+	cfg, err := Load()
+	s.Require().NoError(err)
+	s.Equal([]string{"ACME-[0-9]+"}, cfg.Redaction.Patterns)
+
+	s.T().Setenv("ENGRAM_CONFIG_STRICT", "true")
+	_, err = Load()
+	var verr *ValidationError
+	s.Require().ErrorAs(err, &verr)
+	s.Contains(err.Error(), "ENGRAM_CONTEXT_TEMPLATE")
+
+	report, err := Check()
+	s.Require().NoError(err)
+	s.True(report.HasErrors())
+}
+
+// TestEffective verifies the reported source of each setting and that
+// secrets are masked.
+func (s *ConfigSuite) TestEffective() {
+	s.Require().NoError(EnsureDataDir())
+	settings := `{"ENGRAM_MODEL": "sonnet", "ENGRAM_HUB_THRESHOLD": 0, "ENGRAM_WEBHOOKS": {"ci": {"project": "p", "secret": "s3cret"}}}`
+	s.Require().NoError(os.WriteFile(SettingsPath(), []byte(settings), 0600))
+	s.T().Setenv("ENGRAM_PROCESSING_WORKERS", "8")
+	s.T().Setenv("DATABASE_DSN", "postgres://user:pw@host/db")
+
+	cfg, err := Load()
+	s.Require().NoError(err)
+	got := map[string]EffectiveSetting{}
+	for _, e := range Effective(cfg) {
+		got[e.Name] = e
+	}
+
+	s.Equal(SourceSettings, got["model"].Source)
+	s.Equal("sonnet", got["model"].Value)
+	s.Equal(DefaultModel, got["model"].Default)
+	s.Equal(SourceDefault, got["hub_threshold"].Source, "a rejected value keeps the default")
+	s.Equal(SourceEnv, got["processing_workers"].Source)
+	s.Equal("ENGRAM_PROCESSING_WORKERS", got["processing_workers"].Key)
+	s.Equal(SourceDefault, got["context_observations"].Source)
+	s.Equal("(set)", got["database_dsn"].Value)
+	s.Nil(got["database_dsn"].Default)
+	s.Equal("(set)", got["webhooks"].Value.(map[string]WebhookSource)["ci"].Secret)
+	s.Equal("s3cret", cfg.Webhooks["ci"].Secret, "the live config is not modified")
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/thebtf/engram/internal/tenant"
+)
+
+// WebhookSource configures one inbound webhook, POST /api/webhooks/{name}.
+// Each delivery is verified against Secret and turned into a memory of
+// Project by the template of its event.
+//
+// Settings file key: ENGRAM_WEBHOOKS, e.g.
+//
+//	"ENGRAM_WEBHOOKS": {"github": {"project": "engram", "tags": ["type:change"]}, "ci": {"project": "engram", "templates": {"*": "CI {{.pipeline}}: {{.status}}"}}}
+//
+// Secrets are better kept out of the settings file:
+// ENGRAM_WEBHOOK_SECRET_<NAME> (name upper-cased, "-" as "_") overrides Secret.
+type WebhookSource struct {
+	// Kind selects the built-in templates and how the event name is read:
+	// "github" (X-GitHub-Event) or "generic" (X-Webhook-Event). Defaults to
+	// "github" for a source named github, otherwise "generic".
+	Kind string `json:"kind,omitempty"`
+	// Secret is the HMAC-SHA256 key deliveries are signed with
+	// (X-Hub-Signature-256 or X-Webhook-Signature: "sha256=<hex>"). A source
+	// without a secret is disabled.
+	Secret string `json:"secret,omitempty"`
+	// Project receives the memories.
+	Project string `json:"project"`
+	// Tenant owns the memories. Deliveries carry no token, so without it
+	// they land in the default tenant. A source with an invalid tenant name
+	// is dropped at load.
+	Tenant string `json:"tenant,omitempty"`
+	// Templates maps event names ("*" for any) to Go text/template strings
+	// rendered over the JSON payload. They override the built-in templates
+	// of Kind; an empty rendering ignores the delivery.
+	Templates map[string]string `json:"templates,omitempty"`
+	// Tags are added to every memory of this source.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Webhook kinds.
+const (
+	WebhookKindGitHub  = "github"
+	WebhookKindGeneric = "generic"
+)
+
+// KindOf returns the source's kind, defaulted from its name.
+func (w WebhookSource) KindOf(name string) string {
+	if w.Kind != "" {
+		return strings.ToLower(w.Kind)
+	}
+	if strings.EqualFold(name, WebhookKindGitHub) {
+		return WebhookKindGitHub
+	}
+	return WebhookKindGeneric
+}
+
+// Validate reports why the source cannot be used, or nil.
+func (w WebhookSource) Validate() error {
+	return tenant.Validate(w.Tenant)
+}
+
+// dropInvalidWebhookSources removes the sources Validate rejects, so a
+// mistyped tenant never receives memories. engram config check reports them.
+func dropInvalidWebhookSources(sources map[string]WebhookSource) {
+	for name, src := range sources {
+		if src.Validate() != nil {
+			delete(sources, name)
+		}
+	}
+}
+
+// checkWebhookSources reports the sources of an ENGRAM_WEBHOOKS value that
+// Load drops, in name order.
+func checkWebhookSources(raw []byte) []Issue {
+	var sources map[string]WebhookSource
+	if err := json.Unmarshal(raw, &sources); err != nil {
+		return nil // reported by the decode check
+	}
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var issues []Issue
+	for _, name := range names {
+		if err := sources[name].Validate(); err != nil {
+			issues = append(issues, Issue{Severity: SeverityError, Message: fmt.Sprintf("source %q: %v; source ignored", name, err)})
+		}
+	}
+	return issues
+}
+
+// applyWebhookSecretEnv overrides webhook secrets from
+// ENGRAM_WEBHOOK_SECRET_<NAME>.
+func applyWebhookSecretEnv(sources map[string]WebhookSource) {
+	for name, src := range sources {
+		key := "ENGRAM_WEBHOOK_SECRET_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+			src.Secret = v
+			sources[name] = src
+		}
+	}
+}
+
+// OutboundWebhook configures one receiver of memory change events. Each
+// event is POSTed as JSON and, when Secret is set, signed in
+// X-Engram-Signature-256 ("sha256=<hex>", the format POST
+// /api/webhooks/{source} verifies).
+//
+// Settings file key: ENGRAM_OUTBOUND_WEBHOOKS, e.g.
+//
+//	"ENGRAM_OUTBOUND_WEBHOOKS": [{"url": "https://hooks.example.com/engram", "secret": "...", "events": ["memory.created", "memory.merged"], "projects": ["engram"]}]
+type OutboundWebhook struct {
+	// URL receives the events.
+	URL string `json:"url"`
+	// Secret is the HMAC-SHA256 key events are signed with.
+	Secret string `json:"secret,omitempty"`
+	// Events limits delivery to these event types; "memory.*" matches every
+	// memory event. Empty means all.
+	Events []string `json:"events,omitempty"`
+	// Projects limits delivery to events of these projects. Empty means all.
+	Projects []string `json:"projects,omitempty"`
+}
+
+// Wants reports whether the receiver subscribes to an event of type
+// eventType in project.
+func (w OutboundWebhook) Wants(eventType, project string) bool {
+	if len(w.Projects) > 0 && !slices.Contains(w.Projects, project) {
+		return false
+	}
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == "*" || e == eventType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(e, "*"); ok && strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboundWebhook_Wants(t *testing.T) {
+	all := OutboundWebhook{URL: "http://x"}
+	assert.True(t, all.Wants("memory.created", "p"))
+
+	memory := OutboundWebhook{Events: []string{"memory.*"}}
+	assert.True(t, memory.Wants("memory.merged", "p"))
+	assert.False(t, memory.Wants("session.created", "p"))
+
+	scoped := OutboundWebhook{Events: []string{"memory.deleted"}, Projects: []string{"a"}}
+	assert.True(t, scoped.Wants("memory.deleted", "a"))
+	assert.False(t, scoped.Wants("memory.deleted", "b"))
+	assert.False(t, scoped.Wants("memory.created", "a"))
+}
@@ -0,0 +1,85 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// AttachmentStore manages the memory_attachments table (migration 111).
+// Attachments are deleted with their memory (ON DELETE CASCADE).
+type AttachmentStore struct {
+	db *gorm.DB
+}
+
+// NewAttachmentStore creates a new AttachmentStore backed by the given Store.
+func NewAttachmentStore(store *Store) *AttachmentStore {
+	return &AttachmentStore{db: store.DB}
+}
+
+// Create stores the attachments of one memory in a single transaction and
+// writes the assigned IDs, sizes and timestamps back. Limits are not checked
+// here; callers validate against models.MaxAttachmentSize and
+// models.MaxAttachmentsPerMemory.
+func (s *AttachmentStore) Create(ctx context.Context, memoryID int64, attachments []*models.MemoryAttachment) error {
+	if len(attachments) == 0 {
+		return nil
+	}
+	rows := make([]*MemoryAttachmentRow, len(attachments))
+	for i, a := range attachments {
+		if !models.IsValidAttachmentKind(a.Kind) {
+			return fmt.Errorf("invalid attachment kind %q", a.Kind)
+		}
+		rows[i] = &MemoryAttachmentRow{
+			MemoryID: memoryID,
+			Kind:     a.Kind,
+			Name:     a.Name,
+			Language: a.Language,
+			Content:  a.Content,
+			Size:     len(a.Content),
+		}
+	}
+	if err := s.db.WithContext(ctx).Create(&rows).Error; err != nil {
+		return fmt.Errorf("create attachments for memory %d: %w", memoryID, err)
+	}
+	for i, row := range rows {
+		attachments[i].ID = row.ID
+		attachments[i].MemoryID = memoryID
+		attachments[i].Size = row.Size
+		attachments[i].CreatedAt = row.CreatedAt
+	}
+	return nil
+}
+
+// ListByMemories returns the attachments of the given memories keyed by memory
+// ID, oldest first. Without withContent only metadata is loaded.
+func (s *AttachmentStore) ListByMemories(ctx context.Context, memoryIDs []int64, withContent bool) (map[int64][]*models.MemoryAttachment, error) {
+	result := make(map[int64][]*models.MemoryAttachment)
+	if len(memoryIDs) == 0 {
+		return result, nil
+	}
+	q := s.db.WithContext(ctx).Where("memory_id IN ?", memoryIDs).Order("memory_id, id")
+	if !withContent {
+		q = q.Select("id", "memory_id", "kind", "name", "language", "size", "created_at")
+	}
+	var rows []MemoryAttachmentRow
+	if err := q.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("list attachments: %w", err)
+	}
+	for _, row := range rows {
+		result[row.MemoryID] = append(result[row.MemoryID], &models.MemoryAttachment{
+			ID:        row.ID,
+			MemoryID:  row.MemoryID,
+			Kind:      row.Kind,
+			Name:      row.Name,
+			Language:  row.Language,
+			Content:   row.Content,
+			Size:      row.Size,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,53 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// TestAttachmentStore_CreateList stores attachments for two memories and checks
+// that listing groups them by memory, and omits content unless asked for.
+func TestAttachmentStore_CreateList(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	defer db.Exec(`DELETE FROM memories WHERE project = 'test-attachment-store'`)
+
+	store := &Store{DB: db}
+	ms := NewMemoryStore(store)
+	as := NewAttachmentStore(store)
+	ctx := context.Background()
+
+	const project = "test-attachment-store"
+	first, err := ms.Create(ctx, &models.Memory{Project: project, Content: "retry fix"})
+	require.NoError(t, err)
+	second, err := ms.Create(ctx, &models.Memory{Project: project, Content: "no attachments"})
+	require.NoError(t, err)
+
+	attachments := []*models.MemoryAttachment{
+		{Kind: models.AttachmentKindDiff, Name: "internal/retry.go", Content: "-a\n+b\n"},
+		{Kind: models.AttachmentKindCode, Language: "go", Content: "backoff(3)"},
+	}
+	require.NoError(t, as.Create(ctx, first.ID, attachments))
+	assert.Greater(t, attachments[0].ID, int64(0))
+	assert.Equal(t, 6, attachments[0].Size)
+
+	require.Error(t, as.Create(ctx, first.ID, []*models.MemoryAttachment{{Kind: "image", Content: "x"}}))
+
+	full, err := as.ListByMemories(ctx, []int64{first.ID, second.ID}, true)
+	require.NoError(t, err)
+	require.Len(t, full[first.ID], 2)
+	assert.Empty(t, full[second.ID])
+	assert.Equal(t, "-a\n+b\n", full[first.ID][0].Content)
+	assert.Equal(t, "go", full[first.ID][1].Language)
+
+	meta, err := as.ListByMemories(ctx, []int64{first.ID}, false)
+	require.NoError(t, err)
+	require.Len(t, meta[first.ID], 2)
+	assert.Empty(t, meta[first.ID][0].Content)
+	assert.Equal(t, 6, meta[first.ID][0].Size)
+}
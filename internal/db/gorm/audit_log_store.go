@@ -0,0 +1,108 @@
+// Package gorm provides GORM-based database operations for engram.
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/thebtf/engram/internal/tenant"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// defaultAuditLogLimit is the number of entries List returns when the filter
+// sets no limit.
+const defaultAuditLogLimit = 50
+
+// AuditLogStore appends to and queries the audit_log table (migration 109).
+// Entries are scoped to the tenant on the context.
+type AuditLogStore struct {
+	db *gorm.DB
+}
+
+// NewAuditLogStore creates a new AuditLogStore backed by the given Store.
+func NewAuditLogStore(store *Store) *AuditLogStore {
+	return &AuditLogStore{db: store.DB}
+}
+
+// Record appends entry to the audit log. A zero CreatedAt is set to now; the
+// assigned ID and timestamp are written back to entry.
+func (s *AuditLogStore) Record(ctx context.Context, entry *models.AuditEntry) error {
+	if entry == nil {
+		return fmt.Errorf("audit entry must not be nil")
+	}
+	if entry.Operation == "" {
+		return fmt.Errorf("audit entry operation must not be empty")
+	}
+	createdAt := entry.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now().UTC()
+	}
+	targetIDs := entry.TargetIDs
+	if targetIDs == nil {
+		targetIDs = []string{}
+	}
+
+	row := &AuditLogEntry{
+		Tenant:    tenant.FromContext(ctx),
+		Actor:     entry.Actor,
+		Client:    entry.Client,
+		Operation: entry.Operation,
+		Project:   entry.Project,
+		Before:    entry.Before,
+		TargetIDs: models.JSONStringArray(targetIDs),
+		CreatedAt: createdAt,
+	}
+	if err := s.db.WithContext(ctx).Create(row).Error; err != nil {
+		return fmt.Errorf("record audit entry %q: %w", entry.Operation, err)
+	}
+	entry.ID = row.ID
+	entry.CreatedAt = row.CreatedAt
+	return nil
+}
+
+// List returns audit entries matching filter, newest first.
+func (s *AuditLogStore) List(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEntry, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultAuditLogLimit
+	}
+	if limit > MaxPaginationLimit {
+		limit = MaxPaginationLimit
+	}
+
+	q := s.db.WithContext(ctx).Scopes(tenantScope(ctx)).Order("created_at DESC, id DESC").Limit(limit)
+	if filter.Operation != "" {
+		q = q.Where("operation = ?", filter.Operation)
+	}
+	if filter.Project != "" {
+		q = q.Where("project = ?", filter.Project)
+	}
+	if filter.Actor != "" {
+		q = q.Where("actor = ?", filter.Actor)
+	}
+	if !filter.Since.IsZero() {
+		q = q.Where("created_at >= ?", filter.Since)
+	}
+
+	var rows []AuditLogEntry
+	if err := q.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("list audit log: %w", err)
+	}
+	result := make([]*models.AuditEntry, len(rows))
+	for i, row := range rows {
+		result[i] = &models.AuditEntry{
+			ID:        row.ID,
+			Actor:     row.Actor,
+			Client:    row.Client,
+			Operation: row.Operation,
+			Project:   row.Project,
+			Before:    row.Before,
+			TargetIDs: []string(row.TargetIDs),
+			CreatedAt: row.CreatedAt,
+		}
+	}
+	return result, nil
+}
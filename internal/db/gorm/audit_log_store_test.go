@@ -0,0 +1,75 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/tenant"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// TestAuditLogStore_RecordList records entries in two tenants and checks that
+// List filters by operation and project, orders newest first and never leaks
+// entries across tenants.
+func TestAuditLogStore_RecordList(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	defer db.Exec(`DELETE FROM audit_log WHERE project = 'test-audit-log'`)
+
+	store := NewAuditLogStore(&Store{DB: db})
+	ctx := context.Background()
+	otherCtx := tenant.WithTenant(ctx, "audit-other-tenant")
+	const testProject = "test-audit-log"
+	base := time.Now().UTC().Add(-time.Minute)
+
+	first := &models.AuditEntry{
+		Actor:     "operator",
+		Client:    models.AuditClientMCP,
+		Operation: models.AuditOpMemoryDelete,
+		Project:   testProject,
+		TargetIDs: []string{"41"},
+		Before:    "gRPC retry policy requires idempotency tokens",
+		CreatedAt: base,
+	}
+	require.NoError(t, store.Record(ctx, first))
+	assert.Greater(t, first.ID, int64(0), "Record should write back the ID")
+
+	require.NoError(t, store.Record(ctx, &models.AuditEntry{
+		Actor:     "keycard:uuid-1",
+		Client:    models.AuditClientAPI,
+		Operation: models.AuditOpMemoryRevert,
+		Project:   testProject,
+		TargetIDs: []string{"42"},
+		CreatedAt: base.Add(time.Second),
+	}))
+	require.NoError(t, store.Record(otherCtx, &models.AuditEntry{
+		Actor:     "keycard:uuid-2",
+		Client:    models.AuditClientAPI,
+		Operation: models.AuditOpMemoryDelete,
+		Project:   testProject,
+		TargetIDs: []string{"43"},
+	}))
+
+	all, err := store.List(ctx, models.AuditFilter{Project: testProject})
+	require.NoError(t, err)
+	require.Len(t, all, 2, "other tenant's entry must not be listed")
+	assert.Equal(t, models.AuditOpMemoryRevert, all[0].Operation, "newest entry first")
+	assert.Equal(t, []string{"41"}, all[1].TargetIDs)
+	assert.Equal(t, first.Before, all[1].Before)
+
+	deletes, err := store.List(ctx, models.AuditFilter{Project: testProject, Operation: models.AuditOpMemoryDelete})
+	require.NoError(t, err)
+	require.Len(t, deletes, 1)
+	assert.Equal(t, "operator", deletes[0].Actor)
+
+	other, err := store.List(otherCtx, models.AuditFilter{Project: testProject})
+	require.NoError(t, err)
+	require.Len(t, other, 1)
+	assert.Equal(t, []string{"43"}, other[0].TargetIDs)
+
+	assert.Error(t, store.Record(ctx, &models.AuditEntry{Actor: "operator"}), "operation is required")
+}
@@ -8,6 +8,7 @@ import (
 
 	"gorm.io/gorm"
 
+	"github.com/thebtf/engram/internal/tenant"
 	"github.com/thebtf/engram/pkg/models"
 )
 
@@ -16,6 +17,8 @@ import (
 //
 // Global rules (project IS NULL) are always included in List results regardless of the
 // project filter — this ensures every session receives globally-applicable guidance.
+// "Global" means global within the caller's tenant: every method is scoped to the
+// tenant on ctx (see internal/tenant).
 //
 // Immutability contract: Create and Update return NEW *models.BehavioralRule values
 // populated from the database row. The caller's input struct is never mutated.
@@ -49,6 +52,7 @@ func (s *BehavioralRulesStore) Create(ctx context.Context, rule *models.Behavior
 	}
 
 	row := &BehavioralRule{
+		Tenant:    tenant.FromContext(ctx),
 		Project:   project,
 		Content:   rule.Content,
 		Priority:  rule.Priority,
@@ -75,6 +79,7 @@ func (s *BehavioralRulesStore) Get(ctx context.Context, id int64) (*models.Behav
 	}
 	var row BehavioralRule
 	err := s.db.WithContext(ctx).
+		Scopes(tenantScope(ctx), primaryReads(ctx)).
 		Where("id = ? AND deleted_at IS NULL", id).
 		First(&row).Error
 	if err != nil {
@@ -98,6 +103,7 @@ func (s *BehavioralRulesStore) List(ctx context.Context, project *string, limit
 	}
 
 	q := s.db.WithContext(ctx).
+		Scopes(tenantScope(ctx)).
 		Where("deleted_at IS NULL").
 		Order("priority DESC, created_at DESC").
 		Limit(limit)
@@ -146,6 +152,7 @@ func (s *BehavioralRulesStore) Update(ctx context.Context, rule *models.Behavior
 
 	result := s.db.WithContext(ctx).
 		Model(&BehavioralRule{}).
+		Scopes(tenantScope(ctx)).
 		Where("id = ? AND deleted_at IS NULL", rule.ID).
 		Updates(updates)
 	if result.Error != nil {
@@ -155,7 +162,7 @@ func (s *BehavioralRulesStore) Update(ctx context.Context, rule *models.Behavior
 		return nil, fmt.Errorf("update behavioral rule id=%d: %w", rule.ID, gorm.ErrRecordNotFound)
 	}
 
-	return s.Get(ctx, rule.ID)
+	return s.Get(withPrimaryReads(ctx), rule.ID)
 }
 
 // Delete soft-deletes the behavioral rule by setting deleted_at = NOW().
@@ -167,6 +174,7 @@ func (s *BehavioralRulesStore) Delete(ctx context.Context, id int64) error {
 	now := time.Now().UTC()
 	result := s.db.WithContext(ctx).
 		Model(&BehavioralRule{}).
+		Scopes(tenantScope(ctx)).
 		Where("id = ? AND deleted_at IS NULL", id).
 		Updates(map[string]any{
 			"deleted_at": now,
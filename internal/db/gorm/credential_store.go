@@ -8,6 +8,7 @@ import (
 
 	"gorm.io/gorm"
 
+	"github.com/thebtf/engram/internal/tenant"
 	"github.com/thebtf/engram/pkg/models"
 )
 
@@ -18,6 +19,11 @@ import (
 // (CountCredentials / CountCredentialsWithDifferentFingerprint / DeleteOrphanedCredentials)
 // so callers in internal/worker/handlers_vault.go can swap without signature changes
 // beyond the receiver type.
+//
+// CRUD methods are scoped to the tenant on ctx (see internal/tenant). The vault
+// key maintenance helpers (CountCredentials, CountWithDifferentFingerprint,
+// DeleteOrphanedByFingerprint) deliberately span all tenants: one encryption key
+// protects the whole table.
 type CredentialStore struct {
 	db *gorm.DB
 }
@@ -56,6 +62,7 @@ func (s *CredentialStore) Create(ctx context.Context, cred *models.Credential) (
 	secret := append([]byte(nil), cred.EncryptedSecret...)
 
 	row := &Credential{
+		Tenant:                   tenant.FromContext(ctx),
 		Project:                  cred.Project,
 		Key:                      cred.Key,
 		EncryptedSecret:          secret,
@@ -87,6 +94,7 @@ func (s *CredentialStore) Get(ctx context.Context, project, key string) (*models
 	}
 	var row Credential
 	err := s.db.WithContext(ctx).
+		Scopes(tenantScope(ctx)).
 		Where("project = ? AND key = ? AND deleted_at IS NULL", project, key).
 		First(&row).Error
 	if err != nil {
@@ -106,6 +114,7 @@ func (s *CredentialStore) GetByName(ctx context.Context, key string) (*models.Cr
 	}
 	var row Credential
 	err := s.db.WithContext(ctx).
+		Scopes(tenantScope(ctx)).
 		Where("key = ? AND deleted_at IS NULL", key).
 		Order("project ASC").
 		First(&row).Error
@@ -123,6 +132,7 @@ func (s *CredentialStore) List(ctx context.Context, project string) ([]*models.C
 	}
 	var rows []Credential
 	err := s.db.WithContext(ctx).
+		Scopes(tenantScope(ctx)).
 		Where("project = ? AND deleted_at IS NULL", project).
 		Order("key ASC").
 		Find(&rows).Error
@@ -136,11 +146,12 @@ func (s *CredentialStore) List(ctx context.Context, project string) ([]*models.C
 	return result, nil
 }
 
-// ListAll returns all active (non-soft-deleted) credentials across all projects,
+// ListAll returns all active (non-soft-deleted) credentials across all projects of the caller's tenant,
 // ordered by project then key. Used by the dashboard admin view.
 func (s *CredentialStore) ListAll(ctx context.Context) ([]*models.Credential, error) {
 	var rows []Credential
 	err := s.db.WithContext(ctx).
+		Scopes(tenantScope(ctx)).
 		Where("deleted_at IS NULL").
 		Order("project ASC, key ASC").
 		Find(&rows).Error
@@ -174,6 +185,7 @@ func (s *CredentialStore) Delete(ctx context.Context, project, key string) error
 		return fmt.Errorf("key: must not be empty")
 	}
 	result := s.db.WithContext(ctx).
+		Scopes(tenantScope(ctx)).
 		Where("project = ? AND key = ? AND deleted_at IS NULL", project, key).
 		Delete(&Credential{})
 	if result.Error != nil {
@@ -195,6 +207,7 @@ func (s *CredentialStore) DeleteByName(ctx context.Context, key string) error {
 	// Find the credential first to get the exact row (deterministic: ordered by project).
 	var row Credential
 	if err := s.db.WithContext(ctx).
+		Scopes(tenantScope(ctx)).
 		Where("key = ? AND deleted_at IS NULL", key).
 		Order("project ASC").
 		First(&row).Error; err != nil {
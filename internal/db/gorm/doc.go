@@ -5,6 +5,16 @@
 //   - Automatic statement caching
 //   - Auto-migrations for schema management
 //
+// # Tenant isolation
+//
+// Tables with a tenant column (memories, behavioral_rules, credentials,
+// audit_log, search_query_log, sdk_sessions) are written with
+// tenant.FromContext and read through tenantScope or memoryScope. The
+// remaining tables (issues, versioned_documents, reasoning_traces) have no
+// tenant column and are shared by every tenant on the instance. New per-request features must
+// not read them; instance-wide views over shared tables belong behind admin
+// checks.
+//
 // # Usage
 //
 //	import "github.com/thebtf/engram/internal/db/gorm"
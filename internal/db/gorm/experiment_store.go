@@ -0,0 +1,100 @@
+package gorm
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Events of the injection experiment log.
+const (
+	// ExperimentEventAssigned records the arm a session was assigned.
+	ExperimentEventAssigned = "assigned"
+	// ExperimentEventFollowUpSearch is a context search made by a session
+	// after its context was injected.
+	ExperimentEventFollowUpSearch = "follow_up_search"
+	// ExperimentEventNegativeFeedback is a memory rated not useful.
+	ExperimentEventNegativeFeedback = "negative_feedback"
+	// ExperimentEventPositiveFeedback is a memory rated useful.
+	ExperimentEventPositiveFeedback = "positive_feedback"
+)
+
+// ExperimentArmStats holds the outcome metrics of one experiment arm.
+type ExperimentArmStats struct {
+	Arm                  string `json:"arm"`
+	Sessions             int64  `json:"sessions"`
+	FollowUpSearches     int64  `json:"follow_up_searches"`
+	SessionsWithFollowUp int64  `json:"sessions_with_follow_up"`
+	NegativeFeedback     int64  `json:"negative_feedback"`
+	PositiveFeedback     int64  `json:"positive_feedback"`
+	SessionsWithOutcome  int64  `json:"sessions_with_outcome"`
+	SuccessfulSessions   int64  `json:"successful_sessions"`
+}
+
+// ExperimentStore records injection experiment assignments and outcomes in
+// injection_experiment_events.
+type ExperimentStore struct {
+	db *gorm.DB
+}
+
+// NewExperimentStore creates a new ExperimentStore.
+func NewExperimentStore(db *gorm.DB) *ExperimentStore {
+	return &ExperimentStore{db: db}
+}
+
+// Assign records that sessionID was assigned arm of experiment. A session
+// keeps its first assignment; later calls are no-ops.
+func (s *ExperimentStore) Assign(ctx context.Context, experiment, arm, sessionID string) error {
+	return s.db.WithContext(ctx).Exec(`
+		INSERT INTO injection_experiment_events (experiment, arm, session_id, event)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (experiment, session_id) WHERE event = 'assigned' DO NOTHING
+	`, experiment, arm, sessionID, ExperimentEventAssigned).Error
+}
+
+// RecordEvent appends event for sessionID under every arm the session was
+// assigned. It reports whether the session takes part in any experiment.
+func (s *ExperimentStore) RecordEvent(ctx context.Context, sessionID, event string) (bool, error) {
+	result := s.db.WithContext(ctx).Exec(`
+		INSERT INTO injection_experiment_events (experiment, arm, session_id, event)
+		SELECT experiment, arm, session_id, ?
+		FROM injection_experiment_events
+		WHERE session_id = ? AND event = 'assigned'
+	`, event, sessionID)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Report returns the outcome metrics of every arm of experiment, ordered by
+// arm name. Session outcomes come from sdk_sessions.
+func (s *ExperimentStore) Report(ctx context.Context, experiment string) ([]ExperimentArmStats, error) {
+	var rows []ExperimentArmStats
+	err := s.db.WithContext(ctx).Raw(`
+		SELECT a.arm,
+			COUNT(*) AS sessions,
+			COALESCE(SUM(e.follow_up_searches), 0) AS follow_up_searches,
+			COUNT(*) FILTER (WHERE e.follow_up_searches > 0) AS sessions_with_follow_up,
+			COALESCE(SUM(e.negative_feedback), 0) AS negative_feedback,
+			COALESCE(SUM(e.positive_feedback), 0) AS positive_feedback,
+			COUNT(ss.outcome) AS sessions_with_outcome,
+			COUNT(*) FILTER (WHERE ss.outcome = 'success') AS successful_sessions
+		FROM injection_experiment_events a
+		LEFT JOIN (
+			SELECT experiment, session_id,
+				COUNT(*) FILTER (WHERE event = ?) AS follow_up_searches,
+				COUNT(*) FILTER (WHERE event = ?) AS negative_feedback,
+				COUNT(*) FILTER (WHERE event = ?) AS positive_feedback
+			FROM injection_experiment_events
+			WHERE experiment = ? AND event <> 'assigned'
+			GROUP BY experiment, session_id
+		) e ON e.experiment = a.experiment AND e.session_id = a.session_id
+		LEFT JOIN sdk_sessions ss ON ss.claude_session_id = a.session_id
+		WHERE a.experiment = ? AND a.event = 'assigned'
+		GROUP BY a.arm
+		ORDER BY a.arm
+	`, ExperimentEventFollowUpSearch, ExperimentEventNegativeFeedback, ExperimentEventPositiveFeedback,
+		experiment, experiment).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
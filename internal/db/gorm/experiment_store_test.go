@@ -0,0 +1,56 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExperimentStore_Report assigns sessions to two arms, records outcome
+// events and checks that they are reported under the arm of their session.
+func TestExperimentStore_Report(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	const experiment = "test-experiment-store"
+	defer db.Exec(`DELETE FROM injection_experiment_events WHERE experiment = ?`, experiment)
+
+	store := NewExperimentStore(db)
+	ctx := context.Background()
+
+	require.NoError(t, store.Assign(ctx, experiment, "a", "exp-sess-1"))
+	require.NoError(t, store.Assign(ctx, experiment, "b", "exp-sess-2"))
+	require.NoError(t, store.Assign(ctx, experiment, "b", "exp-sess-3"))
+	// A session keeps its first arm.
+	require.NoError(t, store.Assign(ctx, experiment, "b", "exp-sess-1"))
+
+	for _, ev := range []struct{ session, event string }{
+		{"exp-sess-1", ExperimentEventFollowUpSearch},
+		{"exp-sess-1", ExperimentEventFollowUpSearch},
+		{"exp-sess-2", ExperimentEventNegativeFeedback},
+		{"exp-sess-3", ExperimentEventPositiveFeedback},
+	} {
+		enrolled, err := store.RecordEvent(ctx, ev.session, ev.event)
+		require.NoError(t, err)
+		assert.True(t, enrolled)
+	}
+	enrolled, err := store.RecordEvent(ctx, "exp-sess-unknown", ExperimentEventFollowUpSearch)
+	require.NoError(t, err)
+	assert.False(t, enrolled, "sessions outside the experiment record nothing")
+
+	report, err := store.Report(ctx, experiment)
+	require.NoError(t, err)
+	require.Len(t, report, 2)
+
+	assert.Equal(t, "a", report[0].Arm)
+	assert.Equal(t, int64(1), report[0].Sessions)
+	assert.Equal(t, int64(2), report[0].FollowUpSearches)
+	assert.Equal(t, int64(1), report[0].SessionsWithFollowUp)
+
+	assert.Equal(t, "b", report[1].Arm)
+	assert.Equal(t, int64(2), report[1].Sessions)
+	assert.Equal(t, int64(0), report[1].FollowUpSearches)
+	assert.Equal(t, int64(1), report[1].NegativeFeedback)
+	assert.Equal(t, int64(1), report[1].PositiveFeedback)
+}
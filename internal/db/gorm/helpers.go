@@ -10,6 +10,9 @@ import (
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+
+	"github.com/thebtf/engram/internal/tenant"
+	"github.com/thebtf/engram/pkg/models"
 )
 
 // EnsureSessionExists creates a session if it doesn't exist.
@@ -37,6 +40,31 @@ func EnsureSessionExists(ctx context.Context, db *gorm.DB, sdkSessionID, project
 		Create(session).Error
 }
 
+// tenantScope restricts a query on a tenant-aware table (see "Tenant
+// isolation" in the package doc) to the tenant stamped on ctx. Use via
+// db.Scopes(tenantScope(ctx)). Contexts without a tenant resolve to
+// tenant.Default, which owns all pre-multi-tenant data.
+func tenantScope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	t := tenant.FromContext(ctx)
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("tenant = ?", t)
+	}
+}
+
+// memoryScope restricts a memories query to the tenant on ctx and hides
+// private memories of other owners. Contexts without an owner (background
+// jobs, authentication disabled) see every memory of the tenant.
+func memoryScope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	owner, restricted := tenant.OwnerFromContext(ctx)
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Scopes(tenantScope(ctx))
+		if restricted {
+			db = db.Where("(visibility <> ? OR owner = ?)", models.VisibilityPrivate, owner)
+		}
+		return db
+	}
+}
+
 // sqlNullString creates a sql.NullString from a string.
 func sqlNullString(s string) sql.NullString {
 	if s == "" {
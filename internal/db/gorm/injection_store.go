@@ -45,6 +45,23 @@ func (s *InjectionStore) GetInjectionsBySession(ctx context.Context, sessionID s
 	return rows, nil
 }
 
+// InjectedIDs returns the distinct observation IDs injected into a session.
+func (s *InjectionStore) InjectedIDs(ctx context.Context, sessionID string) (map[int64]struct{}, error) {
+	var ids []int64
+	err := s.db.WithContext(ctx).Table("observation_injections").
+		Where("session_id = ?", sessionID).
+		Distinct().
+		Pluck("observation_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		result[id] = struct{}{}
+	}
+	return result, nil
+}
+
 // CountInjectionsBySession returns the number of injection records for a session.
 func (s *InjectionStore) CountInjectionsBySession(ctx context.Context, sessionID string) (int64, error) {
 	var count int64
@@ -0,0 +1,340 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+
+	"github.com/thebtf/engram/internal/tenant"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// Memory access sources: how a memory reached an agent.
+const (
+	// AccessInjection: injected into context by a hook (session start or prompt).
+	AccessInjection = "injection"
+	// AccessSearch: returned by an explicit search (recall, /api/context/search).
+	AccessSearch = "search"
+	// AccessGet: fetched by ID.
+	AccessGet = "get"
+)
+
+// AccessSources lists the access sources in display order.
+var AccessSources = []string{AccessInjection, AccessSearch, AccessGet}
+
+const (
+	memoryAccessChanSize      = 1000
+	memoryAccessFlushSize     = 200
+	memoryAccessFlushInterval = 5 * time.Second
+)
+
+// memoryAccess is one buffered access of a set of memories.
+type memoryAccess struct {
+	at      time.Time
+	source  string
+	project string
+	ids     []int64
+}
+
+// accessKey identifies one row of memory_access_daily.
+type accessKey struct {
+	day     string
+	source  string
+	project string
+	id      int64
+}
+
+// MemoryAccessStore counts memory accesses per day, source and requesting
+// project in memory_access_daily. Accesses are buffered and written in batches, so
+// recording never blocks a request.
+type MemoryAccessStore struct {
+	db        *gorm.DB
+	ch        chan memoryAccess
+	done      chan struct{}
+	closeOnce sync.Once
+	mu        sync.RWMutex // guards closed against Record racing Close
+	closed    bool
+}
+
+// NewMemoryAccessStore creates a store and starts its background flusher.
+func NewMemoryAccessStore(db *gorm.DB) *MemoryAccessStore {
+	s := &MemoryAccessStore{
+		db:   db,
+		ch:   make(chan memoryAccess, memoryAccessChanSize),
+		done: make(chan struct{}),
+	}
+	go s.flusher()
+	return s
+}
+
+// Record enqueues one access of ids from source, made from project ("" when
+// the requesting project is unknown). Non-blocking: drops the access when the
+// buffer is full or the store is closed. Safe on a nil store.
+func (s *MemoryAccessStore) Record(source, project string, ids []int64) {
+	if s == nil || len(ids) == 0 {
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.ch <- memoryAccess{at: time.Now(), source: source, project: project, ids: append([]int64(nil), ids...)}:
+	default:
+		// Channel full — drop to avoid blocking the caller.
+	}
+}
+
+// flusher aggregates buffered accesses and writes them periodically.
+func (s *MemoryAccessStore) flusher() {
+	defer close(s.done)
+	ticker := time.NewTicker(memoryAccessFlushInterval)
+	defer ticker.Stop()
+
+	pending := map[accessKey]int64{}
+	for {
+		select {
+		case a, ok := <-s.ch:
+			if !ok {
+				s.flush(pending)
+				return
+			}
+			day := a.at.UTC().Format(time.DateOnly)
+			for _, id := range a.ids {
+				pending[accessKey{day: day, source: a.source, project: a.project, id: id}]++
+			}
+			if len(pending) >= memoryAccessFlushSize {
+				s.flush(pending)
+				pending = map[accessKey]int64{}
+			}
+		case <-ticker.C:
+			if len(pending) > 0 {
+				s.flush(pending)
+				pending = map[accessKey]int64{}
+			}
+		}
+	}
+}
+
+// flush upserts the aggregated counts. Accesses of memories deleted in the
+// meantime are skipped by the join.
+func (s *MemoryAccessStore) flush(pending map[accessKey]int64) {
+	if len(pending) == 0 {
+		return
+	}
+	values := make([]string, 0, len(pending))
+	args := make([]any, 0, 5*len(pending))
+	for k, n := range pending {
+		values = append(values, "(?::bigint, ?, ?::date, ?, ?::bigint)")
+		args = append(args, k.id, k.source, k.day, k.project, n)
+	}
+	sql := `INSERT INTO memory_access_daily (memory_id, source, day, project, count)
+		SELECT v.id, v.source, v.day, v.project, v.n FROM (VALUES ` + strings.Join(values, ", ") + `) AS v(id, source, day, project, n)
+		JOIN memories m ON m.id = v.id
+		ON CONFLICT (memory_id, source, day, project) DO UPDATE SET count = memory_access_daily.count + EXCLUDED.count`
+	if err := s.db.Exec(sql, args...).Error; err != nil {
+		log.Warn().Err(err).Int("rows", len(pending)).Msg("failed to flush memory access counts")
+	}
+}
+
+// Close drains the buffer and stops the background flusher.
+func (s *MemoryAccessStore) Close() {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		close(s.ch)
+		s.mu.Unlock()
+		<-s.done
+	})
+}
+
+// AccessDay is the access counts of one memory on one day, by source.
+type AccessDay struct {
+	Counts map[string]int64 `json:"counts"`
+	Day    string           `json:"day"`
+}
+
+// MemoryAccessSeries is the access history of one memory.
+type MemoryAccessSeries struct {
+	Totals  map[string]int64 `json:"totals"`
+	Preview string           `json:"preview"`
+	Days    []AccessDay      `json:"days"`
+	ID      int64            `json:"id"`
+	Total   int64            `json:"total"`
+	// Tokens estimates the memory's size in context (4 characters a token).
+	Tokens int64 `json:"tokens"`
+}
+
+// HeatmapQuery selects the memories and window of a heatmap.
+type HeatmapQuery struct {
+	Since   time.Time
+	Project string
+	IDs     []int64
+	Limit   int
+}
+
+// Heatmap returns the daily access series of the most accessed memories of
+// a project since q.Since, busiest first. With q.IDs only those memories are
+// returned. Days without accesses are omitted. Memories are scoped like
+// memoryScope: the tenant on ctx, without deleted memories or other owners'
+// private ones.
+func (s *MemoryAccessStore) Heatmap(ctx context.Context, q HeatmapQuery) ([]MemoryAccessSeries, error) {
+	if q.Limit <= 0 {
+		q.Limit = 20
+	}
+	since := q.Since.UTC().Format(time.DateOnly)
+
+	type topRow struct {
+		Preview string
+		ID      int64
+		Total   int64
+		Tokens  int64
+	}
+	top := s.db.WithContext(ctx).Table("memory_access_daily a").
+		Select("a.memory_id AS id, SUM(a.count) AS total, LEFT(m.content, 120) AS preview, (LENGTH(m.content) + 3) / 4 AS tokens").
+		Joins("JOIN memories m ON m.id = a.memory_id").
+		Where("m.tenant = ? AND m.project = ? AND m.deleted_at IS NULL AND a.day >= ?::date", tenant.FromContext(ctx), q.Project, since)
+	if owner, restricted := tenant.OwnerFromContext(ctx); restricted {
+		top = top.Where("(m.visibility <> ? OR m.owner = ?)", models.VisibilityPrivate, owner)
+	}
+	if len(q.IDs) > 0 {
+		top = top.Where("a.memory_id IN ?", q.IDs)
+	}
+	var tops []topRow
+	if err := top.Group("a.memory_id, m.content").Order("total DESC, a.memory_id").Limit(q.Limit).Scan(&tops).Error; err != nil {
+		return nil, fmt.Errorf("memory access heatmap: %w", err)
+	}
+	if len(tops) == 0 {
+		return []MemoryAccessSeries{}, nil
+	}
+
+	ids := make([]int64, len(tops))
+	series := make([]MemoryAccessSeries, len(tops))
+	index := make(map[int64]int, len(tops))
+	for i, t := range tops {
+		ids[i] = t.ID
+		index[t.ID] = i
+		series[i] = MemoryAccessSeries{ID: t.ID, Total: t.Total, Preview: t.Preview, Tokens: t.Tokens, Totals: map[string]int64{}}
+	}
+
+	type dayRow struct {
+		Day      time.Time
+		Source   string
+		MemoryID int64
+		Count    int64
+	}
+	var days []dayRow
+	if err := s.db.WithContext(ctx).Table("memory_access_daily").
+		Select("memory_id, source, day, count").
+		Where("memory_id IN ? AND day >= ?::date", ids, since).
+		Order("memory_id, day").
+		Scan(&days).Error; err != nil {
+		return nil, fmt.Errorf("memory access heatmap: %w", err)
+	}
+	for _, d := range days {
+		ser := &series[index[d.MemoryID]]
+		ser.Totals[d.Source] += d.Count
+		day := d.Day.Format(time.DateOnly)
+		if n := len(ser.Days); n == 0 || ser.Days[n-1].Day != day {
+			ser.Days = append(ser.Days, AccessDay{Day: day, Counts: map[string]int64{}})
+		}
+		ser.Days[len(ser.Days)-1].Counts[d.Source] += d.Count
+	}
+	return series, nil
+}
+
+// PromotionQuery selects the memories list_promotion_candidates proposes for
+// global scope.
+type PromotionQuery struct {
+	Since time.Time
+	// Project limits candidates to memories of one project ("" for all).
+	Project string
+	// Concepts are the concept tags that make a memory globalizable; a
+	// memory needs one of them, with or without a "concept:" prefix.
+	Concepts []string
+	// MinProjects is the fewest other projects that must have accessed a
+	// memory since Since.
+	MinProjects int
+	Limit       int
+}
+
+// PromotionCandidate is a project-scoped memory that other projects use.
+type PromotionCandidate struct {
+	Preview string   `json:"preview"`
+	Project string   `json:"project"`
+	Tags    []string `json:"tags"`
+	// AccessedFrom lists the other projects that accessed the memory.
+	AccessedFrom []string `json:"accessed_from"`
+	ID           int64    `json:"id"`
+	// Accesses counts the accesses from those projects.
+	Accesses int64 `json:"accesses"`
+}
+
+// PromotionCandidates returns the memories of the tenant on ctx that carry
+// one of q.Concepts and were accessed from at least q.MinProjects other
+// projects since q.Since, most widely used first. Memories already tagged
+// scope:global, private memories and memories pending review are skipped,
+// as are accesses whose requesting project is unknown.
+func (s *MemoryAccessStore) PromotionCandidates(ctx context.Context, q PromotionQuery) ([]PromotionCandidate, error) {
+	if q.Limit <= 0 {
+		q.Limit = 20
+	}
+	if q.MinProjects <= 0 {
+		q.MinProjects = 1
+	}
+	concepts := make([]string, 0, 2*len(q.Concepts))
+	for _, c := range q.Concepts {
+		c = strings.ToLower(c)
+		concepts = append(concepts, c, "concept:"+c)
+	}
+	if len(concepts) == 0 {
+		return []PromotionCandidate{}, nil
+	}
+
+	type row struct {
+		Preview  string
+		Project  string
+		Projects string
+		Tags     models.JSONStringArray
+		ID       int64
+		Accesses int64
+	}
+	query := s.db.WithContext(ctx).Table("memory_access_daily a").
+		Select(`m.id, m.project, LEFT(m.content, 120) AS preview, m.tags,
+			string_agg(DISTINCT a.project, E'\n' ORDER BY a.project) AS projects, SUM(a.count) AS accesses`).
+		Joins("JOIN memories m ON m.id = a.memory_id").
+		Where("m.tenant = ? AND m.deleted_at IS NULL AND NOT m.pending_review AND m.visibility <> ?",
+			tenant.FromContext(ctx), models.VisibilityPrivate).
+		Where("NOT m.tags @> ?::jsonb", `["scope:`+string(models.ScopeGlobal)+`"]`).
+		Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(m.tags) t WHERE lower(t) IN ?)", concepts).
+		Where("a.day >= ?::date AND a.project <> '' AND a.project <> m.project", q.Since.UTC().Format(time.DateOnly))
+	if q.Project != "" {
+		query = query.Where("m.project = ?", q.Project)
+	}
+	var rows []row
+	if err := query.Group("m.id, m.project, m.content, m.tags").
+		Having("COUNT(DISTINCT a.project) >= ?", q.MinProjects).
+		Order("COUNT(DISTINCT a.project) DESC, accesses DESC, m.id").
+		Limit(q.Limit).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("promotion candidates: %w", err)
+	}
+	out := make([]PromotionCandidate, len(rows))
+	for i, r := range rows {
+		out[i] = PromotionCandidate{
+			ID:           r.ID,
+			Project:      r.Project,
+			Preview:      r.Preview,
+			Tags:         r.Tags,
+			AccessedFrom: strings.Split(r.Projects, "\n"),
+			Accesses:     r.Accesses,
+		}
+	}
+	return out, nil
+}
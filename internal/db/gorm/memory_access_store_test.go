@@ -0,0 +1,111 @@
+package gorm
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/tenant"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// TestMemoryAccessStore_Heatmap checks that buffered accesses are counted per
+// day and source, and that the heatmap ranks memories by total accesses.
+func TestMemoryAccessStore_Heatmap(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	const project = "test-memory-access"
+	defer db.Exec(`DELETE FROM memories WHERE project = ?`, project)
+
+	ms := NewMemoryStore(&Store{DB: db})
+	ctx := context.Background()
+	busy, err := ms.Create(ctx, &models.Memory{Project: project, Content: strings.Repeat("busy ", 20)})
+	require.NoError(t, err)
+	quiet, err := ms.Create(ctx, &models.Memory{Project: project, Content: "quiet"})
+	require.NoError(t, err)
+
+	store := NewMemoryAccessStore(db)
+	store.Record(AccessInjection, project, []int64{busy.ID, quiet.ID})
+	store.Record(AccessInjection, project, []int64{busy.ID})
+	store.Record(AccessSearch, project, []int64{busy.ID})
+	store.Record(AccessGet, "", []int64{-1}) // unknown memory: skipped
+	store.Close()
+	store.Record(AccessGet, "", []int64{busy.ID}) // after Close: dropped, no panic
+
+	series, err := store.Heatmap(ctx, HeatmapQuery{Project: project, Since: time.Now().AddDate(0, 0, -1)})
+	require.NoError(t, err)
+	require.Len(t, series, 2)
+	assert.Equal(t, busy.ID, series[0].ID)
+	assert.Equal(t, int64(3), series[0].Total)
+	assert.Equal(t, map[string]int64{AccessInjection: 2, AccessSearch: 1}, series[0].Totals)
+	assert.Equal(t, int64(25), series[0].Tokens)
+	require.Len(t, series[0].Days, 1)
+	assert.Equal(t, time.Now().UTC().Format(time.DateOnly), series[0].Days[0].Day)
+
+	only, err := store.Heatmap(ctx, HeatmapQuery{Project: project, IDs: []int64{quiet.ID}, Since: time.Now().AddDate(0, 0, -1)})
+	require.NoError(t, err)
+	require.Len(t, only, 1)
+	assert.Equal(t, int64(1), only[0].Totals[AccessInjection])
+
+	// Other tenants see nothing; other owners do not see private memories.
+	other, err := store.Heatmap(tenant.WithTenant(ctx, "acme"), HeatmapQuery{Project: project, Since: time.Now().AddDate(0, 0, -1)})
+	require.NoError(t, err)
+	assert.Empty(t, other)
+
+	require.NoError(t, db.Exec(`UPDATE memories SET visibility = ?, owner = ? WHERE id = ?`, models.VisibilityPrivate, "alice", busy.ID).Error)
+	bob, err := store.Heatmap(tenant.WithOwner(ctx, "bob"), HeatmapQuery{Project: project, Since: time.Now().AddDate(0, 0, -1)})
+	require.NoError(t, err)
+	require.Len(t, bob, 1)
+	assert.Equal(t, quiet.ID, bob[0].ID)
+}
+
+// TestMemoryAccessStore_PromotionCandidates checks that only concept-tagged,
+// non-global memories accessed from enough other projects are proposed.
+func TestMemoryAccessStore_PromotionCandidates(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	const project = "test-memory-promotion"
+	defer db.Exec(`DELETE FROM memories WHERE project = ?`, project)
+
+	ms := NewMemoryStore(&Store{DB: db})
+	ctx := context.Background()
+	shared, err := ms.Create(ctx, &models.Memory{Project: project, Content: "validate webhook signatures", Tags: []string{"concept:security", "scope:project"}})
+	require.NoError(t, err)
+	untagged, err := ms.Create(ctx, &models.Memory{Project: project, Content: "local build quirk", Tags: []string{"scope:project"}})
+	require.NoError(t, err)
+	global, err := ms.Create(ctx, &models.Memory{Project: project, Content: "already global", Tags: []string{"security", "scope:global"}})
+	require.NoError(t, err)
+	local, err := ms.Create(ctx, &models.Memory{Project: project, Content: "used at home only", Tags: []string{"security"}})
+	require.NoError(t, err)
+
+	store := NewMemoryAccessStore(db)
+	all := []int64{shared.ID, untagged.ID, global.ID}
+	store.Record(AccessSearch, "test-promotion-a", all)
+	store.Record(AccessInjection, "test-promotion-b", all)
+	store.Record(AccessSearch, "test-promotion-b", []int64{shared.ID})
+	store.Record(AccessSearch, project, []int64{local.ID, local.ID})
+	store.Record(AccessGet, "", []int64{local.ID})
+	store.Close()
+
+	q := PromotionQuery{Project: project, Concepts: models.GlobalizableConcepts, MinProjects: 2, Since: time.Now().AddDate(0, 0, -1)}
+	got, err := store.PromotionCandidates(ctx, q)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, shared.ID, got[0].ID)
+	assert.Equal(t, []string{"test-promotion-a", "test-promotion-b"}, got[0].AccessedFrom)
+	assert.Equal(t, int64(3), got[0].Accesses)
+
+	q.MinProjects = 3
+	got, err = store.PromotionCandidates(ctx, q)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	q.MinProjects = 1
+	other, err := store.PromotionCandidates(tenant.WithTenant(ctx, "acme"), q)
+	require.NoError(t, err)
+	assert.Empty(t, other)
+}
@@ -0,0 +1,120 @@
+package gorm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// bulkDeleteBatch is the number of memories soft-deleted per statement.
+const bulkDeleteBatch = 1000
+
+// MemoryFilter selects a project's active memories for bulk operations.
+// Zero fields do not filter.
+type MemoryFilter struct {
+	// Since and Until bound created_at (inclusive, exclusive).
+	Since   time.Time
+	Until   time.Time
+	Project string
+	// Tags must all be present on a memory.
+	Tags []string
+	// Unlocked leaves out locked memories, which bulk changes skip.
+	Unlocked bool
+	// Limit caps the memories returned; 0 or more than MaxPaginationLimit
+	// means MaxPaginationLimit.
+	Limit int
+}
+
+// MatchMemories returns the active memories matching f, oldest first, at
+// most f.Limit of them. Locked memories are included unless f.Unlocked is
+// set. f.Project must not be empty.
+func (s *MemoryStore) MatchMemories(ctx context.Context, f MemoryFilter) ([]*models.Memory, error) {
+	if f.Project == "" {
+		return nil, fmt.Errorf("project: must not be empty")
+	}
+
+	q := s.db.WithContext(ctx).
+		Scopes(memoryScope(ctx)).
+		Where("project = ? AND deleted_at IS NULL", f.Project)
+	if len(f.Tags) > 0 {
+		tags, err := json.Marshal(f.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("encode tags: %w", err)
+		}
+		q = q.Where("tags @> ?::jsonb", string(tags))
+	}
+	if f.Unlocked {
+		q = q.Where("NOT locked")
+	}
+	if !f.Since.IsZero() {
+		q = q.Where("created_at >= ?", f.Since)
+	}
+	if !f.Until.IsZero() {
+		q = q.Where("created_at < ?", f.Until)
+	}
+
+	limit := f.Limit
+	if limit <= 0 || limit > MaxPaginationLimit {
+		limit = MaxPaginationLimit
+	}
+
+	var rows []Memory
+	if err := q.Order("id").Limit(limit).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("match memories for project %q: %w", f.Project, err)
+	}
+	result := make([]*models.Memory, len(rows))
+	for i := range rows {
+		result[i] = memoryRowToModel(&rows[i])
+	}
+	return result, nil
+}
+
+// DeleteMany soft-deletes the memories with the given IDs, skipping locked
+// and already deleted ones, and returns the IDs it deleted.
+func (s *MemoryStore) DeleteMany(ctx context.Context, ids []int64) ([]int64, error) {
+	var deletedIDs []int64
+	for start := 0; start < len(ids); start += bulkDeleteBatch {
+		batch := ids[start:min(start+bulkDeleteBatch, len(ids))]
+
+		var rows []Memory
+		now := time.Now().UTC()
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Scopes(memoryScope(ctx)).
+				Where("id IN ? AND deleted_at IS NULL AND NOT locked", batch).
+				Find(&rows).Error; err != nil {
+				return err
+			}
+			if len(rows) == 0 {
+				return nil
+			}
+			matched := make([]int64, len(rows))
+			for i := range rows {
+				matched[i] = rows[i].ID
+			}
+			return tx.Model(&Memory{}).
+				Where("id IN ?", matched).
+				Updates(map[string]any{
+					"deleted_at": now,
+					"updated_at": now,
+				}).Error
+		})
+		if err != nil {
+			return deletedIDs, fmt.Errorf("delete memories: %w", err)
+		}
+
+		for i := range rows {
+			deletedIDs = append(deletedIDs, rows[i].ID)
+			if s.onChange != nil {
+				mem := memoryRowToModel(&rows[i])
+				mem.DeletedAt = &now
+				s.notify(MemoryChangeDeleted, mem)
+			}
+		}
+	}
+	return deletedIDs, nil
+}
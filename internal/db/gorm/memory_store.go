@@ -3,21 +3,53 @@ package gorm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"github.com/thebtf/engram/internal/tenant"
+	"github.com/thebtf/engram/pkg/langdetect"
 	"github.com/thebtf/engram/pkg/models"
 )
 
+// ErrMemoryNotOwner is returned when a client changes the visibility of a
+// memory created by another client.
+var ErrMemoryNotOwner = errors.New("memory is owned by another client")
+
+// ErrMemoryLocked is returned when an edit, merge, revert or delete targets a
+// locked memory.
+var ErrMemoryLocked = errors.New("memory is locked")
+
 // MemoryStore provides memory-related database operations using GORM.
 // It targets the dedicated memories table created by migration 088.
 //
 // Immutability contract: Create and Update return NEW *models.Memory values populated
 // from the database row. The caller's input struct is never mutated.
+//
+// Tenant isolation: every method is scoped to the tenant on ctx (see internal/tenant);
+// rows of other tenants behave as if they do not exist. Private memories of
+// other owners (tenant.OwnerFromContext) are hidden the same way.
 type MemoryStore struct {
-	db *gorm.DB
+	db       *gorm.DB
+	onChange func(MemoryChange)
+}
+
+// Memory change actions reported to the SetOnChange hook.
+const (
+	MemoryChangeCreated = "created"
+	MemoryChangeUpdated = "updated"
+	MemoryChangeMerged  = "merged"
+	MemoryChangeDeleted = "deleted"
+)
+
+// MemoryChange is a committed change to a memory: its action and the memory
+// as it is after the change (before it, for deletes).
+type MemoryChange struct {
+	Memory *models.Memory
+	Action string
 }
 
 // NewMemoryStore creates a new MemoryStore backed by the given Store.
@@ -25,6 +57,20 @@ func NewMemoryStore(store *Store) *MemoryStore {
 	return &MemoryStore{db: store.DB}
 }
 
+// SetOnChange registers fn to be called after every committed create, update
+// (merges reported as such) and delete, on the caller's goroutine. Set it
+// before the store is shared; fn must not block.
+func (s *MemoryStore) SetOnChange(fn func(MemoryChange)) {
+	s.onChange = fn
+}
+
+// notify reports a change to the SetOnChange hook, if any.
+func (s *MemoryStore) notify(action string, mem *models.Memory) {
+	if s.onChange != nil && mem != nil {
+		s.onChange(MemoryChange{Action: action, Memory: mem})
+	}
+}
+
 // Create inserts a new memory row. Returns a new *models.Memory populated with the
 // database-assigned ID and timestamps. The caller's input is never mutated.
 func (s *MemoryStore) Create(ctx context.Context, mem *models.Memory) (*models.Memory, error) {
@@ -38,25 +84,44 @@ func (s *MemoryStore) Create(ctx context.Context, mem *models.Memory) (*models.M
 		return nil, fmt.Errorf("memory.Content must not be empty")
 	}
 
+	visibility := mem.Visibility
+	if visibility == "" {
+		visibility = models.VisibilityTeam
+	}
+	if !models.IsValidVisibility(string(visibility)) {
+		return nil, fmt.Errorf("memory.Visibility %q is not valid", visibility)
+	}
+	owner, _ := tenant.OwnerFromContext(ctx)
+
 	now := time.Now().UTC()
 	row := &Memory{
-		Project:     mem.Project,
-		Content:     mem.Content,
-		Tags:        models.JSONStringArray(mem.Tags),
-		SourceAgent: mem.SourceAgent,
-		EditedBy:    mem.EditedBy,
-		Version:     1,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		Tenant:        tenant.FromContext(ctx),
+		Visibility:    string(visibility),
+		Owner:         owner,
+		Project:       mem.Project,
+		Content:       mem.Content,
+		Tags:          models.JSONStringArray(mem.Tags),
+		SourceAgent:   mem.SourceAgent,
+		EditedBy:      mem.EditedBy,
+		Language:      mem.Language,
+		PendingReview: mem.PendingReview,
+		Version:       1,
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}
 	if mem.Version > 0 {
 		row.Version = mem.Version
 	}
+	if row.Language == "" {
+		row.Language = langdetect.Detect(mem.Content)
+	}
 
 	if err := s.db.WithContext(ctx).Create(row).Error; err != nil {
 		return nil, fmt.Errorf("create memory for project %q: %w", mem.Project, err)
 	}
-	return memoryRowToModel(row), nil
+	created := memoryRowToModel(row)
+	s.notify(MemoryChangeCreated, created)
+	return created, nil
 }
 
 // Get returns the active (non-soft-deleted) memory with the given ID.
@@ -67,6 +132,7 @@ func (s *MemoryStore) Get(ctx context.Context, id int64) (*models.Memory, error)
 	}
 	var row Memory
 	err := s.db.WithContext(ctx).
+		Scopes(memoryScope(ctx), primaryReads(ctx)).
 		Where("id = ? AND deleted_at IS NULL", id).
 		First(&row).Error
 	if err != nil {
@@ -76,9 +142,235 @@ func (s *MemoryStore) Get(ctx context.Context, id int64) (*models.Memory, error)
 }
 
 // List returns active (non-soft-deleted) memories for the given project,
-// ordered by created_at DESC, limited to limit rows.
+// ordered by created_at DESC (ties broken by id DESC), limited to limit rows.
 // project must not be empty.
 func (s *MemoryStore) List(ctx context.Context, project string, limit int) ([]*models.Memory, error) {
+	return s.ListBefore(ctx, project, time.Time{}, 0, limit)
+}
+
+// ListBefore continues List after the memory (createdAt, id): it returns the
+// memories that sort strictly after it in created_at DESC, id DESC order.
+// Memories created since the previous page sort before it, so paging with
+// the last row of each page neither skips nor repeats rows. A zero createdAt
+// starts from the newest memory.
+func (s *MemoryStore) ListBefore(ctx context.Context, project string, createdAt time.Time, id int64, limit int) ([]*models.Memory, error) {
+	return s.ListBeforeAsOf(ctx, project, createdAt, id, limit, time.Time{})
+}
+
+// ListBeforeAsOf is ListBefore over the memories as they were at asOf: those
+// created by then and not yet deleted (merged or superseded memories are
+// soft-deleted), with the content, tags and version they had at that time,
+// restored from their revisions. A zero asOf lists the current memories.
+func (s *MemoryStore) ListBeforeAsOf(ctx context.Context, project string, createdAt time.Time, id int64, limit int, asOf time.Time) ([]*models.Memory, error) {
+	if project == "" {
+		return nil, fmt.Errorf("project: must not be empty")
+	}
+	return s.ListBeforeAsOfIn(ctx, []string{project}, false, createdAt, id, limit, asOf)
+}
+
+// ListBeforeAsOfIn is ListBeforeAsOf over several projects at once, merged
+// into one created_at DESC, id DESC list. With includeGlobal, memories of
+// any project stored with scope=global (tagged scope:global) are included.
+// projects may only be empty with includeGlobal.
+func (s *MemoryStore) ListBeforeAsOfIn(ctx context.Context, projects []string, includeGlobal bool, createdAt time.Time, id int64, limit int, asOf time.Time) ([]*models.Memory, error) {
+	if len(projects) == 0 && !includeGlobal {
+		return nil, fmt.Errorf("projects: must not be empty")
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	q := s.db.WithContext(ctx).
+		Scopes(memoryScope(ctx)).
+		Where("NOT pending_review")
+	globalTag := `["scope:` + string(models.ScopeGlobal) + `"]`
+	switch {
+	case len(projects) == 0:
+		q = q.Where("tags @> ?::jsonb", globalTag)
+	case includeGlobal:
+		q = q.Where("(project IN ? OR tags @> ?::jsonb)", projects, globalTag)
+	default:
+		q = q.Where("project IN ?", projects)
+	}
+	if asOf.IsZero() {
+		q = q.Where("deleted_at IS NULL")
+	} else {
+		q = q.Where("created_at <= ? AND (deleted_at IS NULL OR deleted_at > ?)", asOf, asOf)
+	}
+	if !createdAt.IsZero() {
+		q = q.Where("(created_at, id) < (?, ?)", createdAt, id)
+	}
+
+	var rows []Memory
+	err := q.Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("list memories for projects %q: %w", projects, err)
+	}
+	result := make([]*models.Memory, len(rows))
+	for i := range rows {
+		result[i] = memoryRowToModel(&rows[i])
+	}
+	if !asOf.IsZero() {
+		if err := s.rewindMemories(ctx, result, asOf); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// ListAfter is the counterpart of ListBefore: it returns the active memories
+// of project stored after the memory (createdAt, id), oldest first.
+func (s *MemoryStore) ListAfter(ctx context.Context, project string, createdAt time.Time, id int64, limit int) ([]*models.Memory, error) {
+	if project == "" {
+		return nil, fmt.Errorf("project: must not be empty")
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	var rows []Memory
+	err := s.db.WithContext(ctx).
+		Scopes(memoryScope(ctx)).
+		Where("project = ? AND NOT pending_review AND deleted_at IS NULL", project).
+		Where("(created_at, id) > (?, ?)", createdAt, id).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("list memories after id=%d: %w", id, err)
+	}
+	result := make([]*models.Memory, len(rows))
+	for i := range rows {
+		result[i] = memoryRowToModel(&rows[i])
+	}
+	return result, nil
+}
+
+// rewindMemories restores memories changed after asOf to the state they had
+// then. A revision snapshots a memory before the change made at its
+// created_at, so the first revision after asOf holds the state at asOf.
+func (s *MemoryStore) rewindMemories(ctx context.Context, memories []*models.Memory, asOf time.Time) error {
+	byID := make(map[int64]*models.Memory)
+	for _, mem := range memories {
+		mem.DeletedAt = nil
+		if mem.UpdatedAt.After(asOf) {
+			byID[mem.ID] = mem
+		}
+	}
+	if len(byID) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+
+	var revisions []MemoryRevision
+	if err := s.db.WithContext(ctx).
+		Where("memory_id IN ? AND created_at > ?", ids, asOf).
+		Order("memory_id, created_at ASC, id ASC").
+		Find(&revisions).Error; err != nil {
+		return fmt.Errorf("load revisions as of %s: %w", asOf.Format(time.RFC3339), err)
+	}
+	for i := range revisions {
+		rev := &revisions[i]
+		mem, ok := byID[rev.MemoryID]
+		if !ok {
+			continue // an earlier revision of this memory already applied
+		}
+		mem.Content = rev.Content
+		mem.Language = langdetect.Detect(rev.Content)
+		mem.Tags = []string(rev.Tags)
+		mem.Version = rev.Version
+		delete(byID, rev.MemoryID)
+	}
+	return nil
+}
+
+// TenantProject names a project within a tenant.
+type TenantProject struct {
+	Tenant  string
+	Project string
+}
+
+// ListRecentProjects returns the projects with active memories created or
+// updated at or after since, of every tenant, ordered by tenant and name. It
+// serves background sweeps: callers read each project with
+// tenant.WithTenant(ctx, p.Tenant), so no work crosses tenants.
+func (s *MemoryStore) ListRecentProjects(ctx context.Context, since time.Time) ([]TenantProject, error) {
+	var projects []TenantProject
+	err := s.db.WithContext(ctx).
+		Model(&Memory{}).
+		Distinct("tenant", "project").
+		Where("deleted_at IS NULL AND updated_at >= ? AND project != ''", since).
+		Order("tenant ASC, project ASC").
+		Scan(&projects).Error
+	if err != nil {
+		return nil, fmt.Errorf("list recent memory projects: %w", err)
+	}
+	return projects, nil
+}
+
+// ListPinned returns the active pinned memories of the given project, newest
+// first, limited to limit rows.
+func (s *MemoryStore) ListPinned(ctx context.Context, project string, limit int) ([]*models.Memory, error) {
+	if project == "" {
+		return nil, fmt.Errorf("project: must not be empty")
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	var rows []Memory
+	err := s.db.WithContext(ctx).
+		Scopes(memoryScope(ctx)).
+		Where("project = ? AND pinned AND NOT pending_review AND deleted_at IS NULL", project).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("list pinned memories for project %q: %w", project, err)
+	}
+	result := make([]*models.Memory, len(rows))
+	for i := range rows {
+		result[i] = memoryRowToModel(&rows[i])
+	}
+	return result, nil
+}
+
+// ListWorkspace returns memories stored with workspace scope under the given
+// workspace id (the project id of a monorepo root), newest first. These are
+// shared with every project inside that workspace.
+func (s *MemoryStore) ListWorkspace(ctx context.Context, workspace string, limit int) ([]*models.Memory, error) {
+	if workspace == "" {
+		return nil, fmt.Errorf("workspace: must not be empty")
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	var rows []Memory
+	err := s.db.WithContext(ctx).
+		Scopes(memoryScope(ctx)).
+		Where("project = ? AND tags @> ?::jsonb AND NOT pending_review AND deleted_at IS NULL", workspace, `["scope:workspace"]`).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("list workspace memories for %q: %w", workspace, err)
+	}
+	result := make([]*models.Memory, len(rows))
+	for i := range rows {
+		result[i] = memoryRowToModel(&rows[i])
+	}
+	return result, nil
+}
+
+// ListWarnings returns the project's memories tagged with a caution or
+// do_not polarity, newest first, limited to limit rows.
+func (s *MemoryStore) ListWarnings(ctx context.Context, project string, limit int) ([]*models.Memory, error) {
 	if project == "" {
 		return nil, fmt.Errorf("project: must not be empty")
 	}
@@ -88,12 +380,106 @@ func (s *MemoryStore) List(ctx context.Context, project string, limit int) ([]*m
 
 	var rows []Memory
 	err := s.db.WithContext(ctx).
-		Where("project = ? AND deleted_at IS NULL", project).
+		Scopes(memoryScope(ctx)).
+		Where("project = ? AND (tags @> ?::jsonb OR tags @> ?::jsonb) AND NOT pending_review AND deleted_at IS NULL", project,
+			`["`+models.PolarityCaution.Tag()+`"]`, `["`+models.PolarityDoNot.Tag()+`"]`).
 		Order("created_at DESC").
 		Limit(limit).
 		Find(&rows).Error
 	if err != nil {
-		return nil, fmt.Errorf("list memories for project %q: %w", project, err)
+		return nil, fmt.Errorf("list warning memories for project %q: %w", project, err)
+	}
+	result := make([]*models.Memory, len(rows))
+	for i := range rows {
+		result[i] = memoryRowToModel(&rows[i])
+	}
+	return result, nil
+}
+
+// CountPinned returns the number of active pinned memories in the project.
+func (s *MemoryStore) CountPinned(ctx context.Context, project string) (int64, error) {
+	var n int64
+	err := s.db.WithContext(ctx).
+		Model(&Memory{}).
+		Scopes(memoryScope(ctx)).
+		Where("project = ? AND pinned AND NOT pending_review AND deleted_at IS NULL", project).
+		Count(&n).Error
+	if err != nil {
+		return 0, fmt.Errorf("count pinned memories for project %q: %w", project, err)
+	}
+	return n, nil
+}
+
+// SetPinned pins or unpins a memory. Pinning is not a content change, so no
+// revision is recorded and the version is unchanged. Returns a wrapped
+// gorm.ErrRecordNotFound if no active row exists.
+func (s *MemoryStore) SetPinned(ctx context.Context, id int64, pinned bool) (*models.Memory, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("memory id must be non-zero")
+	}
+	result := s.db.WithContext(ctx).
+		Model(&Memory{}).
+		Scopes(memoryScope(ctx)).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Updates(map[string]any{
+			"pinned":     pinned,
+			"updated_at": time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("set pinned on memory id=%d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("set pinned on memory id=%d: %w", id, gorm.ErrRecordNotFound)
+	}
+	return s.Get(withPrimaryReads(ctx), id)
+}
+
+// SetLocked locks or unlocks a memory. A locked memory rejects every content
+// change and deletion with ErrMemoryLocked, so maintenance and consolidation
+// jobs leave it alone; only an explicit unlock lifts that. Like pinning, it
+// records no revision. Returns a wrapped gorm.ErrRecordNotFound if no active
+// row exists.
+func (s *MemoryStore) SetLocked(ctx context.Context, id int64, locked bool) (*models.Memory, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("memory id must be non-zero")
+	}
+	result := s.db.WithContext(ctx).
+		Model(&Memory{}).
+		Scopes(memoryScope(ctx)).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Updates(map[string]any{
+			"locked":     locked,
+			"updated_at": time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("set locked on memory id=%d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("set locked on memory id=%d: %w", id, gorm.ErrRecordNotFound)
+	}
+	return s.Get(withPrimaryReads(ctx), id)
+}
+
+// ListPendingReview returns the project's memories held back by the quality
+// gate, oldest first so the review queue is worked in arrival order, limited
+// to limit rows. An empty project lists every project's pending memories.
+func (s *MemoryStore) ListPendingReview(ctx context.Context, project string, limit int) ([]*models.Memory, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	q := s.db.WithContext(ctx).
+		Scopes(memoryScope(ctx)).
+		Where("pending_review AND deleted_at IS NULL")
+	if project != "" {
+		q = q.Where("project = ?", project)
+	}
+	var rows []Memory
+	err := q.Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("list pending review memories for project %q: %w", project, err)
 	}
 	result := make([]*models.Memory, len(rows))
 	for i := range rows {
@@ -102,10 +488,86 @@ func (s *MemoryStore) List(ctx context.Context, project string, limit int) ([]*m
 	return result, nil
 }
 
+// SetPendingReview holds a memory back for review or releases it into lists,
+// search and injection. Like pinning, it records no revision. Returns a
+// wrapped gorm.ErrRecordNotFound if no active row exists.
+func (s *MemoryStore) SetPendingReview(ctx context.Context, id int64, pending bool) (*models.Memory, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("memory id must be non-zero")
+	}
+	result := s.db.WithContext(ctx).
+		Model(&Memory{}).
+		Scopes(memoryScope(ctx)).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Updates(map[string]any{
+			"pending_review": pending,
+			"updated_at":     time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("set pending review on memory id=%d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("set pending review on memory id=%d: %w", id, gorm.ErrRecordNotFound)
+	}
+	return s.Get(withPrimaryReads(ctx), id)
+}
+
+// SetVisibility changes the visibility of a memory. Only its owner may change
+// it; a memory created without an owner is claimed by the caller. Like
+// pinning, it records no revision. Returns a wrapped ErrMemoryNotOwner when
+// the caller does not own the memory and a wrapped gorm.ErrRecordNotFound if
+// no active row is visible to the caller.
+func (s *MemoryStore) SetVisibility(ctx context.Context, id int64, visibility models.Visibility) (*models.Memory, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("memory id must be non-zero")
+	}
+	if !models.IsValidVisibility(string(visibility)) {
+		return nil, fmt.Errorf("visibility %q is not valid (private, team, public)", visibility)
+	}
+	current, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	updates := map[string]any{
+		"visibility": string(visibility),
+		"updated_at": time.Now().UTC(),
+	}
+	if owner, restricted := tenant.OwnerFromContext(ctx); restricted {
+		switch current.Owner {
+		case owner:
+		case "":
+			updates["owner"] = owner
+		default:
+			return nil, fmt.Errorf("set visibility on memory id=%d: %w", id, ErrMemoryNotOwner)
+		}
+	}
+	result := s.db.WithContext(ctx).
+		Model(&Memory{}).
+		Scopes(memoryScope(ctx)).
+		Where("id = ? AND owner = ? AND deleted_at IS NULL", id, current.Owner).
+		Updates(updates)
+	if result.Error != nil {
+		return nil, fmt.Errorf("set visibility on memory id=%d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("set visibility on memory id=%d: %w", id, gorm.ErrRecordNotFound)
+	}
+	return s.Get(withPrimaryReads(ctx), id)
+}
+
 // Update updates an existing memory row by ID.
 // Bumps version and sets updated_at. Returns a NEW populated model.
-// The caller's input struct is never mutated.
+// The caller's input struct is never mutated. The pre-update state is recorded
+// in memory_revisions with action "edit".
 func (s *MemoryStore) Update(ctx context.Context, mem *models.Memory) (*models.Memory, error) {
+	return s.UpdateWithAction(ctx, mem, models.RevisionActionEdit)
+}
+
+// UpdateWithAction is Update with an explicit revision action (tag, merge, revert).
+// The snapshot insert and the row update run in one transaction so a version
+// bump never happens without its revision row. mem.EditedBy is recorded as the actor.
+// Returns a wrapped ErrMemoryLocked when the memory is locked.
+func (s *MemoryStore) UpdateWithAction(ctx context.Context, mem *models.Memory, action models.RevisionAction) (*models.Memory, error) {
 	if mem == nil {
 		return nil, fmt.Errorf("memory must not be nil")
 	}
@@ -115,44 +577,154 @@ func (s *MemoryStore) Update(ctx context.Context, mem *models.Memory) (*models.M
 	if mem.Content == "" {
 		return nil, fmt.Errorf("memory.Content must not be empty")
 	}
+	if action == "" {
+		action = models.RevisionActionEdit
+	}
 
 	now := time.Now().UTC()
 
-	// Perform the update using a map to avoid GORM zero-value omission issues.
-	updates := map[string]any{
-		"content":      mem.Content,
-		"tags":         models.JSONStringArray(mem.Tags),
-		"source_agent": mem.SourceAgent,
-		"edited_by":    mem.EditedBy,
-		"updated_at":   now,
-		"version":      gorm.Expr("version + 1"),
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current Memory
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Scopes(memoryScope(ctx)).
+			Where("id = ? AND deleted_at IS NULL", mem.ID).
+			First(&current).Error; err != nil {
+			return err
+		}
+		if current.Locked {
+			return ErrMemoryLocked
+		}
+
+		revision := &MemoryRevision{
+			MemoryID:  current.ID,
+			Version:   current.Version,
+			Content:   current.Content,
+			Tags:      current.Tags,
+			Action:    string(action),
+			Actor:     mem.EditedBy,
+			CreatedAt: now,
+		}
+		if err := tx.Create(revision).Error; err != nil {
+			return fmt.Errorf("record revision: %w", err)
+		}
+
+		// Perform the update using a map to avoid GORM zero-value omission issues.
+		// Keep the stored (possibly caller-supplied) language unless the
+		// content changed.
+		language := current.Language
+		if mem.Content != current.Content {
+			language = langdetect.Detect(mem.Content)
+		}
+		updates := map[string]any{
+			"content":      mem.Content,
+			"language":     language,
+			"tags":         models.JSONStringArray(mem.Tags),
+			"source_agent": mem.SourceAgent,
+			"edited_by":    mem.EditedBy,
+			"updated_at":   now,
+			"version":      gorm.Expr("version + 1"),
+		}
+		return tx.Model(&Memory{}).
+			Where("id = ? AND deleted_at IS NULL", mem.ID).
+			Updates(updates).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update memory id=%d: %w", mem.ID, err)
 	}
 
-	result := s.db.WithContext(ctx).
-		Model(&Memory{}).
-		Where("id = ? AND deleted_at IS NULL", mem.ID).
-		Updates(updates)
-	if result.Error != nil {
-		return nil, fmt.Errorf("update memory id=%d: %w", mem.ID, result.Error)
+	// Re-fetch to return the fully-populated model.
+	updated, err := s.Get(withPrimaryReads(ctx), mem.ID)
+	if err != nil {
+		return nil, err
 	}
-	if result.RowsAffected == 0 {
-		return nil, fmt.Errorf("update memory id=%d: %w", mem.ID, gorm.ErrRecordNotFound)
+	if action == models.RevisionActionMerge {
+		s.notify(MemoryChangeMerged, updated)
+	} else {
+		s.notify(MemoryChangeUpdated, updated)
 	}
+	return updated, nil
+}
 
-	// Re-fetch to return the fully-populated model.
-	return s.Get(ctx, mem.ID)
+// ListRevisions returns the recorded revisions of a memory, newest version first.
+// limit <= 0 returns every revision.
+func (s *MemoryStore) ListRevisions(ctx context.Context, memoryID int64, limit int) ([]*models.MemoryRevision, error) {
+	if memoryID == 0 {
+		return nil, fmt.Errorf("memory id must be non-zero")
+	}
+	// Revisions carry no tenant column; scope through the owning memory.
+	visible := s.db.Table("memories").Select("1").Scopes(memoryScope(ctx)).
+		Where("memories.id = memory_revisions.memory_id")
+	q := s.db.WithContext(ctx).
+		Where("memory_id = ?", memoryID).
+		Where("EXISTS (?)", visible).
+		Order("version DESC, id DESC")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	var rows []MemoryRevision
+	if err := q.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("list revisions for memory id=%d: %w", memoryID, err)
+	}
+	result := make([]*models.MemoryRevision, len(rows))
+	for i := range rows {
+		result[i] = memoryRevisionRowToModel(&rows[i])
+	}
+	return result, nil
+}
+
+// Revert restores the content and tags a memory had at the given version.
+// The revert itself is a new version (history is never rewritten), recorded with
+// action "revert". Returns a wrapped gorm.ErrRecordNotFound when the version has
+// no revision row.
+func (s *MemoryStore) Revert(ctx context.Context, memoryID int64, version int, actor string) (*models.Memory, error) {
+	if memoryID == 0 {
+		return nil, fmt.Errorf("memory id must be non-zero")
+	}
+	current, err := s.Get(ctx, memoryID)
+	if err != nil {
+		return nil, err
+	}
+	if version <= 0 || version >= current.Version {
+		return nil, fmt.Errorf("revert memory id=%d: version must be between 1 and %d", memoryID, current.Version-1)
+	}
+
+	var row MemoryRevision
+	err = s.db.WithContext(ctx).
+		Where("memory_id = ? AND version = ?", memoryID, version).
+		Order("id DESC").
+		First(&row).Error
+	if err != nil {
+		return nil, fmt.Errorf("revert memory id=%d to version %d: %w", memoryID, version, err)
+	}
+
+	return s.UpdateWithAction(ctx, &models.Memory{
+		ID:          memoryID,
+		Content:     row.Content,
+		Tags:        []string(row.Tags),
+		SourceAgent: current.SourceAgent,
+		EditedBy:    actor,
+	}, models.RevisionActionRevert)
 }
 
 // Delete soft-deletes the memory by setting deleted_at = NOW().
-// Returns gorm.ErrRecordNotFound if no active row exists.
+// Returns gorm.ErrRecordNotFound if no active row exists and ErrMemoryLocked
+// if the memory is locked.
 func (s *MemoryStore) Delete(ctx context.Context, id int64) error {
 	if id == 0 {
 		return fmt.Errorf("memory id must be non-zero")
 	}
+	// The hook needs the deleted memory's project; load it only when hooked.
+	var deleted *models.Memory
+	if s.onChange != nil {
+		if mem, err := s.Get(ctx, id); err == nil {
+			deleted = mem
+		}
+	}
 	now := time.Now().UTC()
 	result := s.db.WithContext(ctx).
 		Model(&Memory{}).
-		Where("id = ? AND deleted_at IS NULL", id).
+		Scopes(memoryScope(ctx)).
+		Where("id = ? AND deleted_at IS NULL AND NOT locked", id).
 		Updates(map[string]any{
 			"deleted_at": now,
 			"updated_at": now,
@@ -161,23 +733,50 @@ func (s *MemoryStore) Delete(ctx context.Context, id int64) error {
 		return fmt.Errorf("delete memory id=%d: %w", id, result.Error)
 	}
 	if result.RowsAffected == 0 {
+		if mem, err := s.Get(withPrimaryReads(ctx), id); err == nil && mem.Locked {
+			return fmt.Errorf("delete memory id=%d: %w", id, ErrMemoryLocked)
+		}
 		return fmt.Errorf("delete memory id=%d: %w", id, gorm.ErrRecordNotFound)
 	}
+	if deleted != nil {
+		deleted.DeletedAt = &now
+		s.notify(MemoryChangeDeleted, deleted)
+	}
 	return nil
 }
 
 // memoryRowToModel converts an internal GORM Memory row to the pkg/models.Memory type.
 func memoryRowToModel(row *Memory) *models.Memory {
 	return &models.Memory{
-		ID:          row.ID,
-		Project:     row.Project,
-		Content:     row.Content,
-		Tags:        []string(row.Tags),
-		SourceAgent: row.SourceAgent,
-		EditedBy:    row.EditedBy,
-		Version:     row.Version,
-		CreatedAt:   row.CreatedAt,
-		UpdatedAt:   row.UpdatedAt,
-		DeletedAt:   row.DeletedAt,
+		ID:            row.ID,
+		Project:       row.Project,
+		Content:       row.Content,
+		Tags:          []string(row.Tags),
+		SourceAgent:   row.SourceAgent,
+		EditedBy:      row.EditedBy,
+		Version:       row.Version,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+		DeletedAt:     row.DeletedAt,
+		Pinned:        row.Pinned,
+		Locked:        row.Locked,
+		PendingReview: row.PendingReview,
+		Language:      row.Language,
+		Visibility:    models.Visibility(row.Visibility),
+		Owner:         row.Owner,
+	}
+}
+
+// memoryRevisionRowToModel converts an internal GORM MemoryRevision row to the pkg/models type.
+func memoryRevisionRowToModel(row *MemoryRevision) *models.MemoryRevision {
+	return &models.MemoryRevision{
+		ID:        row.ID,
+		MemoryID:  row.MemoryID,
+		Version:   row.Version,
+		Content:   row.Content,
+		Tags:      []string(row.Tags),
+		Action:    models.RevisionAction(row.Action),
+		Actor:     row.Actor,
+		CreatedAt: row.CreatedAt,
 	}
 }
@@ -3,10 +3,13 @@ package gorm
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 
+	"github.com/thebtf/engram/internal/tenant"
 	"github.com/thebtf/engram/pkg/models"
 )
 
@@ -166,3 +169,536 @@ func TestMemoryStore_List_FiltersByProject(t *testing.T) {
 	assert.Equal(t, proj2, list2[0].Project)
 	assert.Equal(t, "proj2 memory A", list2[0].Content)
 }
+
+// TestMemoryStore_ListBefore verifies that paging from the last row of each
+// page is stable when memories are added between pages.
+func TestMemoryStore_ListBefore(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	defer db.Exec(`DELETE FROM memories WHERE project = 'test-memory-list-before'`)
+
+	store := &Store{DB: db}
+	ms := NewMemoryStore(store)
+	ctx := context.Background()
+
+	const project = "test-memory-list-before"
+	for _, content := range []string{"first", "second", "third"} {
+		_, err := ms.Create(ctx, &models.Memory{Project: project, Content: content})
+		require.NoError(t, err)
+	}
+
+	page1, err := ms.List(ctx, project, 2)
+	require.NoError(t, err)
+	require.Len(t, page1, 2)
+	assert.Equal(t, "third", page1[0].Content)
+	assert.Equal(t, "second", page1[1].Content)
+
+	// A memory stored between pages must not shift the next page.
+	_, err = ms.Create(ctx, &models.Memory{Project: project, Content: "fourth"})
+	require.NoError(t, err)
+
+	last := page1[len(page1)-1]
+	page2, err := ms.ListBefore(ctx, project, last.CreatedAt, last.ID, 2)
+	require.NoError(t, err)
+	require.Len(t, page2, 1)
+	assert.Equal(t, "first", page2[0].Content)
+}
+
+// TestMemoryStore_ListBeforeAsOf verifies that listing as of a past time
+// excludes later memories, includes memories deleted since and restores
+// edited memories to their content at that time.
+func TestMemoryStore_ListBeforeAsOf(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	const project = "test-memory-as-of"
+	defer db.Exec(`DELETE FROM memories WHERE project = ?`, project)
+
+	ms := NewMemoryStore(&Store{DB: db})
+	ctx := context.Background()
+
+	edited, err := ms.Create(ctx, &models.Memory{Project: project, Content: "cache TTL is 5m", Tags: []string{"cache"}})
+	require.NoError(t, err)
+	deleted, err := ms.Create(ctx, &models.Memory{Project: project, Content: "use the v1 API"})
+	require.NoError(t, err)
+
+	var asOf time.Time
+	require.NoError(t, db.Raw(`SELECT now()`).Scan(&asOf).Error)
+	time.Sleep(10 * time.Millisecond)
+
+	_, err = ms.Update(ctx, &models.Memory{ID: edited.ID, Content: "cache TTL is 1h", Tags: []string{"cache", "ttl"}})
+	require.NoError(t, err)
+	require.NoError(t, ms.Delete(ctx, deleted.ID))
+	_, err = ms.Create(ctx, &models.Memory{Project: project, Content: "added later"})
+	require.NoError(t, err)
+
+	past, err := ms.ListBeforeAsOf(ctx, project, time.Time{}, 0, 10, asOf)
+	require.NoError(t, err)
+	require.Len(t, past, 2)
+	assert.Equal(t, "use the v1 API", past[0].Content)
+	assert.Nil(t, past[0].DeletedAt)
+	assert.Equal(t, "cache TTL is 5m", past[1].Content)
+	assert.Equal(t, []string{"cache"}, past[1].Tags)
+	assert.Equal(t, 1, past[1].Version)
+
+	current, err := ms.List(ctx, project, 10)
+	require.NoError(t, err)
+	require.Len(t, current, 2)
+	assert.Equal(t, "added later", current[0].Content)
+	assert.Equal(t, "cache TTL is 1h", current[1].Content)
+}
+
+// TestMemoryStore_ListBeforeAsOfIn verifies listing several projects at
+// once, with and without the global memories of other projects.
+func TestMemoryStore_ListBeforeAsOfIn(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	projects := []string{"test-multi-a", "test-multi-b", "test-multi-c"}
+	defer db.Exec(`DELETE FROM memories WHERE project IN ?`, projects)
+
+	ms := NewMemoryStore(&Store{DB: db})
+	ctx := context.Background()
+
+	_, err := ms.Create(ctx, &models.Memory{Project: projects[0], Content: "a note"})
+	require.NoError(t, err)
+	_, err = ms.Create(ctx, &models.Memory{Project: projects[1], Content: "b note"})
+	require.NoError(t, err)
+	global, err := ms.Create(ctx, &models.Memory{Project: projects[2], Content: "global rule", Tags: []string{"scope:global"}})
+	require.NoError(t, err)
+	_, err = ms.Create(ctx, &models.Memory{Project: projects[2], Content: "c note"})
+	require.NoError(t, err)
+
+	both, err := ms.ListBeforeAsOfIn(ctx, projects[:2], false, time.Time{}, 0, 10, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, both, 2)
+	assert.Equal(t, "b note", both[0].Content)
+	assert.Equal(t, "a note", both[1].Content)
+
+	withGlobal, err := ms.ListBeforeAsOfIn(ctx, projects[:2], true, time.Time{}, 0, 100, time.Time{})
+	require.NoError(t, err)
+	var ids []int64
+	for _, mem := range withGlobal {
+		ids = append(ids, mem.ID)
+		assert.NotEqual(t, "c note", mem.Content)
+	}
+	assert.Contains(t, ids, global.ID)
+
+	_, err = ms.ListBeforeAsOfIn(ctx, nil, false, time.Time{}, 0, 10, time.Time{})
+	assert.Error(t, err)
+}
+
+// TestMemoryStore_Pinning verifies SetPinned, ListPinned and CountPinned, and that
+// pinning does not bump the version.
+func TestMemoryStore_Pinning(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	defer db.Exec(`DELETE FROM memories WHERE project = 'test-memory-pinned'`)
+
+	ms := NewMemoryStore(&Store{DB: db})
+	ctx := context.Background()
+	const project = "test-memory-pinned"
+
+	a, err := ms.Create(ctx, &models.Memory{Project: project, Content: "never touch prod config"})
+	require.NoError(t, err)
+	_, err = ms.Create(ctx, &models.Memory{Project: project, Content: "ordinary note"})
+	require.NoError(t, err)
+
+	pinned, err := ms.SetPinned(ctx, a.ID, true)
+	require.NoError(t, err)
+	assert.True(t, pinned.Pinned)
+	assert.Equal(t, a.Version, pinned.Version, "pinning must not create a new version")
+
+	list, err := ms.ListPinned(ctx, project, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, a.ID, list[0].ID)
+
+	n, err := ms.CountPinned(ctx, project)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	_, err = ms.SetPinned(ctx, a.ID, false)
+	require.NoError(t, err)
+	list, err = ms.ListPinned(ctx, project, 10)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	_, err = ms.SetPinned(ctx, 999999999, true)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+// TestMemoryStore_Locking verifies that a locked memory rejects edits,
+// reverts and deletes until it is unlocked.
+func TestMemoryStore_Locking(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	defer db.Exec(`DELETE FROM memories WHERE project = 'test-memory-locked'`)
+
+	ms := NewMemoryStore(&Store{DB: db})
+	ctx := context.Background()
+
+	created, err := ms.Create(ctx, &models.Memory{Project: "test-memory-locked", Content: "v1 content"})
+	require.NoError(t, err)
+	_, err = ms.Update(ctx, &models.Memory{ID: created.ID, Content: "v2 content"})
+	require.NoError(t, err)
+
+	locked, err := ms.SetLocked(ctx, created.ID, true)
+	require.NoError(t, err)
+	assert.True(t, locked.Locked)
+	assert.Equal(t, 2, locked.Version, "locking must not create a new version")
+
+	_, err = ms.Update(ctx, &models.Memory{ID: created.ID, Content: "v3 content"})
+	assert.ErrorIs(t, err, ErrMemoryLocked)
+	_, err = ms.Revert(ctx, created.ID, 1, "carol")
+	assert.ErrorIs(t, err, ErrMemoryLocked)
+	assert.ErrorIs(t, ms.Delete(ctx, created.ID), ErrMemoryLocked)
+
+	current, err := ms.Get(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "v2 content", current.Content)
+
+	_, err = ms.SetLocked(ctx, created.ID, false)
+	require.NoError(t, err)
+	require.NoError(t, ms.Delete(ctx, created.ID))
+	assert.ErrorIs(t, ms.Delete(ctx, created.ID), gorm.ErrRecordNotFound)
+
+	_, err = ms.SetLocked(ctx, 999999999, true)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+// TestMemoryStore_PendingReview verifies that a memory held back by the
+// quality gate is left out of lists until it is approved.
+func TestMemoryStore_PendingReview(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	defer db.Exec(`DELETE FROM memories WHERE project = 'test-memory-pending'`)
+
+	ms := NewMemoryStore(&Store{DB: db})
+	ctx := context.Background()
+
+	pending, err := ms.Create(ctx, &models.Memory{Project: "test-memory-pending", Content: "Fixed it.", PendingReview: true})
+	require.NoError(t, err)
+	assert.True(t, pending.PendingReview)
+	_, err = ms.Create(ctx, &models.Memory{Project: "test-memory-pending", Content: "Reviewed content"})
+	require.NoError(t, err)
+
+	listed, err := ms.List(ctx, "test-memory-pending", 10)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "Reviewed content", listed[0].Content)
+
+	queue, err := ms.ListPendingReview(ctx, "test-memory-pending", 10)
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	assert.Equal(t, pending.ID, queue[0].ID)
+
+	approved, err := ms.SetPendingReview(ctx, pending.ID, false)
+	require.NoError(t, err)
+	assert.False(t, approved.PendingReview)
+	assert.Equal(t, 1, approved.Version, "approval must not create a new version")
+
+	listed, err = ms.List(ctx, "test-memory-pending", 10)
+	require.NoError(t, err)
+	assert.Len(t, listed, 2)
+	queue, err = ms.ListPendingReview(ctx, "test-memory-pending", 10)
+	require.NoError(t, err)
+	assert.Empty(t, queue)
+
+	_, err = ms.SetPendingReview(ctx, 999999999, false)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+// TestMemoryStore_Language verifies that the language is detected on create,
+// kept on tag-only updates and re-detected when the content changes.
+func TestMemoryStore_Language(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	defer db.Exec(`DELETE FROM memories WHERE project = 'test-memory-language'`)
+
+	ms := NewMemoryStore(&Store{DB: db})
+	ctx := context.Background()
+
+	mem, err := ms.Create(ctx, &models.Memory{Project: "test-memory-language", Content: "Der Cache wird nicht neu geladen, wenn die Datei fehlt."})
+	require.NoError(t, err)
+	assert.Equal(t, "de", mem.Language)
+
+	mem.Tags = []string{"cache"}
+	mem, err = ms.Update(ctx, mem)
+	require.NoError(t, err)
+	assert.Equal(t, "de", mem.Language)
+
+	mem.Content = "The cache is not reloaded when the file is missing."
+	mem, err = ms.Update(ctx, mem)
+	require.NoError(t, err)
+	assert.Equal(t, "en", mem.Language)
+}
+
+// TestMemoryStore_ListWorkspace verifies that only scope:workspace memories of
+// the requested workspace are returned.
+func TestMemoryStore_ListWorkspace(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	defer db.Exec(`DELETE FROM memories WHERE project IN ('test-ws-root', 'test-ws-other')`)
+
+	ms := NewMemoryStore(&Store{DB: db})
+	ctx := context.Background()
+
+	shared, err := ms.Create(ctx, &models.Memory{Project: "test-ws-root", Content: "use pnpm, never npm", Tags: []string{"scope:workspace"}})
+	require.NoError(t, err)
+	_, err = ms.Create(ctx, &models.Memory{Project: "test-ws-root", Content: "root-only note", Tags: []string{"scope:project"}})
+	require.NoError(t, err)
+	_, err = ms.Create(ctx, &models.Memory{Project: "test-ws-other", Content: "other workspace", Tags: []string{"scope:workspace"}})
+	require.NoError(t, err)
+
+	list, err := ms.ListWorkspace(ctx, "test-ws-root", 10)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, shared.ID, list[0].ID)
+
+	_, err = ms.ListWorkspace(ctx, "", 10)
+	assert.Error(t, err)
+}
+
+// TestMemoryStore_ListWarnings verifies that only caution and do_not memories
+// of the requested project are returned.
+func TestMemoryStore_ListWarnings(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	defer db.Exec(`DELETE FROM memories WHERE project IN ('test-warnings', 'test-warnings-other')`)
+
+	ms := NewMemoryStore(&Store{DB: db})
+	ctx := context.Background()
+
+	caution, err := ms.Create(ctx, &models.Memory{Project: "test-warnings", Content: "retries hide the race", Tags: []string{"polarity:caution"}})
+	require.NoError(t, err)
+	doNot, err := ms.Create(ctx, &models.Memory{Project: "test-warnings", Content: "do not vendor the SDK", Tags: []string{"polarity:do_not"}})
+	require.NoError(t, err)
+	_, err = ms.Create(ctx, &models.Memory{Project: "test-warnings", Content: "use the cache", Tags: []string{"type:feature"}})
+	require.NoError(t, err)
+	_, err = ms.Create(ctx, &models.Memory{Project: "test-warnings-other", Content: "other project", Tags: []string{"polarity:do_not"}})
+	require.NoError(t, err)
+
+	list, err := ms.ListWarnings(ctx, "test-warnings", 10)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, doNot.ID, list[0].ID)
+	assert.Equal(t, caution.ID, list[1].ID)
+
+	_, err = ms.ListWarnings(ctx, "", 10)
+	assert.Error(t, err)
+}
+
+func TestMemoryStore_MatchAndDeleteMany(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	defer db.Exec(`DELETE FROM memories WHERE project = 'test-bulk'`)
+
+	ms := NewMemoryStore(&Store{DB: db})
+	ctx := context.Background()
+
+	change, err := ms.Create(ctx, &models.Memory{Project: "test-bulk", Content: "commit a", Tags: []string{"type:change", "commit"}})
+	require.NoError(t, err)
+	locked, err := ms.Create(ctx, &models.Memory{Project: "test-bulk", Content: "commit b", Tags: []string{"type:change", "commit"}})
+	require.NoError(t, err)
+	_, err = ms.SetLocked(ctx, locked.ID, true)
+	require.NoError(t, err)
+	_, err = ms.Create(ctx, &models.Memory{Project: "test-bulk", Content: "a decision", Tags: []string{"type:decision"}})
+	require.NoError(t, err)
+
+	matched, err := ms.MatchMemories(ctx, MemoryFilter{Project: "test-bulk", Tags: []string{"type:change"}})
+	require.NoError(t, err)
+	require.Len(t, matched, 2)
+	assert.Equal(t, change.ID, matched[0].ID)
+
+	unlocked, err := ms.MatchMemories(ctx, MemoryFilter{Project: "test-bulk", Tags: []string{"type:change"}, Unlocked: true})
+	require.NoError(t, err)
+	require.Len(t, unlocked, 1)
+	assert.Equal(t, change.ID, unlocked[0].ID)
+
+	future, err := ms.MatchMemories(ctx, MemoryFilter{Project: "test-bulk", Since: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Empty(t, future)
+
+	deleted, err := ms.DeleteMany(ctx, []int64{change.ID, locked.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{change.ID}, deleted, "locked memories are skipped")
+	_, err = ms.Get(ctx, change.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	_, err = ms.MatchMemories(ctx, MemoryFilter{})
+	assert.Error(t, err)
+}
+
+func TestMemoryStore_MigrateProject(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	defer db.Exec(`DELETE FROM memories WHERE project IN ('test-migrate-old', 'test-migrate-new')`)
+	defer db.Exec(`DELETE FROM projects WHERE id IN ('test-migrate-old', 'test-migrate-new')`)
+
+	ms := NewMemoryStore(&Store{DB: db})
+	ctx := context.Background()
+
+	mem, err := ms.Create(ctx, &models.Memory{Project: "test-migrate-old", Content: "stored before the move"})
+	require.NoError(t, err)
+	require.NoError(t, UpsertProject(ctx, db, "test-migrate-old", "", "", "", "old"))
+
+	result, err := ms.MigrateProject(ctx, "test-migrate-old", "test-migrate-new")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Moved["memories"])
+
+	got, err := ms.Get(ctx, mem.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "test-migrate-new", got.Project)
+	assert.Equal(t, "test-migrate-new", ResolveProjectID(ctx, db, "test-migrate-old"), "the old ID becomes an alias")
+
+	_, err = ms.MigrateProject(ctx, "test-migrate-new", "test-migrate-new")
+	assert.Error(t, err)
+	_, err = ms.MigrateProject(tenant.WithTenant(ctx, "acme"), "test-migrate-new", "test-migrate-old")
+	assert.ErrorContains(t, err, "default tenant")
+}
+
+// TestMemoryStore_RevisionsAndRevert verifies that every Update snapshots the prior
+// state into memory_revisions and that Revert restores an earlier version as a new one.
+func TestMemoryStore_RevisionsAndRevert(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	defer db.Exec(`DELETE FROM memories WHERE project = 'test-memory-revisions'`)
+
+	store := &Store{DB: db}
+	ms := NewMemoryStore(store)
+	ctx := context.Background()
+
+	created, err := ms.Create(ctx, &models.Memory{Project: "test-memory-revisions", Content: "v1 content", Tags: []string{"a"}})
+	require.NoError(t, err)
+
+	_, err = ms.Update(ctx, &models.Memory{ID: created.ID, Content: "v2 content", Tags: []string{"a"}, EditedBy: "alice"})
+	require.NoError(t, err)
+	_, err = ms.UpdateWithAction(ctx, &models.Memory{ID: created.ID, Content: "v2 content", Tags: []string{"a", "b"}, EditedBy: "bob"}, models.RevisionActionTag)
+	require.NoError(t, err)
+
+	revisions, err := ms.ListRevisions(ctx, created.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, 2, revisions[0].Version)
+	assert.Equal(t, models.RevisionActionTag, revisions[0].Action)
+	assert.Equal(t, "bob", revisions[0].Actor)
+	assert.Equal(t, 1, revisions[1].Version)
+	assert.Equal(t, "v1 content", revisions[1].Content)
+	assert.Equal(t, models.RevisionActionEdit, revisions[1].Action)
+
+	reverted, err := ms.Revert(ctx, created.ID, 1, "carol")
+	require.NoError(t, err)
+	assert.Equal(t, 4, reverted.Version, "revert must create a new version")
+	assert.Equal(t, "v1 content", reverted.Content)
+	assert.Equal(t, []string{"a"}, reverted.Tags)
+
+	revisions, err = ms.ListRevisions(ctx, created.ID, 1)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, models.RevisionActionRevert, revisions[0].Action)
+
+	_, err = ms.Revert(ctx, created.ID, 4, "carol")
+	require.Error(t, err, "reverting to the current version must fail")
+}
+
+// TestMemoryStore_TenantIsolation verifies that memories written under one tenant
+// are invisible to every other tenant, including the default tenant.
+func TestMemoryStore_TenantIsolation(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	defer db.Exec(`DELETE FROM memories WHERE project = 'test-memory-tenants'`)
+
+	ms := NewMemoryStore(&Store{DB: db})
+	alice := tenant.WithTenant(context.Background(), "alice")
+	bob := tenant.WithTenant(context.Background(), "bob")
+
+	created, err := ms.Create(alice, &models.Memory{Project: "test-memory-tenants", Content: "alice secret"})
+	require.NoError(t, err)
+
+	_, err = ms.Get(alice, created.ID)
+	require.NoError(t, err)
+
+	_, err = ms.Get(bob, created.ID)
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = ms.Get(context.Background(), created.ID)
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	list, err := ms.List(bob, "test-memory-tenants", 10)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	_, err = ms.Update(bob, &models.Memory{ID: created.ID, Content: "hijacked"})
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	require.ErrorIs(t, ms.Delete(bob, created.ID), gorm.ErrRecordNotFound)
+
+	revisions, err := ms.ListRevisions(bob, created.ID, 0)
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+
+	// Background sweeps list the recent projects of every tenant, each
+	// tagged with its tenant.
+	recent, err := ms.ListRecentProjects(bob, created.UpdatedAt.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Contains(t, recent, TenantProject{Tenant: "alice", Project: "test-memory-tenants"})
+}
+
+// TestMemoryStore_Visibility checks that private memories are returned only
+// to their owner, that contexts without an owner see everything, and that
+// only the owner can change a memory's visibility.
+func TestMemoryStore_Visibility(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	const project = "test-memory-visibility"
+	defer db.Exec(`DELETE FROM memories WHERE project = ?`, project)
+
+	ms := NewMemoryStore(&Store{DB: db})
+	alice := tenant.WithOwner(context.Background(), "keycard:alice")
+	bob := tenant.WithOwner(context.Background(), "keycard:bob")
+
+	private, err := ms.Create(alice, &models.Memory{Project: project, Content: "alice's scratch notes", Visibility: models.VisibilityPrivate})
+	require.NoError(t, err)
+	assert.Equal(t, "keycard:alice", private.Owner)
+	team, err := ms.Create(alice, &models.Memory{Project: project, Content: "shared deploy checklist"})
+	require.NoError(t, err)
+	assert.Equal(t, models.VisibilityTeam, team.Visibility, "team is the default")
+
+	_, err = ms.Create(alice, &models.Memory{Project: project, Content: "x", Visibility: "secret"})
+	require.Error(t, err)
+
+	_, err = ms.Get(alice, private.ID)
+	require.NoError(t, err)
+	_, err = ms.Get(bob, private.ID)
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = ms.Get(context.Background(), private.ID)
+	require.NoError(t, err, "background jobs are not restricted")
+
+	list, err := ms.List(bob, project, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, team.ID, list[0].ID)
+
+	require.ErrorIs(t, ms.Delete(bob, private.ID), gorm.ErrRecordNotFound)
+	revisions, err := ms.ListRevisions(bob, private.ID, 0)
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+
+	_, err = ms.SetVisibility(bob, team.ID, models.VisibilityPrivate)
+	require.ErrorIs(t, err, ErrMemoryNotOwner)
+	_, err = ms.SetVisibility(bob, private.ID, models.VisibilityTeam)
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	shared, err := ms.SetVisibility(alice, private.ID, models.VisibilityTeam)
+	require.NoError(t, err)
+	assert.Equal(t, models.VisibilityTeam, shared.Visibility)
+	_, err = ms.Get(bob, private.ID)
+	require.NoError(t, err)
+
+	// Memories created without an owner are claimed by the first client
+	// that changes their visibility.
+	unowned, err := ms.Create(context.Background(), &models.Memory{Project: project, Content: "imported note"})
+	require.NoError(t, err)
+	claimed, err := ms.SetVisibility(bob, unowned.ID, models.VisibilityPrivate)
+	require.NoError(t, err)
+	assert.Equal(t, "keycard:bob", claimed.Owner)
+	_, err = ms.Get(alice, unowned.ID)
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-gormigrate/gormigrate/v2"
 	"github.com/rs/zerolog/log"
+	"github.com/thebtf/engram/pkg/langdetect"
 	"github.com/thebtf/engram/pkg/models"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -3268,6 +3269,622 @@ WHERE utility_propagated_at IS NOT NULL`).Error
 				return fmt.Errorf("104_drop_sdk_sessions: IRREVERSIBLE — pg_restore required (C3)")
 			},
 		},
+
+		// Migration 105: memory_revisions — append-only history of memory edits.
+		// MemoryStore writes the pre-change snapshot inside the same transaction as
+		// the UPDATE, so every version bump on memories has a matching revision row.
+		{
+			ID: "105_memory_revisions",
+			Migrate: func(tx *gorm.DB) error {
+				sqls := []string{
+					`CREATE TABLE IF NOT EXISTS memory_revisions (
+						id         BIGSERIAL PRIMARY KEY,
+						memory_id  BIGINT NOT NULL REFERENCES memories(id) ON DELETE CASCADE,
+						version    INTEGER NOT NULL,
+						content    TEXT NOT NULL,
+						tags       JSONB NOT NULL DEFAULT '[]',
+						action     TEXT NOT NULL,
+						actor      TEXT,
+						created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+					)`,
+					`CREATE INDEX IF NOT EXISTS idx_memory_revisions_memory_version
+						ON memory_revisions (memory_id, version DESC)`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 105_memory_revisions: %w", err)
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Exec(`DROP TABLE IF EXISTS memory_revisions`).Error
+			},
+		},
+
+		// Migration 106: tenant isolation. api_tokens.tenant assigns each keycard to
+		// a tenant; memories, behavioral_rules and credentials carry the tenant of
+		// the writer and every store query filters on it. Existing rows (and
+		// operator-key / session callers) belong to the default tenant ''.
+		// The credentials uniqueness moves from (project, key) to
+		// (tenant, project, key) so two users can each own e.g. "GITHUB_TOKEN".
+		{
+			ID: "106_tenant_isolation",
+			Migrate: func(tx *gorm.DB) error {
+				sqls := []string{
+					`ALTER TABLE api_tokens ADD COLUMN IF NOT EXISTS tenant TEXT NOT NULL DEFAULT ''`,
+					`ALTER TABLE memories ADD COLUMN IF NOT EXISTS tenant TEXT NOT NULL DEFAULT ''`,
+					`ALTER TABLE behavioral_rules ADD COLUMN IF NOT EXISTS tenant TEXT NOT NULL DEFAULT ''`,
+					`ALTER TABLE credentials ADD COLUMN IF NOT EXISTS tenant TEXT NOT NULL DEFAULT ''`,
+					`CREATE INDEX IF NOT EXISTS idx_memories_tenant_project_created
+						ON memories (tenant, project, created_at DESC)
+						WHERE deleted_at IS NULL`,
+					`CREATE INDEX IF NOT EXISTS idx_behavioral_rules_tenant
+						ON behavioral_rules (tenant)
+						WHERE deleted_at IS NULL`,
+					`ALTER TABLE credentials DROP CONSTRAINT IF EXISTS credentials_project_key_key`,
+					`CREATE UNIQUE INDEX IF NOT EXISTS idx_credentials_tenant_project_key
+						ON credentials (tenant, project, key)`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 106_tenant_isolation: %w", err)
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				sqls := []string{
+					`DROP INDEX IF EXISTS idx_credentials_tenant_project_key`,
+					`ALTER TABLE credentials ADD CONSTRAINT credentials_project_key_key UNIQUE (project, key)`,
+					`DROP INDEX IF EXISTS idx_behavioral_rules_tenant`,
+					`DROP INDEX IF EXISTS idx_memories_tenant_project_created`,
+					`ALTER TABLE credentials DROP COLUMN IF EXISTS tenant`,
+					`ALTER TABLE behavioral_rules DROP COLUMN IF EXISTS tenant`,
+					`ALTER TABLE memories DROP COLUMN IF EXISTS tenant`,
+					`ALTER TABLE api_tokens DROP COLUMN IF EXISTS tenant`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 106_tenant_isolation rollback: %w", err)
+					}
+				}
+				return nil
+			},
+		},
+
+		// Migration 107: memories.pinned — pinned memories are injected into every
+		// context for their project ahead of scored results (pin_observation tool).
+		{
+			ID: "107_memories_pinned",
+			Migrate: func(tx *gorm.DB) error {
+				sqls := []string{
+					`ALTER TABLE memories ADD COLUMN IF NOT EXISTS pinned BOOLEAN NOT NULL DEFAULT FALSE`,
+					`CREATE INDEX IF NOT EXISTS idx_memories_pinned
+						ON memories (project, created_at DESC)
+						WHERE pinned AND deleted_at IS NULL`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 107_memories_pinned: %w", err)
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				sqls := []string{
+					`DROP INDEX IF EXISTS idx_memories_pinned`,
+					`ALTER TABLE memories DROP COLUMN IF EXISTS pinned`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 107_memories_pinned rollback: %w", err)
+					}
+				}
+				return nil
+			},
+		},
+		// Migration 108: memories.language — ISO 639-1 code detected from the
+		// content (pkg/langdetect), '' when unknown. Existing rows are backfilled
+		// in batches; search and injection use it to prefer the session language.
+		{
+			ID: "108_memories_language",
+			Migrate: func(tx *gorm.DB) error {
+				sqls := []string{
+					`ALTER TABLE memories ADD COLUMN IF NOT EXISTS language TEXT NOT NULL DEFAULT ''`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 108_memories_language: %w", err)
+					}
+				}
+
+				const batchSize = 500
+				var lastID int64
+				for {
+					var rows []struct {
+						ID      int64
+						Content string
+					}
+					if err := tx.Raw(`SELECT id, content FROM memories WHERE id > ? ORDER BY id LIMIT ?`, lastID, batchSize).
+						Scan(&rows).Error; err != nil {
+						return fmt.Errorf("migration 108_memories_language: scan: %w", err)
+					}
+					if len(rows) == 0 {
+						return nil
+					}
+					for _, row := range rows {
+						if lang := langdetect.Detect(row.Content); lang != "" {
+							if err := tx.Exec(`UPDATE memories SET language = ? WHERE id = ?`, lang, row.ID).Error; err != nil {
+								return fmt.Errorf("migration 108_memories_language: backfill id=%d: %w", row.ID, err)
+							}
+						}
+					}
+					lastID = rows[len(rows)-1].ID
+				}
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Exec(`ALTER TABLE memories DROP COLUMN IF EXISTS language`).Error; err != nil {
+					return fmt.Errorf("migration 108_memories_language rollback: %w", err)
+				}
+				return nil
+			},
+		},
+		// Migration 109: audit_log — append-only trail of destructive operations
+		// (deletes, reverts, overwrites) with the actor, client, affected IDs and
+		// a summary of the state that was removed. Queried by get_audit_log.
+		{
+			ID: "109_audit_log",
+			Migrate: func(tx *gorm.DB) error {
+				sqls := []string{
+					`CREATE TABLE IF NOT EXISTS audit_log (
+						id         BIGSERIAL PRIMARY KEY,
+						tenant     TEXT NOT NULL DEFAULT '',
+						actor      TEXT NOT NULL,
+						client     TEXT NOT NULL,
+						operation  TEXT NOT NULL,
+						project    TEXT NOT NULL DEFAULT '',
+						target_ids JSONB NOT NULL DEFAULT '[]',
+						before     TEXT NOT NULL DEFAULT '',
+						created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+					)`,
+					`CREATE INDEX IF NOT EXISTS idx_audit_log_tenant_created
+						ON audit_log (tenant, created_at DESC)`,
+					`CREATE INDEX IF NOT EXISTS idx_audit_log_operation
+						ON audit_log (operation, created_at DESC)`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 109_audit_log: %w", err)
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Exec(`DROP TABLE IF EXISTS audit_log`).Error; err != nil {
+					return fmt.Errorf("migration 109_audit_log rollback: %w", err)
+				}
+				return nil
+			},
+		},
+		// Migration 110: allow detection_source 'manual' for relations created
+		// explicitly through the create_relation MCP tool.
+		{
+			ID: "110_relations_manual_source",
+			Migrate: func(tx *gorm.DB) error {
+				sqls := []string{
+					`ALTER TABLE observation_relations DROP CONSTRAINT IF EXISTS chk_observation_relations_detection_source`,
+					`ALTER TABLE observation_relations ADD CONSTRAINT chk_observation_relations_detection_source CHECK (detection_source IN ('file_overlap','embedding_similarity','temporal_proximity','narrative_mention','concept_overlap','type_progression','creative_association','manual'))`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 110_relations_manual_source: %w", err)
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				sqls := []string{
+					`DELETE FROM observation_relations WHERE detection_source = 'manual'`,
+					`ALTER TABLE observation_relations DROP CONSTRAINT IF EXISTS chk_observation_relations_detection_source`,
+					`ALTER TABLE observation_relations ADD CONSTRAINT chk_observation_relations_detection_source CHECK (detection_source IN ('file_overlap','embedding_similarity','temporal_proximity','narrative_mention','concept_overlap','type_progression','creative_association'))`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 110_relations_manual_source rollback: %w", err)
+					}
+				}
+				return nil
+			},
+		},
+		// Migration 111: memory_attachments — code snippets and diffs attached to
+		// a memory, kept out of the memories row so search and injection skip them.
+		{
+			ID: "111_memory_attachments",
+			Migrate: func(tx *gorm.DB) error {
+				sqls := []string{
+					`CREATE TABLE IF NOT EXISTS memory_attachments (
+						id         BIGSERIAL PRIMARY KEY,
+						memory_id  BIGINT NOT NULL REFERENCES memories(id) ON DELETE CASCADE,
+						kind       TEXT NOT NULL CHECK (kind IN ('code','diff')),
+						name       TEXT NOT NULL DEFAULT '',
+						language   TEXT NOT NULL DEFAULT '',
+						content    TEXT NOT NULL,
+						size       INTEGER NOT NULL,
+						created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+					)`,
+					`CREATE INDEX IF NOT EXISTS idx_memory_attachments_memory
+						ON memory_attachments (memory_id, id)`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 111_memory_attachments: %w", err)
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Exec(`DROP TABLE IF EXISTS memory_attachments`).Error; err != nil {
+					return fmt.Errorf("migration 111_memory_attachments rollback: %w", err)
+				}
+				return nil
+			},
+		},
+		// Migration 112: relation type 'merged_from', the provenance link from a
+		// memory to one merged into it (store action=merge).
+		{
+			ID: "112_relations_merged_from",
+			Migrate: func(tx *gorm.DB) error {
+				sqls := []string{
+					`ALTER TABLE observation_relations DROP CONSTRAINT IF EXISTS chk_observation_relations_relation_type`,
+					`ALTER TABLE observation_relations ADD CONSTRAINT chk_observation_relations_relation_type CHECK (relation_type IN ('causes','fixes','supersedes','depends_on','relates_to','evolves_from','leads_to','similar_to','contradicts','reinforces','invalidated_by','explains','shares_theme','parallel_context','summarizes','part_of','prefers_over','modifies','reads','follows','prompted_by','references','referenced_by','merged_from'))`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 112_relations_merged_from: %w", err)
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				sqls := []string{
+					`DELETE FROM observation_relations WHERE relation_type = 'merged_from'`,
+					`ALTER TABLE observation_relations DROP CONSTRAINT IF EXISTS chk_observation_relations_relation_type`,
+					`ALTER TABLE observation_relations ADD CONSTRAINT chk_observation_relations_relation_type CHECK (relation_type IN ('causes','fixes','supersedes','depends_on','relates_to','evolves_from','leads_to','similar_to','contradicts','reinforces','invalidated_by','explains','shares_theme','parallel_context','summarizes','part_of','prefers_over','modifies','reads','follows','prompted_by','references','referenced_by'))`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 112_relations_merged_from rollback: %w", err)
+					}
+				}
+				return nil
+			},
+		},
+		// Migration 113: injection experiment log. One 'assigned' row per session
+		// and experiment records the arm it got; outcome events of the session
+		// (follow-up searches, memory ratings) are appended under the same arm.
+		{
+			ID: "113_injection_experiment_events",
+			Migrate: func(tx *gorm.DB) error {
+				sqls := []string{
+					`CREATE TABLE IF NOT EXISTS injection_experiment_events (
+						id         BIGSERIAL PRIMARY KEY,
+						experiment TEXT NOT NULL,
+						arm        TEXT NOT NULL,
+						session_id TEXT NOT NULL,
+						event      TEXT NOT NULL,
+						created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+					)`,
+					`CREATE UNIQUE INDEX IF NOT EXISTS idx_injection_experiment_assigned
+						ON injection_experiment_events (experiment, session_id)
+						WHERE event = 'assigned'`,
+					`CREATE INDEX IF NOT EXISTS idx_injection_experiment_session
+						ON injection_experiment_events (session_id, created_at DESC)`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 113_injection_experiment_events: %w", err)
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Exec(`DROP TABLE IF EXISTS injection_experiment_events`).Error; err != nil {
+					return fmt.Errorf("migration 113_injection_experiment_events rollback: %w", err)
+				}
+				return nil
+			},
+		},
+		// Migration 114: memory visibility. Private memories are returned only to
+		// the client that created them (owner); existing memories become team.
+		{
+			ID: "114_memories_visibility",
+			Migrate: func(tx *gorm.DB) error {
+				sqls := []string{
+					`ALTER TABLE memories ADD COLUMN IF NOT EXISTS visibility TEXT NOT NULL DEFAULT 'team'`,
+					`ALTER TABLE memories ADD COLUMN IF NOT EXISTS owner TEXT NOT NULL DEFAULT ''`,
+					`ALTER TABLE memories DROP CONSTRAINT IF EXISTS chk_memories_visibility`,
+					`ALTER TABLE memories ADD CONSTRAINT chk_memories_visibility CHECK (visibility IN ('private','team','public'))`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 114_memories_visibility: %w", err)
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				sqls := []string{
+					`ALTER TABLE memories DROP CONSTRAINT IF EXISTS chk_memories_visibility`,
+					`ALTER TABLE memories DROP COLUMN IF EXISTS owner`,
+					`ALTER TABLE memories DROP COLUMN IF EXISTS visibility`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 114_memories_visibility rollback: %w", err)
+					}
+				}
+				return nil
+			},
+		},
+		// Migration 115: full-text index over logged user prompts, for
+		// searching past prompts (find_past_prompt). Expression index, so the
+		// query must repeat to_tsvector('english', query) verbatim.
+		{
+			ID: "115_search_query_log_prompt_fts",
+			Migrate: func(tx *gorm.DB) error {
+				if err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_search_query_log_prompt_fts
+					ON search_query_log USING GIN (to_tsvector('english', query))
+					WHERE search_type = 'prompt'`).Error; err != nil {
+					return fmt.Errorf("migration 115_search_query_log_prompt_fts: %w", err)
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Exec(`DROP INDEX IF EXISTS idx_search_query_log_prompt_fts`).Error
+			},
+		},
+		// Migration 116: user-assigned session name and tags, so a session can
+		// be found later by label instead of by date (tag_session).
+		{
+			ID: "116_sdk_sessions_labels",
+			Migrate: func(tx *gorm.DB) error {
+				sqls := []string{
+					`ALTER TABLE sdk_sessions ADD COLUMN IF NOT EXISTS name TEXT`,
+					`ALTER TABLE sdk_sessions ADD COLUMN IF NOT EXISTS tags JSONB NOT NULL DEFAULT '[]'`,
+					`CREATE INDEX IF NOT EXISTS idx_sdk_sessions_tags ON sdk_sessions USING GIN (tags)`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 116_sdk_sessions_labels: %w", err)
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				sqls := []string{
+					`DROP INDEX IF EXISTS idx_sdk_sessions_tags`,
+					`ALTER TABLE sdk_sessions DROP COLUMN IF EXISTS tags`,
+					`ALTER TABLE sdk_sessions DROP COLUMN IF EXISTS name`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 116_sdk_sessions_labels rollback: %w", err)
+					}
+				}
+				return nil
+			},
+		},
+		// Migration 117: memories.locked — a locked memory rejects edits, merges,
+		// reverts and deletes until it is explicitly unlocked (lock_observation).
+		{
+			ID: "117_memories_locked",
+			Migrate: func(tx *gorm.DB) error {
+				if err := tx.Exec(`ALTER TABLE memories ADD COLUMN IF NOT EXISTS locked BOOLEAN NOT NULL DEFAULT FALSE`).Error; err != nil {
+					return fmt.Errorf("migration 117_memories_locked: %w", err)
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Exec(`ALTER TABLE memories DROP COLUMN IF EXISTS locked`).Error
+			},
+		},
+		// Migration 118: memories.pending_review — memories stored below the
+		// quality gate floor wait for review_observation before they are listed,
+		// searched or injected.
+		{
+			ID: "118_memories_pending_review",
+			Migrate: func(tx *gorm.DB) error {
+				sqls := []string{
+					`ALTER TABLE memories ADD COLUMN IF NOT EXISTS pending_review BOOLEAN NOT NULL DEFAULT FALSE`,
+					`CREATE INDEX IF NOT EXISTS idx_memories_pending_review ON memories (project, created_at DESC) WHERE pending_review AND deleted_at IS NULL`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 118_memories_pending_review: %w", err)
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				sqls := []string{
+					`DROP INDEX IF EXISTS idx_memories_pending_review`,
+					`ALTER TABLE memories DROP COLUMN IF EXISTS pending_review`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 118_memories_pending_review rollback: %w", err)
+					}
+				}
+				return nil
+			},
+		},
+		// Migration 119: retrieval_stats_daily — per-day totals of pruned
+		// retrieval_stats_log rows, so the raw event log stays within the event
+		// retention window (ENGRAM_EVENT_RETENTION_DAYS) while all-time stats
+		// survive. Plus a created_at index for the pruning sweeps.
+		{
+			ID: "119_retrieval_stats_daily",
+			Migrate: func(tx *gorm.DB) error {
+				sqls := []string{
+					`CREATE TABLE IF NOT EXISTS retrieval_stats_daily (
+					project TEXT NOT NULL,
+					event_type TEXT NOT NULL,
+					day DATE NOT NULL,
+					count BIGINT NOT NULL DEFAULT 0,
+					PRIMARY KEY (project, event_type, day)
+				)`,
+					`CREATE INDEX IF NOT EXISTS idx_retrieval_stats_log_created ON retrieval_stats_log (created_at)`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 119_retrieval_stats_daily: %w", err)
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				sqls := []string{
+					`DROP INDEX IF EXISTS idx_retrieval_stats_log_created`,
+					`DROP TABLE IF EXISTS retrieval_stats_daily`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 119_retrieval_stats_daily rollback: %w", err)
+					}
+				}
+				return nil
+			},
+		},
+		// 120: per-memory daily access counts by source (injection, search,
+		// get), so get_access_heatmap can show which memories are actually used
+		// and which only take up context.
+		{
+			ID: "120_memory_access_daily",
+			Migrate: func(tx *gorm.DB) error {
+				sqls := []string{
+					`CREATE TABLE IF NOT EXISTS memory_access_daily (
+					memory_id BIGINT NOT NULL REFERENCES memories(id) ON DELETE CASCADE,
+					source TEXT NOT NULL,
+					day DATE NOT NULL,
+					count BIGINT NOT NULL DEFAULT 0,
+					PRIMARY KEY (memory_id, source, day)
+				)`,
+					`CREATE INDEX IF NOT EXISTS idx_memory_access_daily_day ON memory_access_daily (day)`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 120_memory_access_daily: %w", err)
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Exec(`DROP TABLE IF EXISTS memory_access_daily`).Error; err != nil {
+					return fmt.Errorf("migration 120_memory_access_daily rollback: %w", err)
+				}
+				return nil
+			},
+		},
+		// 121: tenant of each logged query, so prompt search and prompt history
+		// only read the caller's tenant. Rows logged before stay in the default
+		// tenant.
+		{
+			ID: "121_search_query_log_tenant",
+			Migrate: func(tx *gorm.DB) error {
+				sqls := []string{
+					`ALTER TABLE search_query_log ADD COLUMN IF NOT EXISTS tenant TEXT NOT NULL DEFAULT ''`,
+					`CREATE INDEX IF NOT EXISTS idx_search_query_log_tenant_created
+						ON search_query_log (tenant, created_at DESC)`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 121_search_query_log_tenant: %w", err)
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				sqls := []string{
+					`DROP INDEX IF EXISTS idx_search_query_log_tenant_created`,
+					`ALTER TABLE search_query_log DROP COLUMN IF EXISTS tenant`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 121_search_query_log_tenant rollback: %w", err)
+					}
+				}
+				return nil
+			},
+		},
+		// 122: tenant of each session, so session lists, the session_tag filter
+		// and tag_session only reach the caller's tenant. Sessions started
+		// before stay in the default tenant.
+		{
+			ID: "122_sdk_sessions_tenant",
+			Migrate: func(tx *gorm.DB) error {
+				sqls := []string{
+					`ALTER TABLE sdk_sessions ADD COLUMN IF NOT EXISTS tenant TEXT NOT NULL DEFAULT ''`,
+					`CREATE INDEX IF NOT EXISTS idx_sdk_sessions_tenant_started
+						ON sdk_sessions (tenant, started_at_epoch DESC)`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 122_sdk_sessions_tenant: %w", err)
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				sqls := []string{
+					`DROP INDEX IF EXISTS idx_sdk_sessions_tenant_started`,
+					`ALTER TABLE sdk_sessions DROP COLUMN IF EXISTS tenant`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 122_sdk_sessions_tenant rollback: %w", err)
+					}
+				}
+				return nil
+			},
+		},
+		// 123: project each memory access was made from, so memories used by
+		// other projects can be proposed for global scope
+		// (list_promotion_candidates). Earlier accesses keep project ''.
+		{
+			ID: "123_memory_access_daily_project",
+			Migrate: func(tx *gorm.DB) error {
+				sqls := []string{
+					`ALTER TABLE memory_access_daily ADD COLUMN IF NOT EXISTS project TEXT NOT NULL DEFAULT ''`,
+					`ALTER TABLE memory_access_daily DROP CONSTRAINT IF EXISTS memory_access_daily_pkey`,
+					`ALTER TABLE memory_access_daily ADD PRIMARY KEY (memory_id, source, day, project)`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 123_memory_access_daily_project: %w", err)
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				sqls := []string{
+					`INSERT INTO memory_access_daily (memory_id, source, day, project, count)
+						SELECT memory_id, source, day, '', SUM(count) FROM memory_access_daily
+						WHERE project <> '' GROUP BY memory_id, source, day
+						ON CONFLICT (memory_id, source, day, project) DO UPDATE SET count = memory_access_daily.count + EXCLUDED.count`,
+					`DELETE FROM memory_access_daily WHERE project <> ''`,
+					`ALTER TABLE memory_access_daily DROP CONSTRAINT IF EXISTS memory_access_daily_pkey`,
+					`ALTER TABLE memory_access_daily DROP COLUMN IF EXISTS project`,
+					`ALTER TABLE memory_access_daily ADD PRIMARY KEY (memory_id, source, day)`,
+				}
+				for _, s := range sqls {
+					if err := tx.Exec(s).Error; err != nil {
+						return fmt.Errorf("migration 123_memory_access_daily_project rollback: %w", err)
+					}
+				}
+				return nil
+			},
+		},
 	})
 	if err := m.Migrate(); err != nil {
 		return fmt.Errorf("run gormigrate migrations: %w", err)
@@ -20,6 +20,7 @@ import (
 type SDKSession struct {
 	ClaudeSessionID     string         `gorm:"uniqueIndex;not null"`
 	Project             string         `gorm:"index;not null"`
+	Tenant              string         `gorm:"type:text;not null;default:''"`
 	Status              string         `gorm:"type:text;check:status IN ('active', 'completed', 'failed');default:'active';index"`
 	StartedAt           string         `gorm:"not null"`
 	SDKSessionID        sql.NullString `gorm:"uniqueIndex"`
@@ -27,14 +28,16 @@ type SDKSession struct {
 	CompletedAt         sql.NullString
 	WorkerPort          sql.NullInt64
 	CompletedAtEpoch    sql.NullInt64
-	Outcome             sql.NullString `gorm:"type:text"`
-	OutcomeReason       sql.NullString `gorm:"type:text"`
-	OutcomeRecordedAt   sql.NullString `gorm:"type:timestamptz"`
-	UtilityPropagatedAt sql.NullTime   `gorm:"type:timestamptz"`
-	InjectionStrategy   sql.NullString `gorm:"type:text"`
-	ID                  int64          `gorm:"primaryKey;autoIncrement"`
-	PromptCounter       int            `gorm:"default:0"`
-	StartedAtEpoch      int64          `gorm:"index:idx_sessions_started,sort:desc;not null"`
+	Outcome             sql.NullString         `gorm:"type:text"`
+	OutcomeReason       sql.NullString         `gorm:"type:text"`
+	OutcomeRecordedAt   sql.NullString         `gorm:"type:timestamptz"`
+	UtilityPropagatedAt sql.NullTime           `gorm:"type:timestamptz"`
+	InjectionStrategy   sql.NullString         `gorm:"type:text"`
+	Name                sql.NullString         `gorm:"type:text"`
+	Tags                models.JSONStringArray `gorm:"type:jsonb;not null;default:'[]'"`
+	ID                  int64                  `gorm:"primaryKey;autoIncrement"`
+	PromptCounter       int                    `gorm:"default:0"`
+	StartedAtEpoch      int64                  `gorm:"index:idx_sessions_started,sort:desc;not null"`
 }
 
 func (SDKSession) TableName() string { return "sdk_sessions" }
@@ -79,8 +82,8 @@ func (c *ObservationConflict) BeforeCreate(tx *gorm.DB) error {
 
 // ObservationRelation tracks relationships between observations.
 type ObservationRelation struct {
-	RelationType    models.RelationType            `gorm:"type:text;check:relation_type IN ('causes', 'fixes', 'supersedes', 'depends_on', 'relates_to', 'evolves_from', 'leads_to', 'similar_to', 'contradicts', 'reinforces', 'invalidated_by', 'explains', 'shares_theme', 'parallel_context', 'summarizes', 'part_of', 'prefers_over', 'modifies', 'reads', 'follows', 'prompted_by', 'references', 'referenced_by');index:idx_relations_type;uniqueIndex:idx_relations_unique,priority:3;not null"`
-	DetectionSource models.RelationDetectionSource `gorm:"type:text;check:detection_source IN ('file_overlap', 'embedding_similarity', 'temporal_proximity', 'narrative_mention', 'concept_overlap', 'type_progression', 'creative_association');not null"`
+	RelationType    models.RelationType            `gorm:"type:text;check:relation_type IN ('causes', 'fixes', 'supersedes', 'depends_on', 'relates_to', 'evolves_from', 'leads_to', 'similar_to', 'contradicts', 'reinforces', 'invalidated_by', 'explains', 'shares_theme', 'parallel_context', 'summarizes', 'part_of', 'prefers_over', 'modifies', 'reads', 'follows', 'prompted_by', 'references', 'referenced_by', 'merged_from');index:idx_relations_type;uniqueIndex:idx_relations_unique,priority:3;not null"`
+	DetectionSource models.RelationDetectionSource `gorm:"type:text;check:detection_source IN ('file_overlap', 'embedding_similarity', 'temporal_proximity', 'narrative_mention', 'concept_overlap', 'type_progression', 'creative_association', 'manual');not null"`
 	CreatedAt       string                         `gorm:"not null"`
 	Reason          sql.NullString                 `gorm:"type:text"`
 	ID              int64                          `gorm:"primaryKey;autoIncrement"`
@@ -205,6 +208,7 @@ type APIToken struct {
 	TokenHash    string     `gorm:"type:text;not null"`
 	TokenPrefix  string     `gorm:"type:text;not null;index"`
 	Scope        string     `gorm:"type:text;not null;default:read-write"`
+	Tenant       string     `gorm:"type:text;not null;default:''"`
 	CreatedAt    time.Time  `gorm:"not null;default:now()"`
 	LastUsedAt   *time.Time `gorm:"column:last_used_at"`
 	RequestCount int64      `gorm:"not null;default:0"`
@@ -284,8 +288,9 @@ func (IssueComment) TableName() string { return "issue_comments" }
 // Pre-v5: credentials lived as rows in observations (type='credential').
 // Post-v5 (US3): credentials are migrated here and observations is dropped.
 type Credential struct {
-	Project                  string     `gorm:"type:text;not null;index:idx_credentials_project,where:deleted_at IS NULL;uniqueIndex:idx_credentials_project_key,priority:1" json:"project"`
-	Key                      string     `gorm:"type:text;not null;uniqueIndex:idx_credentials_project_key,priority:2" json:"key"`
+	Tenant                   string     `gorm:"type:text;not null;default:'';uniqueIndex:idx_credentials_tenant_project_key,priority:1" json:"tenant,omitempty"`
+	Project                  string     `gorm:"type:text;not null;index:idx_credentials_project,where:deleted_at IS NULL;uniqueIndex:idx_credentials_tenant_project_key,priority:2" json:"project"`
+	Key                      string     `gorm:"type:text;not null;uniqueIndex:idx_credentials_tenant_project_key,priority:3" json:"key"`
 	EncryptedSecret          []byte     `gorm:"type:bytea;not null" json:"encrypted_secret"`
 	EncryptionKeyFingerprint string     `gorm:"type:text;not null;index:idx_credentials_fingerprint,where:deleted_at IS NULL" json:"encryption_key_fingerprint"`
 	Scope                    string     `gorm:"type:text" json:"scope,omitempty"`
@@ -305,23 +310,46 @@ func (Credential) TableName() string { return "credentials" }
 // statements.  GORM will only write columns that are present in the struct, so omitting the
 // search_vector field here is the correct approach.
 type Memory struct {
-	Project     string                 `gorm:"type:text;not null;index:idx_memories_project_created,priority:1,where:deleted_at IS NULL" json:"project"`
-	Content     string                 `gorm:"type:text;not null" json:"content"`
-	Tags        models.JSONStringArray `gorm:"type:jsonb;not null;default:'[]'" json:"tags"`
-	SourceAgent string                 `gorm:"type:text" json:"source_agent,omitempty"`
-	EditedBy    string                 `gorm:"type:text" json:"edited_by,omitempty"`
-	CreatedAt   time.Time              `gorm:"type:timestamptz;not null;default:now();index:idx_memories_project_created,priority:2,sort:desc" json:"created_at"`
-	UpdatedAt   time.Time              `gorm:"type:timestamptz;not null;default:now()" json:"updated_at"`
-	DeletedAt   *time.Time             `gorm:"type:timestamptz" json:"deleted_at,omitempty"`
-	ID          int64                  `gorm:"primaryKey;autoIncrement" json:"id"`
-	Version     int                    `gorm:"not null;default:1" json:"version"`
+	Tenant        string                 `gorm:"type:text;not null;default:''" json:"tenant,omitempty"`
+	Project       string                 `gorm:"type:text;not null;index:idx_memories_project_created,priority:1,where:deleted_at IS NULL" json:"project"`
+	Content       string                 `gorm:"type:text;not null" json:"content"`
+	Tags          models.JSONStringArray `gorm:"type:jsonb;not null;default:'[]'" json:"tags"`
+	SourceAgent   string                 `gorm:"type:text" json:"source_agent,omitempty"`
+	EditedBy      string                 `gorm:"type:text" json:"edited_by,omitempty"`
+	CreatedAt     time.Time              `gorm:"type:timestamptz;not null;default:now();index:idx_memories_project_created,priority:2,sort:desc" json:"created_at"`
+	UpdatedAt     time.Time              `gorm:"type:timestamptz;not null;default:now()" json:"updated_at"`
+	DeletedAt     *time.Time             `gorm:"type:timestamptz" json:"deleted_at,omitempty"`
+	ID            int64                  `gorm:"primaryKey;autoIncrement" json:"id"`
+	Version       int                    `gorm:"not null;default:1" json:"version"`
+	Pinned        bool                   `gorm:"not null;default:false" json:"pinned"`
+	Locked        bool                   `gorm:"not null;default:false" json:"locked"`
+	PendingReview bool                   `gorm:"not null;default:false" json:"pending_review"`
+	Language      string                 `gorm:"type:text;not null;default:''" json:"language,omitempty"`
+	Visibility    string                 `gorm:"type:text;not null;default:'team'" json:"visibility"`
+	Owner         string                 `gorm:"type:text;not null;default:''" json:"owner,omitempty"`
 }
 
 func (Memory) TableName() string { return "memories" }
 
+// MemoryRevision is the GORM row struct for the memory_revisions table (migration 105).
+// Each row snapshots a memory's content and tags at Version before Action replaced it.
+type MemoryRevision struct {
+	Action    string                 `gorm:"type:text;not null" json:"action"`
+	Actor     string                 `gorm:"type:text" json:"actor,omitempty"`
+	Content   string                 `gorm:"type:text;not null" json:"content"`
+	Tags      models.JSONStringArray `gorm:"type:jsonb;not null;default:'[]'" json:"tags"`
+	CreatedAt time.Time              `gorm:"type:timestamptz;not null;default:now()" json:"created_at"`
+	ID        int64                  `gorm:"primaryKey;autoIncrement" json:"id"`
+	MemoryID  int64                  `gorm:"not null;index:idx_memory_revisions_memory_version,priority:1" json:"memory_id"`
+	Version   int                    `gorm:"not null;index:idx_memory_revisions_memory_version,priority:2" json:"version"`
+}
+
+func (MemoryRevision) TableName() string { return "memory_revisions" }
+
 // BehavioralRule is the GORM row struct for the behavioral_rules table (migration 089).
 // Project is a pointer because the column is NULLable: NULL = global rule.
 type BehavioralRule struct {
+	Tenant    string     `gorm:"type:text;not null;default:''" json:"tenant,omitempty"`
 	Project   *string    `gorm:"type:text" json:"project,omitempty"`
 	Content   string     `gorm:"type:text;not null" json:"content"`
 	EditedBy  string     `gorm:"type:text" json:"edited_by,omitempty"`
@@ -334,3 +362,34 @@ type BehavioralRule struct {
 }
 
 func (BehavioralRule) TableName() string { return "behavioral_rules" }
+
+// AuditLogEntry is the GORM row struct for the audit_log table (migration 109):
+// an append-only record of destructive operations.
+type AuditLogEntry struct {
+	Tenant    string                 `gorm:"type:text;not null;default:''" json:"tenant,omitempty"`
+	Actor     string                 `gorm:"type:text;not null" json:"actor"`
+	Client    string                 `gorm:"type:text;not null" json:"client"`
+	Operation string                 `gorm:"type:text;not null" json:"operation"`
+	Project   string                 `gorm:"type:text;not null;default:''" json:"project"`
+	Before    string                 `gorm:"type:text;not null;default:''" json:"before"`
+	TargetIDs models.JSONStringArray `gorm:"column:target_ids;type:jsonb;not null;default:'[]'" json:"target_ids"`
+	CreatedAt time.Time              `gorm:"type:timestamptz;not null;default:now()" json:"created_at"`
+	ID        int64                  `gorm:"primaryKey;autoIncrement" json:"id"`
+}
+
+func (AuditLogEntry) TableName() string { return "audit_log" }
+
+// MemoryAttachmentRow is the GORM row struct for the memory_attachments table
+// (migration 111): code snippets and diffs attached to a memory.
+type MemoryAttachmentRow struct {
+	CreatedAt time.Time `gorm:"type:timestamptz;not null;default:now()"`
+	Kind      string    `gorm:"type:text;not null"`
+	Name      string    `gorm:"type:text;not null;default:''"`
+	Language  string    `gorm:"type:text;not null;default:''"`
+	Content   string    `gorm:"type:text;not null"`
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	MemoryID  int64     `gorm:"not null;index"`
+	Size      int       `gorm:"not null"`
+}
+
+func (MemoryAttachmentRow) TableName() string { return "memory_attachments" }
@@ -8,6 +8,8 @@ import (
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+
+	"github.com/thebtf/engram/internal/tenant"
 )
 
 // UpsertProject registers or updates a project identity record.
@@ -64,3 +66,116 @@ func ResolveProjectID(ctx context.Context, db *gorm.DB, projectID string) string
 	}
 	return canonicalID
 }
+
+// ProjectMigration reports what MigrateProject moved, as row counts per
+// table. Kept counts rows left under the old ID because the new project
+// already has one with the same key (a credential name or a document path).
+type ProjectMigration struct {
+	Moved map[string]int64 `json:"moved"`
+	Kept  map[string]int64 `json:"kept,omitempty"`
+}
+
+// MigrateProject moves a project's data from oldID to newID, e.g. after its
+// directory was moved or its git remote renamed, and records oldID as an
+// alias of newID so ResolveProjectID maps stragglers to it. The old project
+// row is marked removed. Sessions, issues, documents and the other tables
+// that are not tenant-aware move too, so only the default tenant may migrate
+// projects. Logs and the audit trail keep the ID they were recorded under.
+func MigrateProject(ctx context.Context, db *gorm.DB, oldID, newID string) (*ProjectMigration, error) {
+	if oldID == "" || newID == "" {
+		return nil, fmt.Errorf("old and new project IDs must not be empty")
+	}
+	if oldID == newID {
+		return nil, fmt.Errorf("old and new project IDs are the same")
+	}
+	t := tenant.FromContext(ctx)
+	if t != tenant.Default {
+		return nil, fmt.Errorf("project migration is limited to the default tenant")
+	}
+
+	result := &ProjectMigration{Moved: map[string]int64{}, Kept: map[string]int64{}}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		exec := func(table, query string, args ...any) (int64, error) {
+			res := tx.Exec(query, args...)
+			if res.Error != nil {
+				return 0, fmt.Errorf("migrate %s: %w", table, res.Error)
+			}
+			return res.RowsAffected, nil
+		}
+		move := func(table, query string, args ...any) error {
+			n, err := exec(table, query, args...)
+			if n > 0 {
+				result.Moved[table] += n
+			}
+			return err
+		}
+		keep := func(table, query string, args ...any) error {
+			var n int64
+			if err := tx.Raw(`SELECT COUNT(*) FROM `+table+` WHERE `+query, args...).Scan(&n).Error; err != nil {
+				return fmt.Errorf("count kept %s: %w", table, err)
+			}
+			if n > 0 {
+				result.Kept[table] = n
+			}
+			return nil
+		}
+
+		for _, step := range []struct {
+			table, query string
+			args         []any
+		}{
+			{"memories", `UPDATE memories SET project = ? WHERE project = ? AND tenant = ?`, []any{newID, oldID, t}},
+			{"behavioral_rules", `UPDATE behavioral_rules SET project = ? WHERE project = ? AND tenant = ?`, []any{newID, oldID, t}},
+			{"credentials", `UPDATE credentials c SET project = ? WHERE c.project = ? AND c.tenant = ?
+				AND NOT EXISTS (SELECT 1 FROM credentials n WHERE n.tenant = c.tenant AND n.project = ? AND n.key = c.key)`, []any{newID, oldID, t, newID}},
+			{"sdk_sessions", `UPDATE sdk_sessions SET project = ? WHERE project = ?`, []any{newID, oldID}},
+			{"issues", `UPDATE issues SET source_project = ? WHERE source_project = ?`, []any{newID, oldID}},
+			{"issues", `UPDATE issues SET target_project = ? WHERE target_project = ?`, []any{newID, oldID}},
+			{"issue_comments", `UPDATE issue_comments SET author_project = ? WHERE author_project = ?`, []any{newID, oldID}},
+			{"reasoning_traces", `UPDATE reasoning_traces SET project = ? WHERE project = ?`, []any{newID, oldID}},
+			{"versioned_documents", `UPDATE versioned_documents d SET project = ? WHERE d.project = ?
+				AND NOT EXISTS (SELECT 1 FROM versioned_documents n WHERE n.project = ? AND n.path = d.path)`, []any{newID, oldID, newID}},
+		} {
+			if err := move(step.table, step.query, step.args...); err != nil {
+				return err
+			}
+		}
+
+		for _, step := range []struct {
+			table, query string
+			args         []any
+		}{
+			{"credentials", `project = ? AND tenant = ?`, []any{oldID, t}},
+			{"versioned_documents", `project = ?`, []any{oldID}},
+		} {
+			if err := keep(step.table, step.query, step.args...); err != nil {
+				return err
+			}
+		}
+
+		// Register the alias: the new project (revived if it was removed)
+		// inherits the old one's aliases, and the old row stops being listed.
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&Project{ID: newID}).Error; err != nil {
+			return fmt.Errorf("create project %s: %w", newID, err)
+		}
+		if _, err := exec("projects", `UPDATE projects p
+			SET legacy_ids = ARRAY(SELECT DISTINCT unnest(COALESCE(p.legacy_ids, ARRAY[]::TEXT[]) || ARRAY[?]::TEXT[]
+				|| COALESCE((SELECT legacy_ids FROM projects WHERE id = ?), ARRAY[]::TEXT[]))),
+				removed_at = NULL
+			WHERE p.id = ?`, oldID, oldID, newID); err != nil {
+			return err
+		}
+		_, err := exec("projects", `UPDATE projects SET removed_at = NOW() WHERE id = ? AND removed_at IS NULL`, oldID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// MigrateProject moves a project's data from oldID to newID. See the
+// package-level MigrateProject.
+func (s *MemoryStore) MigrateProject(ctx context.Context, oldID, newID string) (*ProjectMigration, error) {
+	return MigrateProject(ctx, s.db, oldID, newID)
+}
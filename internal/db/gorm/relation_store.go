@@ -204,6 +204,16 @@ func (s *RelationStore) DeleteRelationsByObservationID(ctx context.Context, obsI
 	return result.Error
 }
 
+// DeleteRelation deletes the relation (sourceID, targetID, relationType) and
+// reports how many rows were removed (0 or 1).
+func (s *RelationStore) DeleteRelation(ctx context.Context, sourceID, targetID int64, relationType models.RelationType) (int64, error) {
+	result := s.db.WithContext(ctx).
+		Where("source_id = ? AND target_id = ? AND relation_type = ?", sourceID, targetID, relationType).
+		Delete(&ObservationRelation{})
+
+	return result.RowsAffected, result.Error
+}
+
 // GetRelationCount returns the count of relations for an observation.
 func (s *RelationStore) GetRelationCount(ctx context.Context, obsID int64) (int, error) {
 	var count int64
@@ -241,6 +251,39 @@ func (s *RelationStore) GetRelationCountsBatch(ctx context.Context, obsIDs []int
 	return counts, nil
 }
 
+// GetConfirmationCountsBatch counts, per memory, the current relations that
+// confirm it: memories that reinforce it and duplicates merged into it.
+// Memories without confirmations are absent from the result.
+func (s *RelationStore) GetConfirmationCountsBatch(ctx context.Context, obsIDs []int64) (map[int64]int, error) {
+	counts := make(map[int64]int, len(obsIDs))
+	if len(obsIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ObsID int64 `gorm:"column:obs_id"`
+		Cnt   int64 `gorm:"column:cnt"`
+	}
+
+	now := time.Now()
+	err := s.db.WithContext(ctx).
+		Raw("SELECT obs_id, COUNT(*) AS cnt FROM ("+
+			"SELECT target_id AS obs_id FROM observation_relations WHERE target_id IN (?) AND relation_type = ? AND (valid_to IS NULL OR valid_to > ?) "+
+			"UNION ALL SELECT source_id AS obs_id FROM observation_relations WHERE source_id IN (?) AND relation_type = ? AND (valid_to IS NULL OR valid_to > ?)"+
+			") t GROUP BY obs_id",
+			obsIDs, models.RelationReinforces, now, obsIDs, models.RelationMergedFrom, now).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.ObsID] = int(row.Cnt)
+	}
+
+	return counts, nil
+}
+
 // GetAvgConfidenceBatch returns average confidence for the requested observations.
 func (s *RelationStore) GetAvgConfidenceBatch(ctx context.Context, obsIDs []int64) (map[int64]float64, error) {
 	avgConfidence := make(map[int64]float64, len(obsIDs))
@@ -403,3 +446,44 @@ func (s *RelationStore) GetRelationsAsOf(ctx context.Context, obsID int64, asOf
 
 	return toModelRelations(relations), nil
 }
+
+// GetProjectMemoryRelations returns the current relations (valid_to unset or
+// in the future) whose source and target are both active memories of
+// project that the caller on ctx may see.
+func (s *RelationStore) GetProjectMemoryRelations(ctx context.Context, project string) ([]*models.ObservationRelation, error) {
+	visible := s.db.Model(&Memory{}).Select("id").Scopes(memoryScope(ctx)).
+		Where("project = ? AND deleted_at IS NULL", project)
+
+	var relations []ObservationRelation
+	err := s.db.WithContext(ctx).
+		Where("source_id IN (?) AND target_id IN (?)", visible, visible).
+		Where("(valid_to IS NULL OR valid_to > ?)", time.Now()).
+		Order("source_id, target_id, relation_type").
+		Find(&relations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return toModelRelations(relations), nil
+}
+
+// GetRelationsAmong returns the current relations of the given types whose
+// source and target are both in ids.
+func (s *RelationStore) GetRelationsAmong(ctx context.Context, ids []int64, types []models.RelationType) ([]*models.ObservationRelation, error) {
+	if len(ids) < 2 || len(types) == 0 {
+		return nil, nil
+	}
+
+	var relations []ObservationRelation
+	err := s.db.WithContext(ctx).
+		Where("source_id IN ? AND target_id IN ?", ids, ids).
+		Where("relation_type IN ?", types).
+		Where("(valid_to IS NULL OR valid_to > ?)", time.Now()).
+		Order("source_id, target_id, relation_type").
+		Find(&relations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return toModelRelations(relations), nil
+}
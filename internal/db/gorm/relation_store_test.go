@@ -360,6 +360,38 @@ func TestRelationStore_GetRelationCountsBatch(t *testing.T) {
 	assert.Equal(t, 1, counts[3])
 }
 
+func TestRelationStore_GetConfirmationCountsBatch(t *testing.T) {
+	relationStore, _, cleanup := testRelationStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+	past := now.Add(-time.Hour)
+
+	relations := []*models.ObservationRelation{
+		{SourceID: 1, TargetID: 2, RelationType: models.RelationReinforces},
+		{SourceID: 3, TargetID: 2, RelationType: models.RelationReinforces},
+		{SourceID: 2, TargetID: 4, RelationType: models.RelationMergedFrom},
+		{SourceID: 2, TargetID: 5, RelationType: models.RelationReinforces},
+		{SourceID: 6, TargetID: 2, RelationType: models.RelationRelatesTo},
+		{SourceID: 7, TargetID: 3, RelationType: models.RelationReinforces, ValidTo: &past},
+	}
+	for _, rel := range relations {
+		rel.Confidence = 0.8
+		rel.DetectionSource = models.DetectionSourceManual
+		rel.CreatedAt = now.Format(time.RFC3339)
+		rel.CreatedAtEpoch = now.UnixMilli()
+	}
+
+	err := relationStore.StoreRelations(ctx, relations)
+	require.NoError(t, err)
+
+	counts, err := relationStore.GetConfirmationCountsBatch(ctx, []int64{2, 3})
+	require.NoError(t, err)
+	assert.Equal(t, 3, counts[2], "two reinforcing memories and one merged duplicate")
+	assert.Zero(t, counts[3], "expired relations do not count")
+}
+
 func TestRelationStore_GetAvgConfidenceBatch(t *testing.T) {
 	relationStore, _, cleanup := testRelationStore(t)
 	defer cleanup()
@@ -0,0 +1,152 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+const (
+	// replicaCheckInterval is how often a replica's health is re-checked
+	// while it is being used.
+	replicaCheckInterval = 10 * time.Second
+
+	// replicaPingTimeout bounds one replica health check.
+	replicaPingTimeout = 2 * time.Second
+)
+
+// replicaHealth is the last known health of one replica pool.
+type replicaHealth struct {
+	checked  time.Time
+	down     bool
+	checking bool
+}
+
+// replicaPolicy spreads reads round-robin over the healthy replicas. The last
+// pool it is given is a connection to the primary, used only when every
+// replica is down. Health is checked in the background, at most once per
+// interval per replica, so resolving a query never waits on a ping.
+type replicaPolicy struct {
+	health   map[gorm.ConnPool]*replicaHealth
+	interval time.Duration
+	mu       sync.Mutex
+	next     atomic.Uint64
+}
+
+func newReplicaPolicy(interval time.Duration) *replicaPolicy {
+	return &replicaPolicy{
+		health:   make(map[gorm.ConnPool]*replicaHealth),
+		interval: interval,
+	}
+}
+
+// Resolve implements dbresolver.Policy.
+func (p *replicaPolicy) Resolve(pools []gorm.ConnPool) gorm.ConnPool {
+	replicas := pools[:len(pools)-1]
+	start := p.next.Add(1)
+	for i := range replicas {
+		pool := replicas[(start+uint64(i))%uint64(len(replicas))]
+		if p.healthy(pool) {
+			return pool
+		}
+	}
+	return pools[len(pools)-1]
+}
+
+// healthy reports the last known health of pool, starting a background check
+// when it is due. A replica is assumed healthy until a check says otherwise.
+func (p *replicaPolicy) healthy(pool gorm.ConnPool) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h, ok := p.health[pool]
+	if !ok {
+		h = &replicaHealth{}
+		p.health[pool] = h
+	}
+	if !h.checking && time.Since(h.checked) >= p.interval {
+		h.checking = true
+		go p.check(pool, h)
+	}
+	return !h.down
+}
+
+// check pings pool and records the result, logging changes of health.
+func (p *replicaPolicy) check(pool gorm.ConnPool, h *replicaHealth) {
+	var err error
+	if pinger, ok := pool.(interface{ PingContext(context.Context) error }); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), replicaPingTimeout)
+		err = pinger.PingContext(ctx)
+		cancel()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	wasDown := h.down
+	h.down = err != nil
+	h.checked = time.Now()
+	h.checking = false
+	switch {
+	case h.down && !wasDown:
+		log.Warn().Err(err).Msg("Database replica unhealthy, routing its reads elsewhere")
+	case !h.down && wasDown:
+		log.Info().Msg("Database replica healthy again")
+	}
+}
+
+// replicaResolver routes reads to the replicas at replicaDSNs and writes to
+// the primary. A second connection to the primary (primaryDSN) is appended to
+// the replicas as the fallback replicaPolicy uses when no replica is healthy;
+// it also makes dbresolver consult the policy with a single replica.
+func replicaResolver(primaryDSN string, replicaDSNs []string) *dbresolver.DBResolver {
+	replicas := make([]gorm.Dialector, 0, len(replicaDSNs)+1)
+	for _, dsn := range replicaDSNs {
+		replicas = append(replicas, postgres.Open(dsn))
+	}
+	replicas = append(replicas, postgres.Open(primaryDSN))
+	return dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   newReplicaPolicy(replicaCheckInterval),
+	})
+}
+
+// useReplicas registers read replicas on db, with pool limits matching the
+// primary's. Reads (queries outside a transaction) go to the replicas;
+// writes, transactions and GetRawDB stay on the primary. Replicas may lag
+// the primary: a read that must see a write just made uses primaryReads.
+func useReplicas(db *gorm.DB, primaryDSN string, replicaDSNs []string, maxConns int) (*dbresolver.DBResolver, error) {
+	resolver := replicaResolver(primaryDSN, replicaDSNs).
+		SetMaxOpenConns(maxConns).
+		SetMaxIdleConns(maxConns / 2).
+		SetConnMaxLifetime(1 * time.Hour).
+		SetConnMaxIdleTime(10 * time.Minute)
+	if err := db.Use(resolver); err != nil {
+		return nil, fmt.Errorf("register read replicas: %w", err)
+	}
+	return resolver, nil
+}
+
+type primaryReadsKey struct{}
+
+// withPrimaryReads marks ctx so reads scoped with primaryReads go to the
+// primary, for re-fetching a row right after writing it.
+func withPrimaryReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadsKey{}, true)
+}
+
+// primaryReads is a GORM scope that routes the query to the primary when ctx
+// was marked by withPrimaryReads. Without replicas it is a no-op.
+func primaryReads(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if v, _ := ctx.Value(primaryReadsKey{}).(bool); v {
+			return db.Clauses(dbresolver.Write)
+		}
+		return db
+	}
+}
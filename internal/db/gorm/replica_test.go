@@ -0,0 +1,69 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// fakePool is a gorm.ConnPool whose health check returns pingErr.
+type fakePool struct {
+	pingErr error
+	name    string
+}
+
+func (p *fakePool) PrepareContext(context.Context, string) (*sql.Stmt, error) { return nil, nil }
+func (p *fakePool) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	return nil, nil
+}
+func (p *fakePool) QueryContext(context.Context, string, ...any) (*sql.Rows, error) {
+	return nil, nil
+}
+func (p *fakePool) QueryRowContext(context.Context, string, ...any) *sql.Row { return nil }
+func (p *fakePool) PingContext(context.Context) error                        { return p.pingErr }
+
+func TestReplicaPolicy_Resolve(t *testing.T) {
+	r1, r2, primary := &fakePool{name: "r1"}, &fakePool{name: "r2"}, &fakePool{name: "primary"}
+	pools := []gorm.ConnPool{r1, r2, primary}
+	p := newReplicaPolicy(time.Hour)
+	// Checked just now, so Resolve starts no background checks.
+	for _, pool := range []*fakePool{r1, r2} {
+		p.health[pool] = &replicaHealth{checked: time.Now()}
+	}
+
+	seen := map[gorm.ConnPool]int{}
+	for range 4 {
+		seen[p.Resolve(pools)]++
+	}
+	assert.Equal(t, 2, seen[r1], "reads are spread over the replicas")
+	assert.Equal(t, 2, seen[r2])
+
+	p.health[r1].down = true
+	for range 3 {
+		assert.Same(t, r2, p.Resolve(pools), "a down replica is skipped")
+	}
+
+	p.health[r2].down = true
+	assert.Same(t, primary, p.Resolve(pools), "the primary serves reads when every replica is down")
+}
+
+func TestReplicaPolicy_Check(t *testing.T) {
+	pool := &fakePool{pingErr: errors.New("connection refused")}
+	p := newReplicaPolicy(time.Hour)
+	h := &replicaHealth{checking: true}
+	p.health[pool] = h
+
+	p.check(pool, h)
+	assert.True(t, h.down)
+	assert.False(t, h.checking)
+	assert.False(t, h.checked.IsZero())
+
+	pool.pingErr = nil
+	p.check(pool, h)
+	assert.False(t, h.down, "a replica answering again is used again")
+}
@@ -3,6 +3,7 @@ package gorm
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -134,17 +135,24 @@ func (s *RetrievalStatsLogStore) GetStats(ctx context.Context, project string, s
 	}
 	var rows []row
 
-	q := s.db.WithContext(ctx).
-		Table("retrieval_stats_log").
-		Select("event_type, SUM(count) as total").
-		Group("event_type")
-	if project != "" {
-		q = q.Where("project = ?", project)
-	}
+	// Rows older than the event retention window live on as daily totals
+	// (see Rollup); a day counts when it starts at or after since.
+	sql := `SELECT event_type, SUM(total) AS total FROM (
+		SELECT event_type, SUM(count) AS total FROM retrieval_stats_log
+		WHERE (@project = '' OR project = @project) AND (@since::timestamptz IS NULL OR created_at >= @since)
+		GROUP BY event_type
+		UNION ALL
+		SELECT event_type, SUM(count) AS total FROM retrieval_stats_daily
+		WHERE (@project = '' OR project = @project) AND (@since::timestamptz IS NULL OR day >= @since)
+		GROUP BY event_type
+	) t GROUP BY event_type`
+	var sinceArg any
 	if !since.IsZero() {
-		q = q.Where("created_at >= ?", since)
+		sinceArg = since
 	}
-	if err := q.Find(&rows).Error; err != nil {
+	if err := s.db.WithContext(ctx).
+		Raw(sql, map[string]any{"project": project, "since": sinceArg}).
+		Scan(&rows).Error; err != nil {
 		return nil, err
 	}
 
@@ -170,6 +178,31 @@ func (s *RetrievalStatsLogStore) GetStats(ctx context.Context, project string, s
 	return stats, nil
 }
 
+// Rollup folds the entries created before cutoff into per-day totals in
+// retrieval_stats_daily and deletes them, in one transaction. GetStats reads
+// both tables, so totals are unchanged; only the per-event timestamps are
+// lost. Returns the number of entries rolled up.
+func (s *RetrievalStatsLogStore) Rollup(ctx context.Context, cutoff time.Time) (int64, error) {
+	var n int64
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`INSERT INTO retrieval_stats_daily (project, event_type, day, count)
+			SELECT project, event_type, (created_at AT TIME ZONE 'UTC')::date, SUM(count)
+			FROM retrieval_stats_log WHERE created_at < ?
+			GROUP BY 1, 2, 3
+			ON CONFLICT (project, event_type, day) DO UPDATE SET count = retrieval_stats_daily.count + EXCLUDED.count`,
+			cutoff).Error; err != nil {
+			return fmt.Errorf("roll up retrieval stats: %w", err)
+		}
+		result := tx.Where("created_at < ?", cutoff).Delete(&RetrievalStatsLogEntry{})
+		if result.Error != nil {
+			return fmt.Errorf("delete rolled up retrieval stats: %w", result.Error)
+		}
+		n = result.RowsAffected
+		return nil
+	})
+	return n, err
+}
+
 // Cleanup deletes entries older than the given duration.
 func (s *RetrievalStatsLogStore) Cleanup(ctx context.Context, olderThan time.Duration) (int64, error) {
 	cutoff := time.Now().Add(-olderThan)
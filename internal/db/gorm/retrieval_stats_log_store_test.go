@@ -0,0 +1,56 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRetrievalStatsLogStore_Rollup checks that rolled-up events leave the
+// raw log but still count in GetStats.
+func TestRetrievalStatsLogStore_Rollup(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	const project = "test-retrieval-rollup"
+	defer db.Exec(`DELETE FROM retrieval_stats_log WHERE project = ?`, project)
+	defer db.Exec(`DELETE FROM retrieval_stats_daily WHERE project = ?`, project)
+
+	now := time.Now().UTC()
+	old := now.AddDate(-2, 0, 0)
+	for _, e := range []RetrievalStatsLogEntry{
+		{EventType: "search_request", Count: 2, CreatedAt: old},
+		{EventType: "search_request", Count: 3, CreatedAt: old.Add(time.Minute)},
+		{EventType: "search_request", Count: 1, CreatedAt: now},
+		{EventType: "context_injection", Count: 4, CreatedAt: old},
+	} {
+		e.Project = project
+		require.NoError(t, db.Create(&e).Error)
+	}
+
+	store := &RetrievalStatsLogStore{db: db}
+	ctx := context.Background()
+	before, err := store.GetStats(ctx, project, time.Time{})
+	require.NoError(t, err)
+
+	n, err := store.Rollup(ctx, now.AddDate(-1, 0, 0))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, n, int64(3))
+
+	var raw int64
+	require.NoError(t, db.Model(&RetrievalStatsLogEntry{}).Where("project = ?", project).Count(&raw).Error)
+	assert.Equal(t, int64(1), raw)
+
+	after, err := store.GetStats(ctx, project, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+	assert.Equal(t, int64(6), after.SearchRequests)
+	assert.Equal(t, int64(4), after.ContextInjections)
+
+	recent, err := store.GetStats(ctx, project, now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), recent.SearchRequests)
+	assert.Zero(t, recent.ContextInjections)
+}
@@ -7,11 +7,18 @@ import (
 
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
+
+	"github.com/thebtf/engram/internal/tenant"
 )
 
+// SearchTypePrompt is the search type of queries that are user prompts sent
+// for context injection (/api/context/search), as opposed to explicit searches.
+const SearchTypePrompt = "prompt"
+
 // SearchQueryLogEntry represents a single logged search query.
 type SearchQueryLogEntry struct {
 	ID         int64     `gorm:"primaryKey;autoIncrement"`
+	Tenant     string    `gorm:"type:text;not null;default:''"`
 	Project    string    `gorm:"type:text"`
 	Query      string    `gorm:"type:text;not null"`
 	SearchType string    `gorm:"type:text;not null"`
@@ -33,11 +40,13 @@ func NewSearchQueryLogStore(db *gorm.DB) *SearchQueryLogStore {
 	return &SearchQueryLogStore{db: db}
 }
 
-// LogQuery asynchronously inserts a search query log entry.
+// LogQuery asynchronously inserts a search query log entry in the tenant on ctx.
 // Fire-and-forget: logs warning on error, never blocks caller.
-func (s *SearchQueryLogStore) LogQuery(project, query, searchType string, results int, latencyMs float32) {
+func (s *SearchQueryLogStore) LogQuery(ctx context.Context, project, query, searchType string, results int, latencyMs float32) {
+	t := tenant.FromContext(ctx)
 	go func() {
 		entry := SearchQueryLogEntry{
+			Tenant:     t,
 			Project:    project,
 			Query:      query,
 			SearchType: searchType,
@@ -132,7 +141,8 @@ type RecentQueryEntry struct {
 	Results    int       `json:"results"`
 }
 
-// GetRecent returns the most recent search queries from the persistent log.
+// GetRecent returns the most recent search queries of the tenant on ctx from
+// the persistent log.
 func (s *SearchQueryLogStore) GetRecent(ctx context.Context, project string, limit int) ([]RecentQueryEntry, error) {
 	if limit <= 0 {
 		limit = 20
@@ -143,6 +153,7 @@ func (s *SearchQueryLogStore) GetRecent(ctx context.Context, project string, lim
 
 	var entries []SearchQueryLogEntry
 	q := s.db.WithContext(ctx).
+		Scopes(tenantScope(ctx)).
 		Order("created_at DESC").
 		Limit(limit)
 	if project != "" {
@@ -165,6 +176,91 @@ func (s *SearchQueryLogStore) GetRecent(ctx context.Context, project string, lim
 	return result, nil
 }
 
+// ListSince returns the logged queries of the tenant on ctx created at or
+// after since, newest first, optionally filtered by project and search type,
+// capped at limit.
+func (s *SearchQueryLogStore) ListSince(ctx context.Context, project, searchType string, since time.Time, limit int) ([]SearchQueryLogEntry, error) {
+	if limit <= 0 {
+		limit = 1000
+	}
+
+	q := s.db.WithContext(ctx).
+		Scopes(tenantScope(ctx)).
+		Where("created_at >= ?", since).
+		Order("created_at DESC").
+		Limit(limit)
+	if project != "" {
+		q = q.Where("project = ?", project)
+	}
+	if searchType != "" {
+		q = q.Where("search_type = ?", searchType)
+	}
+
+	var entries []SearchQueryLogEntry
+	if err := q.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// PromptMatch is a past user prompt matching a full-text search. Repeated
+// prompts are reported once, with their number of uses.
+type PromptMatch struct {
+	LastUsedAt time.Time `json:"last_used_at"`
+	Prompt     string    `json:"prompt"`
+	Project    string    `json:"project,omitempty"`
+	// Snippet holds the best matching fragments of the prompt, matched words
+	// wrapped in PromptHighlightStart and PromptHighlightStop.
+	Snippet string  `json:"snippet"`
+	Uses    int     `json:"uses"`
+	Rank    float64 `json:"rank"`
+}
+
+// Markers around matched words in PromptMatch.Snippet.
+const (
+	PromptHighlightStart = "**"
+	PromptHighlightStop  = "**"
+)
+
+// promptHeadlineOptions configures ts_headline for PromptMatch.Snippet.
+const promptHeadlineOptions = `StartSel="` + PromptHighlightStart + `", StopSel="` + PromptHighlightStop + `"` +
+	", MaxWords=25, MinWords=8, MaxFragments=2, FragmentDelimiter=\" … \""
+
+// SearchPrompts full-text searches the logged user prompts (search type
+// "prompt") of the tenant on ctx, optionally within project, best match first. query uses web
+// search syntax: words, "quoted phrases", OR and -exclusions.
+func (s *SearchQueryLogStore) SearchPrompts(ctx context.Context, project, query string, limit int) ([]PromptMatch, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+
+	sql := `
+		WITH q AS (SELECT websearch_to_tsquery('english', ?) AS tsq)
+		SELECT l.query AS prompt, l.project,
+			MAX(l.created_at) AS last_used_at,
+			COUNT(*) AS uses,
+			ts_headline('english', l.query, q.tsq, ?) AS snippet,
+			ts_rank(to_tsvector('english', l.query), q.tsq) AS rank
+		FROM search_query_log l, q
+		WHERE l.tenant = ? AND l.search_type = ? AND to_tsvector('english', l.query) @@ q.tsq`
+	args := []any{query, promptHeadlineOptions, tenant.FromContext(ctx), SearchTypePrompt}
+	if project != "" {
+		sql += ` AND l.project = ?`
+		args = append(args, project)
+	}
+	sql += `
+		GROUP BY l.query, l.project, q.tsq
+		ORDER BY rank DESC, last_used_at DESC
+		LIMIT ?`
+	args = append(args, limit)
+
+	var matches []PromptMatch
+	if err := s.db.WithContext(ctx).Raw(sql, args...).Scan(&matches).Error; err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
 // Cleanup deletes entries older than the given duration.
 func (s *SearchQueryLogStore) Cleanup(ctx context.Context, olderThan time.Duration) (int64, error) {
 	cutoff := time.Now().Add(-olderThan)
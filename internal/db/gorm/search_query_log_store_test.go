@@ -0,0 +1,75 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/tenant"
+)
+
+// TestSearchQueryLogStore_SearchPrompts checks that past prompts are matched
+// by full-text search, deduplicated and returned with highlighted snippets.
+func TestSearchQueryLogStore_SearchPrompts(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	const project = "test-search-prompts"
+	defer db.Exec(`DELETE FROM search_query_log WHERE project = ?`, project)
+
+	now := time.Now()
+	for i, e := range []SearchQueryLogEntry{
+		{Query: "describe the deployment process for the staging cluster", SearchType: SearchTypePrompt},
+		{Query: "describe the deployment process for the staging cluster", SearchType: SearchTypePrompt},
+		{Query: "fix the flaky login test", SearchType: SearchTypePrompt},
+		{Query: "deployment checklist", SearchType: "search"},
+	} {
+		e.Project = project
+		e.CreatedAt = now.Add(time.Duration(i) * time.Second)
+		require.NoError(t, db.Create(&e).Error)
+	}
+
+	store := NewSearchQueryLogStore(db)
+	matches, err := store.SearchPrompts(context.Background(), project, "deploying", 10)
+	require.NoError(t, err)
+	require.Len(t, matches, 1, "explicit searches are not prompts; repeats are merged")
+	assert.Equal(t, 2, matches[0].Uses)
+	assert.Contains(t, matches[0].Snippet, PromptHighlightStart+"deployment"+PromptHighlightStop)
+
+	matches, err = store.SearchPrompts(context.Background(), project, "kubernetes", 10)
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+// TestSearchQueryLogStore_TenantScoped checks that prompts logged in one
+// tenant are neither found nor listed from another.
+func TestSearchQueryLogStore_TenantScoped(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	const project = "test-search-prompts-tenant"
+	defer db.Exec(`DELETE FROM search_query_log WHERE project = ?`, project)
+
+	require.NoError(t, db.Create(&SearchQueryLogEntry{
+		Tenant: "acme", Project: project, Query: "rotate the signing keys", SearchType: SearchTypePrompt, CreatedAt: time.Now(),
+	}).Error)
+
+	store := NewSearchQueryLogStore(db)
+	matches, err := store.SearchPrompts(tenant.WithTenant(context.Background(), "acme"), "", "signing keys", 10)
+	require.NoError(t, err)
+	assert.Len(t, matches, 1)
+
+	matches, err = store.SearchPrompts(context.Background(), "", "signing keys", 10)
+	require.NoError(t, err)
+	assert.Empty(t, matches, "default tenant must not see another tenant's prompts")
+
+	since := time.Now().Add(-time.Hour)
+	entries, err := store.ListSince(tenant.WithTenant(context.Background(), "acme"), project, SearchTypePrompt, since, 10)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	entries, err = store.ListSince(context.Background(), project, SearchTypePrompt, since, 10)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "prompt history must not include another tenant's prompts")
+}
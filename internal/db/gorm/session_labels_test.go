@@ -0,0 +1,88 @@
+package gorm
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/tenant"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestMergeSessionTags(t *testing.T) {
+	got := mergeSessionTags([]string{"Refactor", "wip"}, []string{" auth ", "refactor", ""}, []string{"WIP"})
+	assert.Equal(t, models.JSONStringArray{"refactor", "auth"}, got)
+	assert.Equal(t, models.JSONStringArray{}, mergeSessionTags(nil, nil, nil))
+}
+
+// TestSessionStore_LabelSession checks that sessions can be named and tagged
+// by Claude or numeric ID and then listed by tag or name.
+func TestSessionStore_LabelSession(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	const project = "test-session-labels"
+	defer db.Exec(`DELETE FROM sdk_sessions WHERE project = ?`, project)
+
+	ctx := context.Background()
+	store := &SessionStore{db: db}
+	id, err := store.CreateSDKSession(ctx, "label-session-1", project, "")
+	require.NoError(t, err)
+	_, err = store.CreateSDKSession(ctx, "label-session-2", project, "")
+	require.NoError(t, err)
+
+	name := "  The Big Refactor "
+	sess, err := store.LabelSession(ctx, "label-session-1", SessionLabels{Name: &name, AddTags: []string{"Refactor", "auth"}})
+	require.NoError(t, err)
+	assert.Equal(t, &LabeledSession{ID: id, Name: "The Big Refactor", Tags: []string{"refactor", "auth"}}, sess)
+
+	sess, err = store.LabelSession(ctx, strconv.FormatInt(id, 10), SessionLabels{RemoveTags: []string{"auth"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"refactor"}, sess.Tags)
+	assert.Equal(t, "The Big Refactor", sess.Name, "omitted name is left unchanged")
+
+	for _, label := range []string{"refactor", "the big refactor"} {
+		sessions, total, err := store.ListSDKSessions(ctx, project, 10, 0, 0, 0, 0, label)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, total, label)
+		assert.Equal(t, "label-session-1", sessions[0].ClaudeSessionID)
+	}
+
+	_, err = store.LabelSession(ctx, "label-session-missing", SessionLabels{AddTags: []string{"x"}})
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+
+	// Another tenant can neither label the session nor find it by label.
+	acme := tenant.WithTenant(ctx, "acme")
+	_, err = store.LabelSession(acme, strconv.FormatInt(id, 10), SessionLabels{Name: &name})
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+	_, total, err := store.ListSDKSessions(acme, project, 10, 0, 0, 0, 0, "refactor")
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+// TestSessionStore_ListSDKSessionsTenant checks that a tenant lists only its
+// own sessions. check_prior_art and the digests read sessions this way.
+func TestSessionStore_ListSDKSessionsTenant(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	const project = "test-session-tenant"
+	defer db.Exec(`DELETE FROM sdk_sessions WHERE project = ?`, project)
+
+	ctx := context.Background()
+	acme := tenant.WithTenant(ctx, "acme")
+	store := &SessionStore{db: db}
+
+	_, err := store.CreateSDKSession(ctx, "tenant-session-default", project, "default prompt")
+	require.NoError(t, err)
+	_, err = store.CreateSDKSession(acme, "tenant-session-acme", project, "acme prompt")
+	require.NoError(t, err)
+
+	for c, want := range map[context.Context]string{ctx: "tenant-session-default", acme: "tenant-session-acme"} {
+		sessions, total, err := store.ListSDKSessions(c, project, 10, 0, 0, 0, 0, "")
+		require.NoError(t, err)
+		require.EqualValues(t, 1, total)
+		assert.Equal(t, want, sessions[0].ClaudeSessionID)
+	}
+}
@@ -4,14 +4,17 @@ package gorm
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 
+	"github.com/thebtf/engram/internal/tenant"
 	"github.com/thebtf/engram/pkg/models"
 )
 
@@ -43,6 +46,7 @@ func (s *SessionStore) CreateSDKSession(ctx context.Context, claudeSessionID, pr
 			return sql.NullString{String: claudeSessionID, Valid: true}
 		}(),
 		Project: project,
+		Tenant:  tenant.FromContext(ctx),
 		UserPrompt: func() sql.NullString {
 			if userPrompt != "" {
 				return sql.NullString{String: userPrompt, Valid: true}
@@ -79,6 +83,7 @@ func (s *SessionStore) CreateSDKSession(ctx context.Context, claudeSessionID, pr
 			}
 			if err := s.db.WithContext(ctx).
 				Model(&SDKSession{}).
+				Scopes(tenantScope(ctx)).
 				Where("claude_session_id = ?", claudeSessionID).
 				Updates(updates).Error; err != nil {
 				return 0, fmt.Errorf("failed to update session: %w", err)
@@ -127,6 +132,24 @@ func (s *SessionStore) FindAnySDKSession(ctx context.Context, claudeSessionID st
 	return toModelSDKSession(&sess), nil
 }
 
+// LatestActiveSession returns the project's most recently started session
+// that is still active and started at or after sinceEpochMs, or nil when
+// there is none.
+func (s *SessionStore) LatestActiveSession(ctx context.Context, project string, sinceEpochMs int64) (*models.SDKSession, error) {
+	var sess SDKSession
+	err := s.db.WithContext(ctx).
+		Where("project = ? AND status = ? AND started_at_epoch >= ?", project, models.SessionStatusActive, sinceEpochMs).
+		Order("started_at_epoch DESC").
+		First(&sess).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toModelSDKSession(&sess), nil
+}
+
 // ResolveClaudeSessionID resolves a session identifier to its canonical Claude session ID.
 // Accepts either a Claude session ID or a numeric DB ID string.
 func (s *SessionStore) ResolveClaudeSessionID(ctx context.Context, sessionIdentifier string) (string, error) {
@@ -222,13 +245,15 @@ func (s *SessionStore) GetAllProjects(ctx context.Context) ([]string, error) {
 	return projects, err
 }
 
-// ListSDKSessions returns a paginated list of SDK sessions, optionally filtered by project.
+// ListSDKSessions returns a paginated list of the SDK sessions of the tenant on ctx,
+// optionally filtered by project and by label: a non-empty label matches sessions
+// tagged with it or named it (case-insensitive).
 // Results are ordered by started_at DESC (newest first). Returns sessions and total count.
-func (s *SessionStore) ListSDKSessions(ctx context.Context, project string, limit, offset, minPrompts int, from, to int64) ([]*models.SDKSession, int64, error) {
+func (s *SessionStore) ListSDKSessions(ctx context.Context, project string, limit, offset, minPrompts int, from, to int64, label string) ([]*models.SDKSession, int64, error) {
 	var sessions []SDKSession
 	var total int64
 
-	q := s.db.WithContext(ctx).Model(&SDKSession{})
+	q := s.db.WithContext(ctx).Model(&SDKSession{}).Scopes(tenantScope(ctx))
 	if project != "" {
 		q = q.Where("project = ?", project)
 	}
@@ -241,6 +266,13 @@ func (s *SessionStore) ListSDKSessions(ctx context.Context, project string, limi
 	if to > 0 {
 		q = q.Where("started_at_epoch <= ?", to)
 	}
+	if label = NormalizeSessionTag(label); label != "" {
+		tagJSON, err := json.Marshal([]string{label})
+		if err != nil {
+			return nil, 0, err
+		}
+		q = q.Where("(tags @> ?::jsonb OR LOWER(name) = ?)", string(tagJSON), label)
+	}
 
 	if err := q.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -260,6 +292,90 @@ func (s *SessionStore) ListSDKSessions(ctx context.Context, project string, limi
 	return result, total, nil
 }
 
+// SessionLabels is a change to the user-assigned name and tags of a session.
+// A nil Name or Tags leaves the field unchanged; an empty Name clears it and
+// a non-nil Tags replaces the tag set before AddTags and RemoveTags apply.
+type SessionLabels struct {
+	Name       *string
+	Tags       []string
+	AddTags    []string
+	RemoveTags []string
+}
+
+// LabeledSession is what LabelSession returns: the session ID and its labels,
+// without the prompt or other session details.
+type LabeledSession struct {
+	Name string   `json:"name,omitempty"`
+	Tags []string `json:"tags"`
+	ID   int64    `json:"id"`
+}
+
+// NormalizeSessionTag trims and lowercases a session tag.
+func NormalizeSessionTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// LabelSession names and tags the session identified by Claude session ID or
+// numeric DB ID and returns its new labels. Tags are normalized with
+// NormalizeSessionTag and deduplicated. Only sessions of the tenant on ctx
+// can be labeled; returns ErrSessionNotFound when none matches.
+func (s *SessionStore) LabelSession(ctx context.Context, sessionIdentifier string, labels SessionLabels) (*LabeledSession, error) {
+	var updated *SDKSession
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		scoped := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Scopes(tenantScope(ctx)).Session(&gorm.Session{})
+		sess, _, err := resolveSessionForOutcome(scoped, sessionIdentifier)
+		if err != nil {
+			return err
+		}
+		if sess == nil {
+			return fmt.Errorf("%w: %s", ErrSessionNotFound, sessionIdentifier)
+		}
+
+		if labels.Name != nil {
+			name := strings.TrimSpace(*labels.Name)
+			sess.Name = sql.NullString{String: name, Valid: name != ""}
+		}
+		tags := []string(sess.Tags)
+		if labels.Tags != nil {
+			tags = labels.Tags
+		}
+		sess.Tags = mergeSessionTags(tags, labels.AddTags, labels.RemoveTags)
+
+		if err := tx.Model(&SDKSession{}).Where("id = ?", sess.ID).Updates(map[string]any{
+			"name": sess.Name,
+			"tags": sess.Tags,
+		}).Error; err != nil {
+			return err
+		}
+		updated = sess
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &LabeledSession{ID: updated.ID, Name: updated.Name.String, Tags: updated.Tags}, nil
+}
+
+// mergeSessionTags returns tags plus add minus remove, normalized, without
+// duplicates and in first-seen order.
+func mergeSessionTags(tags, add, remove []string) models.JSONStringArray {
+	drop := make(map[string]bool, len(remove))
+	for _, t := range remove {
+		drop[NormalizeSessionTag(t)] = true
+	}
+	seen := make(map[string]bool, len(tags)+len(add))
+	out := models.JSONStringArray{}
+	for _, t := range append(append([]string{}, tags...), add...) {
+		t = NormalizeSessionTag(t)
+		if t == "" || seen[t] || drop[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
+}
+
 // UpdateSessionOutcome records the outcome of a session identified by Claude session ID or numeric DB ID.
 // If a Claude session row does not exist yet, it is auto-created with empty project/user prompt before recording outcome.
 func (s *SessionStore) UpdateSessionOutcome(ctx context.Context, sessionIdentifier, outcome, reason string) error {
@@ -282,6 +398,7 @@ func (s *SessionStore) UpdateSessionOutcome(ctx context.Context, sessionIdentifi
 					Valid:  true,
 				},
 				Project:        "",
+				Tenant:         tenant.FromContext(ctx),
 				UserPrompt:     sql.NullString{Valid: false},
 				Status:         "active",
 				StartedAt:      now.Format(time.RFC3339),
@@ -585,5 +702,7 @@ func toModelSDKSession(sess *SDKSession) *models.SDKSession {
 		OutcomeRecordedAt:   sess.OutcomeRecordedAt,
 		UtilityPropagatedAt: sess.UtilityPropagatedAt,
 		InjectionStrategy:   sess.InjectionStrategy,
+		Name:                sess.Name,
+		Tags:                sess.Tags,
 	}
 }
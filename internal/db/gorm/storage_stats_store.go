@@ -0,0 +1,193 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StorageGrowthWindow is the period the growth figures of StorageStats cover.
+const StorageGrowthWindow = 30 * 24 * time.Hour
+
+// maxStorageTables caps the per-table breakdown to the largest tables.
+const maxStorageTables = 20
+
+// TableStorage is the on-disk size of one table.
+type TableStorage struct {
+	Name string `json:"name"`
+	// Rows is the planner's row estimate (pg_class.reltuples), not a count.
+	Rows       int64 `json:"rows"`
+	TableBytes int64 `json:"table_bytes"`
+	// IndexBytes covers every index of the table, including the GIN
+	// full-text indexes that replaced the vector index in v5.
+	IndexBytes int64 `json:"index_bytes"`
+	TotalBytes int64 `json:"total_bytes"`
+}
+
+// ProjectStorage is the share of one project in the project-scoped tables.
+// Byte counts are the size of the stored rows (pg_column_size), before
+// compression and without index and page overhead.
+type ProjectStorage struct {
+	Project         string `json:"project"`
+	Memories        int64  `json:"memories"`
+	MemoryBytes     int64  `json:"memory_bytes"`
+	Revisions       int64  `json:"revisions"`
+	RevisionBytes   int64  `json:"revision_bytes"`
+	Attachments     int64  `json:"attachments"`
+	AttachmentBytes int64  `json:"attachment_bytes"`
+	Rules           int64  `json:"rules"`
+	RuleBytes       int64  `json:"rule_bytes"`
+	Queries         int64  `json:"queries"`
+	QueryBytes      int64  `json:"query_bytes"`
+	TotalBytes      int64  `json:"total_bytes"`
+	// GrowthBytes is the size of the rows added within StorageGrowthWindow.
+	GrowthBytes       int64   `json:"growth_bytes"`
+	GrowthBytesPerDay float64 `json:"growth_bytes_per_day"`
+}
+
+// StorageStats reports how much space the database uses and where.
+type StorageStats struct {
+	DatabaseBytes   int64            `json:"database_bytes"`
+	AttachmentBytes int64            `json:"attachment_bytes"`
+	Tables          []TableStorage   `json:"tables"`
+	Projects        []ProjectStorage `json:"projects"`
+	// GrowthBytes and GrowthBytesPerDay sum the projects' growth.
+	GrowthBytes       int64   `json:"growth_bytes"`
+	GrowthBytesPerDay float64 `json:"growth_bytes_per_day"`
+	GrowthWindowDays  int     `json:"growth_window_days"`
+}
+
+// StorageStatsStore computes database size statistics.
+type StorageStatsStore struct {
+	db *gorm.DB
+}
+
+// NewStorageStatsStore creates a new StorageStatsStore.
+func NewStorageStatsStore(db *gorm.DB) *StorageStatsStore {
+	return &StorageStatsStore{db: db}
+}
+
+// projectStorageRow is one (project, kind) aggregate of storageByProjectSQL.
+type projectStorageRow struct {
+	Project     string
+	Kind        string
+	Rows        int64
+	Bytes       int64
+	GrowthBytes int64
+}
+
+// storageByProjectSQL aggregates the project-scoped tables per project.
+// Revisions and attachments belong to the project of their memory; global
+// behavioral rules (project IS NULL) are reported under "".
+const storageByProjectSQL = `
+	SELECT m.project, 'memories' AS kind, COUNT(*) AS rows,
+		COALESCE(SUM(pg_column_size(m.*)), 0) AS bytes,
+		COALESCE(SUM(pg_column_size(m.*)) FILTER (WHERE m.created_at >= @since), 0) AS growth_bytes
+	FROM memories m WHERE (@project = '' OR m.project = @project) GROUP BY m.project
+	UNION ALL
+	SELECT m.project, 'revisions', COUNT(*),
+		COALESCE(SUM(pg_column_size(r.*)), 0),
+		COALESCE(SUM(pg_column_size(r.*)) FILTER (WHERE r.created_at >= @since), 0)
+	FROM memory_revisions r JOIN memories m ON m.id = r.memory_id
+	WHERE (@project = '' OR m.project = @project) GROUP BY m.project
+	UNION ALL
+	SELECT m.project, 'attachments', COUNT(*),
+		COALESCE(SUM(pg_column_size(a.*)), 0),
+		COALESCE(SUM(pg_column_size(a.*)) FILTER (WHERE a.created_at >= @since), 0)
+	FROM memory_attachments a JOIN memories m ON m.id = a.memory_id
+	WHERE (@project = '' OR m.project = @project) GROUP BY m.project
+	UNION ALL
+	SELECT COALESCE(b.project, ''), 'rules', COUNT(*),
+		COALESCE(SUM(pg_column_size(b.*)), 0),
+		COALESCE(SUM(pg_column_size(b.*)) FILTER (WHERE b.created_at >= @since), 0)
+	FROM behavioral_rules b WHERE (@project = '' OR b.project = @project) GROUP BY b.project
+	UNION ALL
+	SELECT q.project, 'queries', COUNT(*),
+		COALESCE(SUM(pg_column_size(q.*)), 0),
+		COALESCE(SUM(pg_column_size(q.*)) FILTER (WHERE q.created_at >= @since), 0)
+	FROM search_query_log q WHERE (@project = '' OR q.project = @project) GROUP BY q.project`
+
+// Stats returns the database size, the largest tables and the per-project
+// breakdown, largest project first. A non-empty project limits the breakdown
+// to that project; database and table sizes are always global.
+func (s *StorageStatsStore) Stats(ctx context.Context, project string) (*StorageStats, error) {
+	db := s.db.WithContext(ctx)
+	stats := &StorageStats{GrowthWindowDays: int(StorageGrowthWindow / (24 * time.Hour))}
+
+	if err := db.Raw(`SELECT pg_database_size(current_database())`).Scan(&stats.DatabaseBytes).Error; err != nil {
+		return nil, fmt.Errorf("database size: %w", err)
+	}
+	if err := db.Raw(`
+		SELECT c.relname AS name,
+			GREATEST(c.reltuples, 0)::bigint AS rows,
+			pg_table_size(c.oid) AS table_bytes,
+			pg_indexes_size(c.oid) AS index_bytes,
+			pg_total_relation_size(c.oid) AS total_bytes
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind = 'r' AND n.nspname = current_schema()
+		ORDER BY total_bytes DESC, name
+		LIMIT ?`, maxStorageTables).Scan(&stats.Tables).Error; err != nil {
+		return nil, fmt.Errorf("table sizes: %w", err)
+	}
+	if err := db.Raw(`SELECT COALESCE(SUM(size), 0) FROM memory_attachments`).Scan(&stats.AttachmentBytes).Error; err != nil {
+		return nil, fmt.Errorf("attachment size: %w", err)
+	}
+
+	var rows []projectStorageRow
+	since := time.Now().Add(-StorageGrowthWindow)
+	if err := db.Raw(storageByProjectSQL, map[string]any{"project": project, "since": since}).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("project storage: %w", err)
+	}
+	stats.Projects = aggregateProjectStorage(rows, float64(stats.GrowthWindowDays))
+	for _, p := range stats.Projects {
+		stats.GrowthBytes += p.GrowthBytes
+	}
+	stats.GrowthBytesPerDay = float64(stats.GrowthBytes) / float64(stats.GrowthWindowDays)
+	return stats, nil
+}
+
+// aggregateProjectStorage folds the (project, kind) rows into one entry per
+// project, largest first.
+func aggregateProjectStorage(rows []projectStorageRow, windowDays float64) []ProjectStorage {
+	byProject := make(map[string]*ProjectStorage)
+	for _, row := range rows {
+		p, ok := byProject[row.Project]
+		if !ok {
+			p = &ProjectStorage{Project: row.Project}
+			byProject[row.Project] = p
+		}
+		switch row.Kind {
+		case "memories":
+			p.Memories, p.MemoryBytes = row.Rows, row.Bytes
+		case "revisions":
+			p.Revisions, p.RevisionBytes = row.Rows, row.Bytes
+		case "attachments":
+			p.Attachments, p.AttachmentBytes = row.Rows, row.Bytes
+		case "rules":
+			p.Rules, p.RuleBytes = row.Rows, row.Bytes
+		case "queries":
+			p.Queries, p.QueryBytes = row.Rows, row.Bytes
+		}
+		p.TotalBytes += row.Bytes
+		p.GrowthBytes += row.GrowthBytes
+	}
+
+	out := make([]ProjectStorage, 0, len(byProject))
+	for _, p := range byProject {
+		if windowDays > 0 {
+			p.GrowthBytesPerDay = float64(p.GrowthBytes) / windowDays
+		}
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].TotalBytes != out[j].TotalBytes {
+			return out[i].TotalBytes > out[j].TotalBytes
+		}
+		return out[i].Project < out[j].Project
+	})
+	return out
+}
@@ -0,0 +1,57 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestAggregateProjectStorage(t *testing.T) {
+	projects := aggregateProjectStorage([]projectStorageRow{
+		{Project: "small", Kind: "memories", Rows: 1, Bytes: 100, GrowthBytes: 100},
+		{Project: "big", Kind: "memories", Rows: 10, Bytes: 3000, GrowthBytes: 300},
+		{Project: "big", Kind: "attachments", Rows: 2, Bytes: 1000},
+		{Project: "big", Kind: "queries", Rows: 5, Bytes: 200, GrowthBytes: 200},
+	}, 10)
+	require.Len(t, projects, 2)
+	assert.Equal(t, "big", projects[0].Project, "largest project first")
+	assert.Equal(t, int64(10), projects[0].Memories)
+	assert.Equal(t, int64(2), projects[0].Attachments)
+	assert.Equal(t, int64(4200), projects[0].TotalBytes)
+	assert.Equal(t, int64(500), projects[0].GrowthBytes)
+	assert.Equal(t, 50.0, projects[0].GrowthBytesPerDay)
+}
+
+// TestStorageStatsStore_Stats checks that a project's memories and
+// attachments show up in its breakdown and in the global totals.
+func TestStorageStatsStore_Stats(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	const project = "test-storage-stats"
+	defer db.Exec(`DELETE FROM memories WHERE project = ?`, project)
+
+	store := &Store{DB: db}
+	ctx := context.Background()
+	mem, err := NewMemoryStore(store).Create(ctx, &models.Memory{Project: project, Content: "storage stats fixture"})
+	require.NoError(t, err)
+	require.NoError(t, NewAttachmentStore(store).Create(ctx, mem.ID, []*models.MemoryAttachment{
+		{Kind: models.AttachmentKindCode, Content: "fmt.Println(1)"},
+	}))
+
+	stats, err := NewStorageStatsStore(db).Stats(ctx, project)
+	require.NoError(t, err)
+	assert.Positive(t, stats.DatabaseBytes)
+	assert.GreaterOrEqual(t, stats.AttachmentBytes, int64(14))
+	assert.NotEmpty(t, stats.Tables)
+	require.Len(t, stats.Projects, 1)
+	p := stats.Projects[0]
+	assert.Equal(t, project, p.Project)
+	assert.Equal(t, int64(1), p.Memories)
+	assert.Equal(t, int64(1), p.Attachments)
+	assert.Positive(t, p.MemoryBytes)
+	assert.Equal(t, p.TotalBytes, p.GrowthBytes, "everything was added within the growth window")
+}
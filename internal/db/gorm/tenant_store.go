@@ -0,0 +1,82 @@
+// Package gorm provides GORM-based database operations for engram.
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// TenantStats summarises the data owned by one tenant (migration 106).
+// Tenant is "" for the default tenant.
+type TenantStats struct {
+	Tenant          string `json:"tenant"`
+	Memories        int64  `json:"memories"`
+	BehavioralRules int64  `json:"behavioral_rules"`
+	Credentials     int64  `json:"credentials"`
+	ActiveTokens    int64  `json:"active_tokens"`
+}
+
+// TenantStore provides cross-tenant reporting. Unlike the data stores it does
+// NOT scope by the context tenant — callers must restrict it to admins.
+type TenantStore struct {
+	db *gorm.DB
+}
+
+// NewTenantStore creates a new TenantStore backed by the given Store.
+func NewTenantStore(store *Store) *TenantStore {
+	return &TenantStore{db: store.DB}
+}
+
+// Stats returns per-tenant row counts for every tenant that owns at least one
+// active memory, rule, credential or non-revoked token, ordered by tenant name.
+func (s *TenantStore) Stats(ctx context.Context) ([]TenantStats, error) {
+	byTenant := make(map[string]*TenantStats)
+	get := func(t string) *TenantStats {
+		if st, ok := byTenant[t]; ok {
+			return st
+		}
+		st := &TenantStats{Tenant: t}
+		byTenant[t] = st
+		return st
+	}
+
+	type tenantCount struct {
+		Tenant string
+		Count  int64
+	}
+	counts := []struct {
+		table string
+		where string
+		set   func(*TenantStats, int64)
+	}{
+		{"memories", "deleted_at IS NULL", func(st *TenantStats, n int64) { st.Memories = n }},
+		{"behavioral_rules", "deleted_at IS NULL", func(st *TenantStats, n int64) { st.BehavioralRules = n }},
+		{"credentials", "deleted_at IS NULL", func(st *TenantStats, n int64) { st.Credentials = n }},
+		{"api_tokens", "NOT revoked", func(st *TenantStats, n int64) { st.ActiveTokens = n }},
+	}
+	for _, c := range counts {
+		var rows []tenantCount
+		err := s.db.WithContext(ctx).
+			Table(c.table).
+			Select("tenant, COUNT(*) AS count").
+			Where(c.where).
+			Group("tenant").
+			Scan(&rows).Error
+		if err != nil {
+			return nil, fmt.Errorf("count %s per tenant: %w", c.table, err)
+		}
+		for _, r := range rows {
+			c.set(get(r.Tenant), r.Count)
+		}
+	}
+
+	result := make([]TenantStats, 0, len(byTenant))
+	for _, st := range byTenant {
+		result = append(result, *st)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Tenant < result[j].Tenant })
+	return result, nil
+}
@@ -18,13 +18,15 @@ func NewTokenStore(store *Store) *TokenStore {
 	return &TokenStore{db: store.DB}
 }
 
-// Create stores a new API token record.
-func (s *TokenStore) Create(ctx context.Context, name, tokenHash, tokenPrefix, scope string) (*APIToken, error) {
+// Create stores a new API token record. tenant assigns every request
+// authenticated with the token to that tenant; "" is the default tenant.
+func (s *TokenStore) Create(ctx context.Context, name, tokenHash, tokenPrefix, scope, tenant string) (*APIToken, error) {
 	token := &APIToken{
 		Name:        name,
 		TokenHash:   tokenHash,
 		TokenPrefix: tokenPrefix,
 		Scope:       scope,
+		Tenant:      tenant,
 	}
 
 	if err := s.db.WithContext(ctx).Create(token).Error; err != nil {
@@ -0,0 +1,265 @@
+// Package digest rolls a project's sessions and memories up into weekly and
+// monthly Markdown digests, so a long-running project has a compact
+// narrative of each period instead of hundreds of individual sessions.
+//
+// Digests are stored as memories tagged type:digest (see Period.Tags), which
+// makes them searchable like any other memory.
+package digest
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// Kind is the length of a digest period.
+type Kind string
+
+const (
+	Weekly  Kind = "weekly"
+	Monthly Kind = "monthly"
+)
+
+const (
+	// TypeTag marks a memory as a digest.
+	TypeTag = "type:digest"
+	// DefaultMaxPerSection is the number of items listed per section.
+	DefaultMaxPerSection = 8
+	itemRunes            = 120
+)
+
+// Period is the half-open UTC interval [Start, End) a digest covers: an ISO
+// week starting on Monday, or a calendar month.
+type Period struct {
+	Start time.Time
+	End   time.Time
+	Kind  Kind
+}
+
+// ParsePeriod resolves ref to a period of the given kind. ref may be a week
+// ("2026-W41") or month ("2026-10") label, a date ("2026-10-07", meaning the
+// period containing it), "current" for the period containing now, or empty
+// for the last completed period.
+func ParsePeriod(kind Kind, ref string, now time.Time) (Period, error) {
+	if kind != Weekly && kind != Monthly {
+		return Period{}, fmt.Errorf("period must be %q or %q", Weekly, Monthly)
+	}
+	now = now.UTC()
+	ref = strings.TrimSpace(ref)
+	switch ref {
+	case "":
+		current := containing(kind, now)
+		return containing(kind, current.Start.Add(-time.Nanosecond)), nil
+	case "current":
+		return containing(kind, now), nil
+	}
+	if t, err := time.Parse(time.DateOnly, ref); err == nil {
+		return containing(kind, t), nil
+	}
+	if kind == Monthly {
+		t, err := time.Parse("2006-01", ref)
+		if err != nil {
+			return Period{}, fmt.Errorf("monthly period must be YYYY-MM or a YYYY-MM-DD date, got %q", ref)
+		}
+		return containing(kind, t), nil
+	}
+	year, week, ok := strings.Cut(ref, "-W")
+	y, yErr := strconv.Atoi(year)
+	w, wErr := strconv.Atoi(week)
+	if !ok || yErr != nil || wErr != nil || w < 1 || w > 53 {
+		return Period{}, fmt.Errorf("weekly period must be YYYY-Www or a YYYY-MM-DD date, got %q", ref)
+	}
+	// January 4th is always in ISO week 1.
+	jan4 := time.Date(y, time.January, 4, 0, 0, 0, 0, time.UTC)
+	start := jan4.AddDate(0, 0, -daysSinceMonday(jan4)+(w-1)*7)
+	p := containing(kind, start)
+	if _, got := p.Start.ISOWeek(); got != w {
+		return Period{}, fmt.Errorf("%d has no ISO week %d", y, w)
+	}
+	return p, nil
+}
+
+// containing returns the period of the given kind that contains t.
+func containing(kind Kind, t time.Time) Period {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if kind == Monthly {
+		start := day.AddDate(0, 0, 1-day.Day())
+		return Period{Kind: kind, Start: start, End: start.AddDate(0, 1, 0)}
+	}
+	start := day.AddDate(0, 0, -daysSinceMonday(day))
+	return Period{Kind: kind, Start: start, End: start.AddDate(0, 0, 7)}
+}
+
+func daysSinceMonday(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
+
+// Label names the period: "2026-W41" for a week, "2026-10" for a month.
+func (p Period) Label() string {
+	if p.Kind == Monthly {
+		return p.Start.Format("2006-01")
+	}
+	year, week := p.Start.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// Complete reports whether the period has ended by now. Only complete
+// periods are stored; the current one is rebuilt on every request.
+func (p Period) Complete(now time.Time) bool {
+	return !now.Before(p.End)
+}
+
+// Tags are the tags of the memory that stores the digest of p.
+func (p Period) Tags() []string {
+	return []string{TypeTag, "digest:" + string(p.Kind), "period:" + p.Label()}
+}
+
+// Options controls Build.
+type Options struct {
+	// MaxPerSection caps the items listed per section; the rest are counted.
+	// <= 0 means DefaultMaxPerSection.
+	MaxPerSection int
+}
+
+// section is one heading of the digest. A memory goes into the first
+// section whose types include its type.
+type section struct {
+	title string
+	types []string
+}
+
+var sections = []section{
+	{title: "Decisions", types: []string{"decision"}},
+	{title: "Changes", types: []string{"change", "feature", "refactor"}},
+	{title: "Fixes and gotchas", types: []string{"bugfix", "pitfall"}},
+	{title: "Discoveries", types: []string{"discovery", "guidance", "operational"}},
+}
+
+// Build renders the digest of project for p from the sessions started and
+// the memories created in it. Credentials, earlier digests and private
+// memories are left out: a stored digest is visible to the whole tenant.
+// Callers pass only sessions of the tenant the digest is built for.
+func Build(project string, p Period, sessions []*models.SDKSession, memories []*models.Memory, opts Options) string {
+	perSection := opts.MaxPerSection
+	if perSection <= 0 {
+		perSection = DefaultMaxPerSection
+	}
+
+	grouped := make([][]*models.Memory, len(sections))
+	counted, other := 0, 0
+	for _, mem := range memories {
+		typ := string(models.TypeFromTags(mem.Tags))
+		if typ == "credential" || typ == "digest" || mem.Visibility == models.VisibilityPrivate {
+			continue
+		}
+		counted++
+		placed := false
+		for i, sec := range sections {
+			for _, t := range sec.types {
+				if typ == t {
+					grouped[i] = append(grouped[i], mem)
+					placed = true
+					break
+				}
+			}
+			if placed {
+				break
+			}
+		}
+		if !placed {
+			other++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s — %s digest %s\n\n", project, p.Kind, p.Label())
+	fmt.Fprintf(&b, "%s to %s: %s, %s", p.Start.Format(time.DateOnly), p.End.AddDate(0, 0, -1).Format(time.DateOnly),
+		plural(len(sessions), "session"), plural(counted, "memory"))
+	if other > 0 {
+		fmt.Fprintf(&b, " (%d not listed below)", other)
+	}
+	b.WriteString(". `#id` refers to the memory.\n")
+
+	if len(sessions) > 0 {
+		sorted := append([]*models.SDKSession(nil), sessions...)
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].StartedAtEpoch < sorted[j].StartedAtEpoch })
+		b.WriteString("\n## Sessions\n\n")
+		for _, sess := range sorted[:min(len(sorted), perSection)] {
+			writeSession(&b, sess)
+		}
+		if more := len(sorted) - perSection; more > 0 {
+			fmt.Fprintf(&b, "- …and %d more\n", more)
+		}
+	}
+
+	for i, sec := range sections {
+		mems := grouped[i]
+		if len(mems) == 0 {
+			continue
+		}
+		sort.SliceStable(mems, func(a, c int) bool { return mems[a].CreatedAt.Before(mems[c].CreatedAt) })
+		fmt.Fprintf(&b, "\n## %s\n\n", sec.title)
+		for _, mem := range mems[:min(len(mems), perSection)] {
+			fmt.Fprintf(&b, "- %s _(#%d, %s)_\n", firstLine(mem.Content), mem.ID, mem.CreatedAt.UTC().Format(time.DateOnly))
+		}
+		if more := len(mems) - perSection; more > 0 {
+			fmt.Fprintf(&b, "- …and %d more\n", more)
+		}
+	}
+
+	if len(sessions) == 0 && counted == 0 {
+		b.WriteString("\nNo sessions or memories in this period.\n")
+	}
+	return b.String()
+}
+
+// writeSession writes one session as a list item: its name, or else its
+// first prompt, then its outcome and start date.
+func writeSession(b *strings.Builder, sess *models.SDKSession) {
+	title := "(unnamed session)"
+	if sess.Name.Valid && sess.Name.String != "" {
+		title = "**" + truncate(sess.Name.String, itemRunes) + "**"
+	} else if sess.UserPrompt.Valid && strings.TrimSpace(sess.UserPrompt.String) != "" {
+		title = firstLine(sess.UserPrompt.String)
+	}
+	b.WriteString("- " + title)
+	if sess.Outcome.Valid && sess.Outcome.String != "" {
+		b.WriteString(" — " + sess.Outcome.String)
+	}
+	fmt.Fprintf(b, " _(%s)_\n", time.UnixMilli(sess.StartedAtEpoch).UTC().Format(time.DateOnly))
+}
+
+// firstLine returns the first non-empty line of s with Markdown list and
+// heading markers stripped, truncated.
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*•#>"))
+		if line != "" {
+			return truncate(line, itemRunes)
+		}
+	}
+	return "(empty)"
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	if strings.HasSuffix(noun, "y") {
+		return fmt.Sprintf("%d %sies", n, strings.TrimSuffix(noun, "y"))
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,82 @@
+package digest
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestParsePeriod(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC) // a Friday in 2026-W42
+
+	last, err := ParsePeriod(Weekly, "", now)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-W41", last.Label())
+	assert.Equal(t, time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC), last.Start)
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), last.End)
+	assert.True(t, last.Complete(now))
+
+	current, err := ParsePeriod(Weekly, "current", now)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-W42", current.Label())
+	assert.False(t, current.Complete(now))
+
+	week1, err := ParsePeriod(Weekly, "2026-W01", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC), week1.Start, "ISO week 1 may start in the previous year")
+
+	month, err := ParsePeriod(Monthly, "", now)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-09", month.Label())
+	byDate, err := ParsePeriod(Monthly, "2026-02-14", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), byDate.End)
+	assert.Equal(t, []string{"type:digest", "digest:monthly", "period:2026-02"}, byDate.Tags())
+
+	for kind, ref := range map[Kind]string{Weekly: "2026-W54", Monthly: "October", "daily": ""} {
+		_, err := ParsePeriod(kind, ref, now)
+		assert.Error(t, err, "%s %q", kind, ref)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	p, err := ParsePeriod(Weekly, "2026-W41", time.Now())
+	require.NoError(t, err)
+	day := p.Start.Add(30 * time.Hour)
+
+	sessions := []*models.SDKSession{
+		{StartedAtEpoch: day.Add(time.Hour).UnixMilli(), UserPrompt: sql.NullString{String: "fix the flaky login test\nit fails on CI", Valid: true}},
+		{StartedAtEpoch: day.UnixMilli(), Name: sql.NullString{String: "auth refactor", Valid: true}, Outcome: sql.NullString{String: "success", Valid: true}},
+	}
+	memories := []*models.Memory{
+		{ID: 1, Content: "Use pgx instead of lib/pq", Tags: []string{"type:decision"}, CreatedAt: day},
+		{ID: 2, Content: "## Retry login on 502", Tags: []string{"type:bugfix"}, CreatedAt: day},
+		{ID: 3, Content: "staging key", Tags: []string{"type:credential"}, CreatedAt: day},
+		{ID: 4, Content: "last week's digest", Tags: []string{TypeTag}, CreatedAt: day},
+		{ID: 5, Content: "a timeline entry", Tags: []string{"type:timeline"}, CreatedAt: day},
+		{ID: 6, Content: "my private plan", Tags: []string{"type:decision"}, Visibility: models.VisibilityPrivate, CreatedAt: day},
+	}
+	got := Build("engram", p, sessions, memories, Options{})
+
+	assert.True(t, strings.HasPrefix(got, "# engram — weekly digest 2026-W41\n\n2026-10-05 to 2026-10-11: 2 sessions, 3 memories (1 not listed below)."))
+	assert.Contains(t, got, "## Sessions\n\n- **auth refactor** — success _(2026-10-06)_\n- fix the flaky login test _(2026-10-06)_\n")
+	assert.Contains(t, got, "## Decisions\n\n- Use pgx instead of lib/pq _(#1, 2026-10-06)_\n")
+	assert.Contains(t, got, "## Fixes and gotchas\n\n- Retry login on 502 _(#2, 2026-10-06)_\n")
+	assert.NotContains(t, got, "staging key")
+	assert.NotContains(t, got, "last week's digest")
+	assert.NotContains(t, got, "my private plan")
+}
+
+func TestBuild_Empty(t *testing.T) {
+	p, err := ParsePeriod(Monthly, "2026-09", time.Now())
+	require.NoError(t, err)
+	got := Build("engram", p, nil, nil, Options{})
+	assert.Contains(t, got, "2026-09-01 to 2026-09-30: 0 sessions, 0 memories.")
+	assert.Contains(t, got, "No sessions or memories in this period.")
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/thebtf/engram/internal/auth"
 	"github.com/thebtf/engram/internal/mcp"
+	"github.com/thebtf/engram/internal/tracing"
 	"github.com/thebtf/engram/internal/worker/projectevents"
 	pb "github.com/thebtf/engram/proto/engram/v1"
 )
@@ -75,8 +76,9 @@ func New(handler MCPHandler, validator *auth.Validator) (*grpc.Server, *Server)
 		// SetValidator promotes the server out of bootstrap. Conditional
 		// registration would lock the server into the construction-time
 		// auth state and silently leave RPCs unprotected after a
-		// nil → non-nil swap.
-		grpc.UnaryInterceptor(srv.authInterceptor),
+		// nil → non-nil swap. Tracing runs first so rejected calls are
+		// traced too.
+		grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor, srv.authInterceptor),
 		grpc.StreamInterceptor(srv.streamAuthInterceptor),
 	}
 
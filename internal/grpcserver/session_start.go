@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/thebtf/engram/internal/config"
 	dbgorm "github.com/thebtf/engram/internal/db/gorm"
 	"github.com/thebtf/engram/pkg/models"
 	pb "github.com/thebtf/engram/proto/engram/v1"
@@ -16,14 +17,15 @@ const (
 	defaultSessionStartMemoriesLimit = 20
 	defaultSessionStartIssuesLimit   = 20
 	defaultSessionStartRulesLimit    = 20
+	defaultSessionStartPinnedLimit   = 10
 	maxSessionStartMemoriesLimit     = 200
 	maxSessionStartIssuesLimit       = 200
 	maxSessionStartRulesLimit        = 200
 )
 
 // GetSessionStartContext returns static session-start entities for a project.
-// The payload is SQL-backed only: active issues, behavioral rules, recent memories,
-// plus the timestamp when the response was generated.
+// The payload is SQL-backed only: active issues, behavioral rules, pinned memories
+// followed by recent memories, plus the timestamp when the response was generated.
 func (s *Server) GetSessionStartContext(ctx context.Context, req *pb.GetSessionStartContextRequest) (*pb.GetSessionStartContextResponse, error) {
 	project := req.GetProject()
 	if project == "" {
@@ -66,10 +68,19 @@ func (s *Server) GetSessionStartContext(ctx context.Context, req *pb.GetSessionS
 	}
 
 	memoryStore := dbgorm.NewMemoryStore(&dbgorm.Store{DB: s.db})
-	memoryRows, err := memoryStore.List(ctx, project, memoriesLimit)
+	pinnedLimit := defaultSessionStartPinnedLimit
+	if cfg := config.Get(); cfg != nil && cfg.PinnedInjectLimit > 0 {
+		pinnedLimit = cfg.PinnedInjectLimit
+	}
+	pinnedRows, err := memoryStore.ListPinned(ctx, project, pinnedLimit)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list session-start pinned memories")
+	}
+	recentRows, err := memoryStore.List(ctx, project, memoriesLimit)
 	if err != nil {
 		return nil, status.Error(codes.Internal, "failed to list session-start memories")
 	}
+	memoryRows := withPinnedFirst(pinnedRows, recentRows)
 
 	var ruleRows []dbgorm.BehavioralRule
 	if err := s.db.WithContext(ctx).
@@ -90,6 +101,29 @@ func (s *Server) GetSessionStartContext(ctx context.Context, req *pb.GetSessionS
 	}, nil
 }
 
+// withPinnedFirst returns pinned followed by the recent memories that are not
+// pinned. Pinned memories do not count against the recent memories limit.
+func withPinnedFirst(pinned, recent []*models.Memory) []*models.Memory {
+	if len(pinned) == 0 {
+		return recent
+	}
+	seen := make(map[int64]struct{}, len(pinned))
+	out := make([]*models.Memory, 0, len(pinned)+len(recent))
+	for _, mem := range pinned {
+		seen[mem.ID] = struct{}{}
+		out = append(out, mem)
+	}
+	for _, mem := range recent {
+		if mem == nil {
+			continue
+		}
+		if _, ok := seen[mem.ID]; !ok {
+			out = append(out, mem)
+		}
+	}
+	return out
+}
+
 func mapSessionStartIssues(rows []dbgorm.IssueWithCount) []*pb.SessionStartIssue {
 	issues := make([]*pb.SessionStartIssue, 0, len(rows))
 	for _, row := range rows {
@@ -263,3 +263,16 @@ func TestGetSessionStartContext_DefaultLimits(t *testing.T) {
 	assert.Len(t, resp.Memories, 3)
 	assert.Len(t, resp.Issues, 3)
 }
+
+func TestWithPinnedFirst(t *testing.T) {
+	pinned := []*models.Memory{{ID: 7}, {ID: 3}}
+	recent := []*models.Memory{{ID: 9}, {ID: 7}, nil, {ID: 5}}
+
+	got := withPinnedFirst(pinned, recent)
+	ids := make([]int64, len(got))
+	for i, mem := range got {
+		ids[i] = mem.ID
+	}
+	assert.Equal(t, []int64{7, 3, 9, 5}, ids)
+	assert.Equal(t, recent, withPinnedFirst(nil, recent))
+}
@@ -25,6 +25,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
 	"sync"
 
@@ -45,6 +46,7 @@ var (
 	_ module.ProjectRemovalAware = (*Module)(nil)
 	_ module.Snapshotter         = (*Module)(nil)
 	_ module.ToolProvider        = (*Module)(nil)
+	_ module.HealthReporter      = (*Module)(nil)
 )
 
 const moduleName = "loom"
@@ -55,11 +57,15 @@ const moduleName = "loom"
 // sessions as JSON-RPC notifications, and exposes 4 MCP tools:
 // loom_submit, loom_get, loom_list, loom_cancel.
 type Module struct {
-	engine   loomEngine
-	db       *sql.DB
-	unsub    func()
-	deps     module.ModuleDeps
-	notifier muxcore.Notifier
+	engine loomEngine
+	db     *sql.DB
+	dbPath string
+	// sqliteCfg holds the pragmas tasks.db was opened with, which
+	// HealthIssues checks the database against.
+	sqliteCfg sqliteConfig
+	unsub     func()
+	deps      module.ModuleDeps
+	notifier  muxcore.Notifier
 
 	// engineOverride is non-nil only in tests. When set, Init skips the
 	// sql.Open + NewEngine path and wires this engine directly.
@@ -91,7 +97,8 @@ func NewModuleWithEngine(eng loomEngine) *Module {
 // Name returns the stable module identifier. Implements module.EngramModule.
 func (m *Module) Name() string { return moduleName }
 
-// Init opens tasks.db, applies WAL pragmas, creates the loom engine, subscribes
+// Init opens tasks.db with the configured pragmas (WAL by default, see
+// loadSQLiteConfig), creates the loom engine, subscribes
 // to the event bus, and runs crash recovery. Implements module.EngramModule.
 //
 // If PRAGMA application or engine creation fails, the DB is closed and an error
@@ -108,25 +115,25 @@ func (m *Module) Init(ctx context.Context, deps module.ModuleDeps) error {
 		eng = m.engineOverride
 	} else {
 		dbPath := filepath.Join(deps.StorageDir, "tasks.db")
-		db, err := sql.Open("sqlite", dbPath)
+		cfg, warnings := loadSQLiteConfig(fileSize(dbPath), os.Getenv)
+		for _, w := range warnings {
+			deps.Logger.WarnContext(ctx, "loom: invalid tasks.db setting", "warning", w)
+		}
+		db, err := sql.Open("sqlite", cfg.dsn(dbPath))
 		if err != nil {
 			return fmt.Errorf("loom: open tasks.db: %w", err)
 		}
 
-		// Apply recommended SQLite durability settings before any schema work.
-		pragmas := []string{
-			"PRAGMA journal_mode=WAL",
-			"PRAGMA synchronous=NORMAL",
-			"PRAGMA busy_timeout=5000",
+		// The pragmas run when the first connection opens; open it now so a
+		// bad setting fails Init rather than the first task.
+		if err := db.PingContext(ctx); err != nil {
+			_ = db.Close()
+			return fmt.Errorf("loom: apply tasks.db pragmas: %w", err)
 		}
-		for _, p := range pragmas {
-			if err := ctx.Err(); err != nil {
-				_ = db.Close()
-				return fmt.Errorf("loom: init canceled: %w", err)
-			}
-			if _, err := db.ExecContext(ctx, p); err != nil {
-				_ = db.Close()
-				return fmt.Errorf("loom: %s: %w", p, err)
+		if cfg.AutoVacuum == "incremental" {
+			// Return the pages freed since the last start to the filesystem.
+			if _, err := db.ExecContext(ctx, "PRAGMA incremental_vacuum"); err != nil {
+				deps.Logger.WarnContext(ctx, "loom: incremental vacuum failed", "error", err)
 			}
 		}
 
@@ -140,6 +147,8 @@ func (m *Module) Init(ctx context.Context, deps module.ModuleDeps) error {
 			return fmt.Errorf("loom: create engine: %w", err)
 		}
 		m.db = db
+		m.dbPath = dbPath
+		m.sqliteCfg = cfg
 		eng = loomEng
 	}
 
@@ -201,11 +210,28 @@ func (m *Module) Shutdown(ctx context.Context) error {
 	}
 
 	if m.db != nil {
+		if m.sqliteCfg.JournalMode == "wal" {
+			// Fold the WAL into the database so the next start (or a backup
+			// of the data directory) does not depend on the -wal file.
+			if _, err := m.db.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+				m.deps.Logger.WarnContext(ctx, "loom: final WAL checkpoint failed", "error", err)
+			}
+		}
 		return m.db.Close()
 	}
 	return nil
 }
 
+// HealthIssues reports misconfiguration of tasks.db, such as a journal mode
+// other than the configured one after the file was restored from a backup.
+// Implements module.HealthReporter.
+func (m *Module) HealthIssues(ctx context.Context) []string {
+	if m.db == nil {
+		return nil
+	}
+	return sqliteIssues(ctx, m.db, m.dbPath, m.sqliteCfg)
+}
+
 // -----------------------------------------------------------------------
 // ProjectLifecycle
 // -----------------------------------------------------------------------
@@ -0,0 +1,194 @@
+package loom
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Environment variables tuning tasks.db. Module-specific config comes from
+// env in v0.1.0 (see ModuleDeps.Config); invalid values fall back to the
+// default with a warning rather than failing daemon startup.
+const (
+	envJournalMode       = "ENGRAM_LOOM_SQLITE_JOURNAL_MODE"
+	envSynchronous       = "ENGRAM_LOOM_SQLITE_SYNCHRONOUS"
+	envBusyTimeoutMS     = "ENGRAM_LOOM_SQLITE_BUSY_TIMEOUT_MS"
+	envCacheSizeKB       = "ENGRAM_LOOM_SQLITE_CACHE_SIZE_KB"
+	envMmapSizeMB        = "ENGRAM_LOOM_SQLITE_MMAP_SIZE_MB"
+	envWALAutoCheckpoint = "ENGRAM_LOOM_SQLITE_WAL_AUTOCHECKPOINT"
+	envAutoVacuum        = "ENGRAM_LOOM_SQLITE_AUTO_VACUUM"
+)
+
+const (
+	mib = 1 << 20
+
+	// walSizeWarnBytes is the WAL size above which checkpoints are assumed
+	// to be starved (a reader holding a snapshot, or autocheckpoint off).
+	walSizeWarnBytes = 64 * mib
+	// freelistWarnRatio is the share of free pages above which a database
+	// without auto-vacuum is reported as worth a VACUUM.
+	freelistWarnRatio = 0.25
+)
+
+// sqliteConfig holds the pragmas applied to every tasks.db connection.
+type sqliteConfig struct {
+	JournalMode       string
+	Synchronous       string
+	AutoVacuum        string
+	BusyTimeoutMS     int64
+	CacheSizeKB       int64
+	MmapSizeBytes     int64
+	WALAutoCheckpoint int64
+}
+
+// sizeTier returns the cache and mmap defaults for a database of size bytes:
+// small task stores get a small footprint, large ones enough cache and mmap
+// to keep List scans off the disk.
+func sizeTier(size int64) (cacheKB, mmapBytes int64) {
+	switch {
+	case size < 64*mib:
+		return 8 * 1024, 64 * mib
+	case size < 1024*mib:
+		return 32 * 1024, 256 * mib
+	default:
+		return 64 * 1024, 1024 * mib
+	}
+}
+
+// loadSQLiteConfig resolves the tasks.db pragmas from getenv, with defaults
+// tiered by the current database size. It returns one warning per invalid
+// variable.
+func loadSQLiteConfig(dbSize int64, getenv func(string) string) (sqliteConfig, []string) {
+	cacheKB, mmapBytes := sizeTier(dbSize)
+	cfg := sqliteConfig{
+		JournalMode:       "wal",
+		Synchronous:       "normal",
+		AutoVacuum:        "incremental",
+		BusyTimeoutMS:     5000,
+		CacheSizeKB:       cacheKB,
+		MmapSizeBytes:     mmapBytes,
+		WALAutoCheckpoint: 1000,
+	}
+	var warnings []string
+
+	enum := func(key string, dst *string, allowed ...string) {
+		v := strings.ToLower(strings.TrimSpace(getenv(key)))
+		if v == "" {
+			return
+		}
+		for _, a := range allowed {
+			if v == a {
+				*dst = v
+				return
+			}
+		}
+		warnings = append(warnings, fmt.Sprintf("%s=%q is not one of %s; using %s", key, v, strings.Join(allowed, ", "), *dst))
+	}
+	number := func(key string, dst *int64, scale int64) {
+		v := strings.TrimSpace(getenv(key))
+		if v == "" {
+			return
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			warnings = append(warnings, fmt.Sprintf("%s=%q is not a non-negative integer; using the default", key, v))
+			return
+		}
+		*dst = n * scale
+	}
+
+	enum(envJournalMode, &cfg.JournalMode, "wal", "delete", "truncate", "persist")
+	enum(envSynchronous, &cfg.Synchronous, "off", "normal", "full", "extra")
+	enum(envAutoVacuum, &cfg.AutoVacuum, "none", "full", "incremental")
+	number(envBusyTimeoutMS, &cfg.BusyTimeoutMS, 1)
+	number(envCacheSizeKB, &cfg.CacheSizeKB, 1)
+	number(envMmapSizeMB, &cfg.MmapSizeBytes, mib)
+	number(envWALAutoCheckpoint, &cfg.WALAutoCheckpoint, 1)
+	return cfg, warnings
+}
+
+// dsn returns the tasks.db data source name. The pragmas are passed as
+// _pragma parameters so the driver applies them to every pooled connection,
+// not just the first one. auto_vacuum sorts before journal_mode, so a new
+// database gets it before its first page is written.
+func (c sqliteConfig) dsn(path string) string {
+	q := url.Values{}
+	for _, p := range []string{
+		fmt.Sprintf("busy_timeout(%d)", c.BusyTimeoutMS),
+		fmt.Sprintf("auto_vacuum(%s)", c.AutoVacuum),
+		fmt.Sprintf("journal_mode(%s)", c.JournalMode),
+		fmt.Sprintf("synchronous(%s)", c.Synchronous),
+		// A negative cache_size is in KiB rather than pages.
+		fmt.Sprintf("cache_size(-%d)", c.CacheSizeKB),
+		fmt.Sprintf("mmap_size(%d)", c.MmapSizeBytes),
+		fmt.Sprintf("wal_autocheckpoint(%d)", c.WALAutoCheckpoint),
+	} {
+		q.Add("_pragma", p)
+	}
+	return path + "?" + q.Encode()
+}
+
+// fileSize returns the size of path, 0 when it does not exist yet.
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// autoVacuumNames maps PRAGMA auto_vacuum results to their names.
+var autoVacuumNames = map[int64]string{0: "none", 1: "full", 2: "incremental"}
+
+// synchronousNames maps PRAGMA synchronous results to their names.
+var synchronousNames = map[int64]string{0: "off", 1: "normal", 2: "full", 3: "extra"}
+
+// sqliteIssues compares tasks.db with cfg and reports misconfiguration: a
+// journal mode other than the configured one (typically DELETE after the
+// file was restored or copied from a backup), an auto-vacuum mode that
+// needs a VACUUM to take effect, synchronous=OFF, a WAL file that is not
+// being checkpointed, and a mostly empty file that would shrink on VACUUM.
+func sqliteIssues(ctx context.Context, db *sql.DB, path string, cfg sqliteConfig) []string {
+	var issues []string
+	var journal string
+	var autoVacuum, synchronous, pageCount, freePages int64
+	for _, q := range []struct {
+		pragma string
+		dst    any
+	}{
+		{"journal_mode", &journal},
+		{"auto_vacuum", &autoVacuum},
+		{"synchronous", &synchronous},
+		{"page_count", &pageCount},
+		{"freelist_count", &freePages},
+	} {
+		if err := db.QueryRowContext(ctx, "PRAGMA "+q.pragma).Scan(q.dst); err != nil {
+			return append(issues, fmt.Sprintf("tasks.db: PRAGMA %s failed: %v", q.pragma, err))
+		}
+	}
+
+	if journal = strings.ToLower(journal); journal != cfg.JournalMode {
+		issues = append(issues, fmt.Sprintf("tasks.db: journal_mode is %s, configured %s (restored or copied from a backup?); restart the daemon to reapply it", journal, cfg.JournalMode))
+	}
+	if got := autoVacuumNames[autoVacuum]; got != cfg.AutoVacuum {
+		issues = append(issues, fmt.Sprintf("tasks.db: auto_vacuum is %s, configured %s; it takes effect after a VACUUM", got, cfg.AutoVacuum))
+	}
+	if synchronous == 0 {
+		issues = append(issues, "tasks.db: synchronous is off; a crash or power loss can corrupt the task store")
+	} else if got := synchronousNames[synchronous]; got != cfg.Synchronous {
+		issues = append(issues, fmt.Sprintf("tasks.db: synchronous is %s, configured %s", got, cfg.Synchronous))
+	}
+	if journal == "wal" {
+		if size := fileSize(path + "-wal"); size > walSizeWarnBytes {
+			issues = append(issues, fmt.Sprintf("tasks.db: WAL file is %d MiB; checkpoints are not keeping up (wal_autocheckpoint is %d pages)", size/mib, cfg.WALAutoCheckpoint))
+		}
+	}
+	if autoVacuum == 0 && pageCount > 0 && float64(freePages)/float64(pageCount) > freelistWarnRatio {
+		issues = append(issues, fmt.Sprintf("tasks.db: %d of %d pages are free; a VACUUM would shrink the file", freePages, pageCount))
+	}
+	return issues
+}
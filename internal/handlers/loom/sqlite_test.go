@@ -0,0 +1,108 @@
+package loom
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadSQLiteConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg, warnings := loadSQLiteConfig(0, func(string) string { return "" })
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+	want := sqliteConfig{
+		JournalMode:       "wal",
+		Synchronous:       "normal",
+		AutoVacuum:        "incremental",
+		BusyTimeoutMS:     5000,
+		CacheSizeKB:       8 * 1024,
+		MmapSizeBytes:     64 * mib,
+		WALAutoCheckpoint: 1000,
+	}
+	if cfg != want {
+		t.Errorf("defaults: got %+v, want %+v", cfg, want)
+	}
+
+	if cfg, _ := loadSQLiteConfig(2048*mib, func(string) string { return "" }); cfg.CacheSizeKB != 64*1024 || cfg.MmapSizeBytes != 1024*mib {
+		t.Errorf("large tier: got cache %d KiB, mmap %d", cfg.CacheSizeKB, cfg.MmapSizeBytes)
+	}
+
+	env := map[string]string{
+		envJournalMode: "DELETE",
+		envMmapSizeMB:  "0",
+		envSynchronous: "sometimes",
+		envCacheSizeKB: "-1",
+	}
+	cfg, warnings = loadSQLiteConfig(0, func(k string) string { return env[k] })
+	if cfg.JournalMode != "delete" || cfg.MmapSizeBytes != 0 {
+		t.Errorf("overrides not applied: %+v", cfg)
+	}
+	if cfg.Synchronous != "normal" || cfg.CacheSizeKB != 8*1024 {
+		t.Errorf("invalid values must keep the defaults: %+v", cfg)
+	}
+	if len(warnings) != 2 {
+		t.Errorf("expected 2 warnings, got %v", warnings)
+	}
+}
+
+func TestSQLiteIssues(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tasks.db")
+	cfg, _ := loadSQLiteConfig(0, func(string) string { return "" })
+
+	db, err := sql.Open("sqlite", cfg.dsn(path))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	db.SetMaxOpenConns(2)
+	if _, err := db.ExecContext(ctx, "CREATE TABLE t (x INTEGER)"); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	if issues := sqliteIssues(ctx, db, path, cfg); len(issues) != 0 {
+		t.Errorf("freshly opened database reported issues: %v", issues)
+	}
+	// The pragmas apply to every pooled connection, not just the first.
+	conns := make([]*sql.Conn, 2)
+	for i := range conns {
+		if conns[i], err = db.Conn(ctx); err != nil {
+			t.Fatalf("conn: %v", err)
+		}
+		var timeout int64
+		if err := conns[i].QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&timeout); err != nil || timeout != cfg.BusyTimeoutMS {
+			t.Errorf("connection %d busy_timeout: got %d (%v), want %d", i, timeout, err, cfg.BusyTimeoutMS)
+		}
+	}
+	for _, c := range conns {
+		_ = c.Close()
+	}
+	_ = db.Close()
+
+	// Reopen the file the way a restore from a pre-WAL backup leaves it.
+	restored := cfg
+	restored.JournalMode = "delete"
+	restored.AutoVacuum = "none"
+	db, err = sql.Open("sqlite", restored.dsn(path))
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.ExecContext(ctx, "VACUUM"); err != nil {
+		t.Fatalf("vacuum: %v", err)
+	}
+
+	issues := sqliteIssues(ctx, db, path, cfg)
+	joined := strings.Join(issues, "\n")
+	if !strings.Contains(joined, "journal_mode is delete, configured wal") {
+		t.Errorf("journal mode drift not reported: %v", issues)
+	}
+	if !strings.Contains(joined, "auto_vacuum is none, configured incremental") {
+		t.Errorf("auto_vacuum drift not reported: %v", issues)
+	}
+}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// Digest line limits, in runes.
+const (
+	digestTitleLen = 60
+	digestFactLen  = 80
+)
+
+// formatMemoryDigest renders memories as a compact plain-text block, one line
+// per memory:
+//
+//	#42 decision 3d Use pgx instead of lib/pq | pgx supports COPY and batching
+//
+// The fields are ID, type (from the type: tag, "memory" when untagged), age,
+// title (the first line of the content) and top fact (the next non-empty
+// line), separated by " | " when a fact exists. It is meant for scanning
+// results cheaply and picking IDs to look at in full.
+func formatMemoryDigest(memories []*models.Memory, now time.Time) string {
+	if len(memories) == 0 {
+		return "No memories found."
+	}
+	var sb strings.Builder
+	for _, mem := range memories {
+		title, fact := digestTitleAndFact(mem.Content)
+		fmt.Fprintf(&sb, "#%d %s %s %s", mem.ID, digestType(mem.Tags), digestAge(now.Sub(mem.CreatedAt)), title)
+		if fact != "" {
+			sb.WriteString(" | ")
+			sb.WriteString(fact)
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// digestType returns the value of the first type: tag, or "memory".
+func digestType(tags []string) string {
+	if t := models.TypeFromTags(tags); t != "" {
+		return string(t)
+	}
+	return "memory"
+}
+
+// digestTitleAndFact splits content into its first line (the title) and the
+// next non-empty line (the top fact), with list markers stripped and both
+// truncated for a single-line digest.
+func digestTitleAndFact(content string) (title, fact string) {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*•#>"))
+		if line != "" {
+			lines = append(lines, line)
+			if len(lines) == 2 {
+				break
+			}
+		}
+	}
+	if len(lines) > 0 {
+		title = truncateTitle(lines[0], digestTitleLen)
+	}
+	if len(lines) > 1 {
+		fact = truncateTitle(lines[1], digestFactLen)
+	}
+	return title, fact
+}
+
+// digestAge formats a duration as a short age such as 5m, 3h, 12d, 4mo or 2y.
+func digestAge(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", max(0, int(d/time.Minute)))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	case d < 60*24*time.Hour:
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo", int(d/(30*24*time.Hour)))
+	default:
+		return fmt.Sprintf("%dy", int(d/(365*24*time.Hour)))
+	}
+}
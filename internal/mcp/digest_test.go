@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestFormatMemoryDigest(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	memories := []*models.Memory{
+		{
+			ID:        42,
+			Content:   "Use pgx instead of lib/pq\n\n- pgx supports COPY and batching\n- more detail",
+			Tags:      []string{"scope:project", "type:decision"},
+			CreatedAt: now.Add(-3 * 24 * time.Hour),
+		},
+		{
+			ID:        7,
+			Content:   "Single line note",
+			CreatedAt: now.Add(-90 * time.Minute),
+		},
+	}
+
+	lines := strings.Split(strings.TrimRight(formatMemoryDigest(memories, now), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "#42 decision 3d Use pgx instead of lib/pq | pgx supports COPY and batching", lines[0])
+	assert.Equal(t, "#7 memory 1h Single line note", lines[1])
+
+	assert.Equal(t, "No memories found.", formatMemoryDigest(nil, now))
+}
+
+func TestDigestAge(t *testing.T) {
+	cases := map[time.Duration]string{
+		-time.Minute:             "0m",
+		5 * time.Minute:          "5m",
+		30 * time.Hour:           "30h",
+		10 * 24 * time.Hour:      "10d",
+		120 * 24 * time.Hour:     "4mo",
+		2 * 365 * 24 * time.Hour: "2y",
+	}
+	for d, want := range cases {
+		assert.Equal(t, want, digestAge(d), d.String())
+	}
+}
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// resultFields are the fields recall search results can be shaped to with
+// the fields argument. title, fact and type are derived the way digests
+// derive them; matched_terms and highlight are only set on fuzzy results.
+var resultFields = []string{
+	"id", "project", "title", "fact", "type", "content", "tags", "source_agent",
+	"version", "created_at", "updated_at", "matched_terms", "highlight",
+}
+
+// parseResultFields parses the fields argument: a comma-separated string or
+// an array of field names. It returns nil when v selects nothing, meaning
+// full results. id is always included.
+func parseResultFields(v any) ([]string, error) {
+	var names []string
+	switch v := v.(type) {
+	case nil:
+	case string:
+		names = strings.Split(v, ",")
+	case []any:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("fields must be strings")
+			}
+			names = append(names, s)
+		}
+	default:
+		return nil, fmt.Errorf("fields must be a comma-separated string or an array")
+	}
+
+	var fields []string
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case name == "":
+		case !slices.Contains(resultFields, name):
+			return nil, fmt.Errorf("unknown field %q (valid: %s)", name, strings.Join(resultFields, ", "))
+		case !slices.Contains(fields, name):
+			fields = append(fields, name)
+		}
+	}
+	if len(fields) > 0 && !slices.Contains(fields, "id") {
+		fields = append([]string{"id"}, fields...)
+	}
+	return fields, nil
+}
+
+// shapeMemory returns the selected fields of mem. matched holds the words a
+// fuzzy search matched (nil otherwise).
+func shapeMemory(mem *models.Memory, fields, matched []string) map[string]any {
+	out := make(map[string]any, len(fields))
+	for _, field := range fields {
+		switch field {
+		case "id":
+			out[field] = mem.ID
+		case "project":
+			out[field] = mem.Project
+		case "title":
+			out[field], _ = digestTitleAndFact(mem.Content)
+		case "fact":
+			_, out[field] = digestTitleAndFact(mem.Content)
+		case "type":
+			out[field] = digestType(mem.Tags)
+		case "content":
+			out[field] = mem.Content
+		case "tags":
+			out[field] = mem.Tags
+		case "source_agent":
+			out[field] = mem.SourceAgent
+		case "version":
+			out[field] = mem.Version
+		case "created_at":
+			out[field] = mem.CreatedAt.UTC().Format(time.RFC3339)
+		case "updated_at":
+			out[field] = mem.UpdatedAt.UTC().Format(time.RFC3339)
+		case "matched_terms":
+			if matched != nil {
+				out[field] = matched
+			}
+		case "highlight":
+			if matched != nil {
+				out[field] = highlightMatches(mem.Content, matched)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestParseResultFields(t *testing.T) {
+	fields, err := parseResultFields(nil)
+	require.NoError(t, err)
+	assert.Nil(t, fields)
+
+	fields, err = parseResultFields(" , ")
+	require.NoError(t, err)
+	assert.Nil(t, fields, "an empty selection means full results")
+
+	fields, err = parseResultFields("title, Tags,title")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "title", "tags"}, fields, "id is always included")
+
+	fields, err = parseResultFields([]any{"content", "id"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"content", "id"}, fields)
+
+	_, err = parseResultFields("title,narrative")
+	assert.ErrorContains(t, err, `unknown field "narrative"`)
+	_, err = parseResultFields(42)
+	assert.Error(t, err)
+}
+
+func TestShapeMemory(t *testing.T) {
+	mem := &models.Memory{
+		ID:        7,
+		Project:   "engram",
+		Content:   "Use pgx instead of lib/pq\n- pgx supports COPY",
+		Tags:      []string{"type:decision", "db"},
+		CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	got := shapeMemory(mem, []string{"id", "title", "fact", "type", "created_at", "highlight"}, nil)
+	assert.Equal(t, map[string]any{
+		"id":         int64(7),
+		"title":      "Use pgx instead of lib/pq",
+		"fact":       "pgx supports COPY",
+		"type":       "decision",
+		"created_at": "2026-03-01T12:00:00Z",
+	}, got, "highlight is only set on fuzzy results")
+
+	got = shapeMemory(mem, []string{"id", "matched_terms"}, []string{"pgx"})
+	assert.Equal(t, []string{"pgx"}, got["matched_terms"])
+	assert.NotContains(t, got, "content")
+}
@@ -0,0 +1,205 @@
+package mcp
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+const (
+	// maxFuzzyWords is the longest query, in words, that falls back to
+	// fuzzy matching; longer queries are specific enough to mean what they say.
+	maxFuzzyWords = 3
+	// minFuzzyWordRunes is the shortest query word matched fuzzily; shorter
+	// words would match too much at any distance.
+	minFuzzyWordRunes = 4
+	// highlightContextRunes is how much text a highlight keeps around its
+	// first match.
+	highlightContextRunes = 80
+)
+
+// fuzzyQueryWords returns the words of p's terms when the query qualifies for
+// the fuzzy fallback: at most maxFuzzyWords words, each long enough.
+func fuzzyQueryWords(p SearchParams) []string {
+	var words []string
+	for _, term := range p.Terms {
+		words = append(words, strings.Fields(term)...)
+	}
+	if len(words) == 0 || len(words) > maxFuzzyWords {
+		return nil
+	}
+	for _, w := range words {
+		if utf8.RuneCountInString(w) < minFuzzyWordRunes {
+			return nil
+		}
+	}
+	return words
+}
+
+// maxTypoDistance is the edit distance tolerated for a query word: one typo
+// up to 5 runes, two up to 10, three beyond.
+func maxTypoDistance(word string) int {
+	switch n := utf8.RuneCountInString(word); {
+	case n <= 5:
+		return 1
+	case n <= 10:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// fuzzyMatch reports whether every query word is within its typo distance of
+// a word of mem's content or tags, case-insensitively, and returns the
+// matched words as they appear in the memory.
+func fuzzyMatch(words []string, mem *models.Memory) ([]string, bool) {
+	candidates := memoryWords(mem)
+	matched := make([]string, 0, len(words))
+	for _, w := range words {
+		w = strings.ToLower(w)
+		limit := maxTypoDistance(w)
+		best, bestDist := "", limit+1
+		for _, c := range candidates {
+			if d := levenshtein(w, strings.ToLower(c), limit); d < bestDist {
+				best, bestDist = c, d
+			}
+		}
+		if best == "" {
+			return nil, false
+		}
+		matched = append(matched, best)
+	}
+	return matched, true
+}
+
+// memoryWords returns the distinct identifier-like words of mem's content and
+// tags: runs of letters, digits and underscores.
+func memoryWords(mem *models.Memory) []string {
+	seen := make(map[string]bool)
+	var words []string
+	add := func(text string) {
+		for _, w := range strings.FieldsFunc(text, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+		}) {
+			if !seen[w] {
+				seen[w] = true
+				words = append(words, w)
+			}
+		}
+	}
+	add(mem.Content)
+	for _, tag := range mem.Tags {
+		add(tag)
+	}
+	return words
+}
+
+// levenshtein returns the edit distance between a and b, or limit+1 as soon
+// as it is known to exceed limit.
+func levenshtein(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > limit || -d > limit {
+		return limit + 1
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// highlightMatches returns the content around the first matched word, with
+// every matched word marked as **word**.
+func highlightMatches(content string, matched []string) string {
+	first := -1
+	for _, w := range matched {
+		if i := strings.Index(content, w); i >= 0 && (first < 0 || i < first) {
+			first = i
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+
+	runes := []rune(content)
+	center := utf8.RuneCountInString(content[:first])
+	start, end := max(0, center-highlightContextRunes/2), min(len(runes), center+highlightContextRunes)
+	snippet := strings.Join(strings.Fields(string(runes[start:end])), " ")
+	for _, w := range matched {
+		snippet = replaceWord(snippet, w, "**"+w+"**")
+	}
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(runes) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// replaceWord replaces the whole-word occurrences of w in s.
+func replaceWord(s, w, repl string) string {
+	isWordRune := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' }
+	var b strings.Builder
+	for {
+		i := strings.Index(s, w)
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		before, _ := utf8.DecodeLastRuneInString(s[:i])
+		after, _ := utf8.DecodeRuneInString(s[i+len(w):])
+		b.WriteString(s[:i])
+		if (i > 0 && isWordRune(before)) || (i+len(w) < len(s) && isWordRune(after)) {
+			b.WriteString(w)
+		} else {
+			b.WriteString(repl)
+		}
+		s = s[i+len(w):]
+	}
+}
+
+// fuzzySearch returns up to limit candidates that satisfy p's filters and
+// fuzzily match its terms, with the words each matched.
+func fuzzySearch(p SearchParams, candidates []*models.Memory, limit int) ([]*models.Memory, map[int64][]string) {
+	words := fuzzyQueryWords(p)
+	if words == nil {
+		return nil, nil
+	}
+	filters := p
+	filters.Terms = nil
+	var found []*models.Memory
+	matches := make(map[int64][]string)
+	for _, mem := range candidates {
+		if !filters.Matches(mem) {
+			continue
+		}
+		if matched, ok := fuzzyMatch(words, mem); ok {
+			found = append(found, mem)
+			matches[mem.ID] = matched
+			if len(found) == limit {
+				break
+			}
+		}
+	}
+	return found, matches
+}
@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("cache", "cache", 2))
+	assert.Equal(t, 1, levenshtein("getobservtion", "getobservation", 3))
+	assert.Equal(t, 2, levenshtein("recieve", "receive", 2))
+	assert.Equal(t, 3, levenshtein("auth", "authorization", 2), "stops at limit+1")
+}
+
+func TestFuzzyQueryWords(t *testing.T) {
+	parse := func(q string) SearchParams {
+		p, err := ParseSearchQuery(q)
+		require.NoError(t, err)
+		return p
+	}
+	assert.Equal(t, []string{"GetObservtion"}, fuzzyQueryWords(parse("GetObservtion type:bugfix")))
+	assert.Equal(t, []string{"tokn", "refersh"}, fuzzyQueryWords(parse(`"tokn refersh"`)))
+	assert.Nil(t, fuzzyQueryWords(parse("fix the jwt")), "words under four runes are not fuzzed")
+	assert.Nil(t, fuzzyQueryWords(parse("rotate signing keys every week")), "long queries are not fuzzed")
+	assert.Nil(t, fuzzyQueryWords(parse("type:bugfix")))
+}
+
+func TestFuzzySearch(t *testing.T) {
+	memories := []*models.Memory{
+		{ID: 1, Content: "GetObservation(id) returns ErrNotFound for soft-deleted rows.", Tags: []string{"type:bugfix"}},
+		{ID: 2, Content: "GetObservation is cached for 5 minutes.", Tags: []string{"type:decision"}},
+		{ID: 3, Content: "Unrelated note about deploys."},
+	}
+	p, err := ParseSearchQuery("GetObservtion type:bugfix")
+	require.NoError(t, err)
+	require.Empty(t, filterMatching(p, memories), "the exact search finds nothing")
+
+	found, matches := fuzzySearch(p, memories, 10)
+	require.Len(t, found, 1, "filters still apply")
+	assert.Equal(t, int64(1), found[0].ID)
+	assert.Equal(t, []string{"GetObservation"}, matches[1])
+	assert.Equal(t, "**GetObservation**(id) returns ErrNotFound for soft-deleted rows.",
+		highlightMatches(found[0].Content, matches[1]))
+}
+
+func TestHighlightMatches_WholeWords(t *testing.T) {
+	content := "The session cache is rebuilt on start; the cache warms from cached rows."
+	got := highlightMatches(content, []string{"cache"})
+	assert.Contains(t, got, "session **cache** is")
+	assert.Contains(t, got, "the **cache** warms")
+	assert.Contains(t, got, "cached rows", "only whole words are marked")
+	assert.NotContains(t, got, "**cache**d")
+}
+
+// filterMatching returns the memories p matches exactly.
+func filterMatching(p SearchParams, memories []*models.Memory) []*models.Memory {
+	var out []*models.Memory
+	for _, mem := range memories {
+		if p.Matches(mem) {
+			out = append(out, mem)
+		}
+	}
+	return out
+}
@@ -0,0 +1,169 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/thebtf/engram/internal/auth"
+)
+
+// readOnlyTools are the tools that never modify state. In read-only mode
+// every other tool is rejected. Credential values (get_credential) are
+// withheld: read-only mode is meant for less-trusted agents.
+var readOnlyTools = map[string]bool{
+	"find_by_file":              true,
+	"find_related_observations": true,
+	"find_similar_observations": true,
+	"find_warnings":             true,
+	"check_prior_art":           true,
+	"suggest_related_files":     true,
+	"get_memory_stats":          true,
+	"get_observation_schema":    true,
+	"get_observation_quality":   true,
+	"get_observation_history":   true,
+	"search_sessions":           true,
+	"list_sessions":             true,
+	"list_rules":                true,
+	"recall_memory":             true,
+	"build_topic_map":           true,
+	"generate_project_brief":    true,
+	"get_digest":                true,
+	"check_system_health":       true,
+	"analyze_search_patterns":   true,
+	"analyze_prompt_history":    true,
+	"find_past_prompt":          true,
+	"get_access_heatmap":        true,
+	"list_promotion_candidates": true,
+	"backfill_status":           true,
+	"get_job_status":            true,
+	"get_audit_log":             true,
+	"get_storage_stats":         true,
+	"list_credentials":          true,
+	"vault_list":                true,
+	"vault_status":              true,
+	"list_collections":          true,
+	"list_documents":            true,
+	"get_document":              true,
+	"search_collection":         true,
+	"doc_list_collections":      true,
+	"doc_list_documents":        true,
+	"doc_get":                   true,
+	"doc_search":                true,
+	"doc_read":                  true,
+	"doc_list":                  true,
+	"doc_history":               true,
+}
+
+// readOnlyActions are the read actions of the consolidated tools. A
+// consolidated tool without an entry (store, feedback) is rejected outright.
+var readOnlyActions = map[string][]string{
+	"recall": {"search", "by_file", "related", "reasoning", "sessions"},
+	"docs":   {"read", "list", "history", "collections", "documents", "get_doc", "search_docs"},
+	"admin":  {"stats", "search_analytics", "backfill_status"},
+	"issues": {"list", "get"},
+	"vault":  {"list", "status"},
+}
+
+// defaultActions are the actions consolidated tools assume when none is given.
+var defaultActions = map[string]string{
+	"recall": "search",
+	"issues": "list",
+}
+
+// SetReadOnly switches the server to read-only mode: tools/list advertises
+// only read tools and actions, and calls to anything else are rejected.
+func (s *Server) SetReadOnly(readOnly bool) {
+	s.readOnly = readOnly
+}
+
+// readOnlyReason reports why calls on ctx are restricted to read tools, or ""
+// when they are not. Besides server-wide read-only mode, read-only client
+// keycards are restricted, as they are on the HTTP API.
+func (s *Server) readOnlyReason(ctx context.Context) string {
+	if s.readOnly {
+		return "the MCP server is in read-only mode"
+	}
+	if id, ok := auth.IdentityFrom(ctx); ok && id.Source == auth.SourceClient && id.Role == auth.RoleReadOnly {
+		return "the token is read-only"
+	}
+	return ""
+}
+
+// checkReadOnly returns an error when the call would modify state and ctx is
+// restricted to read tools.
+func (s *Server) checkReadOnly(ctx context.Context, name string, args json.RawMessage) error {
+	reason := s.readOnlyReason(ctx)
+	if reason == "" || readOnlyTools[name] {
+		return nil
+	}
+	allowed, ok := readOnlyActions[name]
+	if !ok {
+		return fmt.Errorf("tool %q is not available: %s", name, reason)
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+	action := coerceString(m["action"], defaultActions[name])
+	if !slices.Contains(allowed, action) {
+		return fmt.Errorf("%s action %q is not available: %s (allowed: %s)", name, action, reason, strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
+// readOnlyToolList keeps the read tools of tools. Consolidated tools are kept
+// with their action enum narrowed to the read actions.
+func readOnlyToolList(tools []Tool) []Tool {
+	kept := make([]Tool, 0, len(tools))
+	for _, t := range tools {
+		if readOnlyTools[t.Name] {
+			kept = append(kept, t)
+			continue
+		}
+		allowed, ok := readOnlyActions[t.Name]
+		if !ok {
+			continue
+		}
+		kept = append(kept, withActionEnum(t, allowed))
+	}
+	return kept
+}
+
+// withActionEnum returns a copy of t whose "action" property lists only actions.
+func withActionEnum(t Tool, actions []string) Tool {
+	props, ok := t.InputSchema["properties"].(map[string]any)
+	if !ok {
+		return t
+	}
+	action, ok := props["action"].(map[string]any)
+	if !ok {
+		return t
+	}
+
+	narrowed := make(map[string]any, len(action))
+	for k, v := range action {
+		narrowed[k] = v
+	}
+	narrowed["enum"] = actions
+	if def, ok := narrowed["default"].(string); ok && !slices.Contains(actions, def) {
+		delete(narrowed, "default")
+	}
+
+	newProps := make(map[string]any, len(props))
+	for k, v := range props {
+		newProps[k] = v
+	}
+	newProps["action"] = narrowed
+
+	schema := make(map[string]any, len(t.InputSchema))
+	for k, v := range t.InputSchema {
+		schema[k] = v
+	}
+	schema["properties"] = newProps
+	t.InputSchema = schema
+	t.Description += " Read-only: " + strings.Join(actions, ", ") + "."
+	return t
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/auth"
+)
+
+func TestReadOnly_ToolsList(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(ServerOptions{Version: "1.0.0"})
+	server.SetReadOnly(true)
+
+	resp := server.handleToolsList(&Request{JSONRPC: "2.0", ID: 1, Method: "tools/list", Params: json.RawMessage(`{"cursor":"all"}`)})
+	require.Nil(t, resp.Error)
+	tools := resp.Result.(map[string]any)["tools"].([]Tool)
+
+	byName := make(map[string]Tool, len(tools))
+	for _, tool := range tools {
+		byName[tool.Name] = tool
+	}
+	for _, name := range []string{"store", "feedback", "store_memory", "edit_observation", "doc_create", "cancel_job", "get_credential"} {
+		assert.NotContains(t, byName, name, "write tool %s listed in read-only mode", name)
+	}
+	for _, name := range []string{"recall", "docs", "check_system_health", "find_similar_observations"} {
+		assert.Contains(t, byName, name)
+	}
+
+	action := byName["docs"].InputSchema["properties"].(map[string]any)["action"].(map[string]any)
+	assert.Equal(t, readOnlyActions["docs"], action["enum"])
+}
+
+func TestReadOnly_ToolsCall(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(ServerOptions{Version: "1.0.0"})
+	server.SetReadOnly(true)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		args    string
+		blocked bool
+	}{
+		{"store", `{"content":"x"}`, true},
+		{"delete_credential", `{"name":"x"}`, true},
+		{"docs", `{"action":"remove","id":"1"}`, true},
+		{"vault", `{"action":"get","name":"x"}`, true},
+		{"recall", `{}`, false},
+		{"docs", `{"action":"read"}`, false},
+		{"get_observation_schema", `{}`, false},
+	}
+	for _, tt := range tests {
+		err := server.checkReadOnly(ctx, tt.name, json.RawMessage(tt.args))
+		if tt.blocked {
+			require.Error(t, err, "%s %s", tt.name, tt.args)
+			assert.Contains(t, err.Error(), "read-only mode")
+		} else {
+			assert.NoError(t, err, "%s %s", tt.name, tt.args)
+		}
+	}
+
+	resp := server.handleToolsCall(ctx, &Request{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"store_memory","arguments":{"content":"x"}}`),
+	})
+	require.NotNil(t, resp.Error)
+	assert.Contains(t, resp.Error.Data, "not available")
+}
+
+func TestReadOnly_ReadOnlyKeycard(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(ServerOptions{Version: "1.0.0"})
+	readOnly := auth.WithIdentity(context.Background(), auth.Identity{Role: auth.RoleReadOnly, Source: auth.SourceClient})
+	readWrite := auth.WithIdentity(context.Background(), auth.Identity{Role: auth.RoleReadWrite, Source: auth.SourceClient})
+
+	err := server.checkReadOnly(readOnly, "store", json.RawMessage(`{}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "token is read-only")
+	assert.NoError(t, server.checkReadOnly(readOnly, "recall", json.RawMessage(`{}`)))
+	assert.NoError(t, server.checkReadOnly(readWrite, "store", json.RawMessage(`{}`)))
+}
@@ -0,0 +1,465 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// SearchParams is a recall search query parsed from the field:value mini-DSL:
+//
+//	type:decision concepts:auth,jwt files:*.go project:api,web language:de session:abc123 session_tag:refactor subagent:reviewer -tag:draft "token refresh" cache
+//
+// Bare words and quoted phrases are content terms. A field may list several
+// comma-separated values, any of which matches; different fields, repeated
+// fields and terms must all match. A leading "-" negates a term or filter.
+type SearchParams struct {
+	// Projects are searched together, e.g. the packages of a monorepo split
+	// into several projects, or related repositories.
+	Projects []string `json:"projects,omitempty"`
+	// IncludeGlobal adds the memories of any project stored with
+	// scope=global. It comes from the include_global argument.
+	IncludeGlobal   bool     `json:"include_global,omitempty"`
+	Agent           string   `json:"agent,omitempty"`
+	Language        string   `json:"language,omitempty"`
+	Terms           []string `json:"terms,omitempty"`
+	ExcludeTerms    []string `json:"exclude_terms,omitempty"`
+	Types           []string `json:"types,omitempty"`
+	ExcludeTypes    []string `json:"exclude_types,omitempty"`
+	Concepts        []string `json:"concepts,omitempty"`
+	ExcludeConcepts []string `json:"exclude_concepts,omitempty"`
+	Files           []string `json:"files,omitempty"`
+	// Subagents restricts results to memories stored by the named subagent
+	// types (tagged subagent:<type> by the post-tool-use hook).
+	Subagents        []string `json:"subagents,omitempty"`
+	ExcludeSubagents []string `json:"exclude_subagents,omitempty"`
+
+	// Session restricts results to memories of one Claude session: those
+	// tagged session:<id>, and those created while it ran when SessionStart
+	// is set.
+	Session string `json:"session,omitempty"`
+	// SessionStart and SessionEnd bound the session's run time. They are
+	// resolved from the session store, not parsed from the query; a zero
+	// SessionEnd means the session is still running.
+	SessionStart time.Time `json:"-"`
+	SessionEnd   time.Time `json:"-"`
+
+	// SessionTag restricts results to memories of the sessions tagged or
+	// named SessionTag (tag_session), matched like Session against each of
+	// TaggedSessions. TaggedSessions is resolved from the session store; when
+	// it is empty, SessionTag matches nothing.
+	SessionTag     string        `json:"session_tag,omitempty"`
+	TaggedSessions []SessionSpan `json:"-"`
+
+	// Cursor is the next_cursor of a previous page; the search continues
+	// after that page's last memory. It comes from the cursor argument, not
+	// from the query text.
+	Cursor string `json:"-"`
+}
+
+// SessionSpan is one session's ID and run time, as used by SearchParams.
+// A zero End means the session is still running.
+type SessionSpan struct {
+	ID    string
+	Start time.Time
+	End   time.Time
+}
+
+// searchFieldAliases maps accepted field names to their canonical field.
+var searchFieldAliases = map[string]string{
+	"type":     "type",
+	"types":    "type",
+	"concept":  "concept",
+	"concepts": "concept",
+	"tag":      "concept",
+	"tags":     "concept",
+	"file":     "file",
+	"files":    "file",
+	"project":  "project",
+	"agent":    "agent",
+	"language": "language",
+	"session":  "session",
+	"subagent": "subagent",
+
+	"session_tag":  "session_tag",
+	"session_name": "session_tag",
+}
+
+// ParseSearchQuery parses a DSL query into SearchParams. A word containing a
+// colon whose prefix is not a known field ("http://...", "ns:key") is kept as
+// a plain term, so ordinary queries parse unchanged.
+func ParseSearchQuery(query string) (SearchParams, error) {
+	var p SearchParams
+	tokens, err := tokenizeSearchQuery(query)
+	if err != nil {
+		return p, err
+	}
+
+	for _, tok := range tokens {
+		negate := false
+		text := tok.text
+		if !tok.quoted && strings.HasPrefix(text, "-") && len(text) > 1 {
+			negate = true
+			text = text[1:]
+		}
+
+		field, value, isField := "", "", false
+		if !tok.quoted {
+			if name, rest, ok := strings.Cut(text, ":"); ok {
+				field, isField = searchFieldAliases[strings.ToLower(name)]
+				value = unquoteSearchValue(rest)
+			}
+		}
+		if !isField {
+			text = unquoteSearchValue(text)
+			if negate {
+				p.ExcludeTerms = append(p.ExcludeTerms, text)
+			} else {
+				p.Terms = append(p.Terms, text)
+			}
+			continue
+		}
+
+		values := splitSearchValues(value)
+		if len(values) == 0 {
+			return p, fmt.Errorf("query filter %q has no value", text)
+		}
+		switch field {
+		case "type":
+			for _, v := range values {
+				if !isValidStoreObservationType(models.ObservationType(strings.ToLower(v))) {
+					return p, fmt.Errorf("unknown observation type %q in query", v)
+				}
+			}
+			if negate {
+				p.ExcludeTypes = append(p.ExcludeTypes, values...)
+			} else {
+				p.Types = append(p.Types, values...)
+			}
+		case "concept":
+			if negate {
+				p.ExcludeConcepts = append(p.ExcludeConcepts, values...)
+			} else {
+				p.Concepts = append(p.Concepts, values...)
+			}
+		case "subagent":
+			if negate {
+				p.ExcludeSubagents = append(p.ExcludeSubagents, values...)
+			} else {
+				p.Subagents = append(p.Subagents, values...)
+			}
+		case "file":
+			if negate {
+				return p, fmt.Errorf("files: filter cannot be negated")
+			}
+			for _, v := range values {
+				if _, err := path.Match(v, ""); err != nil {
+					return p, fmt.Errorf("invalid file pattern %q: %w", v, err)
+				}
+			}
+			p.Files = append(p.Files, values...)
+		case "project":
+			if negate {
+				return p, fmt.Errorf("project: filter cannot be negated")
+			}
+			p.Projects = appendUnique(p.Projects, values...)
+		case "agent", "language", "session", "session_tag":
+			if negate || len(values) > 1 {
+				return p, fmt.Errorf("%s: filter takes a single value", field)
+			}
+			switch field {
+			case "agent":
+				p.Agent = values[0]
+			case "session":
+				p.Session = values[0]
+			case "session_tag":
+				p.SessionTag = values[0]
+			default:
+				if !isLanguageCode(values[0]) {
+					return p, fmt.Errorf("invalid language %q in query: use an ISO 639-1 code such as en or de", values[0])
+				}
+				p.Language = strings.ToLower(values[0])
+			}
+		}
+	}
+	return p, nil
+}
+
+// appendUnique appends the values not already in list.
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		if !slices.Contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// HasFilters reports whether p uses any field filter beyond content terms.
+func (p SearchParams) HasFilters() bool {
+	return len(p.Projects) > 0 || p.IncludeGlobal || p.Agent != "" || p.Language != "" || p.Session != "" || p.SessionTag != "" || len(p.Types) > 0 || len(p.ExcludeTypes) > 0 ||
+		len(p.Concepts) > 0 || len(p.ExcludeConcepts) > 0 || len(p.Files) > 0 || len(p.ExcludeTerms) > 0 ||
+		len(p.Subagents) > 0 || len(p.ExcludeSubagents) > 0
+}
+
+// IsEmpty reports whether p matches every memory.
+func (p SearchParams) IsEmpty() bool {
+	return len(p.Terms) == 0 && !p.HasFilters()
+}
+
+// Matches reports whether mem satisfies every term and filter in p. Projects
+// and IncludeGlobal are not checked here; callers scope the candidate list.
+func (p SearchParams) Matches(mem *models.Memory) bool {
+	contentLower := strings.ToLower(mem.Content)
+	tagsLower := make([]string, len(mem.Tags))
+	for i, tag := range mem.Tags {
+		tagsLower[i] = strings.ToLower(tag)
+	}
+
+	containsTerm := func(term string) bool {
+		term = strings.ToLower(term)
+		if strings.Contains(contentLower, term) {
+			return true
+		}
+		for _, tag := range tagsLower {
+			if strings.Contains(tag, term) {
+				return true
+			}
+		}
+		return false
+	}
+	hasTag := func(values []string, prefix string) bool {
+		for _, v := range values {
+			want := prefix + strings.ToLower(v)
+			for _, tag := range tagsLower {
+				if tag == want {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	for _, term := range p.Terms {
+		if !containsTerm(term) {
+			return false
+		}
+	}
+	for _, term := range p.ExcludeTerms {
+		if containsTerm(term) {
+			return false
+		}
+	}
+	if len(p.Types) > 0 && !hasTag(p.Types, "type:") {
+		return false
+	}
+	if hasTag(p.ExcludeTypes, "type:") {
+		return false
+	}
+	if len(p.Concepts) > 0 && !hasTag(p.Concepts, "") && !hasTag(p.Concepts, "concept:") {
+		return false
+	}
+	if hasTag(p.ExcludeConcepts, "") || hasTag(p.ExcludeConcepts, "concept:") {
+		return false
+	}
+	if len(p.Subagents) > 0 && !hasTag(p.Subagents, "subagent:") {
+		return false
+	}
+	if hasTag(p.ExcludeSubagents, "subagent:") {
+		return false
+	}
+	if p.Agent != "" && !strings.EqualFold(mem.SourceAgent, p.Agent) {
+		return false
+	}
+	if p.Language != "" && mem.Language != p.Language {
+		return false
+	}
+	if p.Session != "" && !(SessionSpan{ID: p.Session, Start: p.SessionStart, End: p.SessionEnd}).contains(mem) {
+		return false
+	}
+	if p.SessionTag != "" && !p.inTaggedSession(mem) {
+		return false
+	}
+	if len(p.Files) > 0 && !matchesFilePattern(p.Files, mem) {
+		return false
+	}
+	return true
+}
+
+// contains reports whether mem is tagged with the session's ID or was
+// created within the session's run time.
+func (sp SessionSpan) contains(mem *models.Memory) bool {
+	for _, tag := range mem.Tags {
+		if id, ok := strings.CutPrefix(tag, "session:"); ok && strings.EqualFold(id, sp.ID) {
+			return true
+		}
+	}
+	if sp.Start.IsZero() || mem.CreatedAt.Before(sp.Start) {
+		return false
+	}
+	return sp.End.IsZero() || !mem.CreatedAt.After(sp.End)
+}
+
+// inTaggedSession reports whether mem belongs to any of p.TaggedSessions.
+func (p SearchParams) inTaggedSession(mem *models.Memory) bool {
+	for _, sp := range p.TaggedSessions {
+		if sp.contains(mem) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesFilePattern reports whether any path mentioned in the memory content
+// or in a "file:" tag matches one of the glob patterns. Patterns without a
+// slash are matched against the base name, so "*.go" finds "internal/x/y.go".
+func matchesFilePattern(patterns []string, mem *models.Memory) bool {
+	var paths []string
+	for _, tag := range mem.Tags {
+		if p, ok := strings.CutPrefix(tag, "file:"); ok {
+			paths = append(paths, p)
+		}
+	}
+	for _, word := range strings.FieldsFunc(mem.Content, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune("`'\"()[]{},;<>", r)
+	}) {
+		word = strings.TrimRight(word, ".:")
+		if strings.ContainsAny(word, "./") {
+			paths = append(paths, word)
+		}
+	}
+
+	for _, pattern := range patterns {
+		for _, p := range paths {
+			target := p
+			if !strings.Contains(pattern, "/") {
+				target = path.Base(p)
+			}
+			if ok, _ := path.Match(pattern, target); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// searchCursor is the position encoded in a search page token: the sort key
+// (created_at) and ID of the last memory returned.
+type searchCursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+type searchCursorToken struct {
+	CreatedAt int64 `json:"c"` // microseconds since the epoch, the precision PostgreSQL stores
+	ID        int64 `json:"i"`
+}
+
+// encodeSearchCursor returns the opaque token for the page that follows mem.
+func encodeSearchCursor(mem *models.Memory) string {
+	data, _ := json.Marshal(searchCursorToken{CreatedAt: mem.CreatedAt.UnixMicro(), ID: mem.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeSearchCursor parses a token made by encodeSearchCursor.
+func decodeSearchCursor(token string) (searchCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return searchCursor{}, fmt.Errorf("invalid cursor")
+	}
+	var tok searchCursorToken
+	if err := json.Unmarshal(data, &tok); err != nil || tok.CreatedAt <= 0 || tok.ID <= 0 {
+		return searchCursor{}, fmt.Errorf("invalid cursor")
+	}
+	return searchCursor{CreatedAt: time.UnixMicro(tok.CreatedAt).UTC(), ID: tok.ID}, nil
+}
+
+type searchToken struct {
+	text   string
+	quoted bool
+}
+
+// tokenizeSearchQuery splits on whitespace, keeping double-quoted phrases
+// (including quoted filter values such as concepts:"rate limit") together.
+func tokenizeSearchQuery(query string) ([]searchToken, error) {
+	var (
+		tokens  []searchToken
+		cur     strings.Builder
+		inQuote bool
+		quoted  bool // current token is a bare quoted phrase
+	)
+	flush := func() {
+		if cur.Len() > 0 || quoted {
+			text := cur.String()
+			if quoted {
+				text = strings.TrimSpace(text)
+			}
+			if text != "" {
+				tokens = append(tokens, searchToken{text: text, quoted: quoted})
+			}
+		}
+		cur.Reset()
+		quoted = false
+	}
+
+	for _, r := range query {
+		switch {
+		case r == '"':
+			if !inQuote && cur.Len() == 0 {
+				quoted = true // phrase; quotes are dropped
+			} else if !quoted {
+				cur.WriteRune(r) // quoted filter value; unquoted later
+			}
+			inQuote = !inQuote
+			if !inQuote && quoted {
+				flush()
+			}
+		case unicode.IsSpace(r) && !inQuote:
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quote in query")
+	}
+	flush()
+	return tokens, nil
+}
+
+// isLanguageCode reports whether v is a two-letter ISO 639-1 code.
+func isLanguageCode(v string) bool {
+	if len(v) != 2 {
+		return false
+	}
+	for _, r := range v {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func unquoteSearchValue(v string) string {
+	if len(v) >= 2 && strings.HasPrefix(v, `"`) && strings.HasSuffix(v, `"`) {
+		return v[1 : len(v)-1]
+	}
+	return v
+}
+
+func splitSearchValues(v string) []string {
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
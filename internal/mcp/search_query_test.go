@@ -0,0 +1,232 @@
+package mcp
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  SearchParams
+	}{
+		{
+			name:  "plain words",
+			query: "token refresh",
+			want:  SearchParams{Terms: []string{"token", "refresh"}},
+		},
+		{
+			name:  "fields and phrase",
+			query: `type:decision concepts:auth files:*.go "token refresh"`,
+			want: SearchParams{
+				Types:    []string{"decision"},
+				Concepts: []string{"auth"},
+				Files:    []string{"*.go"},
+				Terms:    []string{"token refresh"},
+			},
+		},
+		{
+			name:  "comma values and negation",
+			query: `type:bugfix,pitfall -tag:draft -flaky agent:codex project:engram`,
+			want: SearchParams{
+				Types:           []string{"bugfix", "pitfall"},
+				ExcludeConcepts: []string{"draft"},
+				ExcludeTerms:    []string{"flaky"},
+				Agent:           "codex",
+				Projects:        []string{"engram"},
+			},
+		},
+		{
+			name:  "quoted filter value",
+			query: `concepts:"rate limit" -"not this"`,
+			want: SearchParams{
+				Concepts:     []string{"rate limit"},
+				ExcludeTerms: []string{"not this"},
+			},
+		},
+		{
+			name:  "language filter",
+			query: "language:DE cache",
+			want:  SearchParams{Language: "de", Terms: []string{"cache"}},
+		},
+		{
+			name:  "session filter",
+			query: "session:abc-123 retry",
+			want:  SearchParams{Session: "abc-123", Terms: []string{"retry"}},
+		},
+		{
+			name:  "session tag filter",
+			query: `session_tag:"big refactor" retry`,
+			want:  SearchParams{SessionTag: "big refactor", Terms: []string{"retry"}},
+		},
+		{
+			name:  "subagent filter",
+			query: "subagent:code-reviewer,explore -subagent:general-purpose",
+			want: SearchParams{
+				Subagents:        []string{"code-reviewer", "explore"},
+				ExcludeSubagents: []string{"general-purpose"},
+			},
+		},
+		{
+			name:  "several projects",
+			query: "project:api,web project:docs,api retry",
+			want:  SearchParams{Projects: []string{"api", "web", "docs"}, Terms: []string{"retry"}},
+		},
+		{
+			name:  "unknown field stays a term",
+			query: "http://localhost:37777 ns:key",
+			want:  SearchParams{Terms: []string{"http://localhost:37777", "ns:key"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSearchQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseSearchQuery(%q): %v", tt.query, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseSearchQuery(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSearchQuery_Errors(t *testing.T) {
+	for _, query := range []string{
+		`"unterminated`,
+		"type:insight",
+		"type:",
+		"-files:*.go",
+		"-project:a",
+		"agent:a,b",
+		"files:[",
+		"language:german",
+		"-language:de",
+	} {
+		if _, err := ParseSearchQuery(query); err == nil {
+			t.Errorf("ParseSearchQuery(%q) succeeded, want error", query)
+		}
+	}
+}
+
+func TestSearchParams_Matches(t *testing.T) {
+	mem := &models.Memory{
+		Content:     "Token refresh moved to internal/auth/refresh.go; see `Refresh()`.",
+		Tags:        []string{"type:decision", "auth", "scope:project", "subagent:code-reviewer"},
+		SourceAgent: "codex",
+		Language:    "en",
+	}
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"token refresh", true},
+		{`"refresh moved"`, true},
+		{"type:decision", true},
+		{"type:bugfix", false},
+		{"type:bugfix,decision", true},
+		{"-type:decision", false},
+		{"concepts:auth", true},
+		{"concepts:billing", false},
+		{"-tag:draft", true},
+		{"files:*.go", true},
+		{"files:*.ts", false},
+		{"files:internal/auth/*.go", true},
+		{"agent:codex", true},
+		{"agent:gemini", false},
+		{"language:en", true},
+		{"lang:go", false},
+		{"language:de", false},
+		{"token -refresh", false},
+		{"subagent:code-reviewer", true},
+		{"subagent:Explore", false},
+		{"-subagent:code-reviewer", false},
+		{"type:decision concepts:auth files:*.go \"token refresh\"", true},
+	}
+	for _, tt := range tests {
+		params, err := ParseSearchQuery(tt.query)
+		if err != nil {
+			t.Fatalf("ParseSearchQuery(%q): %v", tt.query, err)
+		}
+		if got := params.Matches(mem); got != tt.want {
+			t.Errorf("Matches(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearchParams_MatchesSession(t *testing.T) {
+	start := time.Date(2026, 5, 1, 14, 0, 0, 0, time.UTC)
+	tagged := &models.Memory{Content: "session started", Tags: []string{"session:ABC"}, CreatedAt: start.Add(-time.Hour)}
+	during := &models.Memory{Content: "retry fix", CreatedAt: start.Add(30 * time.Minute)}
+	after := &models.Memory{Content: "later", CreatedAt: start.Add(3 * time.Hour)}
+
+	tagOnly := SearchParams{Session: "abc"}
+	if !tagOnly.Matches(tagged) || tagOnly.Matches(during) {
+		t.Errorf("without a session window only session-tagged memories match")
+	}
+
+	window := SearchParams{Session: "abc", SessionStart: start, SessionEnd: start.Add(time.Hour)}
+	for _, tt := range []struct {
+		mem  *models.Memory
+		want bool
+	}{{tagged, true}, {during, true}, {after, false}} {
+		if got := window.Matches(tt.mem); got != tt.want {
+			t.Errorf("Matches(%q) = %v, want %v", tt.mem.Content, got, tt.want)
+		}
+	}
+
+	running := SearchParams{Session: "abc", SessionStart: start}
+	if !running.Matches(after) {
+		t.Errorf("a running session has no end bound")
+	}
+}
+
+func TestSearchParams_MatchesSessionTag(t *testing.T) {
+	start := time.Date(2026, 5, 1, 14, 0, 0, 0, time.UTC)
+	tagged := &models.Memory{Content: "session started", Tags: []string{"session:ABC"}, CreatedAt: start.Add(-time.Hour)}
+	first := &models.Memory{Content: "retry fix", CreatedAt: start.Add(30 * time.Minute)}
+	second := &models.Memory{Content: "cache fix", CreatedAt: start.Add(25 * time.Hour)}
+	between := &models.Memory{Content: "unrelated", CreatedAt: start.Add(5 * time.Hour)}
+
+	p := SearchParams{SessionTag: "refactor", TaggedSessions: []SessionSpan{
+		{ID: "abc", Start: start, End: start.Add(time.Hour)},
+		{ID: "def", Start: start.Add(24 * time.Hour)},
+	}}
+	for _, tt := range []struct {
+		mem  *models.Memory
+		want bool
+	}{{tagged, true}, {first, true}, {second, true}, {between, false}} {
+		if got := p.Matches(tt.mem); got != tt.want {
+			t.Errorf("Matches(%q) = %v, want %v", tt.mem.Content, got, tt.want)
+		}
+	}
+
+	if (SearchParams{SessionTag: "refactor"}).Matches(first) {
+		t.Errorf("a session tag without tagged sessions matches nothing")
+	}
+}
+
+func TestSearchCursorRoundTrip(t *testing.T) {
+	mem := &models.Memory{ID: 42, CreatedAt: time.Date(2026, 3, 1, 12, 30, 0, 123456000, time.UTC)}
+	token := encodeSearchCursor(mem)
+
+	got, err := decodeSearchCursor(token)
+	if err != nil {
+		t.Fatalf("decodeSearchCursor(%q): %v", token, err)
+	}
+	if got.ID != 42 || !got.CreatedAt.Equal(mem.CreatedAt) {
+		t.Errorf("decodeSearchCursor = %+v, want id 42 at %v", got, mem.CreatedAt)
+	}
+
+	for _, bad := range []string{"", "not base64!", "bnVsbA", "eyJjIjowLCJpIjowfQ"} {
+		if _, err := decodeSearchCursor(bad); err == nil {
+			t.Errorf("decodeSearchCursor(%q) succeeded, want error", bad)
+		}
+	}
+}
@@ -14,6 +14,9 @@ import (
 	"sync"
 
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
 	"github.com/thebtf/engram/internal/chunking"
 	"github.com/thebtf/engram/internal/collections"
 	"github.com/thebtf/engram/internal/config"
@@ -21,6 +24,8 @@ import (
 	gorm "github.com/thebtf/engram/internal/db/gorm"
 	"github.com/thebtf/engram/internal/privacy"
 	"github.com/thebtf/engram/internal/sessions"
+	"github.com/thebtf/engram/internal/tracing"
+	"github.com/thebtf/engram/internal/worker/jobs"
 )
 
 // Server is the MCP server that exposes engram tools.
@@ -31,6 +36,7 @@ type Server struct {
 	relationStore          *gorm.RelationStore
 	sessionStore           *gorm.SessionStore
 	injectionStore         *gorm.InjectionStore
+	experimentStore        *gorm.ExperimentStore
 	collectionRegistry     *collections.Registry
 	sessionIdxStore        *sessions.Store
 	documentStore          *gorm.DocumentStore
@@ -44,7 +50,15 @@ type Server struct {
 	vaultInitErr           error
 	vaultOnce              sync.Once
 	backfillStatusFunc     func() (any, error)
+	jobManager             *jobs.Manager
+	maintenanceFunc        jobs.Func
+	auditLogStore          *gorm.AuditLogStore
+	storageStatsStore      *gorm.StorageStatsStore
+	searchQueryLogStore    *gorm.SearchQueryLogStore
+	memoryAccessStore      *gorm.MemoryAccessStore
+	attachmentStore        *gorm.AttachmentStore
 	version                string
+	readOnly               bool
 }
 
 // ServerOptions holds the dependencies injected into the MCP Server.
@@ -78,6 +92,12 @@ func (s *Server) SetInjectionStore(is *gorm.InjectionStore) {
 	s.injectionStore = is
 }
 
+// SetExperimentStore sets the injection experiment log, so memory ratings
+// given with a session_id count toward the session's experiment arm.
+func (s *Server) SetExperimentStore(es *gorm.ExperimentStore) {
+	s.experimentStore = es
+}
+
 // SetBackfillStatusFunc sets the function to retrieve backfill run status.
 func (s *Server) SetBackfillStatusFunc(fn func() (any, error)) {
 	s.backfillStatusFunc = fn
@@ -376,7 +396,7 @@ Engram is your permanent memory store. Memories saved here persist across ALL se
 | ` + "`issues`" + ` | **Cross-project issue tracking** between agents | create, list, get, update, comment, reopen |
 | ` + "`vault`" + ` | **Credentials** — encrypted AES-256-GCM | store, get, list, delete, status |
 | ` + "`docs`" + ` | **Documents** — versioned docs & collections | create, read, list, history, comment, collections, documents, get_doc, remove, ingest, search_docs |
-| ` + "`admin`" + ` | **Bulk ops**, analytics | bulk_delete, bulk_supersede, tag, stats, trends, quality, export, ... |
+| ` + "`admin`" + ` | **Bulk ops**, analytics | stats, search_analytics, backfill_status, maintenance, bulk_delete (by filter; dry run first), migrate_project (old ID → new ID) |
 | ` + "`check_system_health`" + ` | **Health** check of all subsystems | (no params) |
 
 ## Issues — Cross-Project Agent Bug Tracker
@@ -486,12 +506,20 @@ func (s *Server) primaryTools() []Tool {
 				"type": "object",
 				"properties": map[string]any{
 					"action":         map[string]any{"type": "string", "enum": []string{"search", "by_file", "related", "reasoning"}, "default": "search", "description": "Action to perform"},
-					"query":          map[string]any{"type": "string", "description": "Search query / substring filter (for search)"},
+					"query":          map[string]any{"type": "string", "description": "Search query (for search). Words and \"quoted phrases\" must all appear (a short query that matches nothing is retried typo-tolerantly, e.g. GetObservtion finds GetObservation; such results carry matched_terms and a highlight); filters: type:decision,bugfix concepts:auth files:*.go agent:codex project:api,web language:de session:id session_tag:refactor subagent:code-reviewer (memories stored by that subagent type); prefix - to exclude (e.g. -tag:draft)"},
 					"files":          map[string]any{"type": "string", "description": "File paths (for action=by_file)"},
 					"id":             map[string]any{"type": "number", "description": "Observation ID (for action=related)"},
-					"project":        map[string]any{"type": "string", "description": "Project name filter"},
+					"project":        map[string]any{"type": []string{"string", "array"}, "items": map[string]any{"type": "string"}, "description": "Project name filter. For search, several projects as an array or comma-separated list are searched together"},
+					"include_global": map[string]any{"type": "boolean", "default": false, "description": "Also search memories stored with scope=global in any project (for search)"},
 					"limit":          map[string]any{"type": "number", "description": "Max results"},
+					"session_id":     map[string]any{"type": "string", "description": "Claude session ID (for search): only memories tagged with the session or stored while it ran; the session's project is used when project is omitted"},
+					"session_tag":    map[string]any{"type": "string", "description": "Session tag or name (for search): only memories tagged with, or stored during, sessions labelled with it via tag_session"},
+					"as_of":          map[string]any{"type": "string", "description": "RFC 3339 timestamp or YYYY-MM-DD date (for search): search memory as it was then — memories created later are excluded, deleted or merged ones are included if still live then, and edited ones show their content at that time. Without a query this lists the recent memories as of that date"},
+					"cursor":         map[string]any{"type": "string", "description": "next_cursor from a previous search page (for search): continues after its last result; memories stored since do not shift pages"},
 					"min_confidence": map[string]any{"type": "number", "description": "Min confidence 0-1 (for action=related)"},
+					"format":         map[string]any{"type": "string", "enum": []string{"json", "digest"}, "default": "json", "description": "Result format (for search): digest returns one plain-text line per result (id, type, age, title, top fact) to save context"},
+					"fields":         map[string]any{"type": "string", "description": "Comma-separated result fields (for search, json format), e.g. \"id,title,tags\": id, project, title, fact, type, content, tags, source_agent, version, created_at, updated_at, matched_terms, highlight. id is always included; omit for full results"},
+					"type":           map[string]any{"type": "string", "enum": []string{"memories", "prompts"}, "default": "memories", "description": "What to search (for search): prompts full-text searches past user prompts and returns highlighted snippets (see find_past_prompt)"},
 				},
 			},
 		},
@@ -506,13 +534,15 @@ func (s *Server) primaryTools() []Tool {
 					"content":       map[string]any{"type": "string", "description": "Observation content (for create)"},
 					"title":         map[string]any{"type": "string", "description": "Title (for create, edit)"},
 					"id":            map[string]any{"type": "number", "description": "Observation ID (for edit)"},
-					"source_id":     map[string]any{"type": "number", "description": "Source observation ID (for merge)"},
-					"target_id":     map[string]any{"type": "number", "description": "Target observation ID (for merge)"},
+					"edited_by":     map[string]any{"type": "string", "description": "Actor recorded on the revision (for edit, merge)"},
+					"source_id":     map[string]any{"type": "number", "description": "Memory merged away (for merge): its new facts and tags move to target_id, then it is deleted"},
+					"target_id":     map[string]any{"type": "number", "description": "Memory that absorbs source_id (for merge); keeps its type and scope"},
 					"type":          map[string]any{"type": "string", "enum": []string{"decision", "bugfix", "feature", "refactor", "discovery", "change", "guidance", "credential", "entity", "wiki", "pitfall", "operational", "timeline"}, "description": "Observation type (for create). Must be an observation type, not a memory_type value like insight/context/pattern."},
 					"tags":          map[string]any{"type": "string", "description": "Comma-separated tags (for create)"},
 					"scope":         map[string]any{"type": "string", "description": "Scope: project/global/agent (for create)"},
 					"always_inject": map[string]any{"type": "boolean", "description": "Always inject in context (for create, edit)"},
-					"narrative":     map[string]any{"type": "string", "description": "Narrative text (for edit)"},
+					"fields":        map[string]any{"type": "object", "description": "Schema sections by name for typed observations (for create; see get_observation_schema)"},
+					"narrative":     map[string]any{"type": "string", "description": "Narrative text (for edit; alias of content)"},
 					"path":          map[string]any{"type": "string", "description": "File path (for import)"},
 					"project":       map[string]any{"type": "string", "description": "Project name"},
 				},
@@ -529,7 +559,7 @@ func (s *Server) primaryTools() []Tool {
 					"action":     map[string]any{"type": "string", "enum": []string{"rate", "suppress", "outcome"}, "description": "Action to perform (required)"},
 					"id":         map[string]any{"type": "number", "description": "Observation ID (for rate, suppress)"},
 					"rating":     map[string]any{"type": "string", "enum": []string{"useful", "not_useful"}, "description": "Rating value for action=rate"},
-					"session_id": map[string]any{"type": "string", "description": "Claude session ID string (required for action=outcome)"},
+					"session_id": map[string]any{"type": "string", "description": "Claude session ID string (required for action=outcome; for action=rate, attributes the rating to the session's injection experiment arm)"},
 					"outcome":    map[string]any{"type": "string", "enum": []string{"success", "partial", "failure", "abandoned"}, "description": "Session outcome (for action=outcome)"},
 					"reason":     map[string]any{"type": "string", "description": "Outcome reason (for action=outcome)"},
 				},
@@ -580,9 +610,16 @@ func (s *Server) primaryTools() []Tool {
 				"type":     "object",
 				"required": []string{"action"},
 				"properties": map[string]any{
-					"action":  map[string]any{"type": "string", "description": "Action to perform (required). See tool description for valid actions."},
-					"project": map[string]any{"type": "string", "description": "Project name (for stats, search_analytics)"},
-					"days":    map[string]any{"type": "number", "description": "Days to analyze (for search_analytics)"},
+					"action":        map[string]any{"type": "string", "description": "Action to perform (required). See tool description for valid actions."},
+					"project":       map[string]any{"type": "string", "description": "Project name (for stats, search_analytics; required for bulk_delete)"},
+					"days":          map[string]any{"type": "number", "description": "Days to analyze (for search_analytics)"},
+					"type":          map[string]any{"type": "string", "description": "Only memories of this type, e.g. change (for bulk_delete)"},
+					"tags":          map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Only memories carrying all of these tags/concepts (for bulk_delete)"},
+					"since":         map[string]any{"type": "string", "description": "Only memories created at or after this RFC 3339 time or YYYY-MM-DD date (for bulk_delete)"},
+					"until":         map[string]any{"type": "string", "description": "Only memories created before this RFC 3339 time or YYYY-MM-DD date (for bulk_delete)"},
+					"confirm_token": map[string]any{"type": "string", "description": "Token from a bulk_delete dry run; omit it to preview what the filter matches"},
+					"old_project":   map[string]any{"type": "string", "description": "Project ID to move data from (for migrate_project), e.g. the ID a directory had before it was moved"},
+					"new_project":   map[string]any{"type": "string", "description": "Project ID to move data to (for migrate_project; defaults to the session's project)"},
 				},
 			},
 		},
@@ -673,6 +710,31 @@ func (s *Server) handleToolsList(req *Request) *Response {
 				"properties": map[string]any{},
 			},
 		},
+		{
+			Name:        "get_observation_schema",
+			Description: "Show the structure expected of typed observations (e.g. decision requires \"Alternatives considered\", bugfix requires \"Root cause\"). store_memory rejects explicitly typed observations missing required sections.",
+			tier:        tierUseful,
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"type": map[string]any{"type": "string", "description": "Observation type (omit to list every schema)"},
+				},
+			},
+		},
+		{
+			Name:        "get_observation_quality",
+			Description: "Score a stored memory (id) or a draft (content + type) 0-1 for completeness: schema sections, length, specificity and tags, with suggestions.",
+			tier:        tierUseful,
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"id":      map[string]any{"type": "number", "description": "Memory ID to score"},
+					"content": map[string]any{"type": "string", "description": "Draft content to score (when id is omitted)"},
+					"type":    map[string]any{"type": "string", "description": "Observation type (default: the memory's type: tag)"},
+					"tags":    map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Draft tags"},
+				},
+			},
+		},
 		{
 			Name:        "search_sessions",
 			Description: "[DEPRECATED] Full-text search across indexed Claude Code sessions. Removed in v5 (US3) — indexed_sessions table dropped.",
@@ -761,6 +823,219 @@ func (s *Server) handleToolsList(req *Request) *Response {
 		)
 	}
 
+	// Memory history tools — only advertise when the memory store is wired.
+	if s.memoryStore != nil {
+		tools = append(tools,
+			Tool{
+				Name:        "edit_observation",
+				Description: "Edit a memory's content and/or tags. The previous version is kept in its revision history.",
+				tier:        tierUseful,
+				InputSchema: map[string]any{
+					"type":     "object",
+					"required": []string{"id"},
+					"properties": map[string]any{
+						"id":        map[string]any{"type": "number", "description": "Memory ID"},
+						"content":   map[string]any{"type": "string", "description": "New content (replaces the existing text)"},
+						"tags":      map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Replacement tag list"},
+						"edited_by": map[string]any{"type": "string", "description": "Actor recorded on the revision (default: caller project)"},
+					},
+				},
+			},
+			Tool{
+				Name:        "tag_observation",
+				Description: "Add and/or remove tags on a memory. Recorded in the revision history as a tag change.",
+				tier:        tierUseful,
+				InputSchema: map[string]any{
+					"type":     "object",
+					"required": []string{"id"},
+					"properties": map[string]any{
+						"id":        map[string]any{"type": "number", "description": "Memory ID"},
+						"add":       map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Tags to add"},
+						"remove":    map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Tags to remove"},
+						"edited_by": map[string]any{"type": "string", "description": "Actor recorded on the revision (default: caller project)"},
+					},
+				},
+			},
+			Tool{
+				Name:        "promote_observation",
+				Description: "Move a memory to global scope (tag scope:global) so recall with include_global returns it in every project. Use on candidates from list_promotion_candidates. Private memories cannot be promoted. Recorded in the revision history as a tag change.",
+				tier:        tierAdmin,
+				InputSchema: map[string]any{
+					"type":     "object",
+					"required": []string{"id"},
+					"properties": map[string]any{
+						"id":        map[string]any{"type": "number", "description": "Memory ID"},
+						"edited_by": map[string]any{"type": "string", "description": "Actor recorded on the revision (default: caller project)"},
+					},
+				},
+			},
+			Tool{
+				Name:        "get_observation_history",
+				Description: "Show the revision history of a memory: every prior version with timestamp, actor, action, and a line diff to the version that replaced it.",
+				tier:        tierUseful,
+				InputSchema: map[string]any{
+					"type":     "object",
+					"required": []string{"id"},
+					"properties": map[string]any{
+						"id":    map[string]any{"type": "number", "description": "Memory ID"},
+						"limit": map[string]any{"type": "number", "default": 20, "minimum": 1, "maximum": 200, "description": "Max revisions to return"},
+						"diff":  map[string]any{"type": "boolean", "default": true, "description": "Include line diffs between versions"},
+					},
+				},
+			},
+			Tool{
+				Name:        "pin_observation",
+				Description: "Pin a memory so it is injected into every context for its project, ahead of scored results. Use for critical constraints (\"never touch prod config\"). The number of pins per project is capped.",
+				tier:        tierUseful,
+				InputSchema: map[string]any{
+					"type":     "object",
+					"required": []string{"id"},
+					"properties": map[string]any{
+						"id":     map[string]any{"type": "number", "description": "Memory ID"},
+						"pinned": map[string]any{"type": "boolean", "default": true, "description": "false to unpin"},
+					},
+				},
+			},
+			Tool{
+				Name:        "lock_observation",
+				Description: "Lock a memory so nothing can edit, tag, merge, revert or delete it (agents and maintenance jobs included) until it is explicitly unlocked. Use for hand-curated facts that must not drift.",
+				tier:        tierUseful,
+				InputSchema: map[string]any{
+					"type":     "object",
+					"required": []string{"id"},
+					"properties": map[string]any{
+						"id":     map[string]any{"type": "number", "description": "Memory ID"},
+						"locked": map[string]any{"type": "boolean", "default": true, "description": "false to unlock"},
+					},
+				},
+			},
+			Tool{
+				Name:        "review_observation",
+				Description: "Work the review queue of memories stored below the quality gate floor (ENGRAM_QUALITY_GATE_FLOOR). Pending memories are kept out of search and injection. list shows them with score and suggestions; approve releases one as is; enrich replaces its content and/or tags and releases it once it clears the floor; discard deletes it.",
+				tier:        tierUseful,
+				InputSchema: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"action":  map[string]any{"type": "string", "enum": []string{"list", "approve", "enrich", "discard"}, "default": "list"},
+						"id":      map[string]any{"type": "number", "description": "Memory ID (approve, enrich, discard)"},
+						"project": map[string]any{"type": "string", "description": "Project to list (default: all projects)"},
+						"limit":   map[string]any{"type": "number", "default": 20, "minimum": 1, "maximum": 100, "description": "Max memories to list"},
+						"content": map[string]any{"type": "string", "description": "Replacement content (enrich)"},
+						"tags":    map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Replacement tag list (enrich)"},
+					},
+				},
+			},
+			Tool{
+				Name:        "set_observation_visibility",
+				Description: "Change who a memory is returned to: private (only the client that created it), team (every authenticated client) or public. Only the creating client may change it.",
+				tier:        tierUseful,
+				InputSchema: map[string]any{
+					"type":     "object",
+					"required": []string{"id", "visibility"},
+					"properties": map[string]any{
+						"id":         map[string]any{"type": "number", "description": "Memory ID"},
+						"visibility": map[string]any{"type": "string", "enum": []string{"private", "team", "public"}, "description": "New visibility (public is currently the same as team)"},
+					},
+				},
+			},
+			Tool{
+				Name:        "suggest_related_files",
+				Description: "Given a file path, rank the files that memories mention together with it (file: tags and paths in memory content), with the memories behind each suggestion. Reveals coupling the code layout does not show: check before changing a file.",
+				tier:        tierUseful,
+				InputSchema: map[string]any{
+					"type":     "object",
+					"required": []string{"path"},
+					"properties": map[string]any{
+						"path":    map[string]any{"type": "string", "description": "File path, relative to the repository root or absolute"},
+						"project": map[string]any{"type": "string", "description": "Project ID (defaults to current)"},
+						"limit":   map[string]any{"type": "number", "default": 10, "minimum": 1, "maximum": 50, "description": "Max related files to return"},
+					},
+				},
+			},
+			Tool{
+				Name:        "find_warnings",
+				Description: "List the project's cautions and known-bad approaches (memories stored with polarity caution or do_not). Check before trying an approach that may already have failed.",
+				tier:        tierUseful,
+				InputSchema: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"project": map[string]any{"type": "string", "description": "Project ID (defaults to current)"},
+						"query":   map[string]any{"type": "string", "description": "Only warnings sharing terms with this text, best matches first"},
+						"limit":   map[string]any{"type": "number", "default": 10, "minimum": 1, "maximum": 100, "description": "Max warnings to return"},
+					},
+				},
+			},
+			Tool{
+				Name:        "check_prior_art",
+				Description: "Have we done this before? Compare a task description with the project's decision, feature and refactor memories and its past sessions. Returns the closest matches with links and a verdict: likely-done-before, related-work or novel. Call before starting a feature to avoid re-implementing it.",
+				tier:        tierUseful,
+				InputSchema: map[string]any{
+					"type":     "object",
+					"required": []string{"task"},
+					"properties": map[string]any{
+						"task":    map[string]any{"type": "string", "description": "What you are about to build or change"},
+						"project": map[string]any{"type": "string", "description": "Project ID (defaults to current)"},
+						"limit":   map[string]any{"type": "number", "default": 5, "minimum": 1, "maximum": 20, "description": "Max matches to return"},
+					},
+				},
+			},
+			Tool{
+				Name:        "revert_observation",
+				Description: "Restore a memory to the content and tags of an earlier version. The revert is recorded as a new version; history is never rewritten.",
+				tier:        tierUseful,
+				InputSchema: map[string]any{
+					"type":     "object",
+					"required": []string{"id", "version"},
+					"properties": map[string]any{
+						"id":        map[string]any{"type": "number", "description": "Memory ID"},
+						"version":   map[string]any{"type": "number", "description": "Version to restore (see get_observation_history)"},
+						"edited_by": map[string]any{"type": "string", "description": "Actor recorded on the revision (default: caller project)"},
+					},
+				},
+			},
+		)
+	}
+
+	// Relation tools — only advertise when the relation store is wired
+	if s.relationStore != nil {
+		relationProps := func(extra map[string]any) map[string]any {
+			props := map[string]any{
+				"source_id":     map[string]any{"type": "number", "description": "Source memory ID"},
+				"target_id":     map[string]any{"type": "number", "description": "Target memory ID"},
+				"relation_type": map[string]any{"type": "string", "enum": relationTypeNames(), "description": "Relation type, read as: source <relation_type> target"},
+			}
+			for k, v := range extra {
+				props[k] = v
+			}
+			return props
+		}
+		tools = append(tools,
+			Tool{
+				Name:        "create_relation",
+				Description: "Link two memories with a typed relation (e.g. A fixes B). Symmetric types (relates_to, similar_to, contradicts, ...) and references/referenced_by also store the inverse edge. Re-creating a relation updates its confidence.",
+				tier:        tierUseful,
+				InputSchema: map[string]any{
+					"type":     "object",
+					"required": []string{"source_id", "target_id", "relation_type"},
+					"properties": relationProps(map[string]any{
+						"confidence": map[string]any{"type": "number", "default": 1.0, "minimum": 0.0, "maximum": 1.0, "description": "Confidence 0-1"},
+						"reason":     map[string]any{"type": "string", "description": "Why the memories are related"},
+					}),
+				},
+			},
+			Tool{
+				Name:        "delete_relation",
+				Description: "Remove a relation between two memories, together with its inverse edge when the type has one.",
+				tier:        tierAdmin,
+				InputSchema: map[string]any{
+					"type":       "object",
+					"required":   []string{"source_id", "target_id", "relation_type"},
+					"properties": relationProps(nil),
+				},
+			},
+		)
+	}
+
 	// Backfill status tool — only advertise when backfill tracker is available
 	if s.backfillStatusFunc != nil {
 		tools = append(tools, Tool{
@@ -774,6 +1049,131 @@ func (s *Server) handleToolsList(req *Request) *Response {
 		})
 	}
 
+	// Background job tools — only advertise when the job manager is wired
+	if s.jobManager != nil {
+		tools = append(tools,
+			Tool{
+				Name:        "get_job_status",
+				Description: "Get the status of a background job started by a long-running operation such as admin(action=\"maintenance\"): running, succeeded, failed or cancelled, with progress, result or error.",
+				tier:        tierAdmin,
+				InputSchema: map[string]any{
+					"type":     "object",
+					"required": []string{"job_id"},
+					"properties": map[string]any{
+						"job_id": map[string]any{"type": "string", "description": "Job ID returned by the operation"},
+					},
+				},
+			},
+			Tool{
+				Name:        "cancel_job",
+				Description: "Cancel a running background job. The job reports status cancelled once it stops.",
+				tier:        tierAdmin,
+				InputSchema: map[string]any{
+					"type":     "object",
+					"required": []string{"job_id"},
+					"properties": map[string]any{
+						"job_id": map[string]any{"type": "string", "description": "Job ID to cancel"},
+					},
+				},
+			},
+		)
+	}
+
+	// Audit log — only advertise when the audit log store is wired
+	if s.auditLogStore != nil {
+		tools = append(tools, Tool{
+			Name:        "get_audit_log",
+			Description: "List the audit trail of destructive operations (memory deletes, edits and reverts, relation, credential, issue and project deletes), newest first: who did it (actor), through which client (mcp, hook, api), the affected IDs and a summary of the state that was removed.",
+			tier:        tierAdmin,
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"operation": map[string]any{"type": "string", "description": "Filter by operation, e.g. memory.delete, memory.revert, credential.delete"},
+					"project":   map[string]any{"type": "string", "description": "Filter by project"},
+					"actor":     map[string]any{"type": "string", "description": "Filter by actor, e.g. operator or keycard:<id>"},
+					"since":     map[string]any{"type": "string", "description": "Only entries at or after this RFC 3339 timestamp"},
+					"limit":     map[string]any{"type": "integer", "description": "Maximum entries to return (default 50, max 1000)"},
+				},
+			},
+		})
+	}
+
+	if s.storageStatsStore != nil {
+		tools = append(tools, Tool{
+			Name:        "get_storage_stats",
+			Description: "Report database size and where it goes: total size, the largest tables with their index sizes, attachment size, and per project the rows and bytes of memories, revisions, attachments, rules and logged queries with growth over the last 30 days. Use it to decide when and where to prune. Covers every tenant, so it needs admin access.",
+			tier:        tierAdmin,
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"project": map[string]any{"type": "string", "description": "Limit the per-project breakdown to this project"},
+				},
+			},
+		})
+	}
+
+	// Prompt history analytics — only advertise when the search query log is wired
+	if s.searchQueryLogStore != nil {
+		tools = append(tools, Tool{
+			Name:        "analyze_prompt_history",
+			Description: "Analyze the user prompts sent for context injection: recurring questions (similar prompts grouped), prompts that matched no memory, and suggested memories to store for questions that keep going unanswered.",
+			tier:        tierAdmin,
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"project": map[string]any{"type": "string", "description": "Filter by project (default: all projects)"},
+					"days":    map[string]any{"type": "number", "default": 30, "description": "Look-back window in days"},
+					"top_n":   map[string]any{"type": "number", "default": 10, "minimum": 1, "maximum": 100, "description": "Max entries per list"},
+				},
+			},
+		}, Tool{
+			Name:        "find_past_prompt",
+			Description: "Full-text search over past user prompts (e.g. \"that prompt where I described the deployment process\"). Returns the matching prompts, best match first, with highlighted snippets (matched words in **bold**). Also available as recall(action=\"search\", type=\"prompts\").",
+			tier:        tierUseful,
+			InputSchema: map[string]any{
+				"type":     "object",
+				"required": []string{"query"},
+				"properties": map[string]any{
+					"query":   map[string]any{"type": "string", "description": "Words to find; supports \"quoted phrases\", OR and -exclusions"},
+					"project": map[string]any{"type": "string", "description": "Filter by project (default: all projects)"},
+					"limit":   map[string]any{"type": "number", "default": 10, "minimum": 1, "maximum": 50, "description": "Max prompts to return"},
+				},
+			},
+		})
+	}
+
+	// Access heatmap — only advertise when access counting is wired
+	if s.memoryAccessStore != nil {
+		tools = append(tools, Tool{
+			Name:        "get_access_heatmap",
+			Description: "Show how often each memory reached an agent, per day and by source: injection (added to context by a hook), search (returned by recall or a context search) and get (fetched by ID). Busiest memories first, with their size in tokens and the tokens spent injecting them. Memories injected but never searched or fetched are listed in injected_only: candidates for unpinning or pruning.",
+			tier:        tierAdmin,
+			InputSchema: map[string]any{
+				"type":     "object",
+				"required": []string{"project"},
+				"properties": map[string]any{
+					"project": map[string]any{"type": "string", "description": "Project ID (defaults to current)"},
+					"ids":     map[string]any{"type": "array", "items": map[string]any{"type": "number"}, "description": "Only these memory IDs"},
+					"days":    map[string]any{"type": "number", "default": 30, "minimum": 1, "maximum": 365, "description": "Look-back window in days"},
+					"limit":   map[string]any{"type": "number", "default": 20, "minimum": 1, "maximum": 100, "description": "Max memories to return"},
+				},
+			},
+		}, Tool{
+			Name:        "list_promotion_candidates",
+			Description: "Review queue for global knowledge: project memories tagged with a globalizable concept (security, best-practice, pattern, architecture, ...) that other projects of the tenant reached by search or injection. Most widely used first, with the projects that accessed them. Accept a candidate with promote_observation.",
+			tier:        tierAdmin,
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"project":      map[string]any{"type": "string", "description": "Only memories of this project (default: all projects)"},
+					"days":         map[string]any{"type": "number", "default": 30, "minimum": 1, "maximum": 365, "description": "Look-back window in days"},
+					"min_projects": map[string]any{"type": "number", "default": 2, "minimum": 1, "description": "Fewest other projects that must have accessed the memory"},
+					"limit":        map[string]any{"type": "number", "default": 20, "minimum": 1, "maximum": 100, "description": "Max candidates to return"},
+				},
+			},
+		})
+	}
+
 	// Memory management tools — advertise when memory storage is available
 	if s.memoryStore != nil {
 		tools = append(tools,
@@ -789,13 +1189,33 @@ func (s *Server) handleToolsList(req *Request) *Response {
 						"title":         map[string]any{"type": "string", "description": "Short title for the memory"},
 						"tags":          map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Concept tags (supports hierarchical: lang:go:concurrency)"},
 						"rejected":      map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Alternatives considered and dismissed (for decision observations)"},
+						"fields":        map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}, "description": "Schema sections by name, e.g. {\"root_cause\": \"...\"} for bugfix (see get_observation_schema)"},
 						"type":          map[string]any{"type": "string", "description": "Memory type: decision, bugfix, feature, discovery, refactor"},
 						"importance":    map[string]any{"type": "number", "minimum": 0, "maximum": 1, "description": "Importance score (0-1)"},
-						"scope":         map[string]any{"type": "string", "enum": []string{"project", "global"}, "description": "Visibility scope"},
+						"scope":         map[string]any{"type": "string", "enum": []string{"project", "workspace", "global"}, "description": "Visibility scope. workspace shares the memory with every project in a monorepo and requires workspace"},
 						"project":       map[string]any{"type": "string", "description": "Project ID (defaults to current)"},
+						"workspace":     map[string]any{"type": "string", "description": "Workspace ID (the project ID of the monorepo root) for scope=workspace"},
+						"language":      map[string]any{"type": "string", "description": "ISO 639-1 language code of the content (detected automatically when omitted)"},
 						"ttl_days":      map[string]any{"type": "integer", "minimum": 1, "description": "TTL in days for verified facts. Auto-computed from tags if not provided. Only applies to observations with 'verified' tag."},
 						"always_inject": map[string]any{"type": "boolean", "description": "If true, this memory will be injected into every agent context regardless of query relevance. Use for behavioral rules that must always be present."},
 						"agent_source":  map[string]any{"type": "string", "enum": []string{"claude-code", "codex", "gemini", "other", "unknown"}, "description": "Which AI tool created this observation"},
+						"polarity":      map[string]any{"type": "string", "enum": []string{"positive", "caution", "do_not"}, "default": "positive", "description": "caution or do_not for past failures and approaches to avoid; they are injected as warnings and listed by find_warnings"},
+						"visibility":    map[string]any{"type": "string", "enum": []string{"private", "team", "public"}, "default": "team", "description": "private: only returned to this client (workstation token or logged-in user); team: every authenticated client of the tenant; public: currently the same as team"},
+						"attachments": map[string]any{
+							"type":        "array",
+							"maxItems":    10,
+							"description": "Code snippets or diffs stored apart from the content (max 64 KiB each); returned only by recall_memory format=detailed",
+							"items": map[string]any{
+								"type":     "object",
+								"required": []string{"content"},
+								"properties": map[string]any{
+									"kind":     map[string]any{"type": "string", "enum": []string{"code", "diff"}, "default": "code"},
+									"content":  map[string]any{"type": "string"},
+									"name":     map[string]any{"type": "string", "description": "Label, e.g. a file path"},
+									"language": map[string]any{"type": "string", "description": "Syntax hint, e.g. go"},
+								},
+							},
+						},
 					},
 				},
 			},
@@ -811,11 +1231,53 @@ func (s *Server) handleToolsList(req *Request) *Response {
 						"tags":    map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Filter by concept tags"},
 						"type":    map[string]any{"type": "string", "description": "Filter by observation type"},
 						"limit":   map[string]any{"type": "number", "default": 10, "minimum": 1, "maximum": 50},
-						"format":  map[string]any{"type": "string", "enum": []string{"text", "items", "detailed"}, "default": "text"},
+						"format":  map[string]any{"type": "string", "enum": []string{"text", "items", "detailed", "digest"}, "default": "text", "description": "digest: one plain-text line per result (id, type, age, title, top fact); detailed: full records including attachments"},
 						"project": map[string]any{"type": "string", "description": "Project ID to scope results (includes project-scoped and global observations)"},
 					},
 				},
 			},
+			Tool{
+				Name:        "build_topic_map",
+				Description: "Overview of what memory knows about a project: groups its memories into topics by concept tag, with per-topic counts, type breakdown, co-occurring subtopics and sample memory IDs.",
+				tier:        tierUseful,
+				InputSchema: map[string]any{
+					"type":     "object",
+					"required": []string{"project"},
+					"properties": map[string]any{
+						"project":    map[string]any{"type": "string", "description": "Project to map"},
+						"max_topics": map[string]any{"type": "integer", "default": 10, "minimum": 1, "maximum": 50, "description": "Top-level topics to return; the rest are counted as other"},
+						"limit":      map[string]any{"type": "integer", "default": 500, "minimum": 1, "maximum": 1000, "description": "Most recent memories to include"},
+					},
+				},
+			},
+			Tool{
+				Name:        "generate_project_brief",
+				Description: "Onboarding brief for a project as Markdown: architecture, key decisions, conventions, gotchas, operations, discoveries and recent changes from memory, pinned memories first. Save it to a file to bring a teammate or a fresh session up to speed.",
+				tier:        tierUseful,
+				InputSchema: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"project":         map[string]any{"type": "string", "description": "Project to brief (default: the session's project)"},
+						"max_per_section": map[string]any{"type": "integer", "default": 10, "minimum": 1, "description": "Memories listed per section; the rest are counted"},
+						"limit":           map[string]any{"type": "integer", "default": 500, "minimum": 1, "maximum": 1000, "description": "Most recent memories to draw from"},
+					},
+				},
+			},
+			Tool{
+				Name:        "get_digest",
+				Description: "Weekly or monthly digest of a project as Markdown: its sessions, decisions, changes, fixes and discoveries in that period. Digests of completed periods are stored as searchable memories (type:digest) the first time they are requested.",
+				tier:        tierUseful,
+				InputSchema: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"project":         map[string]any{"type": "string", "description": "Project to digest (default: the session's project)"},
+						"kind":            map[string]any{"type": "string", "enum": []string{"weekly", "monthly"}, "default": "weekly", "description": "Digest period length"},
+						"period":          map[string]any{"type": "string", "description": "Week (2026-W41), month (2026-10), a date inside the period, or current (default: the last completed period)"},
+						"regenerate":      map[string]any{"type": "boolean", "default": false, "description": "Rebuild a stored digest from the current memories"},
+						"max_per_section": map[string]any{"type": "integer", "default": 8, "minimum": 1, "description": "Items listed per section; the rest are counted"},
+					},
+				},
+			},
 			Tool{
 				Name:        "rate_memory",
 				Description: "Rate a memory as useful or not useful. Affects future ranking in search results.",
@@ -864,6 +1326,28 @@ func (s *Server) handleToolsList(req *Request) *Response {
 		)
 	}
 
+	// Session labels — only advertise when session store is available
+	if s.sessionStore != nil {
+		tools = append(tools,
+			Tool{
+				Name:        "tag_session",
+				Description: "Name and tag a session so it can be found later by label (e.g. \"big refactor\") instead of by date. Filter searches with recall(session_tag=...) and list sessions with GET /api/sessions/list?tag=.",
+				tier:        tierUseful,
+				InputSchema: map[string]any{
+					"type":     "object",
+					"required": []string{"session_id"},
+					"properties": map[string]any{
+						"session_id":  map[string]any{"type": "string", "description": "Claude session ID or numeric session ID"},
+						"name":        map[string]any{"type": "string", "description": "Session name; empty string clears it"},
+						"tags":        map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Replace the session's tags"},
+						"add_tags":    map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Tags to add"},
+						"remove_tags": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Tags to remove"},
+					},
+				},
+			},
+		)
+	}
+
 	// Credential vault tools — advertise only when credential persistence and vault keying are actually available.
 	if config.GetDatabaseDSN() != "" && crypto.VaultExists(config.Get()) {
 		tools = append(tools,
@@ -1136,6 +1620,10 @@ func (s *Server) handleToolsList(req *Request) *Response {
 		}
 	}
 
+	if s.readOnly {
+		primary = readOnlyToolList(primary)
+	}
+
 	return &Response{
 		JSONRPC: "2.0",
 		ID:      req.ID,
@@ -1160,7 +1648,17 @@ func (s *Server) handleToolsCall(ctx context.Context, req *Request) *Response {
 		}
 	}
 
-	result, err := s.callTool(ctx, params.Name, params.Arguments)
+	ctx, span := tracing.Start(ctx, "mcp.tool "+params.Name, attribute.String("mcp.tool", params.Name))
+	var result string
+	err := s.checkReadOnly(ctx, params.Name, params.Arguments)
+	if err == nil {
+		result, err = s.callTool(ctx, params.Name, params.Arguments)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
 	if err != nil {
 		// Truncated args for debugging (first 200 chars)
 		argsStr := string(params.Arguments)
@@ -1218,6 +1716,10 @@ func (s *Server) callTool(ctx context.Context, name string, args json.RawMessage
 		return s.handleFindRelatedObservations(ctx, args)
 	case "find_similar_observations":
 		return s.handleFindSimilarObservations(ctx, args)
+	case "get_observation_schema":
+		return s.handleGetObservationSchema(ctx, args)
+	case "get_observation_quality":
+		return s.handleGetObservationQuality(ctx, args)
 	case "get_memory_stats":
 		return s.handleGetMemoryStats(ctx)
 	case "store_rule":
@@ -1228,6 +1730,14 @@ func (s *Server) callTool(ctx context.Context, name string, args json.RawMessage
 		return s.handleIssues(ctx, args)
 	case "check_system_health":
 		return s.handleCheckSystemHealth(ctx)
+	case "analyze_prompt_history":
+		return s.handleAnalyzePromptHistory(ctx, args)
+	case "find_past_prompt":
+		return s.handleFindPastPrompt(ctx, args)
+	case "get_access_heatmap":
+		return s.handleGetAccessHeatmap(ctx, args)
+	case "list_promotion_candidates":
+		return s.handleListPromotionCandidates(ctx, args)
 	case "analyze_search_patterns":
 		return s.handleAnalyzeSearchPatterns(ctx, args)
 	case "search_sessions":
@@ -1277,6 +1787,16 @@ func (s *Server) callTool(ctx context.Context, name string, args json.RawMessage
 		return s.handleImportInstincts(ctx, args)
 	case "backfill_status":
 		return s.handleBackfillStatus()
+	case "get_job_status":
+		return s.handleGetJobStatus(args)
+	case "cancel_job":
+		return s.handleCancelJob(args)
+	case "get_audit_log":
+		return s.handleGetAuditLog(ctx, args)
+	case "get_storage_stats":
+		return s.handleGetStorageStats(ctx, args)
+	case "tag_session":
+		return s.handleTagSession(ctx, args)
 	case "store_credential":
 		return s.handleStoreCredential(ctx, args)
 	case "get_credential":
@@ -1300,10 +1820,44 @@ func (s *Server) callTool(ctx context.Context, name string, args json.RawMessage
 		return s.handleStoreMemory(ctx, args)
 	case "recall_memory":
 		return s.handleRecallMemory(ctx, args)
+	case "build_topic_map":
+		return s.handleBuildTopicMap(ctx, args)
+	case "generate_project_brief":
+		return s.handleGenerateProjectBrief(ctx, args)
+	case "get_digest":
+		return s.handleGetDigest(ctx, args)
 	case "rate_memory":
 		return s.handleRateMemory(ctx, args)
 	case "suppress_memory":
 		return s.handleSuppressMemory(ctx, args)
+	case "edit_observation":
+		return s.handleEditMemory(ctx, args)
+	case "create_relation":
+		return s.handleCreateRelation(ctx, args)
+	case "delete_relation":
+		return s.handleDeleteRelation(ctx, args)
+	case "tag_observation":
+		return s.handleTagObservation(ctx, args)
+	case "promote_observation":
+		return s.handlePromoteObservation(ctx, args)
+	case "get_observation_history":
+		return s.handleGetObservationHistory(ctx, args)
+	case "revert_observation":
+		return s.handleRevertObservation(ctx, args)
+	case "pin_observation":
+		return s.handlePinObservation(ctx, args)
+	case "lock_observation":
+		return s.handleLockObservation(ctx, args)
+	case "review_observation":
+		return s.handleReviewObservation(ctx, args)
+	case "set_observation_visibility":
+		return s.handleSetObservationVisibility(ctx, args)
+	case "find_warnings":
+		return s.handleFindWarnings(ctx, args)
+	case "check_prior_art":
+		return s.handleCheckPriorArt(ctx, args)
+	case "suggest_related_files":
+		return s.handleSuggestRelatedFiles(ctx, args)
 	}
 
 	// v5 (US9): search/timeline/decisions/changes/how_it_works/find_by_concept/
@@ -1561,6 +1561,18 @@ func TestCallTool_AllSpecialTools(t *testing.T) {
 			args:     `{}`,
 			wantErr:  true,
 		},
+		{
+			name:     "review_observation - invalid json",
+			toolName: "review_observation",
+			args:     `{invalid`,
+			wantErr:  true,
+		},
+		{
+			name:     "review_observation - approve without id",
+			toolName: "review_observation",
+			args:     `{"action": "approve"}`,
+			wantErr:  true,
+		},
 		{
 			name:     "suggest_consolidations - invalid json",
 			toolName: "suggest_consolidations",
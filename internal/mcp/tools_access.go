@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// SetMemoryAccessStore wires the per-memory access counters. It enables the
+// get_access_heatmap tool and makes recall searches count as accesses.
+func (s *Server) SetMemoryAccessStore(store *gorm.MemoryAccessStore) {
+	s.memoryAccessStore = store
+}
+
+// recordMemoryAccess counts one access of each memory from source, made from
+// the calling session's project. Non-blocking; a no-op when access counting
+// is not wired.
+func (s *Server) recordMemoryAccess(ctx context.Context, source string, mems []*models.Memory) {
+	if s.memoryAccessStore == nil || len(mems) == 0 {
+		return
+	}
+	ids := make([]int64, 0, len(mems))
+	for _, mem := range mems {
+		ids = append(ids, mem.ID)
+	}
+	s.memoryAccessStore.Record(source, projectFromContext(ctx), ids)
+}
+
+// accessHeatmapEntry is one memory of the heatmap with the derived cost and
+// use figures.
+type accessHeatmapEntry struct {
+	gorm.MemoryAccessSeries
+	// Explicit counts the accesses an agent asked for (search and get).
+	Explicit int64 `json:"explicit"`
+	// InjectedTokens estimates the context tokens spent injecting the memory.
+	InjectedTokens int64 `json:"injected_tokens"`
+}
+
+// summarizeAccess derives the explicit accesses and injected tokens of each
+// memory, and lists the memories that were injected but never searched for
+// or fetched.
+func summarizeAccess(series []gorm.MemoryAccessSeries) ([]accessHeatmapEntry, []int64) {
+	entries := make([]accessHeatmapEntry, len(series))
+	injectedOnly := []int64{}
+	for i, ser := range series {
+		injected := ser.Totals[gorm.AccessInjection]
+		explicit := ser.Totals[gorm.AccessSearch] + ser.Totals[gorm.AccessGet]
+		entries[i] = accessHeatmapEntry{
+			MemoryAccessSeries: ser,
+			Explicit:           explicit,
+			InjectedTokens:     injected * ser.Tokens,
+		}
+		if injected > 0 && explicit == 0 {
+			injectedOnly = append(injectedOnly, ser.ID)
+		}
+	}
+	return entries, injectedOnly
+}
+
+// handleGetAccessHeatmap returns the daily access series of a project's
+// busiest memories, split by source (injection, search, get).
+func (s *Server) handleGetAccessHeatmap(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryAccessStore == nil {
+		return "", fmt.Errorf("access heatmap not available: access counting not configured")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	project := projectFromContext(ctx)
+	if project == "" {
+		project = coerceString(m["project"], "")
+	}
+	if project == "" {
+		return "", fmt.Errorf("project is required for get_access_heatmap")
+	}
+	days := coerceInt(m["days"], 30)
+	if days <= 0 {
+		days = 30
+	} else if days > 365 {
+		days = 365
+	}
+	limit := coerceInt(m["limit"], 20)
+	if limit <= 0 {
+		limit = 20
+	} else if limit > 100 {
+		limit = 100
+	}
+	since := time.Now().UTC().AddDate(0, 0, -days+1)
+
+	series, err := s.memoryAccessStore.Heatmap(ctx, gorm.HeatmapQuery{
+		Project: project,
+		IDs:     coerceInt64Slice(m["ids"]),
+		Since:   since,
+		Limit:   limit,
+	})
+	if err != nil {
+		return "", err
+	}
+	entries, injectedOnly := summarizeAccess(series)
+	out := map[string]any{
+		"project":       project,
+		"since":         since.Format(time.DateOnly),
+		"days":          days,
+		"sources":       gorm.AccessSources,
+		"memories":      entries,
+		"count":         len(entries),
+		"injected_only": injectedOnly,
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal access heatmap: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/db/gorm"
+)
+
+func TestSummarizeAccess(t *testing.T) {
+	t.Parallel()
+
+	series := []gorm.MemoryAccessSeries{
+		{ID: 1, Tokens: 50, Totals: map[string]int64{gorm.AccessInjection: 12}},
+		{ID: 2, Tokens: 10, Totals: map[string]int64{gorm.AccessInjection: 4, gorm.AccessSearch: 2, gorm.AccessGet: 1}},
+		{ID: 3, Tokens: 30, Totals: map[string]int64{gorm.AccessGet: 3}},
+	}
+	entries, injectedOnly := summarizeAccess(series)
+	require.Len(t, entries, 3)
+	assert.Equal(t, []int64{1}, injectedOnly)
+	assert.Equal(t, int64(600), entries[0].InjectedTokens)
+	assert.Zero(t, entries[0].Explicit)
+	assert.Equal(t, int64(3), entries[1].Explicit)
+	assert.Equal(t, int64(40), entries[1].InjectedTokens)
+	assert.Zero(t, entries[2].InjectedTokens)
+
+	_, injectedOnly = summarizeAccess(nil)
+	assert.NotNil(t, injectedOnly, "encodes as [] rather than null")
+}
+
+func TestHandleGetAccessHeatmap_Validation(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(ServerOptions{Version: "1.0.0"})
+	_, err := server.handleGetAccessHeatmap(t.Context(), []byte(`{"project":"p"}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "access counting not configured")
+
+	server.SetMemoryAccessStore(&gorm.MemoryAccessStore{})
+	_, err = server.handleGetAccessHeatmap(t.Context(), []byte(`{}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "project is required")
+}
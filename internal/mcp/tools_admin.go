@@ -11,7 +11,8 @@ import (
 // It is referenced by handleAdmin for validation messages and by the tool
 // registration in server.go for the tool description.
 var adminActions = []string{
-	"stats", "search_analytics", "backfill_status",
+	"stats", "search_analytics", "backfill_status", "maintenance", "bulk_delete",
+	"migrate_project",
 }
 
 func (s *Server) handleAdmin(ctx context.Context, args json.RawMessage) (string, error) {
@@ -31,6 +32,12 @@ func (s *Server) handleAdmin(ctx context.Context, args json.RawMessage) (string,
 		return s.handleAnalyzeSearchPatterns(ctx, args)
 	case "backfill_status":
 		return s.handleBackfillStatus()
+	case "maintenance":
+		return s.handleAdminMaintenance()
+	case "bulk_delete":
+		return s.handleBulkDelete(ctx, args)
+	case "migrate_project":
+		return s.handleMigrateProject(ctx, args)
 	default:
 		return "", fmt.Errorf("unknown admin action: %q (valid: %s)", action, strings.Join(adminActions, ", "))
 	}
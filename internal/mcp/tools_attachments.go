@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/internal/privacy"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// SetAttachmentStore wires memory attachments: store_memory accepts code
+// snippets and diffs, and recall_memory returns them with format=detailed.
+func (s *Server) SetAttachmentStore(store *gorm.AttachmentStore) {
+	s.attachmentStore = store
+}
+
+// parseAttachments validates the attachments argument of store_memory: a list
+// of {kind, content, name, language} objects within the size limits. Content
+// is redacted like the memory itself.
+func parseAttachments(raw any, project string) ([]*models.MemoryAttachment, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	list, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("attachments must be an array of objects")
+	}
+	if len(list) > models.MaxAttachmentsPerMemory {
+		return nil, fmt.Errorf("too many attachments: %d (max %d)", len(list), models.MaxAttachmentsPerMemory)
+	}
+	attachments := make([]*models.MemoryAttachment, 0, len(list))
+	for i, item := range list {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("attachments[%d] must be an object", i)
+		}
+		kind := strings.ToLower(coerceString(obj["kind"], models.AttachmentKindCode))
+		if !models.IsValidAttachmentKind(kind) {
+			return nil, fmt.Errorf("attachments[%d]: invalid kind %q: must be code or diff", i, kind)
+		}
+		content := coerceString(obj["content"], "")
+		if strings.TrimSpace(content) == "" {
+			return nil, fmt.Errorf("attachments[%d]: content is required", i)
+		}
+		if len(content) > models.MaxAttachmentSize {
+			return nil, fmt.Errorf("attachments[%d]: content exceeds maximum size of %d bytes", i, models.MaxAttachmentSize)
+		}
+		attachments = append(attachments, &models.MemoryAttachment{
+			Kind:     kind,
+			Name:     strings.TrimSpace(coerceString(obj["name"], "")),
+			Language: strings.ToLower(strings.TrimSpace(coerceString(obj["language"], ""))),
+			Content:  privacy.RedactForStorage(project, content),
+		})
+	}
+	return attachments, nil
+}
+
+// detailedMemory is a memory with its attachments, as returned by
+// recall_memory format=detailed.
+type detailedMemory struct {
+	*models.Memory
+	Attachments []*models.MemoryAttachment `json:"attachments,omitempty"`
+}
@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestParseAttachments(t *testing.T) {
+	t.Parallel()
+
+	attachments, err := parseAttachments([]any{
+		map[string]any{"content": "func f() {}", "language": "Go", "name": " f.go "},
+		map[string]any{"kind": "diff", "content": "-a\n+b\n"},
+	}, "p")
+	require.NoError(t, err)
+	require.Len(t, attachments, 2)
+	assert.Equal(t, models.AttachmentKindCode, attachments[0].Kind, "kind defaults to code")
+	assert.Equal(t, "go", attachments[0].Language)
+	assert.Equal(t, "f.go", attachments[0].Name)
+	assert.Equal(t, models.AttachmentKindDiff, attachments[1].Kind)
+
+	none, err := parseAttachments(nil, "p")
+	require.NoError(t, err)
+	assert.Nil(t, none)
+
+	tooMany := make([]any, models.MaxAttachmentsPerMemory+1)
+	for i := range tooMany {
+		tooMany[i] = map[string]any{"content": "x"}
+	}
+	tests := []struct {
+		raw         any
+		name        string
+		errContains string
+	}{
+		{name: "not an array", raw: "x", errContains: "must be an array"},
+		{name: "not an object", raw: []any{"x"}, errContains: "must be an object"},
+		{name: "bad kind", raw: []any{map[string]any{"kind": "image", "content": "x"}}, errContains: "invalid kind"},
+		{name: "empty content", raw: []any{map[string]any{"content": "  "}}, errContains: "content is required"},
+		{name: "too large", raw: []any{map[string]any{"content": strings.Repeat("x", models.MaxAttachmentSize+1)}}, errContains: "maximum size"},
+		{name: "too many", raw: tooMany, errContains: "too many attachments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := parseAttachments(tt.raw, "p")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestDetailedMemoryJSON(t *testing.T) {
+	t.Parallel()
+
+	out, err := json.Marshal(detailedMemory{
+		Memory:      &models.Memory{ID: 7, Project: "p", Content: "retry fix"},
+		Attachments: []*models.MemoryAttachment{{ID: 1, MemoryID: 7, Kind: "diff", Content: "+b", Size: 2}},
+	})
+	require.NoError(t, err)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(out, &decoded))
+	assert.Equal(t, "retry fix", decoded["content"], "memory fields are inlined")
+	require.Len(t, decoded["attachments"], 1)
+
+	out, err = json.Marshal(detailedMemory{Memory: &models.Memory{ID: 8}})
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "attachments")
+}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/thebtf/engram/internal/auth"
+	"github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/pkg/models"
+	"github.com/thebtf/engram/pkg/strutil"
+)
+
+// auditBeforeMaxLen caps the before-state summary stored per audit entry.
+const auditBeforeMaxLen = 500
+
+// SetAuditLogStore wires the audit log. Destructive tools record to it, and
+// the get_audit_log tool is advertised.
+func (s *Server) SetAuditLogStore(store *gorm.AuditLogStore) {
+	s.auditLogStore = store
+}
+
+// recordAudit appends a destructive operation performed through MCP to the
+// audit log. It is best-effort: the operation already happened, so a failed
+// write is logged rather than returned.
+func (s *Server) recordAudit(ctx context.Context, operation, project, before string, targetIDs ...string) {
+	if s.auditLogStore == nil {
+		return
+	}
+	entry := &models.AuditEntry{
+		Actor:     auth.ActorFrom(ctx),
+		Client:    models.AuditClientMCP,
+		Operation: operation,
+		Project:   project,
+		Before:    strutil.Truncate(before, auditBeforeMaxLen),
+		TargetIDs: targetIDs,
+	}
+	if err := s.auditLogStore.Record(ctx, entry); err != nil {
+		log.Warn().Err(err).Str("operation", operation).Strs("targets", targetIDs).Msg("Failed to record audit entry")
+	}
+}
+
+// auditMemoryID formats a memory ID as an audit target.
+func auditMemoryID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// handleGetAuditLog lists audit entries, newest first, filtered by operation,
+// project, actor and a since timestamp.
+func (s *Server) handleGetAuditLog(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.auditLogStore == nil {
+		return "", fmt.Errorf("audit log not available")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+
+	filter := models.AuditFilter{
+		Operation: coerceString(m["operation"], ""),
+		Project:   coerceString(m["project"], ""),
+		Actor:     coerceString(m["actor"], ""),
+		Limit:     coerceInt(m["limit"], 0),
+	}
+	if since := coerceString(m["since"], ""); since != "" {
+		filter.Since, err = time.Parse(time.RFC3339, since)
+		if err != nil {
+			return "", fmt.Errorf("since must be an RFC 3339 timestamp: %w", err)
+		}
+	}
+
+	entries, err := s.auditLogStore.List(ctx, filter)
+	if err != nil {
+		return "", fmt.Errorf("get audit log: %w", err)
+	}
+	data, err := json.MarshalIndent(map[string]any{
+		"entries": entries,
+		"count":   len(entries),
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal audit log: %w", err)
+	}
+	return string(data), nil
+}
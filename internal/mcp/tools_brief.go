@@ -0,0 +1,44 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/thebtf/engram/internal/brief"
+	"github.com/thebtf/engram/internal/db/gorm"
+)
+
+// briefDefaultLimit is the number of recent memories a brief draws from.
+const briefDefaultLimit = 500
+
+// handleGenerateProjectBrief returns a Markdown onboarding brief of a
+// project's memories (see package brief). The brief is returned, not
+// written: the server may not share the client's file system, so saving it
+// is left to the client (or `engram brief --out`).
+func (s *Server) handleGenerateProjectBrief(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryStore == nil {
+		return "", fmt.Errorf("generate_project_brief: memory store not configured")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	project := coerceString(m["project"], "")
+	if project == "" {
+		project = projectFromContext(ctx)
+	}
+	if project == "" {
+		return "", fmt.Errorf("generate_project_brief: project is required")
+	}
+	limit := coerceInt(m["limit"], briefDefaultLimit)
+	if limit <= 0 || limit > gorm.MaxPaginationLimit {
+		limit = briefDefaultLimit
+	}
+
+	memories, err := s.memoryStore.List(ctx, project, limit)
+	if err != nil {
+		return "", fmt.Errorf("generate_project_brief: %w", err)
+	}
+	return brief.Build(project, memories, brief.Options{MaxPerSection: coerceInt(m["max_per_section"], 0)}), nil
+}
@@ -0,0 +1,198 @@
+package mcp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/pkg/models"
+	"github.com/thebtf/engram/pkg/strutil"
+)
+
+// bulkSampleSize is the number of matching memories a dry run lists.
+const bulkSampleSize = 10
+
+// parseBulkFilter reads the filter of a bulk action: project (required),
+// type (obs_type), tags (concepts), since and until. Memories have no stored
+// score in v5, so score_below is rejected rather than ignored.
+func parseBulkFilter(m map[string]any) (gorm.MemoryFilter, error) {
+	f := gorm.MemoryFilter{Project: coerceString(m["project"], "")}
+	if f.Project == "" {
+		return f, fmt.Errorf("project is required")
+	}
+	if _, ok := m["score_below"]; ok {
+		return f, fmt.Errorf("score_below is not supported: memories have no stored score (they are ranked at search time)")
+	}
+
+	typ := coerceString(m["type"], coerceString(m["obs_type"], ""))
+	if typ != "" {
+		f.Tags = append(f.Tags, "type:"+typ)
+	}
+	f.Tags = append(f.Tags, coerceStringSlice(m["tags"])...)
+	f.Tags = append(f.Tags, coerceStringSlice(m["concepts"])...)
+
+	var err error
+	if f.Since, err = parseBulkTime(coerceString(m["since"], "")); err != nil {
+		return f, fmt.Errorf("since: %w", err)
+	}
+	if f.Until, err = parseBulkTime(coerceString(m["until"], "")); err != nil {
+		return f, fmt.Errorf("until: %w", err)
+	}
+	if !f.Since.IsZero() && !f.Until.IsZero() && !f.Until.After(f.Since) {
+		return f, fmt.Errorf("until must be after since")
+	}
+	return f, nil
+}
+
+// parseBulkTime parses an RFC 3339 timestamp or a YYYY-MM-DD date (the
+// start of that day, UTC). An empty value returns the zero time.
+func parseBulkTime(v string) (time.Time, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+	}
+	return t, nil
+}
+
+// bulkTokenKey keys bulkConfirmToken. It is generated per process, so a
+// token can only come from a dry run of this server and expires on restart.
+var bulkTokenKey = func() []byte {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key) // never fails (crypto/rand)
+	return key
+}()
+
+// bulkConfirmToken identifies the outcome of a dry run: the filter and the
+// exact memories it would change. Executing with the token only succeeds
+// while the filter still matches the same memories, so nothing stored or
+// changed after the preview is touched without a new one. The token is an
+// HMAC under bulkTokenKey, so it cannot be computed without the dry run.
+func bulkConfirmToken(action string, f gorm.MemoryFilter, ids []int64) string {
+	h := hmac.New(sha256.New, bulkTokenKey)
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%s", action, f.Project, strings.Join(f.Tags, "\x01"),
+		f.Since.UTC().Format(time.RFC3339Nano), f.Until.UTC().Format(time.RFC3339Nano))
+	for _, id := range ids {
+		fmt.Fprintf(h, "\x00%d", id)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:24]
+}
+
+// handleBulkDelete soft-deletes the memories matching a filter. Without
+// confirm_token it is a dry run: it reports how many memories match, lists
+// a sample and returns the token that executes exactly that deletion.
+// Locked memories are never deleted. Deleting is how v5 supersedes a
+// memory, so this also replaces bulk_mark_superseded.
+func (s *Server) handleBulkDelete(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryStore == nil {
+		return "", fmt.Errorf("memory store not available")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	f, err := parseBulkFilter(m)
+	if err != nil {
+		return "", fmt.Errorf("bulk_delete: %w", err)
+	}
+	// Locked memories are never deleted, so they must not fill the page.
+	f.Unlocked = true
+
+	matched, err := s.memoryStore.MatchMemories(ctx, f)
+	if err != nil {
+		return "", fmt.Errorf("bulk_delete: %w", err)
+	}
+	ids := make([]int64, len(matched))
+	for i, mem := range matched {
+		ids[i] = mem.ID
+	}
+	token := bulkConfirmToken("bulk_delete", f, ids)
+
+	out := map[string]any{
+		"project": f.Project,
+		"matched": len(ids),
+	}
+	// Each call handles at most MaxPaginationLimit memories, oldest first;
+	// a full page means more may match.
+	truncated := len(matched) == gorm.MaxPaginationLimit
+	if truncated {
+		out["truncated"] = true
+	}
+	confirm := coerceString(m["confirm_token"], "")
+	if confirm == "" {
+		sample := make([]map[string]any, 0, min(len(matched), bulkSampleSize))
+		for _, mem := range matched[:min(len(matched), bulkSampleSize)] {
+			sample = append(sample, map[string]any{
+				"id":         mem.ID,
+				"content":    strutil.Truncate(mem.Content, 120),
+				"created_at": mem.CreatedAt.UTC().Format(time.RFC3339),
+			})
+		}
+		out["dry_run"] = true
+		out["sample"] = sample
+		if len(ids) > 0 {
+			out["confirm_token"] = token
+			out["message"] = fmt.Sprintf("Dry run: %d memories would be deleted. Call again with the same filter and confirm_token to delete them.", len(ids))
+			if truncated {
+				out["message"] = fmt.Sprintf("Dry run: %d memories would be deleted; more may match the filter and need another run afterwards. Call again with the same filter and confirm_token to delete these.", len(ids))
+			}
+		} else {
+			out["message"] = "Dry run: no memories match the filter"
+		}
+		return marshalBulkResult(out)
+	}
+	if confirm != token {
+		return "", fmt.Errorf("bulk_delete: confirm_token does not match; the filter now matches %d memories, so run a new dry run", len(ids))
+	}
+
+	deleted, err := s.memoryStore.DeleteMany(ctx, ids)
+	if len(deleted) > 0 {
+		targets := make([]string, len(deleted))
+		for i, id := range deleted {
+			targets[i] = auditMemoryID(id)
+		}
+		s.recordAudit(ctx, models.AuditOpMemoryBulkDelete, f.Project, describeBulkFilter(f), targets...)
+	}
+	if err != nil {
+		return "", fmt.Errorf("bulk_delete: deleted %d of %d memories: %w", len(deleted), len(ids), err)
+	}
+	out["deleted"] = len(deleted)
+	out["message"] = fmt.Sprintf("Deleted %d memories", len(deleted))
+	return marshalBulkResult(out)
+}
+
+// describeBulkFilter summarizes a filter for the audit log.
+func describeBulkFilter(f gorm.MemoryFilter) string {
+	parts := []string{"project=" + f.Project}
+	if len(f.Tags) > 0 {
+		parts = append(parts, "tags="+strings.Join(f.Tags, ","))
+	}
+	if !f.Since.IsZero() {
+		parts = append(parts, "since="+f.Since.UTC().Format(time.RFC3339))
+	}
+	if !f.Until.IsZero() {
+		parts = append(parts, "until="+f.Until.UTC().Format(time.RFC3339))
+	}
+	return "filter: " + strings.Join(parts, " ")
+}
+
+func marshalBulkResult(out map[string]any) (string, error) {
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal result: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBulkFilter(t *testing.T) {
+	f, err := parseBulkFilter(map[string]any{
+		"project":  "engram",
+		"obs_type": "change",
+		"tags":     []any{"commit"},
+		"concepts": []any{"auth", "security"},
+		"since":    "2026-01-01",
+		"until":    "2026-02-01T12:00:00Z",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "engram", f.Project)
+	assert.Equal(t, []string{"type:change", "commit", "auth", "security"}, f.Tags)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), f.Since)
+	assert.Equal(t, time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC), f.Until)
+
+	for name, args := range map[string]map[string]any{
+		"project is required": {"type": "change"},
+		"score_below":         {"project": "p", "score_below": 0.2},
+		"until must be after": {"project": "p", "since": "2026-02-01", "until": "2026-01-01"},
+		"since:":              {"project": "p", "since": "last week"},
+	} {
+		_, err := parseBulkFilter(args)
+		assert.ErrorContains(t, err, name)
+	}
+}
+
+func TestBulkConfirmToken(t *testing.T) {
+	f, err := parseBulkFilter(map[string]any{"project": "p", "type": "change"})
+	require.NoError(t, err)
+
+	token := bulkConfirmToken("bulk_delete", f, []int64{1, 2, 3})
+	assert.Equal(t, token, bulkConfirmToken("bulk_delete", f, []int64{1, 2, 3}))
+	assert.NotEqual(t, token, bulkConfirmToken("bulk_delete", f, []int64{1, 2, 3, 4}), "a memory matching after the dry run invalidates the token")
+
+	other := f
+	other.Tags = []string{"type:decision"}
+	assert.NotEqual(t, token, bulkConfirmToken("bulk_delete", other, []int64{1, 2, 3}), "the token is bound to the filter")
+}
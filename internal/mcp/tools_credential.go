@@ -284,6 +284,7 @@ func (s *Server) handleDeleteCredential(ctx context.Context, args json.RawMessag
 		}
 		return "", fmt.Errorf("delete credential: %w", err)
 	}
+	s.recordAudit(ctx, models.AuditOpCredentialDelete, params.Project, "credential "+params.Name, params.Name)
 
 	result := map[string]any{
 		"deleted": true,
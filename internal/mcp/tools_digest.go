@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/internal/digest"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// handleGetDigest returns the weekly or monthly digest of a project (see
+// package digest). The digest of a completed period is stored as a memory
+// the first time it is requested and returned from there afterwards, unless
+// regenerate is set. The current period is rebuilt on every call and never
+// stored, since it is still changing. In read-only mode nothing is stored.
+func (s *Server) handleGetDigest(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryStore == nil {
+		return "", fmt.Errorf("get_digest: memory store not configured")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	project := coerceString(m["project"], "")
+	if project == "" {
+		project = projectFromContext(ctx)
+	}
+	if project == "" {
+		return "", fmt.Errorf("get_digest: project is required")
+	}
+	now := time.Now().UTC()
+	p, err := digest.ParsePeriod(digest.Kind(coerceString(m["kind"], string(digest.Weekly))), coerceString(m["period"], ""), now)
+	if err != nil {
+		return "", fmt.Errorf("get_digest: %w", err)
+	}
+
+	var stored *models.Memory
+	if p.Complete(now) {
+		existing, err := s.memoryStore.MatchMemories(ctx, gorm.MemoryFilter{Project: project, Tags: p.Tags()})
+		if err != nil {
+			return "", fmt.Errorf("get_digest: %w", err)
+		}
+		if len(existing) > 0 {
+			stored = existing[len(existing)-1]
+			if !coerceBool(m["regenerate"], false) {
+				return stored.Content, nil
+			}
+		}
+	}
+
+	// Both reads are scoped to the caller's tenant, which is also the tenant
+	// the digest is stored in.
+	var sessions []*models.SDKSession
+	if s.sessionStore != nil {
+		sessions, _, err = s.sessionStore.ListSDKSessions(ctx, project, gorm.MaxPaginationLimit, 0, 0,
+			p.Start.UnixMilli(), p.End.UnixMilli()-1, "")
+		if err != nil {
+			return "", fmt.Errorf("get_digest: list sessions: %w", err)
+		}
+	}
+	memories, err := s.memoryStore.MatchMemories(ctx, gorm.MemoryFilter{Project: project, Since: p.Start, Until: p.End})
+	if err != nil {
+		return "", fmt.Errorf("get_digest: %w", err)
+	}
+	content := digest.Build(project, p, sessions, memories, digest.Options{MaxPerSection: coerceInt(m["max_per_section"], 0)})
+
+	if !p.Complete(now) || s.readOnlyReason(ctx) != "" {
+		return content, nil
+	}
+	if stored != nil {
+		if stored.Content != content {
+			updated := *stored
+			updated.Content = content
+			updated.EditedBy = "digest"
+			if _, err := s.memoryStore.Update(ctx, &updated); err != nil {
+				return "", fmt.Errorf("get_digest: store digest: %w", err)
+			}
+		}
+		return content, nil
+	}
+	if _, err := s.memoryStore.Create(ctx, &models.Memory{
+		Project:     project,
+		Content:     content,
+		Tags:        p.Tags(),
+		SourceAgent: "digest",
+	}); err != nil {
+		return "", fmt.Errorf("get_digest: store digest: %w", err)
+	}
+	return content, nil
+}
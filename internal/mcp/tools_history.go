@@ -0,0 +1,285 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	gormlib "gorm.io/gorm"
+
+	"github.com/thebtf/engram/internal/privacy"
+	"github.com/thebtf/engram/pkg/models"
+	"github.com/thebtf/engram/pkg/strutil"
+)
+
+// memoryActor resolves the actor recorded on memory revisions: an explicit
+// edited_by argument wins, then the caller's project identity, then "agent".
+func memoryActor(ctx context.Context, m map[string]any) string {
+	if actor := strings.TrimSpace(coerceString(m["edited_by"], "")); actor != "" {
+		return actor
+	}
+	if project := projectFromContext(ctx); project != "" {
+		return "agent:" + project
+	}
+	return "agent"
+}
+
+// handleEditMemory replaces the content and/or tags of a memory. The previous
+// state is kept in memory_revisions (see get_observation_history).
+// Accepts "content" or the legacy "narrative" field for the new text.
+func (s *Server) handleEditMemory(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryStore == nil {
+		return "", fmt.Errorf("memory store not available")
+	}
+
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+
+	id := coerceInt64(m["id"], 0)
+	if id <= 0 {
+		return "", fmt.Errorf("id is required and must be a positive integer")
+	}
+	content := coerceString(m["content"], "")
+	if content == "" {
+		content = coerceString(m["narrative"], "")
+	}
+	_, hasTags := m["tags"]
+	if content == "" && !hasTags {
+		return "", fmt.Errorf("edit requires content (or narrative) and/or tags")
+	}
+
+	current, err := s.memoryStore.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, gormlib.ErrRecordNotFound) {
+			return "", fmt.Errorf("memory %d not found", id)
+		}
+		return "", fmt.Errorf("edit memory: %w", err)
+	}
+
+	next := &models.Memory{
+		ID:          id,
+		Content:     current.Content,
+		Tags:        current.Tags,
+		SourceAgent: current.SourceAgent,
+		EditedBy:    memoryActor(ctx, m),
+	}
+	if content != "" {
+		content = privacy.RedactForStorage(current.Project, content)
+		next.Content = content
+	}
+	if hasTags {
+		next.Tags = coerceStringSlice(m["tags"])
+	}
+
+	updated, err := s.memoryStore.Update(ctx, next)
+	if err != nil {
+		return "", fmt.Errorf("edit memory: %w", err)
+	}
+	if content != "" && content != current.Content {
+		s.recordAudit(ctx, models.AuditOpMemoryEdit, current.Project, current.Content, auditMemoryID(id))
+	}
+	return marshalMemoryChange(updated, "Memory updated")
+}
+
+// handleTagObservation adds and/or removes tags on a memory without touching its
+// content. Recorded as a "tag" revision.
+func (s *Server) handleTagObservation(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryStore == nil {
+		return "", fmt.Errorf("memory store not available")
+	}
+
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+
+	id := coerceInt64(m["id"], 0)
+	if id <= 0 {
+		return "", fmt.Errorf("id is required and must be a positive integer")
+	}
+	add := coerceStringSlice(m["add"])
+	remove := coerceStringSlice(m["remove"])
+	if len(add) == 0 && len(remove) == 0 {
+		return "", fmt.Errorf("tag_observation requires add and/or remove")
+	}
+
+	current, err := s.memoryStore.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, gormlib.ErrRecordNotFound) {
+			return "", fmt.Errorf("memory %d not found", id)
+		}
+		return "", fmt.Errorf("tag memory: %w", err)
+	}
+
+	removeSet := make(map[string]bool, len(remove))
+	for _, tag := range remove {
+		removeSet[tag] = true
+	}
+	seen := make(map[string]bool, len(current.Tags)+len(add))
+	tags := make([]string, 0, len(current.Tags)+len(add))
+	for _, tag := range append(append([]string{}, current.Tags...), add...) {
+		if removeSet[tag] || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+
+	updated, err := s.memoryStore.UpdateWithAction(ctx, &models.Memory{
+		ID:          id,
+		Content:     current.Content,
+		Tags:        tags,
+		SourceAgent: current.SourceAgent,
+		EditedBy:    memoryActor(ctx, m),
+	}, models.RevisionActionTag)
+	if err != nil {
+		return "", fmt.Errorf("tag memory: %w", err)
+	}
+	return marshalMemoryChange(updated, "Memory tags updated")
+}
+
+// handleGetObservationHistory returns the revision history of a memory, newest
+// first, with a line diff from each revision to the version that replaced it.
+func (s *Server) handleGetObservationHistory(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryStore == nil {
+		return "", fmt.Errorf("memory store not available")
+	}
+
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+
+	id := coerceInt64(m["id"], 0)
+	if id <= 0 {
+		return "", fmt.Errorf("id is required and must be a positive integer")
+	}
+	limit := coerceInt(m["limit"], 20)
+	if limit <= 0 || limit > 200 {
+		limit = 20
+	}
+	withDiff := coerceBool(m["diff"], true)
+
+	current, err := s.memoryStore.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, gormlib.ErrRecordNotFound) {
+			return "", fmt.Errorf("memory %d not found", id)
+		}
+		return "", fmt.Errorf("get history: %w", err)
+	}
+	revisions, err := s.memoryStore.ListRevisions(ctx, id, limit)
+	if err != nil {
+		return "", fmt.Errorf("get history: %w", err)
+	}
+
+	type revisionEntry struct {
+		CreatedAt time.Time `json:"replaced_at"`
+		Action    string    `json:"replaced_by_action"`
+		Actor     string    `json:"replaced_by,omitempty"`
+		Content   string    `json:"content"`
+		Diff      string    `json:"diff,omitempty"`
+		Tags      []string  `json:"tags"`
+		Version   int       `json:"version"`
+	}
+
+	// Revisions are newest-first, so the "next" state of revisions[i] is
+	// revisions[i-1] (or the current row for i == 0).
+	nextContent := current.Content
+	entries := make([]revisionEntry, 0, len(revisions))
+	for _, rev := range revisions {
+		entry := revisionEntry{
+			Version:   rev.Version,
+			Content:   rev.Content,
+			Tags:      rev.Tags,
+			Action:    string(rev.Action),
+			Actor:     rev.Actor,
+			CreatedAt: rev.CreatedAt,
+		}
+		if withDiff && rev.Content != nextContent {
+			entry.Diff = strutil.LineDiff(rev.Content, nextContent)
+		}
+		entries = append(entries, entry)
+		nextContent = rev.Content
+	}
+
+	out := map[string]any{
+		"id": current.ID,
+		"current": map[string]any{
+			"version":    current.Version,
+			"content":    current.Content,
+			"tags":       current.Tags,
+			"edited_by":  current.EditedBy,
+			"updated_at": current.UpdatedAt,
+		},
+		"revisions": entries,
+		"count":     len(entries),
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return "", fmt.Errorf("marshal history: %w", err)
+	}
+	return string(data), nil
+}
+
+// handleRevertObservation restores a memory to the content and tags it had at
+// an earlier version. The revert is itself recorded as a new revision.
+func (s *Server) handleRevertObservation(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryStore == nil {
+		return "", fmt.Errorf("memory store not available")
+	}
+
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+
+	id := coerceInt64(m["id"], 0)
+	if id <= 0 {
+		return "", fmt.Errorf("id is required and must be a positive integer")
+	}
+	version := coerceInt(m["version"], 0)
+	if version <= 0 {
+		return "", fmt.Errorf("version is required and must be a positive integer")
+	}
+
+	current, err := s.memoryStore.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, gormlib.ErrRecordNotFound) {
+			return "", fmt.Errorf("memory %d not found", id)
+		}
+		return "", fmt.Errorf("revert memory: %w", err)
+	}
+
+	reverted, err := s.memoryStore.Revert(ctx, id, version, memoryActor(ctx, m))
+	if err != nil {
+		if errors.Is(err, gormlib.ErrRecordNotFound) {
+			return "", fmt.Errorf("memory %d has no revision at version %d", id, version)
+		}
+		return "", fmt.Errorf("revert memory: %w", err)
+	}
+	s.recordAudit(ctx, models.AuditOpMemoryRevert, current.Project,
+		fmt.Sprintf("v%d: %s", current.Version, current.Content), auditMemoryID(id))
+	return marshalMemoryChange(reverted, fmt.Sprintf("Memory reverted to the content of version %d", version))
+}
+
+// marshalMemoryChange renders the standard response for memory-modifying tools.
+func marshalMemoryChange(mem *models.Memory, message string) (string, error) {
+	out := map[string]any{
+		"id":        mem.ID,
+		"version":   mem.Version,
+		"content":   mem.Content,
+		"tags":      mem.Tags,
+		"edited_by": mem.EditedBy,
+		"message":   message,
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal result: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/thebtf/engram/internal/worker/jobs"
+)
+
+// SetJobManager wires the background job manager and the maintenance job body.
+// It enables admin(action="maintenance") and the get_job_status / cancel_job tools.
+func (s *Server) SetJobManager(m *jobs.Manager, maintenance jobs.Func) {
+	s.jobManager = m
+	s.maintenanceFunc = maintenance
+}
+
+// handleAdminMaintenance starts the maintenance job and returns its job_id
+// without waiting for it. A maintenance run already in progress is returned
+// instead of starting a second one.
+func (s *Server) handleAdminMaintenance() (string, error) {
+	if s.jobManager == nil || s.maintenanceFunc == nil {
+		return "", fmt.Errorf("background jobs not available")
+	}
+	job, err := s.jobManager.Submit("maintenance", s.maintenanceFunc)
+	message := "Maintenance started; poll get_job_status with job_id"
+	if errors.Is(err, jobs.ErrAlreadyRunning) {
+		message = "Maintenance is already running; poll get_job_status with job_id"
+	} else if err != nil {
+		return "", fmt.Errorf("start maintenance: %w", err)
+	}
+	return marshalJobResult(job, message)
+}
+
+// handleGetJobStatus returns the status, progress and outcome of a job.
+func (s *Server) handleGetJobStatus(args json.RawMessage) (string, error) {
+	if s.jobManager == nil {
+		return "", fmt.Errorf("background jobs not available")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	id := coerceString(m["job_id"], "")
+	if id == "" {
+		return "", fmt.Errorf("job_id is required")
+	}
+	job, err := s.jobManager.Get(id)
+	if err != nil {
+		return "", fmt.Errorf("job %q: %w", id, err)
+	}
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal job: %w", err)
+	}
+	return string(data), nil
+}
+
+// handleCancelJob requests cancellation of a running job.
+func (s *Server) handleCancelJob(args json.RawMessage) (string, error) {
+	if s.jobManager == nil {
+		return "", fmt.Errorf("background jobs not available")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	id := coerceString(m["job_id"], "")
+	if id == "" {
+		return "", fmt.Errorf("job_id is required")
+	}
+	job, err := s.jobManager.Cancel(id)
+	if err != nil {
+		return "", fmt.Errorf("job %q: %w", id, err)
+	}
+	return marshalJobResult(job, "Cancellation requested; the job reports status cancelled once it stops")
+}
+
+func marshalJobResult(job jobs.Job, message string) (string, error) {
+	data, err := json.MarshalIndent(map[string]any{
+		"job_id":  job.ID,
+		"kind":    job.Kind,
+		"status":  job.Status,
+		"message": message,
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal job: %w", err)
+	}
+	return string(data), nil
+}
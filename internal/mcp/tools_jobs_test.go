@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/worker/jobs"
+)
+
+func TestJobTools_MaintenanceLifecycle(t *testing.T) {
+	manager := jobs.NewManager(10)
+	defer manager.Close(context.Background())
+
+	s := &Server{}
+	s.SetJobManager(manager, func(ctx context.Context, progress func(string)) (any, error) {
+		progress("analyzing tables")
+		return map[string]any{"steps": []string{"analyze"}}, nil
+	})
+
+	out, err := s.handleAdmin(context.Background(), json.RawMessage(`{"action":"maintenance"}`))
+	require.NoError(t, err)
+	var started map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &started))
+	jobID, _ := started["job_id"].(string)
+	require.NotEmpty(t, jobID)
+
+	var status jobs.Job
+	require.Eventually(t, func() bool {
+		out, err := s.handleGetJobStatus(json.RawMessage(`{"job_id":"` + jobID + `"}`))
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal([]byte(out), &status))
+		return status.Status.Done()
+	}, 2*time.Second, 5*time.Millisecond)
+	assert.Equal(t, jobs.StatusSucceeded, status.Status)
+	assert.Equal(t, "analyzing tables", status.Progress)
+
+	_, err = s.handleCancelJob(json.RawMessage(`{"job_id":"` + jobID + `"}`))
+	assert.ErrorIs(t, err, jobs.ErrFinished)
+
+	_, err = s.handleGetJobStatus(json.RawMessage(`{"job_id":"job_missing"}`))
+	assert.ErrorIs(t, err, jobs.ErrNotFound)
+}
+
+func TestJobTools_Unavailable(t *testing.T) {
+	s := &Server{}
+	_, err := s.handleAdmin(context.Background(), json.RawMessage(`{"action":"maintenance"}`))
+	assert.Error(t, err)
+	_, err = s.handleGetJobStatus(json.RawMessage(`{"job_id":"x"}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	gormlib "gorm.io/gorm"
+)
+
+// handleLockObservation locks or unlocks a memory. A locked memory cannot be
+// edited, tagged, merged, reverted or deleted by anyone, agents and
+// maintenance jobs included, until it is unlocked with this tool.
+func (s *Server) handleLockObservation(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryStore == nil {
+		return "", fmt.Errorf("memory store not available")
+	}
+
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+
+	id := coerceInt64(m["id"], 0)
+	if id <= 0 {
+		return "", fmt.Errorf("id is required and must be a positive integer")
+	}
+	locked := coerceBool(m["locked"], true)
+
+	updated, err := s.memoryStore.SetLocked(ctx, id, locked)
+	if err != nil {
+		if errors.Is(err, gormlib.ErrRecordNotFound) {
+			return "", fmt.Errorf("memory %d not found", id)
+		}
+		return "", fmt.Errorf("lock memory: %w", err)
+	}
+
+	message := "Memory locked: it cannot be edited, merged or deleted until unlocked"
+	if !locked {
+		message = "Memory unlocked"
+	}
+	out := map[string]any{
+		"id":      updated.ID,
+		"project": updated.Project,
+		"locked":  updated.Locked,
+		"message": message,
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal result: %w", err)
+	}
+	return string(data), nil
+}
@@ -6,12 +6,16 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	gormlib "gorm.io/gorm"
 
 	"github.com/rs/zerolog/log"
+	"github.com/thebtf/engram/internal/capture"
+	"github.com/thebtf/engram/internal/concepts"
 	"github.com/thebtf/engram/internal/config"
+	"github.com/thebtf/engram/internal/db/gorm"
 	"github.com/thebtf/engram/internal/privacy"
 	"github.com/thebtf/engram/pkg/models"
 )
@@ -56,7 +60,11 @@ func (s *Server) handleStoreMemory(ctx context.Context, args json.RawMessage) (s
 		Type         string
 		Scope        string
 		Project      string
+		Workspace    string
+		Language     string
 		AgentSource  string
+		Polarity     string
+		Visibility   string
 		Importance   *float64
 		TtlDays      *int
 		AlwaysInject bool
@@ -68,6 +76,8 @@ func (s *Server) handleStoreMemory(ctx context.Context, args json.RawMessage) (s
 	params.Type = coerceString(m["type"], "")
 	params.Scope = coerceString(m["scope"], "")
 	params.AgentSource = coerceString(m["agent_source"], "")
+	params.Polarity = coerceString(m["polarity"], "")
+	params.Visibility = coerceString(m["visibility"], "")
 	if config.Get().EnforceSourceProject {
 		params.Project = projectFromContext(ctx)
 		if params.Project == "" {
@@ -76,6 +86,8 @@ func (s *Server) handleStoreMemory(ctx context.Context, args json.RawMessage) (s
 	} else {
 		params.Project = coerceString(m["project"], "")
 	}
+	params.Workspace = coerceString(m["workspace"], "")
+	params.Language = strings.ToLower(coerceString(m["language"], ""))
 	params.AlwaysInject = coerceBool(m["always_inject"], false)
 	if v, ok := m["importance"]; ok && v != nil {
 		f := coerceFloat64(v, 0)
@@ -93,6 +105,32 @@ func (s *Server) handleStoreMemory(ctx context.Context, args json.RawMessage) (s
 	if params.Importance != nil && (*params.Importance < 0 || *params.Importance > 1) {
 		return "", fmt.Errorf("importance must be between 0 and 1")
 	}
+	if params.Language != "" && !isLanguageCode(params.Language) {
+		return "", fmt.Errorf("invalid language %q: use an ISO 639-1 code such as en or de", params.Language)
+	}
+	if params.Polarity != "" && !models.IsValidPolarity(params.Polarity) {
+		return "", fmt.Errorf("invalid polarity %q: must be one of positive, caution, do_not", params.Polarity)
+	}
+	if params.Visibility != "" && !models.IsValidVisibility(params.Visibility) {
+		return "", fmt.Errorf("invalid visibility %q: must be one of private, team, public", params.Visibility)
+	}
+
+	// Structured sections (fields, and rejected alternatives for decisions) are
+	// appended to the content as labelled lines so schema validation, recall and
+	// quality scoring all see them.
+	rejectedStored := false
+	if schema, ok := models.SchemaFor(models.ObservationType(params.Type)); ok {
+		sections, err := schemaSectionsFromArgs(schema, m["fields"], params.Rejected)
+		if err != nil {
+			return "", err
+		}
+		if len(sections) > 0 {
+			params.Content = strings.TrimRight(params.Content, "\n") + "\n\n" + strings.Join(sections, "\n")
+			rejectedStored = len(params.Rejected) > 0
+		}
+	} else if fields, ok := m["fields"].(map[string]any); ok && len(fields) > 0 {
+		return "", fmt.Errorf("fields require a type with a schema (%s); see get_observation_schema", joinObservationTypes(models.SchemaTypes()))
+	}
 
 	cfg := config.Get()
 	hardLimit := cfg.StoreMemoryHardLimit
@@ -113,23 +151,48 @@ func (s *Server) handleStoreMemory(ctx context.Context, args json.RawMessage) (s
 			Msg("store_memory: content truncated to soft limit")
 	}
 
-	if privacy.ContainsSecrets(params.Content) {
-		log.Warn().Msg("store_memory: content contains secrets — redacting before storage")
-		params.Content = privacy.RedactSecrets(params.Content)
-	}
-
 	resolvedScope := params.Scope
 	if resolvedScope == "" {
 		resolvedScope = string(models.ScopeProject)
 	}
-	if resolvedScope != string(models.ScopeProject) && resolvedScope != string(models.ScopeGlobal) {
-		return "", fmt.Errorf("invalid scope %q: must be one of project, global", resolvedScope)
+	switch models.ObservationScope(resolvedScope) {
+	case models.ScopeProject, models.ScopeGlobal:
+	case models.ScopeWorkspace:
+		// Workspace memories live under the workspace root's project id and
+		// are injected into every project inside that workspace.
+		if params.Workspace == "" {
+			return "", fmt.Errorf("workspace is required for scope=workspace (the project id of the monorepo root)")
+		}
+		if params.AlwaysInject {
+			return "", fmt.Errorf("always_inject is not supported with scope=workspace")
+		}
+		params.Project = params.Workspace
+	default:
+		return "", fmt.Errorf("invalid scope %q: must be one of project, workspace, global", resolvedScope)
 	}
 
 	if params.Project == "" && !(params.AlwaysInject && resolvedScope == string(models.ScopeGlobal)) {
 		return "", fmt.Errorf("project is required for store_memory in v5 unless always_inject=true with scope=global")
 	}
 
+	attachments, err := parseAttachments(m["attachments"], params.Project)
+	if err != nil {
+		return "", err
+	}
+	if len(attachments) > 0 {
+		if params.AlwaysInject {
+			return "", fmt.Errorf("attachments are not supported with always_inject")
+		}
+		if s.attachmentStore == nil {
+			return "", fmt.Errorf("attachments not available: attachment store not configured")
+		}
+	}
+
+	if redacted := privacy.RedactForStorage(params.Project, params.Content); redacted != params.Content {
+		log.Warn().Str("project", params.Project).Msg("store_memory: content contains sensitive values — redacted before storage")
+		params.Content = redacted
+	}
+
 	obsTypeStr := params.Type
 	if obsTypeStr == "" {
 		cl := strings.ToLower(params.Content)
@@ -151,6 +214,24 @@ func (s *Server) handleStoreMemory(ctx context.Context, args json.RawMessage) (s
 		return "", fmt.Errorf("invalid type %q: must be one of decision, bugfix, feature, refactor, discovery, change, guidance, credential, entity, wiki, pitfall, operational, timeline", obsTypeStr)
 	}
 
+	// Schema validation: required sections are enforced only for an explicit
+	// type (inferred types are a guess) and only in strict mode.
+	var schemaWarnings []string
+	if schema, ok := models.SchemaFor(obsType); ok {
+		if missing := schema.MissingRequired(params.Content); len(missing) > 0 {
+			if params.Type != "" && cfg.ObservationSchemaStrict {
+				return "", fmt.Errorf("%s observations require: %s — add labelled lines (e.g. %q) or pass fields; see get_observation_schema",
+					obsTypeStr, sectionTitles(missing), missing[0].FormatSection("..."))
+			}
+			for _, sec := range missing {
+				schemaWarnings = append(schemaWarnings, "missing required section: "+sec.Title)
+			}
+		}
+		for _, sec := range schema.MissingRecommended(params.Content) {
+			schemaWarnings = append(schemaWarnings, "missing recommended section: "+sec.Title)
+		}
+	}
+
 	seen := make(map[string]bool)
 	tags := make([]string, 0, len(params.Tags)+3)
 	for _, tag := range params.Tags {
@@ -170,6 +251,11 @@ func (s *Server) handleStoreMemory(ctx context.Context, args json.RawMessage) (s
 		tags = append(tags, "scope:"+resolvedScope)
 		seen["scope:"+resolvedScope] = true
 	}
+	// Only warnings are tagged: a memory without a polarity tag is positive.
+	if polarity := models.Polarity(params.Polarity); polarity.IsWarning() && !seen[polarity.Tag()] {
+		tags = append(tags, polarity.Tag())
+		seen[polarity.Tag()] = true
+	}
 	if params.TtlDays != nil && !seen[fmt.Sprintf("ttl:%d", *params.TtlDays)] {
 		ttlTag := fmt.Sprintf("ttl:%d", *params.TtlDays)
 		tags = append(tags, ttlTag)
@@ -186,6 +272,17 @@ func (s *Server) handleStoreMemory(ctx context.Context, args json.RawMessage) (s
 		}
 	}
 
+	// Memories stored without a concept get the ones their content suggests,
+	// before the capture policy sees the tags.
+	var extracted []concepts.Concept
+	if cfg.ConceptExtraction {
+		tags, extracted = concepts.Augment(tags, params.Content, cfg.ConceptMinConfidence)
+	}
+
+	if decision := capture.Evaluate(cfg.CapturePolicy, params.Project, capture.Event{Concepts: tags}); !decision.Allowed {
+		return "", fmt.Errorf("store_memory: blocked by capture policy: %s", decision.Reason())
+	}
+
 	if params.AlwaysInject {
 		if s.behavioralRulesStore == nil {
 			return "", fmt.Errorf("always_inject=true requires behavioral rules store")
@@ -230,16 +327,35 @@ func (s *Server) handleStoreMemory(ctx context.Context, args json.RawMessage) (s
 		}
 	}
 
+	// Quality gate: memories scoring below the floor are stored but held back
+	// from lists, search and injection until reviewed (review_observation).
+	var quality map[string]any
+	pendingReview := false
+	if cfg.QualityGateFloor > 0 {
+		quality = observationQuality(obsType, params.Content, tags)
+		if score, _ := quality["score"].(float64); score < cfg.QualityGateFloor {
+			pendingReview = true
+		}
+	}
+
 	memory := &models.Memory{
-		Project:     params.Project,
-		Content:     params.Content,
-		Tags:        tags,
-		SourceAgent: agentSource,
+		Project:       params.Project,
+		Content:       params.Content,
+		Tags:          tags,
+		SourceAgent:   agentSource,
+		Language:      params.Language,
+		Visibility:    models.Visibility(params.Visibility),
+		PendingReview: pendingReview,
 	}
 	created, err := s.memoryStore.Create(ctx, memory)
 	if err != nil {
 		return "", fmt.Errorf("store memory: %w", err)
 	}
+	if len(attachments) > 0 {
+		if err := s.attachmentStore.Create(ctx, created.ID, attachments); err != nil {
+			return "", fmt.Errorf("store memory %d attachments: %w", created.ID, err)
+		}
+	}
 
 	result := map[string]any{
 		"id":      created.ID,
@@ -249,14 +365,42 @@ func (s *Server) handleStoreMemory(ctx context.Context, args json.RawMessage) (s
 		"storage": "memories",
 		"message": "Memory stored successfully",
 	}
+	if resolvedScope == string(models.ScopeWorkspace) {
+		result["workspace"] = params.Workspace
+	}
+	if created.Language != "" {
+		result["language"] = created.Language
+	}
+	if polarity := models.PolarityFromTags(created.Tags); polarity.IsWarning() {
+		result["polarity"] = string(polarity)
+	}
+	if created.Visibility != models.VisibilityTeam {
+		result["visibility"] = string(created.Visibility)
+	}
 	if ttlApplied {
 		result["ttl_days"] = ttlDays
 	}
 	if params.Importance != nil {
 		result["importance_note"] = "importance metadata is not stored in v5 memories schema"
 	}
-	if len(params.Rejected) > 0 {
-		result["rejected_note"] = "rejected alternatives are not stored in v5 memories schema"
+	if len(params.Rejected) > 0 && !rejectedStored {
+		result["rejected_note"] = "rejected alternatives are only stored for decision observations"
+	}
+	if len(attachments) > 0 {
+		result["attachments"] = len(attachments)
+	}
+	if len(extracted) > 0 {
+		result["concepts_extracted"] = concepts.Names(extracted)
+	}
+	if len(schemaWarnings) > 0 {
+		result["schema_warnings"] = schemaWarnings
+	}
+	if created.PendingReview {
+		result["pending_review"] = true
+		result["quality_score"] = quality["score"]
+		result["quality_floor"] = cfg.QualityGateFloor
+		result["suggestions"] = quality["suggestions"]
+		result["message"] = "Memory stored for review: its quality score is below the gate floor, so it is not searchable until approved or enriched with review_observation"
 	}
 	out, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
@@ -439,13 +583,7 @@ func (s *Server) handleRecallMemory(ctx context.Context, args json.RawMessage) (
 		}
 		items := make([]item, 0, len(filtered))
 		for _, mem := range filtered {
-			memoryType := ""
-			for _, tag := range mem.Tags {
-				if strings.HasPrefix(tag, "type:") {
-					memoryType = strings.TrimPrefix(tag, "type:")
-					break
-				}
-			}
+			memoryType := string(models.TypeFromTags(mem.Tags))
 			items = append(items, item{
 				ID:          mem.ID,
 				Title:       truncateTitle(mem.Content, 80),
@@ -462,8 +600,28 @@ func (s *Server) handleRecallMemory(ctx context.Context, args json.RawMessage) (
 		}
 		return string(out), nil
 
+	case "digest":
+		return formatMemoryDigest(filtered, time.Now()), nil
+
 	case "detailed":
-		out, err := json.MarshalIndent(filtered, "", "  ")
+		detailed := make([]detailedMemory, len(filtered))
+		for i, mem := range filtered {
+			detailed[i] = detailedMemory{Memory: mem}
+		}
+		if s.attachmentStore != nil && len(filtered) > 0 {
+			ids := make([]int64, len(filtered))
+			for i, mem := range filtered {
+				ids[i] = mem.ID
+			}
+			byMemory, err := s.attachmentStore.ListByMemories(ctx, ids, true)
+			if err != nil {
+				return "", fmt.Errorf("recall_memory: %w", err)
+			}
+			for i := range detailed {
+				detailed[i].Attachments = byMemory[detailed[i].ID]
+			}
+		}
+		out, err := json.MarshalIndent(detailed, "", "  ")
 		if err != nil {
 			return "", fmt.Errorf("marshal result: %w", err)
 		}
@@ -477,11 +635,8 @@ func (s *Server) handleRecallMemory(ctx context.Context, args json.RawMessage) (
 		sb.WriteString(fmt.Sprintf("Found %d memories for query: %q\n\n", len(filtered), query))
 		for i, mem := range filtered {
 			typeLabel := "MEMORY"
-			for _, tag := range mem.Tags {
-				if strings.HasPrefix(tag, "type:") {
-					typeLabel = strings.ToUpper(strings.TrimPrefix(tag, "type:"))
-					break
-				}
+			if t := models.TypeFromTags(mem.Tags); t != "" {
+				typeLabel = strings.ToUpper(string(t))
 			}
 			sb.WriteString(fmt.Sprintf("%d. [%s] %s\n", i+1, typeLabel, truncateTitle(mem.Content, 80)))
 			content := mem.Content
@@ -498,7 +653,9 @@ func (s *Server) handleRecallMemory(ctx context.Context, args json.RawMessage) (
 	}
 }
 
-// handleRateMemory is kept explicit in v5: memories do not have a rating field yet.
+// handleRateMemory records a useful / not_useful rating against the session's
+// injection experiment arm. Memories have no rating field in v5, so a rating
+// without a session is explicitly unsupported.
 func (s *Server) handleRateMemory(ctx context.Context, args json.RawMessage) (string, error) {
 	m, err := parseArgs(args)
 	if err != nil {
@@ -524,7 +681,31 @@ func (s *Server) handleRateMemory(ctx context.Context, args json.RawMessage) (st
 		return "", fmt.Errorf("rating must be 'useful' or 'not_useful'")
 	}
 
-	return "", fmt.Errorf("rate_memory removed in v5 (US3): memories table has no rating field yet")
+	sessionID := coerceString(m["session_id"], "")
+	if sessionID == "" || s.experimentStore == nil {
+		return "", fmt.Errorf("rate_memory removed in v5 (US3): memories table has no rating field yet; pass session_id to attribute the rating to an injection experiment")
+	}
+
+	event := gorm.ExperimentEventNegativeFeedback
+	if rating == "useful" {
+		event = gorm.ExperimentEventPositiveFeedback
+	}
+	enrolled, err := s.experimentStore.RecordEvent(ctx, sessionID, event)
+	if err != nil {
+		return "", fmt.Errorf("record experiment feedback: %w", err)
+	}
+
+	out := map[string]any{
+		"id":                  id,
+		"rating":              rating,
+		"session_id":          sessionID,
+		"experiment_recorded": enrolled,
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return "", fmt.Errorf("marshal rating: %w", err)
+	}
+	return string(data), nil
 }
 
 // handleSuppressMemory suppresses a v5 memory via soft-delete in the memories table.
@@ -543,12 +724,21 @@ func (s *Server) handleSuppressMemory(ctx context.Context, args json.RawMessage)
 		return "", fmt.Errorf("id required")
 	}
 
+	mem, err := s.memoryStore.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, gormlib.ErrRecordNotFound) {
+			return "", fmt.Errorf("suppress_memory: memory %d not found", id)
+		}
+		return "", fmt.Errorf("suppress_memory: %w", err)
+	}
+
 	if err := s.memoryStore.Delete(ctx, id); err != nil {
 		if errors.Is(err, gormlib.ErrRecordNotFound) {
 			return "", fmt.Errorf("suppress_memory: memory %d not found", id)
 		}
 		return "", fmt.Errorf("suppress_memory: %w", err)
 	}
+	s.recordAudit(ctx, models.AuditOpMemoryDelete, mem.Project, mem.Content, auditMemoryID(id))
 
 	return fmt.Sprintf("Memory %d suppressed", id), nil
 }
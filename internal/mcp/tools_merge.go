@@ -0,0 +1,197 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	gormlib "gorm.io/gorm"
+
+	"github.com/rs/zerolog/log"
+	"github.com/thebtf/engram/internal/config"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// singleValuedTagPrefixes are tag namespaces a memory carries at most once.
+// When merging, the target keeps its own value for them.
+var singleValuedTagPrefixes = []string{"type:", "scope:", "polarity:", "ttl:"}
+
+// factKey normalizes a content line for duplicate detection: case, bullet
+// markers and whitespace do not make two facts different.
+func factKey(line string) string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimLeft(line, "-*• ")
+	return strings.ToLower(strings.Join(strings.Fields(line), " "))
+}
+
+// mergeMemoryContent appends to target the lines of source that target does
+// not already state, and returns the merged content with the number of lines
+// added. Source lines are appended as one block after a blank line.
+func mergeMemoryContent(target, source string) (string, int) {
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(target, "\n") {
+		if key := factKey(line); key != "" {
+			seen[key] = true
+		}
+	}
+	var added []string
+	for _, line := range strings.Split(source, "\n") {
+		key := factKey(line)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		added = append(added, strings.TrimRight(line, " \t\r"))
+	}
+	if len(added) == 0 {
+		return target, 0
+	}
+	return strings.TrimRight(target, "\n") + "\n\n" + strings.Join(added, "\n"), len(added)
+}
+
+// mergeMemoryTags returns the union of target and source tags, target first.
+// Concepts, file: tags and other multi-valued tags are unioned; for
+// single-valued namespaces (type:, scope:, ...) the target's value wins.
+func mergeMemoryTags(target, source []string) ([]string, int) {
+	seen := make(map[string]bool, len(target)+len(source))
+	merged := make([]string, 0, len(target)+len(source))
+	for _, tag := range target {
+		if !seen[tag] {
+			seen[tag] = true
+			merged = append(merged, tag)
+		}
+	}
+	added := 0
+	for _, tag := range source {
+		if seen[tag] || hasTagInNamespace(merged, tag) {
+			continue
+		}
+		seen[tag] = true
+		merged = append(merged, tag)
+		added++
+	}
+	return merged, added
+}
+
+// hasTagInNamespace reports whether tag is single-valued and tags already
+// carry a value in its namespace.
+func hasTagInNamespace(tags []string, tag string) bool {
+	for _, prefix := range singleValuedTagPrefixes {
+		if !strings.HasPrefix(tag, prefix) {
+			continue
+		}
+		for _, t := range tags {
+			if strings.HasPrefix(t, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// handleMergeMemories merges the source memory into the target: the source's
+// facts (content lines) the target lacks are appended, concepts and file tags
+// are unioned, a merged_from relation records the provenance, and the source
+// is deleted. The target's previous state is kept as a "merge" revision.
+func (s *Server) handleMergeMemories(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryStore == nil {
+		return "", fmt.Errorf("memory store not available")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	sourceID := coerceInt64(m["source_id"], 0)
+	targetID := coerceInt64(m["target_id"], 0)
+	if sourceID <= 0 || targetID <= 0 {
+		return "", fmt.Errorf("merge requires source_id and target_id")
+	}
+	if sourceID == targetID {
+		return "", fmt.Errorf("cannot merge memory %d into itself", sourceID)
+	}
+
+	load := func(id int64) (*models.Memory, error) {
+		mem, err := s.memoryStore.Get(ctx, id)
+		if errors.Is(err, gormlib.ErrRecordNotFound) {
+			return nil, fmt.Errorf("memory %d not found", id)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("merge memories: %w", err)
+		}
+		return mem, nil
+	}
+	source, err := load(sourceID)
+	if err != nil {
+		return "", err
+	}
+	target, err := load(targetID)
+	if err != nil {
+		return "", err
+	}
+	if source.Project != target.Project {
+		return "", fmt.Errorf("cannot merge across projects: memory %d is in %q, memory %d in %q",
+			sourceID, source.Project, targetID, target.Project)
+	}
+	for _, mem := range []*models.Memory{source, target} {
+		if mem.Locked {
+			return "", fmt.Errorf("memory %d is locked; unlock it with lock_observation before merging", mem.ID)
+		}
+	}
+
+	content, factsAdded := mergeMemoryContent(target.Content, source.Content)
+	hardLimit := config.Get().StoreMemoryHardLimit
+	if hardLimit <= 0 {
+		hardLimit = 10000
+	}
+	if utf8.RuneCountInString(content) > hardLimit {
+		return "", fmt.Errorf("merged content would exceed the maximum length of %d characters; edit the memories down first", hardLimit)
+	}
+	tags, tagsAdded := mergeMemoryTags(target.Tags, source.Tags)
+
+	updated, err := s.memoryStore.UpdateWithAction(ctx, &models.Memory{
+		ID:          targetID,
+		Content:     content,
+		Tags:        tags,
+		SourceAgent: target.SourceAgent,
+		EditedBy:    memoryActor(ctx, m),
+	}, models.RevisionActionMerge)
+	if err != nil {
+		return "", fmt.Errorf("merge memories: %w", err)
+	}
+
+	relationStored := false
+	if s.relationStore != nil {
+		relation := models.NewObservationRelation(targetID, sourceID, models.RelationMergedFrom, 1.0,
+			models.DetectionSourceManual, fmt.Sprintf("memory %d merged into %d", sourceID, targetID))
+		if _, err := s.relationStore.StoreRelation(ctx, relation); err != nil {
+			log.Warn().Err(err).Int64("source_id", sourceID).Int64("target_id", targetID).Msg("merge: failed to record merged_from relation")
+		} else {
+			relationStored = true
+		}
+	}
+
+	if err := s.memoryStore.Delete(ctx, sourceID); err != nil {
+		return "", fmt.Errorf("merged into memory %d but failed to delete memory %d: %w", targetID, sourceID, err)
+	}
+	s.recordAudit(ctx, models.AuditOpMemoryDelete, source.Project, source.Content, auditMemoryID(sourceID))
+
+	out := map[string]any{
+		"id":              updated.ID,
+		"version":         updated.Version,
+		"content":         updated.Content,
+		"tags":            updated.Tags,
+		"merged_from":     sourceID,
+		"facts_added":     factsAdded,
+		"tags_added":      tagsAdded,
+		"relation_stored": relationStored,
+		"message":         fmt.Sprintf("Memory %d merged into %d and deleted", sourceID, targetID),
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal result: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,32 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeMemoryContent(t *testing.T) {
+	target := "Use pgx for PostgreSQL.\n- Pool size is 10\n"
+	source := "use pgx for postgresql.\n  - pool size is   10\n- Prepared statements are cached\n\nRetries are capped at 3"
+
+	merged, added := mergeMemoryContent(target, source)
+	assert.Equal(t, 2, added)
+	assert.Equal(t, "Use pgx for PostgreSQL.\n- Pool size is 10\n\n- Prepared statements are cached\nRetries are capped at 3", merged)
+
+	same, added := mergeMemoryContent(target, "- Pool size is 10")
+	assert.Zero(t, added)
+	assert.Equal(t, target, same, "nothing new leaves the target untouched")
+}
+
+func TestMergeMemoryTags(t *testing.T) {
+	target := []string{"type:decision", "scope:project", "database", "file:internal/db/store.go"}
+	source := []string{"type:bugfix", "polarity:caution", "database", "performance", "file:internal/db/pool.go"}
+
+	merged, added := mergeMemoryTags(target, source)
+	assert.Equal(t, 3, added)
+	assert.Equal(t, []string{
+		"type:decision", "scope:project", "database", "file:internal/db/store.go",
+		"polarity:caution", "performance", "file:internal/db/pool.go",
+	}, merged)
+}
@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/thebtf/engram/internal/auth"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// handleMigrateProject moves a project's memories, rules, credentials,
+// sessions, issues and documents from old_project to new_project (default:
+// the calling session's project) and keeps old_project as an alias of it.
+// Use it after a directory move gave a project a new path-based ID. It
+// rewrites tables shared by every tenant, so it requires admin access.
+func (s *Server) handleMigrateProject(ctx context.Context, args json.RawMessage) (string, error) {
+	if id, ok := auth.IdentityFrom(ctx); ok && !id.IsAdmin() {
+		return "", fmt.Errorf("migrate_project: admin access required")
+	}
+	if s.memoryStore == nil {
+		return "", fmt.Errorf("memory store not available")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	oldID := coerceString(m["old_project"], "")
+	newID := coerceString(m["new_project"], projectFromContext(ctx))
+	if oldID == "" || newID == "" {
+		return "", fmt.Errorf("migrate_project: old_project and new_project are required (new_project defaults to the session's project)")
+	}
+
+	result, err := s.memoryStore.MigrateProject(ctx, oldID, newID)
+	if err != nil {
+		return "", fmt.Errorf("migrate_project: %w", err)
+	}
+	s.recordAudit(ctx, models.AuditOpProjectMigrate, newID, "migrated from "+oldID+": "+describeMigration(result.Moved), oldID)
+
+	out := map[string]any{
+		"old_project": oldID,
+		"new_project": newID,
+		"moved":       result.Moved,
+		"message":     fmt.Sprintf("Migrated %s to %s; %s now resolves to %s", oldID, newID, oldID, newID),
+	}
+	if len(result.Kept) > 0 {
+		out["kept"] = result.Kept
+		out["message"] = fmt.Sprintf("Migrated %s to %s; rows that already exist under %s stayed with %s (%s)",
+			oldID, newID, newID, oldID, describeMigration(result.Kept))
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal result: %w", err)
+	}
+	return string(data), nil
+}
+
+// describeMigration renders per-table row counts as "table=n", sorted by
+// table name.
+func describeMigration(counts map[string]int64) string {
+	if len(counts) == 0 {
+		return "nothing to move"
+	}
+	parts := make([]string, 0, len(counts))
+	for table, n := range counts {
+		parts = append(parts, fmt.Sprintf("%s=%d", table, n))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
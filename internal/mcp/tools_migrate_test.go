@@ -0,0 +1,37 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/auth"
+)
+
+func TestDescribeMigration(t *testing.T) {
+	assert.Equal(t, "nothing to move", describeMigration(nil))
+	assert.Equal(t, "issues=2 memories=14", describeMigration(map[string]int64{"memories": 14, "issues": 2}))
+}
+
+func TestMigrateProject_RequiresAdmin(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(ServerOptions{Version: "1.0.0"})
+	args := json.RawMessage(`{"old_project":"a","new_project":"b"}`)
+	client := auth.WithIdentity(context.Background(), auth.Identity{Role: auth.RoleReadWrite, Source: auth.SourceClient})
+	admin := auth.WithIdentity(context.Background(), auth.Identity{Role: auth.RoleAdmin, Source: auth.SourceMaster})
+
+	_, err := server.handleMigrateProject(client, args)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "admin access required")
+
+	// Admins and auth-disabled callers get past the check; no store is wired here.
+	for _, ctx := range []context.Context{admin, context.Background()} {
+		_, err = server.handleMigrateProject(ctx, args)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not available")
+	}
+}
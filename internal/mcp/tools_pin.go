@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	gormlib "gorm.io/gorm"
+
+	"github.com/thebtf/engram/internal/config"
+)
+
+// handlePinObservation pins or unpins a memory. Pinned memories are injected
+// into every context for their project ahead of scored results. Pinning is
+// refused once the project already has PinnedInjectLimit pinned memories, so
+// no pin is ever silently dropped from injection.
+func (s *Server) handlePinObservation(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryStore == nil {
+		return "", fmt.Errorf("memory store not available")
+	}
+
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+
+	id := coerceInt64(m["id"], 0)
+	if id <= 0 {
+		return "", fmt.Errorf("id is required and must be a positive integer")
+	}
+	pinned := coerceBool(m["pinned"], true)
+
+	mem, err := s.memoryStore.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, gormlib.ErrRecordNotFound) {
+			return "", fmt.Errorf("memory %d not found", id)
+		}
+		return "", fmt.Errorf("get memory: %w", err)
+	}
+
+	limit := 10
+	if cfg := config.Get(); cfg != nil && cfg.PinnedInjectLimit > 0 {
+		limit = cfg.PinnedInjectLimit
+	}
+	if pinned && !mem.Pinned {
+		count, err := s.memoryStore.CountPinned(ctx, mem.Project)
+		if err != nil {
+			return "", err
+		}
+		if count >= int64(limit) {
+			return "", fmt.Errorf("project %q already has %d pinned memories (limit %d); unpin one first or raise ENGRAM_PINNED_INJECT_LIMIT", mem.Project, count, limit)
+		}
+	}
+
+	updated, err := s.memoryStore.SetPinned(ctx, id, pinned)
+	if err != nil {
+		return "", fmt.Errorf("pin memory: %w", err)
+	}
+	count, err := s.memoryStore.CountPinned(ctx, updated.Project)
+	if err != nil {
+		return "", err
+	}
+
+	message := "Memory pinned: it will be injected into every context for this project"
+	if !pinned {
+		message = "Memory unpinned"
+	}
+	out := map[string]any{
+		"id":           updated.ID,
+		"project":      updated.Project,
+		"pinned":       updated.Pinned,
+		"pinned_count": count,
+		"pinned_limit": limit,
+		"message":      message,
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal result: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,206 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/thebtf/engram/pkg/models"
+	"github.com/thebtf/engram/pkg/similarity"
+)
+
+const (
+	// priorArtMemoryScanLimit and priorArtSessionScanLimit cap what one
+	// check_prior_art call reads before ranking.
+	priorArtMemoryScanLimit  = 500
+	priorArtSessionScanLimit = 200
+
+	// priorArtMinMatched is the fewest task terms an item must share to be
+	// reported at all.
+	priorArtMinMatched = 2
+
+	// Verdict thresholds on the best match's coverage of the task terms.
+	priorArtDoneCoverage    = 0.6
+	priorArtRelatedCoverage = 0.3
+)
+
+// Prior-art verdicts returned by check_prior_art.
+const (
+	verdictDoneBefore  = "likely-done-before"
+	verdictRelatedWork = "related-work"
+	verdictNovel       = "novel"
+)
+
+// priorArtTypes are the memory types that record work done or settled:
+// decisions and what was built or restructured.
+var priorArtTypes = map[models.ObservationType]bool{
+	models.ObsTypeDecision: true,
+	models.ObsTypeFeature:  true,
+	models.ObsTypeRefactor: true,
+}
+
+// priorArtMatch is one past memory or session similar to the task.
+type priorArtMatch struct {
+	Kind    string   `json:"kind"` // "memory" or "session"
+	Title   string   `json:"title"`
+	Type    string   `json:"type,omitempty"`
+	Outcome string   `json:"outcome,omitempty"`
+	Link    string   `json:"link"`
+	Shared  []string `json:"shared_terms"`
+	ID      int64    `json:"id"`
+	// Coverage is the fraction of the task's terms the item mentions.
+	Coverage float64 `json:"coverage"`
+	// Similarity is the term Jaccard between task and item.
+	Similarity float64 `json:"similarity"`
+}
+
+// scorePriorArt compares the task terms with an item's terms. ok is false
+// when they share fewer than priorArtMinMatched terms.
+func scorePriorArt(task, item map[string]bool) (m priorArtMatch, ok bool) {
+	for term := range item {
+		if task[term] {
+			m.Shared = append(m.Shared, term)
+		}
+	}
+	if len(m.Shared) < priorArtMinMatched {
+		return m, false
+	}
+	sort.Strings(m.Shared)
+	m.Coverage = roundScore(float64(len(m.Shared)) / float64(len(task)))
+	m.Similarity = roundScore(similarity.JaccardSimilarity(task, item))
+	return m, true
+}
+
+func roundScore(v float64) float64 {
+	return float64(int(v*100+0.5)) / 100
+}
+
+// priorArtVerdict classifies the task by its best match. A session alone
+// never proves the work was done unless it ended in success.
+func priorArtVerdict(matches []priorArtMatch) string {
+	verdict := verdictNovel
+	for _, m := range matches {
+		switch {
+		case m.Coverage >= priorArtDoneCoverage && (m.Kind == "memory" || m.Outcome == "success"):
+			return verdictDoneBefore
+		case m.Coverage >= priorArtRelatedCoverage:
+			verdict = verdictRelatedWork
+		}
+	}
+	return verdict
+}
+
+// rankPriorArt orders matches by coverage, then similarity, and keeps limit.
+func rankPriorArt(matches []priorArtMatch, limit int) []priorArtMatch {
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Coverage != matches[j].Coverage {
+			return matches[i].Coverage > matches[j].Coverage
+		}
+		return matches[i].Similarity > matches[j].Similarity
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// handleCheckPriorArt answers "have we done this before?" for a task
+// description: it ranks the project's decision, feature and refactor
+// memories and its past sessions (name, first prompt, tags) by the task
+// terms they share, and returns the best matches with links and a verdict
+// (likely-done-before, related-work or novel).
+func (s *Server) handleCheckPriorArt(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryStore == nil {
+		return "", fmt.Errorf("memory store not available")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	task := strings.TrimSpace(coerceString(m["task"], ""))
+	if task == "" {
+		return "", fmt.Errorf("task is required")
+	}
+	project := projectFromContext(ctx)
+	if project == "" {
+		project = coerceString(m["project"], "")
+	}
+	if project == "" {
+		return "", fmt.Errorf("project is required for check_prior_art")
+	}
+	limit := coerceInt(m["limit"], 5)
+	if limit <= 0 {
+		limit = 5
+	} else if limit > 20 {
+		limit = 20
+	}
+
+	taskTerms := similarity.ExtractTextTerms(task)
+	if len(taskTerms) < priorArtMinMatched {
+		return "", fmt.Errorf("task is too short to compare; describe it in a sentence")
+	}
+
+	var matches []priorArtMatch
+	mems, err := s.memoryStore.List(ctx, project, priorArtMemoryScanLimit)
+	if err != nil {
+		return "", fmt.Errorf("list memories: %w", err)
+	}
+	for _, mem := range mems {
+		obsType := models.TypeFromTags(mem.Tags)
+		if !priorArtTypes[obsType] {
+			continue
+		}
+		match, ok := scorePriorArt(taskTerms, similarity.ExtractMemoryTerms(mem))
+		if !ok {
+			continue
+		}
+		match.Kind = "memory"
+		match.ID = mem.ID
+		match.Type = string(obsType)
+		match.Title = truncateTitle(mem.Content, 100)
+		match.Link = fmt.Sprintf("/api/memories/%d", mem.ID)
+		matches = append(matches, match)
+	}
+
+	// ListSDKSessions is tenant-scoped, so other tenants' session names and
+	// prompts never appear here.
+	if s.sessionStore != nil {
+		sessions, _, err := s.sessionStore.ListSDKSessions(ctx, project, priorArtSessionScanLimit, 0, 0, 0, 0, "")
+		if err != nil {
+			return "", fmt.Errorf("list sessions: %w", err)
+		}
+		for _, sess := range sessions {
+			summary := strings.TrimSpace(sess.Name.String + "\n" + sess.UserPrompt.String + "\n" + strings.Join(sess.Tags, " "))
+			if summary == "" {
+				continue
+			}
+			match, ok := scorePriorArt(taskTerms, similarity.ExtractTextTerms(summary))
+			if !ok {
+				continue
+			}
+			match.Kind = "session"
+			match.ID = sess.ID
+			match.Outcome = sess.Outcome.String
+			match.Title = truncateTitle(strings.TrimSpace(sess.Name.String+" "+sess.UserPrompt.String), 100)
+			match.Link = "/api/sessions?claudeSessionId=" + url.QueryEscape(sess.ClaudeSessionID)
+			matches = append(matches, match)
+		}
+	}
+
+	matches = rankPriorArt(matches, limit)
+	out := map[string]any{
+		"project": project,
+		"task":    task,
+		"verdict": priorArtVerdict(matches),
+		"matches": matches,
+		"count":   len(matches),
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal result: %w", err)
+	}
+	return string(data), nil
+}
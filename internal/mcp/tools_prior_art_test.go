@@ -0,0 +1,44 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/similarity"
+)
+
+func TestScorePriorArt(t *testing.T) {
+	task := similarity.ExtractTextTerms("add rate limiting to the webhook endpoint")
+
+	match, ok := scorePriorArt(task, similarity.ExtractTextTerms("Decision: rate limiting for webhook delivery uses a token bucket"))
+	require.True(t, ok)
+	assert.Equal(t, []string{"limiting", "rate", "webhook"}, match.Shared)
+	assert.Equal(t, 0.6, match.Coverage)
+	assert.Greater(t, match.Similarity, 0.0)
+
+	_, ok = scorePriorArt(task, similarity.ExtractTextTerms("Refactored the webhook retry loop"))
+	assert.False(t, ok, "one shared term is not prior art")
+}
+
+func TestPriorArtVerdict(t *testing.T) {
+	assert.Equal(t, verdictNovel, priorArtVerdict(nil))
+	assert.Equal(t, verdictNovel, priorArtVerdict([]priorArtMatch{{Kind: "memory", Coverage: 0.2}}))
+	assert.Equal(t, verdictRelatedWork, priorArtVerdict([]priorArtMatch{{Kind: "memory", Coverage: 0.4}}))
+	assert.Equal(t, verdictDoneBefore, priorArtVerdict([]priorArtMatch{{Kind: "memory", Coverage: 0.8}}))
+	assert.Equal(t, verdictRelatedWork, priorArtVerdict([]priorArtMatch{{Kind: "session", Coverage: 0.8}}),
+		"a session without a successful outcome is only related work")
+	assert.Equal(t, verdictDoneBefore, priorArtVerdict([]priorArtMatch{{Kind: "session", Coverage: 0.8, Outcome: "success"}}))
+}
+
+func TestRankPriorArt(t *testing.T) {
+	ranked := rankPriorArt([]priorArtMatch{
+		{ID: 1, Coverage: 0.4, Similarity: 0.1},
+		{ID: 2, Coverage: 0.8, Similarity: 0.1},
+		{ID: 3, Coverage: 0.4, Similarity: 0.3},
+	}, 2)
+	require.Len(t, ranked, 2)
+	assert.Equal(t, int64(2), ranked[0].ID)
+	assert.Equal(t, int64(3), ranked[1].ID, "ties on coverage go to the more similar item")
+}
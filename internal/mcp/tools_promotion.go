@@ -0,0 +1,149 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	gormlib "gorm.io/gorm"
+
+	"github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// handleListPromotionCandidates is the review queue of cross-project
+// knowledge: project memories tagged with a globalizable concept (security,
+// best-practice, ...) that other projects keep reaching through search or
+// injection. promote_observation moves an accepted candidate to global scope.
+func (s *Server) handleListPromotionCandidates(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryAccessStore == nil {
+		return "", fmt.Errorf("promotion candidates not available: access counting not configured")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	days := coerceInt(m["days"], 30)
+	if days <= 0 {
+		days = 30
+	} else if days > 365 {
+		days = 365
+	}
+	minProjects := coerceInt(m["min_projects"], 2)
+	if minProjects <= 0 {
+		minProjects = 2
+	}
+	limit := coerceInt(m["limit"], 20)
+	if limit <= 0 {
+		limit = 20
+	} else if limit > 100 {
+		limit = 100
+	}
+	since := time.Now().UTC().AddDate(0, 0, -days+1)
+
+	candidates, err := s.memoryAccessStore.PromotionCandidates(ctx, gorm.PromotionQuery{
+		Project:     coerceString(m["project"], ""),
+		Concepts:    models.GlobalizableConcepts,
+		MinProjects: minProjects,
+		Since:       since,
+		Limit:       limit,
+	})
+	if err != nil {
+		return "", err
+	}
+	type entry struct {
+		gorm.PromotionCandidate
+		Concepts []string `json:"concepts"`
+	}
+	entries := make([]entry, len(candidates))
+	for i, c := range candidates {
+		entries[i] = entry{PromotionCandidate: c, Concepts: globalizableConcepts(c.Tags)}
+	}
+	out := map[string]any{
+		"since":        since.Format(time.DateOnly),
+		"days":         days,
+		"min_projects": minProjects,
+		"candidates":   entries,
+		"count":        len(entries),
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal promotion candidates: %w", err)
+	}
+	return string(data), nil
+}
+
+// handlePromoteObservation moves a memory to global scope: its scope:project
+// (or workspace, agent) tag is replaced by scope:global, so recall with
+// include_global returns it in every project of the tenant. The change is
+// recorded as a tag revision.
+func (s *Server) handlePromoteObservation(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryStore == nil {
+		return "", fmt.Errorf("memory store not available")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	id := coerceInt64(m["id"], 0)
+	if id <= 0 {
+		return "", fmt.Errorf("id is required and must be a positive integer")
+	}
+
+	current, err := s.memoryStore.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, gormlib.ErrRecordNotFound) {
+			return "", fmt.Errorf("memory %d not found", id)
+		}
+		return "", fmt.Errorf("promote memory: %w", err)
+	}
+	if current.Visibility == models.VisibilityPrivate {
+		return "", fmt.Errorf("memory %d is private; make it team-visible before promoting it", id)
+	}
+	global := "scope:" + string(models.ScopeGlobal)
+	tags := make([]string, 0, len(current.Tags)+1)
+	for _, tag := range current.Tags {
+		if tag == global {
+			return "", fmt.Errorf("memory %d is already global", id)
+		}
+		if scope, ok := strings.CutPrefix(tag, "scope:"); ok {
+			switch models.ObservationScope(scope) {
+			case models.ScopeProject, models.ScopeWorkspace, models.ScopeAgent:
+				continue // replaced by scope:global; path scopes (scope:backend) stay
+			}
+		}
+		tags = append(tags, tag)
+	}
+	tags = append(tags, global)
+
+	updated, err := s.memoryStore.UpdateWithAction(ctx, &models.Memory{
+		ID:          id,
+		Content:     current.Content,
+		Tags:        tags,
+		SourceAgent: current.SourceAgent,
+		EditedBy:    memoryActor(ctx, m),
+	}, models.RevisionActionTag)
+	if err != nil {
+		return "", fmt.Errorf("promote memory: %w", err)
+	}
+	return marshalMemoryChange(updated, "Memory promoted to global scope")
+}
+
+// globalizableConcepts returns the globalizable concepts among tags, with
+// any "concept:" prefix removed.
+func globalizableConcepts(tags []string) []string {
+	found := []string{}
+	for _, tag := range tags {
+		tag = strings.TrimPrefix(strings.ToLower(tag), "concept:")
+		for _, c := range models.GlobalizableConcepts {
+			if tag == c {
+				found = append(found, c)
+				break
+			}
+		}
+	}
+	return found
+}
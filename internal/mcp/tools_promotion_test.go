@@ -0,0 +1,28 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobalizableConcepts(t *testing.T) {
+	assert.Equal(t, []string{"security", "best-practice"},
+		globalizableConcepts([]string{"type:decision", "concept:Security", "best-practice", "gotcha"}))
+	assert.Equal(t, []string{}, globalizableConcepts([]string{"scope:project"}))
+}
+
+func TestPromotionTools_NotConfigured(t *testing.T) {
+	server := NewServer(ServerOptions{Version: "1.0.0"})
+
+	_, err := server.handleListPromotionCandidates(context.Background(), json.RawMessage(`{}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not available")
+
+	_, err = server.handlePromoteObservation(context.Background(), json.RawMessage(`{"id":1}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not available")
+}
@@ -0,0 +1,258 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/pkg/similarity"
+	"github.com/thebtf/engram/pkg/strutil"
+)
+
+const (
+	// promptHistoryMaxEntries caps the prompts one analysis reads.
+	promptHistoryMaxEntries = 5000
+
+	// promptClusterThreshold is the term Jaccard at which two prompts count
+	// as the same recurring question.
+	promptClusterThreshold = 0.5
+
+	// promptTextMaxLen truncates prompt text in the analysis output.
+	promptTextMaxLen = 200
+)
+
+// SetSearchQueryLogStore wires the search query log, which records every
+// prompt sent for context injection with its result count. It enables the
+// analyze_prompt_history and find_past_prompt tools.
+func (s *Server) SetSearchQueryLogStore(store *gorm.SearchQueryLogStore) {
+	s.searchQueryLogStore = store
+}
+
+// promptCluster is a group of similar prompts. Prompt is the newest one.
+type promptCluster struct {
+	LastSeen    time.Time       `json:"last_seen"`
+	terms       map[string]bool // terms of the first (newest) prompt
+	Prompt      string          `json:"prompt"`
+	Projects    []string        `json:"projects"`
+	Count       int             `json:"count"`
+	ZeroResults int             `json:"zero_results"`
+}
+
+// clusterPrompts groups prompts (newest first) whose terms overlap by at least
+// promptClusterThreshold. Prompts without meaningful terms are skipped.
+func clusterPrompts(entries []gorm.SearchQueryLogEntry) []*promptCluster {
+	var clusters []*promptCluster
+	for _, e := range entries {
+		terms := similarity.ExtractTextTerms(e.Query)
+		if len(terms) == 0 {
+			continue
+		}
+		var match *promptCluster
+		for _, c := range clusters {
+			if similarity.JaccardSimilarity(c.terms, terms) >= promptClusterThreshold {
+				match = c
+				break
+			}
+		}
+		if match == nil {
+			match = &promptCluster{
+				terms:    terms,
+				Prompt:   strutil.Truncate(strings.TrimSpace(e.Query), promptTextMaxLen),
+				LastSeen: e.CreatedAt,
+			}
+			clusters = append(clusters, match)
+		}
+		match.Count++
+		if e.Results == 0 {
+			match.ZeroResults++
+		}
+		if e.Project != "" && !containsString(match.Projects, e.Project) {
+			match.Projects = append(match.Projects, e.Project)
+		}
+	}
+	return clusters
+}
+
+// suggestedTags returns up to three terms of the cluster, longest first, as
+// tags for a memory that would answer it.
+func (c *promptCluster) suggestedTags() []string {
+	terms := make([]string, 0, len(c.terms))
+	for t := range c.terms {
+		terms = append(terms, t)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if len(terms[i]) != len(terms[j]) {
+			return len(terms[i]) > len(terms[j])
+		}
+		return terms[i] < terms[j]
+	})
+	if len(terms) > 3 {
+		terms = terms[:3]
+	}
+	return terms
+}
+
+func containsString(values []string, v string) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// handleAnalyzePromptHistory aggregates the prompts sent for context
+// injection: recurring questions, prompts that matched no memory, and
+// memories worth creating for questions that keep going unanswered.
+func (s *Server) handleAnalyzePromptHistory(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.searchQueryLogStore == nil {
+		return "", fmt.Errorf("prompt history not available: search query log not configured")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	project := coerceString(m["project"], "")
+	days := coerceInt(m["days"], 30)
+	if days <= 0 {
+		days = 30
+	}
+	topN := coerceInt(m["top_n"], 10)
+	if topN <= 0 {
+		topN = 10
+	} else if topN > 100 {
+		topN = 100
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	entries, err := s.searchQueryLogStore.ListSince(ctx, project, gorm.SearchTypePrompt, since, promptHistoryMaxEntries)
+	if err != nil {
+		return "", fmt.Errorf("load prompt history: %w", err)
+	}
+
+	clusters := clusterPrompts(entries)
+
+	recurring := make([]*promptCluster, 0)
+	for _, c := range clusters {
+		if c.Count >= 2 {
+			recurring = append(recurring, c)
+		}
+	}
+	sort.SliceStable(recurring, func(i, j int) bool { return recurring[i].Count > recurring[j].Count })
+
+	// Zero-match prompts, one per cluster, newest first.
+	zeroMatch := make([]map[string]any, 0)
+	for _, c := range clusters {
+		if c.ZeroResults > 0 && len(zeroMatch) < topN {
+			zeroMatch = append(zeroMatch, map[string]any{
+				"prompt":       c.Prompt,
+				"zero_results": c.ZeroResults,
+				"last_seen":    c.LastSeen,
+			})
+		}
+	}
+
+	// Suggest a memory for each question that recurs and mostly goes unanswered.
+	gaps := make([]*promptCluster, 0)
+	for _, c := range clusters {
+		if c.Count >= 2 && c.ZeroResults*2 >= c.Count {
+			gaps = append(gaps, c)
+		}
+	}
+	sort.SliceStable(gaps, func(i, j int) bool { return gaps[i].ZeroResults > gaps[j].ZeroResults })
+	suggestions := make([]map[string]any, 0, min(len(gaps), topN))
+	for _, c := range gaps {
+		if len(suggestions) == topN {
+			break
+		}
+		suggestions = append(suggestions, map[string]any{
+			"question": c.Prompt,
+			"asked":    c.Count,
+			"answered": c.Count - c.ZeroResults,
+			"projects": c.Projects,
+			"tags":     c.suggestedTags(),
+			"action":   "store a memory that answers this question",
+		})
+	}
+
+	zeroTotal := 0
+	for _, e := range entries {
+		if e.Results == 0 {
+			zeroTotal++
+		}
+	}
+	if len(recurring) > topN {
+		recurring = recurring[:topN]
+	}
+
+	analysis := map[string]any{
+		"period":                 fmt.Sprintf("Last %d days", days),
+		"total_prompts":          len(entries),
+		"zero_result_prompts":    zeroTotal,
+		"distinct_questions":     len(clusters),
+		"recurring_questions":    recurring,
+		"zero_match_prompts":     zeroMatch,
+		"suggested_observations": suggestions,
+	}
+	if project != "" {
+		analysis["project"] = project
+	}
+	if len(entries) == promptHistoryMaxEntries {
+		analysis["note"] = fmt.Sprintf("analysis limited to the newest %d prompts", promptHistoryMaxEntries)
+	}
+
+	output, err := json.Marshal(analysis)
+	if err != nil {
+		return "", fmt.Errorf("marshal analysis: %w", err)
+	}
+	return string(output), nil
+}
+
+// handleFindPastPrompt full-text searches the user prompts sent for context
+// injection and returns the best matches with highlighted snippets.
+func (s *Server) handleFindPastPrompt(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.searchQueryLogStore == nil {
+		return "", fmt.Errorf("prompt history not available: search query log not configured")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	query := strings.TrimSpace(coerceString(m["query"], ""))
+	if query == "" {
+		return "", fmt.Errorf("query is required")
+	}
+	project := coerceString(m["project"], "")
+	limit := coerceInt(m["limit"], 10)
+	if limit <= 0 {
+		limit = 10
+	} else if limit > 50 {
+		limit = 50
+	}
+
+	matches, err := s.searchQueryLogStore.SearchPrompts(ctx, project, query, limit)
+	if err != nil {
+		return "", fmt.Errorf("search prompts: %w", err)
+	}
+	for i := range matches {
+		matches[i].Prompt = strutil.Truncate(strings.TrimSpace(matches[i].Prompt), promptTextMaxLen)
+	}
+
+	result := map[string]any{
+		"query":   query,
+		"prompts": matches,
+		"count":   len(matches),
+	}
+	if project != "" {
+		result["project"] = project
+	}
+	output, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("marshal prompts: %w", err)
+	}
+	return string(output), nil
+}
@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/db/gorm"
+)
+
+func TestClusterPrompts(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	entries := []gorm.SearchQueryLogEntry{
+		{Query: "how do we rotate the database credentials", Project: "api", Results: 0, CreatedAt: now},
+		{Query: "rotate database credentials", Project: "billing", Results: 0, CreatedAt: now.Add(-time.Hour)},
+		{Query: "How do we rotate the database credentials?", Project: "api", Results: 2, CreatedAt: now.Add(-2 * time.Hour)},
+		{Query: "why is the frontend build slow", Project: "web", Results: 1, CreatedAt: now.Add(-3 * time.Hour)},
+		{Query: "ok", Results: 0, CreatedAt: now.Add(-4 * time.Hour)},
+	}
+
+	clusters := clusterPrompts(entries)
+	require.Len(t, clusters, 2, "the short prompt has no terms and is skipped")
+
+	rotate := clusters[0]
+	assert.Equal(t, "how do we rotate the database credentials", rotate.Prompt)
+	assert.Equal(t, 3, rotate.Count)
+	assert.Equal(t, 2, rotate.ZeroResults)
+	assert.Equal(t, []string{"api", "billing"}, rotate.Projects)
+	assert.Equal(t, now, rotate.LastSeen)
+	assert.Equal(t, []string{"credentials", "database", "rotate"}, rotate.suggestedTags())
+
+	assert.Equal(t, 1, clusters[1].Count)
+	assert.Equal(t, 0, clusters[1].ZeroResults)
+}
+
+func TestHandleAnalyzePromptHistory_NotConfigured(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(ServerOptions{Version: "1.0.0"})
+	_, err := server.handleAnalyzePromptHistory(t.Context(), []byte(`{}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "search query log not configured")
+}
+
+func TestHandleFindPastPrompt_Validation(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(ServerOptions{Version: "1.0.0"})
+	_, err := server.handleFindPastPrompt(t.Context(), []byte(`{"query":"deploy"}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "search query log not configured")
+
+	// recall(type=prompts) routes to the prompt search.
+	_, err = server.handleRecall(t.Context(), []byte(`{"action":"search","type":"prompts","query":"deploy"}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "search query log not configured")
+
+	server.SetSearchQueryLogStore(gorm.NewSearchQueryLogStore(nil))
+	_, err = server.handleFindPastPrompt(t.Context(), []byte(`{"query":"  "}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "query is required")
+}
@@ -13,6 +13,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/pkg/models"
 )
 
 // handleRecall is the consolidated recall tool handler. It parses the "action"
@@ -27,6 +31,9 @@ func (s *Server) handleRecall(ctx context.Context, args json.RawMessage) (string
 
 	switch action {
 	case "search":
+		if coerceString(m["type"], "") == "prompts" {
+			return s.handleFindPastPrompt(ctx, args)
+		}
 		return s.handleRecallSearch(ctx, m)
 
 	case "preset":
@@ -77,13 +84,68 @@ func (s *Server) handleRecall(ctx context.Context, args json.RawMessage) (string
 	}
 }
 
+// sameProject reports whether all sessions belong to one project.
+func sameProject(sessions []*models.SDKSession) bool {
+	for _, sess := range sessions[1:] {
+		if sess.Project != sessions[0].Project {
+			return false
+		}
+	}
+	return true
+}
+
+// parseAsOf parses the as_of argument of recall search: an RFC 3339
+// timestamp, or a date (YYYY-MM-DD, UTC) meaning the end of that day. An
+// empty value returns the zero time (now).
+func parseAsOf(v string) (time.Time, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	day, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("as_of must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+	}
+	return day.Add(24*time.Hour - time.Nanosecond), nil
+}
+
+// searchProjects reads the project argument of recall search: one project,
+// a comma-separated list or an array of projects.
+func searchProjects(v any) []string {
+	var projects []string
+	for _, item := range coerceStringSlice(v) {
+		for _, project := range strings.Split(item, ",") {
+			if project = strings.TrimSpace(project); project != "" {
+				projects = appendUnique(projects, project)
+			}
+		}
+	}
+	return projects
+}
+
+// maxTaggedSessions caps the sessions a session_tag filter resolves to.
+const maxTaggedSessions = 200
+
 // handleRecallSearch performs trivial SQL-based memory retrieval.
-// It filters the memories table by project (required when non-empty) and
-// optionally applies a case-insensitive substring match on content when a
-// query string is provided. Results are ordered by created_at DESC.
+// It filters the memories table by one or more projects, plus the global
+// memories of every project with include_global (one of the two is required), and
+// optionally applies the query, parsed with ParseSearchQuery: plain words are
+// case-insensitive substring matches on content and tags, field:value terms
+// filter by type, concept, file and agent. Results are ordered by created_at DESC.
+// When more results may follow, the response carries a next_cursor token;
+// passing it back as cursor returns the next page, unaffected by memories
+// stored in the meantime. With as_of, the search runs over memory as it was
+// at that time (see MemoryStore.ListBeforeAsOf). A short query whose first
+// page matches nothing is retried typo-tolerantly (see fuzzy.go); its results
+// carry the matched words and a highlighted snippet. With fields, JSON
+// results are reduced to the selected fields (see parseResultFields).
 func (s *Server) handleRecallSearch(ctx context.Context, m map[string]any) (string, error) {
-	project := coerceString(m["project"], "")
+	projects := searchProjects(m["project"])
 	query := coerceString(m["query"], "")
+	format := coerceString(m["format"], "json")
 	limit := coerceInt(m["limit"], 20)
 	if limit <= 0 {
 		limit = 20
@@ -91,12 +153,84 @@ func (s *Server) handleRecallSearch(ctx context.Context, m map[string]any) (stri
 		limit = 100
 	}
 
+	if format != "json" && format != "digest" {
+		return "", fmt.Errorf("recall search: format must be \"json\" or \"digest\"")
+	}
+	fields, err := parseResultFields(m["fields"])
+	if err != nil {
+		return "", fmt.Errorf("recall search: %w", err)
+	}
+
 	if s.memoryStore == nil {
 		return "", fmt.Errorf("recall: memory store not configured")
 	}
 
+	query = strings.TrimSpace(query)
+	params, err := ParseSearchQuery(query)
+	if err != nil {
+		return "", fmt.Errorf("recall search: %w", err)
+	}
+	if sessionID := strings.TrimSpace(coerceString(m["session_id"], "")); sessionID != "" {
+		params.Session = sessionID
+	}
+	if params.Session != "" && s.sessionStore != nil {
+		sess, err := s.sessionStore.FindAnySDKSession(ctx, params.Session)
+		if err != nil {
+			return "", fmt.Errorf("recall search: load session: %w", err)
+		}
+		if sess != nil {
+			params.SessionStart = time.UnixMilli(sess.StartedAtEpoch)
+			if sess.CompletedAtEpoch.Valid {
+				params.SessionEnd = time.UnixMilli(sess.CompletedAtEpoch.Int64)
+			}
+			if len(projects) == 0 && len(params.Projects) == 0 {
+				projects = []string{sess.Project}
+			}
+		}
+	}
+	if sessionTag := strings.TrimSpace(coerceString(m["session_tag"], "")); sessionTag != "" {
+		params.SessionTag = sessionTag
+	}
+	if len(projects) == 0 {
+		projects = params.Projects
+	}
+	params.Projects = projects
+	params.IncludeGlobal = coerceBool(m["include_global"], false)
+	if params.SessionTag != "" && s.sessionStore != nil {
+		sessionProject := ""
+		if len(projects) == 1 {
+			sessionProject = projects[0]
+		}
+		sessions, _, err := s.sessionStore.ListSDKSessions(ctx, sessionProject, maxTaggedSessions, 0, 0, 0, 0, params.SessionTag)
+		if err != nil {
+			return "", fmt.Errorf("recall search: load tagged sessions: %w", err)
+		}
+		for _, sess := range sessions {
+			span := SessionSpan{ID: sess.ClaudeSessionID, Start: time.UnixMilli(sess.StartedAtEpoch)}
+			if sess.CompletedAtEpoch.Valid {
+				span.End = time.UnixMilli(sess.CompletedAtEpoch.Int64)
+			}
+			params.TaggedSessions = append(params.TaggedSessions, span)
+		}
+		if len(projects) == 0 && len(sessions) > 0 && sameProject(sessions) {
+			projects = []string{sessions[0].Project}
+			params.Projects = projects
+		}
+	}
+	asOf, err := parseAsOf(coerceString(m["as_of"], ""))
+	if err != nil {
+		return "", fmt.Errorf("recall search: %w", err)
+	}
+	params.Cursor = coerceString(m["cursor"], "")
+	var after searchCursor
+	if params.Cursor != "" {
+		if after, err = decodeSearchCursor(params.Cursor); err != nil {
+			return "", fmt.Errorf("recall search: %w", err)
+		}
+	}
+
 	// List returns created_at DESC, project-filtered results.
-	if project == "" {
+	if len(projects) == 0 && !params.IncludeGlobal {
 		// No project scope: return a helpful message rather than silently
 		// returning zero rows (the project param is required by List).
 		return `{"memories":[],"count":0,"note":"project parameter required for memory search in v5"}`, nil
@@ -104,10 +238,10 @@ func (s *Server) handleRecallSearch(ctx context.Context, m map[string]any) (stri
 
 	// When a query filter is provided, fetch a wider candidate pool so that
 	// older matching memories are not excluded by the limit before filtering.
-	// The final result is capped at `limit` after filtering.
-	fetchLimit := limit
-	query = strings.TrimSpace(query)
-	if query != "" {
+	// The final result is capped at `limit` after filtering. Without a filter,
+	// one extra row tells whether another page exists.
+	fetchLimit := limit + 1
+	if !params.IsEmpty() {
 		const candidateMultiplier = 10
 		const minCandidatePool = 1000
 		fetchLimit = limit * candidateMultiplier
@@ -116,47 +250,102 @@ func (s *Server) handleRecallSearch(ctx context.Context, m map[string]any) (stri
 		}
 	}
 
-	memories, err := s.memoryStore.List(ctx, project, fetchLimit)
+	memories, err := s.memoryStore.ListBeforeAsOfIn(ctx, projects, params.IncludeGlobal, after.CreatedAt, after.ID, fetchLimit, asOf)
 	if err != nil {
 		return "", fmt.Errorf("recall search: %w", err)
 	}
-
-	// Apply optional query filter in-memory (case-insensitive substring),
-	// then cap at the originally requested limit.
-	if query != "" {
-		queryLower := strings.ToLower(query)
+	poolFull := len(memories) == fetchLimit
+
+	// Apply optional query filter in-memory, then cap at the originally
+	// requested limit. The next page starts after the last result, or after
+	// the last candidate scanned when the pool ran out first.
+	var nextCursor string
+	var fuzzyMatches map[int64][]string
+	if params.IsEmpty() {
+		if len(memories) > limit {
+			memories = memories[:limit]
+			nextCursor = encodeSearchCursor(memories[limit-1])
+		}
+	} else {
 		filtered := memories[:0:0] // same element type, zero length, zero cap (avoids models import)
+		scanned := 0
 		for _, mem := range memories {
-			if strings.Contains(strings.ToLower(mem.Content), queryLower) {
+			scanned++
+			if params.Matches(mem) {
 				filtered = append(filtered, mem)
 				if len(filtered) == limit {
 					break
 				}
 			}
 		}
+		switch {
+		case len(filtered) == limit && (scanned < len(memories) || poolFull):
+			nextCursor = encodeSearchCursor(filtered[limit-1])
+		case len(filtered) < limit && poolFull:
+			nextCursor = encodeSearchCursor(memories[len(memories)-1])
+		}
+		if len(filtered) == 0 && params.Cursor == "" {
+			if found, matches := fuzzySearch(params, memories, limit); len(found) > 0 {
+				filtered, fuzzyMatches, nextCursor = found, matches, ""
+			}
+		}
 		memories = filtered
 	}
+	s.recordMemoryAccess(ctx, gorm.AccessSearch, memories)
+
+	if format == "digest" {
+		now := time.Now()
+		if !asOf.IsZero() {
+			now = asOf
+		}
+		digest := formatMemoryDigest(memories, now)
+		if nextCursor != "" {
+			digest = strings.TrimSuffix(digest, "\n") + "\nnext_cursor: " + nextCursor + "\n"
+		}
+		return digest, nil
+	}
 
 	type memoryResult struct {
-		Tags        []string `json:"tags,omitempty"`
-		Content     string   `json:"content"`
-		SourceAgent string   `json:"source_agent,omitempty"`
-		Project     string   `json:"project"`
-		ID          int64    `json:"id"`
-		Version     int      `json:"version"`
+		Tags         []string `json:"tags,omitempty"`
+		Content      string   `json:"content"`
+		SourceAgent  string   `json:"source_agent,omitempty"`
+		Project      string   `json:"project"`
+		MatchedTerms []string `json:"matched_terms,omitempty"`
+		Highlight    string   `json:"highlight,omitempty"`
+		ID           int64    `json:"id"`
+		Version      int      `json:"version"`
+	}
+	// With fields, each result carries only the selected fields.
+	if fields != nil {
+		shaped := make([]map[string]any, 0, len(memories))
+		for _, mem := range memories {
+			shaped = append(shaped, shapeMemory(mem, fields, fuzzyMatches[mem.ID]))
+		}
+		return marshalSearchResponse(shaped, query, params, fuzzyMatches != nil, nextCursor, asOf)
 	}
 	results := make([]memoryResult, 0, len(memories))
 	for _, mem := range memories {
-		results = append(results, memoryResult{
+		result := memoryResult{
 			ID:          mem.ID,
 			Project:     mem.Project,
 			Content:     mem.Content,
 			Tags:        mem.Tags,
 			SourceAgent: mem.SourceAgent,
 			Version:     mem.Version,
-		})
+		}
+		if matched, ok := fuzzyMatches[mem.ID]; ok {
+			result.MatchedTerms = matched
+			result.Highlight = highlightMatches(mem.Content, matched)
+		}
+		results = append(results, result)
 	}
 
+	return marshalSearchResponse(results, query, params, fuzzyMatches != nil, nextCursor, asOf)
+}
+
+// marshalSearchResponse renders the JSON response of recall search around
+// results, a slice of memory results.
+func marshalSearchResponse[T any](results []T, query string, params SearchParams, fuzzy bool, nextCursor string, asOf time.Time) (string, error) {
 	out := map[string]any{
 		"memories": results,
 		"count":    len(results),
@@ -164,6 +353,18 @@ func (s *Server) handleRecallSearch(ctx context.Context, m map[string]any) (stri
 	if query != "" {
 		out["query"] = query
 	}
+	if params.HasFilters() {
+		out["parsed"] = params
+	}
+	if fuzzy {
+		out["fuzzy"] = true
+	}
+	if nextCursor != "" {
+		out["next_cursor"] = nextCursor
+	}
+	if !asOf.IsZero() {
+		out["as_of"] = asOf.UTC().Format(time.RFC3339)
+	}
 
 	output, err := json.Marshal(out)
 	if err != nil {
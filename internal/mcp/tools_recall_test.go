@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseAsOf(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want time.Time
+	}{
+		{"", time.Time{}},
+		{"2026-03-05T10:30:00Z", time.Date(2026, 3, 5, 10, 30, 0, 0, time.UTC)},
+		{"2026-03-05", time.Date(2026, 3, 5, 23, 59, 59, 999999999, time.UTC)},
+	} {
+		got, err := parseAsOf(tt.in)
+		if err != nil {
+			t.Fatalf("parseAsOf(%q): %v", tt.in, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseAsOf(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	if _, err := parseAsOf("last tuesday"); err == nil {
+		t.Errorf("parseAsOf accepted an invalid date")
+	}
+}
+
+func TestSearchProjects(t *testing.T) {
+	for _, tt := range []struct {
+		in   any
+		want []string
+	}{
+		{nil, nil},
+		{"engram", []string{"engram"}},
+		{"api, web,,api", []string{"api", "web"}},
+		{[]any{"api", "web,docs"}, []string{"api", "web", "docs"}},
+	} {
+		if got := searchProjects(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("searchProjects(%#v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/thebtf/engram/internal/worker/relinfer"
+)
+
+// relatedFilesScanLimit caps the memories one suggest_related_files call
+// reads; the newest are kept.
+const relatedFilesScanLimit = 1000
+
+// handleSuggestRelatedFiles ranks the files that the project's memories
+// mention together with a given file, surfacing coupling the code layout
+// does not show.
+func (s *Server) handleSuggestRelatedFiles(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryStore == nil {
+		return "", fmt.Errorf("memory store not available")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	filePath := strings.TrimSpace(coerceString(m["path"], ""))
+	if filePath == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	project := projectFromContext(ctx)
+	if project == "" {
+		project = coerceString(m["project"], "")
+	}
+	if project == "" {
+		return "", fmt.Errorf("project is required for suggest_related_files")
+	}
+	limit := coerceInt(m["limit"], 10)
+	if limit <= 0 {
+		limit = 10
+	} else if limit > 50 {
+		limit = 50
+	}
+
+	mems, err := s.memoryStore.List(ctx, project, relatedFilesScanLimit)
+	if err != nil {
+		return "", fmt.Errorf("list memories: %w", err)
+	}
+	touching, related := relinfer.RelatedFiles(filePath, mems, limit)
+
+	out := map[string]any{
+		"project":          project,
+		"path":             filePath,
+		"memories_touched": touching,
+		"related_files":    related,
+		"count":            len(related),
+	}
+	if touching == 0 {
+		out["note"] = "no memory mentions this file"
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal result: %w", err)
+	}
+	return string(data), nil
+}
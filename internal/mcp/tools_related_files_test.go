@@ -0,0 +1,28 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/db/gorm"
+)
+
+func TestHandleSuggestRelatedFiles_Validation(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(ServerOptions{Version: "1.0.0"})
+	_, err := server.handleSuggestRelatedFiles(t.Context(), []byte(`{"path":"main.go","project":"p"}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "memory store not available")
+
+	server.SetMemoryStore(gorm.NewMemoryStore(&gorm.Store{}))
+	_, err = server.handleSuggestRelatedFiles(t.Context(), []byte(`{"project":"p"}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "path is required")
+
+	_, err = server.handleSuggestRelatedFiles(t.Context(), []byte(`{"path":"main.go"}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "project is required")
+}
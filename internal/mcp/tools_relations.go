@@ -0,0 +1,172 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	gormlib "gorm.io/gorm"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// relationTypeNames returns models.AllRelationTypes as strings, for tool schemas.
+func relationTypeNames() []string {
+	names := make([]string, len(models.AllRelationTypes))
+	for i, t := range models.AllRelationTypes {
+		names[i] = string(t)
+	}
+	return names
+}
+
+// relationArgs are the arguments shared by create_relation and delete_relation.
+type relationArgs struct {
+	RelationType models.RelationType
+	SourceID     int64
+	TargetID     int64
+}
+
+// parseRelationArgs validates source_id, target_id and relation_type.
+func parseRelationArgs(m map[string]any) (relationArgs, error) {
+	a := relationArgs{
+		SourceID:     coerceInt64(m["source_id"], 0),
+		TargetID:     coerceInt64(m["target_id"], 0),
+		RelationType: models.RelationType(strings.ToLower(strings.TrimSpace(coerceString(m["relation_type"], "")))),
+	}
+	if a.SourceID <= 0 || a.TargetID <= 0 {
+		return a, fmt.Errorf("source_id and target_id are required and must be positive integers")
+	}
+	if a.SourceID == a.TargetID {
+		return a, fmt.Errorf("source_id and target_id must differ")
+	}
+	if !models.IsValidRelationType(a.RelationType) {
+		return a, fmt.Errorf("unknown relation_type %q (valid: %s)", a.RelationType, strings.Join(relationTypeNames(), ", "))
+	}
+	return a, nil
+}
+
+// checkRelationEndpoint returns an error when id is not an active memory.
+// Without a memory store the endpoints are not checked.
+func (s *Server) checkRelationEndpoint(ctx context.Context, field string, id int64) error {
+	if s.memoryStore == nil {
+		return nil
+	}
+	if _, err := s.memoryStore.Get(ctx, id); err != nil {
+		if errors.Is(err, gormlib.ErrRecordNotFound) {
+			return fmt.Errorf("%s: memory %d not found", field, id)
+		}
+		return fmt.Errorf("%s: %w", field, err)
+	}
+	return nil
+}
+
+// handleCreateRelation stores a directed relation between two memories. When
+// the relation type has an inverse (references/referenced_by, or a symmetric
+// type such as similar_to), the inverse edge is stored as well. Creating an
+// existing relation updates its confidence.
+func (s *Server) handleCreateRelation(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.relationStore == nil {
+		return "", fmt.Errorf("relation store not available")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	rel, err := parseRelationArgs(m)
+	if err != nil {
+		return "", err
+	}
+	confidence := coerceFloat64(m["confidence"], 1.0)
+	if confidence < 0 || confidence > 1 {
+		return "", fmt.Errorf("confidence must be between 0 and 1")
+	}
+	reason := strings.TrimSpace(coerceString(m["reason"], ""))
+
+	if err := s.checkRelationEndpoint(ctx, "source_id", rel.SourceID); err != nil {
+		return "", err
+	}
+	if err := s.checkRelationEndpoint(ctx, "target_id", rel.TargetID); err != nil {
+		return "", err
+	}
+
+	store := func(sourceID, targetID int64, relType models.RelationType) (int64, error) {
+		relation := models.NewObservationRelation(sourceID, targetID, relType, confidence, models.DetectionSourceManual, reason)
+		id, err := s.relationStore.StoreRelation(ctx, relation)
+		if err != nil {
+			return 0, fmt.Errorf("create relation: %w", err)
+		}
+		// StoreRelation keeps an existing row as is; apply the new confidence.
+		if err := s.relationStore.UpdateRelationConfidence(ctx, id, confidence); err != nil {
+			return 0, fmt.Errorf("create relation: %w", err)
+		}
+		return id, nil
+	}
+
+	id, err := store(rel.SourceID, rel.TargetID, rel.RelationType)
+	if err != nil {
+		return "", err
+	}
+	response := map[string]any{
+		"id":            id,
+		"source_id":     rel.SourceID,
+		"target_id":     rel.TargetID,
+		"relation_type": rel.RelationType,
+		"confidence":    confidence,
+	}
+	if inverse, ok := models.InverseRelationType(rel.RelationType); ok {
+		inverseID, err := store(rel.TargetID, rel.SourceID, inverse)
+		if err != nil {
+			return "", err
+		}
+		response["inverse"] = map[string]any{"id": inverseID, "relation_type": inverse}
+	}
+
+	output, err := json.Marshal(response)
+	if err != nil {
+		return "", fmt.Errorf("marshal response: %w", err)
+	}
+	return string(output), nil
+}
+
+// handleDeleteRelation deletes a relation and, when the type has one, its
+// inverse edge.
+func (s *Server) handleDeleteRelation(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.relationStore == nil {
+		return "", fmt.Errorf("relation store not available")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	rel, err := parseRelationArgs(m)
+	if err != nil {
+		return "", err
+	}
+
+	deleted, err := s.relationStore.DeleteRelation(ctx, rel.SourceID, rel.TargetID, rel.RelationType)
+	if err != nil {
+		return "", fmt.Errorf("delete relation: %w", err)
+	}
+	if inverse, ok := models.InverseRelationType(rel.RelationType); ok {
+		n, err := s.relationStore.DeleteRelation(ctx, rel.TargetID, rel.SourceID, inverse)
+		if err != nil {
+			return "", fmt.Errorf("delete inverse relation: %w", err)
+		}
+		deleted += n
+	}
+	if deleted == 0 {
+		return "", fmt.Errorf("relation %d -[%s]-> %d not found", rel.SourceID, rel.RelationType, rel.TargetID)
+	}
+
+	s.recordAudit(ctx, models.AuditOpRelationDelete, "",
+		fmt.Sprintf("%d %s %d", rel.SourceID, rel.RelationType, rel.TargetID),
+		auditMemoryID(rel.SourceID), auditMemoryID(rel.TargetID))
+
+	output, err := json.Marshal(map[string]any{"deleted": deleted})
+	if err != nil {
+		return "", fmt.Errorf("marshal response: %w", err)
+	}
+	return string(output), nil
+}
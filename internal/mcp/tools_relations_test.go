@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestParseRelationArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args        map[string]any
+		name        string
+		errContains string
+	}{
+		{name: "valid", args: map[string]any{"source_id": 1.0, "target_id": 2.0, "relation_type": "Fixes"}},
+		{name: "missing ids", args: map[string]any{"relation_type": "fixes"}, errContains: "source_id and target_id are required"},
+		{name: "self relation", args: map[string]any{"source_id": 3.0, "target_id": 3.0, "relation_type": "fixes"}, errContains: "must differ"},
+		{name: "unknown type", args: map[string]any{"source_id": 1.0, "target_id": 2.0, "relation_type": "blocks"}, errContains: "unknown relation_type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rel, err := parseRelationArgs(tt.args)
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, relationArgs{SourceID: 1, TargetID: 2, RelationType: models.RelationFixes}, rel)
+		})
+	}
+}
+
+func TestRelationTools_ReadOnly(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(ServerOptions{Version: "1.0.0"})
+	server.SetReadOnly(true)
+	for _, name := range []string{"create_relation", "delete_relation"} {
+		err := server.checkReadOnly(t.Context(), name, []byte(`{}`))
+		require.Error(t, err, name)
+	}
+}
@@ -0,0 +1,174 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	gormlib "gorm.io/gorm"
+
+	"github.com/thebtf/engram/internal/config"
+	"github.com/thebtf/engram/internal/privacy"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// reviewListLimit caps the pending memories returned by one list call.
+const reviewListLimit = 100
+
+// handleReviewObservation works the queue of memories the quality gate held
+// back at store time (ENGRAM_QUALITY_GATE_FLOOR): list them with their score
+// and suggestions, approve one as is, enrich it (new content and/or tags,
+// re-scored and released once it clears the floor) or discard it.
+func (s *Server) handleReviewObservation(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryStore == nil {
+		return "", fmt.Errorf("memory store not available")
+	}
+
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+
+	action := coerceString(m["action"], "list")
+	if action == "list" {
+		return s.listPendingReview(ctx, m)
+	}
+
+	id := coerceInt64(m["id"], 0)
+	if id <= 0 {
+		return "", fmt.Errorf("id is required and must be a positive integer")
+	}
+	current, err := s.memoryStore.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, gormlib.ErrRecordNotFound) {
+			return "", fmt.Errorf("memory %d not found", id)
+		}
+		return "", fmt.Errorf("review memory: %w", err)
+	}
+	if !current.PendingReview {
+		return "", fmt.Errorf("memory %d is not pending review", id)
+	}
+
+	switch action {
+	case "approve":
+		updated, err := s.memoryStore.SetPendingReview(ctx, id, false)
+		if err != nil {
+			return "", fmt.Errorf("approve memory: %w", err)
+		}
+		return marshalReview(updated, nil, "Memory approved: it is now searchable")
+
+	case "enrich":
+		content := coerceString(m["content"], "")
+		_, hasTags := m["tags"]
+		if content == "" && !hasTags {
+			return "", fmt.Errorf("enrich requires content and/or tags")
+		}
+		next := &models.Memory{
+			ID:          id,
+			Content:     current.Content,
+			Tags:        current.Tags,
+			SourceAgent: current.SourceAgent,
+			EditedBy:    memoryActor(ctx, m),
+		}
+		if content != "" {
+			next.Content = privacy.RedactForStorage(current.Project, content)
+		}
+		if hasTags {
+			next.Tags = coerceStringSlice(m["tags"])
+			// Keep the type so the memory is still scored against its schema.
+			if t := models.TypeFromTags(current.Tags); t != "" && models.TypeFromTags(next.Tags) == "" {
+				next.Tags = append(next.Tags, "type:"+string(t))
+			}
+		}
+		updated, err := s.memoryStore.Update(ctx, next)
+		if err != nil {
+			return "", fmt.Errorf("enrich memory: %w", err)
+		}
+
+		quality := observationQuality(models.TypeFromTags(updated.Tags), updated.Content, updated.Tags)
+		if score, _ := quality["score"].(float64); score < config.Get().QualityGateFloor {
+			return marshalReview(updated, quality, "Memory updated but still below the quality gate floor; enrich it further or approve it as is")
+		}
+		updated, err = s.memoryStore.SetPendingReview(ctx, id, false)
+		if err != nil {
+			return "", fmt.Errorf("approve memory: %w", err)
+		}
+		return marshalReview(updated, quality, "Memory enriched and approved: it is now searchable")
+
+	case "discard":
+		if err := s.memoryStore.Delete(ctx, id); err != nil {
+			return "", fmt.Errorf("discard memory: %w", err)
+		}
+		s.recordAudit(ctx, models.AuditOpMemoryDelete, current.Project, current.Content, auditMemoryID(id))
+		return marshalReview(current, nil, "Memory discarded")
+
+	default:
+		return "", fmt.Errorf("invalid action %q: must be one of list, approve, enrich, discard", action)
+	}
+}
+
+// listPendingReview returns the memories waiting for review, oldest first,
+// each with its current quality score and suggestions.
+func (s *Server) listPendingReview(ctx context.Context, m map[string]any) (string, error) {
+	project := projectFromContext(ctx)
+	if project == "" {
+		project = coerceString(m["project"], "")
+	}
+	limit := coerceInt(m["limit"], 20)
+	if limit <= 0 {
+		limit = 20
+	} else if limit > reviewListLimit {
+		limit = reviewListLimit
+	}
+
+	mems, err := s.memoryStore.ListPendingReview(ctx, project, limit)
+	if err != nil {
+		return "", fmt.Errorf("list pending review: %w", err)
+	}
+	items := make([]map[string]any, 0, len(mems))
+	for _, mem := range mems {
+		quality := observationQuality(models.TypeFromTags(mem.Tags), mem.Content, mem.Tags)
+		items = append(items, map[string]any{
+			"id":          mem.ID,
+			"project":     mem.Project,
+			"title":       truncateTitle(mem.Content, 80),
+			"content":     mem.Content,
+			"tags":        mem.Tags,
+			"score":       quality["score"],
+			"suggestions": quality["suggestions"],
+			"created_at":  mem.CreatedAt,
+		})
+	}
+	out := map[string]any{
+		"pending":       items,
+		"count":         len(items),
+		"quality_floor": config.Get().QualityGateFloor,
+	}
+	if project != "" {
+		out["project"] = project
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal result: %w", err)
+	}
+	return string(data), nil
+}
+
+func marshalReview(mem *models.Memory, quality map[string]any, message string) (string, error) {
+	out := map[string]any{
+		"id":             mem.ID,
+		"project":        mem.Project,
+		"pending_review": mem.PendingReview,
+		"message":        message,
+	}
+	if quality != nil {
+		out["score"] = quality["score"]
+		out["suggestions"] = quality["suggestions"]
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal result: %w", err)
+	}
+	return string(data), nil
+}
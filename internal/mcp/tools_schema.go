@@ -0,0 +1,264 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	gormlib "gorm.io/gorm"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// schemaSectionsFromArgs renders the store_memory "fields" object (section name
+// → text) and, for schemas with an alternatives_considered section, the
+// "rejected" list as labelled content lines. Unknown section names are an error.
+func schemaSectionsFromArgs(schema models.ObservationSchema, rawFields any, rejected []string) ([]string, error) {
+	var lines []string
+
+	if rawFields != nil {
+		fields, ok := rawFields.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("fields must be an object of section name to text")
+		}
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			sec, ok := schema.Section(name)
+			if !ok {
+				return nil, fmt.Errorf("unknown field %q for %s observations (valid: %s)", name, schema.Type, sectionNames(schema))
+			}
+			if value := strings.TrimSpace(coerceString(fields[name], "")); value != "" {
+				lines = append(lines, sec.FormatSection(value))
+			}
+		}
+	}
+
+	if len(rejected) > 0 {
+		if sec, ok := schema.Section("alternatives_considered"); ok {
+			lines = append(lines, sec.FormatSection(strings.Join(rejected, "; ")))
+		}
+	}
+	return lines, nil
+}
+
+func sectionNames(schema models.ObservationSchema) string {
+	names := make([]string, 0, len(schema.Required)+len(schema.Recommended))
+	for _, sec := range schema.Required {
+		names = append(names, sec.Name)
+	}
+	for _, sec := range schema.Recommended {
+		names = append(names, sec.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func sectionTitles(sections []models.SchemaSection) string {
+	return strings.Join(sectionTitlesList(sections), ", ")
+}
+
+func joinObservationTypes(types []models.ObservationType) string {
+	parts := make([]string, len(types))
+	for i, t := range types {
+		parts[i] = string(t)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// handleGetObservationSchema returns the schema of one observation type, or of
+// every type that has one.
+func (s *Server) handleGetObservationSchema(_ context.Context, args json.RawMessage) (string, error) {
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+
+	var out any
+	if obsType := coerceString(m["type"], ""); obsType != "" {
+		schema, ok := models.SchemaFor(models.ObservationType(obsType))
+		if !ok {
+			out = map[string]any{
+				"type":      obsType,
+				"free_form": true,
+				"message":   fmt.Sprintf("%s observations have no schema; types with one: %s", obsType, joinObservationTypes(models.SchemaTypes())),
+			}
+		} else {
+			out = schema
+		}
+	} else {
+		schemas := make([]models.ObservationSchema, 0, len(models.ObservationSchemas))
+		for _, t := range models.SchemaTypes() {
+			schemas = append(schemas, models.ObservationSchemas[t])
+		}
+		out = map[string]any{
+			"schemas": schemas,
+			"usage":   "Write each section as a labelled line (\"Root cause: ...\") or pass store_memory fields={\"root_cause\": \"...\"}.",
+		}
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		return "", fmt.Errorf("marshal schema: %w", err)
+	}
+	return string(data), nil
+}
+
+// Weights of the observation quality components; they sum to 1.
+const (
+	qualityWeightLength      = 0.25
+	qualityWeightSpecificity = 0.15
+	qualityWeightTags        = 0.10
+	qualityWeightRequired    = 0.35
+	qualityWeightRecommended = 0.15
+)
+
+// specificityPattern matches concrete references: code spans, file paths,
+// function calls, identifiers with underscores or dots, and numbers with units.
+var specificityPattern = regexp.MustCompile("`[^`]+`|\\b[\\w-]+/[\\w./-]+|\\b\\w+\\.(go|ts|js|py|rs|sql|json|ya?ml|md)\\b|\\b\\w+\\(\\)|\\b[a-z]+_[a-z_]+\\b|\\b\\d+(\\.\\d+)?\\s?(ms|s|mb|gb|kb|%)\\b")
+
+// observationQuality scores content (0-1) for completeness against its type's
+// schema, length, specificity and tagging, with suggestions for improvement.
+func observationQuality(obsType models.ObservationType, content string, tags []string) map[string]any {
+	components := map[string]float64{}
+
+	length := utf8.RuneCountInString(strings.TrimSpace(content))
+	switch {
+	case length < 40:
+		components["length"] = 0.2
+	case length < 120:
+		components["length"] = 0.6
+	case length <= 1500:
+		components["length"] = 1
+	default:
+		components["length"] = 0.8
+	}
+
+	hits := len(specificityPattern.FindAllString(content, 4))
+	components["specificity"] = min(1, float64(hits)/2)
+
+	userTags := 0
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, "type:") && !strings.HasPrefix(tag, "scope:") && !strings.HasPrefix(tag, "ttl:") {
+			userTags++
+		}
+	}
+	components["tags"] = min(1, float64(userTags)/2)
+
+	var missingRequired, missingRecommended []models.SchemaSection
+	schema, hasSchema := models.SchemaFor(obsType)
+	components["required_sections"] = 1
+	components["recommended_sections"] = 1
+	if hasSchema {
+		missingRequired = schema.MissingRequired(content)
+		missingRecommended = schema.MissingRecommended(content)
+		if n := len(schema.Required); n > 0 {
+			components["required_sections"] = float64(n-len(missingRequired)) / float64(n)
+		}
+		if n := len(schema.Recommended); n > 0 {
+			components["recommended_sections"] = float64(n-len(missingRecommended)) / float64(n)
+		}
+	}
+
+	score := components["length"]*qualityWeightLength +
+		components["specificity"]*qualityWeightSpecificity +
+		components["tags"]*qualityWeightTags +
+		components["required_sections"]*qualityWeightRequired +
+		components["recommended_sections"]*qualityWeightRecommended
+
+	var suggestions []string
+	for _, sec := range missingRequired {
+		suggestions = append(suggestions, fmt.Sprintf("Add the required %q section: %s", sec.Title, sec.Description))
+	}
+	for _, sec := range missingRecommended {
+		suggestions = append(suggestions, fmt.Sprintf("Consider a %q section: %s", sec.Title, sec.Description))
+	}
+	if components["length"] < 0.6 {
+		suggestions = append(suggestions, "Content is very short; state the context and the outcome.")
+	}
+	if components["specificity"] < 0.5 {
+		suggestions = append(suggestions, "Reference concrete files, functions, commands or numbers.")
+	}
+	if components["tags"] < 0.5 {
+		suggestions = append(suggestions, "Add concept tags so the memory can be found by topic.")
+	}
+
+	grade := "poor"
+	switch {
+	case score >= 0.75:
+		grade = "good"
+	case score >= 0.5:
+		grade = "fair"
+	}
+
+	return map[string]any{
+		"type":                string(obsType),
+		"schema":              hasSchema,
+		"score":               float64(int(score*100+0.5)) / 100,
+		"grade":               grade,
+		"components":          components,
+		"missing_required":    sectionTitlesList(missingRequired),
+		"missing_recommended": sectionTitlesList(missingRecommended),
+		"suggestions":         suggestions,
+	}
+}
+
+func sectionTitlesList(sections []models.SchemaSection) []string {
+	titles := make([]string, len(sections))
+	for i, sec := range sections {
+		titles[i] = sec.Title
+	}
+	return titles
+}
+
+// handleGetObservationQuality scores a stored memory (by id) or a draft
+// (content + type) for completeness against its observation schema.
+func (s *Server) handleGetObservationQuality(ctx context.Context, args json.RawMessage) (string, error) {
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+
+	obsType := models.ObservationType(coerceString(m["type"], ""))
+	content := coerceString(m["content"], "")
+	tags := coerceStringSlice(m["tags"])
+	id := coerceInt64(m["id"], 0)
+
+	if id > 0 {
+		if s.memoryStore == nil {
+			return "", fmt.Errorf("memory store not available")
+		}
+		mem, err := s.memoryStore.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, gormlib.ErrRecordNotFound) {
+				return "", fmt.Errorf("memory %d not found", id)
+			}
+			return "", fmt.Errorf("get memory: %w", err)
+		}
+		content = mem.Content
+		tags = mem.Tags
+		if obsType == "" {
+			obsType = models.TypeFromTags(mem.Tags)
+		}
+	}
+	if content == "" {
+		return "", fmt.Errorf("id or content is required")
+	}
+
+	result := observationQuality(obsType, content, tags)
+	if id > 0 {
+		result["id"] = id
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("marshal quality: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestSchemaSectionsFromArgs(t *testing.T) {
+	t.Parallel()
+
+	schema, ok := models.SchemaFor(models.ObsTypeDecision)
+	require.True(t, ok)
+
+	lines, err := schemaSectionsFromArgs(schema, map[string]any{"rationale": "fewer moving parts"}, []string{"SQLite", "BoltDB"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"Rationale: fewer moving parts",
+		"Alternatives considered: SQLite; BoltDB",
+	}, lines)
+	assert.Empty(t, schema.MissingRequired("Use PostgreSQL.\n\n"+lines[1]))
+
+	_, err = schemaSectionsFromArgs(schema, map[string]any{"root_cause": "x"}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "alternatives_considered")
+}
+
+func TestObservationQuality(t *testing.T) {
+	t.Parallel()
+
+	poor := observationQuality(models.ObsTypeBugfix, "Fixed login.", nil)
+	assert.Equal(t, "poor", poor["grade"])
+	assert.Equal(t, []string{"Root cause"}, poor["missing_required"])
+
+	good := observationQuality(models.ObsTypeBugfix,
+		"Symptom: `POST /api/login` returned 500 after 30s.\n"+
+			"Root cause: session_cache in internal/auth/cache.go never evicted expired entries.\n"+
+			"Fix: evict on read in Get().",
+		[]string{"auth", "cache", "type:bugfix"})
+	assert.Equal(t, "good", good["grade"])
+	assert.Empty(t, good["missing_required"])
+	assert.Greater(t, good["score"].(float64), poor["score"].(float64))
+}
+
+func TestHandleGetObservationSchema(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(ServerOptions{Version: "1.0.0"})
+	out, err := server.handleGetObservationSchema(context.Background(), json.RawMessage(`{"type": "bugfix"}`))
+	require.NoError(t, err)
+
+	var schema models.ObservationSchema
+	require.NoError(t, json.Unmarshal([]byte(out), &schema))
+	assert.Equal(t, models.ObsTypeBugfix, schema.Type)
+	require.Len(t, schema.Required, 1)
+	assert.Equal(t, "root_cause", schema.Required[0].Name)
+
+	out, err = server.handleGetObservationSchema(context.Background(), json.RawMessage(`{"type": "feature"}`))
+	require.NoError(t, err)
+	assert.Contains(t, out, `"free_form":true`)
+
+	_, err = server.handleGetObservationQuality(context.Background(), json.RawMessage(`{}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/thebtf/engram/internal/db/gorm"
+)
+
+// handleTagSession names and tags a session. Omitted fields are left
+// unchanged; tags replaces the tag set before add_tags and remove_tags apply.
+func (s *Server) handleTagSession(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.sessionStore == nil {
+		return "", fmt.Errorf("session store not available")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	sessionID := strings.TrimSpace(coerceString(m["session_id"], ""))
+	if sessionID == "" {
+		return "", fmt.Errorf("tag_session: session_id is required")
+	}
+
+	var labels gorm.SessionLabels
+	if v, ok := m["name"]; ok && v != nil {
+		name := coerceString(v, "")
+		labels.Name = &name
+	}
+	if v, ok := m["tags"]; ok && v != nil {
+		labels.Tags = coerceStringSlice(v)
+		if labels.Tags == nil {
+			labels.Tags = []string{}
+		}
+	}
+	labels.AddTags = coerceStringSlice(m["add_tags"])
+	labels.RemoveTags = coerceStringSlice(m["remove_tags"])
+	if labels.Name == nil && labels.Tags == nil && len(labels.AddTags) == 0 && len(labels.RemoveTags) == 0 {
+		return "", fmt.Errorf("tag_session: provide name, tags, add_tags or remove_tags")
+	}
+
+	sess, err := s.sessionStore.LabelSession(ctx, sessionID, labels)
+	if errors.Is(err, gorm.ErrSessionNotFound) {
+		return "", fmt.Errorf("tag_session: session %q not found", sessionID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("tag_session: %w", err)
+	}
+	data, err := json.Marshal(sess)
+	if err != nil {
+		return "", fmt.Errorf("marshal session: %w", err)
+	}
+	return string(data), nil
+}
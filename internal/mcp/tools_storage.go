@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/thebtf/engram/internal/auth"
+	"github.com/thebtf/engram/internal/db/gorm"
+)
+
+// SetStorageStatsStore wires database size reporting, so the
+// get_storage_stats tool is advertised.
+func (s *Server) SetStorageStatsStore(store *gorm.StorageStatsStore) {
+	s.storageStatsStore = store
+}
+
+// handleGetStorageStats reports the database size, the largest tables and
+// the per-project breakdown with 30-day growth, optionally for one project.
+// The figures cover every tenant, so tenant-scoped tokens are rejected; no
+// identity means auth is disabled, which is allowed.
+func (s *Server) handleGetStorageStats(ctx context.Context, args json.RawMessage) (string, error) {
+	if id, ok := auth.IdentityFrom(ctx); ok && !id.IsAdmin() {
+		return "", fmt.Errorf("get_storage_stats: admin access required")
+	}
+	if s.storageStatsStore == nil {
+		return "", fmt.Errorf("storage stats not available")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+
+	stats, err := s.storageStatsStore.Stats(ctx, coerceString(m["project"], ""))
+	if err != nil {
+		return "", fmt.Errorf("get storage stats: %w", err)
+	}
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal storage stats: %w", err)
+	}
+	return string(data), nil
+}
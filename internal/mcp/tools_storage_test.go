@@ -0,0 +1,31 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/auth"
+)
+
+func TestGetStorageStats_RequiresAdmin(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(ServerOptions{Version: "1.0.0"})
+	tenantKey := auth.WithIdentity(context.Background(), auth.Identity{Role: auth.RoleReadWrite, Source: auth.SourceClient, Tenant: "acme"})
+	admin := auth.WithIdentity(context.Background(), auth.Identity{Role: auth.RoleAdmin, Source: auth.SourceMaster})
+
+	_, err := server.handleGetStorageStats(tenantKey, json.RawMessage(`{}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "admin access required")
+
+	// Admins and auth-disabled callers get past the check; no store is wired here.
+	for _, ctx := range []context.Context{admin, context.Background()} {
+		_, err = server.handleGetStorageStats(ctx, json.RawMessage(`{}`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not available")
+	}
+}
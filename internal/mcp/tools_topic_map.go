@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// Topic map limits.
+const (
+	topicMapDefaultTopics = 10
+	topicMapMaxTopics     = 50
+	topicMapDefaultLimit  = 500
+	topicMapSubtopics     = 5
+	topicMapSampleIDs     = 5
+)
+
+// topicLabel is a concept tag with the number of memories carrying it.
+type topicLabel struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// topicCluster is one top-level topic of a topic map.
+type topicCluster struct {
+	Types     map[string]int `json:"types"`
+	Label     string         `json:"label"`
+	Subtopics []topicLabel   `json:"subtopics,omitempty"`
+	MemoryIDs []int64        `json:"memory_ids"`
+	Count     int            `json:"count"`
+}
+
+// topicMap groups a project's memories into labelled topics.
+type topicMap struct {
+	Topics        []topicCluster `json:"topics"`
+	MemoryCount   int            `json:"memory_count"`
+	OtherCount    int            `json:"other_count"`
+	UntaggedCount int            `json:"untagged_count"`
+}
+
+// topicConcepts returns the concept tags of a memory: every tag except the
+// type: and scope: bookkeeping tags, lower-cased and de-duplicated.
+func topicConcepts(tags []string) []string {
+	seen := make(map[string]struct{}, len(tags))
+	concepts := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || strings.HasPrefix(tag, "type:") || strings.HasPrefix(tag, "scope:") {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		concepts = append(concepts, tag)
+	}
+	return concepts
+}
+
+// sortedLabels orders counts by count descending, then label, and keeps at
+// most n entries (all when n <= 0).
+func sortedLabels(counts map[string]int, n int) []topicLabel {
+	labels := make([]topicLabel, 0, len(counts))
+	for label, count := range counts {
+		labels = append(labels, topicLabel{Label: label, Count: count})
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].Count != labels[j].Count {
+			return labels[i].Count > labels[j].Count
+		}
+		return labels[i].Label < labels[j].Label
+	})
+	if n > 0 && len(labels) > n {
+		labels = labels[:n]
+	}
+	return labels
+}
+
+// buildTopicMap clusters memories by concept tag. Each memory joins the topic
+// of its most widespread concept, so broad concepts become top-level topics
+// and the concepts that co-occur inside a topic become its subtopics. Topics
+// beyond maxTopics are folded into OtherCount; memories without concept tags
+// are counted in UntaggedCount.
+func buildTopicMap(memories []*models.Memory, maxTopics int) topicMap {
+	result := topicMap{MemoryCount: len(memories), Topics: []topicCluster{}}
+
+	frequency := make(map[string]int)
+	concepts := make([][]string, len(memories))
+	for i, mem := range memories {
+		concepts[i] = topicConcepts(mem.Tags)
+		for _, c := range concepts[i] {
+			frequency[c]++
+		}
+	}
+
+	members := make(map[string][]int)
+	for i, cs := range concepts {
+		if len(cs) == 0 {
+			result.UntaggedCount++
+			continue
+		}
+		best := cs[0]
+		for _, c := range cs[1:] {
+			if frequency[c] > frequency[best] || (frequency[c] == frequency[best] && c < best) {
+				best = c
+			}
+		}
+		members[best] = append(members[best], i)
+	}
+
+	sizes := make(map[string]int, len(members))
+	for label, idx := range members {
+		sizes[label] = len(idx)
+	}
+	for rank, topic := range sortedLabels(sizes, 0) {
+		if rank >= maxTopics {
+			result.OtherCount += topic.Count
+			continue
+		}
+		cluster := topicCluster{Label: topic.Label, Count: topic.Count, Types: make(map[string]int)}
+		co := make(map[string]int)
+		for _, i := range members[topic.Label] {
+			cluster.Types[digestType(memories[i].Tags)]++
+			for _, c := range concepts[i] {
+				if c != topic.Label {
+					co[c]++
+				}
+			}
+			if len(cluster.MemoryIDs) < topicMapSampleIDs {
+				cluster.MemoryIDs = append(cluster.MemoryIDs, memories[i].ID)
+			}
+		}
+		cluster.Subtopics = sortedLabels(co, topicMapSubtopics)
+		result.Topics = append(result.Topics, cluster)
+	}
+	return result
+}
+
+// handleBuildTopicMap returns a concept-tag topic map of a project's memories.
+func (s *Server) handleBuildTopicMap(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryStore == nil {
+		return "", fmt.Errorf("build_topic_map: memory store not configured")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	project := coerceString(m["project"], "")
+	if project == "" {
+		return "", fmt.Errorf("build_topic_map: project is required")
+	}
+	maxTopics := coerceInt(m["max_topics"], topicMapDefaultTopics)
+	if maxTopics <= 0 {
+		maxTopics = topicMapDefaultTopics
+	}
+	if maxTopics > topicMapMaxTopics {
+		maxTopics = topicMapMaxTopics
+	}
+	limit := coerceInt(m["limit"], topicMapDefaultLimit)
+	if limit <= 0 || limit > gorm.MaxPaginationLimit {
+		limit = topicMapDefaultLimit
+	}
+
+	memories, err := s.memoryStore.List(ctx, project, limit)
+	if err != nil {
+		return "", fmt.Errorf("build_topic_map: %w", err)
+	}
+
+	output, err := json.Marshal(struct {
+		Project string `json:"project"`
+		topicMap
+	}{Project: project, topicMap: buildTopicMap(memories, maxTopics)})
+	if err != nil {
+		return "", fmt.Errorf("marshal response: %w", err)
+	}
+	return string(output), nil
+}
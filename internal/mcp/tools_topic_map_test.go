@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestBuildTopicMap(t *testing.T) {
+	memories := []*models.Memory{
+		{ID: 1, Tags: []string{"type:decision", "auth", "jwt"}},
+		{ID: 2, Tags: []string{"type:pattern", "auth", "retry"}},
+		{ID: 3, Tags: []string{"Auth", "jwt", "scope:project"}},
+		{ID: 4, Tags: []string{"migrations"}},
+		{ID: 5, Tags: []string{"type:decision", "scope:global"}},
+		{ID: 6, Tags: []string{"ci"}},
+	}
+
+	tm := buildTopicMap(memories, 2)
+	assert.Equal(t, 6, tm.MemoryCount)
+	assert.Equal(t, 1, tm.UntaggedCount, "memory 5 has only bookkeeping tags")
+	require.Len(t, tm.Topics, 2)
+
+	auth := tm.Topics[0]
+	assert.Equal(t, "auth", auth.Label)
+	assert.Equal(t, 3, auth.Count)
+	assert.Equal(t, []int64{1, 2, 3}, auth.MemoryIDs)
+	assert.Equal(t, map[string]int{"decision": 1, "pattern": 1, "memory": 1}, auth.Types)
+	assert.Equal(t, []topicLabel{{Label: "jwt", Count: 2}, {Label: "retry", Count: 1}}, auth.Subtopics)
+
+	// ci and migrations tie on size; labels break the tie, and the topic past
+	// max_topics is folded into other.
+	assert.Equal(t, "ci", tm.Topics[1].Label)
+	assert.Equal(t, 1, tm.OtherCount)
+}
+
+func TestBuildTopicMap_Empty(t *testing.T) {
+	tm := buildTopicMap(nil, 10)
+	assert.Zero(t, tm.MemoryCount)
+	assert.NotNil(t, tm.Topics)
+	assert.Empty(t, tm.Topics)
+}
@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	gormlib "gorm.io/gorm"
+
+	"github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// handleSetObservationVisibility changes who a memory is returned to. Private
+// memories are hidden from every client but their owner, so only the owner
+// may change the visibility; memories created without authentication are
+// claimed by the first client to change it.
+func (s *Server) handleSetObservationVisibility(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryStore == nil {
+		return "", fmt.Errorf("memory store not available")
+	}
+
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+
+	id := coerceInt64(m["id"], 0)
+	if id <= 0 {
+		return "", fmt.Errorf("id is required and must be a positive integer")
+	}
+	visibility := coerceString(m["visibility"], "")
+	if !models.IsValidVisibility(visibility) {
+		return "", fmt.Errorf("visibility must be one of private, team, public")
+	}
+
+	updated, err := s.memoryStore.SetVisibility(ctx, id, models.Visibility(visibility))
+	if err != nil {
+		switch {
+		case errors.Is(err, gormlib.ErrRecordNotFound):
+			return "", fmt.Errorf("memory %d not found", id)
+		case errors.Is(err, gorm.ErrMemoryNotOwner):
+			return "", fmt.Errorf("memory %d was created by another client; only its owner can change its visibility", id)
+		}
+		return "", fmt.Errorf("set visibility: %w", err)
+	}
+
+	out := map[string]any{
+		"id":         updated.ID,
+		"project":    updated.Project,
+		"visibility": updated.Visibility,
+		"owner":      updated.Owner,
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal result: %w", err)
+	}
+	return string(data), nil
+}
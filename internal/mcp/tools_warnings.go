@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/thebtf/engram/pkg/models"
+	"github.com/thebtf/engram/pkg/similarity"
+)
+
+// warningScanLimit caps the warning memories one find_warnings call reads
+// before ranking them against the query.
+const warningScanLimit = 500
+
+// warningResult is one memory returned by find_warnings.
+type warningResult struct {
+	Polarity models.Polarity `json:"polarity"`
+	Content  string          `json:"content"`
+	Tags     []string        `json:"tags"`
+	ID       int64           `json:"id"`
+	Matched  int             `json:"matched_terms,omitempty"`
+}
+
+// rankWarnings keeps the memories sharing at least one term with query, most
+// shared terms first, and returns at most limit of them. With no query terms
+// every memory is kept in its original (newest first) order.
+func rankWarnings(mems []*models.Memory, query string, limit int) []warningResult {
+	queryTerms := similarity.ExtractTextTerms(query)
+	results := make([]warningResult, 0, len(mems))
+	for _, mem := range mems {
+		matched := 0
+		if len(queryTerms) > 0 {
+			for term := range similarity.ExtractMemoryTerms(mem) {
+				if queryTerms[term] {
+					matched++
+				}
+			}
+			if matched == 0 {
+				continue
+			}
+		}
+		results = append(results, warningResult{
+			ID:       mem.ID,
+			Polarity: models.PolarityFromTags(mem.Tags),
+			Content:  mem.Content,
+			Tags:     mem.Tags,
+			Matched:  matched,
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Matched > results[j].Matched })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// handleFindWarnings lists the project's cautions and known-bad approaches
+// (memories stored with polarity caution or do_not), optionally narrowed to
+// those sharing terms with a query. Use it before trying an approach to see
+// whether it already failed.
+func (s *Server) handleFindWarnings(ctx context.Context, args json.RawMessage) (string, error) {
+	if s.memoryStore == nil {
+		return "", fmt.Errorf("memory store not available")
+	}
+	m, err := parseArgs(args)
+	if err != nil {
+		return "", err
+	}
+	project := projectFromContext(ctx)
+	if project == "" {
+		project = coerceString(m["project"], "")
+	}
+	if project == "" {
+		return "", fmt.Errorf("project is required for find_warnings")
+	}
+	query := coerceString(m["query"], "")
+	limit := coerceInt(m["limit"], 10)
+	if limit <= 0 {
+		limit = 10
+	} else if limit > 100 {
+		limit = 100
+	}
+
+	mems, err := s.memoryStore.ListWarnings(ctx, project, warningScanLimit)
+	if err != nil {
+		return "", fmt.Errorf("list warnings: %w", err)
+	}
+	warnings := rankWarnings(mems, query, limit)
+
+	out := map[string]any{
+		"project":  project,
+		"warnings": warnings,
+		"count":    len(warnings),
+	}
+	if query != "" {
+		out["query"] = query
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal result: %w", err)
+	}
+	return string(data), nil
+}
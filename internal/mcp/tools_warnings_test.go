@@ -0,0 +1,31 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestRankWarnings(t *testing.T) {
+	mems := []*models.Memory{
+		{ID: 1, Content: "Retrying the websocket reconnect hides the race", Tags: []string{"polarity:caution"}},
+		{ID: 2, Content: "Do not vendor the payment SDK", Tags: []string{"polarity:do_not"}},
+		{ID: 3, Content: "Do not retry the payment webhook without an idempotency key", Tags: []string{"polarity:do_not"}},
+	}
+
+	all := rankWarnings(mems, "", 10)
+	require.Len(t, all, 3)
+	assert.Equal(t, int64(1), all[0].ID, "no query keeps the newest-first order")
+	assert.Equal(t, models.PolarityCaution, all[0].Polarity)
+
+	payment := rankWarnings(mems, "payment webhook", 10)
+	require.Len(t, payment, 2)
+	assert.Equal(t, int64(3), payment[0].ID, "more shared terms rank first")
+	assert.Equal(t, int64(2), payment[1].ID)
+
+	assert.Len(t, rankWarnings(mems, "payment", 1), 1)
+	assert.Empty(t, rankWarnings(mems, "kubernetes", 10))
+}
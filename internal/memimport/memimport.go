@@ -0,0 +1,362 @@
+// Package memimport converts the memory exports of other tools (mem0, Letta,
+// the MCP memory server, plain JSON) into memories, so switching to engram
+// keeps what the old tool remembered.
+package memimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// Format names an export format Parse understands.
+type Format string
+
+// Supported formats.
+const (
+	// FormatEngram is the JSON array written by `engram export`.
+	FormatEngram Format = "engram"
+	// FormatMem0 is mem0's get_all output: an array of memories, or an
+	// object holding them under "results" or "memories".
+	FormatMem0 Format = "mem0"
+	// FormatLetta is a Letta agent file (.af) or a list of archival
+	// passages. Core memory blocks and passages both become memories.
+	FormatLetta Format = "letta"
+	// FormatClaudeMemory is the knowledge graph of the MCP memory server:
+	// memory.json (one entity or relation per line) or read_graph output.
+	FormatClaudeMemory Format = "claude-memory"
+	// FormatJSON is any array of strings or of objects with a content,
+	// text, memory or value field.
+	FormatJSON Format = "json"
+)
+
+// Formats lists the supported formats in the order shown to users.
+var Formats = []Format{FormatEngram, FormatMem0, FormatLetta, FormatClaudeMemory, FormatJSON}
+
+// Parse converts data in the given format into memories. Imported memories
+// carry the source tool as SourceAgent (except for FormatEngram, which keeps
+// the exported one) and have no Project unless the export names one; the
+// caller decides where they go. Entries without content are skipped.
+//
+// Source scores (mem0 search scores, importance values) are not carried
+// over: memories have no stored score and are ranked at search time.
+func Parse(format Format, data []byte) ([]models.Memory, error) {
+	var (
+		mems []models.Memory
+		err  error
+	)
+	switch format {
+	case FormatEngram:
+		err = json.Unmarshal(data, &mems)
+	case FormatMem0:
+		mems, err = parseMem0(data)
+	case FormatLetta:
+		mems, err = parseLetta(data)
+	case FormatClaudeMemory:
+		mems, err = parseClaudeMemory(data)
+	case FormatJSON:
+		mems, err = parsePlain(data)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s export: %w", format, err)
+	}
+
+	out := mems[:0]
+	for _, mem := range mems {
+		mem.Content = strings.TrimSpace(mem.Content)
+		if mem.Content == "" {
+			continue
+		}
+		mem.Tags = cleanTags(mem.Tags)
+		out = append(out, mem)
+	}
+	return out, nil
+}
+
+// unwrap returns the array held in data, either data itself or the value of
+// the first of keys present in a top-level object.
+func unwrap(data []byte, keys ...string) (json.RawMessage, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		return data, nil
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if raw, ok := obj[key]; ok {
+			return raw, nil
+		}
+	}
+	return nil, fmt.Errorf("expected an array or an object with %s", strings.Join(keys, " or "))
+}
+
+type mem0Memory struct {
+	Metadata   map[string]any `json:"metadata"`
+	Memory     string         `json:"memory"`
+	Categories []string       `json:"categories"`
+}
+
+func parseMem0(data []byte) ([]models.Memory, error) {
+	raw, err := unwrap(data, "results", "memories")
+	if err != nil {
+		return nil, err
+	}
+	var items []mem0Memory
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, err
+	}
+	mems := make([]models.Memory, 0, len(items))
+	for _, item := range items {
+		tags := append([]string(nil), item.Categories...)
+		tags = append(tags, stringList(item.Metadata["tags"])...)
+		mems = append(mems, models.Memory{
+			Content:     item.Memory,
+			Tags:        tags,
+			SourceAgent: "mem0",
+		})
+	}
+	return mems, nil
+}
+
+type lettaBlock struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+type lettaPassage struct {
+	Text string   `json:"text"`
+	Tags []string `json:"tags"`
+}
+
+type lettaAgent struct {
+	CoreMemory   []lettaBlock   `json:"core_memory"`
+	MemoryBlocks []lettaBlock   `json:"memory_blocks"`
+	Blocks       []lettaBlock   `json:"blocks"`
+	Passages     []lettaPassage `json:"passages"`
+}
+
+// lettaFile covers both agent file layouts: a single agent at the top
+// level, and the newer schema listing agents and a shared block pool.
+type lettaFile struct {
+	lettaAgent
+	Agents []lettaAgent `json:"agents"`
+}
+
+func parseLetta(data []byte) ([]models.Memory, error) {
+	data = bytes.TrimSpace(data)
+	var file lettaFile
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &file.Passages); err != nil {
+			return nil, err
+		}
+	} else if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	var mems []models.Memory
+	addAgent := func(agent lettaAgent) {
+		blocks := append(append(append([]lettaBlock(nil), agent.CoreMemory...), agent.MemoryBlocks...), agent.Blocks...)
+		for _, block := range blocks {
+			mems = append(mems, models.Memory{
+				Content:     block.Value,
+				Tags:        []string{block.Label},
+				SourceAgent: "letta",
+			})
+		}
+		for _, p := range agent.Passages {
+			mems = append(mems, models.Memory{
+				Content:     p.Text,
+				Tags:        p.Tags,
+				SourceAgent: "letta",
+			})
+		}
+	}
+	addAgent(file.lettaAgent)
+	for _, agent := range file.Agents {
+		addAgent(agent)
+	}
+	return mems, nil
+}
+
+type graphEntity struct {
+	Name         string   `json:"name"`
+	EntityType   string   `json:"entityType"`
+	Observations []string `json:"observations"`
+}
+
+type graphRelation struct {
+	From         string `json:"from"`
+	To           string `json:"to"`
+	RelationType string `json:"relationType"`
+}
+
+type graph struct {
+	Entities  []graphEntity   `json:"entities"`
+	Relations []graphRelation `json:"relations"`
+}
+
+// readGraph reads read_graph output, or memory.json with one entity or
+// relation per line.
+func readGraph(data []byte) (graph, error) {
+	var g graph
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' && json.Unmarshal(trimmed, &g) == nil && (g.Entities != nil || g.Relations != nil) {
+		return g, nil
+	}
+	g = graph{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var kind struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(text, &kind); err != nil {
+			return g, fmt.Errorf("line %d: %w", line, err)
+		}
+		var err error
+		switch kind.Type {
+		case "entity":
+			var e graphEntity
+			err = json.Unmarshal(text, &e)
+			g.Entities = append(g.Entities, e)
+		case "relation":
+			var r graphRelation
+			err = json.Unmarshal(text, &r)
+			g.Relations = append(g.Relations, r)
+		}
+		if err != nil {
+			return g, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	return g, scanner.Err()
+}
+
+// parseClaudeMemory turns each entity of the knowledge graph into one
+// memory listing its observations and outgoing relations.
+func parseClaudeMemory(data []byte) ([]models.Memory, error) {
+	graph, err := readGraph(data)
+	if err != nil {
+		return nil, err
+	}
+
+	outgoing := make(map[string][]graphRelation)
+	for _, r := range graph.Relations {
+		outgoing[r.From] = append(outgoing[r.From], r)
+	}
+	mems := make([]models.Memory, 0, len(graph.Entities))
+	for _, e := range graph.Entities {
+		if len(e.Observations) == 0 && len(outgoing[e.Name]) == 0 {
+			continue
+		}
+		var b strings.Builder
+		b.WriteString(e.Name)
+		if e.EntityType != "" {
+			fmt.Fprintf(&b, " (%s)", e.EntityType)
+		}
+		for _, o := range e.Observations {
+			fmt.Fprintf(&b, "\n- %s", o)
+		}
+		for _, r := range outgoing[e.Name] {
+			fmt.Fprintf(&b, "\n- %s %s", r.RelationType, r.To)
+		}
+		var tags []string
+		if e.EntityType != "" {
+			tags = []string{strings.ToLower(e.EntityType)}
+		}
+		mems = append(mems, models.Memory{
+			Content:     b.String(),
+			Tags:        tags,
+			SourceAgent: "claude-memory",
+		})
+	}
+	return mems, nil
+}
+
+// plainContentKeys are the fields a plain JSON object's content is read
+// from, first non-empty wins.
+var plainContentKeys = []string{"content", "text", "memory", "value", "note"}
+
+func parsePlain(data []byte) ([]models.Memory, error) {
+	raw, err := unwrap(data, "memories", "items", "data")
+	if err != nil {
+		return nil, err
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, err
+	}
+	mems := make([]models.Memory, 0, len(items))
+	for i, item := range items {
+		var text string
+		if json.Unmarshal(item, &text) == nil {
+			mems = append(mems, models.Memory{Content: text, SourceAgent: "import"})
+			continue
+		}
+		var obj map[string]any
+		if err := json.Unmarshal(item, &obj); err != nil {
+			return nil, fmt.Errorf("item %d: expected a string or an object", i)
+		}
+		mem := models.Memory{SourceAgent: "import"}
+		for _, key := range plainContentKeys {
+			if s, ok := obj[key].(string); ok && strings.TrimSpace(s) != "" {
+				mem.Content = s
+				break
+			}
+		}
+		for _, key := range []string{"tags", "categories", "labels"} {
+			mem.Tags = append(mem.Tags, stringList(obj[key])...)
+		}
+		if s, ok := obj["project"].(string); ok {
+			mem.Project = s
+		}
+		mems = append(mems, mem)
+	}
+	return mems, nil
+}
+
+// stringList reads a list of strings or a comma-separated string.
+func stringList(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return strings.Split(v, ",")
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// cleanTags trims tags and drops empties and duplicates, keeping order.
+func cleanTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(tags))
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package memimport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestParseMem0(t *testing.T) {
+	data := `{"results": [
+		{"id": "a1", "memory": "Prefers tabs over spaces", "categories": ["preferences"], "metadata": {"tags": "style, editor"}, "score": 0.92},
+		{"id": "a2", "memory": "  "}
+	]}`
+	mems, err := Parse(FormatMem0, []byte(data))
+	require.NoError(t, err)
+	require.Len(t, mems, 1)
+	assert.Equal(t, models.Memory{
+		Content:     "Prefers tabs over spaces",
+		Tags:        []string{"preferences", "style", "editor"},
+		SourceAgent: "mem0",
+	}, mems[0])
+
+	mems, err = Parse(FormatMem0, []byte(`[{"memory": "Deploys on Fridays are frozen"}]`))
+	require.NoError(t, err)
+	require.Len(t, mems, 1)
+}
+
+func TestParseLetta(t *testing.T) {
+	agentFile := `{
+		"agents": [{"core_memory": [{"label": "human", "value": "Name is Sam"}], "passages": [{"text": "Uses Postgres 16", "tags": ["db"]}]}],
+		"blocks": [{"label": "persona", "value": "Terse reviewer"}]
+	}`
+	mems, err := Parse(FormatLetta, []byte(agentFile))
+	require.NoError(t, err)
+	require.Len(t, mems, 3)
+	assert.Equal(t, "Terse reviewer", mems[0].Content)
+	assert.Equal(t, []string{"persona"}, mems[0].Tags)
+	assert.Equal(t, "Name is Sam", mems[1].Content)
+	assert.Equal(t, []string{"db"}, mems[2].Tags)
+	assert.Equal(t, "letta", mems[2].SourceAgent)
+
+	mems, err = Parse(FormatLetta, []byte(`[{"text": "Archival note"}]`))
+	require.NoError(t, err)
+	require.Len(t, mems, 1)
+	assert.Equal(t, "Archival note", mems[0].Content)
+}
+
+func TestParseClaudeMemory(t *testing.T) {
+	jsonl := `{"type":"entity","name":"engram","entityType":"Project","observations":["Written in Go","Uses Postgres"]}
+{"type":"entity","name":"Empty","entityType":"Person","observations":[]}
+{"type":"relation","from":"engram","to":"chi","relationType":"depends on"}
+`
+	mems, err := Parse(FormatClaudeMemory, []byte(jsonl))
+	require.NoError(t, err)
+	require.Len(t, mems, 1)
+	assert.Equal(t, "engram (Project)\n- Written in Go\n- Uses Postgres\n- depends on chi", mems[0].Content)
+	assert.Equal(t, []string{"project"}, mems[0].Tags)
+
+	graph := `{"entities":[{"name":"Sam","entityType":"person","observations":["Owns billing"]}],"relations":[]}`
+	mems, err = Parse(FormatClaudeMemory, []byte(graph))
+	require.NoError(t, err)
+	require.Len(t, mems, 1)
+	assert.Equal(t, "Sam (person)\n- Owns billing", mems[0].Content)
+
+	_, err = Parse(FormatClaudeMemory, []byte("{\"type\":\"entity\"}\nnot json\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestParsePlain(t *testing.T) {
+	data := `{"memories": [
+		"Plain string memory",
+		{"text": "Object memory", "tags": ["a", "a", " b "], "project": "p"},
+		{"title": "no content"}
+	]}`
+	mems, err := Parse(FormatJSON, []byte(data))
+	require.NoError(t, err)
+	require.Len(t, mems, 2)
+	assert.Equal(t, "Plain string memory", mems[0].Content)
+	assert.Equal(t, models.Memory{Project: "p", Content: "Object memory", Tags: []string{"a", "b"}, SourceAgent: "import"}, mems[1])
+
+	_, err = Parse(FormatJSON, []byte(`[1]`))
+	assert.ErrorContains(t, err, "item 0")
+	_, err = Parse("yaml", []byte(`[]`))
+	assert.ErrorContains(t, err, "unknown format")
+}
@@ -183,3 +183,14 @@ type ProxyToolProvider interface {
 	// JSON-RPC -32603.
 	ProxyHandleTool(ctx context.Context, p muxcore.ProjectContext, name string, args json.RawMessage) (json.RawMessage, error)
 }
+
+// HealthReporter is implemented by modules that keep local state engram-server
+// cannot see, such as an on-disk database. The dispatcher appends their
+// findings to the result of the proxied check_system_health tool, so one
+// health check covers both the server and the daemon.
+type HealthReporter interface {
+	// HealthIssues returns human-readable problems with the module's local
+	// state, or nil when it is healthy. Called on every check_system_health
+	// call, so it MUST be fast: read a few settings, never scan data.
+	HealthIssues(ctx context.Context) []string
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/thebtf/engram/internal/module"
@@ -200,6 +201,11 @@ func (d *Dispatcher) handleToolsCall(ctx context.Context, p muxcore.ProjectConte
 
 	// Success: wrap the raw module result in the MCP content envelope.
 	content := []json.RawMessage{raw}
+	if params.Name == healthToolName {
+		if block := d.localHealthBlock(ctx); block != nil {
+			content = append(content, block)
+		}
+	}
 	result := map[string]any{
 		"content": content,
 		"isError": false,
@@ -207,6 +213,32 @@ func (d *Dispatcher) handleToolsCall(ctx context.Context, p muxcore.ProjectConte
 	return marshalResult(req.ID, result), nil
 }
 
+// healthToolName is the engram-server tool whose result the dispatcher
+// extends with the findings of module.HealthReporter modules.
+const healthToolName = "check_system_health"
+
+// localHealthBlock renders the issues reported by module.HealthReporter
+// modules as an MCP text content block, or returns nil when there are none.
+func (d *Dispatcher) localHealthBlock(ctx context.Context) json.RawMessage {
+	var lines []string
+	d.reg.ForEachHealthReporter(func(name string, h module.HealthReporter) {
+		for _, issue := range h.HealthIssues(ctx) {
+			lines = append(lines, fmt.Sprintf("- %s: %s", name, issue))
+		}
+	})
+	if len(lines) == 0 {
+		return nil
+	}
+	block, err := json.Marshal(map[string]any{
+		"type": "text",
+		"text": "Daemon module issues:\n" + strings.Join(lines, "\n"),
+	})
+	if err != nil {
+		return nil
+	}
+	return block
+}
+
 // isModuleError checks whether err is a *module.ModuleError and assigns it to
 // out if so. Returns true on match.
 func isModuleError(err error, out **module.ModuleError) bool {
@@ -387,3 +387,62 @@ func TestHandleToolsCall_ProxyModuleError_ReturnsResultLevelError(t *testing.T)
 		t.Errorf("content type: got %q, want text", result.Content[0].Type)
 	}
 }
+
+// healthMod is a module.HealthReporter with fixed issues.
+type healthMod struct {
+	name   string
+	issues []string
+}
+
+func (f *healthMod) Name() string                                      { return f.name }
+func (f *healthMod) Init(_ context.Context, _ module.ModuleDeps) error { return nil }
+func (f *healthMod) Shutdown(_ context.Context) error                  { return nil }
+func (f *healthMod) HealthIssues(_ context.Context) []string           { return f.issues }
+
+// TestHandleToolsCall_HealthIssuesAppended verifies that the findings of
+// HealthReporter modules are appended to check_system_health results, and
+// only to those.
+func TestHandleToolsCall_HealthIssuesAppended(t *testing.T) {
+	t.Parallel()
+
+	proxy := &proxyMod{name: "engramcore"}
+	d := buildDispatcher(t, proxy,
+		&healthMod{name: "loom", issues: []string{"tasks.db: journal_mode is delete, configured wal"}},
+		&healthMod{name: "quiet"},
+	)
+
+	call := func(tool string) []json.RawMessage {
+		t.Helper()
+		resp, err := d.HandleRequest(context.Background(), projectCtx("p1"), jsonrpcReq(1, "tools/call", map[string]any{"name": tool}))
+		if err != nil {
+			t.Fatalf("HandleRequest: %v", err)
+		}
+		var result struct {
+			Content []json.RawMessage `json:"content"`
+		}
+		if err := json.Unmarshal(parseResp(t, resp).Result, &result); err != nil {
+			t.Fatalf("unmarshal result: %v", err)
+		}
+		return result.Content
+	}
+
+	content := call("check_system_health")
+	if len(content) != 2 {
+		t.Fatalf("expected the server result plus one health block, got %d entries", len(content))
+	}
+	var block struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(content[1], &block); err != nil {
+		t.Fatalf("unmarshal health block: %v", err)
+	}
+	want := "Daemon module issues:\n- loom: tasks.db: journal_mode is delete, configured wal"
+	if block.Type != "text" || block.Text != want {
+		t.Errorf("health block: got %+v, want text %q", block, want)
+	}
+
+	if content := call("memory_store"); len(content) != 1 {
+		t.Errorf("other tools must not get the health block, got %d entries", len(content))
+	}
+}
@@ -36,4 +36,7 @@ type moduleEntry struct {
 	// At most one module in the registry may have a non-nil ProxyTool —
 	// enforced at Register time via [ErrMultipleProxyToolProviders].
 	ProxyTool module.ProxyToolProvider
+
+	// Health is non-nil if Module implements module.HealthReporter.
+	Health module.HealthReporter
 }
@@ -68,7 +68,7 @@ func (r *Registry) Register(m module.EngramModule) error {
 		}
 	}
 
-	// Capability discovery — five type assertions, results cached in entry.
+	// Capability discovery — six type assertions, results cached in entry.
 	entry := moduleEntry{Module: m}
 	if s, ok := m.(module.Snapshotter); ok {
 		entry.Snap = s
@@ -82,6 +82,9 @@ func (r *Registry) Register(m module.EngramModule) error {
 	if tp, ok := m.(module.ToolProvider); ok {
 		entry.ToolProv = tp
 	}
+	if h, ok := m.(module.HealthReporter); ok {
+		entry.Health = h
+	}
 	if ptp, ok := m.(module.ProxyToolProvider); ok {
 		// Single-instance enforcement — FR-11a. Reject the second proxy
 		// provider before mutating any registry state so partial-registration
@@ -190,6 +193,16 @@ func (r *Registry) ForEachSnapshotter(fn func(name string, s module.Snapshotter)
 	}
 }
 
+// ForEachHealthReporter calls fn for each module that implements
+// module.HealthReporter, in registration order.
+func (r *Registry) ForEachHealthReporter(fn func(name string, h module.HealthReporter)) {
+	for i := range r.entries {
+		if r.entries[i].Health != nil {
+			fn(r.entries[i].Module.Name(), r.entries[i].Health)
+		}
+	}
+}
+
 // GetProxyToolProvider returns the registered proxy tool provider and its
 // owning module name, or (nil, "", false) if none is registered.
 //
@@ -223,6 +236,8 @@ type Entry struct {
 	ToolProv module.ToolProvider
 	// ProxyTool is non-nil if Module implements module.ProxyToolProvider.
 	ProxyTool module.ProxyToolProvider
+	// Health is non-nil if Module implements module.HealthReporter.
+	Health module.HealthReporter
 }
 
 // ListLifecycleHandlers returns a slice of all modules that implement
@@ -278,6 +293,7 @@ func (r *Registry) Entries() []Entry {
 			RemovalAware: e.RemovalAware,
 			ToolProv:     e.ToolProv,
 			ProxyTool:    e.ProxyTool,
+			Health:       e.Health,
 		}
 	}
 	return result
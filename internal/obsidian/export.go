@@ -0,0 +1,274 @@
+// Package obsidian writes memories as interlinked Markdown notes that
+// Obsidian (and Logseq, which reads the same format) can open as a vault.
+package obsidian
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+const (
+	// idKey is the frontmatter key that marks a note as written by Export.
+	idKey = "engram_id"
+	// maxTitleRunes caps the title part of a note's file name.
+	maxTitleRunes = 60
+)
+
+// Options controls Export.
+type Options struct {
+	// Sync removes the notes of memories that are no longer exported
+	// (deleted or merged away), keeping the directory a mirror of memory.
+	Sync bool
+}
+
+// Result counts the notes Export touched.
+type Result struct {
+	Written   int `json:"written"`
+	Unchanged int `json:"unchanged"`
+	Removed   int `json:"removed"`
+}
+
+// Export writes one note per memory into dir. Relations between exported
+// memories become [[wikilinks]] under a "Related" heading. Notes whose
+// content did not change are not rewritten, so repeated exports only touch
+// what changed; a note whose title changed is renamed. Files without an
+// engram_id in their frontmatter are never modified.
+func Export(dir string, memories []*models.Memory, relations []*models.ObservationRelation, opts Options) (Result, error) {
+	var res Result
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return res, err
+	}
+	existing, err := scanNotes(dir)
+	if err != nil {
+		return res, err
+	}
+
+	names := make(map[int64]string, len(memories))
+	for _, mem := range memories {
+		names[mem.ID] = noteName(mem)
+	}
+	links := linksByMemory(relations, names)
+
+	for _, mem := range memories {
+		name := names[mem.ID]
+		path := filepath.Join(dir, name+".md")
+		if old, ok := existing[mem.ID]; ok && old != name+".md" {
+			if err := os.Remove(filepath.Join(dir, old)); err != nil && !os.IsNotExist(err) {
+				return res, err
+			}
+		}
+		note := renderNote(mem, links[mem.ID])
+		if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, note) {
+			res.Unchanged++
+			continue
+		}
+		if err := os.WriteFile(path, note, 0o600); err != nil {
+			return res, err
+		}
+		res.Written++
+	}
+
+	if opts.Sync {
+		for id, file := range existing {
+			if _, ok := names[id]; ok {
+				continue
+			}
+			if err := os.Remove(filepath.Join(dir, file)); err != nil && !os.IsNotExist(err) {
+				return res, err
+			}
+			res.Removed++
+		}
+	}
+	return res, nil
+}
+
+// link is one relation as seen from a note.
+type link struct {
+	relation models.RelationType
+	target   string // note name of the other memory
+	incoming bool
+}
+
+// linksByMemory indexes relations by both of their memories, keeping only
+// relations between exported memories.
+func linksByMemory(relations []*models.ObservationRelation, names map[int64]string) map[int64][]link {
+	out := make(map[int64][]link)
+	for _, rel := range relations {
+		source, okSource := names[rel.SourceID]
+		target, okTarget := names[rel.TargetID]
+		if !okSource || !okTarget || rel.SourceID == rel.TargetID {
+			continue
+		}
+		out[rel.SourceID] = append(out[rel.SourceID], link{relation: rel.RelationType, target: target})
+		out[rel.TargetID] = append(out[rel.TargetID], link{relation: rel.RelationType, target: source, incoming: true})
+	}
+	return out
+}
+
+// noteName returns the file name (without .md) of a memory's note: its
+// title followed by its ID, so names stay unique and recognisable.
+func noteName(mem *models.Memory) string {
+	title := []rune(strings.TrimSpace(safeTitle(noteTitle(mem.Content))))
+	if len(title) > maxTitleRunes {
+		title = []rune(strings.TrimSpace(string(title[:maxTitleRunes])))
+	}
+	if len(title) == 0 {
+		return fmt.Sprintf("memory (%d)", mem.ID)
+	}
+	return fmt.Sprintf("%s (%d)", string(title), mem.ID)
+}
+
+// noteTitle returns the first non-empty line of content without Markdown
+// heading marks.
+func noteTitle(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#")); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// safeTitle replaces the characters that file systems or wikilinks reject.
+func safeTitle(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', '[', ']', '#', '^':
+			return ' '
+		}
+		if r < 0x20 {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// renderNote returns the Markdown note of a memory: YAML frontmatter with
+// its metadata and tags, the content, and its relations as wikilinks.
+func renderNote(mem *models.Memory, links []link) []byte {
+	var b bytes.Buffer
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "%s: %d\n", idKey, mem.ID)
+	fmt.Fprintf(&b, "project: %s\n", strconv.Quote(mem.Project))
+	if t := models.TypeFromTags(mem.Tags); t != "" {
+		fmt.Fprintf(&b, "type: %s\n", strconv.Quote(string(t)))
+	}
+	fmt.Fprintf(&b, "created: %s\n", mem.CreatedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "updated: %s\n", mem.UpdatedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "version: %d\n", mem.Version)
+	if mem.SourceAgent != "" {
+		fmt.Fprintf(&b, "source_agent: %s\n", strconv.Quote(mem.SourceAgent))
+	}
+	if mem.Pinned {
+		b.WriteString("pinned: true\n")
+	}
+	if tags := noteTags(mem.Tags); len(tags) > 0 {
+		b.WriteString("tags:\n")
+		for _, tag := range tags {
+			fmt.Fprintf(&b, "  - %s\n", strconv.Quote(tag))
+		}
+	}
+	b.WriteString("---\n\n")
+	b.WriteString(strings.TrimSpace(mem.Content))
+	b.WriteString("\n")
+
+	if len(links) > 0 {
+		sorted := append([]link(nil), links...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			if sorted[i].incoming != sorted[j].incoming {
+				return !sorted[i].incoming
+			}
+			if sorted[i].relation != sorted[j].relation {
+				return sorted[i].relation < sorted[j].relation
+			}
+			return sorted[i].target < sorted[j].target
+		})
+		b.WriteString("\n## Related\n\n")
+		for _, l := range sorted {
+			if l.incoming {
+				fmt.Fprintf(&b, "- [[%s]] %s this\n", l.target, l.relation)
+			} else {
+				fmt.Fprintf(&b, "- %s [[%s]]\n", l.relation, l.target)
+			}
+		}
+	}
+	return b.Bytes()
+}
+
+// noteTags converts memory tags to Obsidian tags: "field:value" becomes the
+// nested tag "field/value" and whitespace becomes "-".
+func noteTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(strings.ReplaceAll(strings.TrimSpace(tag), ":", "/")), "-")
+		tag = strings.Trim(tag, "/#")
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
+}
+
+// scanNotes maps the memory ID of every note in dir written by Export to its
+// file name.
+func scanNotes(dir string) (map[int64]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	notes := make(map[int64]string)
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".md" {
+			continue
+		}
+		id, err := noteID(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if id > 0 {
+			notes[id] = e.Name()
+		}
+	}
+	return notes, nil
+}
+
+// noteID returns the engram_id of the note at path, or 0 when the note has
+// no frontmatter or no engram_id in it.
+func noteID(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	if !sc.Scan() || strings.TrimSpace(sc.Text()) != "---" {
+		return 0, nil
+	}
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "---" {
+			break
+		}
+		if v, ok := strings.CutPrefix(line, idKey+":"); ok {
+			id, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				return 0, nil
+			}
+			return id, nil
+		}
+	}
+	return 0, sc.Err()
+}
@@ -0,0 +1,61 @@
+package obsidian
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestNoteName(t *testing.T) {
+	assert.Equal(t, "Use JWT  not sessions (7)", noteName(&models.Memory{ID: 7, Content: "\n## Use JWT: not sessions\nbody"}))
+	assert.Equal(t, "memory (8)", noteName(&models.Memory{ID: 8, Content: "   "}))
+}
+
+func TestExport(t *testing.T) {
+	dir := t.TempDir()
+	created := time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC)
+	auth := &models.Memory{ID: 1, Project: "p", Content: "Auth uses JWT", Tags: []string{"type:decision", "auth"}, CreatedAt: created, UpdatedAt: created, Version: 1}
+	fix := &models.Memory{ID: 2, Project: "p", Content: "Token refresh fix", CreatedAt: created, UpdatedAt: created, Version: 1}
+	relations := []*models.ObservationRelation{
+		{SourceID: 2, TargetID: 1, RelationType: models.RelationFixes},
+		{SourceID: 2, TargetID: 99, RelationType: models.RelationRelatesTo}, // not exported
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "My own note.md"), []byte("# mine\n"), 0o600))
+
+	res, err := Export(dir, []*models.Memory{auth, fix}, relations, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, Result{Written: 2}, res)
+
+	note, err := os.ReadFile(filepath.Join(dir, "Auth uses JWT (1).md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(note), "engram_id: 1\n")
+	assert.Contains(t, string(note), "type: \"decision\"\n")
+	assert.Contains(t, string(note), "  - \"type/decision\"\n  - \"auth\"\n")
+	assert.Contains(t, string(note), "- [[Token refresh fix (2)]] fixes this\n")
+	note, err = os.ReadFile(filepath.Join(dir, "Token refresh fix (2).md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(note), "- fixes [[Auth uses JWT (1)]]\n")
+	assert.NotContains(t, string(note), "relates_to")
+
+	// Unchanged notes are not rewritten; a retitled memory is renamed, and
+	// with Sync the notes of memories no longer exported are removed.
+	auth.Content = "Auth uses JWT with refresh tokens"
+	res, err = Export(dir, []*models.Memory{auth}, nil, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, Result{Written: 1}, res)
+	assert.NoFileExists(t, filepath.Join(dir, "Auth uses JWT (1).md"))
+	assert.FileExists(t, filepath.Join(dir, "Auth uses JWT with refresh tokens (1).md"))
+	assert.FileExists(t, filepath.Join(dir, "Token refresh fix (2).md"))
+
+	res, err = Export(dir, []*models.Memory{auth}, nil, Options{Sync: true})
+	require.NoError(t, err)
+	assert.Equal(t, Result{Unchanged: 1, Removed: 1}, res)
+	assert.NoFileExists(t, filepath.Join(dir, "Token refresh fix (2).md"))
+	assert.FileExists(t, filepath.Join(dir, "My own note.md"), "notes not written by Export are kept")
+}
@@ -0,0 +1,204 @@
+package privacy
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/thebtf/engram/internal/config"
+)
+
+// maxRedactionStatsProjects bounds the per-project redaction counters.
+const maxRedactionStatsProjects = 1000
+
+var (
+	// jwtPattern matches JSON Web Tokens; it is applied before the other
+	// secret patterns so tokens are counted as JWTs.
+	jwtPattern = regexp.MustCompile(`eyJ[a-zA-Z0-9_-]+\.eyJ[a-zA-Z0-9_-]+\.[a-zA-Z0-9_-]+`)
+
+	emailPattern = regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9-]+(\.[a-zA-Z0-9-]+)*\.[a-zA-Z]{2,}`)
+)
+
+// RedactionCounts reports how many values of each kind were redacted.
+type RedactionCounts struct {
+	Secrets int `json:"secrets"`
+	JWTs    int `json:"jwts"`
+	Emails  int `json:"emails"`
+	Custom  int `json:"custom"`
+}
+
+// Total returns the number of redacted values.
+func (c RedactionCounts) Total() int {
+	return c.Secrets + c.JWTs + c.Emails + c.Custom
+}
+
+func (c *RedactionCounts) add(o RedactionCounts) {
+	c.Secrets += o.Secrets
+	c.JWTs += o.JWTs
+	c.Emails += o.Emails
+	c.Custom += o.Custom
+}
+
+// Redactor scrubs text before it is stored: JWTs and the secret patterns are
+// replaced like RedactSecrets does, e-mail addresses (when enabled) and
+// custom pattern matches with [REDACTED:email] and [REDACTED:custom].
+type Redactor struct {
+	custom []*regexp.Regexp
+	emails bool
+}
+
+// NewRedactor compiles the custom patterns. An invalid pattern is an error.
+func NewRedactor(emails bool, patterns []string) (*Redactor, error) {
+	r := &Redactor{emails: emails}
+	for _, p := range patterns {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		r.custom = append(r.custom, re)
+	}
+	return r, nil
+}
+
+// Redact returns text with sensitive values replaced and the number of
+// values redacted per kind.
+func (r *Redactor) Redact(text string) (string, RedactionCounts) {
+	var counts RedactionCounts
+	if text == "" {
+		return text, counts
+	}
+
+	text = jwtPattern.ReplaceAllStringFunc(text, func(match string) string {
+		counts.JWTs++
+		return redactSecretMatch(match)
+	})
+	for _, pattern := range secretPatterns {
+		text = pattern.ReplaceAllStringFunc(text, func(match string) string {
+			counts.Secrets++
+			return redactSecretMatch(match)
+		})
+	}
+	if r.emails {
+		text, counts.Emails = redactEmails(text)
+	}
+	for _, pattern := range r.custom {
+		text = pattern.ReplaceAllStringFunc(text, func(string) string {
+			counts.Custom++
+			return "[REDACTED:custom]"
+		})
+	}
+	return text, counts
+}
+
+// redactEmails replaces e-mail addresses. A match directly followed by ":"
+// is an SSH remote (git@github.com:org/repo) and is kept.
+func redactEmails(text string) (string, int) {
+	locs := emailPattern.FindAllStringIndex(text, -1)
+	if len(locs) == 0 {
+		return text, 0
+	}
+	var b strings.Builder
+	count, last := 0, 0
+	for _, loc := range locs {
+		if loc[1] < len(text) && text[loc[1]] == ':' {
+			continue
+		}
+		b.WriteString(text[last:loc[0]])
+		b.WriteString("[REDACTED:email]")
+		last = loc[1]
+		count++
+	}
+	b.WriteString(text[last:])
+	return b.String(), count
+}
+
+// redactorCache holds the redactor of the current policy per project, keyed
+// by the settings it was built from so settings reloads take effect.
+var redactorCache sync.Map // project -> cachedRedactor
+
+type cachedRedactor struct {
+	redactor *Redactor
+	key      string
+}
+
+func redactorFor(policy config.RedactionPolicy, project string) *Redactor {
+	patterns := policy.PatternsFor(project)
+	key := strings.Join(patterns, "\x00")
+	if policy.Emails {
+		key = "emails\x00" + key
+	}
+	if v, ok := redactorCache.Load(project); ok {
+		if c := v.(cachedRedactor); c.key == key {
+			return c.redactor
+		}
+	}
+
+	r, err := NewRedactor(policy.Emails, patterns)
+	if err != nil {
+		log.Warn().Err(err).Str("project", project).Msg("Invalid redaction pattern; applying built-in rules only")
+		r, _ = NewRedactor(policy.Emails, nil)
+	}
+	redactorCache.Store(project, cachedRedactor{redactor: r, key: key})
+	return r
+}
+
+// RedactForStorage applies the configured redaction policy of project to
+// text and records the counts in the redaction stats.
+func RedactForStorage(project, text string) string {
+	if text == "" {
+		return text
+	}
+	redacted, counts := redactorFor(config.Get().Redaction, project).Redact(text)
+	if counts.Total() > 0 {
+		stats.record(project, counts)
+	}
+	return redacted
+}
+
+// RedactionStats is a snapshot of the redactions made since startup.
+type RedactionStats struct {
+	Projects map[string]RedactionCounts `json:"projects,omitempty"`
+	Total    RedactionCounts            `json:"total"`
+}
+
+type redactionStats struct {
+	projects map[string]RedactionCounts
+	total    RedactionCounts
+	mu       sync.Mutex
+}
+
+var stats = &redactionStats{projects: make(map[string]RedactionCounts)}
+
+func (s *redactionStats) record(project string, counts RedactionCounts) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.total.add(counts)
+	if project == "" {
+		return
+	}
+	if _, ok := s.projects[project]; !ok && len(s.projects) >= maxRedactionStatsProjects {
+		return
+	}
+	c := s.projects[project]
+	c.add(counts)
+	s.projects[project] = c
+}
+
+// Stats returns the redaction counts since startup. When project is set,
+// Projects holds only that project.
+func Stats(project string) RedactionStats {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+	snapshot := RedactionStats{Total: stats.total, Projects: make(map[string]RedactionCounts)}
+	for name, c := range stats.projects {
+		if project == "" || name == project {
+			snapshot.Projects[name] = c
+		}
+	}
+	return snapshot
+}
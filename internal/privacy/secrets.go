@@ -142,37 +142,40 @@ func RedactSecrets(text string) string {
 
 	result := text
 	for _, pattern := range secretPatterns {
-		result = pattern.ReplaceAllStringFunc(result, func(match string) string {
-			hashPrefix := deriveHashPrefix(match)
-			redacted := "[REDACTED:" + hashPrefix + "]"
-
-			// Preserve the key name by cutting at the earliest key/value separator.
-			// Checking "=" before ":" is wrong when the value itself contains "=" (e.g.
-			// password: "a=b"), which would expose part of the secret. Instead, pick the
-			// separator that appears first in the string.
-			idxEq := strings.Index(match, "=")
-			idxColon := strings.Index(match, ":")
-			var sepIdx int
-			switch {
-			case idxEq == -1:
-				sepIdx = idxColon
-			case idxColon == -1:
-				sepIdx = idxEq
-			case idxEq < idxColon:
-				sepIdx = idxEq
-			default:
-				sepIdx = idxColon
-			}
-			if sepIdx != -1 {
-				return match[:sepIdx+1] + redacted
-			}
-			// For standalone secrets (like sk-xxx, ghp_xxx), show prefix + hash
-			if len(match) > 8 {
-				return match[:4] + "..." + redacted
-			}
-			return redacted
-		})
+		result = pattern.ReplaceAllStringFunc(result, redactSecretMatch)
 	}
 	return result
 }
 
+// redactSecretMatch returns the redacted form of a single secret match.
+func redactSecretMatch(match string) string {
+	hashPrefix := deriveHashPrefix(match)
+	redacted := "[REDACTED:" + hashPrefix + "]"
+
+	// Preserve the key name by cutting at the earliest key/value separator.
+	// Checking "=" before ":" is wrong when the value itself contains "=" (e.g.
+	// password: "a=b"), which would expose part of the secret. Instead, pick the
+	// separator that appears first in the string.
+	idxEq := strings.Index(match, "=")
+	idxColon := strings.Index(match, ":")
+	var sepIdx int
+	switch {
+	case idxEq == -1:
+		sepIdx = idxColon
+	case idxColon == -1:
+		sepIdx = idxEq
+	case idxEq < idxColon:
+		sepIdx = idxEq
+	default:
+		sepIdx = idxColon
+	}
+	if sepIdx != -1 {
+		return match[:sepIdx+1] + redacted
+	}
+	// For standalone secrets (like sk-xxx, ghp_xxx), show prefix + hash
+	if len(match) > 8 {
+		return match[:4] + "..." + redacted
+	}
+	return redacted
+}
+
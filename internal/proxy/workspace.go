@@ -0,0 +1,91 @@
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// workspaceMarkerFiles are files whose mere presence marks a monorepo root.
+var workspaceMarkerFiles = []string{
+	"go.work",
+	"pnpm-workspace.yaml",
+	"lerna.json",
+	"nx.json",
+	"turbo.json",
+	"rush.json",
+}
+
+// ResolveWorkspaceSlug returns the workspace identity for cwd: the project id
+// the enclosing monorepo root would have if it were opened as a project. The
+// algorithm mirrors WorkspaceIDWithName in plugin/engram/hooks/lib.js.
+//
+// The workspace root is the nearest ancestor of cwd inside the same git repo
+// that carries a monorepo marker (go.work, pnpm-workspace.yaml, lerna.json,
+// nx.json, turbo.json, rush.json, a package.json with "workspaces", or a
+// Cargo.toml with a [workspace] table); without a marker it is the repository
+// root. ok is false when cwd is not inside a git repo with a remote, or when
+// cwd is itself the workspace root — a project is never its own workspace.
+//
+// Unlike ResolveProjectSlug, this never creates or reads .engram-project
+// anchor files.
+func ResolveWorkspaceSlug(cwd string) (id string, displayName string, ok bool) {
+	resolved, err := filepath.Abs(cwd)
+	if err != nil {
+		return "", "", false
+	}
+
+	remoteURL, relativePath, err := getGitInfo(resolved)
+	if err != nil || remoteURL == "" || relativePath == "" {
+		return "", "", false
+	}
+
+	segments := strings.Split(strings.Trim(filepath.ToSlash(relativePath), "/"), "/")
+	rootDir, rootPrefix := resolved, ""
+	dir := resolved
+	for i := len(segments) - 1; i >= 0; i-- {
+		dir = filepath.Dir(dir)
+		rootDir = dir
+		rootPrefix = ""
+		if i > 0 {
+			rootPrefix = strings.Join(segments[:i], "/") + "/"
+		}
+		if hasWorkspaceMarker(dir) {
+			break
+		}
+	}
+
+	hash := sha256Hex(remoteURL + "/" + rootPrefix)
+	return hash[:8], filepath.Base(rootDir), true
+}
+
+// hasWorkspaceMarker reports whether dir looks like a monorepo root.
+func hasWorkspaceMarker(dir string) bool {
+	for _, name := range workspaceMarkerFiles {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		var pkg struct {
+			Workspaces json.RawMessage `json:"workspaces"`
+		}
+		if json.Unmarshal(data, &pkg) == nil && len(pkg.Workspaces) > 0 && string(pkg.Workspaces) != "null" {
+			return true
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "Cargo.toml")); err == nil {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			if strings.TrimSpace(scanner.Text()) == "[workspace]" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package proxy_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thebtf/engram/internal/proxy"
+)
+
+const workspaceFixtureRemote = "https://example.invalid/test/engram-identity-fixture.git"
+
+func workspaceTestID(prefix string) string {
+	sum := sha256.Sum256([]byte(workspaceFixtureRemote + "/" + prefix))
+	return fmt.Sprintf("%x", sum)[:8]
+}
+
+func mkdirAll(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", dir, err)
+	}
+}
+
+// TestResolveWorkspaceSlug_RepoRootFallback verifies that a subdirectory of a
+// repo without monorepo markers belongs to the workspace of the repo root, and
+// that the workspace id equals the project id of that root.
+func TestResolveWorkspaceSlug_RepoRootFallback(t *testing.T) {
+	t.Parallel()
+
+	repoDir := initSyntheticGitRepo(t)
+	pkgDir := filepath.Join(repoDir, "packages", "api")
+	mkdirAll(t, pkgDir)
+
+	id, name, ok := proxy.ResolveWorkspaceSlug(pkgDir)
+	if !ok {
+		t.Fatal("expected a workspace for a repo subdirectory")
+	}
+	if want := workspaceTestID(""); id != want {
+		t.Errorf("id %q, want %q", id, want)
+	}
+	if name != filepath.Base(repoDir) {
+		t.Errorf("displayName %q, want %q", name, filepath.Base(repoDir))
+	}
+
+	rootID, _, _, err := proxy.ResolveProjectSlug(repoDir)
+	if err != nil {
+		t.Fatalf("ResolveProjectSlug: %v", err)
+	}
+	if rootID != id {
+		t.Errorf("workspace id %q differs from root project id %q", id, rootID)
+	}
+}
+
+// TestResolveWorkspaceSlug_NearestMarker verifies that the nearest ancestor
+// carrying a monorepo marker wins over the repository root.
+func TestResolveWorkspaceSlug_NearestMarker(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"go.work", "go.work", "go 1.22\n"},
+		{"pnpm", "pnpm-workspace.yaml", "packages:\n  - 'svc/*'\n"},
+		{"package.json workspaces", "package.json", `{"name":"apps","workspaces":["svc/*"]}`},
+		{"cargo workspace", "Cargo.toml", "[workspace]\nmembers = [\"svc/*\"]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repoDir := initSyntheticGitRepo(t)
+			appsDir := filepath.Join(repoDir, "apps")
+			svcDir := filepath.Join(appsDir, "svc", "billing")
+			mkdirAll(t, svcDir)
+			if err := os.WriteFile(filepath.Join(appsDir, tt.file), []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("write marker: %v", err)
+			}
+
+			id, name, ok := proxy.ResolveWorkspaceSlug(svcDir)
+			if !ok {
+				t.Fatal("expected a workspace")
+			}
+			if want := workspaceTestID("apps/"); id != want {
+				t.Errorf("id %q, want %q", id, want)
+			}
+			if name != "apps" {
+				t.Errorf("displayName %q, want %q", name, "apps")
+			}
+		})
+	}
+}
+
+// TestResolveWorkspaceSlug_NonMarkers verifies that a plain package.json or
+// a Cargo.toml without [workspace] does not mark a workspace root.
+func TestResolveWorkspaceSlug_NonMarkers(t *testing.T) {
+	t.Parallel()
+
+	repoDir := initSyntheticGitRepo(t)
+	appsDir := filepath.Join(repoDir, "apps")
+	svcDir := filepath.Join(appsDir, "svc")
+	mkdirAll(t, svcDir)
+	if err := os.WriteFile(filepath.Join(appsDir, "package.json"), []byte(`{"name":"apps"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(appsDir, "Cargo.toml"), []byte("[package]\nname = \"apps\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	id, _, ok := proxy.ResolveWorkspaceSlug(svcDir)
+	if !ok {
+		t.Fatal("expected a workspace")
+	}
+	if want := workspaceTestID(""); id != want {
+		t.Errorf("id %q, want repo-root workspace %q", id, want)
+	}
+}
+
+// TestResolveWorkspaceSlug_None verifies that the repository root and
+// non-git directories have no workspace.
+func TestResolveWorkspaceSlug_None(t *testing.T) {
+	t.Parallel()
+
+	repoDir := initSyntheticGitRepo(t)
+	if id, _, ok := proxy.ResolveWorkspaceSlug(repoDir); ok {
+		t.Errorf("repo root resolved to workspace %q, want none", id)
+	}
+
+	plainDir := filepath.Join(t.TempDir(), "sub")
+	mkdirAll(t, plainDir)
+	if id, _, ok := proxy.ResolveWorkspaceSlug(plainDir); ok {
+		t.Errorf("non-git dir resolved to workspace %q, want none", id)
+	}
+}
@@ -0,0 +1,20 @@
+package tenant
+
+import "context"
+
+type ownerKey struct{}
+
+// WithOwner returns a new context acting as owner, the principal within the
+// tenant ("keycard:<id>", "operator", "user:<id>"). Memories written with it
+// are owned by it, and private memories are returned only to their owner.
+func WithOwner(ctx context.Context, owner string) context.Context {
+	return context.WithValue(ctx, ownerKey{}, owner)
+}
+
+// OwnerFromContext returns the principal stamped on ctx. ok is false when
+// there is none (background jobs, authentication disabled): such callers are
+// not restricted by memory visibility.
+func OwnerFromContext(ctx context.Context) (owner string, ok bool) {
+	owner, ok = ctx.Value(ownerKey{}).(string)
+	return owner, ok
+}
@@ -0,0 +1,47 @@
+// Package tenant carries the tenant identity that scopes user data (memories,
+// behavioral rules, credentials) when one worker serves several users.
+//
+// The tenant is resolved from the authenticated keycard (api_tokens.tenant) and
+// stamped on the request context by auth.WithIdentity, so both transports (HTTP
+// middleware, gRPC interceptor) scope every downstream store call the same way.
+// It lives in its own leaf package because internal/db/gorm reads it and
+// internal/auth already imports internal/db/gorm.
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// Default is the tenant of operator-key bearers, browser sessions, background
+// jobs and every keycard issued without an explicit tenant. Data written before
+// multi-tenant support belongs to it, so single-user deployments are unaffected.
+const Default = ""
+
+type contextKey struct{}
+
+// validName bounds tenant names to a URL- and log-safe alphabet.
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,62}$`)
+
+// WithTenant returns a new context scoped to tenant t.
+func WithTenant(ctx context.Context, t string) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the tenant stamped on ctx, or Default when none is set.
+func FromContext(ctx context.Context) string {
+	if t, ok := ctx.Value(contextKey{}).(string); ok {
+		return t
+	}
+	return Default
+}
+
+// Validate reports whether t is an acceptable tenant name. The empty string
+// (Default) is always valid.
+func Validate(t string) error {
+	if t == Default || validName.MatchString(t) {
+		return nil
+	}
+	return fmt.Errorf("tenant %q: must be 1-63 chars of lowercase letters, digits, '_', '.', '-' and start with a letter or digit", t)
+}
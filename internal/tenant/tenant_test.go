@@ -0,0 +1,30 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, Default, FromContext(context.Background()))
+	assert.Equal(t, "team-a", FromContext(WithTenant(context.Background(), "team-a")))
+}
+
+func TestOwnerFromContext(t *testing.T) {
+	_, ok := OwnerFromContext(context.Background())
+	assert.False(t, ok)
+	owner, ok := OwnerFromContext(WithOwner(context.Background(), "keycard:uuid-1"))
+	assert.True(t, ok)
+	assert.Equal(t, "keycard:uuid-1", owner)
+}
+
+func TestValidate(t *testing.T) {
+	for _, name := range []string{"", "alice", "team-a", "dev.server_1"} {
+		assert.NoError(t, Validate(name), name)
+	}
+	for _, name := range []string{"Alice", "-team", "has space", "a/b", string(make([]byte, 64))} {
+		assert.Error(t, Validate(name), name)
+	}
+}
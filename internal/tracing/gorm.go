@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// gormSpanKey is the gorm instance key holding the span of a running statement.
+const gormSpanKey = "engram:tracing_span"
+
+// GormPlugin records a client span for every GORM statement: db.create,
+// db.query, db.update, db.delete, db.row and db.raw, carrying the table and
+// the SQL text (with placeholders, never bound values).
+type GormPlugin struct{}
+
+// Name implements gorm.Plugin.
+func (GormPlugin) Name() string { return "engram:tracing" }
+
+// Initialize implements gorm.Plugin.
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	hooks := []struct {
+		name   string
+		before func(string, func(*gorm.DB)) error
+		after  func(string, func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+	for _, h := range hooks {
+		if err := h.before("tracing:before_"+h.name, startGormSpan("db."+h.name)); err != nil {
+			return err
+		}
+		if err := h.after("tracing:after_"+h.name, endGormSpan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startGormSpan(name string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		if tx.Statement == nil || tx.Statement.Context == nil {
+			return
+		}
+		// Only trace statements that belong to a trace; background work
+		// without a parent span would otherwise produce a flood of roots.
+		if !trace.SpanFromContext(tx.Statement.Context).SpanContext().IsValid() {
+			return
+		}
+		ctx, span := Tracer().Start(tx.Statement.Context, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("db.system", "postgresql")),
+		)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func endGormSpan(tx *gorm.DB) {
+	v, ok := tx.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	if tx.Statement.Table != "" {
+		span.SetAttributes(attribute.String("db.collection.name", tx.Statement.Table))
+	}
+	if sql := tx.Statement.SQL.String(); sql != "" {
+		span.SetAttributes(attribute.String("db.query.text", sql))
+	}
+	span.SetAttributes(attribute.Int64("db.rows_affected", tx.RowsAffected))
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(tx.Error)
+		span.SetStatus(codes.Error, tx.Error.Error())
+	}
+}
@@ -0,0 +1,92 @@
+// Package tracing wires OpenTelemetry distributed tracing through the engram
+// server: hook HTTP calls, MCP tool calls over gRPC, retrieval and the
+// database layer.
+//
+// Export is configured entirely through the standard OTel environment
+// variables. Tracing stays a no-op unless OTEL_EXPORTER_OTLP_ENDPOINT or
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is set; spans are then exported over
+// OTLP/HTTP. OTEL_TRACES_EXPORTER=none or OTEL_SDK_DISABLED=true turn export
+// off again, and OTEL_SERVICE_NAME, OTEL_RESOURCE_ATTRIBUTES,
+// OTEL_TRACES_SAMPLER and the other OTLP exporter variables are honoured by the
+// SDK as usual.
+//
+// W3C trace context is always propagated, so a traceparent header sent by a
+// hook ties the worker's spans to the caller's trace even when the caller
+// exports nothing itself.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// scopeName is the instrumentation scope of every engram span.
+const scopeName = "github.com/thebtf/engram"
+
+// Tracer returns the engram tracer from the global tracer provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(scopeName)
+}
+
+// Start starts a span named name as a child of any span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Enabled reports whether the environment asks for trace export.
+func Enabled() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+	if strings.EqualFold(os.Getenv("OTEL_TRACES_EXPORTER"), "none") {
+		return false
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Init installs the W3C trace context propagator and, when Enabled, an SDK
+// tracer provider that batches spans to an OTLP/HTTP exporter. The returned
+// shutdown function flushes pending spans; it is a no-op when export is off.
+func Init(ctx context.Context, serviceName, version string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create otlp trace exporter: %w", err)
+	}
+
+	// WithFromEnv comes after the defaults so OTEL_SERVICE_NAME and
+	// OTEL_RESOURCE_ATTRIBUTES override them.
+	res, err := resource.New(ctx,
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			attribute.String("service.name", serviceName),
+			attribute.String("service.version", version),
+		),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
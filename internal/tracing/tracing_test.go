@@ -0,0 +1,110 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	testTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+)
+
+// useRecorder installs an in-memory tracer provider for the test.
+func useRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return recorder
+}
+
+func TestMiddleware_ContinuesTraceAndNamesRoute(t *testing.T) {
+	recorder := useRecorder(t)
+
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Get("/api/memories/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/memories/42", nil)
+	req.Header.Set("traceparent", testTraceParent)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "GET /api/memories/{id}", span.Name())
+	assert.Equal(t, testTraceID, span.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	assert.Equal(t, "Error", span.Status().Code.String())
+}
+
+func TestUnaryServerInterceptor_ExtractsMetadata(t *testing.T) {
+	recorder := useRecorder(t)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", testTraceParent))
+	info := &grpc.UnaryServerInfo{FullMethod: "/engram.v1.EngramService/CallTool"}
+	_, err := UnaryServerInterceptor(ctx, nil, info, func(ctx context.Context, _ any) (any, error) {
+		// Work inside the handler hangs off the RPC span.
+		_, child := Start(ctx, "child")
+		child.End()
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, info.FullMethod, spans[1].Name())
+	assert.Equal(t, testTraceID, spans[1].SpanContext().TraceID().String())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
+
+func TestEnabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_TRACES_EXPORTER", "")
+	t.Setenv("OTEL_SDK_DISABLED", "")
+	assert.False(t, Enabled())
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+	assert.True(t, Enabled())
+
+	t.Setenv("OTEL_TRACES_EXPORTER", "none")
+	assert.False(t, Enabled())
+
+	t.Setenv("OTEL_TRACES_EXPORTER", "otlp")
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+	assert.False(t, Enabled())
+}
+
+func TestInit_DisabledIsNoop(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	prev := otel.GetTextMapPropagator()
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+
+	shutdown, err := Init(context.Background(), "engram-server", "test")
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+	assert.Contains(t, otel.GetTextMapPropagator().Fields(), "traceparent")
+}
@@ -14,10 +14,12 @@ import (
 )
 
 // Watcher monitors a file or directory for deletion and calls onDelete when removed.
+// With OnChange it also reports writes to (and recreation of) the target.
 // It watches the parent directory since fsnotify cannot watch non-existent files.
 type Watcher struct {
 	ctx        context.Context
 	onDelete   func()
+	onChange   func()
 	watcher    *fsnotify.Watcher
 	cancel     context.CancelFunc
 	targetPath string
@@ -48,6 +50,13 @@ func New(targetPath string, onDelete func()) (*Watcher, error) {
 	}, nil
 }
 
+// OnChange sets a callback for writes to the target and for its recreation
+// (editors that save by replacing the file). Bursts of events are debounced
+// into one call. Must be called before Start.
+func (w *Watcher) OnChange(fn func()) {
+	w.onChange = fn
+}
+
 // Start begins watching for file deletion events.
 func (w *Watcher) Start() error {
 	w.mu.Lock()
@@ -95,8 +104,18 @@ func (w *Watcher) addWatch() error {
 func (w *Watcher) watchLoop() {
 	var (
 		debounceTimer *time.Timer
+		changeTimer   *time.Timer
 		pendingDelete bool
 	)
+	scheduleChange := func() {
+		if w.onChange == nil {
+			return
+		}
+		if changeTimer != nil {
+			changeTimer.Stop()
+		}
+		changeTimer = time.AfterFunc(w.debounce, w.onChange)
+	}
 
 	for {
 		select {
@@ -104,6 +123,9 @@ func (w *Watcher) watchLoop() {
 			if debounceTimer != nil {
 				debounceTimer.Stop()
 			}
+			if changeTimer != nil {
+				changeTimer.Stop()
+			}
 			return
 
 		case event, ok := <-w.watcher.Events:
@@ -157,6 +179,11 @@ func (w *Watcher) watchLoop() {
 				}
 			}
 
+			// Content changed: written in place or replaced by a new file
+			if eventPath == targetPath && event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+				scheduleChange()
+			}
+
 		case err, ok := <-w.watcher.Errors:
 			if !ok {
 				return
@@ -0,0 +1,45 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcherOnChange(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "settings.json")
+	if err := os.WriteFile(target, []byte(`{}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	changed := make(chan struct{}, 10)
+	w, err := New(target, func() {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.OnChange(func() { changed <- struct{}{} })
+	if err := w.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = w.Stop() }()
+
+	// Several writes in a burst are debounced into one callback.
+	for i := 0; i < 3; i++ {
+		if err := os.WriteFile(target, []byte(`{"ENGRAM_MODEL":"sonnet"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnChange callback not called after write")
+	}
+	select {
+	case <-changed:
+		t.Fatal("burst of writes produced more than one callback")
+	case <-time.After(300 * time.Millisecond):
+	}
+}
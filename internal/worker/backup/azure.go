@@ -0,0 +1,173 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// azureTarget stores backups in an Azure Blob container addressed by a
+// container SAS URL (needs read, write, delete and list permissions).
+type azureTarget struct {
+	client *http.Client
+	base   *url.URL // container URL without the SAS query
+	sas    url.Values
+	prefix string
+}
+
+func newAzureTarget(u *url.URL) (*azureTarget, error) {
+	parts := strings.SplitN(strings.Trim(u.Path, "/"), "/", 2)
+	if parts[0] == "" {
+		return nil, fmt.Errorf("backup target %q has no container", redactURL(u))
+	}
+	base := *u
+	base.RawQuery = ""
+	base.Path = "/" + parts[0]
+	t := &azureTarget{
+		client: &http.Client{Timeout: 10 * time.Minute},
+		base:   &base,
+		sas:    u.Query(),
+	}
+	if len(parts) == 2 {
+		t.prefix = strings.Trim(parts[1], "/")
+	}
+	return t, nil
+}
+
+func (t *azureTarget) String() string {
+	return redactURL(t.base) + "/" + t.prefix
+}
+
+// url returns the URL of blob (the container when blob is empty) with the
+// SAS and extra query parameters.
+func (t *azureTarget) url(blob string, extra url.Values) string {
+	u := *t.base
+	if blob != "" {
+		u.Path += "/" + blob
+	}
+	query := url.Values{}
+	for k, v := range t.sas {
+		query[k] = v
+	}
+	for k, v := range extra {
+		query[k] = v
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func (t *azureTarget) do(ctx context.Context, method, rawURL string, body []byte, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("x-ms-version", "2021-08-06")
+	resp, err := t.client.Do(req)
+	if err != nil {
+		// url.Error repeats the URL, SAS signature included; keep only the cause.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return nil, fmt.Errorf("%s %s: %w", method, t.String(), err)
+	}
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return nil, fmt.Errorf("%s %s: %s: %s", method, t.String(), resp.Status, strings.TrimSpace(string(msg)))
+}
+
+func (t *azureTarget) Put(ctx context.Context, name string, data []byte) error {
+	header := http.Header{"X-Ms-Blob-Type": {"BlockBlob"}}
+	resp, err := t.do(ctx, http.MethodPut, t.url(objectKey(t.prefix, name), nil), data, header)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (t *azureTarget) Get(ctx context.Context, name string) ([]byte, error) {
+	resp, err := t.do(ctx, http.MethodGet, t.url(objectKey(t.prefix, name), nil), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+func (t *azureTarget) Delete(ctx context.Context, name string) error {
+	resp, err := t.do(ctx, http.MethodDelete, t.url(objectKey(t.prefix, name), nil), nil, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// enumerationResults is the part of a List Blobs response List reads.
+type enumerationResults struct {
+	Blobs []struct {
+		Name       string `xml:"Name"`
+		Properties struct {
+			LastModified  string `xml:"Last-Modified"`
+			ContentLength int64  `xml:"Content-Length"`
+		} `xml:"Properties"`
+	} `xml:"Blobs>Blob"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+func (t *azureTarget) List(ctx context.Context, prefix string) ([]Object, error) {
+	var objects []Object
+	marker := ""
+	for {
+		query := url.Values{"restype": {"container"}, "comp": {"list"}, "prefix": {objectKey(t.prefix, prefix)}}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		resp, err := t.do(ctx, http.MethodGet, t.url("", query), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page enumerationResults
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decode container listing: %w", err)
+		}
+		for _, b := range page.Blobs {
+			name := strings.TrimPrefix(b.Name, objectKey(t.prefix, ""))
+			if name == "" || strings.Contains(name, "/") {
+				continue
+			}
+			modified, _ := time.Parse(time.RFC1123, b.Properties.LastModified)
+			objects = append(objects, Object{Name: name, Size: b.Properties.ContentLength, Modified: modified.UTC()})
+		}
+		if page.NextMarker == "" {
+			break
+		}
+		marker = page.NextMarker
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+	return objects, nil
+}
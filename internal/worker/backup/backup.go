@@ -0,0 +1,322 @@
+// Package backup provides a periodic job that snapshots the database to a
+// remote target, so losing the machine running the server does not lose the
+// memories it holds.
+//
+// A backup is a gzipped JSON-lines snapshot of the tables in Tables, read in
+// one transaction. With ENGRAM_BACKUP_PASSPHRASE set it is encrypted with
+// AES-256-GCM under a scrypt-derived key; the passphrase alone restores it.
+// Credentials stay encrypted with the vault key inside the snapshot, so back
+// up vault.key (or ENGRAM_ENCRYPTION_KEY) separately.
+//
+// Targets take and return whole files, and the encryption seals the file in
+// one piece, so a backup is held in memory while it is uploaded or restored:
+// the compressed snapshot plus, when encrypted, its sealed copy. Expect to
+// need about twice the compressed backup size in free RAM. Compressed
+// snapshots run to a small fraction of the database size; for databases
+// whose snapshot does not fit, use pg_dump instead.
+//
+// Configuration:
+//   - ENGRAM_BACKUP_TARGET: where backups go; see ParseTarget (empty = off)
+//   - ENGRAM_BACKUP_ACCESS_KEY_ID, ENGRAM_BACKUP_SECRET_ACCESS_KEY: keys of an
+//     s3:// or gs:// target (fall back to AWS_ACCESS_KEY_ID and
+//     AWS_SECRET_ACCESS_KEY)
+//   - ENGRAM_BACKUP_REGION, ENGRAM_BACKUP_ENDPOINT: S3 region and a custom
+//     endpoint for S3-compatible stores
+//   - ENGRAM_BACKUP_INTERVAL_HOURS: schedule (default 24, 0 = on demand only)
+//   - ENGRAM_BACKUP_RETENTION: backups kept on the target (default 7, 0 = all)
+//   - ENGRAM_BACKUP_PASSPHRASE: encrypts backups (recommended)
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultIntervalHours is how often a scheduled backup runs.
+	defaultIntervalHours = 24
+
+	// defaultRetention is how many backups are kept on the target.
+	defaultRetention = 7
+
+	// namePrefix starts every backup name; retention only touches these.
+	namePrefix = "engram-backup-"
+
+	// nameTimeFormat sorts lexically in time order.
+	nameTimeFormat = "20060102T150405Z"
+)
+
+// ErrDisabled is returned when no backup target is configured.
+var ErrDisabled = errors.New("backups are not configured (set ENGRAM_BACKUP_TARGET)")
+
+// Result summarizes one backup run.
+type Result struct {
+	Tables    map[string]*TableStats `json:"tables"`
+	Name      string                 `json:"name"`
+	Target    string                 `json:"target"`
+	Deleted   []string               `json:"deleted,omitempty"`
+	Size      int64                  `json:"size"`
+	Encrypted bool                   `json:"encrypted"`
+}
+
+// RestoreResult summarizes one restore.
+type RestoreResult struct {
+	Tables map[string]*TableStats `json:"tables"`
+	Name   string                 `json:"name"`
+	Target string                 `json:"target"`
+}
+
+// Backuper periodically backs up the database to a Target.
+type Backuper struct {
+	db         *gorm.DB
+	target     Target // nil when backups are not configured
+	passphrase string
+	interval   time.Duration
+	retention  int
+	mu         sync.Mutex // serializes backups and restores
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// New creates a Backuper configured from the environment. A missing target
+// yields a disabled Backuper; an invalid one is an error.
+func New(db *gorm.DB) (*Backuper, error) {
+	b := &Backuper{
+		db:         db,
+		passphrase: os.Getenv("ENGRAM_BACKUP_PASSPHRASE"),
+		interval:   time.Duration(envInt("ENGRAM_BACKUP_INTERVAL_HOURS", defaultIntervalHours)) * time.Hour,
+		retention:  envInt("ENGRAM_BACKUP_RETENTION", defaultRetention),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	raw := strings.TrimSpace(os.Getenv("ENGRAM_BACKUP_TARGET"))
+	if raw == "" {
+		return b, nil
+	}
+	target, err := ParseTarget(raw, Credentials{
+		AccessKeyID:     firstEnv("ENGRAM_BACKUP_ACCESS_KEY_ID", "AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: firstEnv("ENGRAM_BACKUP_SECRET_ACCESS_KEY", "AWS_SECRET_ACCESS_KEY"),
+		Region:          firstEnv("ENGRAM_BACKUP_REGION", "AWS_REGION"),
+		Endpoint:        os.Getenv("ENGRAM_BACKUP_ENDPOINT"),
+	})
+	if err != nil {
+		return b, err
+	}
+	b.target = target
+	return b, nil
+}
+
+// Enabled reports whether a target is configured.
+func (b *Backuper) Enabled() bool {
+	return b.target != nil
+}
+
+// Target describes the configured target without credentials ("" when off).
+func (b *Backuper) Target() string {
+	if b.target == nil {
+		return ""
+	}
+	return b.target.String()
+}
+
+// Start launches the backup loop in a background goroutine. It respects ctx
+// for graceful shutdown and also responds to Stop(). Returns immediately.
+// Without a target or with a zero interval only on-demand backups run.
+func (b *Backuper) Start(ctx context.Context) {
+	if b.target == nil || b.interval <= 0 {
+		close(b.done)
+		if b.target != nil {
+			log.Info().Str("target", b.target.String()).Msg("scheduled backups disabled; on-demand backups only")
+		}
+		return
+	}
+	if b.passphrase == "" {
+		log.Warn().Msg("ENGRAM_BACKUP_PASSPHRASE is not set: backups are stored unencrypted")
+	}
+	log.Info().
+		Str("target", b.target.String()).
+		Dur("interval", b.interval).
+		Int("retention", b.retention).
+		Msg("scheduled backups started")
+
+	go func() {
+		defer close(b.done)
+
+		ticker := time.NewTicker(b.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-b.stop:
+				return
+			case <-ticker.C:
+				res, err := b.RunOnce(ctx)
+				if err != nil {
+					log.Error().Err(err).Msg("backup: run failed")
+					continue
+				}
+				log.Info().
+					Str("name", res.Name).
+					Int64("bytes", res.Size).
+					Int("deleted", len(res.Deleted)).
+					Msg("backup: uploaded")
+			}
+		}
+	}()
+}
+
+// Stop signals the loop to cease and waits for the goroutine to exit.
+func (b *Backuper) Stop() {
+	select {
+	case <-b.stop:
+		// Already closed — idempotent.
+	default:
+		close(b.stop)
+	}
+	<-b.done
+}
+
+// RunOnce takes a backup, uploads it and deletes the oldest backups past the
+// retention count. Concurrent calls are serialized. The backup is built in
+// memory; see the package doc for the size limit.
+func (b *Backuper) RunOnce(ctx context.Context) (Result, error) {
+	if b.target == nil {
+		return Result{}, ErrDisabled
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var buf bytes.Buffer
+	tables, err := dump(ctx, b.db, &buf)
+	if err != nil {
+		return Result{}, fmt.Errorf("snapshot: %w", err)
+	}
+	data := buf.Bytes()
+	res := Result{
+		Tables: tables,
+		Name:   namePrefix + time.Now().UTC().Format(nameTimeFormat) + ".jsonl.gz",
+		Target: b.target.String(),
+	}
+	if b.passphrase != "" {
+		if data, err = seal(b.passphrase, data); err != nil {
+			return Result{}, fmt.Errorf("encrypt backup: %w", err)
+		}
+		res.Name += ".enc"
+		res.Encrypted = true
+	}
+	res.Size = int64(len(data))
+	if err := b.target.Put(ctx, res.Name, data); err != nil {
+		return Result{}, fmt.Errorf("upload backup: %w", err)
+	}
+
+	deleted, err := b.prune(ctx)
+	res.Deleted = deleted
+	if err != nil {
+		// The backup itself is stored; report the failed cleanup in the log.
+		log.Warn().Err(err).Msg("backup: retention cleanup failed")
+	}
+	return res, nil
+}
+
+// prune deletes the oldest backups beyond the retention count.
+func (b *Backuper) prune(ctx context.Context) ([]string, error) {
+	if b.retention <= 0 {
+		return nil, nil
+	}
+	objects, err := b.target.List(ctx, namePrefix)
+	if err != nil {
+		return nil, err
+	}
+	var deleted []string
+	// List is sorted by name, which sorts by time: the oldest come first.
+	for i := 0; i < len(objects)-b.retention; i++ {
+		if err := b.target.Delete(ctx, objects[i].Name); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, objects[i].Name)
+	}
+	return deleted, nil
+}
+
+// List returns the backups on the target, oldest first.
+func (b *Backuper) List(ctx context.Context) ([]Object, error) {
+	if b.target == nil {
+		return nil, ErrDisabled
+	}
+	return b.target.List(ctx, namePrefix)
+}
+
+// Restore downloads the named backup ("latest" for the newest) and loads it
+// into the database. Existing rows are kept; see restore. The download is
+// held in memory; see the package doc for the size limit.
+func (b *Backuper) Restore(ctx context.Context, name string) (RestoreResult, error) {
+	if b.target == nil {
+		return RestoreResult{}, ErrDisabled
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if name == "" || name == "latest" {
+		objects, err := b.target.List(ctx, namePrefix)
+		if err != nil {
+			return RestoreResult{}, fmt.Errorf("list backups: %w", err)
+		}
+		if len(objects) == 0 {
+			return RestoreResult{}, ErrNotFound
+		}
+		name = objects[len(objects)-1].Name
+	}
+	if !strings.HasPrefix(name, namePrefix) || strings.ContainsAny(name, `/\`) {
+		return RestoreResult{}, fmt.Errorf("invalid backup name %q", name)
+	}
+
+	data, err := b.target.Get(ctx, name)
+	if err != nil {
+		return RestoreResult{}, fmt.Errorf("download %s: %w", name, err)
+	}
+	if isEncrypted(data) {
+		if b.passphrase == "" {
+			return RestoreResult{}, fmt.Errorf("backup %s is encrypted: set ENGRAM_BACKUP_PASSPHRASE", name)
+		}
+		if data, err = open(b.passphrase, data); err != nil {
+			return RestoreResult{}, err
+		}
+	}
+	tables, err := restore(ctx, b.db, bytes.NewReader(data))
+	if err != nil {
+		return RestoreResult{}, fmt.Errorf("restore %s: %w", name, err)
+	}
+	return RestoreResult{Tables: tables, Name: name, Target: b.target.String()}, nil
+}
+
+// envInt reads a non-negative integer from the environment, or def.
+func envInt(name string, def int) int {
+	if v := os.Getenv(name); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return def
+}
+
+// firstEnv returns the first non-empty environment variable of names.
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
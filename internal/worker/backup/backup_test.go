@@ -0,0 +1,224 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealOpen(t *testing.T) {
+	plaintext := []byte("snapshot bytes")
+	sealed, err := seal("correct horse", plaintext)
+	require.NoError(t, err)
+	assert.True(t, isEncrypted(sealed))
+	assert.NotContains(t, string(sealed), "snapshot")
+
+	opened, err := open("correct horse", sealed)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, opened)
+
+	_, err = open("wrong", sealed)
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+	sealed[len(sealed)-1] ^= 0xff
+	_, err = open("correct horse", sealed)
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+}
+
+func TestParseTarget(t *testing.T) {
+	keys := Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}
+
+	target, err := ParseTarget("file:///var/backups/engram", Credentials{})
+	require.NoError(t, err)
+	assert.Equal(t, "file:///var/backups/engram", target.String())
+
+	target, err = ParseTarget("s3://bucket/engram/laptop", keys)
+	require.NoError(t, err)
+	assert.Equal(t, "s3://bucket/engram/laptop", target.String())
+	assert.Equal(t, "https://s3.us-east-1.amazonaws.com", target.(*s3Target).endpoint)
+
+	target, err = ParseTarget("gs://bucket", keys)
+	require.NoError(t, err)
+	assert.Equal(t, "https://storage.googleapis.com", target.(*s3Target).endpoint)
+
+	target, err = ParseTarget("https://acct.blob.core.windows.net/backups/engram?sv=2021&sig=abc", Credentials{})
+	require.NoError(t, err)
+	assert.NotContains(t, target.String(), "sig=", "the SAS signature is never shown")
+
+	for _, bad := range []string{
+		"s3://bucket",                          // no keys
+		"ftp://host/path",                      // unknown scheme
+		"https://acct.blob.core.windows.net/c", // no SAS
+		"file://",                              // no path
+	} {
+		_, err := ParseTarget(bad, Credentials{})
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestFileTargetAndRetention(t *testing.T) {
+	ctx := context.Background()
+	target := &fileTarget{dir: t.TempDir()}
+	b := &Backuper{target: target, retention: 2}
+
+	for i := 1; i <= 4; i++ {
+		name := fmt.Sprintf("%s2026010%dT000000Z.jsonl.gz", namePrefix, i)
+		require.NoError(t, target.Put(ctx, name, []byte{byte(i)}))
+	}
+	require.NoError(t, target.Put(ctx, "unrelated.txt", []byte("x")))
+
+	deleted, err := b.prune(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{namePrefix + "20260101T000000Z.jsonl.gz", namePrefix + "20260102T000000Z.jsonl.gz"}, deleted)
+
+	objects, err := b.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, namePrefix+"20260104T000000Z.jsonl.gz", objects[1].Name)
+
+	data, err := target.Get(ctx, objects[1].Name)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{4}, data)
+
+	_, err = target.Get(ctx, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestBackuper_Disabled(t *testing.T) {
+	b := &Backuper{}
+	assert.False(t, b.Enabled())
+	_, err := b.RunOnce(context.Background())
+	assert.ErrorIs(t, err, ErrDisabled)
+	_, err = b.Restore(context.Background(), "latest")
+	assert.ErrorIs(t, err, ErrDisabled)
+}
+
+// fakeBucket is an in-memory S3 endpoint that records the signed requests.
+type fakeBucket struct {
+	objects map[string][]byte
+	auth    []string
+	mu      sync.Mutex
+}
+
+func (f *fakeBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+	key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/bucket":
+		prefix := r.URL.Query().Get("prefix")
+		fmt.Fprint(w, `<ListBucketResult><IsTruncated>false</IsTruncated>`)
+		for k, v := range f.objects {
+			if strings.HasPrefix(k, prefix) {
+				fmt.Fprintf(w, `<Contents><Key>%s</Key><Size>%d</Size><LastModified>2026-01-01T00:00:00Z</LastModified></Contents>`, k, len(v))
+			}
+		}
+		fmt.Fprint(w, `</ListBucketResult>`)
+	case r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[key] = data
+	case r.Method == http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Target(t *testing.T) {
+	bucket := &fakeBucket{objects: map[string][]byte{}}
+	srv := httptest.NewServer(bucket)
+	defer srv.Close()
+
+	target, err := ParseTarget("s3://bucket/laptop", Credentials{
+		AccessKeyID: "AKID", SecretAccessKey: "secret", Region: "eu-west-1", Endpoint: srv.URL,
+	})
+	require.NoError(t, err)
+	s3 := target.(*s3Target)
+	s3.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+
+	ctx := context.Background()
+	name := namePrefix + "20261016T120000Z.jsonl.gz"
+	require.NoError(t, target.Put(ctx, name, []byte("data")))
+	assert.Contains(t, bucket.objects, "laptop/"+name)
+
+	objects, err := target.List(ctx, namePrefix)
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.Equal(t, name, objects[0].Name)
+	assert.EqualValues(t, 4, objects[0].Size)
+
+	data, err := target.Get(ctx, name)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+
+	require.NoError(t, target.Delete(ctx, name))
+	_, err = target.Get(ctx, name)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NotEmpty(t, bucket.auth)
+	assert.True(t, strings.HasPrefix(bucket.auth[0],
+		"AWS4-HMAC-SHA256 Credential=AKID/20261016/eu-west-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="))
+}
+
+func TestAzureTarget(t *testing.T) {
+	var queries []url.Values
+	objects := map[string][]byte{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		key := strings.TrimPrefix(r.URL.Path, "/backups/")
+		switch {
+		case r.URL.Query().Get("comp") == "list":
+			fmt.Fprint(w, `<EnumerationResults><Blobs>`)
+			for k, v := range objects {
+				fmt.Fprintf(w, `<Blob><Name>%s</Name><Properties><Content-Length>%d</Content-Length><Last-Modified>Fri, 16 Oct 2026 12:00:00 GMT</Last-Modified></Properties></Blob>`, k, len(v))
+			}
+			fmt.Fprint(w, `</Blobs><NextMarker/></EnumerationResults>`)
+		case r.Method == http.MethodPut:
+			assert.Equal(t, "BlockBlob", r.Header.Get("x-ms-blob-type"))
+			objects[key], _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodGet:
+			_, _ = w.Write(objects[key])
+		}
+	}))
+	defer srv.Close()
+
+	target, err := ParseTarget(srv.URL+"/backups/engram?sv=2021&sig=abc", Credentials{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	name := namePrefix + "20261016T120000Z.jsonl.gz"
+	require.NoError(t, target.Put(ctx, name, []byte("data")))
+	assert.Contains(t, objects, "engram/"+name)
+
+	list, err := target.List(ctx, namePrefix)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, name, list[0].Name)
+	assert.Equal(t, 2026, list[0].Modified.Year())
+
+	data, err := target.Get(ctx, name)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+
+	for _, q := range queries {
+		assert.Equal(t, "abc", q.Get("sig"), "every request carries the SAS")
+	}
+}
@@ -0,0 +1,98 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Encrypted backups are laid out as magic | salt | nonce | AES-256-GCM
+// ciphertext, with the key derived from the passphrase by scrypt. Only the
+// passphrase is needed to restore, so a backup can be read on a new machine.
+const (
+	saltSize = 16
+
+	// scrypt cost parameters (the 2017 interactive-login recommendation).
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var encryptedMagic = []byte("ENGRAMBK1")
+
+// ErrWrongPassphrase is returned when a backup does not decrypt.
+var ErrWrongPassphrase = errors.New("backup does not decrypt: wrong passphrase or corrupted file")
+
+// isEncrypted reports whether data is an encrypted backup.
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedMagic)
+}
+
+func deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+}
+
+// seal encrypts plaintext with a key derived from passphrase.
+func seal(passphrase string, plaintext []byte) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	out := make([]byte, 0, len(encryptedMagic)+saltSize+len(nonce)+len(plaintext)+gcm.Overhead())
+	out = append(out, encryptedMagic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	// The header is authenticated too, so it cannot be swapped undetected.
+	return gcm.Seal(out, nonce, plaintext, out), nil
+}
+
+// open decrypts data produced by seal.
+func open(passphrase string, data []byte) ([]byte, error) {
+	if !isEncrypted(data) {
+		return nil, fmt.Errorf("not an encrypted backup")
+	}
+	header := len(encryptedMagic) + saltSize
+	if len(data) < header {
+		return nil, ErrWrongPassphrase
+	}
+	gcm, err := newGCM(passphrase, data[len(encryptedMagic):header])
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < header+gcm.NonceSize() {
+		return nil, ErrWrongPassphrase
+	}
+	nonce := data[header : header+gcm.NonceSize()]
+	plaintext, err := gcm.Open(nil, nonce, data[header+gcm.NonceSize():], data[:header+gcm.NonceSize()])
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
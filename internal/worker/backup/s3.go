@@ -0,0 +1,255 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// s3Target talks to an S3-compatible API with AWS Signature Version 4, using
+// path-style addressing so custom endpoints work without DNS wildcards.
+// Google Cloud Storage serves the same API at storage.googleapis.com for
+// HMAC keys.
+type s3Target struct {
+	client   *http.Client
+	now      func() time.Time
+	scheme   string
+	endpoint string
+	bucket   string
+	prefix   string
+	creds    Credentials
+}
+
+func newS3Target(scheme, bucket, prefix string, creds Credentials) (*s3Target, error) {
+	if creds.Region == "" {
+		creds.Region = "us-east-1"
+		if scheme == "gs" {
+			creds.Region = "auto"
+		}
+	}
+	endpoint := creds.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + creds.Region + ".amazonaws.com"
+		if scheme == "gs" {
+			endpoint = "https://storage.googleapis.com"
+		}
+	}
+	if _, err := url.Parse(endpoint); err != nil {
+		return nil, fmt.Errorf("parse backup endpoint: %w", err)
+	}
+	return &s3Target{
+		client:   &http.Client{Timeout: 10 * time.Minute},
+		now:      time.Now,
+		scheme:   scheme,
+		endpoint: strings.TrimRight(endpoint, "/"),
+		bucket:   bucket,
+		prefix:   prefix,
+		creds:    creds,
+	}, nil
+}
+
+func (t *s3Target) String() string {
+	return t.scheme + "://" + objectKey(t.bucket, t.prefix)
+}
+
+func (t *s3Target) Put(ctx context.Context, name string, data []byte) error {
+	resp, err := t.do(ctx, http.MethodPut, objectKey(t.prefix, name), nil, data)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (t *s3Target) Get(ctx context.Context, name string) ([]byte, error) {
+	resp, err := t.do(ctx, http.MethodGet, objectKey(t.prefix, name), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+func (t *s3Target) Delete(ctx context.Context, name string) error {
+	resp, err := t.do(ctx, http.MethodDelete, objectKey(t.prefix, name), nil, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// listBucketResult is the part of a ListObjectsV2 response List reads.
+type listBucketResult struct {
+	Contents []struct {
+		LastModified time.Time `xml:"LastModified"`
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+	} `xml:"Contents"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+}
+
+func (t *s3Target) List(ctx context.Context, prefix string) ([]Object, error) {
+	keyPrefix := objectKey(t.prefix, prefix)
+	var objects []Object
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {keyPrefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		resp, err := t.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decode bucket listing: %w", err)
+		}
+		for _, c := range page.Contents {
+			name := strings.TrimPrefix(c.Key, objectKey(t.prefix, ""))
+			if name == "" || strings.Contains(name, "/") {
+				continue
+			}
+			objects = append(objects, Object{Name: name, Size: c.Size, Modified: c.LastModified})
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			break
+		}
+		token = page.NextContinuationToken
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+	return objects, nil
+}
+
+// do sends a signed request for key (the bucket itself when key is empty) and
+// returns the response when its status is 2xx.
+func (t *s3Target) do(ctx context.Context, method, key string, query url.Values, body []byte) (*http.Response, error) {
+	path := "/" + t.bucket
+	if key != "" {
+		path += "/" + key
+	}
+	rawURL := t.endpoint + uriEncode(path, false)
+	if len(query) > 0 {
+		rawURL += "?" + canonicalQuery(query)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	t.sign(req, body)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, t.String(), err)
+	}
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound && key != "" {
+		return nil, ErrNotFound
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return nil, fmt.Errorf("%s %s: %s: %s", method, t.String(), resp.Status, strings.TrimSpace(string(msg)))
+}
+
+// sign adds the AWS Signature Version 4 headers to req.
+func (t *s3Target) sign(req *http.Request, body []byte) {
+	now := t.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signed := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	headers := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		headers,
+		strings.Join(signed, ";"),
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + t.creds.Region + "/s3/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+
+	key := hmacSHA256([]byte("AWS4"+t.creds.SecretAccessKey), day)
+	key = hmacSHA256(key, t.creds.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		t.creds.AccessKeyID, scope, strings.Join(signed, ";"), signature))
+}
+
+// canonicalQuery encodes query sorted by key, as SigV4 requires.
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		for _, v := range query[k] {
+			parts = append(parts, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes s the way SigV4 does: every byte except the
+// unreserved characters, and "/" too when encodeSlash is set.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
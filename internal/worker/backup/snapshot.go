@@ -0,0 +1,407 @@
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tables are the tables a backup holds, in restore order: referenced tables
+// come before the tables that reference them. Logs, caches, auth sessions and
+// tokens are left out; they are rebuilt or re-issued on a new server.
+var Tables = []string{
+	"projects",
+	"sdk_sessions",
+	"memories",
+	"memory_revisions",
+	"memory_attachments",
+	"observation_relations",
+	"behavioral_rules",
+	"credentials",
+	"issues",
+	"issue_comments",
+	"versioned_documents",
+	"versioned_document_comments",
+	"reasoning_traces",
+	"project_settings",
+}
+
+// parentRef is a column of a backed-up table holding the id of a row in
+// another backed-up table.
+type parentRef struct {
+	column, table string
+}
+
+// parentRefs lists, per table, the references restore checks. When the
+// parent row was skipped because its id is taken, the row holding that id is
+// a different record, so the child rows of the backed-up parent are skipped
+// too instead of being attached to it.
+var parentRefs = map[string][]parentRef{
+	"memory_revisions":            {{"memory_id", "memories"}},
+	"memory_attachments":          {{"memory_id", "memories"}},
+	"observation_relations":       {{"source_id", "memories"}, {"target_id", "memories"}},
+	"issue_comments":              {{"issue_id", "issues"}},
+	"versioned_document_comments": {{"document_id", "versioned_documents"}},
+}
+
+const (
+	snapshotFormat  = "engram-backup"
+	snapshotVersion = 1
+
+	// restoreBatchSize is how many rows one INSERT restores.
+	restoreBatchSize = 500
+)
+
+// snapshotHeader is the first line of a snapshot.
+type snapshotHeader struct {
+	CreatedAt time.Time `json:"created_at"`
+	Format    string    `json:"format"`
+	Tables    []string  `json:"tables"`
+	Version   int       `json:"version"`
+}
+
+// snapshotRow is every following line: one table row as JSON.
+type snapshotRow struct {
+	Table string          `json:"t"`
+	Row   json.RawMessage `json:"r"`
+}
+
+// TableStats counts the rows of one table in a backup or restore.
+type TableStats struct {
+	Rows     int64 `json:"rows"`
+	Restored int64 `json:"restored,omitempty"`
+	// Skipped rows already existed (same primary key) and were left as is,
+	// or belong to a parent row that was skipped (see parentRefs).
+	Skipped int64 `json:"skipped,omitempty"`
+}
+
+// dump writes a gzipped JSON-lines snapshot of Tables to w. All tables are
+// read in one repeatable-read transaction, so the snapshot is consistent.
+func dump(ctx context.Context, db *gorm.DB, w io.Writer) (map[string]*TableStats, error) {
+	stats := map[string]*TableStats{}
+	gz := gzip.NewWriter(w)
+	enc := json.NewEncoder(gz)
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		present, err := existingTables(tx)
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(snapshotHeader{
+			Format:    snapshotFormat,
+			Version:   snapshotVersion,
+			CreatedAt: time.Now().UTC(),
+			Tables:    present,
+		}); err != nil {
+			return err
+		}
+		for _, table := range present {
+			// table comes from the fixed Tables list, never from input.
+			rows, err := tx.Raw("SELECT row_to_json(t)::text FROM " + table + " t").Rows()
+			if err != nil {
+				return fmt.Errorf("read %s: %w", table, err)
+			}
+			ts := &TableStats{}
+			for rows.Next() {
+				var row string
+				if err := rows.Scan(&row); err != nil {
+					rows.Close()
+					return fmt.Errorf("read %s: %w", table, err)
+				}
+				if err := enc.Encode(snapshotRow{Table: table, Row: json.RawMessage(row)}); err != nil {
+					rows.Close()
+					return err
+				}
+				ts.Rows++
+			}
+			if err := rows.Close(); err != nil {
+				return fmt.Errorf("read %s: %w", table, err)
+			}
+			if err := rows.Err(); err != nil {
+				return fmt.Errorf("read %s: %w", table, err)
+			}
+			stats[table] = ts
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// existingTables returns the entries of Tables present in the database.
+func existingTables(db *gorm.DB) ([]string, error) {
+	var present []string
+	for _, table := range Tables {
+		var exists bool
+		if err := db.Raw("SELECT to_regclass(?) IS NOT NULL", table).Scan(&exists).Error; err != nil {
+			return nil, fmt.Errorf("check table %s: %w", table, err)
+		}
+		if exists {
+			present = append(present, table)
+		}
+	}
+	return present, nil
+}
+
+// restore loads a snapshot written by dump into db in one transaction. Rows
+// whose primary key already exists are skipped, so restoring into a live
+// database only adds what is missing; rows referencing a skipped parent are
+// skipped with it. Sequences are advanced past the restored IDs.
+func restore(ctx context.Context, db *gorm.DB, r io.Reader) (map[string]*TableStats, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a backup file: %w", err)
+	}
+	defer gz.Close()
+	dec := json.NewDecoder(gz)
+
+	var header snapshotHeader
+	if err := dec.Decode(&header); err != nil {
+		return nil, fmt.Errorf("read backup header: %w", err)
+	}
+	if header.Format != snapshotFormat {
+		return nil, fmt.Errorf("not an engram backup (format %q)", header.Format)
+	}
+	if header.Version > snapshotVersion {
+		return nil, fmt.Errorf("backup format version %d is newer than this server supports (%d)", header.Version, snapshotVersion)
+	}
+
+	known := map[string]bool{}
+	for _, table := range Tables {
+		known[table] = true
+	}
+	stats := map[string]*TableStats{}
+	// skipped holds, per parent table of parentRefs, the backed-up ids that
+	// were not restored.
+	skipped := map[string]map[int64]bool{}
+	for _, refs := range parentRefs {
+		for _, ref := range refs {
+			skipped[ref.table] = map[int64]bool{}
+		}
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var (
+			table string
+			batch []json.RawMessage
+			cols  []string
+		)
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			orphans := int64(len(batch))
+			var err error
+			if batch, err = withoutSkippedParents(table, batch, skipped); err != nil {
+				return err
+			}
+			orphans -= int64(len(batch))
+			stats[table].Skipped += orphans
+			if len(batch) == 0 {
+				return nil
+			}
+			if cols == nil {
+				if cols, err = restoreColumns(tx, table, batch[0]); err != nil {
+					return err
+				}
+			}
+			var n int64
+			if ids, isParent := skipped[table]; isParent {
+				n, err = insertParentBatch(tx, table, cols, batch, ids)
+			} else {
+				n, err = insertBatch(tx, table, cols, batch)
+			}
+			if err != nil {
+				return err
+			}
+			stats[table].Restored += n
+			stats[table].Skipped += int64(len(batch)) - n
+			batch = batch[:0]
+			return nil
+		}
+		finish := func() error {
+			if table == "" {
+				return nil
+			}
+			if err := flush(); err != nil {
+				return err
+			}
+			return resetSequence(tx, table)
+		}
+
+		for {
+			var row snapshotRow
+			err := dec.Decode(&row)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("read backup: %w", err)
+			}
+			if !known[row.Table] {
+				return fmt.Errorf("backup has rows for unknown table %q", row.Table)
+			}
+			if row.Table != table {
+				if err := finish(); err != nil {
+					return err
+				}
+				table, cols = row.Table, nil
+				stats[table] = &TableStats{}
+			}
+			stats[table].Rows++
+			batch = append(batch, row.Row)
+			if len(batch) == restoreBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+		return finish()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// restoreColumns returns the columns both the table and the backed-up row
+// have, leaving out generated columns. Columns added since the backup keep
+// their defaults; columns dropped since are ignored.
+func restoreColumns(tx *gorm.DB, table string, sample json.RawMessage) ([]string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(sample, &fields); err != nil {
+		return nil, fmt.Errorf("decode %s row: %w", table, err)
+	}
+	var columns []string
+	err := tx.Raw(`SELECT column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ? AND is_generated = 'NEVER'
+		ORDER BY ordinal_position`, table).Scan(&columns).Error
+	if err != nil {
+		return nil, fmt.Errorf("read %s columns: %w", table, err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s does not exist; run migrations first", table)
+	}
+	var cols []string
+	for _, c := range columns {
+		if _, ok := fields[c]; ok {
+			cols = append(cols, c)
+		}
+	}
+	return cols, nil
+}
+
+// withoutSkippedParents drops the rows of table that reference a parent row
+// recorded in skipped.
+func withoutSkippedParents(table string, rows []json.RawMessage, skipped map[string]map[int64]bool) ([]json.RawMessage, error) {
+	refs := parentRefs[table]
+	if len(refs) == 0 {
+		return rows, nil
+	}
+	kept := rows[:0]
+	for _, raw := range rows {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, fmt.Errorf("decode %s row: %w", table, err)
+		}
+		orphan := false
+		for _, ref := range refs {
+			var id int64
+			if v, ok := fields[ref.column]; ok && json.Unmarshal(v, &id) == nil && skipped[ref.table][id] {
+				orphan = true
+				break
+			}
+		}
+		if !orphan {
+			kept = append(kept, raw)
+		}
+	}
+	return kept, nil
+}
+
+// insertSQL is the statement inserting a JSON array of rows into table,
+// leaving rows whose key already exists alone.
+func insertSQL(table string, cols []string) string {
+	quoted := make([]string, len(cols))
+	for i, c := range cols {
+		quoted[i] = `"` + strings.ReplaceAll(c, `"`, `""`) + `"`
+	}
+	list := strings.Join(quoted, ", ")
+	return "INSERT INTO " + table + " (" + list + ") OVERRIDING SYSTEM VALUE SELECT " + list +
+		" FROM json_populate_recordset(NULL::" + table + ", ?::json) ON CONFLICT DO NOTHING"
+}
+
+// insertBatch inserts rows into table and returns how many were new.
+func insertBatch(tx *gorm.DB, table string, cols []string, rows []json.RawMessage) (int64, error) {
+	payload, err := json.Marshal(rows)
+	if err != nil {
+		return 0, err
+	}
+	res := tx.Exec(insertSQL(table, cols), string(payload))
+	if res.Error != nil {
+		return 0, fmt.Errorf("restore %s: %w", table, res.Error)
+	}
+	return res.RowsAffected, nil
+}
+
+// insertParentBatch is insertBatch for a table other rows reference: the ids
+// of rows that were not inserted are added to skipped.
+func insertParentBatch(tx *gorm.DB, table string, cols []string, rows []json.RawMessage, skipped map[int64]bool) (int64, error) {
+	payload, err := json.Marshal(rows)
+	if err != nil {
+		return 0, err
+	}
+	var inserted []int64
+	if err := tx.Raw(insertSQL(table, cols)+" RETURNING id", string(payload)).Scan(&inserted).Error; err != nil {
+		return 0, fmt.Errorf("restore %s: %w", table, err)
+	}
+	isNew := make(map[int64]bool, len(inserted))
+	for _, id := range inserted {
+		isNew[id] = true
+	}
+	for _, raw := range rows {
+		var row struct {
+			ID int64 `json:"id"`
+		}
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return 0, fmt.Errorf("decode %s row: %w", table, err)
+		}
+		if !isNew[row.ID] {
+			skipped[row.ID] = true
+		}
+	}
+	return int64(len(inserted)), nil
+}
+
+// resetSequence moves the id sequence of table past its largest id, so new
+// rows do not collide with restored ones. Tables without one are left alone.
+func resetSequence(tx *gorm.DB, table string) error {
+	var seq sql.NullString
+	err := tx.Raw(`SELECT pg_get_serial_sequence(?, 'id')
+		WHERE EXISTS (SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = ? AND column_name = 'id')`, table, table).Scan(&seq).Error
+	if err != nil {
+		return fmt.Errorf("read %s sequence: %w", table, err)
+	}
+	if !seq.Valid {
+		return nil
+	}
+	err = tx.Exec(`SELECT setval(?::regclass, m) FROM (SELECT MAX(id) AS m FROM `+table+`) s WHERE m IS NOT NULL`, seq.String).Error
+	if err != nil {
+		return fmt.Errorf("reset %s sequence: %w", table, err)
+	}
+	return nil
+}
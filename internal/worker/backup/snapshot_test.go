@@ -0,0 +1,125 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testSnapshotDB opens a postgres test DB on a private schema holding a
+// minimal projects, memories and memory_revisions table. Tests skip when DATABASE_DSN is not set.
+func testSnapshotDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("DATABASE_DSN not set, skipping backup integration test")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// One connection, so the search_path below applies to every query.
+	sqlDB.SetMaxOpenConns(1)
+
+	schema := fmt.Sprintf("backup_test_%d", time.Now().UnixNano())
+	for _, stmt := range []string{
+		"CREATE SCHEMA " + schema,
+		"SET search_path TO " + schema,
+		`CREATE TABLE projects (id TEXT PRIMARY KEY, display_name TEXT)`,
+		`CREATE TABLE memories (
+			id BIGSERIAL PRIMARY KEY,
+			project TEXT NOT NULL,
+			content TEXT NOT NULL,
+			tags JSONB NOT NULL DEFAULT '[]',
+			search_vector tsvector GENERATED ALWAYS AS (to_tsvector('english', content)) STORED
+		)`,
+		`CREATE TABLE memory_revisions (
+			id BIGSERIAL PRIMARY KEY,
+			memory_id BIGINT NOT NULL REFERENCES memories(id) ON DELETE CASCADE,
+			content TEXT NOT NULL
+		)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error, stmt)
+	}
+	t.Cleanup(func() {
+		db.Exec("DROP SCHEMA " + schema + " CASCADE")
+		sqlDB.Close()
+	})
+	return db
+}
+
+func TestDumpRestore(t *testing.T) {
+	db := testSnapshotDB(t)
+	ctx := context.Background()
+
+	require.NoError(t, db.Exec(`INSERT INTO projects (id, display_name) VALUES ('p1', 'Project')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO memories (project, content, tags) VALUES
+		('p1', 'use pgx for postgres', '["decision"]'), ('p1', 'second', '[]')`).Error)
+
+	var buf bytes.Buffer
+	dumped, err := dump(ctx, db, &buf)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, dumped["projects"].Rows)
+	assert.EqualValues(t, 2, dumped["memories"].Rows)
+	assert.NotContains(t, dumped, "behavioral_rules", "missing tables are skipped")
+
+	// Lose one memory, then restore: only the missing row comes back.
+	require.NoError(t, db.Exec(`DELETE FROM memories WHERE content = 'second'`).Error)
+	restored, err := restore(ctx, db, bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, restored["memories"].Restored)
+	assert.EqualValues(t, 1, restored["memories"].Skipped)
+	assert.EqualValues(t, 1, restored["projects"].Skipped)
+
+	var tags string
+	require.NoError(t, db.Raw(`SELECT tags::text FROM memories WHERE id = 1`).Scan(&tags).Error)
+	assert.JSONEq(t, `["decision"]`, tags)
+
+	// The sequence moved past the restored IDs.
+	var next int64
+	require.NoError(t, db.Raw(`INSERT INTO memories (project, content) VALUES ('p1', 'new') RETURNING id`).Scan(&next).Error)
+	assert.EqualValues(t, 3, next)
+}
+
+func TestRestore_SkipsChildrenOfSkippedParents(t *testing.T) {
+	db := testSnapshotDB(t)
+	ctx := context.Background()
+
+	require.NoError(t, db.Exec(`INSERT INTO memories (project, content) VALUES ('p1', 'backed up')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO memory_revisions (memory_id, content) VALUES (1, 'old text')`).Error)
+
+	var buf bytes.Buffer
+	_, err := dump(ctx, db, &buf)
+	require.NoError(t, err)
+
+	// Id 1 now belongs to a different memory with no history.
+	require.NoError(t, db.Exec(`DELETE FROM memories`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO memories (id, project, content) VALUES (1, 'p2', 'someone else')`).Error)
+
+	restored, err := restore(ctx, db, bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, restored["memories"].Skipped)
+	assert.EqualValues(t, 1, restored["memory_revisions"].Skipped)
+	assert.EqualValues(t, 0, restored["memory_revisions"].Restored)
+
+	var revisions int64
+	require.NoError(t, db.Raw(`SELECT COUNT(*) FROM memory_revisions`).Scan(&revisions).Error)
+	assert.Zero(t, revisions, "the backed-up revision must not attach to the other memory")
+}
+
+func TestRestore_RejectsOtherFiles(t *testing.T) {
+	_, err := restore(context.Background(), nil, bytes.NewReader([]byte("not gzip")))
+	assert.Error(t, err)
+}
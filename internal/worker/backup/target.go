@@ -0,0 +1,163 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by Target.Get for a missing backup.
+var ErrNotFound = errors.New("backup not found")
+
+// Object is one stored backup.
+type Object struct {
+	Modified time.Time `json:"modified"`
+	Name     string    `json:"name"`
+	Size     int64     `json:"size"`
+}
+
+// Target stores backup files. Names are flat (no slashes); a target applies
+// its own prefix.
+type Target interface {
+	Put(ctx context.Context, name string, data []byte) error
+	Get(ctx context.Context, name string) ([]byte, error)
+	// List returns the stored objects whose name starts with prefix.
+	List(ctx context.Context, prefix string) ([]Object, error)
+	Delete(ctx context.Context, name string) error
+	// String describes the target without credentials, for logs and the API.
+	String() string
+}
+
+// Credentials are the access keys of an S3-compatible target.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	Region          string
+	// Endpoint overrides the service URL (MinIO, R2, a GCS interop proxy...).
+	Endpoint string
+}
+
+// ParseTarget builds a Target from a location:
+//
+//	file:///var/backups/engram       local or mounted directory
+//	s3://bucket/prefix               Amazon S3 or any S3-compatible store
+//	gs://bucket/prefix               Google Cloud Storage (HMAC interop keys)
+//	https://acct.blob.core.windows.net/container/prefix?<SAS>   Azure Blob
+//
+// Azure is addressed with a container SAS URL, so it needs no other keys.
+func ParseTarget(raw string, creds Credentials) (Target, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("parse backup target: %w", err)
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Path == "" {
+			return nil, fmt.Errorf("backup target %q has no path", raw)
+		}
+		return &fileTarget{dir: filepath.FromSlash(u.Path)}, nil
+	case "s3", "gs":
+		if u.Host == "" {
+			return nil, fmt.Errorf("backup target %q has no bucket", raw)
+		}
+		if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+			return nil, fmt.Errorf("backup target %q needs an access key id and secret", raw)
+		}
+		return newS3Target(u.Scheme, u.Host, strings.Trim(u.Path, "/"), creds)
+	case "https", "http":
+		if u.Query().Get("sig") == "" {
+			return nil, fmt.Errorf("backup target %q: https targets must be Azure container SAS URLs", redactURL(u))
+		}
+		return newAzureTarget(u)
+	default:
+		return nil, fmt.Errorf("unsupported backup target scheme %q (want file, s3, gs or an Azure SAS URL)", u.Scheme)
+	}
+}
+
+// objectKey joins a target prefix and a backup name.
+func objectKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "/" + name
+}
+
+// redactURL returns u without its query, which carries the SAS signature.
+func redactURL(u *url.URL) string {
+	c := *u
+	c.RawQuery = ""
+	c.User = nil
+	return c.String()
+}
+
+// fileTarget stores backups in a directory.
+type fileTarget struct {
+	dir string
+}
+
+func (t *fileTarget) String() string { return "file://" + filepath.ToSlash(t.dir) }
+
+func (t *fileTarget) Put(_ context.Context, name string, data []byte) error {
+	if err := os.MkdirAll(t.dir, 0o700); err != nil {
+		return err
+	}
+	// Write then rename so a crash never leaves a truncated backup behind.
+	tmp, err := os.CreateTemp(t.dir, ".partial-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(t.dir, name))
+}
+
+func (t *fileTarget) Get(_ context.Context, name string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(t.dir, filepath.Base(name)))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+func (t *fileTarget) List(_ context.Context, prefix string) ([]Object, error) {
+	entries, err := os.ReadDir(t.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var objects []Object
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), prefix) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		objects = append(objects, Object{Name: e.Name(), Size: info.Size(), Modified: info.ModTime().UTC()})
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+	return objects, nil
+}
+
+func (t *fileTarget) Delete(_ context.Context, name string) error {
+	err := os.Remove(filepath.Join(t.dir, filepath.Base(name)))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/thebtf/engram/internal/concepts"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+const (
+	// conceptBoostWeight is the share of the blended score that comes from
+	// concept overlap; the rest comes from matching the query text. A text
+	// match therefore always outranks a concept-only match, and concept
+	// overlap orders the text matches among themselves.
+	conceptBoostWeight = 0.3
+
+	// conceptCandidateMultiplier widens the candidate pool for concept-only
+	// matches relative to the requested result count.
+	conceptCandidateMultiplier = 5
+)
+
+// applyConceptBoost blends text matches with observations sharing concepts
+// extracted from the prompt. Observations in matched score
+// (1-conceptBoostWeight) for the text match; every observation in matched or
+// candidates gains conceptBoostWeight times its concept overlap. Candidates
+// without overlap are dropped. The result is ordered by blended score,
+// keeping the incoming order for ties.
+func applyConceptBoost(matched, candidates []*models.Observation, boost []concepts.Concept) ([]*models.Observation, map[int64]float64) {
+	scores := make(map[int64]float64, len(matched))
+	out := make([]*models.Observation, 0, len(matched))
+	for _, obs := range matched {
+		if _, seen := scores[obs.ID]; seen {
+			continue
+		}
+		scores[obs.ID] = 1 - conceptBoostWeight + conceptBoostWeight*conceptOverlap(obs.Concepts, boost)
+		out = append(out, obs)
+	}
+	for _, obs := range candidates {
+		if _, seen := scores[obs.ID]; seen {
+			continue
+		}
+		if overlap := conceptOverlap(obs.Concepts, boost); overlap > 0 {
+			scores[obs.ID] = conceptBoostWeight * overlap
+			out = append(out, obs)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return scores[out[i].ID] > scores[out[j].ID]
+	})
+	return out, scores
+}
+
+// conceptOverlap returns the confidence-weighted share of the boost concepts
+// found in tags (with or without a "concept:" prefix), between 0 and 1.
+func conceptOverlap(tags []string, boost []concepts.Concept) float64 {
+	if len(tags) == 0 || len(boost) == 0 {
+		return 0
+	}
+	have := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		have[strings.TrimPrefix(strings.ToLower(tag), "concept:")] = true
+	}
+	var shared, total float64
+	for _, c := range boost {
+		total += c.Confidence
+		if have[c.Name] {
+			shared += c.Confidence
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return shared / total
+}
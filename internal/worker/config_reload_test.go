@@ -0,0 +1,92 @@
+package worker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/config"
+	"github.com/thebtf/engram/internal/worker/sse"
+)
+
+func TestReloadConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	require.NoError(t, config.EnsureDataDir())
+	writeSettings := func(settings string) {
+		require.NoError(t, os.WriteFile(config.SettingsPath(), []byte(settings), 0600))
+	}
+	writeSettings(`{}`)
+	_, _, err := config.Reload()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.Remove(config.SettingsPath())
+		_, _, _ = config.Reload()
+	})
+
+	s := &Service{
+		config:         config.Get(),
+		sseBroadcaster: sse.NewBroadcaster(),
+		restartCh:      make(chan struct{}, 1),
+	}
+
+	// Limits and thresholds apply in place.
+	writeSettings(`{"ENGRAM_CONTEXT_OBSERVATIONS": 42, "ENGRAM_CONTEXT_RELEVANCE_THRESHOLD": 0.45}`)
+	s.reloadConfig()
+	assert.Equal(t, 42, s.currentConfig().ContextObservations)
+	assert.Equal(t, 0.45, s.currentConfig().ContextRelevanceThreshold)
+	select {
+	case <-s.RestartRequested():
+		t.Fatal("restart requested for settings that apply in place")
+	default:
+	}
+
+	// A new listen port needs a restart.
+	writeSettings(`{"ENGRAM_CONTEXT_OBSERVATIONS": 42, "ENGRAM_CONTEXT_RELEVANCE_THRESHOLD": 0.45, "ENGRAM_WORKER_PORT": 38888}`)
+	s.reloadConfig()
+	select {
+	case <-s.RestartRequested():
+	default:
+		t.Fatal("changing worker_port did not request a restart")
+	}
+}
+
+func TestReloadConfig_StrictKeepsCurrent(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	require.NoError(t, config.EnsureDataDir())
+	require.NoError(t, os.WriteFile(config.SettingsPath(), []byte(`{"ENGRAM_CONTEXT_OBSERVATIONS": 42}`), 0600))
+	_, _, err := config.Reload()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.Remove(config.SettingsPath())
+		_, _, _ = config.Reload()
+	})
+	s := &Service{config: config.Get(), sseBroadcaster: sse.NewBroadcaster(), restartCh: make(chan struct{}, 1)}
+
+	t.Setenv("ENGRAM_CONFIG_STRICT", "true")
+	require.NoError(t, os.WriteFile(config.SettingsPath(), []byte(`{"ENGRAM_CONTEXT_OBSERVATIONS": "7"}`), 0600))
+	s.reloadConfig()
+	assert.Equal(t, 42, s.currentConfig().ContextObservations)
+
+	rec := httptest.NewRecorder()
+	s.handleEffectiveConfig(rec, httptest.NewRequest(http.MethodGet, "/api/config/effective", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp struct {
+		Issues   []config.Issue            `json:"issues"`
+		Settings []config.EffectiveSetting `json:"settings"`
+		Strict   bool                      `json:"strict"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.True(t, resp.Strict)
+	require.Len(t, resp.Issues, 1)
+	assert.Equal(t, "ENGRAM_CONTEXT_OBSERVATIONS", resp.Issues[0].Key)
+	assert.NotEmpty(t, resp.Settings)
+}
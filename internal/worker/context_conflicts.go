@@ -0,0 +1,204 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/thebtf/engram/pkg/models"
+	"github.com/thebtf/engram/pkg/similarity"
+)
+
+const (
+	// conflictSimilarity is the Jaccard similarity (negations aside) above
+	// which two memories are taken to state the same fact, so a negation in
+	// only one of them makes them contradict each other.
+	conflictSimilarity = 0.7
+
+	// minConflictTerms keeps short memories, whose similarity is mostly
+	// noise, out of lexical conflict detection.
+	minConflictTerms = 4
+)
+
+// conflictRelationTypes are the relations that mark two memories as not
+// both true.
+var conflictRelationTypes = []models.RelationType{
+	models.RelationContradicts,
+	models.RelationSupersedes,
+	models.RelationInvalidatedBy,
+}
+
+// negationTerms are the terms (as tokenized by pkg/similarity, so "don't"
+// is "don") that flip the meaning of an otherwise identical statement.
+var negationTerms = map[string]bool{
+	"not": true, "never": true, "none": true, "nor": true, "without": true, "cannot": true,
+	"don": true, "doesn": true, "didn": true, "isn": true, "aren": true,
+	"wasn": true, "weren": true, "won": true, "shouldn": true, "mustn": true,
+}
+
+// contextConflict is a pair of injected memories that cannot both be true.
+// Dropped is left out of the injected context in favour of Kept. Resolved
+// is set when a relation already says which one holds (supersedes,
+// invalidated_by); otherwise Kept is only the newer of the two and is
+// flagged as conflicting.
+type contextConflict struct {
+	Reason   string `json:"reason"`
+	Kept     int64  `json:"kept"`
+	Dropped  int64  `json:"dropped"`
+	Resolved bool   `json:"resolved"`
+}
+
+// detectContextConflicts finds the memories among observations that
+// contradict each other, either through a contradicts, supersedes or
+// invalidated_by relation or because they state the same thing with
+// opposite negation or polarity. Pinned memories are never dropped. Each
+// memory is dropped at most once, and a dropped memory does not take part
+// in later conflicts.
+func detectContextConflicts(observations []*models.Observation, relations []*models.ObservationRelation, pinned map[int64]struct{}) []contextConflict {
+	byID := make(map[int64]*models.Observation, len(observations))
+	for _, obs := range observations {
+		byID[obs.ID] = obs
+	}
+
+	var conflicts []contextConflict
+	dropped := make(map[int64]bool)
+	add := func(kept, drop *models.Observation, reason string, resolved bool) {
+		if kept.ID == drop.ID || dropped[kept.ID] || dropped[drop.ID] {
+			return
+		}
+		if _, ok := pinned[drop.ID]; ok {
+			if _, ok := pinned[kept.ID]; ok {
+				return
+			}
+			kept, drop = drop, kept
+			resolved = false
+		}
+		dropped[drop.ID] = true
+		conflicts = append(conflicts, contextConflict{Reason: reason, Kept: kept.ID, Dropped: drop.ID, Resolved: resolved})
+	}
+
+	for _, rel := range relations {
+		source, target := byID[rel.SourceID], byID[rel.TargetID]
+		if source == nil || target == nil {
+			continue
+		}
+		switch rel.RelationType {
+		case models.RelationSupersedes:
+			add(source, target, "supersedes", true)
+		case models.RelationInvalidatedBy:
+			add(target, source, "invalidated_by", true)
+		case models.RelationContradicts:
+			newer, older := newerObservation(source, target)
+			add(newer, older, "contradicts", false)
+		}
+	}
+
+	type statement struct {
+		obs     *models.Observation
+		terms   map[string]bool
+		negated bool
+		avoid   bool
+	}
+	statements := make([]statement, 0, len(observations))
+	for _, obs := range observations {
+		terms := similarity.ExtractObservationTerms(obs)
+		negated := false
+		for term := range terms {
+			if negationTerms[term] {
+				negated = true
+				delete(terms, term)
+			}
+		}
+		if len(terms) < minConflictTerms {
+			continue
+		}
+		statements = append(statements, statement{
+			obs:     obs,
+			terms:   terms,
+			negated: negated,
+			avoid:   models.PolarityFromTags(obs.Concepts) == models.PolarityDoNot,
+		})
+	}
+	for i := range statements {
+		for j := i + 1; j < len(statements); j++ {
+			a, b := statements[i], statements[j]
+			if a.negated == b.negated && a.avoid == b.avoid {
+				continue
+			}
+			if similarity.JaccardSimilarity(a.terms, b.terms) < conflictSimilarity {
+				continue
+			}
+			newer, older := newerObservation(a.obs, b.obs)
+			add(newer, older, "opposite statements", false)
+		}
+	}
+	return conflicts
+}
+
+// newerObservation orders a and b by creation time, the higher ID winning a
+// tie.
+func newerObservation(a, b *models.Observation) (newer, older *models.Observation) {
+	if a.CreatedAtEpoch > b.CreatedAtEpoch || (a.CreatedAtEpoch == b.CreatedAtEpoch && a.ID > b.ID) {
+		return a, b
+	}
+	return b, a
+}
+
+// droppedConflictIDs returns the IDs conflicts leave out of the context.
+func droppedConflictIDs(conflicts []contextConflict) map[int64]struct{} {
+	ids := make(map[int64]struct{}, len(conflicts))
+	for _, c := range conflicts {
+		ids[c.Dropped] = struct{}{}
+	}
+	return ids
+}
+
+// conflictBanner returns the note rendered after the injected memories for
+// the conflicts no relation resolved, so the agent treats the kept memory
+// as disputed instead of as settled fact. It is "" when there are none.
+func conflictBanner(conflicts []contextConflict) string {
+	var lines []string
+	for _, c := range conflicts {
+		if c.Resolved {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- #%d conflicts with #%d (%s), which was left out. Verify #%d before relying on it.", c.Kept, c.Dropped, c.Reason, c.Kept))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "\n## ⚠️ Conflicting information\n" + strings.Join(lines, "\n") + "\n"
+}
+
+// contextConflicts loads the conflict relations among the memories about to
+// be injected and detects the conflicts between them. A failed relation
+// lookup only disables the relation-based half of the detection.
+func (s *Service) contextConflicts(ctx context.Context, sections [][]*models.Observation, pinned map[int64]struct{}) []contextConflict {
+	var observations []*models.Observation
+	var ids []int64
+	seen := make(map[int64]bool)
+	for _, section := range sections {
+		for _, obs := range section {
+			if !seen[obs.ID] {
+				seen[obs.ID] = true
+				observations = append(observations, obs)
+				ids = append(ids, obs.ID)
+			}
+		}
+	}
+
+	s.initMu.RLock()
+	relationStore := s.relationStore
+	s.initMu.RUnlock()
+	var relations []*models.ObservationRelation
+	if relationStore != nil {
+		var err error
+		relations, err = relationStore.GetRelationsAmong(ctx, ids, conflictRelationTypes)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to load conflict relations for context injection")
+		}
+	}
+	return detectContextConflicts(observations, relations, pinned)
+}
@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func conflictObs(id, createdAt int64, text string, tags ...string) *models.Observation {
+	return &models.Observation{
+		ID:             id,
+		CreatedAtEpoch: createdAt,
+		Narrative:      sql.NullString{String: text, Valid: true},
+		Concepts:       models.JSONStringArray(tags),
+	}
+}
+
+func TestDetectContextConflicts_Relations(t *testing.T) {
+	older := conflictObs(1, 100, "Deploys go through the staging cluster")
+	newer := conflictObs(2, 200, "Production releases ship directly from main")
+	replaced := conflictObs(3, 300, "Use pgbouncer in session mode")
+	replacement := conflictObs(4, 50, "Use pgbouncer in transaction mode")
+	stale := conflictObs(5, 400, "The API listens on port 8080")
+	fresh := conflictObs(6, 10, "The API listens on port 37777")
+
+	relations := []*models.ObservationRelation{
+		{SourceID: 1, TargetID: 2, RelationType: models.RelationContradicts},
+		{SourceID: 4, TargetID: 3, RelationType: models.RelationSupersedes},
+		{SourceID: 5, TargetID: 6, RelationType: models.RelationInvalidatedBy},
+		{SourceID: 1, TargetID: 99, RelationType: models.RelationContradicts},
+	}
+	conflicts := detectContextConflicts([]*models.Observation{older, newer, replaced, replacement, stale, fresh}, relations, nil)
+	assert.Equal(t, []contextConflict{
+		{Reason: "contradicts", Kept: 2, Dropped: 1},
+		{Reason: "supersedes", Kept: 4, Dropped: 3, Resolved: true},
+		{Reason: "invalidated_by", Kept: 6, Dropped: 5, Resolved: true},
+	}, conflicts)
+
+	banner := conflictBanner(conflicts)
+	assert.Contains(t, banner, "Conflicting information")
+	assert.Contains(t, banner, "#2 conflicts with #1 (contradicts)")
+	assert.NotContains(t, banner, "#4", "resolved conflicts need no banner")
+}
+
+func TestDetectContextConflicts_OppositeStatements(t *testing.T) {
+	obs := []*models.Observation{
+		conflictObs(1, 100, "The billing service retries failed webhook deliveries automatically"),
+		conflictObs(2, 200, "The billing service never retries failed webhook deliveries automatically"),
+		conflictObs(3, 300, "The search service caches ranked results per project"),
+		conflictObs(4, 400, "Run migrations before starting the worker in local development", models.PolarityTagPrefix+string(models.PolarityDoNot)),
+		conflictObs(5, 500, "Run migrations before starting the worker in local development"),
+	}
+	conflicts := detectContextConflicts(obs, nil, map[int64]struct{}{4: {}})
+	assert.Equal(t, []contextConflict{
+		{Reason: "opposite statements", Kept: 2, Dropped: 1},
+		{Reason: "opposite statements", Kept: 4, Dropped: 5},
+	}, conflicts, "a pinned memory is kept even when it is the older one")
+
+	assert.Empty(t, detectContextConflicts(obs[:1], nil, nil))
+	assert.Empty(t, conflictBanner(nil))
+}
+
+func TestAppendToContext(t *testing.T) {
+	rendered := "<engram-context>\n## Recent\n- note\n</engram-context>\n"
+	out := appendToContext(rendered, "\n## ⚠️ Conflicting information\n- x\n")
+	require.True(t, strings.HasSuffix(out, "- x\n</engram-context>\n"), out)
+	assert.Equal(t, "", appendToContext("", "note"))
+	assert.Equal(t, rendered, appendToContext(rendered, ""))
+}
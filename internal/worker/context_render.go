@@ -0,0 +1,145 @@
+package worker
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/thebtf/engram/internal/config"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// contextSection is one titled group of memories in the rendered context.
+// Trust holds the trust line of each memory by ID (see trustLine); it is nil
+// when trust lines are off.
+type contextSection struct {
+	Trust        map[int64]string
+	Title        string
+	Observations []*models.Observation
+}
+
+// contextHeaderData is the data of the header template.
+type contextHeaderData struct {
+	Project string
+	Count   int
+}
+
+// contextItemData is the data of the per-memory template. Facts holds the
+// memory's facts already rendered with the fact template. Marker is the
+// warning prefix of caution and do_not memories ("" for positive ones).
+// Trust is the memory's trust line ("" when trust lines are off).
+type contextItemData struct {
+	Section   string
+	Type      string
+	Polarity  string
+	Marker    string
+	Title     string
+	Subtitle  string
+	Narrative string
+	Facts     string
+	Trust     string
+	ID        int64
+}
+
+// contextFactData is the data of the fact template.
+type contextFactData struct {
+	Fact  string
+	Index int
+}
+
+// renderInjectedContext renders the sections into the <engram-context> block
+// the hooks inject verbatim. Memory text is escaped so it cannot close the
+// block. Empty sections are skipped; with no memories at all the result is "".
+func renderInjectedContext(layout config.ContextLayout, project string, sections []contextSection) (string, error) {
+	header, err := template.New("header").Parse(layout.Header)
+	if err != nil {
+		return "", fmt.Errorf("parse header template: %w", err)
+	}
+	item, err := template.New("item").Parse(layout.Item)
+	if err != nil {
+		return "", fmt.Errorf("parse item template: %w", err)
+	}
+	fact, err := template.New("fact").Parse(layout.Fact)
+	if err != nil {
+		return "", fmt.Errorf("parse fact template: %w", err)
+	}
+
+	count := 0
+	for _, sec := range sections {
+		count += len(sec.Observations)
+	}
+	if count == 0 {
+		return "", nil
+	}
+
+	var b strings.Builder
+	b.WriteString("<engram-context>\n")
+	if err := header.Execute(&b, contextHeaderData{Project: escapeContextTags(project), Count: count}); err != nil {
+		return "", fmt.Errorf("render header: %w", err)
+	}
+	for _, sec := range sections {
+		if len(sec.Observations) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n", sec.Title)
+		for _, obs := range sec.Observations {
+			var facts strings.Builder
+			for i, f := range obs.Facts {
+				if f == "" {
+					continue
+				}
+				if err := fact.Execute(&facts, contextFactData{Fact: escapeContextTags(f), Index: i + 1}); err != nil {
+					return "", fmt.Errorf("render fact: %w", err)
+				}
+			}
+			polarity := models.PolarityFromTags(obs.Concepts)
+			data := contextItemData{
+				Section:   sec.Title,
+				Type:      escapeContextTags(string(obs.Type)),
+				Polarity:  string(polarity),
+				Marker:    polarityMarker(polarity),
+				Title:     escapeContextTags(obs.Title.String),
+				Subtitle:  escapeContextTags(obs.Subtitle.String),
+				Narrative: escapeContextTags(obs.Narrative.String),
+				Facts:     facts.String(),
+				Trust:     sec.Trust[obs.ID],
+				ID:        obs.ID,
+			}
+			if err := item.Execute(&b, data); err != nil {
+				return "", fmt.Errorf("render item: %w", err)
+			}
+		}
+	}
+	if !strings.HasSuffix(b.String(), "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString("</engram-context>\n")
+	return b.String(), nil
+}
+
+// polarityMarker returns the prefix that sets warnings apart from knowledge
+// to apply in the rendered context.
+func polarityMarker(p models.Polarity) string {
+	switch p {
+	case models.PolarityDoNot:
+		return "⚠️ Avoid: "
+	case models.PolarityCaution:
+		return "⚠️ Caution: "
+	}
+	return ""
+}
+
+// escapeContextTags escapes angle brackets, as the hooks do for injected text.
+func escapeContextTags(s string) string {
+	return strings.NewReplacer("<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// appendToContext adds text at the end of a rendered <engram-context> block,
+// inside the closing tag. An empty block stays empty.
+func appendToContext(rendered, text string) string {
+	if rendered == "" || text == "" {
+		return rendered
+	}
+	const closing = "</engram-context>\n"
+	return strings.TrimSuffix(rendered, closing) + text + closing
+}
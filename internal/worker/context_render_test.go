@@ -0,0 +1,142 @@
+package worker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thebtf/engram/internal/config"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestRenderInjectedContext_DefaultLayout(t *testing.T) {
+	sections := []contextSection{
+		{Title: "Pinned", Observations: []*models.Observation{makeObs(1, "Use pgx", "The driver is pgx <v5>.", []string{"pool size 10"})}},
+		{Title: "Recent", Observations: nil},
+		{Title: "Relevant", Observations: []*models.Observation{makeObs(2, "Retry policy", "", nil)}},
+	}
+	out, err := renderInjectedContext(config.ContextTemplate{}.LayoutFor("engram"), "engram", sections)
+	if err != nil {
+		t.Fatalf("renderInjectedContext: %v", err)
+	}
+	want := "<engram-context>\n" +
+		"# Engram memory for engram\nPrefer these memories before rediscovering context.\n" +
+		"\n## Pinned\n" +
+		"- [discovery] Use pgx\n  The driver is pgx &lt;v5&gt;.\n  - pool size 10\n" +
+		"\n## Relevant\n" +
+		"- [discovery] Retry policy\n" +
+		"</engram-context>\n"
+	if out != want {
+		t.Errorf("rendered:\n%q\nwant:\n%q", out, want)
+	}
+}
+
+func TestRenderInjectedContext_PolarityMarkers(t *testing.T) {
+	avoid := makeObs(1, "Vendoring the SDK", "", nil)
+	avoid.Concepts = models.JSONStringArray{"type:pitfall", "polarity:do_not"}
+	caution := makeObs(2, "Retries hide the race", "", nil)
+	caution.Concepts = models.JSONStringArray{"polarity:caution"}
+	sections := []contextSection{{Title: "Relevant", Observations: []*models.Observation{avoid, caution, makeObs(3, "Use pgx", "", nil)}}}
+
+	out, err := renderInjectedContext(config.ContextTemplate{}.LayoutFor("p"), "p", sections)
+	if err != nil {
+		t.Fatalf("renderInjectedContext: %v", err)
+	}
+	for _, want := range []string{
+		"- [discovery] ⚠️ Avoid: Vendoring the SDK\n",
+		"- [discovery] ⚠️ Caution: Retries hide the race\n",
+		"- [discovery] Use pgx\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rendered output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderInjectedContext_CustomLayout(t *testing.T) {
+	layout := config.ContextTemplate{
+		Default:  config.ContextLayout{Fact: "{{.Index}}. {{.Fact}}\n"},
+		Projects: map[string]config.ContextLayout{"docs": {Header: "{{.Count}} memories\n", Item: "#{{.ID}} {{.Title}}\n{{.Facts}}"}},
+	}.LayoutFor("docs")
+	sections := []contextSection{{Title: "Recent", Observations: []*models.Observation{makeObs(7, "Style", "", []string{"tabs", "", "80 cols"})}}}
+
+	out, err := renderInjectedContext(layout, "docs", sections)
+	if err != nil {
+		t.Fatalf("renderInjectedContext: %v", err)
+	}
+	want := "<engram-context>\n1 memories\n\n## Recent\n#7 Style\n1. tabs\n3. 80 cols\n</engram-context>\n"
+	if out != want {
+		t.Errorf("rendered:\n%q\nwant:\n%q", out, want)
+	}
+}
+
+func TestRenderInjectedContext_EmptyAndInvalid(t *testing.T) {
+	layout := config.ContextTemplate{}.LayoutFor("p")
+	out, err := renderInjectedContext(layout, "p", []contextSection{{Title: "Recent"}})
+	if err != nil || out != "" {
+		t.Errorf("empty sections: got %q, %v; want empty", out, err)
+	}
+
+	layout.Item = "{{.Title"
+	sections := []contextSection{{Title: "Recent", Observations: []*models.Observation{makeObs(1, "x", "", nil)}}}
+	if _, err := renderInjectedContext(layout, "p", sections); err == nil || !strings.Contains(err.Error(), "item template") {
+		t.Errorf("invalid item template: err = %v, want parse error", err)
+	}
+}
+
+func TestRenderInjectedContext_TrustLine(t *testing.T) {
+	sections := []contextSection{{
+		Title:        "Relevant",
+		Observations: []*models.Observation{makeObs(1, "Use pgx", "", nil), makeObs(2, "Retry policy", "", nil)},
+		Trust:        map[int64]string{1: "captured 3d ago, confirmed twice, score 0.98"},
+	}}
+	out, err := renderInjectedContext(config.ContextTemplate{}.LayoutFor("p"), "p", sections)
+	if err != nil {
+		t.Fatalf("renderInjectedContext: %v", err)
+	}
+	for _, want := range []string{
+		"- [discovery] Use pgx (captured 3d ago, confirmed twice, score 0.98)\n",
+		"- [discovery] Retry policy\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rendered output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestTrustLine(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	obs := makeObs(1, "Use pgx", "", nil)
+	obs.SourceType = models.SourceManual // 30-day half-life
+
+	obs.CreatedAtEpoch = now.Add(-30 * 24 * time.Hour).UnixMilli()
+	if got, want := trustLine(obs, 0, now), "captured 30d ago, unconfirmed, score 0.50"; got != want {
+		t.Errorf("trustLine = %q, want %q", got, want)
+	}
+	if got, want := trustLine(obs, 2, now), "captured 30d ago, confirmed twice, score 0.88"; got != want {
+		t.Errorf("trustLine = %q, want %q", got, want)
+	}
+
+	obs.CreatedAtEpoch = now.Add(-time.Hour).UnixMilli()
+	if got, want := trustLine(obs, 1, now), "captured today, confirmed once, score 1.00"; got != want {
+		t.Errorf("trustLine = %q, want %q", got, want)
+	}
+
+	obs.CreatedAtEpoch = 0
+	if got, want := trustLine(obs, 5, now), "confirmed 5 times, score 1.00"; got != want {
+		t.Errorf("trustLine = %q, want %q", got, want)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		5 * time.Hour:        "today",
+		3 * 24 * time.Hour:   "3d ago",
+		90 * 24 * time.Hour:  "3mo ago",
+		800 * 24 * time.Hour: "2y ago",
+	} {
+		if got := formatAge(d); got != want {
+			t.Errorf("formatAge(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
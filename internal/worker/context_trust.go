@@ -0,0 +1,102 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// contextTrustLines returns the trust line of every memory in sections by
+// ID (see trustLine). Confirmations come from the relation store; without
+// one every memory counts as unconfirmed.
+func (s *Service) contextTrustLines(ctx context.Context, sections []contextSection, now time.Time) map[int64]string {
+	var ids []int64
+	for _, sec := range sections {
+		for _, obs := range sec.Observations {
+			ids = append(ids, obs.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	s.initMu.RLock()
+	relationStore := s.relationStore
+	s.initMu.RUnlock()
+	confirmations := map[int64]int{}
+	if relationStore != nil {
+		counts, err := relationStore.GetConfirmationCountsBatch(ctx, ids)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to load confirmations for context trust lines")
+		} else {
+			confirmations = counts
+		}
+	}
+
+	lines := make(map[int64]string, len(ids))
+	for _, sec := range sections {
+		for _, obs := range sec.Observations {
+			lines[obs.ID] = trustLine(obs, confirmations[obs.ID], now)
+		}
+	}
+	return lines
+}
+
+// trustLine summarizes how far a memory can be trusted, e.g. "captured 3d
+// ago, confirmed twice, score 0.81".
+func trustLine(obs *models.Observation, confirmations int, now time.Time) string {
+	var parts []string
+	if obs.CreatedAtEpoch > 0 {
+		parts = append(parts, "captured "+formatAge(now.Sub(time.UnixMilli(obs.CreatedAtEpoch))))
+	}
+	switch confirmations {
+	case 0:
+		parts = append(parts, "unconfirmed")
+	case 1:
+		parts = append(parts, "confirmed once")
+	case 2:
+		parts = append(parts, "confirmed twice")
+	default:
+		parts = append(parts, fmt.Sprintf("confirmed %d times", confirmations))
+	}
+	parts = append(parts, fmt.Sprintf("score %.2f", trustScore(obs, confirmations, now)))
+	return strings.Join(parts, ", ")
+}
+
+// trustScore rates a memory from 0 to 1. It decays with age at the
+// half-life of the memory's source (models.DefaultSourceHalfLives), and each
+// confirmation halves the decay, so a memory confirmed twice keeps three
+// quarters of what it lost to age.
+func trustScore(obs *models.Observation, confirmations int, now time.Time) float64 {
+	recency := 1.0
+	if obs.CreatedAtEpoch > 0 {
+		halfLife, ok := models.DefaultSourceHalfLives[obs.SourceType]
+		if !ok {
+			halfLife = models.DefaultSourceHalfLives[models.SourceUnknown]
+		}
+		ageDays := max(0, now.Sub(time.UnixMilli(obs.CreatedAtEpoch)).Hours()/24)
+		recency = math.Pow(0.5, ageDays/halfLife)
+	}
+	return 1 - (1-recency)*math.Pow(0.5, float64(confirmations))
+}
+
+// formatAge renders an age compactly: "today", "3d ago", "5mo ago", "2y ago".
+func formatAge(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	switch {
+	case days < 1:
+		return "today"
+	case days < 60:
+		return fmt.Sprintf("%dd ago", days)
+	case days < 365:
+		return fmt.Sprintf("%dmo ago", days/30)
+	default:
+		return fmt.Sprintf("%dy ago", days/365)
+	}
+}
@@ -0,0 +1,146 @@
+package worker
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultDrainTimeout is how long POST /api/admin/drain waits for queued
+	// work to finish when no timeout is given.
+	defaultDrainTimeout = 30 * time.Second
+	// maxDrainTimeout caps the timeout parameter of POST /api/admin/drain.
+	maxDrainTimeout = 5 * time.Minute
+	// drainPollInterval is how often draining re-dispatches queued messages
+	// and checks whether the worker is idle.
+	drainPollInterval = 200 * time.Millisecond
+)
+
+// drainStatus reports the drain state of the worker: "active" when not
+// draining, "draining" while queued work remains and "drained" once it is
+// safe to restart.
+func (s *Service) drainStatus() map[string]any {
+	isProcessing, queueDepth := s.processingState()
+	status := "active"
+	if s.draining.Load() {
+		status = "draining"
+		if !isProcessing && queueDepth == 0 {
+			status = "drained"
+		}
+	}
+	return map[string]any{
+		"status":       status,
+		"draining":     s.draining.Load(),
+		"isProcessing": isProcessing,
+		"queueDepth":   queueDepth,
+	}
+}
+
+// drain stops intake of new observations and processes everything already
+// queued, summaries included, until the worker is idle or ctx ends. It
+// reports whether the worker drained.
+func (s *Service) drain(ctx context.Context) bool {
+	s.draining.Store(true)
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		// Dispatch messages that were queued since the last pass instead of
+		// waiting for the next processing tick.
+		s.processAllSessions()
+		if isProcessing, queueDepth := s.processingState(); !isProcessing && queueDepth == 0 {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// handleDrain godoc
+// @Summary Drain the worker
+// @Description Stops accepting new observations, processes everything already queued (summaries included) and reports whether the worker drained within the timeout. Intended for upgrade scripts: restart the worker once status is "drained". Observations are refused with 503 until the worker restarts or draining is cancelled.
+// @Tags System
+// @Produce json
+// @Security ApiKeyAuth
+// @Param timeout query string false "How long to wait for queued work, e.g. 30s (default 30s, max 5m)"
+// @Success 200 {object} map[string]interface{} "drained"
+// @Success 202 {object} map[string]interface{} "still draining after the timeout"
+// @Failure 400 {string} string "bad request"
+// @Failure 403 {string} string "admin access required"
+// @Failure 503 {string} string "service initializing"
+// @Router /api/admin/drain [post]
+func (s *Service) handleDrain(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminIdentity(w, r) {
+		return
+	}
+	if !s.ready.Load() {
+		http.Error(w, "service initializing", http.StatusServiceUnavailable)
+		return
+	}
+	timeout := defaultDrainTimeout
+	if v := r.URL.Query().Get("timeout"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			http.Error(w, "invalid timeout", http.StatusBadRequest)
+			return
+		}
+		timeout = min(d, maxDrainTimeout)
+	}
+
+	if !s.draining.Load() {
+		log.Info().Dur("timeout", timeout).Msg("Draining worker: new observations are refused")
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	drained := s.drain(ctx)
+	if drained {
+		log.Info().Msg("Worker drained")
+	}
+
+	resp := s.drainStatus()
+	if !drained {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+	}
+	writeJSON(w, resp)
+}
+
+// handleDrainStatus godoc
+// @Summary Drain status
+// @Description Returns the drain state of the worker ("active", "draining" or "drained") and the queued work left, without waiting.
+// @Tags System
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {string} string "admin access required"
+// @Router /api/admin/drain [get]
+func (s *Service) handleDrainStatus(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminIdentity(w, r) {
+		return
+	}
+	writeJSON(w, s.drainStatus())
+}
+
+// handleCancelDrain godoc
+// @Summary Cancel draining
+// @Description Accepts observations again, e.g. after an aborted upgrade.
+// @Tags System
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {string} string "admin access required"
+// @Router /api/admin/drain [delete]
+func (s *Service) handleCancelDrain(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminIdentity(w, r) {
+		return
+	}
+	if s.draining.Swap(false) {
+		log.Info().Msg("Draining cancelled: accepting observations again")
+	}
+	writeJSON(w, s.drainStatus())
+}
@@ -0,0 +1,99 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/worker/pool"
+	"github.com/thebtf/engram/internal/worker/session"
+	"github.com/thebtf/engram/internal/worker/sse"
+)
+
+func newDrainTestService(t *testing.T) *Service {
+	t.Helper()
+	manager := session.NewManager(nil)
+	processingPool := pool.New(pool.Options{Workers: 1})
+	t.Cleanup(func() {
+		manager.ShutdownAll(context.Background())
+		_ = processingPool.Close(context.Background())
+	})
+	svc := &Service{
+		sessionManager: manager,
+		processingPool: processingPool,
+		sseBroadcaster: sse.NewBroadcaster(),
+	}
+	svc.ready.Store(true)
+	return svc
+}
+
+func decodeDrainStatus(t *testing.T, rec *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp
+}
+
+// TestHandleDrain_WaitsForQueuedWork checks that draining reports "draining"
+// while a task is running and "drained" once it has finished.
+func TestHandleDrain_WaitsForQueuedWork(t *testing.T) {
+	svc := newDrainTestService(t)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	require.NoError(t, svc.processingPool.Submit(context.Background(), 1, func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}))
+	<-started
+
+	rec := httptest.NewRecorder()
+	svc.handleDrain(rec, httptest.NewRequest(http.MethodPost, "/api/admin/drain?timeout=50ms", nil))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, "draining", decodeDrainStatus(t, rec)["status"])
+
+	close(release)
+	rec = httptest.NewRecorder()
+	svc.handleDrain(rec, httptest.NewRequest(http.MethodPost, "/api/admin/drain?timeout=5s", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "drained", decodeDrainStatus(t, rec)["status"])
+
+	rec = httptest.NewRecorder()
+	svc.handleDrainStatus(rec, httptest.NewRequest(http.MethodGet, "/api/admin/drain", nil))
+	assert.Equal(t, "drained", decodeDrainStatus(t, rec)["status"])
+}
+
+// TestHandleDrain_RefusesObservations checks that observations are refused
+// while draining and accepted again once draining is cancelled.
+func TestHandleDrain_RefusesObservations(t *testing.T) {
+	svc := newDrainTestService(t)
+
+	rec := httptest.NewRecorder()
+	svc.handleDrain(rec, httptest.NewRequest(http.MethodPost, "/api/admin/drain", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	body := strings.NewReader(`{"claudeSessionId":"drain-sess","tool_name":"Read"}`)
+	svc.handleObservation(rec, httptest.NewRequest(http.MethodPost, "/api/sessions/observations", body))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	rec = httptest.NewRecorder()
+	svc.handleCancelDrain(rec, httptest.NewRequest(http.MethodDelete, "/api/admin/drain", nil))
+	assert.Equal(t, "active", decodeDrainStatus(t, rec)["status"])
+	assert.False(t, svc.draining.Load())
+}
+
+func TestHandleDrain_InvalidTimeout(t *testing.T) {
+	svc := newDrainTestService(t)
+	rec := httptest.NewRecorder()
+	svc.handleDrain(rec, httptest.NewRequest(http.MethodPost, "/api/admin/drain?timeout=soon", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.False(t, svc.draining.Load(), "a rejected request does not start draining")
+}
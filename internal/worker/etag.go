@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// pollCacheControl is sent with read endpoints that dashboards and the
+// statusline poll: clients may keep the response but must revalidate it
+// with If-None-Match before every use.
+const pollCacheControl = "private, no-cache"
+
+// statsCacheControl lets clients reuse a /api/stats response for a second
+// before revalidating it.
+const statsCacheControl = "private, max-age=1"
+
+// bodyETag returns the strong ETag of a response body.
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+// versionETag returns a strong ETag derived from parts instead of from a
+// response body, for handlers that can tell whether their data changed
+// without building it. Maps are hashed in sorted key order.
+func versionETag(parts ...any) string {
+	h := sha256.New()
+	for _, part := range parts {
+		fmt.Fprintf(h, "%v\x00", part)
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:12]) + `"`
+}
+
+// notModified sets the ETag and Cache-Control headers and, if the request's
+// If-None-Match holds etag, answers 304 Not Modified and reports true.
+func notModified(w http.ResponseWriter, r *http.Request, etag, cacheControl string) bool {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", cacheControl)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, using the
+// weak comparison RFC 9110 prescribes for If-None-Match.
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeJSONCacheable writes data like writeJSON, with cacheControl and an
+// ETag of the encoded body. A request whose If-None-Match holds that ETag
+// gets 304 Not Modified without a body, so a poller whose data has not
+// changed neither downloads nor decodes it again.
+func writeJSONCacheable(w http.ResponseWriter, r *http.Request, data any, cacheControl string) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(data); err != nil {
+		log.Error().Err(err).Msg("Failed to encode JSON response")
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if notModified(w, r, bodyETag(buf.Bytes()), cacheControl) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(buf.Bytes())
+}
@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJSONCacheable(t *testing.T) {
+	data := map[string]any{"total": 3}
+
+	first := httptest.NewRecorder()
+	writeJSONCacheable(first, httptest.NewRequest(http.MethodGet, "/api/observations", nil), data, pollCacheControl)
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, pollCacheControl, first.Header().Get("Cache-Control"))
+	assert.JSONEq(t, `{"total": 3}`, first.Body.String())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/observations", nil)
+	req.Header.Set("If-None-Match", `"other", W/`+etag)
+	second := httptest.NewRecorder()
+	writeJSONCacheable(second, req, data, pollCacheControl)
+	assert.Equal(t, http.StatusNotModified, second.Code)
+	assert.Empty(t, second.Body.String())
+	assert.Equal(t, etag, second.Header().Get("ETag"))
+
+	changed := httptest.NewRecorder()
+	writeJSONCacheable(changed, req, map[string]any{"total": 4}, pollCacheControl)
+	assert.Equal(t, http.StatusOK, changed.Code)
+	assert.NotEqual(t, etag, changed.Header().Get("ETag"))
+}
+
+func TestETagMatches(t *testing.T) {
+	assert.False(t, etagMatches("", `"a"`))
+	assert.True(t, etagMatches(`"a"`, `"a"`))
+	assert.True(t, etagMatches(`W/"a"`, `"a"`))
+	assert.True(t, etagMatches(`"b", "a"`, `"a"`))
+	assert.True(t, etagMatches("*", `"a"`))
+	assert.False(t, etagMatches(`"ab"`, `"a"`))
+}
+
+func TestVersionETag(t *testing.T) {
+	a := versionETag("t1", map[string]any{"b": 2, "a": 1})
+	assert.Equal(t, a, versionETag("t1", map[string]any{"a": 1, "b": 2}))
+	assert.NotEqual(t, a, versionETag("t2", map[string]any{"a": 1, "b": 2}))
+	assert.NotEqual(t, a, versionETag("t1", map[string]any{"a": 1, "b": 3}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/stats", nil)
+	req.Header.Set("If-None-Match", a)
+	rec := httptest.NewRecorder()
+	assert.True(t, notModified(rec, req, a, statsCacheControl))
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Equal(t, a, rec.Header().Get("ETag"))
+	assert.False(t, notModified(httptest.NewRecorder(), req, versionETag("t2"), statsCacheControl))
+}
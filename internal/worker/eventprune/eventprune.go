@@ -0,0 +1,163 @@
+// Package eventprune provides a periodic job that keeps the high-volume event
+// logs within a retention window, so they do not grow without bound as more
+// events are captured.
+//
+// Tool events themselves are not persisted in v5 (raw_events was dropped in
+// migration 101; they are queued in memory and processed into memories). The
+// tables that do grow with every request are:
+//   - retrieval_stats_log: rolled up into per-day totals (retrieval_stats_daily)
+//     before deletion, so all-time retrieval stats are preserved
+//   - search_query_log: deleted; search analytics, recent queries and prompt
+//     history cover the retention window only
+//
+// The retention window is ENGRAM_EVENT_RETENTION_DAYS (default 90, 0 keeps
+// every event).
+package eventprune
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/thebtf/engram/internal/db/gorm"
+)
+
+const (
+	// defaultRetentionDays is how long raw events are kept.
+	defaultRetentionDays = 90
+
+	// pruneInterval is how often the pruning sweep runs.
+	pruneInterval = 1 * time.Hour
+)
+
+// Stats summarizes one pruning sweep.
+type Stats struct {
+	Cutoff               time.Time `json:"cutoff"`
+	RetrievalRolledUp    int64     `json:"retrieval_events_rolled_up"`
+	SearchQueriesDeleted int64     `json:"search_queries_deleted"`
+}
+
+// Pruner periodically prunes the event logs.
+type Pruner struct {
+	retrieval *gorm.RetrievalStatsLogStore
+	queries   *gorm.SearchQueryLogStore
+	mu        sync.Mutex // serializes sweeps
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// New creates a Pruner for the given stores; either may be nil.
+func New(retrieval *gorm.RetrievalStatsLogStore, queries *gorm.SearchQueryLogStore) *Pruner {
+	return &Pruner{
+		retrieval: retrieval,
+		queries:   queries,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start launches the pruning loop in a background goroutine. It respects ctx
+// for graceful shutdown and also responds to Stop(). Returns immediately.
+// A retention of 0 disables the loop.
+func (p *Pruner) Start(ctx context.Context) {
+	if retentionDays() == 0 {
+		close(p.done)
+		log.Info().Msg("event pruning disabled")
+		return
+	}
+	log.Info().
+		Dur("interval", pruneInterval).
+		Int("retention_days", retentionDays()).
+		Msg("event pruning started")
+
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(pruneInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				stats, err := p.RunOnce(ctx)
+				if err != nil {
+					log.Error().Err(err).Msg("event pruning: sweep failed")
+					continue
+				}
+				if stats.RetrievalRolledUp > 0 || stats.SearchQueriesDeleted > 0 {
+					log.Info().
+						Int64("retrieval_rolled_up", stats.RetrievalRolledUp).
+						Int64("search_queries_deleted", stats.SearchQueriesDeleted).
+						Time("cutoff", stats.Cutoff).
+						Msg("event pruning: pruned expired events")
+				}
+			}
+		}
+	}()
+}
+
+// Stop signals the loop to cease and waits for the goroutine to exit.
+func (p *Pruner) Stop() {
+	select {
+	case <-p.stop:
+		// Already closed — idempotent.
+	default:
+		close(p.stop)
+	}
+	<-p.done
+}
+
+// RunOnce runs a single sweep synchronously. It does nothing when retention
+// is disabled. Concurrent calls are serialized.
+func (p *Pruner) RunOnce(ctx context.Context) (Stats, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	days := retentionDays()
+	if days == 0 {
+		return Stats{}, nil
+	}
+	stats := Stats{Cutoff: cutoff(time.Now(), days)}
+
+	if p.retrieval != nil {
+		n, err := p.retrieval.Rollup(ctx, stats.Cutoff)
+		if err != nil {
+			return stats, err
+		}
+		stats.RetrievalRolledUp = n
+	}
+	if p.queries != nil {
+		n, err := p.queries.Cleanup(ctx, time.Since(stats.Cutoff))
+		if err != nil {
+			return stats, fmt.Errorf("prune search queries: %w", err)
+		}
+		stats.SearchQueriesDeleted = n
+	}
+	return stats, nil
+}
+
+// cutoff returns the start of the UTC day days before now. Pruning whole days
+// keeps each rolled-up day complete.
+func cutoff(now time.Time, days int) time.Time {
+	return now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -days)
+}
+
+// retentionDays returns the configured retention window in days.
+// Reads ENGRAM_EVENT_RETENTION_DAYS; falls back to defaultRetentionDays.
+func retentionDays() int {
+	if v := os.Getenv("ENGRAM_EVENT_RETENTION_DAYS"); v != "" {
+		if days, err := strconv.Atoi(v); err == nil && days >= 0 {
+			return days
+		}
+	}
+	return defaultRetentionDays
+}
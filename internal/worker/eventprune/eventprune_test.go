@@ -0,0 +1,41 @@
+package eventprune
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCutoff(t *testing.T) {
+	now := time.Date(2026, 3, 15, 17, 42, 0, 0, time.FixedZone("CET", 3600))
+	assert.Equal(t, time.Date(2026, 2, 13, 0, 0, 0, 0, time.UTC), cutoff(now, 30))
+}
+
+func TestRetentionDays(t *testing.T) {
+	t.Setenv("ENGRAM_EVENT_RETENTION_DAYS", "")
+	assert.Equal(t, defaultRetentionDays, retentionDays())
+
+	t.Setenv("ENGRAM_EVENT_RETENTION_DAYS", "7")
+	assert.Equal(t, 7, retentionDays())
+
+	t.Setenv("ENGRAM_EVENT_RETENTION_DAYS", "0")
+	assert.Equal(t, 0, retentionDays())
+
+	t.Setenv("ENGRAM_EVENT_RETENTION_DAYS", "-3")
+	assert.Equal(t, defaultRetentionDays, retentionDays())
+}
+
+func TestRunOnce_Disabled(t *testing.T) {
+	t.Setenv("ENGRAM_EVENT_RETENTION_DAYS", "0")
+
+	p := New(nil, nil)
+	stats, err := p.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.True(t, stats.Cutoff.IsZero())
+
+	p.Start(context.Background())
+	p.Stop()
+}
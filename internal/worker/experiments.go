@@ -0,0 +1,154 @@
+package worker
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/thebtf/engram/internal/config"
+	"github.com/thebtf/engram/internal/db/gorm"
+)
+
+// experimentArmReport is one arm of the experiment report: the raw counts
+// plus the per-session rates they are compared by.
+type experimentArmReport struct {
+	gorm.ExperimentArmStats
+	// Settings of the arm when the experiment is the configured one.
+	Settings *config.ExperimentArm `json:"settings,omitempty"`
+	// FollowUpSearchesPerSession is the mean number of context searches a
+	// session made after its context was injected.
+	FollowUpSearchesPerSession float64 `json:"follow_up_searches_per_session"`
+	// NegativeFeedbackRate is the share of memory ratings that were negative.
+	NegativeFeedbackRate float64 `json:"negative_feedback_rate"`
+	// SuccessRate is the share of sessions with a recorded outcome that
+	// succeeded.
+	SuccessRate float64 `json:"success_rate"`
+}
+
+// injectionArm returns the strategy name ("experiment/arm") and arm of the
+// configured injection experiment for sessionID. With record set, the
+// assignment is stored for the report, asynchronously. ok is false when no
+// valid experiment is configured or the session is unknown.
+func (s *Service) injectionArm(sessionID string, record bool) (strategy string, arm config.ExperimentArm, ok bool) {
+	cfg := s.currentConfig()
+	if cfg == nil || !cfg.InjectionExperiment.Enabled() || sessionID == "" {
+		return "", config.ExperimentArm{}, false
+	}
+	exp := cfg.InjectionExperiment
+	if err := exp.Validate(); err != nil {
+		log.Debug().Err(err).Msg("Injection experiment misconfigured; using defaults")
+		return "", config.ExperimentArm{}, false
+	}
+	arm, ok = exp.ArmFor(sessionID)
+	if !ok {
+		return "", config.ExperimentArm{}, false
+	}
+	strategy = exp.Name + "/" + arm.Name
+	if record {
+		experimentStore := s.experimentStore
+		sessionStore := s.sessionStore
+		go func() {
+			ctx := context.Background()
+			if experimentStore != nil {
+				if err := experimentStore.Assign(ctx, exp.Name, arm.Name, sessionID); err != nil {
+					log.Debug().Err(err).Str("session_id", sessionID).Msg("Failed to record experiment assignment")
+				}
+			}
+			if sessionStore != nil {
+				_ = sessionStore.UpdateInjectionStrategy(ctx, sessionID, strategy)
+			}
+		}()
+	}
+	return strategy, arm, true
+}
+
+// recordExperimentEvent appends an outcome event for sessionID to the
+// experiment log, asynchronously. Sessions outside any experiment record
+// nothing.
+func (s *Service) recordExperimentEvent(sessionID, event string) {
+	experimentStore := s.experimentStore
+	if experimentStore == nil || sessionID == "" {
+		return
+	}
+	go func() {
+		if _, err := experimentStore.RecordEvent(context.Background(), sessionID, event); err != nil {
+			log.Debug().Err(err).Str("session_id", sessionID).Str("event", event).Msg("Failed to record experiment event")
+		}
+	}()
+}
+
+// buildExperimentReport derives the per-session rates of every arm and
+// attaches the settings of arms of the configured experiment.
+func buildExperimentReport(stats []gorm.ExperimentArmStats, arms []config.ExperimentArm) []experimentArmReport {
+	settings := make(map[string]config.ExperimentArm, len(arms))
+	for _, arm := range arms {
+		settings[arm.Name] = arm
+	}
+	out := make([]experimentArmReport, len(stats))
+	for i, st := range stats {
+		r := experimentArmReport{ExperimentArmStats: st}
+		if arm, ok := settings[st.Arm]; ok {
+			r.Settings = &arm
+		}
+		if st.Sessions > 0 {
+			r.FollowUpSearchesPerSession = float64(st.FollowUpSearches) / float64(st.Sessions)
+		}
+		if rated := st.NegativeFeedback + st.PositiveFeedback; rated > 0 {
+			r.NegativeFeedbackRate = float64(st.NegativeFeedback) / float64(rated)
+		}
+		if st.SessionsWithOutcome > 0 {
+			r.SuccessRate = float64(st.SuccessfulSessions) / float64(st.SessionsWithOutcome)
+		}
+		out[i] = r
+	}
+	return out
+}
+
+// handleExperimentReport godoc
+// @Summary Injection experiment report
+// @Description Returns the outcome metrics per arm of an injection experiment: sessions, follow-up searches, memory ratings and session outcomes. Defaults to the configured experiment (ENGRAM_INJECTION_EXPERIMENT).
+// @Tags Context
+// @Produce json
+// @Security ApiKeyAuth
+// @Param experiment query string false "Experiment name (default: the configured one)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {string} string "bad request"
+// @Router /api/experiments/report [get]
+func (s *Service) handleExperimentReport(w http.ResponseWriter, r *http.Request) {
+	if s.experimentStore == nil {
+		http.Error(w, "experiment store not available", http.StatusServiceUnavailable)
+		return
+	}
+	configured := s.currentConfig().InjectionExperiment
+	name := r.URL.Query().Get("experiment")
+	if name == "" {
+		name = configured.Name
+	}
+	if name == "" {
+		http.Error(w, "experiment required: none configured", http.StatusBadRequest)
+		return
+	}
+
+	stats, err := s.experimentStore.Report(r.Context(), name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	active := name == configured.Name
+	var arms []config.ExperimentArm
+	if active {
+		arms = configured.Arms
+	}
+	resp := map[string]any{
+		"experiment": name,
+		"active":     active,
+		"arms":       buildExperimentReport(stats, arms),
+	}
+	if active {
+		if err := configured.Validate(); err != nil {
+			resp["config_error"] = err.Error()
+		}
+	}
+	writeJSON(w, resp)
+}
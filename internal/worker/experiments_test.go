@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/config"
+	"github.com/thebtf/engram/internal/db/gorm"
+)
+
+func TestInjectionArm(t *testing.T) {
+	svc := newInjectTestService(true)
+
+	_, _, ok := svc.injectionArm("sess-1", false)
+	assert.False(t, ok, "no experiment configured")
+
+	svc.config.InjectionExperiment = config.InjectionExperiment{
+		Name: "limits",
+		Arms: []config.ExperimentArm{{Name: "five", MaxResults: 5}, {Name: "fifteen", MaxResults: 15}},
+	}
+	strategy, arm, ok := svc.injectionArm("sess-1", false)
+	require.True(t, ok)
+	assert.Equal(t, "limits/"+arm.Name, strategy)
+	want, _ := svc.config.InjectionExperiment.ArmFor("sess-1")
+	assert.Equal(t, want, arm)
+
+	_, _, ok = svc.injectionArm("", false)
+	assert.False(t, ok, "sessions without an ID are not assigned")
+
+	svc.config.InjectionExperiment.Arms = svc.config.InjectionExperiment.Arms[:1]
+	_, _, ok = svc.injectionArm("sess-1", false)
+	assert.False(t, ok, "a misconfigured experiment falls back to the defaults")
+}
+
+func TestBuildExperimentReport(t *testing.T) {
+	arms := []config.ExperimentArm{{Name: "a", MaxResults: 5}}
+	report := buildExperimentReport([]gorm.ExperimentArmStats{
+		{Arm: "a", Sessions: 4, FollowUpSearches: 6, NegativeFeedback: 1, PositiveFeedback: 3, SessionsWithOutcome: 2, SuccessfulSessions: 1},
+		{Arm: "b"},
+	}, arms)
+	require.Len(t, report, 2)
+
+	assert.Equal(t, 1.5, report[0].FollowUpSearchesPerSession)
+	assert.Equal(t, 0.25, report[0].NegativeFeedbackRate)
+	assert.Equal(t, 0.5, report[0].SuccessRate)
+	require.NotNil(t, report[0].Settings)
+	assert.Equal(t, 5, report[0].Settings.MaxResults)
+
+	assert.Zero(t, report[1].FollowUpSearchesPerSession, "no sessions, no division by zero")
+	assert.Nil(t, report[1].Settings, "arms no longer configured carry no settings")
+}
@@ -0,0 +1,115 @@
+package worker
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/thebtf/engram/internal/worker/relinfer"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+const (
+	// fileBoostWeight is the share of the blended score that comes from
+	// mentioning a file the user is working on. As with concept boosting, a
+	// text match always outranks a file-only match.
+	fileBoostWeight = 0.3
+
+	// maxWorkingFiles caps the working files one request may send.
+	maxWorkingFiles = 50
+)
+
+// cleanWorkingFiles normalizes the files a client reports as open or
+// recently edited: trims them, converts Windows separators, drops empties
+// and duplicates, and keeps at most maxWorkingFiles.
+func cleanWorkingFiles(files []string) []string {
+	seen := make(map[string]bool, len(files))
+	out := make([]string, 0, len(files))
+	for _, f := range files {
+		f = strings.TrimSpace(strings.ReplaceAll(f, `\`, "/"))
+		if f == "" {
+			continue
+		}
+		f = path.Clean(f)
+		if seen[f] {
+			continue
+		}
+		seen[f] = true
+		out = append(out, f)
+		if len(out) == maxWorkingFiles {
+			break
+		}
+	}
+	return out
+}
+
+// observationFiles returns the files an observation mentions: its
+// files_read and files_modified, file: tags and paths in the narrative.
+func observationFiles(obs *models.Observation) map[string]bool {
+	files := relinfer.MentionedFiles(obs.Concepts, obs.Narrative.String)
+	for _, f := range obs.FilesRead {
+		files[path.Clean(f)] = true
+	}
+	for _, f := range obs.FilesModified {
+		files[path.Clean(f)] = true
+	}
+	return files
+}
+
+// matchedWorkingFiles returns the working files the observation mentions,
+// in the order given. Relative and absolute forms of a path match.
+func matchedWorkingFiles(obs *models.Observation, working []string) []string {
+	if len(working) == 0 {
+		return nil
+	}
+	mentioned := observationFiles(obs)
+	var matched []string
+	for _, w := range working {
+		for f := range mentioned {
+			if relinfer.SamePath(f, w) {
+				matched = append(matched, w)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// applyFileBoost blends the ranking of matched with whether each
+// observation mentions a working file. An observation in matched keeps
+// (1-fileBoostWeight) of its score (its entry in scores, 1 when absent) and
+// gains fileBoostWeight when it mentions a working file; candidates that
+// mention one are added with fileBoostWeight. The result is ordered by
+// blended score, keeping the incoming order for ties.
+func applyFileBoost(matched, candidates []*models.Observation, working []string, scores map[int64]float64) ([]*models.Observation, map[int64]float64) {
+	blended := make(map[int64]float64, len(matched))
+	out := make([]*models.Observation, 0, len(matched))
+	for _, obs := range matched {
+		if _, seen := blended[obs.ID]; seen {
+			continue
+		}
+		base, ok := scores[obs.ID]
+		if !ok {
+			base = 1
+		}
+		score := (1 - fileBoostWeight) * base
+		if len(matchedWorkingFiles(obs, working)) > 0 {
+			score += fileBoostWeight
+		}
+		blended[obs.ID] = score
+		out = append(out, obs)
+	}
+	for _, obs := range candidates {
+		if _, seen := blended[obs.ID]; seen {
+			continue
+		}
+		if len(matchedWorkingFiles(obs, working)) > 0 {
+			blended[obs.ID] = fileBoostWeight
+			out = append(out, obs)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return blended[out[i].ID] > blended[out[j].ID]
+	})
+	return out, blended
+}
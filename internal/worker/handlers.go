@@ -9,8 +9,10 @@
 package worker
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/rs/zerolog/log"
 )
@@ -132,14 +134,15 @@ func (s *Service) handleHealth(w http.ResponseWriter, r *http.Request) {
 
 // handleVersion godoc
 // @Summary Get worker version
-// @Description Returns the worker version string for version checking.
+// @Description Returns the worker version string and the oldest plugin version whose hooks it supports.
 // @Tags System
 // @Produce json
 // @Success 200 {object} map[string]string
 // @Router /api/version [get]
 func (s *Service) handleVersion(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, map[string]string{
-		"version": s.version,
+		"version":          s.version,
+		"min_hook_version": MinHookVersion,
 	})
 }
 
@@ -164,14 +167,40 @@ func (s *Service) handleReady(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, map[string]string{"status": "ready"})
 }
 
-// requireReady is middleware that returns 503 if service isn't ready.
+// readyWaitTimeout is how long a request that arrives during initialization
+// waits for the service to become ready before it is answered with 503. Hooks
+// fired right after the worker starts then succeed instead of failing.
+const readyWaitTimeout = 5 * time.Second
+
+// waitReady blocks until the service is ready, initialization fails, ctx ends
+// or readyWaitTimeout passes, and reports whether the service is ready.
+func (s *Service) waitReady(ctx context.Context) bool {
+	if s.ready.Load() {
+		return true
+	}
+	if s.initDone == nil {
+		return false
+	}
+	timer := time.NewTimer(readyWaitTimeout)
+	defer timer.Stop()
+	select {
+	case <-s.initDone:
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	return s.ready.Load()
+}
+
+// requireReady is middleware that returns 503 if service isn't ready. A
+// request arriving during initialization first waits up to readyWaitTimeout.
 func (s *Service) requireReady(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !s.ready.Load() {
+		if !s.waitReady(r.Context()) {
 			if err := s.GetInitError(); err != nil {
 				http.Error(w, "service initialization failed: "+err.Error(), http.StatusInternalServerError)
 				return
 			}
+			w.Header().Set("Retry-After", "1")
 			http.Error(w, "service initializing", http.StatusServiceUnavailable)
 			return
 		}
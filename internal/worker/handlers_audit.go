@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	authpkg "github.com/thebtf/engram/internal/auth"
+	"github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/pkg/models"
+	"github.com/thebtf/engram/pkg/strutil"
+)
+
+// auditClientHeader names the client behind an HTTP request. The plugin hooks
+// send "hook"; anything else is recorded as "api".
+const auditClientHeader = "X-Engram-Client"
+
+// auditBeforeMaxLen caps the before-state summary stored per audit entry.
+const auditBeforeMaxLen = 500
+
+// auditClient classifies the HTTP caller for the audit log.
+func auditClient(r *http.Request) string {
+	if strings.EqualFold(strings.TrimSpace(r.Header.Get(auditClientHeader)), models.AuditClientHook) {
+		return models.AuditClientHook
+	}
+	return models.AuditClientAPI
+}
+
+// recordAudit appends a destructive operation performed over HTTP to the
+// audit log. It is best-effort: the operation already happened, so a failed
+// write is logged rather than surfaced to the caller.
+func (s *Service) recordAudit(r *http.Request, operation, project, before string, targetIDs ...string) {
+	s.initMu.RLock()
+	store := s.auditLogStore
+	s.initMu.RUnlock()
+	if store == nil {
+		return
+	}
+	entry := &models.AuditEntry{
+		Actor:     authpkg.ActorFrom(r.Context()),
+		Client:    auditClient(r),
+		Operation: operation,
+		Project:   project,
+		Before:    strutil.Truncate(before, auditBeforeMaxLen),
+		TargetIDs: targetIDs,
+	}
+	if err := store.Record(r.Context(), entry); err != nil {
+		log.Warn().Err(err).Str("operation", operation).Strs("targets", targetIDs).Msg("Failed to record audit entry")
+	}
+}
+
+// handleGetAuditLog godoc
+// @Summary Query the audit log
+// @Description Returns destructive operations (deletes, reverts, overwrites) newest first, with the actor, client, affected IDs and a summary of the removed state.
+// @Tags Audit
+// @Produce json
+// @Security ApiKeyAuth
+// @Param operation query string false "Filter by operation, e.g. memory.delete"
+// @Param project query string false "Filter by project"
+// @Param actor query string false "Filter by actor, e.g. operator or keycard:<id>"
+// @Param since query string false "Only entries at or after this RFC 3339 timestamp"
+// @Param limit query int false "Maximum entries (default 50, max 1000)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {string} string "invalid since"
+// @Failure 403 {string} string "admin access required"
+// @Failure 503 {string} string "audit log not available"
+// @Router /api/audit [get]
+func (s *Service) handleGetAuditLog(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminIdentity(w, r) {
+		return
+	}
+	s.initMu.RLock()
+	store := s.auditLogStore
+	s.initMu.RUnlock()
+	if store == nil {
+		http.Error(w, "audit log not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	q := r.URL.Query()
+	filter := models.AuditFilter{
+		Operation: q.Get("operation"),
+		Project:   q.Get("project"),
+		Actor:     q.Get("actor"),
+		Limit:     gorm.ParseLimitParamWithMax(r, 50, gorm.MaxPaginationLimit),
+	}
+	if since := q.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			http.Error(w, "since must be an RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		filter.Since = t
+	}
+
+	entries, err := store.List(r.Context(), filter)
+	if err != nil {
+		log.Error().Err(err).Msg("list audit log failed")
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]any{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
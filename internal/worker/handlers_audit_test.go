@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	authpkg "github.com/thebtf/engram/internal/auth"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestAuditClient(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodDelete, "/api/memories/1", nil)
+	assert.Equal(t, models.AuditClientAPI, auditClient(r))
+
+	r.Header.Set(auditClientHeader, "Hook")
+	assert.Equal(t, models.AuditClientHook, auditClient(r))
+
+	r.Header.Set(auditClientHeader, "dashboard")
+	assert.Equal(t, models.AuditClientAPI, auditClient(r), "unknown clients are recorded as api")
+}
+
+func TestHandleGetAuditLog_Guards(t *testing.T) {
+	t.Parallel()
+	s := &Service{}
+
+	// Tenant-scoped keycards are rejected before the store is consulted.
+	r := httptest.NewRequest(http.MethodGet, "/api/audit", nil)
+	r = r.WithContext(authpkg.WithIdentity(context.Background(), authpkg.Client("read-write", "uuid-1")))
+	w := httptest.NewRecorder()
+	s.handleGetAuditLog(w, r)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	s.handleGetAuditLog(w, httptest.NewRequest(http.MethodGet, "/api/audit", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestRecordAudit_NoStoreIsNoop(t *testing.T) {
+	t.Parallel()
+	s := &Service{}
+	// Must not panic when the database is not ready yet.
+	s.recordAudit(httptest.NewRequest(http.MethodDelete, "/api/memories/1", nil), models.AuditOpMemoryDelete, "p", "before", "1")
+}
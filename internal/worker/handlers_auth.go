@@ -21,6 +21,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	authpkg "github.com/thebtf/engram/internal/auth"
+	"github.com/thebtf/engram/internal/tenant"
 )
 
 // isAuthDisabled returns true when ENGRAM_AUTH_DISABLED env var is "true" or "1".
@@ -59,6 +60,8 @@ type loginRequest struct {
 type tokenCreateRequest struct {
 	Name  string `json:"name"`
 	Scope string `json:"scope"`
+	// Tenant isolates everything the token reads and writes. Empty = default tenant.
+	Tenant string `json:"tenant"`
 }
 
 // handleAuthLogin godoc
@@ -254,6 +257,7 @@ func (s *Service) handleListTokens(w http.ResponseWriter, r *http.Request) {
 		Name         string     `json:"name"`
 		TokenPrefix  string     `json:"token_prefix"`
 		Scope        string     `json:"scope"`
+		Tenant       string     `json:"tenant"`
 		CreatedAt    time.Time  `json:"created_at"`
 		LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
 		RequestCount int64      `json:"request_count"`
@@ -269,6 +273,7 @@ func (s *Service) handleListTokens(w http.ResponseWriter, r *http.Request) {
 			Name:         t.Name,
 			TokenPrefix:  t.TokenPrefix,
 			Scope:        t.Scope,
+			Tenant:       t.Tenant,
 			CreatedAt:    t.CreatedAt,
 			LastUsedAt:   t.LastUsedAt,
 			RequestCount: t.RequestCount,
@@ -312,7 +317,7 @@ func (s *Service) requireSessionAdmin(w http.ResponseWriter, r *http.Request) bo
 
 // handleCreateToken godoc
 // @Summary Create a new API token
-// @Description Generates a new client API token with the specified name and scope. The raw token is returned only once.
+// @Description Generates a new client API token with the specified name, scope and optional tenant. The raw token is returned only once. All data read or written with the token is isolated to its tenant.
 // @Tags Auth
 // @Accept json
 // @Produce json
@@ -358,6 +363,11 @@ func (s *Service) handleCreateToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := tenant.Validate(req.Tenant); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Generate raw token: eng_ + 32 hex chars (16 random bytes)
 	randomBytes := make([]byte, 16)
 	if _, err := rand.Read(randomBytes); err != nil {
@@ -374,7 +384,7 @@ func (s *Service) handleCreateToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := tokenStore.Create(r.Context(), req.Name, string(hash), prefix, scope)
+	token, err := tokenStore.Create(r.Context(), req.Name, string(hash), prefix, scope, req.Tenant)
 	if err != nil {
 		// Check for unique constraint violation (duplicate name)
 		if isDuplicateKeyError(err) {
@@ -387,10 +397,11 @@ func (s *Service) handleCreateToken(w http.ResponseWriter, r *http.Request) {
 	}
 
 	writeJSON(w, map[string]any{
-		"id":    token.ID,
-		"name":  token.Name,
-		"token": rawToken,
-		"scope": token.Scope,
+		"id":     token.ID,
+		"name":   token.Name,
+		"token":  rawToken,
+		"scope":  token.Scope,
+		"tenant": token.Tenant,
 	})
 }
 
@@ -488,6 +499,45 @@ func (s *Service) handleGetTokenStats(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleListTenants godoc
+// @Summary List tenants with usage stats
+// @Description Returns per-tenant counts of memories, behavioral rules, credentials and active tokens. The default tenant is reported as "". Admin only.
+// @Tags Auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {string} string "admin access required"
+// @Failure 500 {string} string "internal error"
+// @Router /api/tenants [get]
+func (s *Service) handleListTenants(w http.ResponseWriter, r *http.Request) {
+	// Keycards are tenant-scoped and must not see other tenants. No identity
+	// means auth is disabled (single-user), which is allowed.
+	if id, ok := authpkg.IdentityFrom(r.Context()); ok && !id.IsAdmin() {
+		http.Error(w, "admin access required", http.StatusForbidden)
+		return
+	}
+
+	s.initMu.RLock()
+	tenantStore := s.tenantStore
+	s.initMu.RUnlock()
+
+	if tenantStore == nil {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+
+	stats, err := tenantStore.Stats(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("auth: failed to compute tenant stats")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"tenants": stats,
+	})
+}
+
 // authRoleKey is the context key for the authenticated role.
 type authRoleKey struct{}
 
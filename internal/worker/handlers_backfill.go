@@ -161,8 +161,8 @@ func (s *Service) handleBackfillSession(w http.ResponseWriter, r *http.Request)
 		}()
 	}
 	for i := range sess.Exchanges {
-		sess.Exchanges[i].UserText = privacy.RedactSecrets(sess.Exchanges[i].UserText)
-		sess.Exchanges[i].AssistantText = privacy.RedactSecrets(sess.Exchanges[i].AssistantText)
+		sess.Exchanges[i].UserText = privacy.RedactForStorage(project, sess.Exchanges[i].UserText)
+		sess.Exchanges[i].AssistantText = privacy.RedactForStorage(project, sess.Exchanges[i].AssistantText)
 	}
 
 	sdkSessionID := fmt.Sprintf("backfill-%s-%s", req.RunID, req.SessionID)
@@ -0,0 +1,145 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/thebtf/engram/internal/worker/backup"
+)
+
+// currentBackuper returns the backup job, or writes 503 and returns nil while
+// the service is initializing.
+func (s *Service) currentBackuper(w http.ResponseWriter) *backup.Backuper {
+	s.initMu.RLock()
+	b := s.backuper
+	s.initMu.RUnlock()
+	if b == nil {
+		http.Error(w, "service initializing", http.StatusServiceUnavailable)
+	}
+	return b
+}
+
+// writeBackupError maps backup errors to HTTP statuses.
+func writeBackupError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, backup.ErrDisabled):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+	case errors.Is(err, backup.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, backup.ErrWrongPassphrase):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	}
+}
+
+// handleListBackups godoc
+// @Summary List remote backups
+// @Description Lists the backups stored on the configured backup target (ENGRAM_BACKUP_TARGET), oldest first.
+// @Tags System
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {string} string "admin access required"
+// @Failure 501 {string} string "backups are not configured"
+// @Router /api/admin/backups [get]
+func (s *Service) handleListBackups(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminIdentity(w, r) {
+		return
+	}
+	b := s.currentBackuper(w)
+	if b == nil {
+		return
+	}
+	objects, err := b.List(r.Context())
+	if err != nil {
+		writeBackupError(w, err)
+		return
+	}
+	if objects == nil {
+		objects = []backup.Object{}
+	}
+	writeJSON(w, map[string]any{
+		"target":  b.Target(),
+		"backups": objects,
+		"count":   len(objects),
+	})
+}
+
+// handleRunBackup godoc
+// @Summary Back up now
+// @Description Snapshots the database, uploads it to the backup target (encrypted when ENGRAM_BACKUP_PASSPHRASE is set) and deletes the oldest backups past ENGRAM_BACKUP_RETENTION.
+// @Tags System
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} backup.Result
+// @Failure 403 {string} string "admin access required"
+// @Failure 501 {string} string "backups are not configured"
+// @Failure 502 {string} string "backup failed"
+// @Router /api/admin/backups [post]
+func (s *Service) handleRunBackup(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminIdentity(w, r) {
+		return
+	}
+	b := s.currentBackuper(w)
+	if b == nil {
+		return
+	}
+	res, err := b.RunOnce(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("On-demand backup failed")
+		writeBackupError(w, err)
+		return
+	}
+	log.Info().Str("name", res.Name).Int64("bytes", res.Size).Msg("On-demand backup uploaded")
+	writeJSON(w, res)
+}
+
+// restoreBackupRequest is the body of POST /api/admin/backups/restore.
+type restoreBackupRequest struct {
+	// Name of the backup to restore; "latest" (or empty) for the newest.
+	Name string `json:"name"`
+}
+
+// handleRestoreBackup godoc
+// @Summary Restore from a remote backup
+// @Description Downloads a backup from the backup target and loads it into the database in one transaction. Rows that already exist (same primary key) are kept, so restoring into a fresh server brings everything back and restoring into a live one only adds what is missing.
+// @Tags System
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body restoreBackupRequest false "Backup to restore (default latest)"
+// @Success 200 {object} backup.RestoreResult
+// @Failure 400 {string} string "bad request"
+// @Failure 403 {string} string "admin access required"
+// @Failure 404 {string} string "backup not found"
+// @Failure 422 {string} string "wrong passphrase"
+// @Failure 501 {string} string "backups are not configured"
+// @Router /api/admin/backups/restore [post]
+func (s *Service) handleRestoreBackup(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminIdentity(w, r) {
+		return
+	}
+	var req restoreBackupRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON body", http.StatusBadRequest)
+			return
+		}
+	}
+	b := s.currentBackuper(w)
+	if b == nil {
+		return
+	}
+	res, err := b.Restore(r.Context(), req.Name)
+	if err != nil {
+		log.Error().Err(err).Str("name", req.Name).Msg("Restore from backup failed")
+		writeBackupError(w, err)
+		return
+	}
+	log.Info().Str("name", res.Name).Msg("Restored from backup")
+	writeJSON(w, res)
+}
@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/worker/backup"
+)
+
+func TestHandleBackups_NotConfigured(t *testing.T) {
+	t.Setenv("ENGRAM_BACKUP_TARGET", "")
+	svc := newDrainTestService(t)
+
+	rec := httptest.NewRecorder()
+	svc.handleListBackups(rec, httptest.NewRequest(http.MethodGet, "/api/admin/backups", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "no backuper while initializing")
+
+	b, err := backup.New(nil)
+	require.NoError(t, err)
+	svc.backuper = b
+
+	rec = httptest.NewRecorder()
+	svc.handleRunBackup(rec, httptest.NewRequest(http.MethodPost, "/api/admin/backups", nil))
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+	assert.Contains(t, rec.Body.String(), "ENGRAM_BACKUP_TARGET")
+
+	rec = httptest.NewRecorder()
+	svc.handleRestoreBackup(rec, httptest.NewRequest(http.MethodPost, "/api/admin/backups/restore", strings.NewReader("{")))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleBackups_ListAndRestoreMissing(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("ENGRAM_BACKUP_TARGET", "file://"+filepath.ToSlash(dir))
+	name := "engram-backup-20261016T120000Z.jsonl.gz"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o600))
+
+	b, err := backup.New(nil)
+	require.NoError(t, err)
+	svc := newDrainTestService(t)
+	svc.backuper = b
+
+	rec := httptest.NewRecorder()
+	svc.handleListBackups(rec, httptest.NewRequest(http.MethodGet, "/api/admin/backups", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp struct {
+		Backups []backup.Object `json:"backups"`
+		Count   int             `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, 1, resp.Count)
+	assert.Equal(t, name, resp.Backups[0].Name)
+
+	rec = httptest.NewRecorder()
+	body := strings.NewReader(`{"name":"engram-backup-20200101T000000Z.jsonl.gz"}`)
+	svc.handleRestoreBackup(rec, httptest.NewRequest(http.MethodPost, "/api/admin/backups/restore", body))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,222 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/thebtf/engram/internal/capture"
+	"github.com/thebtf/engram/internal/config"
+	"github.com/thebtf/engram/internal/privacy"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+const (
+	// commitSessionWindow is how recently a session must have started to be
+	// linked to a commit that does not name its session.
+	commitSessionWindow = 12 * time.Hour
+	// maxCommitFileTags caps the file: tags of a commit memory; the content
+	// still lists every changed file.
+	maxCommitFileTags = 20
+)
+
+// capturePolicyTestRequest is the JSON body for POST /api/capture/policy/test.
+// Files are checked together with any paths named in ToolInput.
+type capturePolicyTestRequest struct {
+	ToolInput any      `json:"tool_input,omitempty"`
+	Project   string   `json:"project"`
+	ToolName  string   `json:"tool_name,omitempty"`
+	Files     []string `json:"files,omitempty"`
+	Concepts  []string `json:"concepts,omitempty"`
+}
+
+// capturePolicyTestResponse reports the decision for a test event and the
+// effective rules it was evaluated against.
+type capturePolicyTestResponse struct {
+	Rules    config.CaptureRules `json:"rules"`
+	Event    capture.Event       `json:"event"`
+	Reason   string              `json:"reason,omitempty"`
+	Project  string              `json:"project"`
+	Decision capture.Decision    `json:"decision"`
+}
+
+// handleTestCapturePolicy godoc
+// @Summary Test the capture policy
+// @Description Evaluates a hypothetical event (tool name, files, concepts) against the project's effective capture rules and reports whether it would be captured, and which rule blocks it if not. Nothing is stored.
+// @Tags Capture
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body capturePolicyTestRequest true "Event to evaluate"
+// @Success 200 {object} capturePolicyTestResponse
+// @Failure 400 {string} string "bad request"
+// @Router /api/capture/policy/test [post]
+func (s *Service) handleTestCapturePolicy(w http.ResponseWriter, r *http.Request) {
+	var req capturePolicyTestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	event := capture.Event{
+		ToolName: req.ToolName,
+		Files:    append(req.Files, capture.FilesFromToolInput(req.ToolInput)...),
+		Concepts: req.Concepts,
+	}
+	policy := config.Get().CapturePolicy
+	decision := capture.Evaluate(policy, req.Project, event)
+
+	writeJSON(w, capturePolicyTestResponse{
+		Project:  req.Project,
+		Event:    event,
+		Decision: decision,
+		Reason:   decision.Reason(),
+		Rules:    policy.RulesFor(req.Project),
+	})
+}
+
+// CommitCaptureRequest is the JSON body for POST /api/capture/commit, sent
+// by the post-commit hook that `engram capture --install` sets up.
+type CommitCaptureRequest struct {
+	Project    string   `json:"project"`
+	SHA        string   `json:"sha"`
+	Branch     string   `json:"branch,omitempty"`
+	Message    string   `json:"message"`
+	SessionID  string   `json:"session_id,omitempty"`
+	Files      []string `json:"files,omitempty"`
+	Insertions int      `json:"insertions"`
+	Deletions  int      `json:"deletions"`
+}
+
+// shortSHA returns the abbreviated form of a commit hash.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// commitMemoryContent renders a commit as memory content: the subject line
+// with hash and branch, the message body, and the diff stat with the
+// changed files.
+func commitMemoryContent(req CommitCaptureRequest) string {
+	subject, body, _ := strings.Cut(strings.TrimSpace(req.Message), "\n")
+	var b strings.Builder
+	fmt.Fprintf(&b, "Commit %s", shortSHA(req.SHA))
+	if req.Branch != "" {
+		fmt.Fprintf(&b, " on %s", req.Branch)
+	}
+	fmt.Fprintf(&b, ": %s\n", strings.TrimSpace(subject))
+	if body = strings.TrimSpace(body); body != "" {
+		fmt.Fprintf(&b, "\n%s\n", body)
+	}
+	fmt.Fprintf(&b, "\n%d file(s) changed, +%d -%d", len(req.Files), req.Insertions, req.Deletions)
+	for _, f := range req.Files {
+		fmt.Fprintf(&b, "\n- %s", f)
+	}
+	return b.String()
+}
+
+// commitMemoryTags tags a commit memory as a change with its hash, branch,
+// session and (up to maxCommitFileTags) changed files.
+func commitMemoryTags(req CommitCaptureRequest, sessionID string) []string {
+	tags := []string{"commit", "type:change", "commit:" + shortSHA(req.SHA)}
+	if req.Branch != "" {
+		tags = append(tags, "branch:"+req.Branch)
+	}
+	if sessionID != "" {
+		tags = append(tags, "session:"+sessionID)
+	}
+	for i, f := range req.Files {
+		if i == maxCommitFileTags {
+			break
+		}
+		tags = append(tags, "file:"+f)
+	}
+	return tags
+}
+
+// capturedFiles drops the files the capture policy excludes, so a commit
+// touching .env is recorded without naming it rather than not at all.
+func capturedFiles(policy config.CapturePolicy, project string, files []string) []string {
+	out := make([]string, 0, len(files))
+	for _, f := range files {
+		if capture.Evaluate(policy, project, capture.Event{Files: []string{f}}).Allowed {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// handleCaptureCommit godoc
+// @Summary Record a git commit
+// @Description Stores a commit (message, changed files and diff stat) as a change memory of the project, tagged with its hash, branch and changed files. Files excluded by the capture policy are left out. The memory is linked with a session: tag to the given session, or else to the project's most recent active session started in the last 12 hours.
+// @Tags Capture
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body CommitCaptureRequest true "Commit to record"
+// @Success 201 {object} models.Memory
+// @Failure 400 {string} string "bad request"
+// @Failure 422 {string} string "blocked by capture policy"
+// @Failure 503 {string} string "service unavailable"
+// @Failure 500 {string} string "internal error"
+// @Router /api/capture/commit [post]
+func (s *Service) handleCaptureCommit(w http.ResponseWriter, r *http.Request) {
+	if s.memoryStore == nil {
+		http.Error(w, "memory store not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	var req CommitCaptureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Project == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	if req.SHA == "" || strings.TrimSpace(req.Message) == "" {
+		http.Error(w, "sha and message are required", http.StatusBadRequest)
+		return
+	}
+
+	sessionID := req.SessionID
+	if sessionID == "" && s.sessionStore != nil {
+		since := time.Now().Add(-commitSessionWindow).UnixMilli()
+		sess, err := s.sessionStore.LatestActiveSession(r.Context(), req.Project, since)
+		if err != nil {
+			log.Warn().Err(err).Str("project", req.Project).Msg("find session for commit failed")
+		} else if sess != nil {
+			sessionID = sess.ClaudeSessionID
+		}
+	}
+
+	cfg := config.Get()
+	req.Files = capturedFiles(cfg.CapturePolicy, req.Project, req.Files)
+	tags := commitMemoryTags(req, sessionID)
+	if decision := capture.Evaluate(cfg.CapturePolicy, req.Project, capture.Event{Concepts: tags}); !decision.Allowed {
+		http.Error(w, "blocked by capture policy: "+decision.Reason(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	created, err := s.memoryStore.Create(r.Context(), &models.Memory{
+		Project:     req.Project,
+		Content:     privacy.RedactForStorage(req.Project, commitMemoryContent(req)),
+		Tags:        tags,
+		SourceAgent: "git",
+	})
+	if err != nil {
+		log.Error().Err(err).Str("project", req.Project).Str("sha", req.SHA).Msg("store commit memory failed")
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, created)
+}
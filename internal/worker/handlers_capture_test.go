@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/capture"
+	"github.com/thebtf/engram/internal/config"
+)
+
+func TestHandleTestCapturePolicy(t *testing.T) {
+	s := &Service{}
+
+	body := `{"project":"engram","tool_name":"Read","tool_input":{"file_path":"/repo/.env"}}`
+	w := httptest.NewRecorder()
+	s.handleTestCapturePolicy(w, httptest.NewRequest(http.MethodPost, "/api/capture/policy/test", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp capturePolicyTestResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.False(t, resp.Decision.Allowed, "the built-in rules exclude .env")
+	assert.Equal(t, capture.RuleExcludeFiles, resp.Decision.Rule)
+	assert.Equal(t, []string{"/repo/.env"}, resp.Event.Files)
+	assert.NotEmpty(t, resp.Reason)
+	assert.NotEmpty(t, resp.Rules.ExcludeFiles)
+
+	w = httptest.NewRecorder()
+	s.handleTestCapturePolicy(w, httptest.NewRequest(http.MethodPost, "/api/capture/policy/test", strings.NewReader(`{"project":"engram","files":["main.go"]}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+	var allowed capturePolicyTestResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &allowed))
+	assert.True(t, allowed.Decision.Allowed)
+	assert.Empty(t, allowed.Decision.Rule)
+
+	w = httptest.NewRecorder()
+	s.handleTestCapturePolicy(w, httptest.NewRequest(http.MethodPost, "/api/capture/policy/test", strings.NewReader(`{`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCommitMemory(t *testing.T) {
+	req := CommitCaptureRequest{
+		Project:    "engram",
+		SHA:        "0123456789abcdef",
+		Branch:     "main",
+		Message:    "Fix token refresh\n\nRefresh before expiry instead of after a 401.\n",
+		Files:      []string{"internal/auth/refresh.go", "internal/auth/refresh_test.go"},
+		Insertions: 12,
+		Deletions:  3,
+	}
+	assert.Equal(t, "Commit 0123456 on main: Fix token refresh\n\nRefresh before expiry instead of after a 401.\n\n2 file(s) changed, +12 -3\n- internal/auth/refresh.go\n- internal/auth/refresh_test.go", commitMemoryContent(req))
+	assert.Equal(t, []string{"commit", "type:change", "commit:0123456", "branch:main", "session:s-1", "file:internal/auth/refresh.go", "file:internal/auth/refresh_test.go"}, commitMemoryTags(req, "s-1"))
+
+	req.Branch = ""
+	req.Message = "One-liner"
+	req.Files = nil
+	assert.Equal(t, "Commit 0123456: One-liner\n\n0 file(s) changed, +12 -3", commitMemoryContent(req))
+	assert.Equal(t, []string{"commit", "type:change", "commit:0123456"}, commitMemoryTags(req, ""))
+}
+
+func TestCapturedFiles(t *testing.T) {
+	policy := config.Get().CapturePolicy
+	assert.Equal(t, []string{"main.go"}, capturedFiles(policy, "engram", []string{"main.go", ".env"}))
+}
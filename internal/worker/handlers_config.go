@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/thebtf/engram/internal/config"
+)
+
+// checkSettings validates the settings file and logs each issue with its
+// line and column. In strict mode (ENGRAM_CONFIG_STRICT) errors are returned.
+func checkSettings() error {
+	report, err := config.Check()
+	if err != nil {
+		log.Warn().Err(err).Msg("Could not read settings file for validation")
+		return nil
+	}
+	for _, issue := range report.Issues {
+		event := log.Warn()
+		if issue.Severity == config.SeverityError {
+			event = log.Error()
+		}
+		event.Str("key", issue.Key).Msg("Settings: " + issue.String())
+	}
+	if config.Strict() && report.HasErrors() {
+		return &config.ValidationError{Issues: report.Issues}
+	}
+	return nil
+}
+
+// handleEffectiveConfig godoc
+// @Summary Show the effective configuration
+// @Description Returns every setting the server resolved with its value and source (env, settings or default), the default when the value differs from it, and the problems found in the settings file with line and column. Secrets are shown only as "(set)".
+// @Tags System
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {string} string "admin access required"
+// @Router /api/config/effective [get]
+func (s *Service) handleEffectiveConfig(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminIdentity(w, r) {
+		return
+	}
+	report, err := config.Check()
+	if err != nil {
+		http.Error(w, "read settings: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if report.Issues == nil {
+		report.Issues = []config.Issue{}
+	}
+	writeJSON(w, map[string]any{
+		"settings_path": report.Path,
+		"strict":        config.Strict(),
+		"issues":        report.Issues,
+		"settings":      config.Effective(s.currentConfig()),
+	})
+}
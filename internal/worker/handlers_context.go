@@ -6,15 +6,19 @@ import (
 	"database/sql"
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc/codes"
 	grpcstatus "google.golang.org/grpc/status"
+	"github.com/thebtf/engram/internal/concepts"
+	"github.com/thebtf/engram/internal/config"
 	"github.com/thebtf/engram/internal/db/gorm"
 	pb "github.com/thebtf/engram/proto/engram/v1"
 	"github.com/thebtf/engram/internal/worker/sdk"
+	"github.com/thebtf/engram/pkg/langdetect"
 	"github.com/thebtf/engram/pkg/models"
 )
 
@@ -71,11 +75,71 @@ func memoriesToObservations(mems []*models.Memory) []*models.Observation {
 			Narrative:       sql.NullString{String: mem.Content, Valid: mem.Content != ""},
 			Concepts:        models.JSONStringArray(mem.Tags),
 			ImportanceScore: 1,
+			Language:        mem.Language,
 		})
 	}
 	return result
 }
 
+// recordMemoryAccess counts one access of each observation from source, made
+// from project, in the per-memory access statistics. Non-blocking.
+func (s *Service) recordMemoryAccess(source, project string, observations []*models.Observation) {
+	s.initMu.RLock()
+	store := s.memoryAccessStore
+	s.initMu.RUnlock()
+	if store == nil || len(observations) == 0 {
+		return
+	}
+	ids := make([]int64, 0, len(observations))
+	for _, obs := range observations {
+		ids = append(ids, obs.ID)
+	}
+	store.Record(source, project, ids)
+}
+
+// loadPinnedObservations returns the project's pinned memories as observations,
+// capped at PinnedInjectLimit. Pinned memories are injected regardless of score.
+func (s *Service) loadPinnedObservations(ctx context.Context, project string) []*models.Observation {
+	return memoriesToObservations(s.loadPinnedMemories(ctx, project))
+}
+
+// loadPinnedMemories returns the project's pinned memories, capped at
+// PinnedInjectLimit.
+func (s *Service) loadPinnedMemories(ctx context.Context, project string) []*models.Memory {
+	if s.memoryStore == nil || project == "" {
+		return nil
+	}
+	limit := 10
+	if cfg := s.currentConfig(); cfg != nil && cfg.PinnedInjectLimit > 0 {
+		limit = cfg.PinnedInjectLimit
+	}
+	mems, err := s.memoryStore.ListPinned(ctx, project, limit)
+	if err != nil {
+		log.Debug().Err(err).Str("project", project).Msg("Failed to fetch pinned memories")
+		return nil
+	}
+	return mems
+}
+
+// defaultWorkspaceInjectLimit caps how many workspace-scoped memories are
+// merged into a sub-project's context.
+const defaultWorkspaceInjectLimit = 20
+
+// loadWorkspaceMemories returns the memories shared through the workspace that
+// encloses project. It returns nil when there is no workspace or when the
+// project is the workspace root itself, whose own memories already cover them.
+func (s *Service) loadWorkspaceMemories(ctx context.Context, workspace, project string) []*models.Memory {
+	if s.memoryStore == nil || workspace == "" || workspace == project {
+		return nil
+	}
+	mems, err := s.memoryStore.ListWorkspace(ctx, workspace, defaultWorkspaceInjectLimit)
+	if err != nil {
+		log.Debug().Err(err).Str("workspace", workspace).Msg("Failed to fetch workspace memories")
+		return nil
+	}
+	return mems
+}
+
 // handleSearchByPrompt godoc
 // @Summary Search observations by prompt
 // @Description Searches observations relevant to a user prompt using hybrid vector + FTS search with query expansion, cross-encoder reranking, and clustering. Supports both GET (query params) and POST (JSON body) to avoid URL length limits.
@@ -88,7 +152,11 @@ func memoriesToObservations(mems []*models.Memory) []*models.Observation {
 // @Param cwd query string false "Working directory (ignored server-side)"
 // @Param agent_id query string false "Agent ID (acts as project scope if project empty)"
 // @Param limit query int false "Number of results (default 50, max 200)"
-// @Param body body object false "POST body: {project, query, agent_id, cwd, limit}"
+// @Param session_id query string false "Session the results are injected into; observations already injected into it are skipped"
+// @Param exclude_already_injected query bool false "Skip observations already injected into session_id (default true)"
+// @Param boost_concepts query bool false "Extract concepts from the query and boost observations tagged with them (default false)"
+// @Param files_being_edited query []string false "Files open or recently edited (repeatable); observations mentioning them rank higher and list them in matched_files"
+// @Param body body object false "POST body: {project, query, agent_id, cwd, limit, session_id, exclude_already_injected, boost_concepts, files_being_edited}"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {string} string "project and query required"
 // @Failure 500 {string} string "internal error"
@@ -99,7 +167,16 @@ func (s *Service) handleSearchByPrompt(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("query")
 	cwd := r.URL.Query().Get("cwd")
 	agentID := r.URL.Query().Get("agent_id")
+	language := r.URL.Query().Get("language")
 	filesBeingEdited := r.URL.Query()["files_being_edited"]
+	sessionID := r.URL.Query().Get("session_id")
+	excludeInjected := true
+	if v := r.URL.Query().Get("exclude_already_injected"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			excludeInjected = b
+		}
+	}
+	boostConcepts, _ := strconv.ParseBool(r.URL.Query().Get("boost_concepts"))
 
 	// For POST requests, allow JSON body to override query params.
 	var obsTypeFilter string
@@ -110,7 +187,11 @@ func (s *Service) handleSearchByPrompt(w http.ResponseWriter, r *http.Request) {
 			Cwd             string `json:"cwd"`
 			AgentID         string `json:"agent_id"`
 			ObsType         string `json:"obs_type"`
+			Language        string `json:"language"`
 			FilesBeingEdited []string `json:"files_being_edited"`
+			SessionID        string   `json:"session_id"`
+			ExcludeInjected  *bool    `json:"exclude_already_injected"`
+			BoostConcepts    *bool    `json:"boost_concepts"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&body); err == nil {
 			if body.Project != "" {
@@ -128,9 +209,21 @@ func (s *Service) handleSearchByPrompt(w http.ResponseWriter, r *http.Request) {
 			if body.ObsType != "" {
 				obsTypeFilter = body.ObsType
 			}
+			if body.Language != "" {
+				language = body.Language
+			}
 			if len(body.FilesBeingEdited) > 0 {
 				filesBeingEdited = body.FilesBeingEdited
 			}
+			if body.SessionID != "" {
+				sessionID = body.SessionID
+			}
+			if body.ExcludeInjected != nil {
+				excludeInjected = *body.ExcludeInjected
+			}
+			if body.BoostConcepts != nil {
+				boostConcepts = *body.BoostConcepts
+			}
 			// agent_id acts as project scope for OpenClaw agents without filesystem context
 			if project == "" && agentID != "" {
 				project = agentID
@@ -161,15 +254,53 @@ func (s *Service) handleSearchByPrompt(w http.ResponseWriter, r *http.Request) {
 
 	limit := gorm.ParseLimitParamWithMax(r, DefaultSearchLimit, 200)
 	searchStart := time.Now()
-	maxResults := s.config.ContextMaxPromptResults
+	maxResults := s.currentConfig().ContextMaxPromptResults
+	// Sessions in an injection experiment get their arm's result cap, and
+	// the search counts as a follow-up to the session's injected context.
+	strategy, arm, inExperiment := s.injectionArm(sessionID, false)
+	if inExperiment {
+		if arm.MaxResults > 0 {
+			maxResults = arm.MaxResults
+		}
+		s.recordExperimentEvent(sessionID, gorm.ExperimentEventFollowUpSearch)
+	}
 	if limit > 0 && (maxResults <= 0 || limit < maxResults) {
 		maxResults = limit
 	}
+	// Prefer results in the prompt's language unless the caller names one.
+	if language == "" {
+		language = langdetect.Detect(query)
+	}
+	// Observations already injected into this session are skipped, so a
+	// repeated prompt does not inject the same memories again. Ask for extra
+	// results to make up for the ones dropped.
+	var injectedIDs map[int64]struct{}
+	if sessionID != "" && excludeInjected && s.injectionStore != nil {
+		ids, injErr := s.injectionStore.InjectedIDs(r.Context(), sessionID)
+		if injErr != nil {
+			log.Debug().Err(injErr).Str("session_id", sessionID).Msg("Failed to load injected observations")
+		} else {
+			injectedIDs = ids
+		}
+	}
+	retrieveLimit := maxResults
+	if retrieveLimit > 0 {
+		retrieveLimit += len(injectedIDs)
+	}
+	// Concept boosting: observations sharing the prompt's concepts rank
+	// higher and are returned even without a text match.
+	var promptConcepts []concepts.Concept
+	if boostConcepts {
+		promptConcepts = concepts.Extract(query, concepts.DefaultMinConfidence)
+	}
 	retrievalMeta := &retrievalMetadata{}
 	retrievalCtx := withRetrievalRequest(r.Context(), agentID, cwd, retrievalMeta)
+	workingFiles := cleanWorkingFiles(filesBeingEdited)
 	clusteredObservations, similarityScores, err := s.RetrieveRelevant(retrievalCtx, project, query, RetrievalOptions{
-		MaxResults: maxResults,
-		FilePaths:  filesBeingEdited,
+		MaxResults:    retrieveLimit,
+		FilePaths:     workingFiles,
+		Language:      language,
+		BoostConcepts: promptConcepts,
 	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -192,6 +323,31 @@ func (s *Service) handleSearchByPrompt(w http.ResponseWriter, r *http.Request) {
 		}
 		clusteredObservations = filtered
 	}
+	alreadyInjected := 0
+	if len(injectedIDs) > 0 {
+		before := len(clusteredObservations)
+		clusteredObservations = excludeByIDs(clusteredObservations, injectedIDs)
+		alreadyInjected = before - len(clusteredObservations)
+	}
+	if maxResults > 0 && len(clusteredObservations) > maxResults {
+		clusteredObservations = clusteredObservations[:maxResults]
+	}
+	if sessionID != "" && s.injectionStore != nil && len(clusteredObservations) > 0 {
+		records := make([]gorm.InjectionRecord, len(clusteredObservations))
+		for i, obs := range clusteredObservations {
+			records[i] = gorm.InjectionRecord{ObservationID: obs.ID, SessionID: sessionID, InjectionSection: "prompt"}
+		}
+		injStore := s.injectionStore
+		go func() {
+			_ = injStore.RecordInjections(context.Background(), records)
+		}()
+	}
+	// Prompt hooks pass a session: their results are injected, not searched.
+	accessSource := gorm.AccessSearch
+	if sessionID != "" {
+		accessSource = gorm.AccessInjection
+	}
+	s.recordMemoryAccess(accessSource, project, clusteredObservations)
 	// Record retrieval stats with staleness metrics
 	s.recordRetrievalStatsExtended(project, int64(len(clusteredObservations)), 0, 0,
 		int64(staleCount), int64(freshCount), int64(duplicatesRemoved), true)
@@ -201,6 +357,7 @@ func (s *Service) handleSearchByPrompt(w http.ResponseWriter, r *http.Request) {
 		Str("query", query).
 		Str("intent", detectedIntent).
 		Int("expansions", len(expandedQueries)).
+		Int("boost_concepts", len(promptConcepts)).
 		Int("found", len(clusteredObservations)).
 		Int("stale_excluded", staleCount).
 		Float64("threshold", threshold).
@@ -213,6 +370,9 @@ func (s *Service) handleSearchByPrompt(w http.ResponseWriter, r *http.Request) {
 		if score, ok := similarityScores[obs.ID]; ok {
 			obsMap["similarity"] = score
 		}
+		if matched := matchedWorkingFiles(obs, workingFiles); len(matched) > 0 {
+			obsMap["matched_files"] = matched
+		}
 		obsWithScores[i] = obsMap
 	}
 
@@ -234,10 +394,10 @@ func (s *Service) handleSearchByPrompt(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Track this search for analytics
-	s.trackSearchQuery(query, project, "observations", len(clusteredObservations), float32(time.Since(searchStart).Milliseconds()))
+	s.trackSearchQuery(r.Context(), query, project, gorm.SearchTypePrompt, len(clusteredObservations), float32(time.Since(searchStart).Milliseconds()))
 
 	// Always-inject tier: backed by behavioral_rules in v5.
-	alwaysInjectLimit := s.config.AlwaysInjectLimit
+	alwaysInjectLimit := s.currentConfig().AlwaysInjectLimit
 	if alwaysInjectLimit <= 0 {
 		alwaysInjectLimit = 20
 	}
@@ -258,13 +418,18 @@ func (s *Service) handleSearchByPrompt(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, map[string]any{
 		"project":       project,
 		"query":         query,
+		"language":      language,
 		"intent":        detectedIntent,
 		"expansions":    expansionInfo,
+		"pinned":        s.loadPinnedObservations(r.Context(), project),
 		"observations":  obsWithScores,
 		"always_inject": alwaysInjectObs,
 		"threshold":     threshold,
 		"max_results":   maxResults,
 		"total_results": totalResults,
+		"already_injected": alreadyInjected,
+		"strategy":         strategy,
+		"concepts":         promptConcepts,
 	})
 }
 
@@ -385,6 +550,20 @@ func filterByIDs(observations []*models.Observation, ids map[int64]struct{}) []*
 	return result
 }
 
+// excludeByIDs returns observations whose ID is not in ids.
+func excludeByIDs(observations []*models.Observation, ids map[int64]struct{}) []*models.Observation {
+	if len(ids) == 0 {
+		return observations
+	}
+	result := make([]*models.Observation, 0, len(observations))
+	for _, obs := range observations {
+		if _, ok := ids[obs.ID]; !ok {
+			result = append(result, obs)
+		}
+	}
+	return result
+}
+
 // compactObservation returns only the fields needed by the session-start hook.
 func compactObservation(obs *models.Observation) map[string]any {
 	m := map[string]any{
@@ -476,10 +655,12 @@ func applyActiveVersions(ctx context.Context, vs *gorm.VersionStore, observation
 }
 
 type sessionStartCompatibilityResponse struct {
-	Issues      []map[string]any `json:"issues"`
-	Rules       []map[string]any `json:"rules"`
-	Memories    []map[string]any `json:"memories"`
-	GeneratedAt string           `json:"generated_at"`
+	Issues   []map[string]any `json:"issues"`
+	Rules    []map[string]any `json:"rules"`
+	Memories []map[string]any `json:"memories"`
+	// Digest is the project's current weekly digest (mode=digest only).
+	Digest      string `json:"digest,omitempty"`
+	GeneratedAt string `json:"generated_at"`
 }
 
 func sessionStartIssuesToMaps(issues []*pb.SessionStartIssue) []map[string]any {
@@ -578,6 +759,36 @@ func sessionStartMemoriesToMaps(memories []*pb.SessionStartMemory) []map[string]
 	return result
 }
 
+// workspaceMemoriesToMaps renders workspace-scoped memories in the same shape
+// as sessionStartMemoriesToMaps, tagged with scope "workspace".
+func workspaceMemoriesToMaps(memories []*models.Memory) []map[string]any {
+	result := memoriesToMaps(memories)
+	for _, entry := range result {
+		entry["scope"] = string(models.ScopeWorkspace)
+	}
+	return result
+}
+
+// memoriesToMaps renders memories in the same shape as
+// sessionStartMemoriesToMaps.
+func memoriesToMaps(memories []*models.Memory) []map[string]any {
+	result := make([]map[string]any, 0, len(memories))
+	for _, memory := range memories {
+		result = append(result, map[string]any{
+			"id":           memory.ID,
+			"project":      memory.Project,
+			"content":      memory.Content,
+			"tags":         append([]string(nil), memory.Tags...),
+			"source_agent": memory.SourceAgent,
+			"edited_by":    memory.EditedBy,
+			"version":      memory.Version,
+			"created_at":   memory.CreatedAt.UTC().Format(time.RFC3339),
+			"updated_at":   memory.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return result
+}
+
 // handleSessionStartContextStatic godoc
 // @Summary Get static session-start context
 // @Description Returns static session-start context sourced from the server gRPC implementation: active issues, behavioral rules, recent memories, and generated_at.
@@ -585,7 +796,9 @@ func sessionStartMemoriesToMaps(memories []*pb.SessionStartMemory) []map[string]
 // @Produce json
 // @Security ApiKeyAuth
 // @Param project query string false "Project slug (required)"
-// @Param body body object false "POST body: {project, memories_limit, issues_limit}"
+// @Param workspace query string false "Workspace ID of the enclosing monorepo; its workspace-scoped memories are appended"
+// @Param mode query string false "What to inject: full (default), pinned (rules and pinned memories), digest (rules and the current weekly digest) or none"
+// @Param body body object false "POST body: {project, workspace, mode, memories_limit, issues_limit}"
 // @Success 200 {object} sessionStartCompatibilityResponse
 // @Failure 400 {string} string "project required"
 // @Failure 500 {string} string "internal error"
@@ -593,12 +806,16 @@ func sessionStartMemoriesToMaps(memories []*pb.SessionStartMemory) []map[string]
 // @Router /api/context/session-start [get]
 func (s *Service) handleSessionStartContextStatic(w http.ResponseWriter, r *http.Request) {
 	project := strings.TrimSpace(r.URL.Query().Get("project"))
+	workspace := strings.TrimSpace(r.URL.Query().Get("workspace"))
+	mode := r.URL.Query().Get("mode")
 	memoriesLimit := int32(0)
 	issuesLimit := int32(0)
 
 	if r.Method == http.MethodPost && r.Body != nil {
 		var body struct {
 			Project       string `json:"project"`
+			Workspace     string `json:"workspace"`
+			Mode          string `json:"mode"`
 			MemoriesLimit int32  `json:"memories_limit"`
 			IssuesLimit   int32  `json:"issues_limit"`
 		}
@@ -609,6 +826,12 @@ func (s *Service) handleSessionStartContextStatic(w http.ResponseWriter, r *http
 		if strings.TrimSpace(body.Project) != "" {
 			project = strings.TrimSpace(body.Project)
 		}
+		if strings.TrimSpace(body.Workspace) != "" {
+			workspace = strings.TrimSpace(body.Workspace)
+		}
+		if strings.TrimSpace(body.Mode) != "" {
+			mode = body.Mode
+		}
 		memoriesLimit = body.MemoriesLimit
 		issuesLimit = body.IssuesLimit
 	}
@@ -621,6 +844,26 @@ func (s *Service) handleSessionStartContextStatic(w http.ResponseWriter, r *http
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if workspace != "" {
+		if err := ValidateProjectName(workspace); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	mode, err := parseSessionStartMode(mode)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if mode == sessionStartModeNone {
+		writeJSON(w, sessionStartCompatibilityResponse{
+			Issues:      []map[string]any{},
+			Rules:       []map[string]any{},
+			Memories:    []map[string]any{},
+			GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
 
 	s.initMu.RLock()
 	grpcSrv := s.grpcInternalServer
@@ -649,12 +892,17 @@ func (s *Service) handleSessionStartContextStatic(w http.ResponseWriter, r *http
 		generatedAt = ts.AsTime().UTC().Format(time.RFC3339)
 	}
 
-	writeJSON(w, sessionStartCompatibilityResponse{
+	memories := sessionStartMemoriesToMaps(resp.GetMemories())
+	memories = append(memories, workspaceMemoriesToMaps(s.loadWorkspaceMemories(r.Context(), workspace, project))...)
+
+	out := sessionStartCompatibilityResponse{
 		Issues:      sessionStartIssuesToMaps(resp.GetIssues()),
 		Rules:       sessionStartRulesToMaps(resp.GetRules()),
-		Memories:    sessionStartMemoriesToMaps(resp.GetMemories()),
+		Memories:    memories,
 		GeneratedAt: generatedAt,
-	})
+	}
+	s.applySessionStartMode(r.Context(), &out, mode, project)
+	writeJSON(w, out)
 }
 
 func grpcCodeToHTTP(code codes.Code) int {
@@ -676,7 +924,7 @@ func grpcCodeToHTTP(code codes.Code) int {
 
 // handleContextInject godoc
 // @Summary Inject context for session start
-// @Description Returns context for injection at session start. Response includes recent (last 5), relevant (top 10 semantic), and guidance sections. Supports GET (deprecated) and POST. Critical startup path — optimized for speed.
+// @Description Returns context for injection at session start. Response includes recent (last 5), relevant (top 10 semantic), and guidance sections. The rendered field holds the sections pre-formatted with the project's context template (ENGRAM_CONTEXT_TEMPLATE). Of two memories that contradict each other (a contradicts, supersedes or invalidated_by relation, or near-identical statements with opposite negation) only the newer or superseding one is injected; the conflicts field lists the pairs and unresolved ones get a "Conflicting information" note in rendered. Supports GET (deprecated) and POST. Critical startup path — optimized for speed.
 // @Tags Context
 // @Accept json
 // @Produce json
@@ -684,14 +932,63 @@ func grpcCodeToHTTP(code codes.Code) int {
 // @Param project query string false "Project name (required)"
 // @Param agent_id query string false "Agent ID (acts as project scope if project empty)"
 // @Param format query string false "Response format: 'compact' for minimal payload"
-// @Param body body object false "POST body: {project, agent_id, cwd, legacy_project, git_remote, relative_path}"
+// @Param workspace query string false "Workspace ID of the enclosing monorepo; its workspace-scoped memories join the relevant section"
+// @Param trust query bool false "Annotate each memory in rendered with a trust line (age, confirmations, score); defaults to ENGRAM_CONTEXT_TRUST_LINE"
+// @Param body body object false "POST body: {project, agent_id, cwd, legacy_project, git_remote, relative_path, workspace}"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {string} string "project required"
 // @Failure 500 {string} string "internal error"
 // @Router /api/context/inject [post]
 // @Router /api/context/inject [get]
 func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
-	var project, agentID, cwd, legacyProject, gitRemote, relativePath, sessionID string
+	s.serveContextInject(w, r, false)
+}
+
+// handleContextInjectPreview godoc
+// @Summary Preview context injection
+// @Description Returns exactly what /api/context/inject would return for the same parameters, plus the retrieval query and the reason each observation was chosen. Nothing is recorded: no injection events, no retrieval stats, no project upsert. Use it to tune relevance thresholds and token budgets without starting sessions.
+// @Tags Context
+// @Produce json
+// @Security ApiKeyAuth
+// @Param project query string false "Project name (required)"
+// @Param agent_id query string false "Agent ID (acts as project scope if project empty)"
+// @Param session_id query string false "Session whose last prompt drives the relevant section"
+// @Param workspace query string false "Workspace ID of the enclosing monorepo"
+// @Param format query string false "Response format: 'compact' for minimal payload"
+// @Param trust query bool false "Annotate each memory in rendered with a trust line"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {string} string "project required"
+// @Failure 500 {string} string "internal error"
+// @Router /api/context/inject/preview [get]
+func (s *Service) handleContextInjectPreview(w http.ResponseWriter, r *http.Request) {
+	s.serveContextInject(w, r, true)
+}
+
+// injectReason explains why an observation was selected for injection.
+type injectReason struct {
+	Section string `json:"section"`
+	Reason  string `json:"reason"`
+	ID      int64  `json:"id"`
+	Tokens  int    `json:"tokens"`
+}
+
+// appendInjectReasons adds one reason per observation in a section.
+func appendInjectReasons(reasons []injectReason, section, reason string, observations []*models.Observation) []injectReason {
+	for _, obs := range observations {
+		reasons = append(reasons, injectReason{
+			ID:      obs.ID,
+			Section: section,
+			Reason:  reason,
+			Tokens:  estimateObsTokens(obs),
+		})
+	}
+	return reasons
+}
+
+// serveContextInject builds the context injection payload. In preview mode the
+// payload is annotated with selection reasons and nothing is recorded.
+func (s *Service) serveContextInject(w http.ResponseWriter, r *http.Request, preview bool) {
+	var project, agentID, cwd, legacyProject, gitRemote, relativePath, sessionID, workspace string
 	var filesBeingEdited []string
 
 	if r.Method == http.MethodPost {
@@ -703,6 +1000,7 @@ func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
 			GitRemote        string   `json:"git_remote"`
 			RelativePath     string   `json:"relative_path"`
 			SessionID        string   `json:"session_id"`
+			Workspace        string   `json:"workspace"`
 			FilesBeingEdited []string `json:"files_being_edited"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -710,6 +1008,7 @@ func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		project = req.Project
+		workspace = req.Workspace
 		agentID = req.AgentID
 		cwd = req.Cwd
 		legacyProject = req.LegacyProject
@@ -726,6 +1025,7 @@ func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
 		gitRemote = r.URL.Query().Get("git_remote")
 		relativePath = r.URL.Query().Get("relative_path")
 		sessionID = r.URL.Query().Get("session_id")
+		workspace = r.URL.Query().Get("workspace")
 		filesBeingEdited = r.URL.Query()["files_being_edited"]
 	}
 
@@ -748,13 +1048,19 @@ func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if workspace != "" {
+		if err := ValidateProjectName(workspace); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 
 	// Server-side: ignore client-provided cwd to prevent filesystem probing (S9-003).
 	// File mtime staleness checks are only meaningful on the client; the server has no
 	// access to client filesystems.
 	cwd = ""
 
-	if legacyProject != "" && legacyProject != project {
+	if legacyProject != "" && legacyProject != project && !preview {
 		displayName := project
 		if idx := strings.Index(project, "_"); idx > 0 {
 			displayName = project[:idx]
@@ -767,17 +1073,29 @@ func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Limit observations for fast startup (configurable, default 100)
-	limit := s.config.ContextObservations
+	limit := s.currentConfig().ContextObservations
 	if limit <= 0 {
 		limit = DefaultContextLimit
 	}
 
 	// Full count determines how many observations get full detail (configurable, default 25)
-	fullCount := s.config.ContextFullCount
+	fullCount := s.currentConfig().ContextFullCount
 	if fullCount <= 0 {
 		fullCount = 25
 	}
 
+	// Sessions in an injection experiment get the settings of their arm.
+	selectedStrategy, arm, inExperiment := s.injectionArm(sessionID, !preview)
+	relevantLimit := 10
+	if inExperiment {
+		if arm.MaxResults > 0 {
+			relevantLimit = arm.MaxResults
+		}
+		if arm.FullCount > 0 {
+			fullCount = arm.FullCount
+		}
+	}
+
 	ctx := r.Context()
 
 	// --- Recent section: last 5 observations by created_at ---
@@ -800,15 +1118,28 @@ func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
 			currentMtimes := sdk.GetFileMtimes(paths, cwd)
 			if obs.CheckStaleness(currentMtimes) {
 				staleCount++
-				s.queueStaleVerification(obs.ID, cwd)
+				if !preview {
+					s.queueStaleVerification(obs.ID, cwd)
+				}
 				continue
 			}
 		}
 		recentFresh = append(recentFresh, obs)
 	}
 
-	// Build a set of IDs already in the recent section for deduplication
-	recentIDs := make(map[int64]struct{}, len(recentFresh))
+	// --- Pinned section: forced into every injection, ordered before scored results ---
+	pinnedObservations := s.loadPinnedObservations(ctx, project)
+	pinnedIDs := make(map[int64]struct{}, len(pinnedObservations))
+	for _, obs := range pinnedObservations {
+		pinnedIDs[obs.ID] = struct{}{}
+	}
+	recentFresh = excludeByIDs(recentFresh, pinnedIDs)
+
+	// Build a set of IDs already in the pinned and recent sections for deduplication
+	recentIDs := make(map[int64]struct{}, len(recentFresh)+len(pinnedIDs))
+	for id := range pinnedIDs {
+		recentIDs[id] = struct{}{}
+	}
 	for _, obs := range recentFresh {
 		recentIDs[obs.ID] = struct{}{}
 	}
@@ -820,15 +1151,17 @@ func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
 	// name when no prompt history exists. Uses the same pipeline as prompt-search.
 	// When InjectUnified=false (ENGRAM_INJECT_UNIFIED=false), the legacy path is used instead.
 	var relevantObservations []*models.Observation
-	if s.config == nil || s.config.InjectUnified {
+	injectQuery := project
+	relevantReason := "full-text match for the project name (no prompt history)"
+	if cfg := s.currentConfig(); cfg == nil || cfg.InjectUnified {
 		// Unified path: derive query from the last user prompt for this session.
-		injectQuery := project
 		if prompt, pErr := s.loadLastUserPromptBySession(ctx, project, sessionID, 20); pErr == nil && prompt != nil {
 			if prompt.PromptText != "" {
 				injectQuery = prompt.PromptText
+				relevantReason = "full-text match for the session's last prompt"
 			}
 		}
-		opts := RetrievalOptions{MaxResults: 10, SessionID: sessionID, FilePaths: filesBeingEdited}
+		opts := RetrievalOptions{MaxResults: relevantLimit, SessionID: sessionID, FilePaths: filesBeingEdited, Language: langdetect.Detect(injectQuery)}
 		retrieved, _, retrieveErr := s.RetrieveRelevant(ctx, project, injectQuery, opts)
 		if retrieveErr != nil {
 			log.Debug().Err(retrieveErr).Str("project", project).Msg("RetrieveRelevant failed for context inject relevant section")
@@ -845,6 +1178,17 @@ func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
 		relevantObservations = []*models.Observation{}
 	}
 
+	// Workspace-scoped memories from the enclosing monorepo join the relevant section.
+	workspaceIDs := make(map[int64]struct{})
+	for _, obs := range memoriesToObservations(s.loadWorkspaceMemories(ctx, workspace, project)) {
+		if _, already := recentIDs[obs.ID]; !already {
+			obs.Scope = models.ScopeWorkspace
+			relevantObservations = append(relevantObservations, obs)
+			recentIDs[obs.ID] = struct{}{}
+			workspaceIDs[obs.ID] = struct{}{}
+		}
+	}
+
 	// --- Guidance section: top behavioral rules in v5 ---
 	var guidanceObservations []*models.Observation
 	if s.behavioralRulesStore != nil {
@@ -870,7 +1214,7 @@ func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
 
 	// --- Always-inject section: backed by behavioral_rules in v5 ---
 	var alwaysInjectObservations []*models.Observation
-	alwaysInjectLimit := s.config.AlwaysInjectLimit
+	alwaysInjectLimit := s.currentConfig().AlwaysInjectLimit
 	if alwaysInjectLimit <= 0 {
 		alwaysInjectLimit = 20
 	}
@@ -922,13 +1266,18 @@ func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
 			currentMtimes := sdk.GetFileMtimes(paths, cwd)
 			if obs.CheckStaleness(currentMtimes) {
 				staleCount++
-				s.queueStaleVerification(obs.ID, cwd)
+				if !preview {
+					s.queueStaleVerification(obs.ID, cwd)
+				}
 				continue
 			}
 		}
 		allFreshObservations = append(allFreshObservations, obs)
 	}
 
+	// Pinned memories are reported in their own section only.
+	allFreshObservations = excludeByIDs(allFreshObservations, pinnedIDs)
+
 	// Merge relevant observations into the union (those not already in allFreshObservations)
 	allFreshIDs := make(map[int64]struct{}, len(allFreshObservations))
 	for _, obs := range allFreshObservations {
@@ -942,24 +1291,37 @@ func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Leave out the older side of contradicting memories rather than
+	// presenting both as true; unresolved conflicts get a banner below.
+	conflicts := s.contextConflicts(ctx, [][]*models.Observation{pinnedObservations, recentFresh, unionObservations}, pinnedIDs)
+	if len(conflicts) > 0 {
+		droppedIDs := droppedConflictIDs(conflicts)
+		recentFresh = excludeByIDs(recentFresh, droppedIDs)
+		relevantObservations = excludeByIDs(relevantObservations, droppedIDs)
+		unionObservations = excludeByIDs(unionObservations, droppedIDs)
+	}
+
 	// Cluster the union to remove duplicates (clustering threshold removed in v5)
 	clusteredObservations := unionObservations
 	duplicatesRemoved := 0
 
 	// Record retrieval stats with staleness metrics
-	s.recordRetrievalStatsExtended(project, int64(len(clusteredObservations)), 0, 0,
-		int64(staleCount), int64(len(allFreshObservations)), int64(duplicatesRemoved), false)
+	if !preview {
+		s.recordRetrievalStatsExtended(project, int64(len(clusteredObservations)), 0, 0,
+			int64(staleCount), int64(len(allFreshObservations)), int64(duplicatesRemoved), false)
+	}
 
 	// Apply token budget: estimate tokens and trim observations to fit
-	tokenBudget := s.config.ContextMaxTokens
+	tokenBudget := s.currentConfig().ContextMaxTokens
 	var tokenEstimate int
 	var budgetTrimmed int
 
 	if tokenBudget > 0 {
 		// Estimate tokens per observation (~4 chars per token for English)
-		// Reserve 20% of budget for guidance
+		// Reserve 20% of budget for guidance. Pinned memories are never trimmed;
+		// they are paid for out of the main budget.
 		guidanceBudget := tokenBudget / 5
-		mainBudget := tokenBudget - guidanceBudget
+		mainBudget := max(0, tokenBudget-guidanceBudget-estimateTokens(pinnedObservations))
 
 		// Trim guidance first
 		guidanceObservations, _, _ = trimToTokenBudget(guidanceObservations, guidanceBudget)
@@ -979,6 +1341,7 @@ func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
 	} else {
 		tokenEstimate = estimateTokens(clusteredObservations) + estimateTokens(guidanceObservations)
 	}
+	tokenEstimate += estimateTokens(pinnedObservations)
 
 	log.Info().
 		Str("project", project).
@@ -992,11 +1355,9 @@ func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
 		Int("recent_section", len(recentFresh)).
 		Int("relevant_section", len(relevantObservations)).
 		Int("guidance_section", len(guidanceObservations)).
+		Int("pinned_section", len(pinnedObservations)).
 		Msg("Context injection with clustering")
 
-	// Agent stats fetch + A/B injection strategy selector were removed in v5.
-	var selectedStrategy string
-
 	// Apply active version substitution (APO-lite, Phase 5).
 	// For each observation in guidance and always-inject sections, check whether an active
 	// ObservationVersion exists. When one does, replace the narrative in a shallow copy so
@@ -1009,10 +1370,18 @@ func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
 		alwaysInjectObservations = applyActiveVersions(ctx, versionStore, alwaysInjectObservations)
 	}
 
+	// Always-inject and guidance entries are behavioral rules, not memories.
+	if !preview {
+		for _, section := range [][]*models.Observation{pinnedObservations, recentFresh, relevantObservations} {
+			s.recordMemoryAccess(gorm.AccessInjection, project, section)
+		}
+	}
+
 	// Record injection events asynchronously (closed-loop learning Phase 1).
 	// Fire-and-forget: injection tracking is non-critical; errors are silently dropped.
-	if sessionID != "" && s.injectionStore != nil {
+	if sessionID != "" && s.injectionStore != nil && !preview {
 		capturedAlwaysInject := alwaysInjectObservations
+		capturedPinned := pinnedObservations
 		capturedRecent := recentFresh
 		capturedRelevant := relevantObservations
 		capturedSessionID := sessionID
@@ -1022,6 +1391,9 @@ func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
 			for _, obs := range capturedAlwaysInject {
 				records = append(records, gorm.InjectionRecord{ObservationID: obs.ID, SessionID: capturedSessionID, InjectionSection: "always_inject"})
 			}
+			for _, obs := range capturedPinned {
+				records = append(records, gorm.InjectionRecord{ObservationID: obs.ID, SessionID: capturedSessionID, InjectionSection: "pinned"})
+			}
 			for _, obs := range capturedRecent {
 				records = append(records, gorm.InjectionRecord{ObservationID: obs.ID, SessionID: capturedSessionID, InjectionSection: "recent"})
 			}
@@ -1034,18 +1406,51 @@ func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
 		}()
 	}
 
+	// Pre-render the injected block with the project's context template so
+	// hooks can inject it verbatim. A broken custom template falls back to
+	// the built-in layout rather than failing the injection.
+	sections := []contextSection{
+		{Title: "Pinned", Observations: pinnedObservations},
+		{Title: "Always apply", Observations: alwaysInjectObservations},
+		{Title: "Guidance", Observations: guidanceObservations},
+		{Title: "Recent", Observations: recentFresh},
+		{Title: "Relevant", Observations: relevantObservations},
+	}
+	trust := s.currentConfig().ContextTrustLine
+	if v, err := strconv.ParseBool(r.URL.Query().Get("trust")); err == nil {
+		trust = v
+	}
+	if trust {
+		lines := s.contextTrustLines(ctx, sections, time.Now())
+		for i := range sections {
+			sections[i].Trust = lines
+		}
+	}
+	layout := s.currentConfig().ContextTemplate.LayoutFor(project)
+	if inExperiment {
+		layout = arm.Layout(layout)
+	}
+	rendered, renderErr := renderInjectedContext(layout, project, sections)
+	if renderErr != nil {
+		log.Warn().Err(renderErr).Str("project", project).Msg("Context template failed; using the default layout")
+		rendered, _ = renderInjectedContext(config.ContextTemplate{}.LayoutFor(project), project, sections)
+	}
+	rendered = appendToContext(rendered, conflictBanner(conflicts))
+
 	// Check if compact format is requested
 	compact := r.URL.Query().Get("format") == "compact"
 
+	var resp map[string]any
 	if compact {
 		// Compact format: only fields the hook actually uses.
 		// Main observations use fullCount limit — condensed entries skip narrative/facts.
 		// Recalculate token estimate accounting for condensed format savings.
 		compactTokenEstimate := estimateTokensWithLimit(clusteredObservations, fullCount) +
-			estimateTokens(guidanceObservations)
-		writeJSON(w, map[string]any{
+			estimateTokens(guidanceObservations) + estimateTokens(pinnedObservations)
+		resp = map[string]any{
 			"strategy":           selectedStrategy,
 			"project":            project,
+			"pinned":             compactObservations(pinnedObservations),
 			"observations":       compactObservationsWithLimit(clusteredObservations, fullCount),
 			"recent":             compactObservations(recentFresh),
 			"relevant":           compactObservations(relevantObservations),
@@ -1057,11 +1462,13 @@ func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
 			"duplicates_removed": duplicatesRemoved,
 			"token_estimate":     compactTokenEstimate,
 			"budget_trimmed":     budgetTrimmed,
-		})
+			"rendered":           rendered,
+		}
 	} else {
-		writeJSON(w, map[string]any{
+		resp = map[string]any{
 			"project":            project,
 			"strategy":           selectedStrategy,
+			"pinned":             pinnedObservations,
 			"observations":       clusteredObservations,
 			"recent":             recentFresh,
 			"relevant":           relevantObservations,
@@ -1073,8 +1480,52 @@ func (s *Service) handleContextInject(w http.ResponseWriter, r *http.Request) {
 			"duplicates_removed": duplicatesRemoved,
 			"token_estimate":     tokenEstimate,
 			"budget_trimmed":     budgetTrimmed,
-		})
+			"rendered":           rendered,
+		}
 	}
+
+	if len(conflicts) > 0 {
+		resp["conflicts"] = conflicts
+	}
+
+	if preview {
+		var searched, fromWorkspace []*models.Observation
+		for _, obs := range relevantObservations {
+			if _, ok := workspaceIDs[obs.ID]; ok {
+				fromWorkspace = append(fromWorkspace, obs)
+			} else {
+				searched = append(searched, obs)
+			}
+		}
+		sectionIDs := make(map[int64]struct{}, len(recentFresh)+len(relevantObservations))
+		for _, obs := range recentFresh {
+			sectionIDs[obs.ID] = struct{}{}
+		}
+		for _, obs := range relevantObservations {
+			sectionIDs[obs.ID] = struct{}{}
+		}
+
+		var reasons []injectReason
+		reasons = appendInjectReasons(reasons, "pinned", "pinned to the project (pin_observation)", pinnedObservations)
+		reasons = appendInjectReasons(reasons, "always_inject", "behavioral rule injected into every session", alwaysInjectObservations)
+		reasons = appendInjectReasons(reasons, "guidance", "highest-priority behavioral rule", guidanceObservations)
+		reasons = appendInjectReasons(reasons, "recent", "among the 5 newest in the project", recentFresh)
+		reasons = appendInjectReasons(reasons, "relevant", relevantReason, searched)
+		reasons = appendInjectReasons(reasons, "relevant", "workspace-scoped memory of "+workspace, fromWorkspace)
+		reasons = appendInjectReasons(reasons, "observations", "recent project history within the token budget",
+			excludeByIDs(clusteredObservations, sectionIDs))
+		if reasons == nil {
+			reasons = []injectReason{}
+		}
+
+		resp["preview"] = true
+		resp["query"] = injectQuery
+		resp["language"] = langdetect.Detect(injectQuery)
+		resp["token_budget"] = s.currentConfig().ContextMaxTokens
+		resp["reasons"] = reasons
+	}
+
+	writeJSON(w, resp)
 }
 
 // handleSearchDecisions godoc
@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// newInjectPreviewTestService wires retrieval hooks so the full inject handler
+// runs without a database: two recent observations, one prompt-matched result.
+func newInjectPreviewTestService() *Service {
+	svc := newInjectTestService(true)
+	svc.retrievalHooks.getRecentObservationsFiltered = func(_ context.Context, _ retrievalScope, _ int) ([]*models.Observation, error) {
+		return []*models.Observation{newObservation(1, "Recent one"), newObservation(2, "Recent two")}, nil
+	}
+	svc.retrievalHooks.getLastPromptBySession = func(_ context.Context, _, _ string) (*models.UserPromptWithSession, error) {
+		return &models.UserPromptWithSession{UserPrompt: models.UserPrompt{PromptText: "fix the auth retry bug"}}, nil
+	}
+	svc.retrievalHooks.retrieveRelevant = func(_ context.Context, _, _ string, _ RetrievalOptions) ([]*models.Observation, map[int64]float64, error) {
+		return []*models.Observation{newObservation(3, "Auth retry")}, nil, nil
+	}
+	return svc
+}
+
+func TestHandleContextInjectPreview_ExplainsAndRecordsNothing(t *testing.T) {
+	svc := newInjectPreviewTestService()
+
+	w := httptest.NewRecorder()
+	svc.handleContextInjectPreview(w, httptest.NewRequest(http.MethodGet, "/api/context/inject/preview?project=engram&session_id=s1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Preview       bool           `json:"preview"`
+		Query         string         `json:"query"`
+		TokenEstimate int            `json:"token_estimate"`
+		Reasons       []injectReason `json:"reasons"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Preview)
+	assert.Equal(t, "fix the auth retry bug", resp.Query)
+	assert.Positive(t, resp.TokenEstimate)
+
+	sections := make(map[int64]injectReason, len(resp.Reasons))
+	for _, reason := range resp.Reasons {
+		sections[reason.ID] = reason
+	}
+	assert.Equal(t, "recent", sections[1].Section)
+	assert.Equal(t, "relevant", sections[3].Section)
+	assert.Contains(t, sections[3].Reason, "last prompt")
+	assert.Positive(t, sections[3].Tokens)
+
+	assert.Empty(t, svc.retrievalStats, "preview must not record retrieval stats")
+}
+
+func TestHandleContextInject_NotPreview(t *testing.T) {
+	svc := newInjectPreviewTestService()
+
+	w := httptest.NewRecorder()
+	svc.handleContextInject(w, httptest.NewRequest(http.MethodGet, "/api/context/inject?project=engram&session_id=s1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.NotContains(t, resp, "preview")
+	assert.NotContains(t, resp, "reasons")
+	assert.Contains(t, svc.retrievalStats, "engram", "a real injection records retrieval stats")
+}
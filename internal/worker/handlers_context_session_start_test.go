@@ -15,6 +15,7 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/thebtf/engram/internal/grpcserver"
+	"github.com/thebtf/engram/pkg/models"
 	pb "github.com/thebtf/engram/proto/engram/v1"
 )
 
@@ -99,6 +100,84 @@ func TestHandleSessionStartContextStatic_MapsGrpcErrors(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "database not ready")
 }
 
+func TestHandleSessionStartContextStatic_RejectsInvalidWorkspace(t *testing.T) {
+	t.Parallel()
+
+	server := &stubSessionStartContextServer{resp: &pb.GetSessionStartContextResponse{}}
+	service := &Service{grpcInternalServer: server}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/context/session-start?project=engram&workspace=../etc", nil)
+	w := httptest.NewRecorder()
+
+	service.handleSessionStartContextStatic(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, server.req, "gRPC must not be called for an invalid workspace")
+}
+
+func TestHandleSessionStartContextStatic_Modes(t *testing.T) {
+	t.Parallel()
+
+	newServer := func() *stubSessionStartContextServer {
+		return &stubSessionStartContextServer{resp: &pb.GetSessionStartContextResponse{
+			Issues:   []*pb.SessionStartIssue{{Id: 1, Title: "Issue title"}},
+			Rules:    []*pb.SessionStartRule{{Id: 2, Content: "Rule content"}},
+			Memories: []*pb.SessionStartMemory{{Id: 3, Content: "Memory content"}},
+		}}
+	}
+	get := func(server *stubSessionStartContextServer, mode string) (*httptest.ResponseRecorder, sessionStartCompatibilityResponse) {
+		service := &Service{grpcInternalServer: server}
+		w := httptest.NewRecorder()
+		service.handleSessionStartContextStatic(w, httptest.NewRequest(http.MethodGet, "/api/context/session-start?project=engram&mode="+mode, nil))
+		var body sessionStartCompatibilityResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		}
+		return w, body
+	}
+
+	none := newServer()
+	w, body := get(none, "none")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, none.req, "mode=none must not build the context")
+	assert.Empty(t, body.Rules)
+	assert.Empty(t, body.Memories)
+
+	_, body = get(newServer(), "pinned")
+	assert.Empty(t, body.Issues)
+	assert.Len(t, body.Rules, 1, "rules are kept")
+	assert.Empty(t, body.Memories, "only pinned memories are injected")
+
+	_, body = get(newServer(), "digest")
+	assert.Empty(t, body.Issues)
+	assert.Len(t, body.Rules, 1)
+	assert.Empty(t, body.Memories)
+
+	w, _ = get(newServer(), "everything")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestWorkspaceMemoriesToMaps(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	maps := workspaceMemoriesToMaps([]*models.Memory{{
+		ID:        7,
+		Project:   "ws-root",
+		Content:   "Use pnpm across all packages",
+		Tags:      []string{"scope:workspace"},
+		Version:   1,
+		CreatedAt: created,
+		UpdatedAt: created,
+	}})
+
+	require.Len(t, maps, 1)
+	assert.Equal(t, int64(7), maps[0]["id"])
+	assert.Equal(t, "ws-root", maps[0]["project"])
+	assert.Equal(t, "workspace", maps[0]["scope"])
+	assert.Equal(t, "2026-10-01T12:00:00Z", maps[0]["created_at"])
+}
+
 func mustProtoTimestamp(t *testing.T, iso string) *timestamppb.Timestamp {
 	t.Helper()
 	parsed, err := time.Parse(time.RFC3339, iso)
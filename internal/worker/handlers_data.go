@@ -10,12 +10,14 @@ import (
 
 	"github.com/rs/zerolog/log"
 	"github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/internal/privacy"
+	"github.com/thebtf/engram/internal/tenant"
 	"github.com/thebtf/engram/pkg/models"
 )
 
 // handleGetObservations godoc
 // @Summary List observations
-// @Description Returns recent observations with optional fallback search. Filters by type, status, memory type, and concept, then applies corrected pagination after in-memory filtering.
+// @Description Returns recent observations with optional fallback search. Filters by type, status, memory type, and concept, then applies corrected pagination after in-memory filtering. Responses carry an ETag; polling clients revalidate with If-None-Match and get 304 while nothing changed.
 // @Tags Observations
 // @Produce json
 // @Security ApiKeyAuth
@@ -30,6 +32,7 @@ import (
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {string} string "bad request"
 // @Failure 500 {string} string "internal error"
+// @Success 304 {string} string "not modified: If-None-Match holds the current ETag"
 // @Router /api/observations [get]
 func (s *Service) handleGetObservations(w http.ResponseWriter, r *http.Request) {
 	pagination := gorm.ParsePaginationParams(r, DefaultObservationsLimit)
@@ -154,7 +157,7 @@ func (s *Service) handleGetObservations(w http.ResponseWriter, r *http.Request)
 	}
 
 	if query != "" {
-		s.trackSearchQuery(query, project, "observations", len(pageItems), float32(time.Since(searchStart).Milliseconds()))
+		s.trackSearchQuery(r.Context(), query, project, "observations", len(pageItems), float32(time.Since(searchStart).Milliseconds()))
 	}
 
 	resp := map[string]any{
@@ -168,7 +171,7 @@ func (s *Service) handleGetObservations(w http.ResponseWriter, r *http.Request)
 	if project != "" {
 		resp["project_display_name"] = s.getProjectDisplayName(r.Context(), project)
 	}
-	writeJSON(w, resp)
+	writeJSONCacheable(w, r, resp, pollCacheControl)
 }
 
 // handleGetProjects godoc
@@ -231,13 +234,14 @@ func (s *Service) handleGetModels(w http.ResponseWriter, _ *http.Request) {
 
 // handleGetStats godoc
 // @Summary Get worker statistics
-// @Description Returns comprehensive worker statistics including uptime, memory, database health, vector cache, graph, and rate limiter stats.
+// @Description Returns comprehensive worker statistics including uptime, memory, database health, vector cache, graph, rate limiter and pre-storage redaction stats. Cacheable for 1 second. The ETag covers the counters only, not uptime, runtime, cache or database gauges, so If-None-Match revalidation gets 304 while the counters are unchanged.
 // @Tags Analytics
 // @Produce json
 // @Security ApiKeyAuth
 // @Param project query string false "Filter stats by project"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {string} string "bad request"
+// @Success 304 {string} string "not modified: If-None-Match holds the current ETag"
 // @Router /api/stats [get]
 func (s *Service) handleGetStats(w http.ResponseWriter, r *http.Request) {
 	project := r.URL.Query().Get("project")
@@ -251,18 +255,46 @@ func (s *Service) handleGetStats(w http.ResponseWriter, r *http.Request) {
 	retrievalStats := s.GetRetrievalStats(project)
 	sessionsToday, _ := s.sessionStore.GetSessionsToday(r.Context())
 
+	isProcessing, queueDepth := s.processingState()
 	response := map[string]any{
-		"uptime":           time.Since(s.startTime).String(),
-		"uptimeSeconds":    time.Since(s.startTime).Seconds(),
 		"activeSessions":   s.sessionManager.GetActiveSessionCount(),
-		"queueDepth":       s.sessionManager.GetTotalQueueDepth(),
-		"isProcessing":     s.sessionManager.IsAnySessionProcessing(),
+		"queueDepth":       queueDepth,
+		"isProcessing":     isProcessing,
 		"connectedClients": s.sseBroadcaster.ClientCount(),
 		"sessionsToday":    sessionsToday,
 		"retrieval":        retrievalStats,
 		"ready":            s.ready.Load(),
+		// Values redacted before storage since startup
+		"redactions": privacy.Stats(project),
 	}
 
+	s.initMu.RLock()
+	processingPool := s.processingPool
+	s.initMu.RUnlock()
+	if processingPool != nil {
+		response["processingPool"] = processingPool.Stats()
+	}
+
+	// Include project-specific observation count if project is specified
+	if project != "" {
+		count, err := s.getCachedObservationCount(r.Context(), project)
+		if err == nil {
+			response["projectObservations"] = count
+			response["project"] = project
+		}
+	}
+
+	// The ETag covers only the counters above. Uptime, runtime, cache and
+	// database gauges below differ on every request; an ETag over them would
+	// never match, so they are left out and a poller whose counters have not
+	// changed gets 304 before any of them is gathered or encoded.
+	if notModified(w, r, versionETag(tenant.FromContext(r.Context()), response), statsCacheControl) {
+		return
+	}
+
+	response["uptime"] = time.Since(s.startTime).String()
+	response["uptimeSeconds"] = time.Since(s.startTime).Seconds()
+
 	// Add memory stats
 	var memStats runtime.MemStats
 	runtime.ReadMemStats(&memStats)
@@ -277,6 +309,7 @@ func (s *Service) handleGetStats(w http.ResponseWriter, r *http.Request) {
 		"gc_cycles":         memStats.NumGC,
 		"gc_pause_total_ms": float64(memStats.PauseTotalNs) / 1e6,
 	}
+	response["caches"] = s.cacheUsage()
 
 	// Add database health if available
 	if s.store != nil {
@@ -289,18 +322,6 @@ func (s *Service) handleGetStats(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// observationCount was backed by the removed observations store in v5.
-	// Keep only projectObservations, which now comes from v5 stores via cache.
-
-	// Include project-specific observation count if project is specified
-	if project != "" {
-		count, err := s.getCachedObservationCount(r.Context(), project)
-		if err == nil {
-			response["projectObservations"] = count
-			response["project"] = project
-		}
-	}
-
 	// Add rate limiter stats
 	if s.rateLimiter != nil {
 		response["rateLimiter"] = s.rateLimiter.Stats()
@@ -311,13 +332,14 @@ func (s *Service) handleGetStats(w http.ResponseWriter, r *http.Request) {
 
 // handleGetRetrievalStats godoc
 // @Summary Get retrieval statistics
-// @Description Returns detailed retrieval statistics including hit rates and latency.
+// @Description Returns detailed retrieval statistics including hit rates and latency. Responses carry an ETag; polling clients revalidate with If-None-Match and get 304 while nothing changed.
 // @Tags Analytics
 // @Produce json
 // @Security ApiKeyAuth
 // @Param project query string false "Filter by project"
 // @Param since query string false "ISO8601 timestamp for time range filter"
 // @Success 200 {object} map[string]interface{}
+// @Success 304 {string} string "not modified: If-None-Match holds the current ETag"
 // @Router /api/stats/retrieval [get]
 func (s *Service) handleGetRetrievalStats(w http.ResponseWriter, r *http.Request) {
 	project := r.URL.Query().Get("project")
@@ -336,7 +358,7 @@ func (s *Service) handleGetRetrievalStats(w http.ResponseWriter, r *http.Request
 		}
 		dbStats, err := logStore.GetStats(r.Context(), project, since)
 		if err == nil {
-			writeJSON(w, dbStats)
+			writeJSONCacheable(w, r, dbStats, pollCacheControl)
 			return
 		}
 		log.Warn().Err(err).Msg("failed to get retrieval stats from DB, falling back to in-memory")
@@ -344,6 +366,45 @@ func (s *Service) handleGetRetrievalStats(w http.ResponseWriter, r *http.Request
 
 	// Fallback to in-memory stats (no time range support).
 	stats := s.GetRetrievalStats(project)
+	writeJSONCacheable(w, r, stats, pollCacheControl)
+}
+
+// handleGetStorageStats godoc
+// @Summary Get storage statistics
+// @Description Returns the database size, the largest tables with their index sizes, attachment size, and per-project row and byte counts with growth over the last 30 days. Covers every tenant, so it requires admin access.
+// @Tags Analytics
+// @Produce json
+// @Security ApiKeyAuth
+// @Param project query string false "Limit the per-project breakdown to this project"
+// @Success 200 {object} gorm.StorageStats
+// @Failure 400 {string} string "bad request"
+// @Failure 403 {string} string "admin access required"
+// @Failure 500 {string} string "internal error"
+// @Failure 503 {string} string "storage stats not available"
+// @Router /api/stats/storage [get]
+func (s *Service) handleGetStorageStats(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminIdentity(w, r) {
+		return
+	}
+	s.initMu.RLock()
+	store := s.storageStatsStore
+	s.initMu.RUnlock()
+	if store == nil {
+		http.Error(w, "storage stats not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	project := r.URL.Query().Get("project")
+	if err := ValidateProjectName(project); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stats, err := store.Stats(r.Context(), project)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	writeJSON(w, stats)
 }
 
@@ -383,6 +444,48 @@ func (s *Service) handleGetRecentQueries(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// handleSearchPrompts godoc
+// @Summary Search past user prompts
+// @Description Full-text searches the user prompts sent for context injection, best match first. Snippets wrap matched words in **. The query supports "quoted phrases", OR and -exclusions.
+// @Tags Analytics
+// @Produce json
+// @Security ApiKeyAuth
+// @Param query query string true "Search query"
+// @Param project query string false "Filter by project"
+// @Param limit query int false "Number of results (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {string} string "query required"
+// @Failure 503 {string} string "search query log not available"
+// @Router /api/search/prompts [get]
+func (s *Service) handleSearchPrompts(w http.ResponseWriter, r *http.Request) {
+	s.initMu.RLock()
+	store := s.searchQueryLogStore
+	s.initMu.RUnlock()
+
+	query := strings.TrimSpace(r.URL.Query().Get("query"))
+	if query == "" {
+		http.Error(w, "query required", http.StatusBadRequest)
+		return
+	}
+	if store == nil {
+		http.Error(w, "search query log not available", http.StatusServiceUnavailable)
+		return
+	}
+	project := r.URL.Query().Get("project")
+
+	matches, err := store.SearchPrompts(r.Context(), project, query, gorm.ParseLimitParamWithMax(r, 20, 100))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]any{
+		"prompts": matches,
+		"count":   len(matches),
+		"query":   query,
+		"project": project,
+	})
+}
+
 // handleGetSearchAnalytics godoc
 // @Summary Get search analytics
 // @Description Returns aggregated search analytics from the persistent search query log, including vector search counts, latency, and zero-result rate. Supports optional time-range filtering via the 'since' parameter.
@@ -12,6 +12,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	gormdb "github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/pkg/models"
 )
 
 // handleListIssues handles GET /api/issues with optional filters.
@@ -319,6 +320,12 @@ func (s *Service) handleDeleteIssue(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var before, project string
+	if issue, _, getErr := s.issueStore.GetIssue(r.Context(), id); getErr == nil {
+		before = fmt.Sprintf("[%s] %s", issue.Status, issue.Title)
+		project = issue.TargetProject
+	}
+
 	if err := s.issueStore.DeleteIssue(r.Context(), id); err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			http.Error(w, `{"error": "issue not found"}`, http.StatusNotFound)
@@ -329,6 +336,7 @@ func (s *Service) handleDeleteIssue(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log.Info().Int64("issue_id", id).Msg("Issue deleted by operator")
+	s.recordAudit(r, models.AuditOpIssueDelete, project, before, idStr)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -0,0 +1,214 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	authpkg "github.com/thebtf/engram/internal/auth"
+	"github.com/thebtf/engram/internal/worker/eventprune"
+	"github.com/thebtf/engram/internal/worker/jobs"
+	"github.com/thebtf/engram/internal/worker/relinfer"
+)
+
+// jobKindMaintenance is the job kind of the database maintenance pass.
+const jobKindMaintenance = "maintenance"
+
+// runMaintenance is the maintenance job body: purge expired soft-deleted
+// projects, prune expired event log rows, infer relations for recently
+// changed projects, then refresh planner statistics.
+func (s *Service) runMaintenance(ctx context.Context, progress func(string)) (any, error) {
+	s.initMu.RLock()
+	store := s.store
+	projectReaper := s.projectReaper
+	relationInferrer := s.relationInferrer
+	eventPruner := s.eventPruner
+	s.initMu.RUnlock()
+	if store == nil {
+		return nil, fmt.Errorf("database not ready")
+	}
+
+	start := time.Now()
+	var steps []string
+
+	if projectReaper != nil {
+		progress("purging expired projects")
+		if err := projectReaper.PurgeOnce(ctx); err != nil {
+			return nil, fmt.Errorf("purge projects: %w", err)
+		}
+		steps = append(steps, "purge_projects")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var pruned *eventprune.Stats
+	if eventPruner != nil {
+		progress("pruning event logs")
+		stats, err := eventPruner.RunOnce(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("prune events: %w", err)
+		}
+		pruned = &stats
+		steps = append(steps, "prune_events")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var inferred *relinfer.Stats
+	if relationInferrer != nil {
+		progress("inferring relations")
+		stats, err := relationInferrer.RunOnce(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("infer relations: %w", err)
+		}
+		inferred = &stats
+		steps = append(steps, "infer_relations")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	progress("analyzing tables")
+	if err := store.Optimize(ctx); err != nil {
+		return nil, fmt.Errorf("optimize: %w", err)
+	}
+	steps = append(steps, "analyze")
+
+	result := map[string]any{
+		"steps":       steps,
+		"duration_ms": time.Since(start).Milliseconds(),
+	}
+	if inferred != nil {
+		result["relations_inferred"] = inferred
+	}
+	if pruned != nil {
+		result["events_pruned"] = pruned
+	}
+	return result, nil
+}
+
+// submitMaintenance starts the maintenance job, or returns the one already
+// running.
+func (s *Service) submitMaintenance() (jobs.Job, error) {
+	job, err := s.jobManager.Submit(jobKindMaintenance, s.runMaintenance)
+	if errors.Is(err, jobs.ErrAlreadyRunning) {
+		return job, nil
+	}
+	return job, err
+}
+
+// requireAdminIdentity rejects tenant-scoped keycards. No identity means auth
+// is disabled (single-user), which is allowed.
+func requireAdminIdentity(w http.ResponseWriter, r *http.Request) bool {
+	if id, ok := authpkg.IdentityFrom(r.Context()); ok && !id.IsAdmin() {
+		http.Error(w, "admin access required", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// handleListJobs godoc
+// @Summary List background jobs
+// @Description Returns running and recently finished background jobs (maintenance, rebuilds), newest first. Jobs are kept in memory and are forgotten on restart.
+// @Tags Jobs
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {string} string "admin access required"
+// @Router /api/jobs [get]
+func (s *Service) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminIdentity(w, r) {
+		return
+	}
+	writeJSON(w, map[string]any{"jobs": s.jobManager.List()})
+}
+
+// handleGetJob godoc
+// @Summary Get background job status
+// @Description Returns the status, progress, result or error of a background job.
+// @Tags Jobs
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Job ID"
+// @Success 200 {object} jobs.Job
+// @Failure 404 {string} string "job not found"
+// @Router /api/jobs/{id} [get]
+func (s *Service) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminIdentity(w, r) {
+		return
+	}
+	job, err := s.jobManager.Get(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, job)
+}
+
+// handleCancelJob godoc
+// @Summary Cancel a background job
+// @Description Requests cancellation of a running job. The job reports status "cancelled" once it stops.
+// @Tags Jobs
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Job ID"
+// @Success 202 {object} jobs.Job
+// @Failure 404 {string} string "job not found"
+// @Failure 409 {string} string "job already finished"
+// @Router /api/jobs/{id}/cancel [post]
+func (s *Service) handleCancelJob(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminIdentity(w, r) {
+		return
+	}
+	job, err := s.jobManager.Cancel(chi.URLParam(r, "id"))
+	switch {
+	case errors.Is(err, jobs.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, jobs.ErrFinished):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(job); err != nil {
+		log.Error().Err(err).Msg("Failed to encode job response")
+	}
+}
+
+// handleTriggerMaintenance godoc
+// @Summary Start database maintenance
+// @Description Starts the maintenance job (expired project purge, relation inference and ANALYZE) in the background and returns its job. If maintenance is already running, the running job is returned.
+// @Tags Jobs
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 202 {object} jobs.Job
+// @Failure 403 {string} string "admin access required"
+// @Router /api/maintenance [post]
+func (s *Service) handleTriggerMaintenance(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminIdentity(w, r) {
+		return
+	}
+	job, err := s.submitMaintenance()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to start maintenance job")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(job); err != nil {
+		log.Error().Err(err).Msg("Failed to encode job response")
+	}
+}
@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/worker/jobs"
+)
+
+func newJobsTestRouter(s *Service) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/api/jobs", s.handleListJobs)
+	r.Get("/api/jobs/{id}", s.handleGetJob)
+	r.Post("/api/jobs/{id}/cancel", s.handleCancelJob)
+	r.Post("/api/maintenance", s.handleTriggerMaintenance)
+	return r
+}
+
+func TestHandleTriggerMaintenance_ReturnsJob(t *testing.T) {
+	t.Parallel()
+
+	s := &Service{jobManager: jobs.NewManager(10)}
+	defer s.jobManager.Close(context.Background())
+	router := newJobsTestRouter(s)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/maintenance", nil))
+	require.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var job jobs.Job
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	require.NotEmpty(t, job.ID)
+	assert.Equal(t, jobKindMaintenance, job.Kind)
+
+	// No database in this test: the job must fail, not hang.
+	require.Eventually(t, func() bool {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/jobs/"+job.ID, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+		return job.Status.Done()
+	}, 2*time.Second, 5*time.Millisecond)
+	assert.Equal(t, jobs.StatusFailed, job.Status)
+	assert.Contains(t, job.Error, "database not ready")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/jobs", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), job.ID)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/jobs/"+job.ID+"/cancel", nil))
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestHandleGetJob_NotFound(t *testing.T) {
+	t.Parallel()
+
+	s := &Service{jobManager: jobs.NewManager(10)}
+	defer s.jobManager.Close(context.Background())
+
+	w := httptest.NewRecorder()
+	newJobsTestRouter(s).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/jobs/job_missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -12,6 +12,11 @@ import (
 	"github.com/rs/zerolog/log"
 	gormlib "gorm.io/gorm"
 
+	"github.com/thebtf/engram/internal/capture"
+	"github.com/thebtf/engram/internal/concepts"
+	"github.com/thebtf/engram/internal/config"
+	"github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/internal/privacy"
 	"github.com/thebtf/engram/pkg/models"
 )
 
@@ -21,6 +26,16 @@ type storeMemoryRequest struct {
 	Content     string   `json:"content"`
 	Tags        []string `json:"tags,omitempty"`
 	SourceAgent string   `json:"source_agent,omitempty"`
+	// Visibility is private, team (default) or public.
+	Visibility string `json:"visibility,omitempty"`
+}
+
+// updateMemoryRequest is the JSON body for PUT /api/memories/{id}.
+// Omitted fields keep their current value.
+type updateMemoryRequest struct {
+	Content  *string   `json:"content,omitempty"`
+	Tags     *[]string `json:"tags,omitempty"`
+	EditedBy string    `json:"edited_by,omitempty"`
 }
 
 // handleStoreMemoryExplicit godoc
@@ -33,6 +48,7 @@ type storeMemoryRequest struct {
 // @Param body body storeMemoryRequest true "Memory to store"
 // @Success 201 {object} models.Memory
 // @Failure 400 {string} string "bad request"
+// @Failure 422 {string} string "blocked by capture policy"
 // @Failure 503 {string} string "service unavailable"
 // @Failure 500 {string} string "internal error"
 // @Router /api/memories [post]
@@ -56,12 +72,28 @@ func (s *Service) handleStoreMemoryExplicit(w http.ResponseWriter, r *http.Reque
 		http.Error(w, "content is required", http.StatusBadRequest)
 		return
 	}
+	if req.Visibility != "" && !models.IsValidVisibility(req.Visibility) {
+		http.Error(w, "visibility must be one of private, team, public", http.StatusBadRequest)
+		return
+	}
+
+	cfg := config.Get()
+	tags := req.Tags
+	if cfg.ConceptExtraction {
+		tags, _ = concepts.Augment(tags, req.Content, cfg.ConceptMinConfidence)
+	}
+
+	if decision := capture.Evaluate(cfg.CapturePolicy, req.Project, capture.Event{Concepts: tags}); !decision.Allowed {
+		http.Error(w, "blocked by capture policy: "+decision.Reason(), http.StatusUnprocessableEntity)
+		return
+	}
 
 	mem := &models.Memory{
 		Project:     req.Project,
-		Content:     req.Content,
-		Tags:        req.Tags,
+		Content:     privacy.RedactForStorage(req.Project, req.Content),
+		Tags:        tags,
 		SourceAgent: req.SourceAgent,
+		Visibility:  models.Visibility(req.Visibility),
 	}
 
 	created, err := s.memoryStore.Create(r.Context(), mem)
@@ -140,6 +172,7 @@ func (s *Service) handleListMemories(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {string} string "invalid id"
 // @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "memory is locked"
 // @Failure 503 {string} string "service unavailable"
 // @Failure 500 {string} string "internal error"
 // @Router /api/memories/{id} [delete]
@@ -149,10 +182,19 @@ func (s *Service) handleDeleteMemoryByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	idStr := chi.URLParam(r, "id")
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil || id <= 0 {
-		http.Error(w, "invalid memory id", http.StatusBadRequest)
+	id, ok := parseMemoryID(w, r)
+	if !ok {
+		return
+	}
+
+	current, err := s.memoryStore.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, gormlib.ErrRecordNotFound) {
+			http.Error(w, "memory not found", http.StatusNotFound)
+			return
+		}
+		log.Error().Err(err).Int64("id", id).Msg("get memory for delete failed")
+		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -161,10 +203,195 @@ func (s *Service) handleDeleteMemoryByID(w http.ResponseWriter, r *http.Request)
 			http.Error(w, "memory not found", http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, gorm.ErrMemoryLocked) {
+			http.Error(w, "memory is locked", http.StatusConflict)
+			return
+		}
 		log.Error().Err(err).Int64("id", id).Msg("delete memory failed")
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
+	s.recordAudit(r, models.AuditOpMemoryDelete, current.Project, current.Content, strconv.FormatInt(id, 10))
 
 	writeJSON(w, map[string]string{"status": "ok"})
 }
+
+// parseMemoryID reads and validates the {id} URL parameter. On failure it writes
+// a 400 response and returns ok=false.
+func parseMemoryID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		http.Error(w, "invalid memory id", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// handleListMemoryRelations godoc
+// @Summary List relations between a project's memories
+// @Description Returns the current relations whose source and target are both active memories of the project, e.g. to link exported notes.
+// @Tags Memories
+// @Produce json
+// @Security ApiKeyAuth
+// @Param project query string true "Project identifier"
+// @Success 200 {array} models.ObservationRelation
+// @Failure 400 {string} string "project is required"
+// @Failure 503 {string} string "service unavailable"
+// @Failure 500 {string} string "internal error"
+// @Router /api/memories/relations [get]
+func (s *Service) handleListMemoryRelations(w http.ResponseWriter, r *http.Request) {
+	s.initMu.RLock()
+	relationStore := s.relationStore
+	s.initMu.RUnlock()
+	if relationStore == nil {
+		http.Error(w, "relation store not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	project := r.URL.Query().Get("project")
+	if project == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+
+	relations, err := relationStore.GetProjectMemoryRelations(r.Context(), project)
+	if err != nil {
+		log.Error().Err(err).Str("project", project).Msg("list memory relations failed")
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if relations == nil {
+		relations = []*models.ObservationRelation{}
+	}
+	writeJSON(w, relations)
+}
+
+// handleGetMemoryByID godoc
+// @Summary Get a memory note by ID
+// @Description Returns a single active memory entry.
+// @Tags Memories
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Memory ID"
+// @Success 200 {object} models.Memory
+// @Failure 400 {string} string "invalid id"
+// @Failure 404 {string} string "not found"
+// @Failure 503 {string} string "service unavailable"
+// @Failure 500 {string} string "internal error"
+// @Router /api/memories/{id} [get]
+func (s *Service) handleGetMemoryByID(w http.ResponseWriter, r *http.Request) {
+	if s.memoryStore == nil {
+		http.Error(w, "memory store not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	id, ok := parseMemoryID(w, r)
+	if !ok {
+		return
+	}
+
+	mem, err := s.memoryStore.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, gormlib.ErrRecordNotFound) {
+			http.Error(w, "memory not found", http.StatusNotFound)
+			return
+		}
+		log.Error().Err(err).Int64("id", id).Msg("get memory failed")
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	s.initMu.RLock()
+	accessStore := s.memoryAccessStore
+	s.initMu.RUnlock()
+	accessStore.Record(gorm.AccessGet, "", []int64{id})
+
+	writeJSON(w, mem)
+}
+
+// handleUpdateMemoryByID godoc
+// @Summary Update a memory note by ID
+// @Description Replaces the content and/or tags of a memory. The previous state is kept in the memory's revision history.
+// @Tags Memories
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Memory ID"
+// @Param body body updateMemoryRequest true "Fields to change"
+// @Success 200 {object} models.Memory
+// @Failure 400 {string} string "bad request"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "memory is locked"
+// @Failure 503 {string} string "service unavailable"
+// @Failure 500 {string} string "internal error"
+// @Router /api/memories/{id} [put]
+func (s *Service) handleUpdateMemoryByID(w http.ResponseWriter, r *http.Request) {
+	if s.memoryStore == nil {
+		http.Error(w, "memory store not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	id, ok := parseMemoryID(w, r)
+	if !ok {
+		return
+	}
+
+	var req updateMemoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Content == nil && req.Tags == nil {
+		http.Error(w, "content or tags is required", http.StatusBadRequest)
+		return
+	}
+	if req.Content != nil && *req.Content == "" {
+		http.Error(w, "content must not be empty", http.StatusBadRequest)
+		return
+	}
+
+	current, err := s.memoryStore.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, gormlib.ErrRecordNotFound) {
+			http.Error(w, "memory not found", http.StatusNotFound)
+			return
+		}
+		log.Error().Err(err).Int64("id", id).Msg("get memory for update failed")
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	next := &models.Memory{
+		ID:          id,
+		Content:     current.Content,
+		Tags:        current.Tags,
+		SourceAgent: current.SourceAgent,
+		EditedBy:    req.EditedBy,
+	}
+	if req.Content != nil {
+		next.Content = privacy.RedactForStorage(current.Project, *req.Content)
+	}
+	if req.Tags != nil {
+		next.Tags = *req.Tags
+	}
+
+	updated, err := s.memoryStore.Update(r.Context(), next)
+	if err != nil {
+		if errors.Is(err, gormlib.ErrRecordNotFound) {
+			http.Error(w, "memory not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, gorm.ErrMemoryLocked) {
+			http.Error(w, "memory is locked", http.StatusConflict)
+			return
+		}
+		log.Error().Err(err).Int64("id", id).Msg("update memory failed")
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if next.Content != current.Content {
+		s.recordAudit(r, models.AuditOpMemoryEdit, current.Project, current.Content, strconv.FormatInt(id, 10))
+	}
+
+	writeJSON(w, updated)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -160,3 +161,59 @@ func TestHandleDeleteMemoryByID_NotFound(t *testing.T) {
 
 	require.Equal(t, http.StatusNotFound, deleteW.Code)
 }
+
+func TestHandleUpdateMemoryByID_RoundTrip(t *testing.T) {
+	project := "test-memory-handler-update-" + uuid.NewString()
+	service := newMemoryTestService(t, project)
+
+	storeReq := httptest.NewRequest(http.MethodPost, "/api/memories", bytes.NewReader([]byte(`{"project":"`+project+`","content":"before","tags":["keep"]}`)))
+	storeW := httptest.NewRecorder()
+	service.handleStoreMemoryExplicit(storeW, storeReq)
+	require.Equal(t, http.StatusCreated, storeW.Code)
+
+	var created models.Memory
+	require.NoError(t, json.Unmarshal(storeW.Body.Bytes(), &created))
+	idStr := strconv.FormatInt(created.ID, 10)
+
+	updateReq := newCHIRequest(http.MethodPut, "/api/memories/"+idStr, "id", idStr)
+	updateReq.Body = io.NopCloser(bytes.NewReader([]byte(`{"content":"after","edited_by":"cli"}`)))
+	updateW := httptest.NewRecorder()
+	service.handleUpdateMemoryByID(updateW, updateReq)
+	require.Equal(t, http.StatusOK, updateW.Code)
+
+	getReq := newCHIRequest(http.MethodGet, "/api/memories/"+idStr, "id", idStr)
+	getW := httptest.NewRecorder()
+	service.handleGetMemoryByID(getW, getReq)
+	require.Equal(t, http.StatusOK, getW.Code)
+
+	var got models.Memory
+	require.NoError(t, json.Unmarshal(getW.Body.Bytes(), &got))
+	assert.Equal(t, "after", got.Content)
+	assert.Equal(t, []string{"keep"}, got.Tags, "omitted tags must be preserved")
+	assert.Equal(t, "cli", got.EditedBy)
+	assert.Equal(t, 2, got.Version)
+}
+
+func TestHandleUpdateMemoryByID_Validation(t *testing.T) {
+	service := &Service{memoryStore: &dbgorm.MemoryStore{}}
+
+	tests := []struct {
+		name string
+		id   string
+		body string
+	}{
+		{name: "bad id", id: "abc", body: `{"content":"x"}`},
+		{name: "no fields", id: "1", body: `{}`},
+		{name: "empty content", id: "1", body: `{"content":""}`},
+		{name: "invalid json", id: "1", body: `{`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := newCHIRequest(http.MethodPut, "/api/memories/"+tc.id, "id", tc.id)
+			req.Body = io.NopCloser(bytes.NewReader([]byte(tc.body)))
+			w := httptest.NewRecorder()
+			service.handleUpdateMemoryByID(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
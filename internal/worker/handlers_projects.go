@@ -10,6 +10,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/thebtf/engram/internal/worker/projectevents"
+	"github.com/thebtf/engram/pkg/models"
 )
 
 // handleDeleteProject godoc
@@ -55,6 +56,8 @@ func (s *Service) handleDeleteProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.recordAudit(r, models.AuditOpProjectDelete, id, "project "+id+" (soft delete)", id)
+
 	// Emit the lifecycle event so gRPC subscribers are notified immediately.
 	if s.eventBus != nil {
 		s.eventBus.Emit(projectevents.Event{
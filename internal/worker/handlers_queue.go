@@ -0,0 +1,217 @@
+package worker
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/thebtf/engram/internal/worker/pool"
+	"github.com/thebtf/engram/internal/worker/session"
+)
+
+// queueItem is a pending message as reported by GET /api/queue.
+type queueItem struct {
+	session.QueuedMessage
+	AgeSeconds float64 `json:"age_seconds"`
+}
+
+// queueMessageID parses the {id} URL parameter, writing a 400 when it is
+// not a message ID.
+func queueMessageID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		http.Error(w, "invalid message id", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// handleListQueue godoc
+// @Summary List queued messages
+// @Description Returns the messages waiting in the session queues, oldest first, with their age and session. Messages already handed to the processing pool are counted in pool but not listed.
+// @Tags Queue
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {string} string "admin access required"
+// @Router /api/queue [get]
+func (s *Service) handleListQueue(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminIdentity(w, r) {
+		return
+	}
+	now := time.Now()
+	pending := s.sessionManager.PendingMessages()
+	items := make([]queueItem, 0, len(pending))
+	for _, msg := range pending {
+		items = append(items, queueItem{QueuedMessage: msg, AgeSeconds: now.Sub(msg.QueuedAt).Seconds()})
+	}
+
+	isProcessing, queueDepth := s.processingState()
+	resp := map[string]any{
+		"items":        items,
+		"count":        len(items),
+		"isProcessing": isProcessing,
+		"queueDepth":   queueDepth,
+	}
+	s.initMu.RLock()
+	processingPool := s.processingPool
+	s.initMu.RUnlock()
+	if processingPool != nil {
+		resp["pool"] = processingPool.Stats()
+	}
+	writeJSON(w, resp)
+}
+
+// handleRetryQueued godoc
+// @Summary Process a queued message now
+// @Description Takes a message, together with the messages queued ahead of it in its session, out of the session queue and hands them to the processing pool in order, so a summary never ends a session before its earlier messages are processed. Messages the pool rejects are put back.
+// @Tags Queue
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Message ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {string} string "invalid message id"
+// @Failure 403 {string} string "admin access required"
+// @Failure 404 {string} string "message not queued"
+// @Failure 503 {string} string "processing pool unavailable"
+// @Router /api/queue/{id}/retry [post]
+func (s *Service) handleRetryQueued(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminIdentity(w, r) {
+		return
+	}
+	id, ok := queueMessageID(w, r)
+	if !ok {
+		return
+	}
+	s.initMu.RLock()
+	processingPool := s.processingPool
+	s.initMu.RUnlock()
+	if processingPool == nil {
+		http.Error(w, "processing pool unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	sess, msgs, ok := s.sessionManager.TakeMessagesThrough(id)
+	if !ok {
+		http.Error(w, "message not queued", http.StatusNotFound)
+		return
+	}
+	dispatched, err := s.dispatchQueued(r.Context(), processingPool, sess, msgs)
+	if dispatched > 0 {
+		s.broadcastProcessingStatus()
+	}
+	if err != nil {
+		http.Error(w, "processing pool rejected message: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, map[string]any{"id": id, "status": "dispatched", "dispatched": dispatched})
+}
+
+// dispatchQueued hands msgs, taken in queue order from one session, to the
+// processing pool. ctx bounds the wait for pool capacity. When the pool
+// rejects a message, it and the messages after it go back to the front of
+// the session queue. It returns how many messages were dispatched.
+func (s *Service) dispatchQueued(ctx context.Context, processingPool *pool.Pool, sess *session.ActiveSession, msgs []session.PendingMessage) (int, error) {
+	priorities := messagePriorities(msgs)
+	for i, msg := range msgs {
+		if err := processingPool.SubmitPriority(ctx, sess.SessionDBID, priorities[i], s.processMessageTask(sess, msg)); err != nil {
+			for k := len(msgs) - 1; k >= i; k-- {
+				s.requeueRejected(processingPool, sess.SessionDBID, msgs[k])
+			}
+			return i, err
+		}
+	}
+	return len(msgs), nil
+}
+
+// handleDeleteQueued godoc
+// @Summary Discard a queued message
+// @Description Removes a message from its session queue without processing it.
+// @Tags Queue
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Message ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {string} string "invalid message id"
+// @Failure 403 {string} string "admin access required"
+// @Failure 404 {string} string "message not queued"
+// @Router /api/queue/{id} [delete]
+func (s *Service) handleDeleteQueued(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminIdentity(w, r) {
+		return
+	}
+	id, ok := queueMessageID(w, r)
+	if !ok {
+		return
+	}
+	sess, msg, ok := s.sessionManager.TakeMessage(id)
+	if !ok {
+		http.Error(w, "message not queued", http.StatusNotFound)
+		return
+	}
+	log.Info().Int64("id", id).Int64("sessionId", sess.SessionDBID).Str("type", msg.Type.String()).
+		Msg("Queued message discarded")
+	s.broadcastProcessingStatus()
+	writeJSON(w, map[string]any{"id": id, "status": "deleted"})
+}
+
+// handleDrainStuckQueue godoc
+// @Summary Dispatch stuck queued messages
+// @Description Hands every queued message older than older_than (default: all) to the processing pool, for messages left behind when a session stopped being processed. Messages queued ahead of a dispatched message in its session are dispatched with it, in order.
+// @Tags Queue
+// @Produce json
+// @Security ApiKeyAuth
+// @Param older_than query string false "Minimum age as a Go duration (e.g. 5m)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {string} string "invalid older_than"
+// @Failure 403 {string} string "admin access required"
+// @Failure 503 {string} string "processing pool unavailable"
+// @Router /api/queue/drain [post]
+func (s *Service) handleDrainStuckQueue(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminIdentity(w, r) {
+		return
+	}
+	var olderThan time.Duration
+	if v := r.URL.Query().Get("older_than"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			http.Error(w, "invalid older_than", http.StatusBadRequest)
+			return
+		}
+		olderThan = d
+	}
+	s.initMu.RLock()
+	processingPool := s.processingPool
+	s.initMu.RUnlock()
+	if processingPool == nil {
+		http.Error(w, "processing pool unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	dispatched := 0
+	for _, queued := range s.sessionManager.PendingMessages() {
+		if queued.QueuedAt.After(cutoff) {
+			break // oldest first: the rest are younger
+		}
+		sess, msgs, ok := s.sessionManager.TakeMessagesThrough(queued.ID)
+		if !ok {
+			continue // already dispatched ahead of a later message of its session
+		}
+		n, err := s.dispatchQueued(r.Context(), processingPool, sess, msgs)
+		dispatched += n
+		if err != nil {
+			log.Warn().Err(err).Int64("id", queued.ID).Msg("Processing pool rejected stuck message")
+			break
+		}
+	}
+	if dispatched > 0 {
+		log.Info().Int("dispatched", dispatched).Dur("olderThan", olderThan).Msg("Stuck queue messages dispatched")
+		s.broadcastProcessingStatus()
+	}
+	writeJSON(w, map[string]any{"dispatched": dispatched})
+}
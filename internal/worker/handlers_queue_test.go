@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thebtf/engram/internal/worker/pool"
+	"github.com/thebtf/engram/internal/worker/session"
+)
+
+func queueRequest(method, target, id string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestHandleListQueue_Empty(t *testing.T) {
+	svc := newDrainTestService(t)
+
+	rec := httptest.NewRecorder()
+	svc.handleListQueue(rec, httptest.NewRequest(http.MethodGet, "/api/queue", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	resp := decodeDrainStatus(t, rec)
+	assert.Equal(t, float64(0), resp["count"])
+	assert.Equal(t, []any{}, resp["items"])
+	assert.Contains(t, resp, "pool")
+}
+
+func TestHandleQueueItem_NotQueued(t *testing.T) {
+	svc := newDrainTestService(t)
+
+	rec := httptest.NewRecorder()
+	svc.handleRetryQueued(rec, queueRequest(http.MethodPost, "/api/queue/42/retry", "42"))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	svc.handleDeleteQueued(rec, queueRequest(http.MethodDelete, "/api/queue/42", "42"))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	svc.handleDeleteQueued(rec, queueRequest(http.MethodDelete, "/api/queue/abc", "abc"))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleDrainStuckQueue(t *testing.T) {
+	svc := newDrainTestService(t)
+
+	rec := httptest.NewRecorder()
+	svc.handleDrainStuckQueue(rec, httptest.NewRequest(http.MethodPost, "/api/queue/drain?older_than=5m", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, float64(0), decodeDrainStatus(t, rec)["dispatched"])
+
+	rec = httptest.NewRecorder()
+	svc.handleDrainStuckQueue(rec, httptest.NewRequest(http.MethodPost, "/api/queue/drain?older_than=soon", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestMessagePriorities(t *testing.T) {
+	obs := session.PendingMessage{Type: session.MessageTypeObservation}
+	sum := session.PendingMessage{Type: session.MessageTypeSummarize}
+
+	assert.Equal(t, []pool.Priority{pool.PriorityLow, pool.PriorityLow},
+		messagePriorities([]session.PendingMessage{obs, obs}))
+	assert.Equal(t, []pool.Priority{pool.PriorityHigh, pool.PriorityHigh, pool.PriorityHigh, pool.PriorityLow},
+		messagePriorities([]session.PendingMessage{obs, obs, sum, obs}),
+		"observations queued before a summary inherit its priority")
+}
@@ -12,6 +12,9 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
+	"github.com/thebtf/engram/internal/capture"
+	"github.com/thebtf/engram/internal/config"
+	"github.com/thebtf/engram/internal/db/gorm"
 	"github.com/thebtf/engram/internal/privacy"
 	"github.com/thebtf/engram/internal/sessions"
 	"github.com/thebtf/engram/internal/worker/session"
@@ -103,8 +106,8 @@ func (s *Service) handleSessionInit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Clean prompt
-	cleanedPrompt := privacy.Clean(req.Prompt)
+	// Clean prompt and redact sensitive values before it is stored
+	cleanedPrompt := privacy.RedactForStorage(req.Project, privacy.Clean(req.Prompt))
 
 	// Create session (idempotent)
 	sessionID, err := s.sessionStore.CreateSDKSession(r.Context(), req.ClaudeSessionID, req.Project, cleanedPrompt)
@@ -224,14 +227,33 @@ type ObservationRequest struct {
 // @Success 200 "OK"
 // @Failure 400 {string} string "bad request"
 // @Failure 500 {string} string "internal error"
+// @Failure 503 {string} string "worker draining"
 // @Router /api/sessions/observations [post]
 func (s *Service) handleObservation(w http.ResponseWriter, r *http.Request) {
+	// A draining worker is about to restart; queued work would be lost.
+	if s.draining.Load() {
+		http.Error(w, "worker draining", http.StatusServiceUnavailable)
+		return
+	}
+
 	var req ObservationRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	// Drop events the capture policy excludes before they are queued.
+	event := capture.Event{ToolName: req.ToolName, Files: capture.FilesFromToolInput(req.ToolInput)}
+	if decision := capture.Evaluate(config.Get().CapturePolicy, req.Project, event); !decision.Allowed {
+		log.Debug().
+			Str("project", req.Project).
+			Str("tool", req.ToolName).
+			Str("reason", decision.Reason()).
+			Msg("Observation skipped by capture policy")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
 	// Find session
 	sess, err := s.sessionStore.FindAnySDKSession(r.Context(), req.ClaudeSessionID)
 	if err != nil {
@@ -270,6 +292,10 @@ func (s *Service) handleObservation(w http.ResponseWriter, r *http.Request) {
 type SubagentCompleteRequest struct {
 	ClaudeSessionID string `json:"claudeSessionId"`
 	Project         string `json:"project"`
+	// AgentID and AgentType identify the subagent (Claude Code's agent_id
+	// and agent_type hook fields); empty with older clients.
+	AgentID   string `json:"agentId,omitempty"`
+	AgentType string `json:"agentType,omitempty"`
 }
 
 // handleSubagentComplete godoc
@@ -301,6 +327,12 @@ func (s *Service) handleSubagentComplete(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	log.Debug().
+		Str("claudeSessionId", req.ClaudeSessionID).
+		Str("agentId", req.AgentID).
+		Str("agentType", req.AgentType).
+		Msg("Subagent complete")
+
 	// Drain any queued messages from the subagent session.
 	s.sessionManager.DrainMessages(sess.ID)
 
@@ -310,13 +342,14 @@ func (s *Service) handleSubagentComplete(w http.ResponseWriter, r *http.Request)
 
 // handleListSessions godoc
 // @Summary List SDK sessions
-// @Description Returns a paginated list of SDK sessions, optionally filtered by project.
+// @Description Returns a paginated list of SDK sessions, optionally filtered by project and label.
 // @Tags Sessions
 // @Produce json
 // @Security ApiKeyAuth
 // @Param project query string false "Filter by project path"
 // @Param limit query int false "Page size (default 50)"
 // @Param offset query int false "Offset (default 0)"
+// @Param tag query string false "Only sessions tagged or named with this label"
 // @Success 200 {object} map[string]interface{}
 // @Failure 500 {string} string "internal error"
 // @Router /api/sessions/list [get]
@@ -348,7 +381,7 @@ func (s *Service) handleListSessions(w http.ResponseWriter, r *http.Request) {
 		to, _ = strconv.ParseInt(v, 10, 64)
 	}
 
-	sessions, total, err := s.sessionStore.ListSDKSessions(r.Context(), project, limit, offset, minPrompts, from, to)
+	sessions, total, err := s.sessionStore.ListSDKSessions(r.Context(), project, limit, offset, minPrompts, from, to, r.URL.Query().Get("tag"))
 	if err != nil {
 		http.Error(w, "failed to list sessions", http.StatusInternalServerError)
 		return
@@ -365,6 +398,59 @@ func (s *Service) handleListSessions(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// SessionLabelsRequest is the request body for naming and tagging a session.
+// Omitted fields are left unchanged; Tags replaces the tag set before AddTags
+// and RemoveTags apply.
+type SessionLabelsRequest struct {
+	Name       *string  `json:"name"`
+	Tags       []string `json:"tags"`
+	AddTags    []string `json:"add_tags"`
+	RemoveTags []string `json:"remove_tags"`
+}
+
+// handleLabelSession godoc
+// @Summary Name and tag a session
+// @Description Sets the name and tags of a session of the caller's tenant, so it can be found later by label (GET /api/sessions/list?tag=, recall session_tag). Returns the session's id, name and tags.
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Claude session ID or numeric session ID"
+// @Param body body SessionLabelsRequest true "Name and tag changes"
+// @Success 200 {object} object
+// @Failure 400 {string} string "invalid request"
+// @Failure 404 {string} string "session not found"
+// @Failure 500 {string} string "internal error"
+// @Router /api/sessions/{id} [patch]
+func (s *Service) handleLabelSession(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req SessionLabelsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Name == nil && req.Tags == nil && len(req.AddTags) == 0 && len(req.RemoveTags) == 0 {
+		http.Error(w, "provide name, tags, add_tags or remove_tags", http.StatusBadRequest)
+		return
+	}
+
+	session, err := s.sessionStore.LabelSession(r.Context(), id, gorm.SessionLabels{
+		Name:       req.Name,
+		Tags:       req.Tags,
+		AddTags:    req.AddTags,
+		RemoveTags: req.RemoveTags,
+	})
+	if errors.Is(err, gorm.ErrSessionNotFound) {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "failed to label session", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, session)
+}
+
 // handleGetSessionByClaudeID godoc
 // @Summary Get session by Claude session ID
 // @Description Looks up a session by its Claude session ID.
@@ -519,12 +605,12 @@ func (s *Service) handleIndexSession(w http.ResponseWriter, r *http.Request) {
 		parts = append(parts, exchange.UserText)
 		parts = append(parts, exchange.AssistantText)
 	}
-	content := strings.Join(parts, "\n")
 
 	projectID := ""
 	if meta.ProjectPath != "" {
 		projectID = sessions.ProjectID(meta.ProjectPath)
 	}
+	content := privacy.RedactForStorage(projectID, strings.Join(parts, "\n"))
 
 	// v5 (US3): IndexedSession removed; pass metadata as a plain map.
 	indexedMap := map[string]any{
@@ -7,9 +7,7 @@ import (
 // handleGetConfig returns the current runtime configuration, grouped by category.
 // Secrets (API keys, DSN, encryption keys) are redacted.
 func (s *Service) handleGetConfig(w http.ResponseWriter, _ *http.Request) {
-	s.initMu.RLock()
-	cfg := s.config
-	s.initMu.RUnlock()
+	cfg := s.currentConfig()
 
 	if cfg == nil {
 		http.Error(w, "config not available", http.StatusServiceUnavailable)
@@ -29,6 +27,7 @@ func (s *Service) handleGetConfig(w http.ResponseWriter, _ *http.Request) {
 			"inject_unified":       cfg.InjectUnified,
 			"always_inject_limit":  cfg.AlwaysInjectLimit,
 			"project_inject_limit": cfg.ProjectInjectLimit,
+			"pinned_inject_limit":  cfg.PinnedInjectLimit,
 		},
 		"storage": map[string]any{
 			"vector_strategy":    cfg.VectorStorageStrategy,
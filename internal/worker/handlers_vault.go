@@ -306,6 +306,7 @@ func (s *Service) handleDeleteCredential(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
+	s.recordAudit(r, models.AuditOpCredentialDelete, project, "credential "+name, name)
 
 	writeJSON(w, map[string]any{
 		"deleted": true,
@@ -346,6 +347,10 @@ func (s *Service) handleDeleteOrphanedCredentials(w http.ResponseWriter, r *http
 		http.Error(w, "failed to delete orphaned credentials", http.StatusInternalServerError)
 		return
 	}
+	if deleted > 0 {
+		s.recordAudit(r, models.AuditOpCredentialPurgeOrphaned, "",
+			fmt.Sprintf("%d credentials encrypted with a key other than %s", deleted, fingerprint))
+	}
 
 	writeJSON(w, map[string]any{
 		"status":  "ok",
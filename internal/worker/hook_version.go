@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// MinHookVersion is the oldest plugin version whose hooks speak the protocol
+// this worker serves. Raise it together with a breaking change to a hook
+// endpoint; older hooks are then told to update.
+const MinHookVersion = "6.0.0"
+
+// Hook version handshake headers. Hooks send their plugin version; the
+// worker answers every such request with the minimum it requires, and flags
+// the response when the hooks are older.
+const (
+	hookVersionHeader    = "X-Engram-Hook-Version"
+	minHookVersionHeader = "X-Engram-Min-Hook-Version"
+	hookOutdatedHeader   = "X-Engram-Hook-Outdated"
+)
+
+// outdatedHookVersions records the hook versions already warned about, so a
+// stale install logs once instead of on every hook call.
+var outdatedHookVersions sync.Map
+
+// HookVersion middleware implements the hook version handshake. Outdated
+// hooks are still served: the hooks decide how to surface the mismatch.
+func HookVersion(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if version := strings.TrimSpace(r.Header.Get(hookVersionHeader)); version != "" {
+			w.Header().Set(minHookVersionHeader, MinHookVersion)
+			if compareVersions(version, MinHookVersion) < 0 {
+				w.Header().Set(hookOutdatedHeader, "true")
+				if _, seen := outdatedHookVersions.LoadOrStore(version, true); !seen {
+					log.Warn().
+						Str("hook_version", version).
+						Str("min_hook_version", MinHookVersion).
+						Msg("Outdated engram hooks connected; update the plugin")
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// compareVersions compares two dotted versions ("6.1.0", "v6.1"), returning
+// -1, 0 or 1. A pre-release or build suffix ("-rc1", "+dev") is ignored and
+// missing components count as zero.
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(v string) []int {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	fields := strings.Split(v, ".")
+	parts := make([]int, len(fields))
+	for i, f := range fields {
+		parts[i], _ = strconv.Atoi(f)
+	}
+	return parts
+}
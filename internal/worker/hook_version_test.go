@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"6.0.0", "6.0.0", 0},
+		{"v6.0", "6.0.0", 0},
+		{"5.9.9", "6.0.0", -1},
+		{"6.10.0", "6.9.0", 1},
+		{"6.1.0-rc1", "6.1.0", 0},
+		{"7", "6.99.99", 1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestHookVersionMiddleware(t *testing.T) {
+	handler := HookVersion(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(version string) http.Header {
+		req := httptest.NewRequest(http.MethodGet, "/api/context/search", nil)
+		if version != "" {
+			req.Header.Set(hookVersionHeader, version)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, outdated hooks must still be served", rec.Code)
+		}
+		return rec.Header()
+	}
+
+	if h := serve(""); h.Get(minHookVersionHeader) != "" {
+		t.Errorf("non-hook request got %s", minHookVersionHeader)
+	}
+	current := serve(MinHookVersion)
+	if current.Get(minHookVersionHeader) != MinHookVersion || current.Get(hookOutdatedHeader) != "" {
+		t.Errorf("current hooks: headers = %v", current)
+	}
+	if h := serve("5.0.0"); h.Get(hookOutdatedHeader) != "true" {
+		t.Errorf("old hooks not flagged: headers = %v", h)
+	}
+}
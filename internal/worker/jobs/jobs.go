@@ -0,0 +1,267 @@
+// Package jobs tracks long-running background operations (maintenance,
+// consolidation, index rebuilds) so callers get a job ID back immediately and
+// can poll or cancel the work instead of holding a request open.
+//
+// Jobs live in memory only: a server restart forgets them. Finished jobs are
+// kept for inspection until more than Manager's retention limit accumulate,
+// then the oldest finished ones are dropped.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Status is the lifecycle state of a job.
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Done reports whether the status is terminal.
+func (s Status) Done() bool {
+	return s != StatusRunning
+}
+
+var (
+	// ErrNotFound is returned for an unknown job ID.
+	ErrNotFound = errors.New("job not found")
+	// ErrFinished is returned when cancelling a job that already ended.
+	ErrFinished = errors.New("job already finished")
+	// ErrAlreadyRunning is returned by Submit when a job of the same kind is
+	// still running; the running job is returned alongside it.
+	ErrAlreadyRunning = errors.New("a job of this kind is already running")
+	// ErrClosed is returned by Submit after Close has been called.
+	ErrClosed = errors.New("job manager closed")
+)
+
+// defaultRetainFinished is how many finished jobs are kept when NewManager is
+// given a non-positive limit.
+const defaultRetainFinished = 100
+
+// Func is the body of a job. It must return promptly once ctx is cancelled.
+// progress may be called at any time to publish a human-readable status line.
+type Func func(ctx context.Context, progress func(message string)) (any, error)
+
+// Job is a point-in-time snapshot of a background operation.
+type Job struct {
+	ID         string     `json:"id"`
+	Kind       string     `json:"kind"`
+	Status     Status     `json:"status"`
+	Progress   string     `json:"progress,omitempty"`
+	Result     any        `json:"result,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+type entry struct {
+	job    Job
+	cancel context.CancelFunc
+}
+
+// Manager runs jobs in their own goroutines and keeps their state.
+type Manager struct {
+	ctx            context.Context
+	cancel         context.CancelFunc
+	jobs           map[string]*entry
+	wg             sync.WaitGroup
+	mu             sync.Mutex
+	retainFinished int
+	closed         bool
+}
+
+// NewManager returns a Manager that keeps at most retainFinished finished jobs.
+func NewManager(retainFinished int) *Manager {
+	if retainFinished <= 0 {
+		retainFinished = defaultRetainFinished
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		ctx:            ctx,
+		cancel:         cancel,
+		jobs:           make(map[string]*entry),
+		retainFinished: retainFinished,
+	}
+}
+
+// Submit starts fn in the background and returns its job immediately. Only one
+// job of a given kind runs at a time: while one is running, Submit returns that
+// job together with ErrAlreadyRunning.
+func (m *Manager) Submit(kind string, fn Func) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return Job{}, ErrClosed
+	}
+	for _, e := range m.jobs {
+		if e.job.Kind == kind && !e.job.Status.Done() {
+			return e.job, ErrAlreadyRunning
+		}
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	e := &entry{
+		job: Job{
+			ID:        newJobID(),
+			Kind:      kind,
+			Status:    StatusRunning,
+			CreatedAt: time.Now().UTC(),
+		},
+		cancel: cancel,
+	}
+	m.jobs[e.job.ID] = e
+	m.wg.Add(1)
+	go m.run(ctx, e, fn)
+
+	log.Info().Str("job_id", e.job.ID).Str("kind", kind).Msg("Job started")
+	return e.job, nil
+}
+
+func (m *Manager) run(ctx context.Context, e *entry, fn Func) {
+	defer m.wg.Done()
+	defer e.cancel()
+
+	progress := func(message string) {
+		m.mu.Lock()
+		e.job.Progress = message
+		m.mu.Unlock()
+	}
+
+	var (
+		result any
+		err    error
+	)
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = errors.New("job panicked")
+				log.Error().Interface("panic", r).Str("job_id", e.job.ID).Msg("Job panicked")
+			}
+		}()
+		result, err = fn(ctx, progress)
+	}()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now().UTC()
+	e.job.FinishedAt = &now
+	switch {
+	case ctx.Err() != nil:
+		e.job.Status = StatusCancelled
+		if err != nil && !errors.Is(err, context.Canceled) {
+			e.job.Error = err.Error()
+		}
+	case err != nil:
+		e.job.Status = StatusFailed
+		e.job.Error = err.Error()
+	default:
+		e.job.Status = StatusSucceeded
+		e.job.Result = result
+	}
+	log.Info().Str("job_id", e.job.ID).Str("kind", e.job.Kind).Str("status", string(e.job.Status)).
+		Dur("duration", now.Sub(e.job.CreatedAt)).Msg("Job finished")
+	m.pruneLocked()
+}
+
+// Get returns the job with the given ID.
+func (m *Manager) Get(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	return e.job, nil
+}
+
+// List returns all known jobs, newest first.
+func (m *Manager) List() []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]Job, 0, len(m.jobs))
+	for _, e := range m.jobs {
+		out = append(out, e.job)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].CreatedAt.After(out[j].CreatedAt)
+	})
+	return out
+}
+
+// Cancel requests cancellation of a running job. The job's status becomes
+// cancelled once its function returns.
+func (m *Manager) Cancel(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	if e.job.Status.Done() {
+		return e.job, ErrFinished
+	}
+	e.cancel()
+	return e.job, nil
+}
+
+// Close cancels all running jobs and waits for them to return or for ctx to
+// expire. Submit fails with ErrClosed afterwards.
+func (m *Manager) Close(ctx context.Context) error {
+	m.mu.Lock()
+	m.closed = true
+	m.mu.Unlock()
+	m.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pruneLocked drops the oldest finished jobs beyond the retention limit.
+func (m *Manager) pruneLocked() {
+	var finished []*entry
+	for _, e := range m.jobs {
+		if e.job.Status.Done() {
+			finished = append(finished, e)
+		}
+	}
+	if len(finished) <= m.retainFinished {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].job.FinishedAt.Before(*finished[j].job.FinishedAt)
+	})
+	for _, e := range finished[:len(finished)-m.retainFinished] {
+		delete(m.jobs, e.job.ID)
+	}
+}
+
+// newJobID returns a random identifier such as "job_3f9a0c1b2d4e5f60".
+func newJobID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "job_" + time.Now().UTC().Format("20060102150405.000000000")
+	}
+	return "job_" + hex.EncodeToString(b[:])
+}
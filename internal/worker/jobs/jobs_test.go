@@ -0,0 +1,143 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitDone polls until the job reaches a terminal status.
+func waitDone(t *testing.T, m *Manager, id string) Job {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := m.Get(id)
+		if err != nil {
+			t.Fatalf("Get(%s): %v", id, err)
+		}
+		if job.Status.Done() {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return Job{}
+}
+
+func TestManager_Succeeds(t *testing.T) {
+	m := NewManager(10)
+	defer m.Close(context.Background())
+
+	job, err := m.Submit("maintenance", func(ctx context.Context, progress func(string)) (any, error) {
+		progress("halfway")
+		return map[string]int{"purged": 3}, nil
+	})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if job.ID == "" || job.Status != StatusRunning {
+		t.Fatalf("unexpected submitted job: %+v", job)
+	}
+
+	done := waitDone(t, m, job.ID)
+	if done.Status != StatusSucceeded {
+		t.Fatalf("status = %s, want succeeded", done.Status)
+	}
+	if done.Progress != "halfway" || done.FinishedAt == nil || done.Result == nil {
+		t.Fatalf("unexpected finished job: %+v", done)
+	}
+}
+
+func TestManager_Fails(t *testing.T) {
+	m := NewManager(10)
+	defer m.Close(context.Background())
+
+	job, _ := m.Submit("rebuild", func(context.Context, func(string)) (any, error) {
+		return nil, errors.New("boom")
+	})
+	done := waitDone(t, m, job.ID)
+	if done.Status != StatusFailed || done.Error != "boom" {
+		t.Fatalf("unexpected job: %+v", done)
+	}
+}
+
+func TestManager_CancelAndSingleFlight(t *testing.T) {
+	m := NewManager(10)
+	defer m.Close(context.Background())
+
+	started := make(chan struct{})
+	job, err := m.Submit("maintenance", func(ctx context.Context, _ func(string)) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	<-started
+
+	dup, err := m.Submit("maintenance", func(context.Context, func(string)) (any, error) { return nil, nil })
+	if !errors.Is(err, ErrAlreadyRunning) || dup.ID != job.ID {
+		t.Fatalf("second Submit = %+v, %v; want running job and ErrAlreadyRunning", dup, err)
+	}
+
+	if _, err := m.Cancel(job.ID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	done := waitDone(t, m, job.ID)
+	if done.Status != StatusCancelled || done.Error != "" {
+		t.Fatalf("unexpected job: %+v", done)
+	}
+	if _, err := m.Cancel(job.ID); !errors.Is(err, ErrFinished) {
+		t.Fatalf("Cancel finished job: %v, want ErrFinished", err)
+	}
+	if _, err := m.Cancel("job_missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Cancel unknown job: %v, want ErrNotFound", err)
+	}
+}
+
+func TestManager_RetainsFinished(t *testing.T) {
+	m := NewManager(2)
+	defer m.Close(context.Background())
+
+	var ids []string
+	for i := 0; i < 4; i++ {
+		job, err := m.Submit("k", func(context.Context, func(string)) (any, error) { return nil, nil })
+		if err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+		waitDone(t, m, job.ID)
+		ids = append(ids, job.ID)
+	}
+
+	if got := len(m.List()); got != 2 {
+		t.Fatalf("List returned %d jobs, want 2", got)
+	}
+	if _, err := m.Get(ids[0]); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("oldest job still present: %v", err)
+	}
+	if _, err := m.Get(ids[3]); err != nil {
+		t.Fatalf("newest job missing: %v", err)
+	}
+}
+
+func TestManager_CloseCancelsRunning(t *testing.T) {
+	m := NewManager(10)
+	job, _ := m.Submit("k", func(ctx context.Context, _ func(string)) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := m.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got, _ := m.Get(job.ID); got.Status != StatusCancelled {
+		t.Fatalf("status after Close = %s, want cancelled", got.Status)
+	}
+	if _, err := m.Submit("k", nil); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Submit after Close: %v, want ErrClosed", err)
+	}
+}
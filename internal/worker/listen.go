@@ -0,0 +1,186 @@
+package worker
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/thebtf/engram/internal/config"
+)
+
+const (
+	// selfSignedValidity is how long a generated certificate is valid.
+	selfSignedValidity = 365 * 24 * time.Hour
+	// selfSignedRenewBefore regenerates a certificate this close to expiry.
+	selfSignedRenewBefore = 30 * 24 * time.Hour
+)
+
+// isLoopbackHost reports whether host only accepts connections from this
+// machine. Unspecified addresses (0.0.0.0, ::, "") listen on every interface.
+func isLoopbackHost(host string) bool {
+	host = strings.Trim(strings.TrimSpace(host), "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// checkBindAuth refuses to expose an unauthenticated worker on the network:
+// binding to a non-loopback address requires the operator token, and
+// ENGRAM_AUTH_DISABLED is only honoured on loopback.
+func checkBindAuth(host, token string, authDisabled bool) error {
+	if isLoopbackHost(host) {
+		return nil
+	}
+	if authDisabled {
+		return fmt.Errorf("binding to %q exposes the worker on the network: ENGRAM_AUTH_DISABLED is only allowed on a loopback address", host)
+	}
+	if token == "" {
+		return fmt.Errorf("binding to %q exposes the worker on the network: set ENGRAM_AUTH_ADMIN_TOKEN", host)
+	}
+	return nil
+}
+
+// listenerTLSConfig returns the TLS configuration of the worker listener, or
+// nil to serve plaintext. A configured certificate and key win; otherwise
+// TLSSelfSigned generates (or reuses) a self-signed certificate in dir.
+func listenerTLSConfig(cfg *config.Config, host, dir string) (*tls.Config, error) {
+	var cert tls.Certificate
+	switch {
+	case cfg.TLSCert != "" || cfg.TLSKey != "":
+		if cfg.TLSCert == "" || cfg.TLSKey == "" {
+			return nil, fmt.Errorf("TLS needs both ENGRAM_TLS_CERT and ENGRAM_TLS_KEY")
+		}
+		var err error
+		if cert, err = tls.LoadX509KeyPair(cfg.TLSCert, cfg.TLSKey); err != nil {
+			return nil, fmt.Errorf("load TLS keypair: %w", err)
+		}
+		log.Info().Str("cert", cfg.TLSCert).Msg("TLS enabled")
+	case cfg.TLSSelfSigned:
+		certPath, keyPath := filepath.Join(dir, "self-signed.crt"), filepath.Join(dir, "self-signed.key")
+		var err error
+		if cert, err = loadOrCreateSelfSigned(certPath, keyPath, certificateHosts(host), time.Now()); err != nil {
+			return nil, fmt.Errorf("self-signed certificate: %w", err)
+		}
+		sum := sha256.Sum256(cert.Certificate[0])
+		log.Info().
+			Str("cert", certPath).
+			Str("sha256", hex.EncodeToString(sum[:])).
+			Msg("TLS enabled with a self-signed certificate; clients can trust it via ENGRAM_TLS_CA")
+	default:
+		return nil, nil
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}, nil
+}
+
+// certificateHosts returns the names and addresses a self-signed certificate
+// covers: loopback, the machine's hostname, and the bind address, or every
+// interface address when binding to all of them.
+func certificateHosts(host string) []string {
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	if name, err := os.Hostname(); err == nil && name != "" {
+		hosts = append(hosts, name)
+	}
+	host = strings.Trim(strings.TrimSpace(host), "[]")
+	ip := net.ParseIP(host)
+	switch {
+	case host == "" || (ip != nil && ip.IsUnspecified()):
+		if addrs, err := net.InterfaceAddrs(); err == nil {
+			for _, a := range addrs {
+				if ipNet, ok := a.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && !ipNet.IP.IsLinkLocalUnicast() {
+					hosts = append(hosts, ipNet.IP.String())
+				}
+			}
+		}
+	case !isLoopbackHost(host):
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+// loadOrCreateSelfSigned loads the certificate at certPath when it covers
+// hosts and is not close to expiry; otherwise it generates a new one and
+// writes it (and its key, owner-only) for the next start.
+func loadOrCreateSelfSigned(certPath, keyPath string, hosts []string, now time.Time) (tls.Certificate, error) {
+	if cert, err := tls.LoadX509KeyPair(certPath, keyPath); err == nil {
+		if leaf, err := x509.ParseCertificate(cert.Certificate[0]); err == nil &&
+			now.Add(selfSignedRenewBefore).Before(leaf.NotAfter) && coversHosts(leaf, hosts) {
+			return cert, nil
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "engram worker", Organization: []string{"engram"}},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(selfSignedValidity),
+		// Its own CA, so clients can trust it directly (ENGRAM_TLS_CA).
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	if err := os.MkdirAll(filepath.Dir(certPath), 0700); err != nil {
+		return tls.Certificate{}, err
+	}
+	if err := os.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		return tls.Certificate{}, err
+	}
+	if err := os.WriteFile(certPath, certPEM, 0644); err != nil {
+		return tls.Certificate{}, err
+	}
+	log.Info().Str("cert", certPath).Strs("hosts", hosts).Msg("Generated self-signed TLS certificate")
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+// coversHosts reports whether cert is valid for every host.
+func coversHosts(cert *x509.Certificate, hosts []string) bool {
+	for _, h := range hosts {
+		if cert.VerifyHostname(h) != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"crypto/x509"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/config"
+)
+
+func TestIsLoopbackHost(t *testing.T) {
+	for host, want := range map[string]bool{
+		"127.0.0.1":    true,
+		"127.0.0.2":    true,
+		"::1":          true,
+		"[::1]":        true,
+		"localhost":    true,
+		"0.0.0.0":      false,
+		"::":           false,
+		"":             false,
+		"192.168.1.20": false,
+		"devbox.lan":   false,
+	} {
+		assert.Equal(t, want, isLoopbackHost(host), host)
+	}
+}
+
+func TestCheckBindAuth(t *testing.T) {
+	assert.NoError(t, checkBindAuth("127.0.0.1", "", true), "loopback may run without auth")
+	assert.NoError(t, checkBindAuth("0.0.0.0", "secret", false))
+	assert.Error(t, checkBindAuth("0.0.0.0", "", false), "network bind needs a token")
+	assert.Error(t, checkBindAuth("192.168.1.20", "secret", true), "auth cannot be disabled on a network bind")
+}
+
+func TestListenerTLSConfig_SelfSigned(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{TLSSelfSigned: true}
+
+	tlsConfig, err := listenerTLSConfig(cfg, "192.168.1.20", dir)
+	require.NoError(t, err)
+	require.NotNil(t, tlsConfig)
+	leaf, err := x509.ParseCertificate(tlsConfig.Certificates[0].Certificate[0])
+	require.NoError(t, err)
+	assert.NoError(t, leaf.VerifyHostname("192.168.1.20"))
+	assert.NoError(t, leaf.VerifyHostname("localhost"))
+
+	// A second start reuses the stored certificate.
+	again, err := listenerTLSConfig(cfg, "192.168.1.20", dir)
+	require.NoError(t, err)
+	assert.Equal(t, tlsConfig.Certificates[0].Certificate[0], again.Certificates[0].Certificate[0])
+
+	// A certificate close to expiry is regenerated.
+	certPath, keyPath := filepath.Join(dir, "self-signed.crt"), filepath.Join(dir, "self-signed.key")
+	renewed, err := loadOrCreateSelfSigned(certPath, keyPath, []string{"localhost"}, time.Now().Add(selfSignedValidity-24*time.Hour))
+	require.NoError(t, err)
+	assert.NotEqual(t, tlsConfig.Certificates[0].Certificate[0], renewed.Certificate[0])
+}
+
+func TestListenerTLSConfig_Plaintext(t *testing.T) {
+	tlsConfig, err := listenerTLSConfig(&config.Config{}, "127.0.0.1", t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, tlsConfig)
+
+	_, err = listenerTLSConfig(&config.Config{TLSCert: "only-cert.pem"}, "127.0.0.1", t.TempDir())
+	assert.Error(t, err, "a certificate without a key is a misconfiguration")
+}
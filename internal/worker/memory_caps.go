@@ -0,0 +1,175 @@
+package worker
+
+import (
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/thebtf/engram/internal/config"
+)
+
+// promptCacheMaxAge is how long a session's last prompt is kept.
+const promptCacheMaxAge = 2 * time.Hour
+
+// trimPromptCache drops prompt cache entries older than maxAge, then the
+// oldest entries past maxEntries (0 = no cap). Returns how many were dropped.
+func (s *Service) trimPromptCache(maxAge time.Duration, maxEntries int) int {
+	type keyed struct {
+		key any
+		at  time.Time
+	}
+	cutoff := time.Now().Add(-maxAge)
+	var kept []keyed
+	dropped := 0
+	s.promptCache.Range(func(key, value any) bool {
+		entry, ok := value.(promptCacheEntry)
+		if ok && entry.Timestamp.Before(cutoff) {
+			s.promptCache.Delete(key)
+			dropped++
+			return true
+		}
+		kept = append(kept, keyed{key: key, at: entry.Timestamp})
+		return true
+	})
+	if maxEntries <= 0 || len(kept) <= maxEntries {
+		return dropped
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].at.Before(kept[j].at) })
+	for _, k := range kept[:len(kept)-maxEntries] {
+		s.promptCache.Delete(k.key)
+		dropped++
+	}
+	return dropped
+}
+
+// promptCacheLen returns the number of cached prompts.
+func (s *Service) promptCacheLen() int {
+	n := 0
+	s.promptCache.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// evictExpiredObsCounts drops cached observation counts older than the stats
+// cache TTL (all of them when all is set). Returns how many were dropped.
+func (s *Service) evictExpiredObsCounts(all bool) int {
+	s.cachedObsCountsMu.Lock()
+	defer s.cachedObsCountsMu.Unlock()
+	dropped := 0
+	for key, cached := range s.cachedObsCounts {
+		if all || time.Since(cached.timestamp) >= s.statsCacheTTL {
+			delete(s.cachedObsCounts, key)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// cacheUsage reports the current size and configured cap of each in-memory
+// cache the worker keeps. A cap of 0 means unlimited.
+func (s *Service) cacheUsage() map[string]any {
+	cfg := config.Get()
+	s.cachedObsCountsMu.RLock()
+	obsCounts := len(s.cachedObsCounts)
+	s.cachedObsCountsMu.RUnlock()
+	s.retrievalStatsMu.RLock()
+	retrievalProjects := len(s.retrievalStats)
+	s.retrievalStatsMu.RUnlock()
+
+	usage := map[string]any{
+		"prompt_cache": map[string]any{
+			"entries": s.promptCacheLen(),
+			"max":     cfg.PromptCacheMax,
+		},
+		"observation_counts": map[string]any{
+			"entries": obsCounts,
+		},
+		"retrieval_stats": map[string]any{
+			"projects": retrievalProjects,
+			"max":      maxRetrievalStatsProjects,
+		},
+	}
+	if s.sessionManager != nil {
+		usage["sessions"] = map[string]any{
+			"active": s.sessionManager.GetActiveSessionCount(),
+			"max":    cfg.MaxActiveSessions,
+		}
+	}
+	if s.sseBroadcaster != nil {
+		usage["sse"] = map[string]any{
+			"clients":         s.sseBroadcaster.ClientCount(),
+			"max_clients":     cfg.SSEMaxClients,
+			"replay_buffered": s.sseBroadcaster.ReplayLen(),
+			"replay_max":      cfg.SSEReplayBuffer,
+		}
+	}
+	return usage
+}
+
+// handleAdminGC godoc
+// @Summary Trim worker caches
+// @Description Trims the worker's in-memory caches, then runs a garbage collection and returns freed memory to the OS. Drops expired and over-cap prompt cache entries, all cached observation counts, idle in-memory sessions (they reload from the database on their next request) and the SSE replay buffer (resuming clients get a replay_gap and refetch). Reports what was trimmed and heap figures before and after.
+// @Tags System
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {string} string "admin access required"
+// @Router /api/admin/gc [post]
+func (s *Service) handleAdminGC(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminIdentity(w, r) {
+		return
+	}
+
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	trimmed := map[string]int{
+		"prompt_cache":       s.trimPromptCache(promptCacheMaxAge, config.Get().PromptCacheMax),
+		"observation_counts": s.evictExpiredObsCounts(true),
+	}
+	if s.sessionManager != nil {
+		trimmed["sessions"] = s.sessionManager.TrimIdleSessions(0)
+	}
+	if s.sseBroadcaster != nil {
+		trimmed["sse_replay"] = s.sseBroadcaster.TrimReplay()
+	}
+	s.retrievalStatsMu.Lock()
+	s.cleanupRetrievalStatsLocked()
+	s.retrievalStatsMu.Unlock()
+
+	// FreeOSMemory forces a collection before returning pages to the OS.
+	debug.FreeOSMemory()
+
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+
+	log.Info().
+		Interface("trimmed", trimmed).
+		Float64("heap_inuse_before_mb", float64(before.HeapInuse)/1024/1024).
+		Float64("heap_inuse_after_mb", float64(after.HeapInuse)/1024/1024).
+		Msg("Trimmed worker caches")
+
+	writeJSON(w, map[string]any{
+		"trimmed": trimmed,
+		"before":  gcMemory(&before),
+		"after":   gcMemory(&after),
+		"caches":  s.cacheUsage(),
+	})
+}
+
+// gcMemory returns the heap figures /api/admin/gc reports.
+func gcMemory(m *runtime.MemStats) map[string]any {
+	return map[string]any{
+		"heap_alloc_mb":    float64(m.HeapAlloc) / 1024 / 1024,
+		"heap_inuse_mb":    float64(m.HeapInuse) / 1024 / 1024,
+		"heap_released_mb": float64(m.HeapReleased) / 1024 / 1024,
+		"sys_mb":           float64(m.Sys) / 1024 / 1024,
+		"heap_objects":     m.HeapObjects,
+	}
+}
@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTrimPromptCache checks that expired prompts go first and the oldest of
+// the rest are dropped past the cap.
+func TestTrimPromptCache(t *testing.T) {
+	svc := &Service{}
+	now := time.Now()
+	svc.promptCache.Store(int64(1), promptCacheEntry{Prompt: "expired", Timestamp: now.Add(-3 * time.Hour)})
+	svc.promptCache.Store(int64(2), promptCacheEntry{Prompt: "old", Timestamp: now.Add(-time.Hour)})
+	svc.promptCache.Store(int64(3), promptCacheEntry{Prompt: "mid", Timestamp: now.Add(-time.Minute)})
+	svc.promptCache.Store(int64(4), promptCacheEntry{Prompt: "new", Timestamp: now})
+
+	assert.Equal(t, 2, svc.trimPromptCache(promptCacheMaxAge, 2))
+	assert.Equal(t, 2, svc.promptCacheLen())
+	assert.Empty(t, svc.GetLastPrompt(2))
+	assert.Equal(t, "mid", svc.GetLastPrompt(3))
+	assert.Equal(t, "new", svc.GetLastPrompt(4))
+
+	// No cap: only expiry applies.
+	assert.Equal(t, 0, svc.trimPromptCache(promptCacheMaxAge, 0))
+}
+
+// TestHandleAdminGC checks that the endpoint trims the caches and reports usage.
+func TestHandleAdminGC(t *testing.T) {
+	svc := newDrainTestService(t)
+	svc.cachedObsCounts = map[string]cachedCount{"t/p": {count: 3, timestamp: time.Now()}}
+	svc.retrievalStats = map[string]*RetrievalStats{}
+	svc.statsCacheTTL = time.Minute
+	svc.SetLastPrompt(1, "hello")
+	svc.sseBroadcaster.Broadcast(map[string]string{"type": "ping"})
+
+	rec := httptest.NewRecorder()
+	svc.handleAdminGC(rec, httptest.NewRequest(http.MethodPost, "/api/admin/gc", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp struct {
+		Trimmed map[string]int            `json:"trimmed"`
+		Before  map[string]any            `json:"before"`
+		After   map[string]any            `json:"after"`
+		Caches  map[string]map[string]any `json:"caches"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Trimmed["observation_counts"])
+	assert.Equal(t, 1, resp.Trimmed["sse_replay"])
+	assert.Equal(t, 0, resp.Trimmed["prompt_cache"], "fresh prompts under the cap are kept")
+	assert.Contains(t, resp.After, "heap_inuse_mb")
+	assert.EqualValues(t, 1, resp.Caches["prompt_cache"]["entries"])
+	assert.EqualValues(t, 0, resp.Caches["sse"]["replay_buffered"])
+	assert.Empty(t, svc.cachedObsCounts)
+}
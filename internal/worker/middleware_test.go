@@ -1,9 +1,12 @@
 package worker
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestSecurityHeaders(t *testing.T) {
@@ -488,3 +491,48 @@ func TestSecurityHeaders_Preflight(t *testing.T) {
 		t.Error("Access-Control-Allow-Methods should be set")
 	}
 }
+
+func TestRequireReady_WaitsForInitialization(t *testing.T) {
+	s := &Service{initDone: make(chan struct{})}
+	handler := s.requireReady(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		s.ready.Store(true)
+		s.finishInit()
+	}()
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/api/context/search", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("request during initialization: status = %d, want %d", rr.Code, http.StatusOK)
+	}
+}
+
+func TestRequireReady_InitFailureAndCancel(t *testing.T) {
+	handler := func(s *Service) http.Handler {
+		return s.requireReady(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+	}
+
+	failed := &Service{initDone: make(chan struct{})}
+	failed.setInitError(errors.New("db down"))
+	rr := httptest.NewRecorder()
+	handler(failed).ServeHTTP(rr, httptest.NewRequest("GET", "/api/context/search", nil))
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("after init failure: status = %d, want %d", rr.Code, http.StatusInternalServerError)
+	}
+
+	// A caller that gives up is answered at once with 503 and Retry-After.
+	starting := &Service{initDone: make(chan struct{})}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rr = httptest.NewRecorder()
+	handler(starting).ServeHTTP(rr, httptest.NewRequest("GET", "/api/context/search", nil).WithContext(ctx))
+	if rr.Code != http.StatusServiceUnavailable || rr.Header().Get("Retry-After") == "" {
+		t.Errorf("cancelled during initialization: status = %d, Retry-After = %q; want 503 with Retry-After", rr.Code, rr.Header().Get("Retry-After"))
+	}
+}
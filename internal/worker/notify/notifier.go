@@ -0,0 +1,239 @@
+// Package notify delivers engram events to outbound webhooks
+// (ENGRAM_OUTBOUND_WEBHOOKS), so external tooling can mirror or react to
+// memory changes.
+//
+// Publish never blocks: events are queued and POSTed by a small pool of
+// workers, each delivery retried with exponential backoff on network errors,
+// 429 and 5xx responses. Events that do not fit the queue are dropped and
+// logged.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/thebtf/engram/internal/config"
+)
+
+const (
+	// QueueSize is how many deliveries may wait for a worker.
+	QueueSize = 1024
+	// Workers is the number of concurrent deliveries.
+	Workers = 4
+	// MaxAttempts is how often a delivery is tried before it is dropped.
+	MaxAttempts = 4
+	// RequestTimeout bounds a single delivery attempt.
+	RequestTimeout = 10 * time.Second
+)
+
+// Headers set on every delivery.
+const (
+	HeaderEvent     = "X-Engram-Event"
+	HeaderDelivery  = "X-Engram-Delivery"
+	HeaderSignature = "X-Engram-Signature-256"
+)
+
+// Event is the JSON body POSTed to a webhook.
+type Event struct {
+	Timestamp time.Time `json:"timestamp"`
+	Data      any       `json:"data,omitempty"`
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Project   string    `json:"project,omitempty"`
+}
+
+// delivery is one event bound for one webhook.
+type delivery struct {
+	hook config.OutboundWebhook
+	body []byte
+	ev   Event
+}
+
+// Notifier fans events out to the configured webhooks.
+type Notifier struct {
+	targets func() []config.OutboundWebhook
+	client  *http.Client
+	queue   chan delivery
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	closeMu sync.RWMutex
+	closed  bool
+	// backoff is the delay before the second attempt; it doubles after each.
+	backoff time.Duration
+}
+
+// New starts a Notifier that delivers to the webhooks returned by targets,
+// which is consulted on every Publish so configuration reloads apply.
+func New(targets func() []config.OutboundWebhook) *Notifier {
+	ctx, cancel := context.WithCancel(context.Background())
+	n := &Notifier{
+		targets: targets,
+		client:  &http.Client{Timeout: RequestTimeout},
+		queue:   make(chan delivery, QueueSize),
+		ctx:     ctx,
+		cancel:  cancel,
+		backoff: time.Second,
+	}
+	for i := 0; i < Workers; i++ {
+		n.wg.Add(1)
+		go n.worker()
+	}
+	return n
+}
+
+// Publish queues ev for every webhook subscribed to its type and project.
+// ID and Timestamp are filled in when empty.
+func (n *Notifier) Publish(ev Event) {
+	if n == nil {
+		return
+	}
+	var hooks []config.OutboundWebhook
+	for _, hook := range n.targets() {
+		if hook.URL != "" && hook.Wants(ev.Type, ev.Project) {
+			hooks = append(hooks, hook)
+		}
+	}
+	if len(hooks) == 0 {
+		return
+	}
+	if ev.ID == "" {
+		ev.ID = newDeliveryID()
+	}
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now().UTC()
+	}
+	body, err := json.Marshal(ev)
+	if err != nil {
+		log.Error().Err(err).Str("event", ev.Type).Msg("notify: marshal event failed")
+		return
+	}
+
+	n.closeMu.RLock()
+	defer n.closeMu.RUnlock()
+	if n.closed {
+		return
+	}
+	for _, hook := range hooks {
+		select {
+		case n.queue <- delivery{hook: hook, body: body, ev: ev}:
+		default:
+			log.Warn().Str("event", ev.Type).Str("url", hook.URL).Msg("notify: queue full, event dropped")
+		}
+	}
+}
+
+// Close stops accepting events and waits for queued deliveries until ctx
+// expires, then abandons the rest.
+func (n *Notifier) Close(ctx context.Context) error {
+	if n == nil {
+		return nil
+	}
+	n.closeMu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.queue)
+	}
+	n.closeMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		n.cancel()
+		return nil
+	case <-ctx.Done():
+		n.cancel()
+		return fmt.Errorf("notify: %d deliveries abandoned: %w", len(n.queue), ctx.Err())
+	}
+}
+
+func (n *Notifier) worker() {
+	defer n.wg.Done()
+	for d := range n.queue {
+		n.deliver(d)
+	}
+}
+
+// deliver POSTs d, retrying retryable failures with exponential backoff.
+func (n *Notifier) deliver(d delivery) {
+	wait := n.backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := n.post(d)
+		if err == nil {
+			return
+		}
+		if !retry || attempt >= MaxAttempts {
+			log.Warn().Err(err).Str("event", d.ev.Type).Str("id", d.ev.ID).Str("url", d.hook.URL).
+				Int("attempts", attempt).Msg("notify: delivery failed")
+			return
+		}
+		select {
+		case <-time.After(wait):
+		case <-n.ctx.Done():
+			return
+		}
+		wait *= 2
+	}
+}
+
+// post makes one delivery attempt and reports whether a failure is worth
+// retrying.
+func (n *Notifier) post(d delivery) (bool, error) {
+	req, err := http.NewRequestWithContext(n.ctx, http.MethodPost, d.hook.URL, bytes.NewReader(d.body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "engram-webhook")
+	req.Header.Set(HeaderEvent, d.ev.Type)
+	req.Header.Set(HeaderDelivery, d.ev.ID)
+	if d.hook.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(d.hook.Secret, d.body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return n.ctx.Err() == nil, err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("webhook responded %s", resp.Status)
+	default:
+		return false, fmt.Errorf("webhook responded %s", resp.Status)
+	}
+}
+
+// Sign returns the "sha256=<hex>" HMAC-SHA256 signature of body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newDeliveryID returns a random event ID.
+func newDeliveryID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/config"
+)
+
+// TestNotifier_DeliversSignedEvents checks the body, headers and signature
+// of a delivery and that subscriptions filter by event type and project.
+func TestNotifier_DeliversSignedEvents(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []*http.Request
+		bodies   [][]byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, r)
+		bodies = append(bodies, body)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	n := New(func() []config.OutboundWebhook {
+		return []config.OutboundWebhook{
+			{URL: srv.URL, Secret: "s3cret", Events: []string{"memory.created"}, Projects: []string{"engram"}},
+		}
+	})
+	n.Publish(Event{Type: "memory.deleted", Project: "engram"})
+	n.Publish(Event{Type: "memory.created", Project: "other"})
+	n.Publish(Event{Type: "memory.created", Project: "engram", Data: map[string]any{"id": 7}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, n.Close(ctx))
+
+	require.Len(t, received, 1)
+	req, body := received[0], bodies[0]
+	assert.Equal(t, "memory.created", req.Header.Get(HeaderEvent))
+	assert.Equal(t, Sign("s3cret", body), req.Header.Get(HeaderSignature))
+
+	var ev Event
+	require.NoError(t, json.Unmarshal(body, &ev))
+	assert.Equal(t, "memory.created", ev.Type)
+	assert.Equal(t, "engram", ev.Project)
+	assert.Equal(t, req.Header.Get(HeaderDelivery), ev.ID)
+	assert.NotEmpty(t, ev.ID)
+	assert.False(t, ev.Timestamp.IsZero())
+}
+
+// TestNotifier_Retries checks that 5xx responses are retried and 4xx are not.
+func TestNotifier_Retries(t *testing.T) {
+	var flaky, rejected atomic.Int32
+	flakySrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if flaky.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer flakySrv.Close()
+	rejectSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rejected.Add(1)
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer rejectSrv.Close()
+
+	n := New(func() []config.OutboundWebhook {
+		return []config.OutboundWebhook{{URL: flakySrv.URL}, {URL: rejectSrv.URL}}
+	})
+	n.backoff = time.Millisecond
+	n.Publish(Event{Type: "memory.updated", Project: "engram"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, n.Close(ctx))
+
+	assert.Equal(t, int32(3), flaky.Load(), "retried until it succeeded")
+	assert.Equal(t, int32(1), rejected.Load(), "a 4xx is not retried")
+}
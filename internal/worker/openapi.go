@@ -0,0 +1,338 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/swaggo/swag"
+)
+
+// openAPIVersion is the OpenAPI revision of the document served at
+// /api/openapi.json.
+const openAPIVersion = "3.0.3"
+
+// chiParamRegexp matches a chi URL parameter with an inline pattern
+// ("{id:[0-9]+}") so it can be reduced to its OpenAPI form ("{id}").
+var chiParamRegexp = regexp.MustCompile(`\{([^}:]+):[^}]*\}`)
+
+// pathParamRegexp extracts the parameter names of an OpenAPI path.
+var pathParamRegexp = regexp.MustCompile(`\{([^}]+)\}`)
+
+// handleOpenAPI godoc
+// @Summary OpenAPI 3 document
+// @Description Returns an OpenAPI 3 description of every route the worker serves. Routes come from the router itself; summaries, parameters and schemas come from the handler annotations where they exist.
+// @Tags System
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/openapi.json [get]
+func (s *Service) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	s.openAPIOnce.Do(func() {
+		// The annotation document is registered by cmd/engram-server; without
+		// it (tests, embedded use) the spec still lists every route.
+		annotations, err := swag.ReadDoc()
+		if err != nil {
+			log.Debug().Err(err).Msg("No swag annotations registered; OpenAPI document lists routes only")
+			annotations = ""
+		}
+		doc, err := buildOpenAPIDocument(s.router, []byte(annotations), s.version)
+		if err == nil {
+			s.openAPIDoc, err = json.Marshal(doc)
+		}
+		s.openAPIErr = err
+	})
+	if s.openAPIErr != nil {
+		log.Error().Err(s.openAPIErr).Msg("Failed to build OpenAPI document")
+		http.Error(w, "failed to build OpenAPI document", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(s.openAPIDoc)
+}
+
+// buildOpenAPIDocument returns an OpenAPI 3 document with one operation per
+// method and route registered on routes. swagger2 is the Swagger 2.0 document
+// generated by swag from the handler annotations (may be empty): operations
+// it describes carry over their summary, parameters, request body and
+// responses, with schema references rewritten to components/schemas.
+// Operations it does not describe get an operation ID derived from the
+// handler name and their path parameters, so the document always matches the
+// router rather than the last swag run.
+func buildOpenAPIDocument(routes chi.Routes, swagger2 []byte, version string) (map[string]any, error) {
+	var legacy struct {
+		Info                map[string]any            `json:"info"`
+		Paths               map[string]map[string]any `json:"paths"`
+		Definitions         map[string]any            `json:"definitions"`
+		SecurityDefinitions map[string]any            `json:"securityDefinitions"`
+	}
+	if len(swagger2) > 0 {
+		if err := json.Unmarshal(swagger2, &legacy); err != nil {
+			return nil, fmt.Errorf("parse swagger annotations: %w", err)
+		}
+	}
+
+	info := map[string]any{"title": "Engram API", "version": version}
+	if title, ok := legacy.Info["title"].(string); ok && title != "" {
+		info["title"] = title
+	}
+	if desc, ok := legacy.Info["description"].(string); ok && desc != "" {
+		info["description"] = desc
+	}
+	if version == "" {
+		info["version"] = "dev"
+	}
+
+	type routeEntry struct {
+		handler http.Handler
+		path    string
+		method  string
+	}
+	var entries []routeEntry
+	err := chi.Walk(routes, func(method, route string, handler http.Handler, _ ...func(http.Handler) http.Handler) error {
+		// Wildcard routes serve static files and the Swagger UI.
+		if strings.Contains(route, "*") {
+			return nil
+		}
+		path := chiParamRegexp.ReplaceAllString(route, "{$1}")
+		if len(path) > 1 {
+			path = strings.TrimSuffix(path, "/")
+		}
+		entries = append(entries, routeEntry{handler: handler, path: path, method: strings.ToLower(method)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk routes: %w", err)
+	}
+	// chi keeps methods in a map; sort so operation IDs are stable.
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].path != entries[j].path {
+			return entries[i].path < entries[j].path
+		}
+		return entries[i].method < entries[j].method
+	})
+
+	paths := map[string]any{}
+	operationIDs := map[string]int{}
+	for _, e := range entries {
+		var op map[string]any
+		if annotated, ok := legacy.Paths[e.path][e.method].(map[string]any); ok {
+			op = convertSwaggerOperation(annotated)
+		} else {
+			op = map[string]any{
+				"responses": map[string]any{"200": map[string]any{"description": "OK"}},
+			}
+		}
+
+		id := operationID(e.handler, e.method)
+		operationIDs[id]++
+		if n := operationIDs[id]; n > 1 {
+			id = fmt.Sprintf("%s%d", id, n)
+		}
+		op["operationId"] = id
+		ensurePathParameters(op, e.path)
+
+		item, _ := paths[e.path].(map[string]any)
+		if item == nil {
+			item = map[string]any{}
+			paths[e.path] = item
+		}
+		item[e.method] = op
+	}
+
+	components := map[string]any{}
+	if len(legacy.Definitions) > 0 {
+		components["schemas"] = rewriteSchemaRefs(legacy.Definitions)
+	}
+	if len(legacy.SecurityDefinitions) > 0 {
+		components["securitySchemes"] = legacy.SecurityDefinitions
+	}
+
+	return map[string]any{
+		"openapi":    openAPIVersion,
+		"info":       info,
+		"paths":      paths,
+		"components": components,
+	}, nil
+}
+
+// convertSwaggerOperation turns a Swagger 2.0 operation into its OpenAPI 3
+// form: the body parameter becomes requestBody and response schemas move
+// under a media type.
+func convertSwaggerOperation(in map[string]any) map[string]any {
+	out := map[string]any{}
+	for _, key := range []string{"summary", "description", "tags", "security", "deprecated"} {
+		if v, ok := in[key]; ok {
+			out[key] = v
+		}
+	}
+	consumes := firstString(in["consumes"], "application/json")
+	produces := firstString(in["produces"], "application/json")
+
+	var params []any
+	list, _ := in["parameters"].([]any)
+	for _, raw := range list {
+		p, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		switch p["in"] {
+		case "body":
+			body := map[string]any{
+				"content": map[string]any{consumes: map[string]any{"schema": rewriteSchemaRefs(p["schema"])}},
+			}
+			if desc, ok := p["description"].(string); ok && desc != "" {
+				body["description"] = desc
+			}
+			if required, ok := p["required"].(bool); ok {
+				body["required"] = required
+			}
+			out["requestBody"] = body
+		case "formData":
+			// No handler takes form bodies; skip rather than mis-describe.
+			continue
+		default:
+			param := map[string]any{"name": p["name"], "in": p["in"]}
+			if desc, ok := p["description"].(string); ok && desc != "" {
+				param["description"] = desc
+			}
+			if required, ok := p["required"].(bool); ok {
+				param["required"] = required
+			}
+			schema := map[string]any{}
+			for _, key := range []string{"type", "format", "items", "enum", "default", "minimum", "maximum"} {
+				if v, ok := p[key]; ok {
+					schema[key] = rewriteSchemaRefs(v)
+				}
+			}
+			param["schema"] = schema
+			params = append(params, param)
+		}
+	}
+	if len(params) > 0 {
+		out["parameters"] = params
+	}
+
+	responses := map[string]any{}
+	if rs, ok := in["responses"].(map[string]any); ok {
+		for code, raw := range rs {
+			r, ok := raw.(map[string]any)
+			if !ok {
+				continue
+			}
+			desc, _ := r["description"].(string)
+			if desc == "" {
+				n, _ := strconv.Atoi(code)
+				desc = http.StatusText(n)
+			}
+			resp := map[string]any{"description": desc}
+			if schema, ok := r["schema"]; ok {
+				mediaType := produces
+				if m, ok := schema.(map[string]any); ok && m["type"] == "string" {
+					mediaType = "text/plain"
+				}
+				resp["content"] = map[string]any{mediaType: map[string]any{"schema": rewriteSchemaRefs(schema)}}
+			}
+			responses[code] = resp
+		}
+	}
+	if len(responses) == 0 {
+		responses["200"] = map[string]any{"description": "OK"}
+	}
+	out["responses"] = responses
+	return out
+}
+
+// ensurePathParameters adds a required string parameter for every path
+// variable the operation does not already describe.
+func ensurePathParameters(op map[string]any, path string) {
+	params, _ := op["parameters"].([]any)
+	declared := map[string]bool{}
+	for _, raw := range params {
+		if p, ok := raw.(map[string]any); ok && p["in"] == "path" {
+			if name, ok := p["name"].(string); ok {
+				declared[name] = true
+			}
+		}
+	}
+	for _, m := range pathParamRegexp.FindAllStringSubmatch(path, -1) {
+		if declared[m[1]] {
+			continue
+		}
+		params = append(params, map[string]any{
+			"name":     m[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]any{"type": "string"},
+		})
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+}
+
+// rewriteSchemaRefs returns a copy of v with Swagger 2.0 definition
+// references pointed at OpenAPI 3 component schemas.
+func rewriteSchemaRefs(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			if ref, ok := val.(string); ok && k == "$ref" {
+				out[k] = strings.Replace(ref, "#/definitions/", "#/components/schemas/", 1)
+				continue
+			}
+			out[k] = rewriteSchemaRefs(val)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, val := range t {
+			out[i] = rewriteSchemaRefs(val)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// operationID derives an operation ID from the handler's function name, e.g.
+// (*Service).handleGetStats becomes "getStats". Handlers without a usable
+// name fall back to the HTTP method.
+func operationID(handler http.Handler, method string) string {
+	name := ""
+	if fn, ok := handler.(http.HandlerFunc); ok {
+		if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+			name = f.Name()
+		}
+	}
+	if i := strings.LastIndexAny(name, "./"); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSuffix(name, "-fm")
+	name = strings.TrimPrefix(name, "handle")
+	if name == "" || strings.HasPrefix(name, "func") {
+		return method
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+// firstString returns the first element of a JSON string array, or def.
+func firstString(v any, def string) string {
+	if list, ok := v.([]any); ok && len(list) > 0 {
+		if s, ok := list[0].(string); ok && s != "" {
+			return s
+		}
+	}
+	return def
+}
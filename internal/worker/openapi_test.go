@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *Service) handleOpenAPITestItem(w http.ResponseWriter, r *http.Request) {}
+
+func TestBuildOpenAPIDocument(t *testing.T) {
+	svc := &Service{}
+	router := chi.NewRouter()
+	router.Get("/assets/*", svc.handleOpenAPITestItem)
+	router.Group(func(r chi.Router) {
+		r.Use(func(next http.Handler) http.Handler { return next })
+		r.Get("/api/items/{id}", svc.handleOpenAPITestItem)
+		r.Put("/api/items/{id:[0-9]+}", svc.handleOpenAPITestItem)
+	})
+
+	swagger2 := []byte(`{
+		"info": {"title": "Engram API", "description": "test"},
+		"paths": {
+			"/api/items/{id}": {
+				"put": {
+					"summary": "Update an item",
+					"consumes": ["application/json"],
+					"parameters": [
+						{"name": "id", "in": "path", "required": true, "type": "integer"},
+						{"name": "body", "in": "body", "required": true, "schema": {"$ref": "#/definitions/item"}}
+					],
+					"responses": {
+						"200": {"description": "OK", "schema": {"$ref": "#/definitions/item"}},
+						"404": {"description": "not found", "schema": {"type": "string"}}
+					}
+				}
+			}
+		},
+		"definitions": {"item": {"type": "object", "properties": {"id": {"type": "integer"}}}}
+	}`)
+
+	doc, err := buildOpenAPIDocument(router, swagger2, "1.2.3")
+	require.NoError(t, err)
+	assert.Equal(t, openAPIVersion, doc["openapi"])
+	assert.Equal(t, "1.2.3", doc["info"].(map[string]any)["version"])
+
+	paths := doc["paths"].(map[string]any)
+	assert.NotContains(t, paths, "/assets/*", "wildcard routes are not API operations")
+	item := paths["/api/items/{id}"].(map[string]any)
+
+	// Unannotated route: operation ID from the handler, path parameter added.
+	get := item["get"].(map[string]any)
+	assert.Equal(t, "openAPITestItem", get["operationId"])
+	params := get["parameters"].([]any)
+	require.Len(t, params, 1)
+	assert.Equal(t, "id", params[0].(map[string]any)["name"])
+	assert.Equal(t, true, params[0].(map[string]any)["required"])
+
+	// Annotated route: body moved to requestBody, refs rewritten.
+	put := item["put"].(map[string]any)
+	assert.Equal(t, "Update an item", put["summary"])
+	assert.Equal(t, "openAPITestItem2", put["operationId"], "operation IDs are unique")
+	body := put["requestBody"].(map[string]any)
+	schema := body["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+	assert.Equal(t, "#/components/schemas/item", schema["$ref"])
+	putParams := put["parameters"].([]any)
+	require.Len(t, putParams, 1)
+	assert.Equal(t, map[string]any{"type": "integer"}, putParams[0].(map[string]any)["schema"])
+	notFound := put["responses"].(map[string]any)["404"].(map[string]any)
+	assert.Contains(t, notFound["content"], "text/plain")
+
+	components := doc["components"].(map[string]any)
+	assert.Contains(t, components["schemas"], "item")
+}
+
+func TestBuildOpenAPIDocument_NoAnnotations(t *testing.T) {
+	svc := &Service{}
+	router := chi.NewRouter()
+	router.Get("/api/openapi.json", svc.handleOpenAPI)
+
+	doc, err := buildOpenAPIDocument(router, nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, "dev", doc["info"].(map[string]any)["version"])
+	op := doc["paths"].(map[string]any)["/api/openapi.json"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, "openAPI", op["operationId"])
+	assert.Contains(t, op["responses"], "200")
+}
@@ -0,0 +1,432 @@
+// Package pool provides the bounded worker pool that processes queued session
+// messages (observations and summaries).
+//
+// Guarantees:
+//   - at most Options.Workers tasks run concurrently;
+//   - tasks submitted under the same key (the session DB ID) run one at a time,
+//     in submission order — a summary never overtakes the observations queued
+//     before it;
+//   - Submit blocks once Options.QueueSize tasks are pending, so producers slow
+//     down instead of growing memory without bound;
+//   - when the moving average task latency exceeds Options.SlowThreshold
+//     (embedding or database latency spikes), the effective concurrency is
+//     halved, down to one, and restored step by step once latency recovers;
+//   - ready keys are served by priority (see Priority). A key runs at the
+//     highest priority of its pending tasks, so tasks queued ahead of a
+//     high-priority task under the same key inherit its priority. High
+//     priority tasks are admitted even when the queue is full, and low
+//     priority tasks leave one worker free for the others.
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ErrClosed is returned by Submit after Close has been called.
+var ErrClosed = errors.New("processing pool closed")
+
+// Priority is the scheduling class of a task.
+type Priority int
+
+const (
+	// PriorityLow is for bulk work such as tool observations.
+	PriorityLow Priority = iota
+	// PriorityNormal is the default of Submit.
+	PriorityNormal
+	// PriorityHigh is for work a user waits on, such as session summaries.
+	PriorityHigh
+
+	numPriorities = int(PriorityHigh) + 1
+)
+
+// String returns the priority's name as used in Stats.
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityHigh:
+		return "high"
+	}
+	return "normal"
+}
+
+// Task is a unit of work. ctx is cancelled when the pool is closed with an
+// expired shutdown deadline.
+type Task func(ctx context.Context) error
+
+// Options configures a Pool.
+type Options struct {
+	// Workers is the maximum number of concurrently running tasks (default 4).
+	Workers int
+	// QueueSize is the number of pending tasks after which Submit blocks (default 1000).
+	QueueSize int
+	// SlowThreshold is the average task latency above which concurrency is
+	// reduced. Zero disables latency back-pressure.
+	SlowThreshold time.Duration
+	// OnIdle, if set, is called (outside the pool lock) each time the last
+	// pending or running task finishes.
+	OnIdle func()
+}
+
+// Stats is a point-in-time snapshot of the pool.
+type Stats struct {
+	Workers int `json:"workers"`
+	Limit   int `json:"limit"`
+	Active  int `json:"active"`
+	Pending int `json:"pending"`
+	// PendingByPriority counts pending tasks by Priority.String.
+	PendingByPriority map[string]int `json:"pending_by_priority"`
+	Processed         int64          `json:"processed"`
+	Failed            int64          `json:"failed"`
+	AvgLatencyMs      float64        `json:"avg_latency_ms"`
+	Throttled         bool           `json:"throttled"`
+}
+
+// queuedTask is a pending task and its priority.
+type queuedTask struct {
+	run      Task
+	priority Priority
+}
+
+// lane holds the pending tasks of one key. A lane is either idle (absent from
+// the ready lists and not running), ready (in the ready list of its priority)
+// or running. Its priority is the highest of its pending tasks.
+type lane struct {
+	tasks    []queuedTask
+	priority Priority
+	running  bool
+	ready    bool
+}
+
+// refreshPriority recomputes the lane's priority from its pending tasks.
+func (l *lane) refreshPriority() {
+	l.priority = PriorityLow
+	for _, t := range l.tasks {
+		l.priority = max(l.priority, t.priority)
+	}
+}
+
+// Pool runs tasks with bounded concurrency and per-key ordering.
+type Pool struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	cond      *sync.Cond
+	lanes     map[int64]*lane
+	ready     [numPriorities][]int64
+	opts      Options
+	wg        sync.WaitGroup
+	mu        sync.Mutex
+	pending   int
+	byPrio    [numPriorities]int
+	active    int
+	limit     int
+	processed int64
+	failed    int64
+	latency   time.Duration // exponential moving average
+	closed    bool
+}
+
+// latencyAlpha is the weight of the newest sample in the latency moving average.
+const latencyAlpha = 0.2
+
+// New creates a Pool and starts its workers.
+func New(opts Options) *Pool {
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1000
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		ctx:    ctx,
+		cancel: cancel,
+		lanes:  make(map[int64]*lane),
+		opts:   opts,
+		limit:  opts.Workers,
+	}
+	p.cond = sync.NewCond(&p.mu)
+	for i := 0; i < opts.Workers; i++ {
+		p.wg.Add(1)
+		go p.worker()
+	}
+	return p
+}
+
+// Submit queues task under key at PriorityNormal. It blocks while the pool is
+// full, returning ctx.Err() if ctx ends first, or ErrClosed once the pool is
+// closed.
+func (p *Pool) Submit(ctx context.Context, key int64, task Task) error {
+	return p.SubmitPriority(ctx, key, PriorityNormal, task)
+}
+
+// SubmitPriority queues task under key at priority. Like Submit it blocks
+// while the pool is full, except for PriorityHigh tasks, which are always
+// admitted.
+func (p *Pool) SubmitPriority(ctx context.Context, key int64, priority Priority, task Task) error {
+	priority = min(max(priority, PriorityLow), PriorityHigh)
+	if ctx.Done() != nil {
+		stop := context.AfterFunc(ctx, func() {
+			p.mu.Lock()
+			p.cond.Broadcast()
+			p.mu.Unlock()
+		})
+		defer stop()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for priority < PriorityHigh && p.pending >= p.opts.QueueSize && !p.closed && ctx.Err() == nil {
+		p.cond.Wait()
+	}
+	if p.closed {
+		return ErrClosed
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l, ok := p.lanes[key]
+	if !ok {
+		l = &lane{}
+		p.lanes[key] = l
+	}
+	l.tasks = append(l.tasks, queuedTask{run: task, priority: priority})
+	p.pending++
+	p.byPrio[priority]++
+	switch {
+	case l.ready && priority > l.priority:
+		p.unready(key, l.priority)
+		l.priority = priority
+		p.ready[priority] = append(p.ready[priority], key)
+	case !l.running && !l.ready:
+		l.ready = true
+		l.priority = priority
+		p.ready[priority] = append(p.ready[priority], key)
+	case priority > l.priority:
+		l.priority = priority // running: takes effect when it is ready again
+	}
+	p.cond.Broadcast()
+	return nil
+}
+
+// unready removes key from the ready list of priority. Caller holds p.mu.
+func (p *Pool) unready(key int64, priority Priority) {
+	list := p.ready[priority]
+	for i, k := range list {
+		if k == key {
+			p.ready[priority] = append(list[:i:i], list[i+1:]...)
+			return
+		}
+	}
+}
+
+// next picks the ready key to run next: the first of the highest non-empty
+// ready list. Low priority lanes may only take a worker while another one
+// stays free for higher priorities. Caller holds p.mu.
+func (p *Pool) next() (int64, bool) {
+	if p.active >= p.limit {
+		return 0, false
+	}
+	for prio := numPriorities - 1; prio >= 0; prio-- {
+		if len(p.ready[prio]) == 0 {
+			continue
+		}
+		if Priority(prio) == PriorityLow && p.limit > 1 && p.active >= p.limit-1 {
+			return 0, false
+		}
+		key := p.ready[prio][0]
+		p.ready[prio] = p.ready[prio][1:]
+		return key, true
+	}
+	return 0, false
+}
+
+// worker runs ready lanes one task at a time. After each task the lane goes to
+// the back of the ready list of its priority, so one busy session cannot
+// starve the others of the same priority.
+func (p *Pool) worker() {
+	defer p.wg.Done()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for {
+		var key int64
+		for {
+			if p.closed && p.pending == 0 {
+				return
+			}
+			var ok bool
+			if key, ok = p.next(); ok {
+				break
+			}
+			p.cond.Wait()
+		}
+
+		l := p.lanes[key]
+		task := l.tasks[0]
+		l.tasks = l.tasks[1:]
+		l.ready = false
+		l.running = true
+		p.pending--
+		p.byPrio[task.priority]--
+		p.active++
+		p.cond.Broadcast() // a queue slot was freed for blocked submitters
+		p.mu.Unlock()
+
+		start := time.Now()
+		err := runTask(p.ctx, task.run)
+		elapsed := time.Since(start)
+
+		p.mu.Lock()
+		p.active--
+		p.processed++
+		if err != nil {
+			p.failed++
+			log.Error().Err(err).Int64("key", key).Msg("Processing task failed")
+		}
+		p.observeLatency(elapsed)
+		l.running = false
+		if len(l.tasks) > 0 {
+			l.ready = true
+			l.refreshPriority()
+			p.ready[l.priority] = append(p.ready[l.priority], key)
+		} else {
+			delete(p.lanes, key)
+		}
+		p.cond.Broadcast()
+
+		if p.opts.OnIdle != nil && p.pending == 0 && p.active == 0 {
+			p.mu.Unlock()
+			p.opts.OnIdle()
+			p.mu.Lock()
+		}
+	}
+}
+
+// runTask runs task, converting a panic into an error so one bad message
+// cannot take down a worker.
+func runTask(ctx context.Context, task Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New("processing task panicked")
+			log.Error().Interface("panic", r).Msg("Recovered from panic in processing task")
+		}
+	}()
+	return task(ctx)
+}
+
+// observeLatency updates the latency average and the concurrency limit.
+// Caller holds p.mu.
+func (p *Pool) observeLatency(d time.Duration) {
+	if p.latency == 0 {
+		p.latency = d
+	} else {
+		p.latency = time.Duration(latencyAlpha*float64(d) + (1-latencyAlpha)*float64(p.latency))
+	}
+	if p.opts.SlowThreshold <= 0 {
+		return
+	}
+
+	switch {
+	case p.latency > p.opts.SlowThreshold && p.limit > 1:
+		p.limit = max(1, p.limit/2)
+		log.Warn().
+			Dur("avg_latency", p.latency).
+			Int("limit", p.limit).
+			Msg("Processing latency high, reducing worker concurrency")
+	case p.latency < p.opts.SlowThreshold/2 && p.limit < p.opts.Workers:
+		p.limit++
+		if p.limit == p.opts.Workers {
+			log.Info().Dur("avg_latency", p.latency).Msg("Processing latency recovered, full worker concurrency restored")
+		}
+	}
+}
+
+// Stats returns a snapshot of the pool.
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	byPriority := make(map[string]int, numPriorities)
+	for prio, n := range p.byPrio {
+		byPriority[Priority(prio).String()] = n
+	}
+	return Stats{
+		PendingByPriority: byPriority,
+		Workers:           p.opts.Workers,
+		Limit:             p.limit,
+		Active:            p.active,
+		Pending:           p.pending,
+		Processed:         p.processed,
+		Failed:            p.failed,
+		AvgLatencyMs:      float64(p.latency) / float64(time.Millisecond),
+		Throttled:         p.limit < p.opts.Workers,
+	}
+}
+
+// CountFailed adds n tasks that were dropped without running, e.g. rejected
+// by Submit and impossible to queue again, to the failed count.
+func (p *Pool) CountFailed(n int) {
+	p.mu.Lock()
+	p.failed += int64(n)
+	p.mu.Unlock()
+}
+
+// Busy reports whether any task is pending or running.
+func (p *Pool) Busy() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.pending > 0 || p.active > 0
+}
+
+// KeyBusy reports whether any task of key is pending or running.
+func (p *Pool) KeyBusy(key int64) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.lanes[key] // lanes are deleted once empty and idle
+	return ok
+}
+
+// Close stops accepting tasks and waits for queued tasks to finish. If ctx
+// expires first, running tasks see their context cancelled, queued tasks are
+// dropped and ctx.Err() is returned.
+func (p *Pool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	p.closed = true
+	p.cond.Broadcast()
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		p.mu.Lock()
+		dropped := p.pending
+		for _, l := range p.lanes {
+			l.tasks = nil // running lanes must not re-enter the ready list
+		}
+		p.lanes = make(map[int64]*lane)
+		p.ready = [numPriorities][]int64{}
+		p.pending = 0
+		p.byPrio = [numPriorities]int{}
+		p.cond.Broadcast()
+		p.mu.Unlock()
+		if dropped > 0 {
+			log.Warn().Int("dropped", dropped).Msg("Processing pool shutdown deadline exceeded, dropped queued tasks")
+		}
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,292 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool_PerKeyOrdering(t *testing.T) {
+	p := New(Options{Workers: 4})
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	got := make(map[int64][]int)
+	for i := 0; i < 50; i++ {
+		for key := int64(1); key <= 3; key++ {
+			i, key := i, key
+			if err := p.Submit(ctx, key, func(context.Context) error {
+				mu.Lock()
+				got[key] = append(got[key], i)
+				mu.Unlock()
+				return nil
+			}); err != nil {
+				t.Fatalf("Submit: %v", err)
+			}
+		}
+	}
+	if err := p.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	for key, seq := range got {
+		if len(seq) != 50 {
+			t.Fatalf("key %d ran %d tasks, want 50", key, len(seq))
+		}
+		for i, v := range seq {
+			if v != i {
+				t.Fatalf("key %d out of order at %d: got %d", key, i, v)
+			}
+		}
+	}
+	if st := p.Stats(); st.Processed != 150 || st.Pending != 0 {
+		t.Fatalf("stats = %+v, want 150 processed and nothing pending", st)
+	}
+}
+
+func TestPool_KeyBusy(t *testing.T) {
+	p := New(Options{Workers: 1})
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	if err := p.Submit(context.Background(), 1, func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	<-started
+	if err := p.Submit(context.Background(), 2, func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if !p.KeyBusy(1) || !p.KeyBusy(2) {
+		t.Fatal("KeyBusy = false for a running and a pending key, want true")
+	}
+	if p.KeyBusy(3) {
+		t.Fatal("KeyBusy(3) = true for a key without tasks")
+	}
+
+	close(release)
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if p.KeyBusy(1) || p.KeyBusy(2) {
+		t.Fatal("KeyBusy = true after all tasks finished")
+	}
+}
+
+func TestPool_ConcurrencyLimit(t *testing.T) {
+	p := New(Options{Workers: 3})
+	ctx := context.Background()
+
+	var running, peak atomic.Int32
+	for key := int64(0); key < 12; key++ {
+		if err := p.Submit(ctx, key, func(context.Context) error {
+			n := running.Add(1)
+			for {
+				old := peak.Load()
+				if n <= old || peak.CompareAndSwap(old, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+			return nil
+		}); err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+	}
+	if err := p.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if peak.Load() > 3 {
+		t.Fatalf("peak concurrency %d exceeds 3 workers", peak.Load())
+	}
+	if peak.Load() < 2 {
+		t.Fatalf("peak concurrency %d, want tasks of different keys to overlap", peak.Load())
+	}
+}
+
+func TestPool_SubmitBlocksWhenFull(t *testing.T) {
+	p := New(Options{Workers: 1, QueueSize: 1})
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	blocker := func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}
+	if err := p.Submit(context.Background(), 1, blocker); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	<-started
+	if err := p.Submit(context.Background(), 1, func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	// Queue is full (one pending): the next Submit must block until ctx ends.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := p.Submit(ctx, 2, func(context.Context) error { return nil })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Submit on full pool = %v, want deadline exceeded", err)
+	}
+
+	close(release)
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := p.Submit(context.Background(), 1, func(context.Context) error { return nil }); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Submit after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestPool_ThrottlesOnSlowTasks(t *testing.T) {
+	p := New(Options{Workers: 4, SlowThreshold: 5 * time.Millisecond})
+	ctx := context.Background()
+
+	for key := int64(0); key < 4; key++ {
+		if err := p.Submit(ctx, key, func(context.Context) error {
+			time.Sleep(15 * time.Millisecond)
+			return nil
+		}); err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+	}
+	if err := p.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	st := p.Stats()
+	if !st.Throttled || st.Limit >= 4 {
+		t.Fatalf("stats = %+v, want reduced concurrency after slow tasks", st)
+	}
+}
+
+func TestPool_FailuresAndPanicsAreCounted(t *testing.T) {
+	p := New(Options{Workers: 2})
+	ctx := context.Background()
+
+	_ = p.Submit(ctx, 1, func(context.Context) error { return errors.New("boom") })
+	_ = p.Submit(ctx, 2, func(context.Context) error { panic("bad message") })
+	_ = p.Submit(ctx, 3, func(context.Context) error { return nil })
+	if err := p.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if st := p.Stats(); st.Processed != 3 || st.Failed != 2 {
+		t.Fatalf("stats = %+v, want 3 processed / 2 failed", st)
+	}
+
+	p.CountFailed(2)
+	if st := p.Stats(); st.Processed != 3 || st.Failed != 4 {
+		t.Fatalf("stats = %+v, want 3 processed / 4 failed after CountFailed", st)
+	}
+}
+
+func TestPool_PriorityOrder(t *testing.T) {
+	p := New(Options{Workers: 1})
+	ctx := context.Background()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	if err := p.Submit(ctx, 0, func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	<-started
+
+	var mu sync.Mutex
+	var got []string
+	record := func(name string) Task {
+		return func(context.Context) error {
+			mu.Lock()
+			got = append(got, name)
+			mu.Unlock()
+			return nil
+		}
+	}
+	// Key 2's observation is queued ahead of its summary, so it inherits
+	// the summary's priority and still runs first within the key.
+	_ = p.SubmitPriority(ctx, 1, PriorityLow, record("obs1"))
+	_ = p.SubmitPriority(ctx, 2, PriorityLow, record("obs2"))
+	_ = p.SubmitPriority(ctx, 3, PriorityNormal, record("retry3"))
+	_ = p.SubmitPriority(ctx, 2, PriorityHigh, record("summary2"))
+	if st := p.Stats(); st.PendingByPriority["low"] != 2 || st.PendingByPriority["high"] != 1 {
+		t.Fatalf("pending by priority = %v, want 2 low and 1 high", st.PendingByPriority)
+	}
+	close(release)
+	if err := p.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	want := []string{"obs2", "summary2", "retry3", "obs1"}
+	if len(got) != len(want) {
+		t.Fatalf("ran %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("ran %v, want %v", got, want)
+		}
+	}
+}
+
+func TestPool_HighPriorityPreemptsQueue(t *testing.T) {
+	p := New(Options{Workers: 2, QueueSize: 1})
+	ctx := context.Background()
+	release := make(chan struct{})
+	defer func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+	}()
+
+	var lowRunning atomic.Int32
+	blockLow := func(context.Context) error {
+		lowRunning.Add(1)
+		<-release
+		return nil
+	}
+	for key := int64(1); key <= 2; key++ {
+		if err := p.SubmitPriority(ctx, key, PriorityLow, blockLow); err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+	}
+
+	for deadline := time.Now().Add(time.Second); lowRunning.Load() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("first low priority task did not start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The queue is full and one worker is busy with a low priority task.
+	// The other is kept free, so a high priority task is admitted and runs.
+	ran := make(chan struct{})
+	if err := p.SubmitPriority(ctx, 3, PriorityHigh, func(context.Context) error {
+		close(ran)
+		return nil
+	}); err != nil {
+		t.Fatalf("SubmitPriority: %v", err)
+	}
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("high priority task did not run while low priority tasks held the pool")
+	}
+	if n := lowRunning.Load(); n != 1 {
+		t.Fatalf("%d low priority tasks running, want 1 with one worker kept free", n)
+	}
+
+	close(release)
+	if err := p.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package relinfer
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/thebtf/engram/pkg/models"
+	"github.com/thebtf/engram/pkg/strutil"
+)
+
+// relatedFileExamples caps the memories quoted as reasons per related file.
+const relatedFileExamples = 3
+
+// RelatedFile is a file that memories mention together with a target file.
+type RelatedFile struct {
+	Path string `json:"path"`
+	// Reasons explain the ranking: the co-mention count, the memories that
+	// mention both files and whether the files share a directory.
+	Reasons []string `json:"reasons"`
+	// MemoryIDs are the memories mentioning both files, newest first.
+	MemoryIDs []int64 `json:"memory_ids"`
+	// Score is the number of memories mentioning both files.
+	Score int `json:"score"`
+}
+
+// RelatedFiles ranks the files mentioned (file: tags and paths in the
+// content) together with target by memories, which are expected newest
+// first. A memory path matches target when equal or when one is a suffix of
+// the other at a directory boundary, so relative and absolute paths meet.
+// It returns the number of memories mentioning target and at most limit
+// related files, most co-mentions first, ties broken by the newest memory.
+func RelatedFiles(target string, memories []*models.Memory, limit int) (int, []RelatedFile) {
+	target = path.Clean(strings.TrimSpace(target))
+	type entry struct {
+		file   *RelatedFile
+		newest int // index of the newest memory mentioning both
+	}
+	related := make(map[string]*entry)
+	touching := 0
+
+	for i, mem := range memories {
+		files := memoryFiles(mem)
+		matched := false
+		for f := range files {
+			if SamePath(f, target) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		touching++
+		quote := fmt.Sprintf("#%d: %s", mem.ID, firstLine(mem.Content))
+		for f := range files {
+			if SamePath(f, target) {
+				continue
+			}
+			e, ok := related[f]
+			if !ok {
+				e = &entry{file: &RelatedFile{Path: f}, newest: i}
+				related[f] = e
+			}
+			e.file.Score++
+			e.file.MemoryIDs = append(e.file.MemoryIDs, mem.ID)
+			if len(e.file.Reasons) < relatedFileExamples {
+				e.file.Reasons = append(e.file.Reasons, quote)
+			}
+		}
+	}
+
+	entries := make([]*entry, 0, len(related))
+	for _, e := range related {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.file.Score != b.file.Score {
+			return a.file.Score > b.file.Score
+		}
+		if a.newest != b.newest {
+			return a.newest < b.newest
+		}
+		return a.file.Path < b.file.Path
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	out := make([]RelatedFile, len(entries))
+	for i, e := range entries {
+		f := *e.file
+		summary := fmt.Sprintf("mentioned with %s in %d of %d memories", target, f.Score, touching)
+		if path.Dir(f.Path) == path.Dir(target) {
+			summary += "; same directory"
+		}
+		f.Reasons = append([]string{summary}, f.Reasons...)
+		out[i] = f
+	}
+	return touching, out
+}
+
+// SamePath reports whether a and b name the same file, allowing one to be a
+// longer form of the other ("/repo/internal/x.go" and "internal/x.go").
+func SamePath(a, b string) bool {
+	return a == b || strings.HasSuffix(a, "/"+b) || strings.HasSuffix(b, "/"+a)
+}
+
+// firstLine returns the first non-empty line of content, truncated.
+func firstLine(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return strutil.Truncate(line, 80)
+		}
+	}
+	return ""
+}
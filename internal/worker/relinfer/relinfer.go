@@ -0,0 +1,339 @@
+// Package relinfer provides a periodic job that infers relations between
+// memories from co-occurrence, so the relation graph grows without manual
+// curation (create_relation).
+//
+// Three signals are used, each with its own relation type:
+//   - shared files (file: tags and paths in the content): relates_to
+//   - session adjacency (the same agent storing memories minutes apart): follows
+//   - content similarity (term Jaccard over content and tags): similar_to
+//
+// Symmetric relations are stored in both directions, as create_relation does.
+// Existing relations are left untouched: the store ignores duplicates.
+package relinfer
+
+import (
+	"context"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/internal/tenant"
+	"github.com/thebtf/engram/pkg/models"
+	"github.com/thebtf/engram/pkg/similarity"
+)
+
+const (
+	// defaultInterval is how often the inference sweep runs.
+	defaultInterval = 1 * time.Hour
+
+	// memoriesPerProject caps how many of a project's newest memories one
+	// sweep compares; pairs are compared exhaustively.
+	memoriesPerProject = 500
+
+	// maxPerMemory caps the inferred relations of one kind per memory, keeping
+	// the strongest, so a memory touching a hot file does not link to all others.
+	maxPerMemory = 5
+
+	// adjacencyWindow is the largest gap between two memories of the same
+	// agent for the newer one to follow the older.
+	adjacencyWindow = 30 * time.Minute
+
+	// similarityThreshold is the minimum term Jaccard for similar_to.
+	similarityThreshold = 0.5
+)
+
+// Stats summarizes one inference sweep.
+type Stats struct {
+	Projects  int `json:"projects"`
+	Memories  int `json:"memories"`
+	Relations int `json:"relations"`
+}
+
+// Inferrer periodically infers relations for projects with recent memories.
+type Inferrer struct {
+	memories  *gorm.MemoryStore
+	relations *gorm.RelationStore
+	mu        sync.Mutex // serializes sweeps and guards lastRun
+	lastRun   time.Time
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// New creates an Inferrer backed by the given stores.
+func New(memories *gorm.MemoryStore, relations *gorm.RelationStore) *Inferrer {
+	return &Inferrer{
+		memories:  memories,
+		relations: relations,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start launches the inference loop in a background goroutine. It respects
+// ctx for graceful shutdown and also responds to Stop(). Returns immediately.
+// An interval of 0 (ENGRAM_RELATION_INFERENCE_INTERVAL=0) disables the loop;
+// RunOnce still works.
+func (inf *Inferrer) Start(ctx context.Context) {
+	interval := inferenceInterval()
+	if interval <= 0 {
+		close(inf.done)
+		log.Info().Msg("relation inference disabled")
+		return
+	}
+	log.Info().Dur("interval", interval).Msg("relation inference started")
+
+	go func() {
+		defer close(inf.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-inf.stop:
+				return
+			case <-ticker.C:
+				stats, err := inf.RunOnce(ctx)
+				if err != nil {
+					log.Error().Err(err).Msg("relation inference: sweep failed")
+					continue
+				}
+				if stats.Relations > 0 {
+					log.Info().
+						Int("projects", stats.Projects).
+						Int("memories", stats.Memories).
+						Int("relations", stats.Relations).
+						Msg("relation inference: stored inferred relations")
+				}
+			}
+		}
+	}()
+}
+
+// Stop signals the loop to cease and waits for the goroutine to exit.
+func (inf *Inferrer) Stop() {
+	select {
+	case <-inf.stop:
+		// Already closed — idempotent.
+	default:
+		close(inf.stop)
+	}
+	<-inf.done
+}
+
+// RunOnce runs a single sweep synchronously over the projects with memories
+// changed since the previous sweep (all projects on the first one), in every
+// tenant whatever tenant ctx carries. Each project is read in its own tenant,
+// so relations never link memories of different tenants. Concurrent calls
+// are serialized.
+func (inf *Inferrer) RunOnce(ctx context.Context) (Stats, error) {
+	inf.mu.Lock()
+	defer inf.mu.Unlock()
+
+	var stats Stats
+	started := time.Now()
+
+	projects, err := inf.memories.ListRecentProjects(ctx, inf.lastRun)
+	if err != nil {
+		return stats, err
+	}
+	for _, p := range projects {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+		mems, err := inf.memories.List(tenant.WithTenant(ctx, p.Tenant), p.Project, memoriesPerProject)
+		if err != nil {
+			return stats, err
+		}
+		relations := Infer(mems)
+		if err := inf.relations.StoreRelations(ctx, relations); err != nil {
+			return stats, err
+		}
+		stats.Projects++
+		stats.Memories += len(mems)
+		stats.Relations += len(relations)
+	}
+	inf.lastRun = started
+	return stats, nil
+}
+
+// candidate is an inferred relation before the per-memory cap is applied.
+// Source is the newer memory of the pair.
+type candidate struct {
+	relType    models.RelationType
+	detection  models.RelationDetectionSource
+	reason     string
+	source     int64
+	target     int64
+	confidence float64
+}
+
+// Infer returns the relations implied by co-occurrence among memories, which
+// are expected to belong to one project.
+func Infer(memories []*models.Memory) []*models.ObservationRelation {
+	mems := make([]*models.Memory, len(memories))
+	copy(mems, memories)
+	sort.SliceStable(mems, func(i, j int) bool {
+		if !mems[i].CreatedAt.Equal(mems[j].CreatedAt) {
+			return mems[i].CreatedAt.After(mems[j].CreatedAt)
+		}
+		return mems[i].ID > mems[j].ID
+	})
+
+	files := make([]map[string]bool, len(mems))
+	terms := make([]map[string]bool, len(mems))
+	for i, mem := range mems {
+		files[i] = memoryFiles(mem)
+		terms[i] = similarity.ExtractMemoryTerms(mem)
+	}
+
+	var candidates []candidate
+	for i, newer := range mems {
+		for j := i + 1; j < len(mems); j++ {
+			older := mems[j]
+			if shared := sharedKeys(files[i], files[j]); len(shared) > 0 {
+				overlap := similarity.JaccardSimilarity(files[i], files[j])
+				candidates = append(candidates, candidate{
+					relType:    models.RelationRelatesTo,
+					detection:  models.DetectionSourceFileOverlap,
+					reason:     "shared files: " + strings.Join(shared, ", "),
+					source:     newer.ID,
+					target:     older.ID,
+					confidence: 0.5 + 0.4*overlap,
+				})
+			}
+			if sim := similarity.JaccardSimilarity(terms[i], terms[j]); len(terms[i]) > 0 && sim >= similarityThreshold {
+				candidates = append(candidates, candidate{
+					relType:    models.RelationSimilarTo,
+					detection:  models.DetectionSourceConceptOverlap,
+					reason:     "similar content",
+					source:     newer.ID,
+					target:     older.ID,
+					confidence: sim,
+				})
+			}
+		}
+		if prev := previousByAgent(mems, i); prev != nil {
+			gap := newer.CreatedAt.Sub(prev.CreatedAt)
+			candidates = append(candidates, candidate{
+				relType:    models.RelationFollows,
+				detection:  models.DetectionSourceTemporalProximity,
+				reason:     "stored " + gap.Round(time.Second).String() + " later by " + newer.SourceAgent,
+				source:     newer.ID,
+				target:     prev.ID,
+				confidence: 0.8 - 0.3*float64(gap)/float64(adjacencyWindow),
+			})
+		}
+	}
+
+	var relations []*models.ObservationRelation
+	for _, c := range capPerMemory(candidates) {
+		relations = append(relations, models.NewObservationRelation(c.source, c.target, c.relType, c.confidence, c.detection, c.reason))
+		if inverse, ok := models.InverseRelationType(c.relType); ok {
+			relations = append(relations, models.NewObservationRelation(c.target, c.source, inverse, c.confidence, c.detection, c.reason))
+		}
+	}
+	return relations
+}
+
+// previousByAgent returns the memory the same agent stored just before
+// mems[i], when it falls within adjacencyWindow. mems is newest first.
+func previousByAgent(mems []*models.Memory, i int) *models.Memory {
+	agent := mems[i].SourceAgent
+	if agent == "" {
+		return nil
+	}
+	for j := i + 1; j < len(mems); j++ {
+		if mems[i].CreatedAt.Sub(mems[j].CreatedAt) > adjacencyWindow {
+			return nil
+		}
+		if mems[j].SourceAgent == agent {
+			return mems[j]
+		}
+	}
+	return nil
+}
+
+// capPerMemory keeps, per relation type, at most maxPerMemory candidates
+// touching any one memory, preferring higher confidence.
+func capPerMemory(candidates []candidate) []candidate {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].confidence > candidates[j].confidence
+	})
+	type key struct {
+		relType models.RelationType
+		id      int64
+	}
+	count := make(map[key]int)
+	kept := candidates[:0]
+	for _, c := range candidates {
+		src, tgt := key{c.relType, c.source}, key{c.relType, c.target}
+		if count[src] >= maxPerMemory || count[tgt] >= maxPerMemory {
+			continue
+		}
+		count[src]++
+		count[tgt]++
+		kept = append(kept, c)
+	}
+	return kept
+}
+
+// memoryFiles returns the file paths a memory mentions, from file: tags and
+// path-like words in the content.
+func memoryFiles(mem *models.Memory) map[string]bool {
+	return MentionedFiles(mem.Tags, mem.Content)
+}
+
+// MentionedFiles returns the file paths named by file: tags and by
+// path-like words ("internal/db/store.go") in content, cleaned.
+func MentionedFiles(tags []string, content string) map[string]bool {
+	files := make(map[string]bool)
+	for _, tag := range tags {
+		if p, ok := strings.CutPrefix(tag, "file:"); ok && p != "" {
+			files[path.Clean(p)] = true
+		}
+	}
+	for _, word := range strings.FieldsFunc(content, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune("`'\"()[]{},;<>", r)
+	}) {
+		word = strings.TrimRight(word, ".:")
+		// A path needs a directory and an extension: "internal/db/store.go".
+		if strings.Contains(word, "/") && path.Ext(word) != "" && !strings.Contains(word, "://") {
+			files[path.Clean(word)] = true
+		}
+	}
+	return files
+}
+
+// sharedKeys returns the keys present in both sets, sorted.
+func sharedKeys(a, b map[string]bool) []string {
+	var shared []string
+	for k := range a {
+		if b[k] {
+			shared = append(shared, k)
+		}
+	}
+	sort.Strings(shared)
+	return shared
+}
+
+// inferenceInterval returns the sweep interval. Reads
+// ENGRAM_RELATION_INFERENCE_INTERVAL (a Go duration, "0" disables); falls
+// back to defaultInterval.
+func inferenceInterval() time.Duration {
+	if v := os.Getenv("ENGRAM_RELATION_INFERENCE_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			return d
+		}
+	}
+	return defaultInterval
+}
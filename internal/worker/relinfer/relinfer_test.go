@@ -0,0 +1,99 @@
+package relinfer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func relationKeys(relations []*models.ObservationRelation) map[string]bool {
+	keys := make(map[string]bool, len(relations))
+	for _, r := range relations {
+		keys[fmt.Sprintf("%s:%d->%d", r.RelationType, r.SourceID, r.TargetID)] = true
+	}
+	return keys
+}
+
+func TestInfer(t *testing.T) {
+	base := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	mems := []*models.Memory{
+		{ID: 1, CreatedAt: base, SourceAgent: "claude", Content: "Token refresh lives in internal/auth/token.go", Tags: []string{"type:decision"}},
+		{ID: 2, CreatedAt: base.Add(10 * time.Minute), SourceAgent: "claude", Content: "Fixed expiry math", Tags: []string{"file:internal/auth/token.go"}},
+		{ID: 3, CreatedAt: base.Add(3 * time.Hour), SourceAgent: "codex", Content: "Billing exports run nightly via cron"},
+		{ID: 4, CreatedAt: base.Add(4 * time.Hour), SourceAgent: "claude", Content: "Billing exports run nightly via cron job"},
+	}
+
+	keys := relationKeys(Infer(mems))
+
+	// Shared file: relates_to in both directions.
+	assert.True(t, keys["relates_to:2->1"])
+	assert.True(t, keys["relates_to:1->2"])
+	// Same agent ten minutes apart; the next claude memory is hours later.
+	assert.True(t, keys["follows:2->1"])
+	assert.False(t, keys["follows:4->2"])
+	// Near-identical content: similar_to both ways.
+	assert.True(t, keys["similar_to:4->3"])
+	assert.True(t, keys["similar_to:3->4"])
+	assert.False(t, keys["similar_to:3->1"])
+}
+
+func TestInfer_CapsRelationsPerMemory(t *testing.T) {
+	base := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	var mems []*models.Memory
+	for i := int64(1); i <= 9; i++ {
+		mems = append(mems, &models.Memory{
+			ID:        i,
+			CreatedAt: base.Add(time.Duration(i) * time.Hour),
+			Content:   "note",
+			Tags:      []string{"file:go.mod"},
+		})
+	}
+
+	perMemory := make(map[int64]int)
+	for _, r := range Infer(mems) {
+		if r.RelationType == models.RelationRelatesTo {
+			perMemory[r.SourceID]++
+		}
+	}
+	for id, n := range perMemory {
+		assert.LessOrEqual(t, n, maxPerMemory, "memory %d", id)
+	}
+}
+
+func TestMemoryFiles(t *testing.T) {
+	mem := &models.Memory{
+		Content: "See `internal/db/store.go` and https://example.com/a.html, not v1.2 or a/b",
+		Tags:    []string{"file:./cmd/main.go", "type:bugfix"},
+	}
+	assert.Equal(t, map[string]bool{"internal/db/store.go": true, "cmd/main.go": true}, memoryFiles(mem))
+}
+
+func TestRelatedFiles(t *testing.T) {
+	mems := []*models.Memory{
+		{ID: 4, Content: "Cache invalidation lives in internal/cache/lru.go", Tags: []string{"file:/repo/internal/db/store.go"}},
+		{ID: 3, Content: "Unrelated change to cmd/main.go"},
+		{ID: 2, Content: "store.go retries use internal/db/retry.go\nmore detail", Tags: []string{"file:internal/db/store.go"}},
+		{ID: 1, Content: "Schema change touched internal/db/store.go and internal/db/retry.go and internal/cache/lru.go"},
+	}
+
+	touching, related := RelatedFiles("internal/db/store.go", mems, 10)
+	assert.Equal(t, 3, touching)
+	require.Len(t, related, 2)
+
+	// Two co-mentions each; lru.go was mentioned by the newer memory.
+	assert.Equal(t, "internal/cache/lru.go", related[0].Path)
+	assert.Equal(t, 2, related[0].Score)
+	assert.Equal(t, []int64{4, 1}, related[0].MemoryIDs)
+
+	assert.Equal(t, "internal/db/retry.go", related[1].Path)
+	assert.Equal(t, "mentioned with internal/db/store.go in 2 of 3 memories; same directory", related[1].Reasons[0])
+	assert.Equal(t, "#2: store.go retries use internal/db/retry.go", related[1].Reasons[1])
+
+	_, related = RelatedFiles("internal/db/store.go", mems, 1)
+	assert.Len(t, related, 1)
+}
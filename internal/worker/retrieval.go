@@ -6,6 +6,11 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/thebtf/engram/internal/concepts"
+	"github.com/thebtf/engram/internal/tracing"
 	"github.com/thebtf/engram/internal/worker/sdk"
 	"github.com/thebtf/engram/pkg/models"
 )
@@ -21,7 +26,16 @@ type RetrievalOptions struct {
 	MaxResults   int
 	SessionID    string
 	UseLLMFilter bool
-	FilePaths    []string
+	// FilePaths are the files the user has open or recently edited.
+	// Observations mentioning one rank higher, and are returned even
+	// without a text match (see applyFileBoost).
+	FilePaths []string
+	// Language, when set, moves results in that ISO 639-1 language ahead of
+	// the rest without dropping anything.
+	Language string
+	// BoostConcepts, when set, adds observations tagged with these concepts
+	// to the text matches and ranks by the blend (see applyConceptBoost).
+	BoostConcepts []concepts.Concept
 }
 
 type retrievalContextKey struct{}
@@ -86,6 +100,13 @@ func (s *Service) RetrieveRelevant(ctx context.Context, project, query string, o
 	if limit <= 0 {
 		limit = DefaultSearchLimit
 	}
+	ctx, span := tracing.Start(ctx, "retrieval.relevant",
+		attribute.String("engram.project", project),
+		attribute.Int("engram.limit", limit),
+		attribute.Bool("engram.llm_filter", opts.UseLLMFilter),
+	)
+	defer span.End()
+
 	state := retrievalStateFromContext(ctx)
 	metadata := state.metadata
 	threshold := s.getProjectThreshold(ctx, project)
@@ -104,10 +125,31 @@ func (s *Service) RetrieveRelevant(ctx context.Context, project, query string, o
 	scopeFilter := retrievalScope{Project: project, AgentID: state.agentID}
 	fallbackObservations, fallbackErr := s.searchFallbackObservations(ctx, query, scopeFilter, limit)
 	if fallbackErr != nil {
+		span.RecordError(fallbackErr)
+		span.SetStatus(codes.Error, fallbackErr.Error())
 		return nil, nil, fallbackErr
 	}
 	observations = fallbackObservations
 	_ = baseSimilarityScores // no vector scores in v5
+	if len(opts.BoostConcepts) > 0 {
+		candidates, candidateErr := s.searchFallbackObservations(ctx, "", scopeFilter, limit*conceptCandidateMultiplier)
+		if candidateErr != nil {
+			span.RecordError(candidateErr)
+			span.SetStatus(codes.Error, candidateErr.Error())
+			return nil, nil, candidateErr
+		}
+		observations, similarityScores = applyConceptBoost(observations, candidates, opts.BoostConcepts)
+	}
+	if working := cleanWorkingFiles(opts.FilePaths); len(working) > 0 {
+		candidates, candidateErr := s.searchFallbackObservations(ctx, "", scopeFilter, limit*conceptCandidateMultiplier)
+		if candidateErr != nil {
+			span.RecordError(candidateErr)
+			span.SetStatus(codes.Error, candidateErr.Error())
+			return nil, nil, candidateErr
+		}
+		observations, similarityScores = applyFileBoost(observations, candidates, working, similarityScores)
+		span.SetAttributes(attribute.Int("engram.working_files", len(working)))
+	}
 
 	freshObservations, staleCount := s.filterFreshObservations(ctx, observations, state.cwd)
 	freshCount := len(freshObservations)
@@ -119,11 +161,11 @@ func (s *Service) RetrieveRelevant(ctx context.Context, project, query string, o
 	duplicatesRemoved := len(freshObservations) - len(clusteredObservations)
 	// search.ApplyCompositeScoring / ApplyLaneWeights / ApplyDiversityPenalty /
 	// ApplySessionBoost all lived in internal/search which was dropped in v5 (US9).
-	// With no vector scores (similarityScores is always empty in v5 FTS-only mode)
-	// these scoring passes were no-ops anyway — observations are already ordered by
-	// FTS rank + importance from the DB query.
+	// With no vector scores (similarityScores is empty in v5 FTS-only mode unless
+	// concept boosting filled it) these scoring passes were no-ops anyway —
+	// observations are already ordered by FTS rank + importance from the DB query.
 	if len(similarityScores) > 0 && len(clusteredObservations) > 0 {
-		sort.Slice(clusteredObservations, func(i, j int) bool {
+		sort.SliceStable(clusteredObservations, func(i, j int) bool {
 			return similarityScores[clusteredObservations[i].ID] > similarityScores[clusteredObservations[j].ID]
 		})
 	}
@@ -139,12 +181,16 @@ func (s *Service) RetrieveRelevant(ctx context.Context, project, query string, o
 			}
 		}
 	}
+	clusteredObservations = preferLanguage(clusteredObservations, opts.Language)
 	if limit > 0 && len(clusteredObservations) > limit {
 		clusteredObservations = clusteredObservations[:limit]
 	}
 	if opts.UseLLMFilter {
-		clusteredObservations = s.applyLLMFilter(ctx, project, query, clusteredObservations)
+		filterCtx, filterSpan := tracing.Start(ctx, "retrieval.llm_filter", attribute.Int("engram.candidates", len(clusteredObservations)))
+		clusteredObservations = s.applyLLMFilter(filterCtx, project, query, clusteredObservations)
+		filterSpan.End()
 	}
+	span.SetAttributes(attribute.Int("engram.results", len(clusteredObservations)), attribute.Int("engram.stale", staleCount))
 	if metadata != nil {
 		metadata.usedVector = false // vector search removed in v5
 		metadata.totalResults = totalResults
@@ -155,10 +201,22 @@ func (s *Service) RetrieveRelevant(ctx context.Context, project, query string, o
 	return clusteredObservations, similarityScores, nil
 }
 
+// preferLanguage stably moves observations in language ahead of the others.
+// Observations of unknown language keep their place relative to the rest.
+func preferLanguage(observations []*models.Observation, language string) []*models.Observation {
+	if language == "" {
+		return observations
+	}
+	sort.SliceStable(observations, func(i, j int) bool {
+		return observations[i].Language == language && observations[j].Language != language
+	})
+	return observations
+}
+
 func (s *Service) getProjectThreshold(ctx context.Context, project string) float64 {
 	globalDefault := 0.3
-	if s.config != nil && s.config.ContextRelevanceThreshold > 0 {
-		globalDefault = s.config.ContextRelevanceThreshold
+	if cfg := s.currentConfig(); cfg != nil && cfg.ContextRelevanceThreshold > 0 {
+		globalDefault = cfg.ContextRelevanceThreshold
 	}
 	if s.retrievalHooks != nil && s.retrievalHooks.getProjectThreshold != nil {
 		return s.retrievalHooks.getProjectThreshold(ctx, project, globalDefault)
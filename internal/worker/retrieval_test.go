@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/thebtf/engram/internal/concepts"
 	"github.com/thebtf/engram/internal/config"
 	"github.com/thebtf/engram/pkg/models"
 )
@@ -143,3 +144,75 @@ func newObservation(id int64, title string) *models.Observation {
 		Title: sql.NullString{String: title, Valid: title != ""},
 	}
 }
+
+// TestRetrieveRelevant_PrefersLanguage verifies that results in the requested
+// language move ahead of the others before MaxResults is applied.
+func TestRetrieveRelevant_PrefersLanguage(t *testing.T) {
+	service := newRetrievalTestService()
+	service.retrievalHooks.searchObservationsFTSFiltered = func(_ context.Context, _ string, _ retrievalScope, _ int) ([]*models.Observation, error) {
+		english := newObservation(1, "Cache invalidation order")
+		english.Language = "en"
+		unknown := newObservation(2, "Gravity kernel panic")
+		german := newObservation(3, "Lunar lattice cipher")
+		german.Language = "de"
+		return []*models.Observation{english, unknown, german}, nil
+	}
+	observations, _, err := service.RetrieveRelevant(context.Background(), "engram", "query", RetrievalOptions{MaxResults: 2, Language: "de"})
+	require.NoError(t, err)
+	require.Len(t, observations, 2)
+	require.Equal(t, int64(3), observations[0].ID)
+	require.Equal(t, int64(1), observations[1].ID)
+}
+
+// TestRetrieveRelevant_BoostConcepts verifies that concept boosting adds
+// observations sharing the prompt's concepts and ranks text matches with
+// concept overlap first.
+func TestRetrieveRelevant_BoostConcepts(t *testing.T) {
+	service := newRetrievalTestService()
+	plain := newObservation(1, "Retry budget for the sync job")
+	tagged := newObservation(2, "Retry budget for the export job")
+	tagged.Concepts = []string{"testing"}
+	conceptOnly := newObservation(3, "Fixture cleanup order")
+	conceptOnly.Concepts = []string{"concept:testing"}
+	unrelated := newObservation(4, "Gravity kernel panic")
+	service.retrievalHooks.searchObservationsFTSFiltered = func(_ context.Context, query string, _ retrievalScope, _ int) ([]*models.Observation, error) {
+		if query == "" {
+			return []*models.Observation{conceptOnly, unrelated, plain, tagged}, nil
+		}
+		return []*models.Observation{plain, tagged}, nil
+	}
+
+	boost := concepts.Extract("why are the retry tests flaky", concepts.DefaultMinConfidence)
+	require.NotEmpty(t, boost)
+	observations, scores, err := service.RetrieveRelevant(context.Background(), "engram", "retry", RetrievalOptions{MaxResults: 5, BoostConcepts: boost})
+	require.NoError(t, err)
+	require.Len(t, observations, 3)
+	require.Equal(t, []int64{2, 1, 3}, []int64{observations[0].ID, observations[1].ID, observations[2].ID})
+	require.Greater(t, scores[1], scores[3], "a text match outranks a concept-only match")
+}
+
+// TestRetrieveRelevant_BoostWorkingFiles verifies that observations mentioning
+// a file the user is working on rank first, relative and absolute paths
+// match, and file-only matches are added after the text matches.
+func TestRetrieveRelevant_BoostWorkingFiles(t *testing.T) {
+	service := newRetrievalTestService()
+	plain := newObservation(1, "Retry budget for the sync job")
+	touching := newObservation(2, "Retry budget for the export job")
+	touching.Narrative = sql.NullString{String: "Raised the retry budget in internal/export/job.go", Valid: true}
+	fileOnly := newObservation(3, "Fixture cleanup order")
+	fileOnly.FilesModified = []string{"/home/dev/repo/internal/sync/fixtures.go"}
+	unrelated := newObservation(4, "Gravity kernel panic")
+	service.retrievalHooks.searchObservationsFTSFiltered = func(_ context.Context, query string, _ retrievalScope, _ int) ([]*models.Observation, error) {
+		if query == "" {
+			return []*models.Observation{fileOnly, unrelated, plain, touching}, nil
+		}
+		return []*models.Observation{plain, touching}, nil
+	}
+
+	working := []string{`internal\export\job.go`, "internal/sync/fixtures.go", " "}
+	observations, scores, err := service.RetrieveRelevant(context.Background(), "engram", "retry", RetrievalOptions{MaxResults: 5, FilePaths: working})
+	require.NoError(t, err)
+	require.Equal(t, []int64{2, 1, 3}, []int64{observations[0].ID, observations[1].ID, observations[2].ID})
+	require.Greater(t, scores[1], scores[3], "a text match outranks a file-only match")
+	require.Equal(t, []string{"internal/export/job.go"}, matchedWorkingFiles(touching, cleanWorkingFiles(working)))
+}
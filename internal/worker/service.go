@@ -9,6 +9,8 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -32,10 +34,18 @@ import (
 	"github.com/thebtf/engram/internal/mcp"
 	"github.com/thebtf/engram/internal/sessions"
 	"github.com/thebtf/engram/internal/telemetry"
+	"github.com/thebtf/engram/internal/tenant"
+	"github.com/thebtf/engram/internal/tracing"
 	"github.com/thebtf/engram/internal/update"
 	"github.com/thebtf/engram/internal/watcher"
+	"github.com/thebtf/engram/internal/worker/backup"
+	"github.com/thebtf/engram/internal/worker/eventprune"
+	"github.com/thebtf/engram/internal/worker/jobs"
+	"github.com/thebtf/engram/internal/worker/notify"
+	"github.com/thebtf/engram/internal/worker/pool"
 	"github.com/thebtf/engram/internal/worker/projectevents"
 	"github.com/thebtf/engram/internal/worker/reaper"
+	"github.com/thebtf/engram/internal/worker/relinfer"
 	"github.com/thebtf/engram/internal/worker/sdk"
 	"github.com/thebtf/engram/internal/worker/session"
 	"github.com/thebtf/engram/internal/worker/sse"
@@ -98,6 +108,8 @@ type Service struct {
 	retrievalStats         map[string]*RetrievalStats
 	sessionStore           *gorm.SessionStore
 	tokenStore             *gorm.TokenStore
+	tenantStore            *gorm.TenantStore
+	processingPool         *pool.Pool
 	cancel                 context.CancelFunc
 	cachedObsCounts        map[string]cachedCount
 	config                 *config.Config
@@ -110,37 +122,54 @@ type Service struct {
 	expensiveOpLimiter     *ExpensiveOperationLimiter
 	logBuffer              *logbuf.RingBuffer
 	backfillTracker        *backfillTracker
+	jobManager             *jobs.Manager
 	grpcServer             *googlegrpc.Server
 	grpcInternalServer     sessionStartContextProvider
 	searchQueryLogStore    *gorm.SearchQueryLogStore
 	retrievalStatsLogStore *gorm.RetrievalStatsLogStore
+	memoryAccessStore      *gorm.MemoryAccessStore
 	injectionStore         *gorm.InjectionStore
+	experimentStore        *gorm.ExperimentStore
 	agentStatsStore        *gorm.AgentStatsStore
 	versionStore           *gorm.VersionStore
 	retrievalHooks         *retrievalHooks
 	authHandlers           *AuthHandlers
 	version                string
+	openAPIDoc             []byte
+	openAPIErr             error
+	openAPIOnce            sync.Once
 	recentQueriesBuf       [maxRecentQueries]RecentSearchQuery
 	wg                     sync.WaitGroup
 	recentQueriesLen       int
 	recentQueriesHead      int
 	statsCacheTTL          time.Duration
 	initMu                 sync.RWMutex
+	configMu               sync.RWMutex // guards config, swapped by reloadConfig
 	retrievalStatsMu       sync.RWMutex
 	recentQueriesMu        sync.RWMutex
 	cachedObsCountsMu      sync.RWMutex
 	staleQueueOnce         sync.Once
 	ready                  atomic.Bool
+	draining               atomic.Bool   // set by POST /api/admin/drain; new observations are refused
+	initDone               chan struct{} // closed once initialization succeeds or fails
+	initDoneOnce           sync.Once
 	vault                  *crypto.Vault
 	issueStore             *gorm.IssueStore
 	credentialStore        *gorm.CredentialStore
 	memoryStore            *gorm.MemoryStore
 	behavioralRulesStore   *gorm.BehavioralRulesStore
+	auditLogStore          *gorm.AuditLogStore
+	storageStatsStore      *gorm.StorageStatsStore
 	vaultOnce              sync.Once
 	vaultErr               error
 	promptCache            sync.Map // map[int64]promptCacheEntry — last user prompt per session
 	eventBus               *projectevents.Bus
+	notifier               *notify.Notifier // outbound webhooks (ENGRAM_OUTBOUND_WEBHOOKS)
 	projectReaper          *reaper.Reaper
+	relationInferrer       *relinfer.Inferrer
+	eventPruner            *eventprune.Pruner
+	backuper               *backup.Backuper
+	restartCh              chan struct{}
 }
 
 // promptCacheEntry stores a user prompt with a timestamp for eviction.
@@ -162,17 +191,6 @@ func (s *Service) GetLastPrompt(sessionID int64) string {
 	return ""
 }
 
-// evictStalePrompts removes prompt cache entries older than 2 hours.
-func (s *Service) evictStalePrompts() {
-	cutoff := time.Now().Add(-2 * time.Hour)
-	s.promptCache.Range(func(key, value any) bool {
-		if entry, ok := value.(promptCacheEntry); ok && entry.Timestamp.Before(cutoff) {
-			s.promptCache.Delete(key)
-		}
-		return true
-	})
-}
-
 // cachedCount stores a cached count value with expiration.
 type cachedCount struct {
 	timestamp time.Time
@@ -183,7 +201,7 @@ type cachedCount struct {
 // All errors are cached — a misconfigured vault fails permanently (no retry).
 func (s *Service) getVault() (*crypto.Vault, error) {
 	s.vaultOnce.Do(func() {
-		s.vault, s.vaultErr = crypto.NewVault(s.config)
+		s.vault, s.vaultErr = crypto.NewVault(s.currentConfig())
 	})
 	return s.vault, s.vaultErr
 }
@@ -232,6 +250,9 @@ func (s *Service) setupCallbacks(
 // The service starts immediately with health endpoint available,
 // while database and SDK initialization happens in the background.
 func NewService(version string, logBuffer *logbuf.RingBuffer) (*Service, error) {
+	if err := checkSettings(); err != nil {
+		return nil, err
+	}
 	cfg := config.Get()
 
 	// Create context
@@ -239,7 +260,7 @@ func NewService(version string, logBuffer *logbuf.RingBuffer) (*Service, error)
 
 	// Create router and SSE broadcaster (lightweight, no dependencies)
 	router := chi.NewRouter()
-	sseBroadcaster := sse.NewBroadcaster()
+	sseBroadcaster := sse.NewBroadcasterWithLimits(cfg.SSEMaxClients, cfg.SSEReplayBuffer)
 
 	// Determine install directory (plugin location)
 	homeDir, _ := os.UserHomeDir()
@@ -270,12 +291,17 @@ func NewService(version string, logBuffer *logbuf.RingBuffer) (*Service, error)
 		expensiveOpLimiter: NewExpensiveOperationLimiter(),
 		logBuffer:          logBuffer,
 		backfillTracker:    newBackfillTracker(),
+		jobManager:         jobs.NewManager(0),
 		cachedObsCounts:    make(map[string]cachedCount),
 		statsCacheTTL:      time.Minute, // Cache stats for 1 minute
 		mcpHealth:          mcp.NewMCPHealth(),
 		eventBus:           &projectevents.Bus{},
+		restartCh:          make(chan struct{}, 1),
+		initDone:           make(chan struct{}),
 	}
 
+	svc.notifier = notify.New(func() []config.OutboundWebhook { return svc.currentConfig().OutboundWebhooks })
+
 	// Setup middleware and routes (health endpoint works immediately)
 	svc.setupMiddleware()
 	svc.setupRoutes()
@@ -319,8 +345,9 @@ func (s *Service) initializeAsync() {
 
 	// Initialize database (this includes migrations - can be slow)
 	store, err := gorm.NewStore(gorm.Config{
-		DSN:      s.config.DatabaseDSN,
-		MaxConns: s.config.DatabaseMaxConns,
+		DSN:         s.currentConfig().DatabaseDSN,
+		ReplicaDSNs: s.currentConfig().DatabaseReplicaDSNs,
+		MaxConns:    s.currentConfig().DatabaseMaxConns,
 	})
 	if err != nil {
 		s.setInitError(fmt.Errorf("init database: %w", err))
@@ -333,6 +360,7 @@ func (s *Service) initializeAsync() {
 
 	// Create session manager
 	sessionManager := session.NewManager(sessionStore)
+	sessionManager.SetMaxSessions(config.Get().MaxActiveSessions)
 
 	// Create SDK processor
 	processor := sdk.NewProcessor()
@@ -343,6 +371,9 @@ func (s *Service) initializeAsync() {
 	// Create token store and wire into auth middleware
 	tokenStore := gorm.NewTokenStore(store)
 
+	// Create tenant store for the per-tenant stats endpoint (migration 106).
+	tenantStore := gorm.NewTenantStore(store)
+
 	// Create auth stores for email/password dashboard authentication (T007-T009).
 	userStore := gorm.NewUserStore(store.DB)
 	invitationStore := gorm.NewInvitationStore(store.DB)
@@ -351,6 +382,12 @@ func (s *Service) initializeAsync() {
 	// Create injection store for closed-loop learning
 	injectionStore := gorm.NewInjectionStore(store.GetDB())
 
+	// Create experiment store for injection A/B experiments
+	experimentStore := gorm.NewExperimentStore(store.GetDB())
+
+	// Create storage stats store for /api/stats/storage and get_storage_stats
+	storageStatsStore := gorm.NewStorageStatsStore(store.GetDB())
+
 	// Create agent stats store for Phase 4 agent-specific effectiveness tracking
 	agentStatsStore := gorm.NewAgentStatsStore(store.GetDB())
 
@@ -366,21 +403,29 @@ func (s *Service) initializeAsync() {
 	// Create memory + behavioral rules + credential stores for US3 observations split.
 	// All three stores are wired here (Commit E — T021).
 	memoryStore := gorm.NewMemoryStore(store)
+	memoryStore.SetOnChange(s.publishMemoryChange)
 	behavioralRulesStore := gorm.NewBehavioralRulesStore(store)
 	credentialStore := gorm.NewCredentialStore(store)
 
+	// Create the audit log for destructive operations (migration 109).
+	auditLogStore := gorm.NewAuditLogStore(store)
+
 	// Set all the initialized components
 	s.initMu.Lock()
 	s.store = store
 	s.sessionStore = sessionStore
 	s.injectionStore = injectionStore
+	s.experimentStore = experimentStore
 	s.issueStore = issueStore
 	s.credentialStore = credentialStore
 	s.memoryStore = memoryStore
 	s.behavioralRulesStore = behavioralRulesStore
+	s.auditLogStore = auditLogStore
+	s.storageStatsStore = storageStatsStore
 	s.agentStatsStore = agentStatsStore
 	s.versionStore = versionStore
 	s.tokenStore = tokenStore
+	s.tenantStore = tenantStore
 	s.relationStore = relationStore
 	s.sessionManager = sessionManager
 	s.processor = processor
@@ -411,7 +456,8 @@ func (s *Service) initializeAsync() {
 	// Setup callbacks on stores and processors
 	s.setupCallbacks(sessionManager)
 
-	// Periodic prompt cache eviction (Learning Memory v3)
+	// Periodic cache eviction: stale and over-cap prompts (Learning Memory v3)
+	// and expired observation counts.
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
@@ -420,7 +466,8 @@ func (s *Service) initializeAsync() {
 		for {
 			select {
 			case <-ticker.C:
-				s.evictStalePrompts()
+				s.trimPromptCache(promptCacheMaxAge, config.Get().PromptCacheMax)
+				s.evictExpiredObsCounts(false)
 			case <-s.ctx.Done():
 				return
 			}
@@ -443,6 +490,9 @@ func (s *Service) initializeAsync() {
 	// Initialize retrieval stats log store with batched flush
 	retrievalStatsLogStore := gorm.NewRetrievalStatsLogStore(store.GetDB())
 
+	// Initialize per-memory access counters (injection / search / get)
+	memoryAccessStore := gorm.NewMemoryAccessStore(store.GetDB())
+
 	// Initialize MCP server and SSE handler (serves /sse and /message on the worker port)
 	// Create document store for collection MCP tools
 	documentStore := gorm.NewDocumentStore(store)
@@ -463,12 +513,17 @@ func (s *Service) initializeAsync() {
 		ChunkManager:       chunkManager,
 	})
 	mcpServer.SetInjectionStore(injectionStore)
+	mcpServer.SetExperimentStore(experimentStore)
+	mcpServer.SetStorageStatsStore(storageStatsStore)
 
 	// Wire backfill status into MCP server.
 	mcpServer.SetBackfillStatusFunc(func() (any, error) {
 		return s.backfillTracker.snapshot(), nil
 	})
 
+	// Wire background jobs into MCP server (admin maintenance, get_job_status, cancel_job).
+	mcpServer.SetJobManager(s.jobManager, s.runMaintenance)
+
 	// Wire versioned document store into MCP server for collaborative document tools.
 	mcpServer.SetVersionedDocumentStore(versionedDocumentStore)
 
@@ -483,6 +538,22 @@ func (s *Service) initializeAsync() {
 	mcpServer.SetMemoryStore(memoryStore)
 	mcpServer.SetBehavioralRulesStore(behavioralRulesStore)
 
+	// Wire the search query log: analyze_prompt_history reads the logged prompts.
+	mcpServer.SetSearchQueryLogStore(searchQueryLogStore)
+	mcpServer.SetMemoryAccessStore(memoryAccessStore)
+
+	// Wire the audit log: destructive MCP tools record to it, get_audit_log reads it.
+	mcpServer.SetAuditLogStore(auditLogStore)
+
+	// Wire memory attachments: code snippets and diffs kept apart from content.
+	mcpServer.SetAttachmentStore(gorm.NewAttachmentStore(store))
+
+	// Read-only mode exposes memory to less-trusted agents without write tools.
+	if config.Get().MCPReadOnly {
+		mcpServer.SetReadOnly(true)
+		log.Info().Msg("MCP server running in read-only mode (ENGRAM_MCP_READ_ONLY)")
+	}
+
 	// Wire gRPC server: create adapter over mcpServer and register with the server.
 	// initMu protects s.grpcServer — the cmux goroutine polls for it.
 	//
@@ -515,19 +586,59 @@ func (s *Service) initializeAsync() {
 	s.sessionIdxStore = sessionIdxStore
 	s.searchQueryLogStore = searchQueryLogStore
 	s.retrievalStatsLogStore = retrievalStatsLogStore
+	s.memoryAccessStore = memoryAccessStore
 	s.initMu.Unlock()
 
 	// Mark as ready
 	s.ready.Store(true)
+	s.finishInit()
 	log.Info().Msg("Async initialization complete - service ready")
 
 	// Start project reaper (hourly cleanup of hard-expired soft-deleted projects).
 	projectReaper := reaper.New(store.DB)
+	s.initMu.Lock()
 	s.projectReaper = projectReaper
+	s.initMu.Unlock()
 	projectReaper.Start(s.ctx)
 
+	// Start relation inference (hourly co-occurrence linking of memories).
+	relationInferrer := relinfer.New(memoryStore, relationStore)
+	s.initMu.Lock()
+	s.relationInferrer = relationInferrer
+	s.initMu.Unlock()
+	relationInferrer.Start(s.ctx)
+
+	// Start event pruning (hourly rollup/deletion of event log rows older than
+	// ENGRAM_EVENT_RETENTION_DAYS).
+	eventPruner := eventprune.New(retrievalStatsLogStore, searchQueryLogStore)
+	s.initMu.Lock()
+	s.eventPruner = eventPruner
+	s.initMu.Unlock()
+	eventPruner.Start(s.ctx)
+
+	// Start scheduled backups (ENGRAM_BACKUP_TARGET; off when unset).
+	backuper, backupErr := backup.New(store.DB)
+	if backupErr != nil {
+		log.Error().Err(backupErr).Msg("Invalid backup configuration; backups disabled")
+	}
+	s.initMu.Lock()
+	s.backuper = backuper
+	s.initMu.Unlock()
+	backuper.Start(s.ctx)
+
 	// Start queue processor if SDK processor is available
 	if processor != nil {
+		processingPool := pool.New(pool.Options{
+			Workers:       s.currentConfig().ProcessingWorkers,
+			QueueSize:     s.currentConfig().ProcessingQueueSize,
+			SlowThreshold: time.Duration(s.currentConfig().ProcessingSlowMs) * time.Millisecond,
+			OnIdle:        s.broadcastProcessingStatus,
+		})
+		s.initMu.Lock()
+		s.processingPool = processingPool
+		s.initMu.Unlock()
+		s.sessionManager.SetInFlightCheck(processingPool.KeyBusy)
+
 		s.wg.Add(1)
 		go s.processQueue()
 	}
@@ -540,15 +651,17 @@ func (s *Service) initializeAsync() {
 func (s *Service) startWatchers() {
 	// Database file watcher is not applicable for PostgreSQL (no local file to watch).
 
-	// Watch config file for changes (triggers process exit for restart)
+	// Watch the config file: edits and deletion both reload it in place.
 	configPath := config.SettingsPath()
-	configWatcher, err := watcher.New(configPath, func() {
-		log.Warn().Str("path", configPath).Msg("Config file changed, reloading...")
+	reload := func() {
+		log.Info().Str("path", configPath).Msg("Config file changed, reloading...")
 		s.reloadConfig()
-	})
+	}
+	configWatcher, err := watcher.New(configPath, reload)
 	if err != nil {
 		log.Warn().Err(err).Msg("Failed to create config watcher")
 	} else {
+		configWatcher.OnChange(reload)
 		s.configWatcher = configWatcher
 		if err := configWatcher.Start(); err != nil {
 			log.Warn().Err(err).Msg("Failed to start config watcher")
@@ -559,11 +672,17 @@ func (s *Service) startWatchers() {
 }
 
 // reloadConfig hot-reloads configuration from disk without process restart.
-// Uses config.Reload() to atomically swap the global config. Services that
-// call config.Get() per-request will pick up new values automatically.
-// Structural changes (port, token) log a warning — manual restart needed.
+// Uses config.Reload() to atomically swap the global config and the service's
+// copy; handlers read limits and thresholds per request, so they pick up new
+// values on their next call.
+// Settings bound at startup (DSN, port, host, operator token) request a
+// graceful restart instead; see RestartRequested.
 func (s *Service) reloadConfig() {
-	_, changed, err := config.Reload()
+	if err := checkSettings(); err != nil {
+		log.Error().Err(err).Msg("Config reload refused (ENGRAM_CONFIG_STRICT) — keeping current config")
+		return
+	}
+	cfg, changed, err := config.Reload()
 	if err != nil {
 		log.Error().Err(err).Msg("Config reload failed — keeping current config")
 		return
@@ -574,14 +693,43 @@ func (s *Service) reloadConfig() {
 		return
 	}
 
-	log.Info().Strs("changed", changed).Msg("Config hot-reloaded")
+	s.configMu.Lock()
+	s.config = cfg
+	s.configMu.Unlock()
+
+	restart := config.RequiresRestart(changed)
+	log.Info().Strs("changed", changed).Bool("restart", restart).Msg("Config hot-reloaded")
 
 	// Broadcast to dashboard
 	s.sseBroadcaster.Broadcast(map[string]any{
 		"type":    "config_reloaded",
 		"message": "Configuration reloaded",
 		"changed": changed,
+		"restart": restart,
 	})
+
+	if restart {
+		log.Warn().Strs("changed", changed).Msg("Changed settings require a restart — shutting down gracefully")
+		select {
+		case s.restartCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// currentConfig returns the live configuration.
+func (s *Service) currentConfig() *config.Config {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.config
+}
+
+// RestartRequested is signalled when a settings reload changed a value that
+// only takes effect on restart (database DSN, listen address, TLS, operator
+// token, request limits). The caller should shut down gracefully and exit non-zero so the
+// supervisor starts a fresh process.
+func (s *Service) RestartRequested() <-chan struct{} {
+	return s.restartCh
 }
 
 // setInitError records an initialization error.
@@ -589,9 +737,19 @@ func (s *Service) setInitError(err error) {
 	s.initMu.Lock()
 	s.initError = err
 	s.initMu.Unlock()
+	s.finishInit()
 	log.Error().Err(err).Msg("Async initialization failed")
 }
 
+// finishInit releases requests waiting in requireReady.
+func (s *Service) finishInit() {
+	s.initDoneOnce.Do(func() {
+		if s.initDone != nil {
+			close(s.initDone)
+		}
+	})
+}
+
 // GetInitError returns any initialization error.
 func (s *Service) GetInitError() error {
 	s.initMu.RLock()
@@ -708,6 +866,7 @@ func (a *mcpHandlerAdapter) ServerInfo() (string, string) {
 func (s *Service) setupMiddleware() {
 	// Add request ID first so all subsequent logs can include it
 	s.router.Use(RequestID)
+	s.router.Use(tracing.Middleware)
 
 	s.router.Use(debugRequestLogger)
 	s.router.Use(middleware.Recoverer)
@@ -716,8 +875,11 @@ func (s *Service) setupMiddleware() {
 	// Add security headers (X-Frame-Options, X-Content-Type-Options, CSP, etc.)
 	s.router.Use(SecurityHeaders)
 
-	// Add request body size limit (10MB) to prevent DoS via large payloads
-	s.router.Use(MaxBodySize(10 * 1024 * 1024))
+	// Answer hooks that report their version with the minimum this worker needs
+	s.router.Use(HookVersion)
+
+	// Add request body size limit (default 10 MiB) to prevent DoS via large payloads
+	s.router.Use(MaxBodySize(int64(s.currentConfig().MaxRequestBodyBytes)))
 
 	// Require JSON Content-Type for POST/PUT/PATCH requests
 	s.router.Use(RequireJSONContentType)
@@ -763,6 +925,19 @@ func (s *Service) setupRoutes() {
 		r.Get("/invitations", s.handleAdminListInvitations)
 		r.Get("/users", s.handleAdminListUsers)
 		r.Put("/users/{id}", s.handleAdminUpdateUser)
+
+		// Graceful draining before a restart
+		r.Post("/drain", s.handleDrain)
+		r.Get("/drain", s.handleDrainStatus)
+		r.Delete("/drain", s.handleCancelDrain)
+
+		// Trim in-memory caches and return freed memory to the OS
+		r.Post("/gc", s.handleAdminGC)
+
+		// Remote backups (ENGRAM_BACKUP_TARGET)
+		r.Get("/backups", s.handleListBackups)
+		r.Post("/backups", s.handleRunBackup)
+		r.Post("/backups/restore", s.handleRestoreBackup)
 	})
 
 	// Health check (both root and API-prefixed for compatibility)
@@ -783,6 +958,7 @@ func (s *Service) setupRoutes() {
 	// OpenAPI docs (read-only spec; protected by global auth middleware if ENGRAM_AUTH_ADMIN_TOKEN is set)
 	s.router.Get("/api/docs", http.RedirectHandler("/api/docs/index.html", http.StatusMovedPermanently).ServeHTTP)
 	s.router.Get("/api/docs/*", httpSwagger.WrapHandler)
+	s.router.Get("/api/openapi.json", s.handleOpenAPI)
 
 	// Admin/management routes — authentication applied globally via setupMiddleware.
 	// Grouped for logical organization; no additional middleware needed.
@@ -801,6 +977,9 @@ func (s *Service) setupRoutes() {
 		// General restart endpoint (works before DB is ready)
 		r.Post("/api/restart", s.handleRestart)
 
+		// Resolved settings with their sources and settings file problems
+		r.Get("/api/config/effective", s.handleEffectiveConfig)
+
 		// Selfcheck endpoint (works before DB is ready - checks all components)
 		r.Get("/api/selfcheck", s.handleSelfCheck)
 
@@ -817,6 +996,20 @@ func (s *Service) setupRoutes() {
 		r.Post("/api/backfill/session", s.handleBackfillSession)
 		r.Get("/api/backfill/status", s.handleBackfillStatus)
 		r.Post("/api/import/feedback", s.handleImportFeedback)
+
+		// Background job endpoints (work before DB is ready)
+		r.Get("/api/jobs", s.handleListJobs)
+		r.Get("/api/jobs/{id}", s.handleGetJob)
+		r.Post("/api/jobs/{id}/cancel", s.handleCancelJob)
+
+		// Processing queue inspection and manual intervention (in-memory, works before DB is ready)
+		r.Get("/api/queue", s.handleListQueue)
+		r.Post("/api/queue/drain", s.handleDrainStuckQueue)
+		r.Post("/api/queue/{id}/retry", s.handleRetryQueued)
+		r.Delete("/api/queue/{id}", s.handleDeleteQueued)
+
+		// Capture policy dry run (reads config only, works before DB is ready)
+		r.Post("/api/capture/policy/test", s.handleTestCapturePolicy)
 	})
 
 	// OpenAI-compatible model list endpoint. Intentionally outside requireReady group:
@@ -829,13 +1022,21 @@ func (s *Service) setupRoutes() {
 		r.Use(s.requireReady)
 		r.Use(middleware.Timeout(DefaultHTTPTimeout))
 
+		// Hook endpoints validate their bodies strictly against the request
+		// types, under tighter size limits than the rest of the API.
+		cfg := s.currentConfig()
+		hookBody := func(schema requestSchema) func(http.Handler) http.Handler {
+			return ValidateJSONBody(schema, cfg.MaxHookBodyBytes, cfg.MaxFieldBytes)
+		}
+
 		// Session routes
-		r.Post("/api/sessions/init", s.handleSessionInit)
+		r.With(hookBody(schemaOf(SessionInitRequest{}, "claudeSessionId"))).Post("/api/sessions/init", s.handleSessionInit)
 		r.Get("/api/sessions/list", s.handleListSessions)
 		r.Get("/api/sessions", s.handleGetSessionByClaudeID)
-		r.Post("/api/sessions/{id}/init", s.handleSessionStart)
-		r.Post("/api/sessions/subagent-complete", s.handleSubagentComplete)
-		r.Post("/api/sessions/{id}/summarize", s.handleSummarize)
+		r.Patch("/api/sessions/{id}", s.handleLabelSession)
+		r.With(hookBody(schemaOf(SessionStartRequest{}))).Post("/api/sessions/{id}/init", s.handleSessionStart)
+		r.With(hookBody(schemaOf(SubagentCompleteRequest{}, "claudeSessionId"))).Post("/api/sessions/subagent-complete", s.handleSubagentComplete)
+		r.With(hookBody(schemaOf(SummarizeRequest{}))).Post("/api/sessions/{id}/summarize", s.handleSummarize)
 
 		// Session transcript indexing (client pushes JSONL for FTS)
 		r.Post("/api/sessions/index", s.handleIndexSession)
@@ -850,6 +1051,7 @@ func (s *Service) setupRoutes() {
 		r.Delete("/api/projects/{id}", s.handleDeleteProject)
 		r.Get("/api/stats", s.handleGetStats)
 		r.Get("/api/stats/retrieval", s.handleGetRetrievalStats)
+		r.Get("/api/stats/storage", s.handleGetStorageStats)
 		r.Get("/api/types", s.handleGetTypes)
 		r.Get("/api/models", s.handleGetModels)
 
@@ -857,15 +1059,20 @@ func (s *Service) setupRoutes() {
 		r.Get("/api/context/count", s.handleContextCount)
 		r.Post("/api/context/inject", s.handleContextInject)
 		r.Get("/api/context/inject", s.handleContextInject) // deprecated — use POST
+		r.Get("/api/context/inject/preview", s.handleContextInjectPreview)
 		r.Post("/api/context/session-start", s.handleSessionStartContextStatic)
 		r.Get("/api/context/session-start", s.handleSessionStartContextStatic)
 		r.Get("/api/context/search", s.handleSearchByPrompt)
 		r.Post("/api/context/search", s.handleSearchByPrompt)
+		r.Get("/api/experiments/report", s.handleExperimentReport)
 		r.Get("/api/context/files", s.handleFileContext)
 		r.Get("/api/context/by-file", s.handleContextByFile)
-		r.Post("/api/memory/triggers", s.handleMemoryTriggers)
+		r.With(hookBody(schemaOf(MemoryTriggerRequest{}, "tool", "project"))).Post("/api/memory/triggers", s.handleMemoryTriggers)
 		r.Post("/api/decisions/search", s.handleSearchDecisions)
 
+		// Inbound webhooks (authenticated by per-source signatures)
+		r.Post("/api/webhooks/{source}", s.handleWebhook)
+
 		// Issue tracking routes (agent-issues feature)
 		r.Get("/api/issues", s.handleListIssues)
 		r.Post("/api/issues", s.handleCreateIssue)
@@ -885,6 +1092,7 @@ func (s *Service) setupRoutes() {
 
 		// Search analytics
 		r.Get("/api/search/recent", s.handleGetRecentQueries)
+		r.Get("/api/search/prompts", s.handleSearchPrompts)
 		r.Get("/api/search/analytics", s.handleGetSearchAnalytics)
 		r.Post("/api/analytics/search-misses", s.handleSearchMissAnalytics)
 
@@ -908,13 +1116,28 @@ func (s *Service) setupRoutes() {
 		// Memory routes (US3 Commit E — explicit user memories stored in memories table)
 		r.Post("/api/memories", s.handleStoreMemoryExplicit)
 		r.Get("/api/memories", s.handleListMemories)
+		r.Get("/api/memories/relations", s.handleListMemoryRelations)
+		r.Get("/api/memories/{id}", s.handleGetMemoryByID)
+		r.Put("/api/memories/{id}", s.handleUpdateMemoryByID)
 		r.Delete("/api/memories/{id}", s.handleDeleteMemoryByID)
 
+		// Commit capture (post-commit hook installed by `engram capture --install`)
+		r.Post("/api/capture/commit", s.handleCaptureCommit)
+
 		// Token stats
 		r.Get("/api/auth/tokens/{id}/stats", s.handleGetTokenStats)
 
+		// Per-tenant usage (admin only)
+		r.Get("/api/tenants", s.handleListTenants)
+
 		// Config
 		r.Get("/api/config", s.handleGetConfig)
+
+		// Maintenance runs as a background job (see /api/jobs)
+		r.Post("/api/maintenance", s.handleTriggerMaintenance)
+
+		// Audit trail of destructive operations (admin only)
+		r.Get("/api/audit", s.handleGetAuditLog)
 	})
 }
 
@@ -1028,13 +1251,13 @@ func (s *Service) GetRetrievalStats(project string) RetrievalStats {
 // trackSearchQuery records a search query for analytics.
 // Writes to the persistent DB store (fire-and-forget) and the in-memory ring buffer.
 // O(1) insertion for the ring buffer - no memory allocation or copying on each insert.
-func (s *Service) trackSearchQuery(query, project, queryType string, results int, latencyMs float32) {
+func (s *Service) trackSearchQuery(ctx context.Context, query, project, queryType string, results int, latencyMs float32) {
 	// Persist to DB asynchronously (fire-and-forget, never blocks caller).
 	s.initMu.RLock()
 	sqlStore := s.searchQueryLogStore
 	s.initMu.RUnlock()
 	if sqlStore != nil {
-		sqlStore.LogQuery(project, query, queryType, results, latencyMs)
+		sqlStore.LogQuery(ctx, project, query, queryType, results, latencyMs)
 	}
 
 	// Also maintain the in-memory ring buffer for low-latency in-process access.
@@ -1062,9 +1285,13 @@ func (s *Service) trackSearchQuery(query, project, queryType string, results int
 // getCachedObservationCount returns observation count for a project, using cache if available.
 // Falls back to database query if cache is expired or missing.
 func (s *Service) getCachedObservationCount(ctx context.Context, project string) (int, error) {
+	// Counts are tenant-scoped (the stores filter by the context tenant), so the
+	// cache key must be too — otherwise one tenant would see another's count.
+	cacheKey := tenant.FromContext(ctx) + "/" + project
+
 	// Check cache first
 	s.cachedObsCountsMu.RLock()
-	if cached, ok := s.cachedObsCounts[project]; ok {
+	if cached, ok := s.cachedObsCounts[cacheKey]; ok {
 		if time.Since(cached.timestamp) < s.statsCacheTTL {
 			s.cachedObsCountsMu.RUnlock()
 			return cached.count, nil
@@ -1092,7 +1319,7 @@ func (s *Service) getCachedObservationCount(ctx context.Context, project string)
 
 	// Update cache
 	s.cachedObsCountsMu.Lock()
-	s.cachedObsCounts[project] = cachedCount{
+	s.cachedObsCounts[cacheKey] = cachedCount{
 		count:     count,
 		timestamp: time.Now(),
 	}
@@ -1136,6 +1363,17 @@ func (s *Service) Start() error {
 	host := config.GetWorkerHost()
 	addr := fmt.Sprintf("%s:%d", host, port)
 
+	// Binding beyond loopback exposes the worker on the network: token auth is mandatory there.
+	if err := checkBindAuth(host, token, authDisabled); err != nil {
+		return err
+	}
+
+	// Optional TLS: configured cert + key, or a self-signed certificate in the data dir.
+	tlsConfig, err := listenerTLSConfig(config.Get(), host, filepath.Join(config.DataDir(), "tls"))
+	if err != nil {
+		return fmt.Errorf("failed to configure TLS: %w", err)
+	}
+
 	s.server = &http.Server{
 		Addr:              addr,
 		Handler:           s.router,
@@ -1156,19 +1394,10 @@ func (s *Service) Start() error {
 			return err
 		}
 
-		// Optional TLS: wrap listener if cert + key are provided.
-		tlsCert := os.Getenv("ENGRAM_TLS_CERT")
-		tlsKey := os.Getenv("ENGRAM_TLS_KEY")
-		if tlsCert != "" && tlsKey != "" {
-			cert, tlsErr := tls.LoadX509KeyPair(tlsCert, tlsKey)
-			if tlsErr != nil {
-				_ = ln.Close()
-				return fmt.Errorf("failed to load TLS keypair: %w", tlsErr)
-			}
-			ln = tls.NewListener(ln, &tls.Config{Certificates: []tls.Certificate{cert}})
-			log.Info().Str("cert", tlsCert).Msg("TLS enabled")
+		if tlsConfig != nil {
+			ln = tls.NewListener(ln, tlsConfig)
 		} else {
-			log.Warn().Msg("TLS not configured (ENGRAM_TLS_CERT / ENGRAM_TLS_KEY unset) — serving plaintext")
+			log.Warn().Msg("TLS not configured (ENGRAM_TLS_CERT / ENGRAM_TLS_KEY / ENGRAM_TLS_SELF_SIGNED unset) — serving plaintext")
 		}
 
 		m := cmux.New(ln)
@@ -1271,80 +1500,143 @@ func (s *Service) processQueue() {
 	}
 }
 
-// processAllSessions processes pending messages for all active sessions.
-// Messages are processed in parallel using goroutines, with concurrency
-// limited by the processor's semaphore.
+// processAllSessions hands pending messages of all active sessions to the
+// processing pool. The pool bounds concurrency and keeps each session's
+// messages in order; Submit blocks when the pool is full, which holds further
+// messages in the session queues until workers catch up. Sessions waiting on
+// a summary are submitted first, at high priority (see messagePriorities), so
+// a summary is not held up behind other sessions' tool observations.
 func (s *Service) processAllSessions() {
-	// Get all sessions with pending messages
-	sessions := s.sessionManager.GetAllSessions()
-
-	var wg sync.WaitGroup
+	s.initMu.RLock()
+	processingPool := s.processingPool
+	s.initMu.RUnlock()
+	if processingPool == nil {
+		return
+	}
 
-	for _, sess := range sessions {
-		// Get pending messages
+	type batch struct {
+		sess       *session.ActiveSession
+		messages   []session.PendingMessage
+		priorities []pool.Priority
+	}
+	var batches []batch
+	for _, sess := range s.sessionManager.GetAllSessions() {
 		messages := s.sessionManager.DrainMessages(sess.SessionDBID)
-		if len(messages) == 0 {
-			continue
+		if len(messages) > 0 {
+			batches = append(batches, batch{sess: sess, messages: messages, priorities: messagePriorities(messages)})
 		}
+	}
+	sort.SliceStable(batches, func(i, j int) bool {
+		return batches[i].priorities[0] > batches[j].priorities[0]
+	})
 
-		// Process each message in a goroutine
-		for _, msg := range messages {
-			wg.Add(1)
-			go func(sess *session.ActiveSession, msg session.PendingMessage) {
-				defer wg.Done()
-
-				switch msg.Type {
-				case session.MessageTypeObservation:
-					if msg.Observation != nil {
-						err := s.processor.ProcessObservation(
-							s.ctx,
-							sess.SDKSessionID,
-							sess.Project,
-							msg.Observation.ToolName,
-							msg.Observation.ToolInput,
-							msg.Observation.ToolResponse,
-							msg.Observation.PromptNumber,
-							msg.Observation.CWD,
-							msg.Observation.UserPrompt,
-						)
-						if err != nil {
-							log.Error().Err(err).
-								Str("tool", msg.Observation.ToolName).
-								Msg("Failed to process observation")
-						}
-					}
-
-				case session.MessageTypeSummarize:
-					if msg.Summarize != nil {
-						err := s.processor.ProcessSummary(
-							s.ctx,
-							sess.SessionDBID,
-							sess.SDKSessionID,
-							sess.Project,
-							sess.UserPrompt,
-							msg.Summarize.LastUserMessage,
-							msg.Summarize.LastAssistantMessage,
-						)
-						if err != nil {
-							log.Error().Err(err).
-								Int64("sessionId", sess.SessionDBID).
-								Msg("Failed to process summary")
-						}
-						// Delete session after summary
-						s.sessionManager.DeleteSession(sess.SessionDBID)
+	for i, b := range batches {
+		for j, msg := range b.messages {
+			if err := processingPool.SubmitPriority(s.ctx, b.sess.SessionDBID, b.priorities[j], s.processMessageTask(b.sess, msg)); err != nil {
+				log.Warn().Err(err).
+					Int64("sessionId", b.sess.SessionDBID).
+					Msg("Processing pool rejected message")
+				// Put the unsubmitted messages back for the next pass.
+				for k := len(b.messages) - 1; k >= j; k-- {
+					s.requeueRejected(processingPool, b.sess.SessionDBID, b.messages[k])
+				}
+				for _, rest := range batches[i+1:] {
+					for k := len(rest.messages) - 1; k >= 0; k-- {
+						s.requeueRejected(processingPool, rest.sess.SessionDBID, rest.messages[k])
 					}
 				}
-			}(sess, msg)
+				return
+			}
 		}
 	}
 
-	// Wait for all goroutines to complete
-	wg.Wait()
-
-	// Broadcast status after processing
 	s.broadcastProcessingStatus()
 }
 
+// requeueRejected puts a message the processing pool rejected back at the
+// front of its session queue. If the session is gone the message cannot be
+// processed any more: it is logged and counted as failed instead.
+func (s *Service) requeueRejected(processingPool *pool.Pool, sessionDBID int64, msg session.PendingMessage) {
+	if s.sessionManager.RequeueMessage(sessionDBID, msg) {
+		return
+	}
+	processingPool.CountFailed(1)
+	log.Error().
+		Int64("sessionId", sessionDBID).
+		Int64("messageId", msg.ID).
+		Str("type", msg.Type.String()).
+		Msg("Dropped queued message: session closed before it could be processed")
+}
+
+// messagePriority is the processing pool priority of a queued message:
+// summaries are high priority, tool observations low.
+func messagePriority(msg session.PendingMessage) pool.Priority {
+	if msg.Type == session.MessageTypeSummarize {
+		return pool.PriorityHigh
+	}
+	return pool.PriorityLow
+}
+
+// messagePriorities returns the priority of each message of one session's
+// queue, in order. Messages queued before a summary must be processed before
+// it, so they are submitted at the summary's priority.
+func messagePriorities(messages []session.PendingMessage) []pool.Priority {
+	priorities := make([]pool.Priority, len(messages))
+	inherited := pool.PriorityLow
+	for i := len(messages) - 1; i >= 0; i-- {
+		inherited = max(inherited, messagePriority(messages[i]))
+		priorities[i] = inherited
+	}
+	return priorities
+}
+
+// processMessageTask builds the pool task that processes one queued message.
+func (s *Service) processMessageTask(sess *session.ActiveSession, msg session.PendingMessage) pool.Task {
+	return func(ctx context.Context) error {
+		switch msg.Type {
+		case session.MessageTypeObservation:
+			if msg.Observation == nil {
+				return nil
+			}
+			err := s.processor.ProcessObservation(
+				ctx,
+				sess.SDKSessionID,
+				sess.Project,
+				msg.Observation.ToolName,
+				msg.Observation.ToolInput,
+				msg.Observation.ToolResponse,
+				msg.Observation.PromptNumber,
+				msg.Observation.CWD,
+				msg.Observation.UserPrompt,
+			)
+			if err != nil {
+				return fmt.Errorf("process observation (tool %s): %w", msg.Observation.ToolName, err)
+			}
+
+		case session.MessageTypeSummarize:
+			if msg.Summarize == nil {
+				return nil
+			}
+			err := s.processor.ProcessSummary(
+				ctx,
+				sess.SessionDBID,
+				sess.SDKSessionID,
+				sess.Project,
+				sess.UserPrompt,
+				msg.Summarize.LastUserMessage,
+				msg.Summarize.LastAssistantMessage,
+			)
+			// Delete session after summary. Per-session ordering in the pool
+			// guarantees earlier observations have already been processed.
+			s.sessionManager.DeleteSession(sess.SessionDBID)
+			if err != nil {
+				return fmt.Errorf("process summary (session %d): %w", sess.SessionDBID, err)
+			}
+		}
+		return nil
+	}
+}
+
 // Shutdown gracefully shuts down the service.
 func (s *Service) Shutdown(ctx context.Context) error {
 	log.Info().Msg("Starting graceful shutdown...")
@@ -1384,6 +1676,14 @@ func (s *Service) Shutdown(ctx context.Context) error {
 
 	// Phase 3: Stop background workers (drain queues)
 	log.Debug().Msg("Phase 3: Stopping background workers...")
+	s.initMu.RLock()
+	processingPool := s.processingPool
+	s.initMu.RUnlock()
+	if processingPool != nil {
+		collectError("processing pool", processingPool.Close(ctx))
+	}
+	collectError("job manager", s.jobManager.Close(ctx))
+	collectError("outbound webhooks", s.notifier.Close(ctx))
 
 	// Phase 4: Shutdown sessions (flush pending work)
 	log.Debug().Msg("Phase 4: Shutting down sessions...")
@@ -1406,6 +1706,14 @@ func (s *Service) Shutdown(ctx context.Context) error {
 		log.Warn().Msg("Timeout waiting for goroutines - forcing shutdown")
 	}
 
+	// Flush buffered memory access counts while the database is still open.
+	s.initMu.RLock()
+	memoryAccessStore := s.memoryAccessStore
+	s.initMu.RUnlock()
+	if memoryAccessStore != nil {
+		memoryAccessStore.Close()
+	}
+
 	// Phase 6: Close AI/ML services (close models)
 	log.Debug().Msg("Phase 6: Closing AI/ML services...")
 	// Phase 7: Close database last (other components may need it)
@@ -1431,8 +1739,7 @@ func (s *Service) Shutdown(ctx context.Context) error {
 
 // broadcastProcessingStatus broadcasts the current processing status.
 func (s *Service) broadcastProcessingStatus() {
-	isProcessing := s.sessionManager.IsAnySessionProcessing()
-	queueDepth := s.sessionManager.GetTotalQueueDepth()
+	isProcessing, queueDepth := s.processingState()
 
 	s.sseBroadcaster.Broadcast(map[string]any{
 		"type":         "processing_status",
@@ -1441,6 +1748,23 @@ func (s *Service) broadcastProcessingStatus() {
 	})
 }
 
+// processingState reports whether any message is queued or being processed and
+// the number of messages waiting, across session queues and the processing pool.
+func (s *Service) processingState() (bool, int) {
+	isProcessing := s.sessionManager.IsAnySessionProcessing()
+	queueDepth := s.sessionManager.GetTotalQueueDepth()
+
+	s.initMu.RLock()
+	processingPool := s.processingPool
+	s.initMu.RUnlock()
+	if processingPool != nil {
+		st := processingPool.Stats()
+		queueDepth += st.Pending
+		isProcessing = isProcessing || st.Pending > 0 || st.Active > 0
+	}
+	return isProcessing, queueDepth
+}
+
 func getPID() int {
 	return os.Getpid()
 }
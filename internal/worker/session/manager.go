@@ -3,6 +3,7 @@ package session
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -37,11 +38,32 @@ type SummarizeData struct {
 
 // PendingMessage represents a message queued for SDK processing.
 type PendingMessage struct {
+	QueuedAt    time.Time
 	Observation *ObservationData
 	Summarize   *SummarizeData
+	ID          int64 // unique per Manager; assigned when queued
 	Type        MessageType
 }
 
+// String returns the message type's name as used by the queue API.
+func (t MessageType) String() string {
+	if t == MessageTypeSummarize {
+		return "summarize"
+	}
+	return "observation"
+}
+
+// QueuedMessage describes one pending message for inspection.
+type QueuedMessage struct {
+	QueuedAt        time.Time `json:"queued_at"`
+	Type            string    `json:"type"`
+	ToolName        string    `json:"tool_name,omitempty"`
+	Project         string    `json:"project"`
+	ClaudeSessionID string    `json:"claude_session_id,omitempty"`
+	ID              int64     `json:"id"`
+	SessionDBID     int64     `json:"session_id"`
+}
+
 // ActiveSession represents an in-memory active session being processed.
 type ActiveSession struct {
 	StartTime              time.Time
@@ -74,9 +96,12 @@ type Manager struct {
 	sessions      map[int64]*ActiveSession
 	onCreated     func(int64)
 	onDeleted     func(int64)
+	inFlight      func(int64) bool // see SetInFlightCheck
 	cancel        context.CancelFunc
 	ProcessNotify chan struct{}
 	mu            sync.RWMutex
+	maxSessions   int // 0 = unlimited; see SetMaxSessions
+	lastMessageID atomic.Int64
 }
 
 // NewManager creates a new session manager.
@@ -111,32 +136,97 @@ func (m *Manager) cleanupLoop() {
 
 // cleanupStaleSessions removes sessions that have been inactive too long.
 func (m *Manager) cleanupStaleSessions() {
+	for _, session := range m.idleSessions() {
+		if time.Since(session.StartTime) <= SessionTimeout {
+			// idleSessions is oldest first, so the rest are younger.
+			break
+		}
+		log.Info().Int64("sessionId", session.SessionDBID).Dur("age", SessionTimeout).Msg("Cleaning up stale session")
+		m.DeleteSession(session.SessionDBID)
+	}
+}
+
+// idleSessions returns the sessions with no pending messages, no active
+// processing and no tasks in the processing pool, oldest first. Only these
+// are safe to evict.
+func (m *Manager) idleSessions() []*ActiveSession {
 	m.mu.RLock()
-	var staleIDs []int64
-	now := time.Now()
-	for id, session := range m.sessions {
-		// Check if session has been inactive for too long
+	idle := make([]*ActiveSession, 0, len(m.sessions))
+	for _, session := range m.sessions {
 		session.messageMu.Lock()
 		hasPending := len(session.pendingMessages) > 0
 		session.messageMu.Unlock()
 
-		// Don't delete sessions with pending messages or active processing
 		if hasPending || session.generatorActive.Load() {
 			continue
 		}
-
-		// Delete if session is older than timeout
-		if now.Sub(session.StartTime) > SessionTimeout {
-			staleIDs = append(staleIDs, id)
+		if m.inFlight != nil && m.inFlight(session.SessionDBID) {
+			continue // messages already taken into the processing pool
 		}
+		idle = append(idle, session)
 	}
 	m.mu.RUnlock()
 
-	// Delete stale sessions
-	for _, id := range staleIDs {
-		log.Info().Int64("sessionId", id).Dur("age", SessionTimeout).Msg("Cleaning up stale session")
-		m.DeleteSession(id)
+	sort.Slice(idle, func(i, j int) bool {
+		return idle[i].StartTime.Before(idle[j].StartTime)
+	})
+	return idle
+}
+
+// SetMaxSessions caps the number of in-memory sessions (0 = unlimited). Past
+// the cap, the oldest idle sessions are evicted; they are reloaded from the
+// database on their next request.
+func (m *Manager) SetMaxSessions(n int) {
+	m.mu.Lock()
+	m.maxSessions = n
+	m.mu.Unlock()
+	m.enforceSessionCap()
+}
+
+// enforceSessionCap evicts the oldest idle sessions until the session count
+// is within the cap. Returns how many were evicted.
+func (m *Manager) enforceSessionCap() int {
+	m.mu.RLock()
+	excess := len(m.sessions) - m.maxSessions
+	capped := m.maxSessions > 0
+	m.mu.RUnlock()
+	if !capped || excess <= 0 {
+		return 0
+	}
+
+	evicted := 0
+	for _, session := range m.idleSessions() {
+		if evicted == excess {
+			break
+		}
+		log.Debug().Int64("sessionId", session.SessionDBID).Msg("Evicting idle session over the session cap")
+		m.DeleteSession(session.SessionDBID)
+		evicted++
 	}
+	return evicted
+}
+
+// TrimIdleSessions evicts every idle session older than maxAge and returns
+// how many were evicted. Sessions with queued or in-flight work are kept.
+func (m *Manager) TrimIdleSessions(maxAge time.Duration) int {
+	evicted := 0
+	for _, session := range m.idleSessions() {
+		if time.Since(session.StartTime) < maxAge {
+			break
+		}
+		m.DeleteSession(session.SessionDBID)
+		evicted++
+	}
+	return evicted
+}
+
+// SetInFlightCheck sets the function reporting whether a session still has
+// messages in the processing pool. Such sessions are not idle: their messages
+// have left pendingMessages but are not processed yet.
+func (m *Manager) SetInFlightCheck(check func(sessionDBID int64) bool) {
+	m.mu.Lock()
+	m.inFlight = check
+	m.mu.Unlock()
 }
 
 // SetOnSessionCreated sets a callback for when a session is created.
@@ -225,6 +315,8 @@ func (m *Manager) InitializeSession(ctx context.Context, sessionDBID int64, user
 		onCreated(sessionDBID)
 	}
 
+	m.enforceSessionCap()
+
 	return session, nil
 }
 
@@ -246,6 +338,8 @@ func (m *Manager) QueueObservation(ctx context.Context, sessionDBID int64, data
 
 	session.messageMu.Lock()
 	session.pendingMessages = append(session.pendingMessages, PendingMessage{
+		ID:          m.lastMessageID.Add(1),
+		QueuedAt:    time.Now(),
 		Type:        MessageTypeObservation,
 		Observation: &data,
 	})
@@ -291,7 +385,9 @@ func (m *Manager) QueueSummarize(ctx context.Context, sessionDBID int64, lastUse
 
 	session.messageMu.Lock()
 	session.pendingMessages = append(session.pendingMessages, PendingMessage{
-		Type: MessageTypeSummarize,
+		ID:       m.lastMessageID.Add(1),
+		QueuedAt: time.Now(),
+		Type:     MessageTypeSummarize,
 		Summarize: &SummarizeData{
 			LastUserMessage:      lastUserMessage,
 			LastAssistantMessage: lastAssistantMessage,
@@ -441,3 +537,89 @@ func (m *Manager) DrainMessages(sessionDBID int64) []PendingMessage {
 
 	return messages
 }
+
+// PendingMessages lists the messages waiting in every session's queue,
+// oldest first.
+func (m *Manager) PendingMessages() []QueuedMessage {
+	var out []QueuedMessage
+	for _, session := range m.GetAllSessions() {
+		session.messageMu.Lock()
+		for _, msg := range session.pendingMessages {
+			item := QueuedMessage{
+				ID:              msg.ID,
+				SessionDBID:     session.SessionDBID,
+				ClaudeSessionID: session.ClaudeSessionID,
+				Project:         session.Project,
+				Type:            msg.Type.String(),
+				QueuedAt:        msg.QueuedAt,
+			}
+			if msg.Observation != nil {
+				item.ToolName = msg.Observation.ToolName
+			}
+			out = append(out, item)
+		}
+		session.messageMu.Unlock()
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].QueuedAt.Equal(out[j].QueuedAt) {
+			return out[i].QueuedAt.Before(out[j].QueuedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// TakeMessage removes the pending message with the given ID from its
+// session's queue and returns it with its session. ok is false when no
+// session has such a message (it was processed, taken or never queued).
+func (m *Manager) TakeMessage(id int64) (*ActiveSession, PendingMessage, bool) {
+	for _, session := range m.GetAllSessions() {
+		session.messageMu.Lock()
+		for i, msg := range session.pendingMessages {
+			if msg.ID == id {
+				session.pendingMessages = append(session.pendingMessages[:i], session.pendingMessages[i+1:]...)
+				session.messageMu.Unlock()
+				return session, msg, true
+			}
+		}
+		session.messageMu.Unlock()
+	}
+	return nil, PendingMessage{}, false
+}
+
+// TakeMessagesThrough removes the pending message with the given ID and every
+// message queued ahead of it in its session, and returns them in queue order
+// with their session. Processing a message before those ahead of it is
+// unsafe: a summary ends the session and drops whatever is still queued. ok
+// is false when no session has such a message.
+func (m *Manager) TakeMessagesThrough(id int64) (*ActiveSession, []PendingMessage, bool) {
+	for _, session := range m.GetAllSessions() {
+		session.messageMu.Lock()
+		for i, msg := range session.pendingMessages {
+			if msg.ID == id {
+				taken := make([]PendingMessage, i+1)
+				copy(taken, session.pendingMessages[:i+1])
+				session.pendingMessages = append(session.pendingMessages[:0], session.pendingMessages[i+1:]...)
+				session.messageMu.Unlock()
+				return session, taken, true
+			}
+		}
+		session.messageMu.Unlock()
+	}
+	return nil, nil, false
+}
+
+// RequeueMessage puts a message taken with TakeMessage back at the front of
+// its session's queue. It is dropped when the session no longer exists.
+func (m *Manager) RequeueMessage(sessionDBID int64, msg PendingMessage) bool {
+	m.mu.RLock()
+	session, ok := m.sessions[sessionDBID]
+	m.mu.RUnlock()
+	if !ok {
+		return false
+	}
+	session.messageMu.Lock()
+	session.pendingMessages = append([]PendingMessage{msg}, session.pendingMessages...)
+	session.messageMu.Unlock()
+	return true
+}
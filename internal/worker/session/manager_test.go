@@ -186,6 +186,52 @@ func (s *ManagerSuite) TestDrainMessages() {
 	s.Empty(messages)
 }
 
+// TestPendingMessages tests queue inspection and taking messages by ID.
+func (s *ManagerSuite) TestPendingMessages() {
+	now := time.Now()
+	s.manager.sessions[1] = &ActiveSession{
+		SessionDBID: 1,
+		Project:     "engram",
+		pendingMessages: []PendingMessage{
+			{ID: 2, QueuedAt: now, Type: MessageTypeObservation, Observation: &ObservationData{ToolName: "Edit"}},
+			{ID: 3, QueuedAt: now.Add(time.Second), Type: MessageTypeSummarize},
+		},
+	}
+	s.manager.sessions[2] = &ActiveSession{
+		SessionDBID:     2,
+		pendingMessages: []PendingMessage{{ID: 1, QueuedAt: now.Add(-time.Minute), Type: MessageTypeObservation}},
+	}
+
+	pending := s.manager.PendingMessages()
+	s.Require().Len(pending, 3)
+	s.Equal([]int64{1, 2, 3}, []int64{pending[0].ID, pending[1].ID, pending[2].ID}, "oldest first")
+	s.Equal("Edit", pending[1].ToolName)
+	s.Equal("engram", pending[1].Project)
+	s.Equal("summarize", pending[2].Type)
+
+	sess, msg, ok := s.manager.TakeMessage(2)
+	s.Require().True(ok)
+	s.Equal(int64(1), sess.SessionDBID)
+	s.Equal(int64(2), msg.ID)
+	s.Len(s.manager.sessions[1].pendingMessages, 1)
+
+	_, _, ok = s.manager.TakeMessage(2)
+	s.False(ok, "a message can only be taken once")
+
+	s.True(s.manager.RequeueMessage(1, msg))
+	s.Equal(int64(2), s.manager.sessions[1].pendingMessages[0].ID, "requeued messages go first")
+	s.False(s.manager.RequeueMessage(999, msg))
+
+	sess, taken, ok := s.manager.TakeMessagesThrough(3)
+	s.Require().True(ok)
+	s.Equal(int64(1), sess.SessionDBID)
+	s.Require().Len(taken, 2, "messages queued ahead are taken too")
+	s.Equal([]int64{2, 3}, []int64{taken[0].ID, taken[1].ID})
+	s.Empty(s.manager.sessions[1].pendingMessages)
+	_, _, ok = s.manager.TakeMessagesThrough(3)
+	s.False(ok)
+}
+
 // TestSetOnSessionCreated tests callback setting.
 func (s *ManagerSuite) TestSetOnSessionCreated() {
 	var calledWith int64
@@ -1424,3 +1470,57 @@ func TestConcurrentQueueAndCleanup(t *testing.T) {
 	// Should have 50 messages total
 	assert.Equal(t, 50, manager.GetTotalQueueDepth())
 }
+
+// addTestSession registers a session started age ago with the given queue.
+func (s *ManagerSuite) addTestSession(id int64, age time.Duration, pending int) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.T().Cleanup(cancel)
+	s.manager.sessions[id] = &ActiveSession{
+		SessionDBID:     id,
+		StartTime:       time.Now().Add(-age),
+		pendingMessages: make([]PendingMessage, pending),
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+}
+
+// TestSessionCap tests that the oldest idle sessions are evicted past the cap.
+func (s *ManagerSuite) TestSessionCap() {
+	s.addTestSession(1, 3*time.Hour, 1) // oldest, but has queued work
+	s.addTestSession(2, 2*time.Hour, 0)
+	s.addTestSession(3, time.Hour, 0)
+	s.addTestSession(4, time.Minute, 0)
+
+	s.manager.SetMaxSessions(2)
+
+	s.Equal(2, s.manager.GetActiveSessionCount())
+	s.Contains(s.manager.sessions, int64(1))
+	s.Contains(s.manager.sessions, int64(4))
+}
+
+// TestTrimIdleSessions tests trimming idle sessions by age.
+func (s *ManagerSuite) TestTrimIdleSessions() {
+	s.addTestSession(1, time.Hour, 0)
+	s.addTestSession(2, time.Hour, 1)
+	s.addTestSession(3, time.Minute, 0)
+
+	s.Equal(1, s.manager.TrimIdleSessions(30*time.Minute))
+	s.Equal(2, s.manager.GetActiveSessionCount())
+	s.NotContains(s.manager.sessions, int64(1))
+
+	s.Equal(1, s.manager.TrimIdleSessions(0))
+	s.Equal(1, s.manager.GetActiveSessionCount())
+	s.Contains(s.manager.sessions, int64(2))
+}
+
+// TestTrimIdleSessions_KeepsInFlight tests that sessions whose messages are
+// in the processing pool are not idle.
+func (s *ManagerSuite) TestTrimIdleSessions_KeepsInFlight() {
+	s.addTestSession(1, time.Hour, 0)
+	s.addTestSession(2, time.Hour, 0)
+	s.manager.SetInFlightCheck(func(id int64) bool { return id == 1 })
+
+	s.Equal(1, s.manager.TrimIdleSessions(0))
+	s.Contains(s.manager.sessions, int64(1))
+	s.NotContains(s.manager.sessions, int64(2))
+}
@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/internal/digest"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// Session-start injection modes, the mode parameter of
+// /api/context/session-start. The session-start hook picks one per session
+// source (startup, resume, clear, compact).
+const (
+	// sessionStartModeFull injects issues, rules and recent memories.
+	sessionStartModeFull = "full"
+	// sessionStartModePinned injects rules and pinned memories only.
+	sessionStartModePinned = "pinned"
+	// sessionStartModeDigest injects rules and the current weekly digest.
+	sessionStartModeDigest = "digest"
+	// sessionStartModeNone injects nothing.
+	sessionStartModeNone = "none"
+)
+
+// parseSessionStartMode validates a mode parameter; empty means full.
+func parseSessionStartMode(v string) (string, error) {
+	switch mode := strings.ToLower(strings.TrimSpace(v)); mode {
+	case "":
+		return sessionStartModeFull, nil
+	case sessionStartModeFull, sessionStartModePinned, sessionStartModeDigest, sessionStartModeNone:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("mode must be full, pinned, digest or none, got %q", v)
+	}
+}
+
+// applySessionStartMode narrows a full session-start response to mode.
+// Behavioral rules are kept in every mode that injects anything.
+func (s *Service) applySessionStartMode(ctx context.Context, out *sessionStartCompatibilityResponse, mode, project string) {
+	switch mode {
+	case sessionStartModePinned:
+		out.Issues = []map[string]any{}
+		out.Memories = memoriesToMaps(s.loadPinnedMemories(ctx, project))
+	case sessionStartModeDigest:
+		out.Issues = []map[string]any{}
+		out.Memories = []map[string]any{}
+		out.Digest = s.currentDigest(ctx, project)
+	}
+}
+
+// currentDigest builds the project's digest of the current week, which is
+// not stored (see get_digest). It returns "" when the digest cannot be built.
+// ctx must carry the caller's tenant: the sessions and memories read here are
+// scoped to it, so the injected digest never shows another tenant's work.
+func (s *Service) currentDigest(ctx context.Context, project string) string {
+	if s.memoryStore == nil {
+		return ""
+	}
+	p, err := digest.ParsePeriod(digest.Weekly, "current", time.Now())
+	if err != nil {
+		return ""
+	}
+	memories, err := s.memoryStore.MatchMemories(ctx, gorm.MemoryFilter{Project: project, Since: p.Start, Until: p.End})
+	if err != nil {
+		log.Debug().Err(err).Str("project", project).Msg("Failed to fetch digest memories")
+		return ""
+	}
+	var sessions []*models.SDKSession
+	if s.sessionStore != nil {
+		sessions, _, err = s.sessionStore.ListSDKSessions(ctx, project, gorm.MaxPaginationLimit, 0, 0,
+			p.Start.UnixMilli(), p.End.UnixMilli()-1, "")
+		if err != nil {
+			log.Debug().Err(err).Str("project", project).Msg("Failed to fetch digest sessions")
+		}
+	}
+	return digest.Build(project, p, sessions, memories, digest.Options{})
+}
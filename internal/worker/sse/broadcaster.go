@@ -3,8 +3,11 @@ package sse
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,8 +18,24 @@ const (
 	// WriteTimeout is the timeout for writing to SSE clients.
 	// Prevents blocking on stale connections.
 	WriteTimeout = 2 * time.Second
+
+	// ReplayBufferSize is how many recent events are kept for clients that
+	// reconnect with Last-Event-ID. Older gaps are reported, not replayed.
+	ReplayBufferSize = 256
+
+	// RetryInterval is the reconnection delay advertised to EventSource clients.
+	RetryInterval = 3 * time.Second
 )
 
+// ErrTooManyClients is returned when a connection would exceed the client cap.
+var ErrTooManyClients = errors.New("too many SSE clients")
+
+// event is a broadcast message retained for replay.
+type event struct {
+	message string
+	id      uint64
+}
+
 // Client represents a connected SSE client.
 type Client struct {
 	Writer  http.ResponseWriter
@@ -30,16 +49,40 @@ type Client struct {
 }
 
 // Broadcaster manages SSE client connections and message broadcasting.
+//
+// Every broadcast gets the next event ID, sent as the SSE "id:" field. IDs
+// increase strictly on every connection, so a client can detect gaps, and a
+// reconnecting EventSource sends the last one back as Last-Event-ID; the
+// events it missed are then replayed from a ring of the most recent events.
 type Broadcaster struct {
-	clients map[string]*Client
-	mu      sync.RWMutex
-	nextID  int
+	clients    map[string]*Client
+	replay     []event // ring buffer; oldest entry at index start once full
+	mu         sync.RWMutex
+	nextID     int
+	lastSeq    uint64
+	start      int
+	replaySize int
+	maxClients int // 0 = unlimited
 }
 
-// NewBroadcaster creates a new SSE broadcaster.
+// NewBroadcaster creates a new SSE broadcaster with no client cap and a
+// replay ring of ReplayBufferSize events.
 func NewBroadcaster() *Broadcaster {
+	return NewBroadcasterWithLimits(0, ReplayBufferSize)
+}
+
+// NewBroadcasterWithLimits creates a broadcaster that refuses connections past
+// maxClients (0 = unlimited) and keeps replaySize events for replay
+// (ReplayBufferSize when replaySize <= 0).
+func NewBroadcasterWithLimits(maxClients, replaySize int) *Broadcaster {
+	if replaySize <= 0 {
+		replaySize = ReplayBufferSize
+	}
 	return &Broadcaster{
-		clients: make(map[string]*Client),
+		clients:    make(map[string]*Client),
+		replay:     make([]event, 0, replaySize),
+		replaySize: replaySize,
+		maxClients: maxClients,
 	}
 }
 
@@ -51,6 +94,10 @@ func (b *Broadcaster) AddClient(w http.ResponseWriter) (*Client, error) {
 	}
 
 	b.mu.Lock()
+	if b.full() {
+		b.mu.Unlock()
+		return nil, ErrTooManyClients
+	}
 	b.nextID++
 	id := fmt.Sprintf("client-%d", b.nextID)
 	client := &Client{
@@ -120,14 +167,18 @@ func (b *Broadcaster) Broadcast(data interface{}) {
 		return
 	}
 
-	message := fmt.Sprintf("data: %s\n\n", jsonData)
-
-	b.mu.RLock()
+	// Sequence assignment, buffering and the client snapshot happen under one
+	// lock so a client registered by HandleSSE either gets this event live or
+	// in its replay, never both and never neither.
+	b.mu.Lock()
+	b.lastSeq++
+	message := fmt.Sprintf("id: %d\ndata: %s\n\n", b.lastSeq, jsonData)
+	b.remember(event{id: b.lastSeq, message: message})
 	clients := make([]*Client, 0, len(b.clients))
 	for _, client := range b.clients {
 		clients = append(clients, client)
 	}
-	b.mu.RUnlock()
+	b.mu.Unlock()
 
 	if len(clients) == 0 {
 		return
@@ -160,6 +211,75 @@ func (b *Broadcaster) Broadcast(data interface{}) {
 	}
 }
 
+// full reports whether the client cap is reached. Caller holds b.mu.
+func (b *Broadcaster) full() bool {
+	return b.maxClients > 0 && len(b.clients) >= b.maxClients
+}
+
+// remember appends ev to the replay ring. Caller holds b.mu.
+func (b *Broadcaster) remember(ev event) {
+	if len(b.replay) < b.replaySize {
+		b.replay = append(b.replay, ev)
+		return
+	}
+	b.replay[b.start] = ev
+	b.start = (b.start + 1) % b.replaySize
+}
+
+// ReplayLen returns the number of events buffered for replay.
+func (b *Broadcaster) ReplayLen() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.replay)
+}
+
+// TrimReplay drops the buffered events and returns how many were dropped.
+// Clients resuming from before the trim get a replay_gap and refetch state.
+func (b *Broadcaster) TrimReplay() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := len(b.replay)
+	b.replay = make([]event, 0, b.replaySize)
+	b.start = 0
+	return n
+}
+
+// missedSince returns buffered events with an ID greater than lastID, oldest
+// first, and whether events between lastID and the oldest buffered one were
+// already evicted. An ID from the future means the server restarted and the
+// sequence was reset: everything buffered is replayed and reported as a gap.
+// Caller holds b.mu.
+func (b *Broadcaster) missedSince(lastID uint64) ([]event, bool) {
+	if lastID > b.lastSeq {
+		return b.missedAfter(0), true
+	}
+	if lastID == b.lastSeq {
+		return nil, false
+	}
+	missed := b.missedAfter(lastID)
+	gap := len(missed) == 0 || missed[0].id > lastID+1
+	return missed, gap
+}
+
+// missedAfter returns buffered events with an ID greater than lastID. Caller holds b.mu.
+func (b *Broadcaster) missedAfter(lastID uint64) []event {
+	var missed []event
+	for i := 0; i < len(b.replay); i++ {
+		ev := b.replay[(b.start+i)%len(b.replay)]
+		if ev.id > lastID {
+			missed = append(missed, ev)
+		}
+	}
+	return missed
+}
+
+// LastEventID returns the ID of the most recent broadcast (0 before the first).
+func (b *Broadcaster) LastEventID() uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.lastSeq
+}
+
 // writeToClient writes a message to a single client with timeout.
 func (b *Broadcaster) writeToClient(client *Client, message string, deadCh chan<- string) {
 	// Use a timeout channel to prevent blocking on stale connections
@@ -202,13 +322,71 @@ func (b *Broadcaster) ClientCount() int {
 	return len(b.clients)
 }
 
-// HandleSSE handles an SSE connection request.
 // KeepaliveInterval is how often the SSE handler sends a comment-line keepalive
 // to each connected client. Without this, HTTP WriteTimeout (60s) will close
 // idle SSE connections, causing the dashboard banner to flap. 25s keeps well
 // under any reasonable write timeout while being invisible to the client.
 const KeepaliveInterval = 25 * time.Second
 
+// lastEventID reads the resume position from the Last-Event-ID header (sent by
+// EventSource on reconnect) or the lastEventId query parameter (for clients
+// that cannot set headers).
+func lastEventID(r *http.Request) (uint64, bool) {
+	raw := strings.TrimSpace(r.Header.Get("Last-Event-ID"))
+	if raw == "" {
+		raw = strings.TrimSpace(r.URL.Query().Get("lastEventId"))
+	}
+	if raw == "" {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// addClientWithReplay registers a client and snapshots the events it missed in
+// one critical section. The client's WriteMu is returned locked; the caller
+// writes the preamble and replay, then unlocks.
+func (b *Broadcaster) addClientWithReplay(w http.ResponseWriter, lastID uint64, resume bool) (*Client, []event, bool, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, nil, false, fmt.Errorf("streaming not supported")
+	}
+
+	b.mu.Lock()
+	if b.full() {
+		b.mu.Unlock()
+		return nil, nil, false, ErrTooManyClients
+	}
+	b.nextID++
+	client := &Client{
+		ID:      fmt.Sprintf("client-%d", b.nextID),
+		Writer:  w,
+		Flusher: flusher,
+		Done:    make(chan struct{}),
+	}
+	client.WriteMu.Lock()
+	b.clients[client.ID] = client
+	var missed []event
+	var gap bool
+	if resume {
+		missed, gap = b.missedSince(lastID)
+	}
+	clientCount := len(b.clients)
+	b.mu.Unlock()
+
+	log.Debug().
+		Str("clientId", client.ID).
+		Int("totalClients", clientCount).
+		Msg("SSE client connected")
+
+	return client, missed, gap, nil
+}
+
+// HandleSSE handles an SSE connection request, replaying missed events when
+// the client resumes with Last-Event-ID.
 func (b *Broadcaster) HandleSSE(w http.ResponseWriter, r *http.Request) {
 	// Set SSE headers
 	w.Header().Set("Content-Type", "text/event-stream")
@@ -219,19 +397,46 @@ func (b *Broadcaster) HandleSSE(w http.ResponseWriter, r *http.Request) {
 	// SSE responses by default, causing the stream to appear stuck.
 	w.Header().Set("X-Accel-Buffering", "no")
 
-	client, err := b.AddClient(w)
+	lastID, resume := lastEventID(r)
+
+	client, missed, gap, err := b.addClientWithReplay(w, lastID, resume)
+	if errors.Is(err, ErrTooManyClients) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(RetryInterval.Seconds())))
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	defer b.RemoveClient(client)
 
-	// Send initial connection message
-	client.WriteMu.Lock()
+	// addClientWithReplay returns with client.WriteMu held, so live broadcasts
+	// queue behind the connection message and the replay.
+	fmt.Fprintf(w, "retry: %d\n", RetryInterval.Milliseconds())
 	fmt.Fprintf(w, "data: {\"type\":\"connected\",\"clientId\":\"%s\"}\n\n", client.ID)
+	if resume {
+		if gap {
+			// Events were evicted from the replay buffer: tell the client to
+			// refetch state instead of silently skipping them.
+			fmt.Fprintf(w, "data: {\"type\":\"replay_gap\",\"lastEventId\":%d}\n\n", lastID)
+		}
+		for _, ev := range missed {
+			fmt.Fprint(w, ev.message)
+		}
+	}
 	client.Flusher.Flush()
 	client.WriteMu.Unlock()
 
+	if resume {
+		log.Debug().
+			Str("clientId", client.ID).
+			Uint64("lastEventId", lastID).
+			Int("replayed", len(missed)).
+			Bool("gap", gap).
+			Msg("SSE client resumed")
+	}
+
 	// Keepalive ticker: SSE comment lines (`: ping\n\n`) are ignored by clients
 	// but keep the HTTP write path active, preventing WriteTimeout-driven closes.
 	ticker := time.NewTicker(KeepaliveInterval)
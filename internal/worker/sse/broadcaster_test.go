@@ -2,6 +2,8 @@
 package sse
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"sync"
@@ -543,3 +545,119 @@ func TestBroadcast_ClientDoneChannel(t *testing.T) {
 	// Give time for async processing
 	time.Sleep(20 * time.Millisecond)
 }
+
+// TestBroadcast_EventIDs verifies every broadcast carries an increasing SSE id.
+func TestBroadcast_EventIDs(t *testing.T) {
+	b := NewBroadcaster()
+	w := newMockResponseWriter()
+	_, err := b.AddClient(w)
+	require.NoError(t, err)
+
+	b.Broadcast(map[string]string{"type": "a"})
+	b.Broadcast(map[string]string{"type": "b"})
+
+	body := string(w.GetBody())
+	assert.Contains(t, body, "id: 1\ndata: {\"type\":\"a\"}\n\n")
+	assert.Contains(t, body, "id: 2\ndata: {\"type\":\"b\"}\n\n")
+	assert.Equal(t, uint64(2), b.LastEventID())
+}
+
+// serveSSE runs HandleSSE until the request context is cancelled and returns the body.
+func serveSSE(t *testing.T, b *Broadcaster, target string, header map[string]string) string {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, target, nil).WithContext(ctx)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := newMockResponseWriter()
+	done := make(chan struct{})
+	go func() {
+		b.HandleSSE(w, req)
+		close(done)
+	}()
+	time.Sleep(30 * time.Millisecond)
+	cancel()
+	<-done
+	return string(w.GetBody())
+}
+
+// TestHandleSSE_ReplaysMissedEvents verifies Last-Event-ID resumption.
+func TestHandleSSE_ReplaysMissedEvents(t *testing.T) {
+	b := NewBroadcaster()
+	for i := 0; i < 5; i++ {
+		b.Broadcast(map[string]int{"n": i})
+	}
+
+	body := serveSSE(t, b, "/api/events", map[string]string{"Last-Event-ID": "3"})
+	assert.Contains(t, body, "retry: 3000\n")
+	assert.Contains(t, body, "\"type\":\"connected\"")
+	assert.NotContains(t, body, "id: 3\n")
+	assert.Contains(t, body, "id: 4\ndata: {\"n\":3}")
+	assert.Contains(t, body, "id: 5\ndata: {\"n\":4}")
+	assert.NotContains(t, body, "replay_gap")
+
+	// Query parameter form, for clients that cannot set headers.
+	body = serveSSE(t, b, "/api/events?lastEventId=4", nil)
+	assert.Contains(t, body, "id: 5\n")
+	assert.NotContains(t, body, "id: 4\n")
+
+	// Fresh connections get no replay.
+	body = serveSSE(t, b, "/api/events", nil)
+	assert.NotContains(t, body, "id: ")
+}
+
+// TestHandleSSE_ReplayGap verifies that evicted events are reported as a gap.
+func TestHandleSSE_ReplayGap(t *testing.T) {
+	b := NewBroadcaster()
+	for i := 0; i < ReplayBufferSize+10; i++ {
+		b.Broadcast(map[string]int{"n": i})
+	}
+
+	body := serveSSE(t, b, "/api/events", map[string]string{"Last-Event-ID": "2"})
+	assert.Contains(t, body, "\"type\":\"replay_gap\"")
+	assert.Contains(t, body, fmt.Sprintf("id: %d\n", ReplayBufferSize+10))
+	assert.NotContains(t, body, "id: 10\n")
+
+	// An ID ahead of the server (restart) replays everything buffered as a gap.
+	body = serveSSE(t, b, "/api/events", map[string]string{"Last-Event-ID": "999999"})
+	assert.Contains(t, body, "\"type\":\"replay_gap\"")
+	assert.Contains(t, body, "id: 11\n")
+}
+
+// TestBroadcaster_ClientCap verifies connections past the cap are refused.
+func TestBroadcaster_ClientCap(t *testing.T) {
+	b := NewBroadcasterWithLimits(1, 0)
+	client, err := b.AddClient(newMockResponseWriter())
+	require.NoError(t, err)
+
+	_, err = b.AddClient(newMockResponseWriter())
+	assert.ErrorIs(t, err, ErrTooManyClients)
+
+	w := newMockResponseWriter()
+	b.HandleSSE(w, httptest.NewRequest(http.MethodGet, "/api/events", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.statusCode)
+
+	b.RemoveClient(client)
+	_, err = b.AddClient(newMockResponseWriter())
+	assert.NoError(t, err)
+}
+
+// TestBroadcaster_TrimReplay verifies a trimmed ring reports a gap on resume.
+func TestBroadcaster_TrimReplay(t *testing.T) {
+	b := NewBroadcasterWithLimits(0, 4)
+	for i := 0; i < 10; i++ {
+		b.Broadcast(map[string]int{"n": i})
+	}
+	assert.Equal(t, 4, b.ReplayLen())
+	assert.Equal(t, 4, b.TrimReplay())
+	assert.Equal(t, 0, b.ReplayLen())
+
+	body := serveSSE(t, b, "/api/events", map[string]string{"Last-Event-ID": "8"})
+	assert.Contains(t, body, "\"type\":\"replay_gap\"")
+	assert.NotContains(t, body, "id: 9\n")
+
+	b.Broadcast(map[string]int{"n": 10})
+	assert.Equal(t, 1, b.ReplayLen())
+	assert.Equal(t, uint64(11), b.LastEventID())
+}
@@ -0,0 +1,175 @@
+package worker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// jsonKind is the JSON type a request field must have.
+type jsonKind string
+
+const (
+	jsonAny    jsonKind = "any"
+	jsonString jsonKind = "string"
+	jsonNumber jsonKind = "number"
+	jsonBool   jsonKind = "boolean"
+	jsonObject jsonKind = "object"
+	jsonArray  jsonKind = "array"
+)
+
+// requestSchema describes the JSON object a hook endpoint accepts: its
+// fields with their types, and the fields that must be present.
+type requestSchema struct {
+	fields   map[string]jsonKind
+	required []string
+}
+
+// schemaOf derives a requestSchema from the json tags of a request struct,
+// so the schema cannot drift from the type the handler decodes into.
+func schemaOf(v any, required ...string) requestSchema {
+	t := reflect.TypeOf(v)
+	schema := requestSchema{fields: make(map[string]jsonKind, t.NumField()), required: required}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		schema.fields[name] = kindOf(f.Type)
+	}
+	for _, name := range required {
+		if _, ok := schema.fields[name]; !ok {
+			panic(fmt.Sprintf("schemaOf(%s): required field %q is not a field", t.Name(), name))
+		}
+	}
+	return schema
+}
+
+func kindOf(t reflect.Type) jsonKind {
+	switch t.Kind() {
+	case reflect.String:
+		return jsonString
+	case reflect.Bool:
+		return jsonBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return jsonNumber
+	case reflect.Map, reflect.Struct:
+		return jsonObject
+	case reflect.Slice, reflect.Array:
+		return jsonArray
+	case reflect.Pointer:
+		return kindOf(t.Elem())
+	default:
+		return jsonAny
+	}
+}
+
+// kindOfValue returns the JSON type of a raw value, or "null".
+func kindOfValue(raw json.RawMessage) jsonKind {
+	switch raw[0] {
+	case '"':
+		return jsonString
+	case '{':
+		return jsonObject
+	case '[':
+		return jsonArray
+	case 't', 'f':
+		return jsonBool
+	case 'n':
+		return "null"
+	default:
+		return jsonNumber
+	}
+}
+
+// validationError is a request body rejected by validate.
+type validationError struct {
+	status int
+	msg    string
+}
+
+// validate checks body against the schema: a single JSON object without
+// unknown fields, required fields present and non-empty, every field of its
+// declared type (null is accepted for optional fields) and at most
+// maxFieldBytes long.
+func (sc requestSchema) validate(body []byte, maxFieldBytes int) *validationError {
+	var fields map[string]json.RawMessage
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if err := dec.Decode(&fields); err != nil || fields == nil {
+		return &validationError{http.StatusBadRequest, "request body must be a JSON object"}
+	}
+	if dec.More() {
+		return &validationError{http.StatusBadRequest, "request body must be a single JSON object"}
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		raw := fields[name]
+		want, ok := sc.fields[name]
+		if !ok {
+			return &validationError{http.StatusBadRequest, fmt.Sprintf("unknown field %q", name)}
+		}
+		if maxFieldBytes > 0 && len(raw) > maxFieldBytes {
+			return &validationError{http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("field %q is %d bytes, limit is %d", name, len(raw), maxFieldBytes)}
+		}
+		got := kindOfValue(raw)
+		if want != jsonAny && got != want && got != "null" {
+			return &validationError{http.StatusBadRequest, fmt.Sprintf("field %q must be a %s, got %s", name, want, got)}
+		}
+	}
+	for _, name := range sc.required {
+		raw, ok := fields[name]
+		if !ok || kindOfValue(raw) == "null" || string(raw) == `""` {
+			return &validationError{http.StatusBadRequest, fmt.Sprintf("field %q is required", name)}
+		}
+	}
+	return nil
+}
+
+// ValidateJSONBody rejects request bodies larger than maxBodyBytes (413) or
+// not matching schema (400, or 413 for a field over maxFieldBytes) before the
+// handler runs. The handler then reads the validated body as usual.
+func ValidateJSONBody(schema requestSchema, maxBodyBytes, maxFieldBytes int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > int64(maxBodyBytes) {
+				http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, int64(maxBodyBytes)))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+					return
+				}
+				http.Error(w, "failed to read request body", http.StatusBadRequest)
+				return
+			}
+			if verr := schema.validate(body, maxFieldBytes); verr != nil {
+				http.Error(w, verr.msg, verr.status)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
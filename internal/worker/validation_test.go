@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaOf(t *testing.T) {
+	schema := schemaOf(MemoryTriggerRequest{}, "tool")
+	assert.Equal(t, map[string]jsonKind{
+		"tool":       jsonString,
+		"params":     jsonObject,
+		"project":    jsonString,
+		"session_id": jsonString,
+	}, schema.fields)
+	assert.Equal(t, jsonAny, schemaOf(ObservationRequest{}).fields["tool_response"])
+	assert.Panics(t, func() { schemaOf(MemoryTriggerRequest{}, "missing") })
+}
+
+func TestValidateJSONBody(t *testing.T) {
+	var got string
+	handler := ValidateJSONBody(schemaOf(MemoryTriggerRequest{}, "tool"), 200, 64)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			got = string(body)
+		}))
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"valid", `{"tool":"Edit","params":{"file_path":"a.go"},"session_id":null}`, http.StatusOK},
+		{"unknown field", `{"tool":"Edit","tool_response":"x"}`, http.StatusBadRequest},
+		{"wrong type", `{"tool":"Edit","params":"a.go"}`, http.StatusBadRequest},
+		{"missing required", `{"project":"p"}`, http.StatusBadRequest},
+		{"empty required", `{"tool":""}`, http.StatusBadRequest},
+		{"not an object", `["tool"]`, http.StatusBadRequest},
+		{"trailing data", `{"tool":"Edit"}{}`, http.StatusBadRequest},
+		{"field too large", `{"tool":"Edit","params":{"x":"` + strings.Repeat("a", 80) + `"}}`, http.StatusRequestEntityTooLarge},
+		{"body too large", `{"tool":"` + strings.Repeat("a", 300) + `"}`, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = ""
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/memory/triggers", strings.NewReader(tt.body)))
+			require.Equal(t, tt.status, rec.Code, rec.Body.String())
+			if tt.status == http.StatusOK {
+				assert.Equal(t, tt.body, got, "the handler reads the validated body")
+			} else {
+				assert.Empty(t, got, "rejected bodies never reach the handler")
+			}
+		})
+	}
+}
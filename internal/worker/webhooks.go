@@ -0,0 +1,208 @@
+package worker
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/thebtf/engram/internal/capture"
+	"github.com/thebtf/engram/internal/config"
+	"github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/internal/privacy"
+	"github.com/thebtf/engram/internal/tenant"
+	"github.com/thebtf/engram/internal/worker/notify"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// defaultWebhookTemplates are the built-in templates per webhook kind and
+// event. Each renders empty for the actions not worth remembering.
+var defaultWebhookTemplates = map[string]map[string]string{
+	config.WebhookKindGitHub: {
+		"push": `{{with .head_commit}}Pushed {{len $.commits}} commit(s) to {{trimPrefix "refs/heads/" $.ref}} in {{$.repository.full_name}}: {{firstLine .message}}{{end}}`,
+		"pull_request": `{{if eq .action "opened"}}PR #{{.number}} opened in {{.repository.full_name}}: {{.pull_request.title}}` +
+			`{{else if and (eq .action "closed") .pull_request.merged}}PR #{{.number}} merged in {{.repository.full_name}}: {{.pull_request.title}}{{end}}`,
+		"workflow_run": `{{if eq .action "completed"}}CI {{.workflow_run.name}} {{.workflow_run.conclusion}} on {{.workflow_run.head_branch}} in {{.repository.full_name}}{{with .workflow_run.html_url}} ({{.}}){{end}}{{end}}`,
+		"release":      `{{if eq .action "published"}}Released {{.release.tag_name}} of {{.repository.full_name}}{{with .release.name}}: {{.}}{{end}}{{end}}`,
+	},
+	config.WebhookKindGeneric: {
+		"*": `{{with .text}}{{.}}{{else}}{{with .message}}{{.}}{{end}}{{end}}`,
+	},
+}
+
+// webhookFuncs are the functions available to webhook templates.
+var webhookFuncs = template.FuncMap{
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"firstLine": func(s string) string {
+		line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+		return strings.TrimSpace(line)
+	},
+}
+
+// webhookTemplate returns the template for event: the source's own for the
+// event, the built-in one of its kind, then the source's and the kind's "*".
+func webhookTemplate(src config.WebhookSource, kind, event string) string {
+	builtin := defaultWebhookTemplates[kind]
+	for _, tmpl := range []string{src.Templates[event], builtin[event], src.Templates["*"], builtin["*"]} {
+		if tmpl != "" {
+			return tmpl
+		}
+	}
+	return ""
+}
+
+// renderWebhook executes tmpl over the decoded payload.
+func renderWebhook(tmpl string, payload any) (string, error) {
+	t, err := template.New("webhook").Funcs(webhookFuncs).Option("missingkey=zero").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := t.Execute(&out, payload); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(strings.ReplaceAll(out.String(), "<no value>", "")), nil
+}
+
+// validWebhookSignature checks a "sha256=<hex>" HMAC-SHA256 signature of body.
+func validWebhookSignature(secret string, body []byte, signature string) bool {
+	sig, ok := strings.CutPrefix(strings.TrimSpace(signature), "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// handleWebhook godoc
+// @Summary Receive an inbound webhook
+// @Description Verifies a delivery from a configured source (ENGRAM_WEBHOOKS) by its HMAC-SHA256 signature and stores it as a memory rendered by the template of its event. Deliveries whose template renders empty are acknowledged and ignored.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param source path string true "Configured webhook source"
+// @Success 201 {object} models.Memory
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {string} string "invalid payload"
+// @Failure 401 {string} string "invalid signature"
+// @Failure 404 {string} string "unknown webhook source"
+// @Failure 422 {string} string "template error or blocked by capture policy"
+// @Failure 500 {string} string "internal error"
+// @Router /api/webhooks/{source} [post]
+func (s *Service) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "source")
+	cfg := s.currentConfig()
+	src, ok := cfg.Webhooks[name]
+	if !ok || src.Secret == "" || src.Project == "" {
+		http.Error(w, "unknown webhook source", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	signature := r.Header.Get("X-Hub-Signature-256")
+	if signature == "" {
+		signature = r.Header.Get("X-Webhook-Signature")
+	}
+	if !validWebhookSignature(src.Secret, body, signature) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	kind := src.KindOf(name)
+	event := r.Header.Get("X-Webhook-Event")
+	if kind == config.WebhookKindGitHub {
+		event = r.Header.Get("X-GitHub-Event")
+	}
+	if event == "ping" {
+		writeJSON(w, map[string]any{"status": "pong"})
+		return
+	}
+
+	var payload any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber() // render IDs and counts verbatim, not as floats
+	if err := dec.Decode(&payload); err != nil {
+		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	content := ""
+	if tmpl := webhookTemplate(src, kind, event); tmpl != "" {
+		if content, err = renderWebhook(tmpl, payload); err != nil {
+			http.Error(w, fmt.Sprintf("webhook template for %q: %v", event, err), http.StatusUnprocessableEntity)
+			return
+		}
+	}
+	if content == "" {
+		w.WriteHeader(http.StatusAccepted)
+		writeJSON(w, map[string]any{"status": "ignored", "event": event})
+		return
+	}
+
+	tags := append([]string{"webhook", "webhook:" + name}, src.Tags...)
+	if event != "" {
+		tags = append(tags, "event:"+event)
+	}
+	if decision := capture.Evaluate(cfg.CapturePolicy, src.Project, capture.Event{Concepts: tags}); !decision.Allowed {
+		http.Error(w, "blocked by capture policy: "+decision.Reason(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	s.initMu.RLock()
+	memoryStore := s.memoryStore
+	s.initMu.RUnlock()
+	if memoryStore == nil {
+		http.Error(w, "memory store not available", http.StatusServiceUnavailable)
+		return
+	}
+	ctx := r.Context()
+	if src.Tenant != "" {
+		ctx = tenant.WithTenant(ctx, src.Tenant)
+	}
+	created, err := memoryStore.Create(ctx, &models.Memory{
+		Project:     src.Project,
+		Content:     privacy.RedactForStorage(src.Project, content),
+		Tags:        tags,
+		SourceAgent: "webhook:" + name,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("source", name).Str("event", event).Msg("store webhook memory failed")
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	log.Info().Str("source", name).Str("event", event).Int64("memory_id", created.ID).Msg("Webhook stored as memory")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, created)
+}
+
+// publishMemoryChange sends a memory change to the outbound webhooks as a
+// "memory.<action>" event. Private memories are never sent.
+func (s *Service) publishMemoryChange(change gorm.MemoryChange) {
+	if change.Memory.Visibility == models.VisibilityPrivate {
+		return
+	}
+	s.notifier.Publish(notify.Event{
+		Type:    "memory." + change.Action,
+		Project: change.Memory.Project,
+		Data:    change.Memory,
+	})
+}
@@ -0,0 +1,99 @@
+package worker
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/internal/config"
+)
+
+func signWebhook(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestValidWebhookSignature(t *testing.T) {
+	body := []byte(`{"a":1}`)
+	assert.True(t, validWebhookSignature("s3cret", body, signWebhook("s3cret", `{"a":1}`)))
+	assert.False(t, validWebhookSignature("other", body, signWebhook("s3cret", `{"a":1}`)))
+	assert.False(t, validWebhookSignature("s3cret", body, strings.TrimPrefix(signWebhook("s3cret", `{"a":1}`), "sha256=")))
+	assert.False(t, validWebhookSignature("s3cret", body, ""))
+}
+
+// TestRenderWebhook_GitHubDefaults checks the built-in GitHub templates,
+// including the actions they deliberately ignore.
+func TestRenderWebhook_GitHubDefaults(t *testing.T) {
+	render := func(event, payload string) string {
+		t.Helper()
+		var v any
+		dec := json.NewDecoder(strings.NewReader(payload))
+		dec.UseNumber()
+		require.NoError(t, dec.Decode(&v))
+		out, err := renderWebhook(webhookTemplate(config.WebhookSource{}, config.WebhookKindGitHub, event), v)
+		require.NoError(t, err)
+		return out
+	}
+	repo := `"repository":{"full_name":"acme/engram"}`
+
+	assert.Equal(t, "PR #1042 merged in acme/engram: Refactor auth middleware",
+		render("pull_request", `{"action":"closed","number":1042,"pull_request":{"title":"Refactor auth middleware","merged":true},`+repo+`}`))
+	assert.Empty(t, render("pull_request", `{"action":"closed","number":7,"pull_request":{"title":"x","merged":false},`+repo+`}`))
+	assert.Empty(t, render("pull_request", `{"action":"labeled","number":7,"pull_request":{"title":"x"},`+repo+`}`))
+	assert.Equal(t, "Pushed 2 commit(s) to main in acme/engram: Fix token refresh",
+		render("push", `{"ref":"refs/heads/main","commits":[{},{}],"head_commit":{"message":"Fix token refresh\n\nDetails"},`+repo+`}`))
+	assert.Empty(t, render("push", `{"ref":"refs/heads/gone","commits":[],"head_commit":null,`+repo+`}`))
+	assert.Equal(t, "CI build failure on main in acme/engram",
+		render("workflow_run", `{"action":"completed","workflow_run":{"name":"build","conclusion":"failure","head_branch":"main"},`+repo+`}`))
+}
+
+func TestWebhookTemplate_Precedence(t *testing.T) {
+	src := config.WebhookSource{Templates: map[string]string{"push": "custom push", "*": "custom any"}}
+	assert.Equal(t, "custom push", webhookTemplate(src, config.WebhookKindGitHub, "push"))
+	assert.Equal(t, defaultWebhookTemplates[config.WebhookKindGitHub]["release"], webhookTemplate(src, config.WebhookKindGitHub, "release"))
+	assert.Equal(t, "custom any", webhookTemplate(src, config.WebhookKindGitHub, "issues"))
+	assert.Equal(t, defaultWebhookTemplates[config.WebhookKindGeneric]["*"], webhookTemplate(config.WebhookSource{}, config.WebhookKindGeneric, "build"))
+}
+
+// TestHandleWebhook_Rejects checks the paths that end before a memory is
+// stored: unknown sources, bad signatures, pings and ignored events.
+func TestHandleWebhook_Rejects(t *testing.T) {
+	cfg := config.Default()
+	cfg.Webhooks = map[string]config.WebhookSource{
+		"github":   {Secret: "s3cret", Project: "engram"},
+		"disabled": {Project: "engram"},
+	}
+	svc := &Service{config: cfg}
+	router := chi.NewRouter()
+	router.Post("/api/webhooks/{source}", svc.handleWebhook)
+
+	post := func(source, event, body, signature string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/webhooks/"+source, strings.NewReader(body))
+		req.Header.Set("X-GitHub-Event", event)
+		req.Header.Set("X-Hub-Signature-256", signature)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	body := `{"action":"labeled","number":7}`
+
+	assert.Equal(t, http.StatusNotFound, post("gitlab", "push", body, signWebhook("s3cret", body)).Code)
+	assert.Equal(t, http.StatusNotFound, post("disabled", "push", body, signWebhook("", body)).Code, "a source without a secret is disabled")
+	assert.Equal(t, http.StatusUnauthorized, post("github", "pull_request", body, signWebhook("wrong", body)).Code)
+	assert.Equal(t, http.StatusOK, post("github", "ping", body, signWebhook("s3cret", body)).Code)
+	assert.Equal(t, http.StatusBadRequest, post("github", "push", "not json", signWebhook("s3cret", "not json")).Code)
+
+	rec := post("github", "pull_request", body, signWebhook("s3cret", body))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"ignored"`)
+}
@@ -0,0 +1,377 @@
+// Package client is a typed Go client for the engram server HTTP API.
+//
+// Requests and responses are plain structs mirroring the worker's JSON
+// contracts (see /api/openapi.json on a running server), so callers no longer
+// build map[string]any payloads or type-assert on decoded maps. Endpoints
+// without a typed method are still reachable through Do.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+// DefaultBaseURL is the server address used when New is given an empty URL.
+const DefaultBaseURL = "http://localhost:37777"
+
+// DefaultTimeout bounds each request made with the default HTTP client.
+const DefaultTimeout = 30 * time.Second
+
+// Client talks to one engram server. It is safe for concurrent use.
+type Client struct {
+	http    *http.Client
+	baseURL string
+	token   string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithToken authenticates every request with a bearer token (a keycard or
+// the admin token).
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithHTTPClient replaces the default HTTP client, e.g. to change timeouts
+// or trust a self-signed certificate.
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *Client) {
+		if h != nil {
+			c.http = h
+		}
+	}
+}
+
+// New returns a client for the server at baseURL.
+func New(baseURL string, opts ...Option) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	c := &Client{
+		http:    &http.Client{Timeout: DefaultTimeout},
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// BaseURL returns the server address without a trailing slash.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// HasToken reports whether requests carry a bearer token.
+func (c *Client) HasToken() bool {
+	return c.token != ""
+}
+
+// APIError is returned for non-2xx responses.
+type APIError struct {
+	Method     string
+	Path       string
+	Status     string
+	Message    string
+	StatusCode int
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s: %s: %s", e.Method, e.Path, e.Status, e.Message)
+}
+
+// Do sends body as JSON (when non-nil) and decodes a JSON response into out
+// (when non-nil). path may carry a query string. Non-2xx responses are
+// returned as *APIError.
+func (c *Client) Do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &APIError{
+			Method:     method,
+			Path:       path,
+			Status:     resp.Status,
+			StatusCode: resp.StatusCode,
+			Message:    strings.TrimSpace(string(data)),
+		}
+	}
+	if out == nil {
+		return nil
+	}
+	if raw, ok := out.(*json.RawMessage); ok {
+		*raw = append((*raw)[:0], data...)
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode %s %s response: %w", method, path, err)
+	}
+	return nil
+}
+
+// Health is the response of GET /health.
+type Health struct {
+	// Status is starting, ready or error.
+	Status  string `json:"status"`
+	Version string `json:"version"`
+}
+
+// Health reports whether the server is up. It answers even while the
+// database is still initialising; use Ready to wait for that.
+func (c *Client) Health(ctx context.Context) (*Health, error) {
+	var h Health
+	if err := c.Do(ctx, http.MethodGet, "/health", nil, &h); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// Ready returns nil once the server has finished initialising.
+func (c *Client) Ready(ctx context.Context) error {
+	return c.Do(ctx, http.MethodGet, "/api/ready", nil, nil)
+}
+
+// VersionInfo is the response of GET /api/version.
+type VersionInfo struct {
+	Version        string `json:"version"`
+	MinHookVersion string `json:"min_hook_version"`
+}
+
+// Version returns the server version and the oldest supported plugin version.
+func (c *Client) Version(ctx context.Context) (*VersionInfo, error) {
+	var v VersionInfo
+	if err := c.Do(ctx, http.MethodGet, "/api/version", nil, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Identity is the response of GET /api/auth/me.
+type Identity struct {
+	Role          string `json:"role"`
+	Authenticated bool   `json:"authenticated"`
+	AuthDisabled  bool   `json:"auth_disabled"`
+}
+
+// Me returns the role the client's credentials map to.
+func (c *Client) Me(ctx context.Context) (*Identity, error) {
+	var id Identity
+	if err := c.Do(ctx, http.MethodGet, "/api/auth/me", nil, &id); err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// StoreMemoryRequest is the body of POST /api/memories.
+type StoreMemoryRequest struct {
+	Project     string   `json:"project"`
+	Content     string   `json:"content"`
+	Tags        []string `json:"tags,omitempty"`
+	SourceAgent string   `json:"source_agent,omitempty"`
+	// Visibility is private, team (the server default) or public.
+	Visibility models.Visibility `json:"visibility,omitempty"`
+}
+
+// StoreMemory creates a memory and returns it as stored.
+func (c *Client) StoreMemory(ctx context.Context, req StoreMemoryRequest) (*models.Memory, error) {
+	var mem models.Memory
+	if err := c.Do(ctx, http.MethodPost, "/api/memories", req, &mem); err != nil {
+		return nil, err
+	}
+	return &mem, nil
+}
+
+// CaptureCommitRequest is the body of POST /api/capture/commit.
+type CaptureCommitRequest struct {
+	Project    string   `json:"project"`
+	SHA        string   `json:"sha"`
+	Branch     string   `json:"branch,omitempty"`
+	Message    string   `json:"message"`
+	SessionID  string   `json:"session_id,omitempty"`
+	Files      []string `json:"files,omitempty"`
+	Insertions int      `json:"insertions"`
+	Deletions  int      `json:"deletions"`
+}
+
+// CaptureCommit records a git commit as a change memory and returns it.
+func (c *Client) CaptureCommit(ctx context.Context, req CaptureCommitRequest) (*models.Memory, error) {
+	var mem models.Memory
+	if err := c.Do(ctx, http.MethodPost, "/api/capture/commit", req, &mem); err != nil {
+		return nil, err
+	}
+	return &mem, nil
+}
+
+// UpdateMemoryRequest is the body of PUT /api/memories/{id}. Nil fields keep
+// their current value.
+type UpdateMemoryRequest struct {
+	Content  *string   `json:"content,omitempty"`
+	Tags     *[]string `json:"tags,omitempty"`
+	EditedBy string    `json:"edited_by,omitempty"`
+}
+
+// UpdateMemory changes a memory's content and/or tags and returns the new
+// version.
+func (c *Client) UpdateMemory(ctx context.Context, id int64, req UpdateMemoryRequest) (*models.Memory, error) {
+	var mem models.Memory
+	if err := c.Do(ctx, http.MethodPut, memoryPath(id), req, &mem); err != nil {
+		return nil, err
+	}
+	return &mem, nil
+}
+
+// GetMemory returns one memory.
+func (c *Client) GetMemory(ctx context.Context, id int64) (*models.Memory, error) {
+	var mem models.Memory
+	if err := c.Do(ctx, http.MethodGet, memoryPath(id), nil, &mem); err != nil {
+		return nil, err
+	}
+	return &mem, nil
+}
+
+// DeleteMemory deletes one memory.
+func (c *Client) DeleteMemory(ctx context.Context, id int64) error {
+	return c.Do(ctx, http.MethodDelete, memoryPath(id), nil, nil)
+}
+
+// ListMemories returns a project's memories, newest first. limit <= 0 uses
+// the server default; the server caps it at 500.
+func (c *Client) ListMemories(ctx context.Context, project string, limit int) ([]models.Memory, error) {
+	q := url.Values{"project": {project}}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var mems []models.Memory
+	if err := c.Do(ctx, http.MethodGet, "/api/memories?"+q.Encode(), nil, &mems); err != nil {
+		return nil, err
+	}
+	return mems, nil
+}
+
+// ListMemoryRelations returns the relations between a project's memories.
+func (c *Client) ListMemoryRelations(ctx context.Context, project string) ([]*models.ObservationRelation, error) {
+	var relations []*models.ObservationRelation
+	q := url.Values{"project": {project}}
+	if err := c.Do(ctx, http.MethodGet, "/api/memories/relations?"+q.Encode(), nil, &relations); err != nil {
+		return nil, err
+	}
+	return relations, nil
+}
+
+// SearchRequest is the body of POST /api/context/search.
+type SearchRequest struct {
+	Project          string   `json:"project"`
+	Query            string   `json:"query"`
+	AgentID          string   `json:"agent_id,omitempty"`
+	ObsType          string   `json:"obs_type,omitempty"`
+	Language         string   `json:"language,omitempty"`
+	FilesBeingEdited []string `json:"files_being_edited,omitempty"`
+	SessionID        string   `json:"session_id,omitempty"`
+	ExcludeInjected  *bool    `json:"exclude_already_injected,omitempty"`
+	BoostConcepts    *bool    `json:"boost_concepts,omitempty"`
+	// Limit is sent as a query parameter; 0 uses the server default.
+	Limit int `json:"-"`
+}
+
+// SearchResult is one observation in a search response.
+type SearchResult struct {
+	models.ObservationJSON
+	// MatchedFiles are the FilesBeingEdited the observation mentions.
+	MatchedFiles []string `json:"matched_files,omitempty"`
+	Similarity   float64  `json:"similarity,omitempty"`
+}
+
+// SearchResponse is the response of POST /api/context/search.
+type SearchResponse struct {
+	Project         string         `json:"project"`
+	Query           string         `json:"query"`
+	Language        string         `json:"language"`
+	Intent          string         `json:"intent"`
+	Strategy        string         `json:"strategy"`
+	Observations    []SearchResult `json:"observations"`
+	Threshold       float64        `json:"threshold"`
+	MaxResults      int            `json:"max_results"`
+	TotalResults    int            `json:"total_results"`
+	AlreadyInjected int            `json:"already_injected"`
+}
+
+// Search returns the observations relevant to a prompt.
+func (c *Client) Search(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
+	path := "/api/context/search"
+	if req.Limit > 0 {
+		path += "?limit=" + strconv.Itoa(req.Limit)
+	}
+	var resp SearchResponse
+	if err := c.Do(ctx, http.MethodPost, path, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SessionStartContext returns the context injected at session start for a
+// project, undecoded.
+func (c *Client) SessionStartContext(ctx context.Context, project string) (json.RawMessage, error) {
+	var raw json.RawMessage
+	q := url.Values{"project": {project}}
+	if err := c.Do(ctx, http.MethodGet, "/api/context/session-start?"+q.Encode(), nil, &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// Stats returns the server statistics. The sections vary with the server's
+// configuration, so the response is left as a map. A non-empty project adds
+// that project's counts.
+func (c *Client) Stats(ctx context.Context, project string) (map[string]any, error) {
+	path := "/api/stats"
+	if project != "" {
+		path += "?" + url.Values{"project": {project}}.Encode()
+	}
+	var stats map[string]any
+	if err := c.Do(ctx, http.MethodGet, path, nil, &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func memoryPath(id int64) string {
+	return "/api/memories/" + strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestClient_StoreMemory(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/memories", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]any{
+			"project":    "p",
+			"content":    "hello",
+			"visibility": "private",
+		}, body)
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 7, "project": "p", "content": "hello", "version": 1}`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL+"/", WithToken("secret"))
+	mem, err := c.StoreMemory(context.Background(), StoreMemoryRequest{
+		Project:    "p",
+		Content:    "hello",
+		Visibility: models.VisibilityPrivate,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), mem.ID)
+	assert.Equal(t, "hello", mem.Content)
+}
+
+func TestClient_Search(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "5", r.URL.Query().Get("limit"))
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.NotContains(t, body, "limit", "limit travels in the query string")
+		_, _ = w.Write([]byte(`{"project": "p", "query": "q", "observations": [
+			{"id": 3, "type": "decision", "title": "Use chi", "similarity": 0.75}
+		], "total_results": 1}`))
+	}))
+	defer srv.Close()
+
+	resp, err := New(srv.URL).Search(context.Background(), SearchRequest{Project: "p", Query: "q", Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, resp.Observations, 1)
+	obs := resp.Observations[0]
+	assert.Equal(t, int64(3), obs.ID)
+	assert.Equal(t, models.ObservationType("decision"), obs.Type)
+	assert.Equal(t, "Use chi", obs.Title)
+	assert.InDelta(t, 0.75, obs.Similarity, 1e-9)
+	assert.Equal(t, 1, resp.TotalResults)
+}
+
+func TestClient_APIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "memory is locked", http.StatusConflict)
+	}))
+	defer srv.Close()
+
+	err := New(srv.URL).DeleteMemory(context.Background(), 9)
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+	assert.Equal(t, "/api/memories/9", apiErr.Path)
+	assert.Equal(t, "memory is locked", apiErr.Message)
+}
+
+func TestClient_DecodeError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status": 1}`))
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL).Health(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "decode GET /health response")
+}
@@ -0,0 +1,216 @@
+// Package engram embeds read access to an engram database in another Go
+// program, such as a CI job or code review bot that annotates pull requests
+// with relevant memories.
+//
+// Open connects straight to an existing database; no server or worker runs,
+// nothing is migrated and every connection is read-only, so a bot cannot
+// change what the team's agents remember. Search accepts the same query
+// syntax as the recall tool.
+//
+// A DB reads the memories of one tenant (the default tenant unless
+// WithTenant names another) and, like any other client, does not see
+// private memories unless WithOwner names their owner.
+package engram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	gogorm "gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/internal/mcp"
+	"github.com/thebtf/engram/internal/tenant"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+const (
+	// DefaultSearchLimit is the number of memories Search returns when
+	// SearchOptions.Limit is not set.
+	DefaultSearchLimit = 20
+	// MaxSearchLimit caps SearchOptions.Limit.
+	MaxSearchLimit = 100
+)
+
+// embedOwner is the owner a DB reads as unless WithOwner names another. No
+// client stores memories under it, so private memories stay hidden.
+const embedOwner = "embedded"
+
+// ErrNotFound is returned when a memory does not exist or was deleted.
+var ErrNotFound = errors.New("engram: memory not found")
+
+// DB is a read-only handle on an engram database. It is safe for concurrent
+// use.
+type DB struct {
+	store    *gorm.Store
+	memories *gorm.MemoryStore
+	tenant   string
+	owner    string
+}
+
+// options collects the settings of Open.
+type options struct {
+	cfg    gorm.Config
+	tenant string
+	owner  string
+}
+
+// Option configures Open.
+type Option func(*options)
+
+// WithMaxConns caps the number of open database connections (default 4).
+func WithMaxConns(n int) Option {
+	return func(o *options) { o.cfg.MaxConns = n }
+}
+
+// WithTenant reads the memories of tenant t instead of the default tenant.
+func WithTenant(t string) Option {
+	return func(o *options) { o.tenant = t }
+}
+
+// WithOwner reads as owner ("keycard:<id>", "operator", "session"), so that
+// owner's private memories are returned too.
+func WithOwner(owner string) Option {
+	return func(o *options) { o.owner = owner }
+}
+
+// Open connects to the engram database at dsn, a PostgreSQL connection
+// string. It fails when the database has never been migrated by an engram
+// server.
+func Open(dsn string, opts ...Option) (*DB, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("engram: dsn is required")
+	}
+	o := options{
+		cfg:    gorm.Config{DSN: dsn, LogLevel: logger.Silent},
+		tenant: tenant.Default,
+		owner:  embedOwner,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := tenant.Validate(o.tenant); err != nil {
+		return nil, fmt.Errorf("engram: %w", err)
+	}
+	store, err := gorm.OpenReadOnly(o.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("engram: %w", err)
+	}
+	return &DB{store: store, memories: gorm.NewMemoryStore(store), tenant: o.tenant, owner: o.owner}, nil
+}
+
+// scope stamps the DB's tenant and owner on ctx, so the stores filter by them.
+func (db *DB) scope(ctx context.Context) context.Context {
+	return tenant.WithOwner(tenant.WithTenant(ctx, db.tenant), db.owner)
+}
+
+// Close closes the database connections.
+func (db *DB) Close() error {
+	return db.store.Close()
+}
+
+// SearchOptions scopes a Search.
+type SearchOptions struct {
+	// Projects limits the search to these projects. Projects named in the
+	// query (project:a,b) are used when it is empty.
+	Projects []string
+	// IncludeGlobal adds memories stored with scope=global in any project.
+	IncludeGlobal bool
+	// Limit caps the result; <= 0 means DefaultSearchLimit.
+	Limit int
+}
+
+// Search returns the memories matching query, newest first. query uses the
+// recall tool's syntax: plain terms, quoted phrases and field filters such
+// as type:decision, file:*.go or -tag:wip. A session: filter matches only
+// memories tagged with the session, and session_tag: is not supported, since
+// both need the session table the server consults.
+//
+// At least one project, or IncludeGlobal, is required.
+func (db *DB) Search(ctx context.Context, query string, opts SearchOptions) ([]*models.Memory, error) {
+	params, err := mcp.ParseSearchQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("engram: search: %w", err)
+	}
+	if params.SessionTag != "" {
+		return nil, fmt.Errorf("engram: search: session_tag filters are not supported")
+	}
+	projects := opts.Projects
+	if len(projects) == 0 {
+		projects = params.Projects
+	}
+	includeGlobal := opts.IncludeGlobal || params.IncludeGlobal
+	if len(projects) == 0 && !includeGlobal {
+		return nil, fmt.Errorf("engram: search: a project or IncludeGlobal is required")
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	limit = min(limit, MaxSearchLimit)
+
+	// As in recall, filter a wider candidate pool so that older matches are
+	// not cut off by the limit before filtering.
+	fetchLimit := limit
+	if !params.IsEmpty() {
+		fetchLimit = max(limit*10, 1000)
+	}
+	candidates, err := db.memories.ListBeforeAsOfIn(db.scope(ctx), projects, includeGlobal, time.Time{}, 0, fetchLimit, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("engram: search: %w", err)
+	}
+	result := make([]*models.Memory, 0, min(limit, len(candidates)))
+	for _, mem := range candidates {
+		if params.Matches(mem) {
+			result = append(result, mem)
+			if len(result) == limit {
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// GetObservation returns the memory with the given ID. Observations are
+// stored as memories, so the IDs Search and Timeline return work here.
+func (db *DB) GetObservation(ctx context.Context, id int64) (*models.Memory, error) {
+	mem, err := db.memories.Get(db.scope(ctx), id)
+	if errors.Is(err, gogorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("engram: %w", err)
+	}
+	return mem, nil
+}
+
+// Timeline returns the memory anchorID with up to before memories stored
+// just before it and up to after memories stored just after it in the same
+// project, oldest first.
+func (db *DB) Timeline(ctx context.Context, anchorID int64, before, after int) ([]*models.Memory, error) {
+	anchor, err := db.GetObservation(ctx, anchorID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = db.scope(ctx)
+	var earlier, later []*models.Memory
+	if before > 0 {
+		if earlier, err = db.memories.ListBefore(ctx, anchor.Project, anchor.CreatedAt, anchor.ID, before); err != nil {
+			return nil, fmt.Errorf("engram: timeline: %w", err)
+		}
+		slices.Reverse(earlier)
+	}
+	if after > 0 {
+		if later, err = db.memories.ListAfter(ctx, anchor.Project, anchor.CreatedAt, anchor.ID, after); err != nil {
+			return nil, fmt.Errorf("engram: timeline: %w", err)
+		}
+	}
+	result := make([]*models.Memory, 0, len(earlier)+1+len(later))
+	result = append(result, earlier...)
+	result = append(result, anchor)
+	return append(result, later...), nil
+}
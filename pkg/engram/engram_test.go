@@ -0,0 +1,102 @@
+package engram
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbgorm "github.com/thebtf/engram/internal/db/gorm"
+	"github.com/thebtf/engram/internal/tenant"
+	"github.com/thebtf/engram/pkg/models"
+)
+
+func TestOpen_RequiresDSN(t *testing.T) {
+	_, err := Open("")
+	assert.Error(t, err)
+
+	_, err = Open("postgres://localhost/engram", WithTenant("Not A Tenant"))
+	assert.Error(t, err, "invalid tenant")
+}
+
+func TestDB_ReadOnly(t *testing.T) {
+	dsn := os.Getenv("DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("DATABASE_DSN not set, skipping integration test")
+	}
+	ctx := context.Background()
+	project := "test-embedded-" + uuid.NewString()
+
+	// Seed through a regular store, which also migrates the schema.
+	store, err := dbgorm.NewStore(dbgorm.Config{DSN: dsn, MaxConns: 2})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, store.DB.Exec("DELETE FROM memories WHERE project = ?", project).Error)
+		require.NoError(t, store.Close())
+	})
+	writer := dbgorm.NewMemoryStore(store)
+	var ids []int64
+	for _, content := range []string{"use pgx for postgres", "retry login on 502", "pgx pool size is 10"} {
+		mem, err := writer.Create(ctx, &models.Memory{Project: project, Content: content, Tags: []string{"type:decision"}})
+		require.NoError(t, err)
+		ids = append(ids, mem.ID)
+	}
+	private, err := writer.Create(tenant.WithOwner(ctx, "keycard:bot"), &models.Memory{
+		Project: project, Content: "pgx secret dsn", Tags: []string{"type:decision"}, Visibility: models.VisibilityPrivate,
+	})
+	require.NoError(t, err)
+	other, err := writer.Create(tenant.WithTenant(ctx, "acme"), &models.Memory{Project: project, Content: "pgx in acme", Tags: []string{"type:decision"}})
+	require.NoError(t, err)
+
+	db, err := Open(dsn, WithMaxConns(1))
+	require.NoError(t, err)
+	defer db.Close()
+
+	found, err := db.Search(ctx, "pgx type:decision", SearchOptions{Projects: []string{project}})
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, ids[2], found[0].ID, "newest first")
+
+	_, err = db.Search(ctx, "pgx", SearchOptions{})
+	assert.Error(t, err, "a project is required")
+
+	mem, err := db.GetObservation(ctx, ids[1])
+	require.NoError(t, err)
+	assert.Equal(t, "retry login on 502", mem.Content)
+	_, err = db.GetObservation(ctx, -1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	timeline, err := db.Timeline(ctx, ids[1], 5, 5)
+	require.NoError(t, err)
+	require.Len(t, timeline, 3)
+	for i, mem := range timeline {
+		assert.Equal(t, ids[i], mem.ID)
+	}
+
+	_, err = db.GetObservation(ctx, private.ID)
+	assert.ErrorIs(t, err, ErrNotFound, "private memories are hidden by default")
+	_, err = db.GetObservation(ctx, other.ID)
+	assert.ErrorIs(t, err, ErrNotFound, "other tenants are hidden")
+
+	ownerDB, err := Open(dsn, WithMaxConns(1), WithOwner("keycard:bot"))
+	require.NoError(t, err)
+	defer ownerDB.Close()
+	found, err = ownerDB.Search(ctx, "pgx type:decision", SearchOptions{Projects: []string{project}})
+	require.NoError(t, err)
+	require.Len(t, found, 3)
+	assert.Equal(t, private.ID, found[0].ID)
+
+	acmeDB, err := Open(dsn, WithMaxConns(1), WithTenant("acme"))
+	require.NoError(t, err)
+	defer acmeDB.Close()
+	found, err = acmeDB.Search(ctx, "pgx", SearchOptions{Projects: []string{project}})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, other.ID, found[0].ID)
+
+	err = db.store.DB.Exec("DELETE FROM memories WHERE project = ?", project).Error
+	assert.Error(t, err, "writes are refused")
+}
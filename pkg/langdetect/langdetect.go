@@ -0,0 +1,137 @@
+// Package langdetect guesses the natural language of short technical notes.
+//
+// Detection is deliberately small and dependency-free: non-Latin scripts are
+// identified by their Unicode ranges, Latin-script languages by counting
+// common function words. It is tuned for observations and prompts (a few
+// sentences mixed with code), not for arbitrary documents, and returns ""
+// rather than guessing when the evidence is weak.
+package langdetect
+
+import (
+	"strings"
+	"unicode"
+)
+
+// minStopwordHits is the number of function-word hits a Latin-script language
+// needs before it is reported.
+const minStopwordHits = 2
+
+// stopwords lists frequent function words that are distinctive for each
+// Latin-script language. Words shared by several languages ("a", "de", "in")
+// are omitted so they do not blur the scores.
+var stopwords = map[string][]string{
+	"en": {"the", "and", "is", "are", "was", "were", "this", "that", "with", "for", "not", "have", "has", "from", "should", "would", "which", "when", "because", "it's", "don't", "we", "you", "they", "of", "to", "be", "by", "or"},
+	"de": {"der", "die", "das", "und", "ist", "sind", "nicht", "mit", "ein", "eine", "einen", "dem", "den", "auch", "auf", "für", "wir", "sie", "ich", "wenn", "weil", "dass", "oder", "wird", "werden", "muss", "kann", "noch", "bei", "nach", "aber", "zu", "von", "im"},
+	"fr": {"le", "la", "les", "et", "est", "sont", "une", "des", "pour", "avec", "pas", "que", "qui", "dans", "sur", "nous", "vous", "il", "elle", "mais", "être", "du", "au", "ce", "cette"},
+	"es": {"el", "los", "las", "y", "es", "son", "una", "para", "con", "no", "que", "por", "pero", "del", "al", "como", "está", "este", "esta", "también", "hay", "se", "lo"},
+	"it": {"il", "gli", "e", "è", "sono", "una", "per", "con", "non", "che", "della", "delle", "nel", "questo", "questa", "anche", "ma", "si", "di", "da"},
+	"nl": {"het", "een", "en", "is", "zijn", "niet", "met", "voor", "van", "dat", "die", "op", "ook", "wij", "maar", "moet", "kan", "wordt", "bij", "naar"},
+	"pt": {"o", "os", "as", "e", "é", "são", "uma", "para", "com", "não", "que", "por", "mas", "do", "da", "dos", "das", "está", "também", "em", "se"},
+}
+
+// stopwordIndex maps a word to the languages it is a stopword of.
+var stopwordIndex = func() map[string][]string {
+	idx := make(map[string][]string)
+	for lang, words := range stopwords {
+		for _, w := range words {
+			idx[w] = append(idx[w], lang)
+		}
+	}
+	return idx
+}()
+
+// Detect returns the ISO 639-1 code of the dominant language of text, or ""
+// when it cannot tell.
+func Detect(text string) string {
+	if lang := detectScript(text); lang != "" {
+		return lang
+	}
+	return detectLatin(text)
+}
+
+// detectScript identifies languages written in a non-Latin script. It requires
+// the script to cover more than half of the letters, so a Russian word in an
+// English note does not flip the result.
+func detectScript(text string) string {
+	var letters, cyrillic, ukrainian, greek, han, kana, hangul, arabic, hebrew int
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+			if strings.ContainsRune("іїєґІЇЄҐ", r) {
+				ukrainian++
+			}
+		case unicode.Is(unicode.Greek, r):
+			greek++
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			kana++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Arabic, r):
+			arabic++
+		case unicode.Is(unicode.Hebrew, r):
+			hebrew++
+		}
+	}
+	if letters == 0 {
+		return ""
+	}
+	dominant := func(n int) bool { return n*2 > letters }
+	switch {
+	case dominant(cyrillic):
+		if ukrainian > 0 {
+			return "uk"
+		}
+		return "ru"
+	case dominant(han + kana):
+		if kana > 0 {
+			return "ja"
+		}
+		return "zh"
+	case dominant(hangul):
+		return "ko"
+	case dominant(greek):
+		return "el"
+	case dominant(arabic):
+		return "ar"
+	case dominant(hebrew):
+		return "he"
+	}
+	return ""
+}
+
+// detectLatin scores Latin-script languages by function-word hits. German
+// also scores umlauts and ß, which are rare in the other candidates.
+func detectLatin(text string) string {
+	scores := make(map[string]int)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	}) {
+		for _, lang := range stopwordIndex[strings.Trim(word, "'")] {
+			scores[lang]++
+		}
+		if strings.ContainsAny(word, "äöüß") {
+			scores["de"]++
+		}
+	}
+
+	best, bestScore, secondScore := "", 0, 0
+	for lang, score := range scores {
+		if score > bestScore {
+			secondScore = bestScore
+			best, bestScore = lang, score
+		} else if score > secondScore {
+			secondScore = score
+		}
+	}
+	if bestScore < minStopwordHits || bestScore == secondScore {
+		return ""
+	}
+	return best
+}
@@ -0,0 +1,32 @@
+package langdetect
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english", "The cache is invalidated when the config file changes, so we should not reload it twice.", "en"},
+		{"german", "Der Cache wird ungültig, wenn sich die Konfiguration ändert. Wir müssen das nicht zweimal laden.", "de"},
+		{"german with code", "Die Funktion `loadConfig()` ist nicht threadsafe und wird beim Start aufgerufen.", "de"},
+		{"french", "Le cache est invalidé quand la configuration change, et nous ne devons pas le recharger.", "fr"},
+		{"spanish", "El servidor no responde cuando la base de datos está caída, pero el proxy sigue activo.", "es"},
+		{"russian", "Кэш сбрасывается при изменении конфигурации.", "ru"},
+		{"ukrainian", "Кеш скидається, коли змінюється конфігурація і є нові файли.", "uk"},
+		{"japanese", "設定ファイルが変更されるとキャッシュが無効になります。", "ja"},
+		{"chinese", "配置文件更改时缓存会失效。", "zh"},
+		{"korean", "설정 파일이 변경되면 캐시가 무효화됩니다.", "ko"},
+		{"code only", "func main() { os.Exit(run()) }", ""},
+		{"empty", "", ""},
+		{"too short", "cache", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.text); got != tt.want {
+				t.Errorf("Detect(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
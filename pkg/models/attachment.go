@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Attachment kinds.
+const (
+	AttachmentKindCode = "code"
+	AttachmentKindDiff = "diff"
+)
+
+// Attachment limits, enforced when a memory is stored.
+const (
+	// MaxAttachmentSize is the largest attachment content, in bytes.
+	MaxAttachmentSize = 64 * 1024
+	// MaxAttachmentsPerMemory caps the attachments of one memory.
+	MaxAttachmentsPerMemory = 10
+)
+
+// MemoryAttachment is a code snippet or diff attached to a memory. Attachments
+// are stored apart from the memory content (migration 111) so search, digests
+// and context injection never pay for them; they are returned only when a
+// memory is read in full.
+type MemoryAttachment struct {
+	CreatedAt time.Time `json:"created_at"`
+	// Kind is AttachmentKindCode or AttachmentKindDiff.
+	Kind string `json:"kind"`
+	// Name is an optional label, typically a file path.
+	Name string `json:"name,omitempty"`
+	// Language is an optional syntax hint such as "go" or "sql".
+	Language string `json:"language,omitempty"`
+	// Content is empty in listings that omit it (see Size).
+	Content  string `json:"content,omitempty"`
+	ID       int64  `json:"id"`
+	MemoryID int64  `json:"memory_id"`
+	Size     int    `json:"size"`
+}
+
+// IsValidAttachmentKind reports whether kind is a known attachment kind.
+func IsValidAttachmentKind(kind string) bool {
+	return kind == AttachmentKindCode || kind == AttachmentKindDiff
+}
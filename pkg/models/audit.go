@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Audit log operations. Each names the destructive or state-rewriting action an
+// AuditEntry records.
+const (
+	AuditOpMemoryDelete            = "memory.delete"
+	AuditOpMemoryBulkDelete        = "memory.bulk_delete"
+	AuditOpMemoryEdit              = "memory.edit"
+	AuditOpMemoryRevert            = "memory.revert"
+	AuditOpCredentialDelete        = "credential.delete"
+	AuditOpCredentialPurgeOrphaned = "credential.purge_orphaned"
+	AuditOpIssueDelete             = "issue.delete"
+	AuditOpProjectDelete           = "project.delete"
+	AuditOpProjectMigrate          = "project.migrate"
+	AuditOpRelationDelete          = "relation.delete"
+)
+
+// Audit log clients: the surface through which an operation arrived.
+const (
+	AuditClientMCP  = "mcp"
+	AuditClientHook = "hook"
+	AuditClientAPI  = "api"
+)
+
+// AuditEntry records one destructive operation: who performed it, through
+// which client, what it touched and a summary of the state it replaced.
+//
+// Migration 109 creates the audit_log table.
+type AuditEntry struct {
+	CreatedAt time.Time `json:"created_at"`
+	// Actor is the authenticated principal, e.g. "operator", "keycard:<id>",
+	// "session" or "anonymous" when auth is disabled.
+	Actor string `json:"actor"`
+	// Client is one of the AuditClient* values.
+	Client    string `json:"client"`
+	Operation string `json:"operation"`
+	Project   string `json:"project,omitempty"`
+	// Before summarizes the state the operation removed or overwrote.
+	Before    string   `json:"before,omitempty"`
+	TargetIDs []string `json:"target_ids"`
+	ID        int64    `json:"id"`
+}
+
+// AuditFilter narrows an audit log query. Zero values match everything;
+// Limit <= 0 uses the store default.
+type AuditFilter struct {
+	Since     time.Time
+	Operation string
+	Project   string
+	Actor     string
+	Limit     int
+}
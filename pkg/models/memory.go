@@ -20,4 +20,49 @@ type Memory struct {
 	Tags        []string   `json:"tags"`
 	ID          int64      `json:"id"`
 	Version     int        `json:"version"`
+	// Pinned memories are injected into every context for their project,
+	// ahead of scored results (see pin_observation).
+	Pinned bool `json:"pinned,omitempty"`
+	// Locked memories cannot be edited, merged, reverted or deleted until
+	// they are unlocked (see lock_observation).
+	Locked bool `json:"locked,omitempty"`
+	// PendingReview memories scored below the quality gate floor when stored.
+	// They are kept out of lists, search and injection until approved with
+	// review_observation.
+	PendingReview bool `json:"pending_review,omitempty"`
+	// Language is the ISO 639-1 code detected from Content ("" when unknown).
+	Language string `json:"language,omitempty"`
+	// Visibility decides which clients the memory is returned to ("" is
+	// treated as team on create).
+	Visibility Visibility `json:"visibility,omitempty"`
+	// Owner is the client that created the memory ("keycard:<id>",
+	// "operator", "session"; "" when created without authentication).
+	Owner string `json:"owner,omitempty"`
+}
+
+// RevisionAction identifies the operation that superseded a memory revision.
+type RevisionAction string
+
+// Revision actions recorded in the memory_revisions table.
+const (
+	RevisionActionEdit   RevisionAction = "edit"
+	RevisionActionTag    RevisionAction = "tag"
+	RevisionActionMerge  RevisionAction = "merge"
+	RevisionActionRevert RevisionAction = "revert"
+)
+
+// MemoryRevision is a snapshot of a memory as it existed before a modifying
+// operation. Version is the memory version the snapshot captures; Action and
+// Actor describe the operation that replaced it.
+//
+// Migration 105 creates the memory_revisions table.
+type MemoryRevision struct {
+	CreatedAt time.Time      `json:"created_at"`
+	Action    RevisionAction `json:"action"`
+	Actor     string         `json:"actor,omitempty"`
+	Content   string         `json:"content"`
+	Tags      []string       `json:"tags"`
+	ID        int64          `json:"id"`
+	MemoryID  int64          `json:"memory_id"`
+	Version   int            `json:"version"`
 }
@@ -30,6 +30,17 @@ const (
 	ObsTypeTimeline    ObservationType = "timeline"
 )
 
+// TypeFromTags returns the observation type recorded in the first "type:"
+// tag of a memory, lowercased, or "" when it has none.
+func TypeFromTags(tags []string) ObservationType {
+	for _, tag := range tags {
+		if v, ok := strings.CutPrefix(tag, "type:"); ok {
+			return ObservationType(strings.ToLower(v))
+		}
+	}
+	return ""
+}
+
 // MemoryType represents the classification for memory storage and retrieval.
 type MemoryType string
 
@@ -123,6 +134,9 @@ type ObservationScope string
 const (
 	// ScopeProject means the observation is only visible within the same project.
 	ScopeProject ObservationScope = "project"
+	// ScopeWorkspace means the observation is shared by every project inside a
+	// monorepo workspace. It is stored under the workspace root's project id.
+	ScopeWorkspace ObservationScope = "workspace"
 	// ScopeGlobal means the observation is visible across all projects.
 	// Used for best practices, advanced patterns, and generalizable knowledge.
 	ScopeGlobal ObservationScope = "global"
@@ -307,6 +321,7 @@ type Observation struct {
 	EffectivenessScore      float64          `db:"effectiveness_score" json:"effectiveness_score"`
 	EffectivenessInjections int              `db:"effectiveness_injections" json:"effectiveness_injections"`
 	EffectivenessSuccesses  int              `db:"effectiveness_successes" json:"effectiveness_successes"`
+	Language                string           `db:"-" json:"language,omitempty"`
 }
 
 // ParsedObservation represents an observation parsed from SDK response XML.
@@ -473,6 +488,7 @@ type ObservationJSON struct {
 	ExpiresAt               *time.Time       `json:"expires_at,omitempty"`
 	TtlDays                 *int32           `json:"ttl_days,omitempty"`
 	IsExpired               bool             `json:"is_expired,omitempty"`
+	Language                string           `json:"language,omitempty"`
 }
 
 // MarshalJSON implements json.Marshaler for Observation.
@@ -495,6 +511,7 @@ func (o *Observation) MarshalJSON() ([]byte, error) {
 		FilesModified:   o.FilesModified,
 		CommandsRun:     o.CommandsRun,
 		FileMtimes:      o.FileMtimes,
+		Language:        o.Language,
 		DiscoveryTokens: o.DiscoveryTokens,
 		CreatedAt:       o.CreatedAt,
 		CreatedAtEpoch:  o.CreatedAtEpoch,
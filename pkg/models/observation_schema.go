@@ -0,0 +1,194 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+// SchemaSection is a named part of a structured observation, e.g. the
+// "root cause" of a bugfix. A section is present when the content contains a
+// line labelled with its title or one of its aliases ("Root cause: ...",
+// "## Root cause", "- **Why**: ...").
+type SchemaSection struct {
+	Name        string   `json:"name"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Aliases     []string `json:"aliases,omitempty"`
+}
+
+// ObservationSchema describes the structure expected of an observation type.
+// Required sections are enforced at write time; recommended sections only
+// contribute to the quality score.
+type ObservationSchema struct {
+	Type        ObservationType `json:"type"`
+	Description string          `json:"description"`
+	Required    []SchemaSection `json:"required"`
+	Recommended []SchemaSection `json:"recommended"`
+}
+
+var (
+	sectionAlternatives = SchemaSection{
+		Name:        "alternatives_considered",
+		Title:       "Alternatives considered",
+		Description: "Options that were evaluated and why they were rejected.",
+		Aliases:     []string{"alternatives", "options considered", "rejected", "rejected alternatives"},
+	}
+	sectionRationale = SchemaSection{
+		Name:        "rationale",
+		Title:       "Rationale",
+		Description: "Why the chosen option won.",
+		Aliases:     []string{"why", "reason", "reasoning"},
+	}
+	sectionRootCause = SchemaSection{
+		Name:        "root_cause",
+		Title:       "Root cause",
+		Description: "The underlying defect, not just the symptom.",
+		Aliases:     []string{"cause", "caused by"},
+	}
+	sectionSymptom = SchemaSection{
+		Name:        "symptom",
+		Title:       "Symptom",
+		Description: "How the bug manifested (error message, failing behaviour).",
+		Aliases:     []string{"symptoms", "error", "observed"},
+	}
+	sectionFix = SchemaSection{
+		Name:        "fix",
+		Title:       "Fix",
+		Description: "What was changed to resolve it.",
+		Aliases:     []string{"solution", "resolution", "fixed by"},
+	}
+	sectionTrigger = SchemaSection{
+		Name:        "trigger",
+		Title:       "Trigger",
+		Description: "The situation in which the pitfall bites.",
+		Aliases:     []string{"when", "context"},
+	}
+	sectionAvoidance = SchemaSection{
+		Name:        "how_to_avoid",
+		Title:       "How to avoid",
+		Description: "What to do instead.",
+		Aliases:     []string{"avoid", "instead", "workaround", "prevention"},
+	}
+	sectionMotivation = SchemaSection{
+		Name:        "motivation",
+		Title:       "Motivation",
+		Description: "Why the change was made.",
+		Aliases:     []string{"why", "reason"},
+	}
+	sectionSteps = SchemaSection{
+		Name:        "steps",
+		Title:       "Steps",
+		Description: "The procedure, in order.",
+		Aliases:     []string{"procedure", "how to", "runbook"},
+	}
+)
+
+// ObservationSchemas maps observation types to their schema. Types without an
+// entry are free-form.
+var ObservationSchemas = map[ObservationType]ObservationSchema{
+	ObsTypeDecision: {
+		Type:        ObsTypeDecision,
+		Description: "An architectural or design choice.",
+		Required:    []SchemaSection{sectionAlternatives},
+		Recommended: []SchemaSection{sectionRationale},
+	},
+	ObsTypeBugfix: {
+		Type:        ObsTypeBugfix,
+		Description: "A defect that was diagnosed and fixed.",
+		Required:    []SchemaSection{sectionRootCause},
+		Recommended: []SchemaSection{sectionSymptom, sectionFix},
+	},
+	ObsTypePitfall: {
+		Type:        ObsTypePitfall,
+		Description: "A trap that is easy to fall into again.",
+		Required:    []SchemaSection{sectionAvoidance},
+		Recommended: []SchemaSection{sectionTrigger},
+	},
+	ObsTypeRefactor: {
+		Type:        ObsTypeRefactor,
+		Description: "A structural change without behaviour change.",
+		Recommended: []SchemaSection{sectionMotivation},
+	},
+	ObsTypeOperational: {
+		Type:        ObsTypeOperational,
+		Description: "How to run, deploy or operate something.",
+		Recommended: []SchemaSection{sectionSteps},
+	},
+}
+
+// SchemaFor returns the schema of obsType and whether one is defined.
+func SchemaFor(obsType ObservationType) (ObservationSchema, bool) {
+	schema, ok := ObservationSchemas[obsType]
+	return schema, ok
+}
+
+// SchemaTypes returns the observation types that have a schema, sorted.
+func SchemaTypes() []ObservationType {
+	types := make([]ObservationType, 0, len(ObservationSchemas))
+	for t := range ObservationSchemas {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// Section looks up a required or recommended section by name.
+func (s ObservationSchema) Section(name string) (SchemaSection, bool) {
+	for _, sec := range append(append([]SchemaSection{}, s.Required...), s.Recommended...) {
+		if sec.Name == name {
+			return sec, true
+		}
+	}
+	return SchemaSection{}, false
+}
+
+// MissingRequired returns the required sections absent from content.
+func (s ObservationSchema) MissingRequired(content string) []SchemaSection {
+	return missingSections(s.Required, content)
+}
+
+// MissingRecommended returns the recommended sections absent from content.
+func (s ObservationSchema) MissingRecommended(content string) []SchemaSection {
+	return missingSections(s.Recommended, content)
+}
+
+func missingSections(sections []SchemaSection, content string) []SchemaSection {
+	var missing []SchemaSection
+	for _, sec := range sections {
+		if !sec.PresentIn(content) {
+			missing = append(missing, sec)
+		}
+	}
+	return missing
+}
+
+// PresentIn reports whether content has a line labelled with the section's
+// title, name or an alias, either as "Label: text" or as a heading.
+func (sec SchemaSection) PresentIn(content string) bool {
+	labels := make([]string, 0, len(sec.Aliases)+2)
+	labels = append(labels, strings.ToLower(sec.Title), strings.ReplaceAll(sec.Name, "_", " "))
+	for _, alias := range sec.Aliases {
+		labels = append(labels, strings.ToLower(alias))
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		heading := strings.HasPrefix(trimmed, "#")
+		lower := strings.ToLower(strings.TrimLeft(trimmed, "#*->_ \t"))
+		for _, label := range labels {
+			if !strings.HasPrefix(lower, label) {
+				continue
+			}
+			rest := strings.TrimLeft(lower[len(label):], "*_ \t")
+			if strings.HasPrefix(rest, ":") || (heading && rest == "") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FormatSection renders a section value the way PresentIn recognises it.
+func (sec SchemaSection) FormatSection(value string) string {
+	return sec.Title + ": " + strings.TrimSpace(value)
+}
@@ -0,0 +1,53 @@
+package models
+
+import "testing"
+
+func TestSchemaSection_PresentIn(t *testing.T) {
+	sec := sectionRootCause
+
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"label", "Login failed.\nRoot cause: token cache never expired.", true},
+		{"alias", "cause: stale cache", true},
+		{"bold markdown", "- **Root cause**: stale cache", true},
+		{"heading", "## Root Cause\nstale cache", true},
+		{"snake name", "root cause:stale cache", true},
+		{"mentioned in prose", "We did not find the root cause yet.", false},
+		{"label without colon", "Root cause was the cache", false},
+		{"absent", "Fixed login.", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sec.PresentIn(tt.content); got != tt.want {
+				t.Fatalf("PresentIn(%q) = %v, want %v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObservationSchema_Missing(t *testing.T) {
+	schema, ok := SchemaFor(ObsTypeDecision)
+	if !ok {
+		t.Fatal("decision schema missing")
+	}
+
+	missing := schema.MissingRequired("Use PostgreSQL.")
+	if len(missing) != 1 || missing[0].Name != "alternatives_considered" {
+		t.Fatalf("MissingRequired = %+v, want alternatives_considered", missing)
+	}
+
+	content := "Use PostgreSQL.\n" + sectionAlternatives.FormatSection("SQLite (no concurrent writers)")
+	if missing := schema.MissingRequired(content); len(missing) != 0 {
+		t.Fatalf("MissingRequired = %+v, want none", missing)
+	}
+	if rec := schema.MissingRecommended(content); len(rec) != 1 || rec[0].Name != "rationale" {
+		t.Fatalf("MissingRecommended = %+v, want rationale", rec)
+	}
+
+	if _, ok := SchemaFor(ObsTypeFeature); ok {
+		t.Fatal("feature should be free-form")
+	}
+}
@@ -41,6 +41,14 @@ func (s *ObservationSuite) TestObservationTypeConstants() {
 	s.Equal(ObservationType("timeline"), ObsTypeTimeline)
 }
 
+// TestTypeFromTags tests reading the observation type from memory tags.
+func (s *ObservationSuite) TestTypeFromTags() {
+	s.Equal(ObsTypeBugfix, TypeFromTags([]string{"auth", "type:bugfix", "scope:project"}))
+	s.Equal(ObsTypeDecision, TypeFromTags([]string{"type:Decision", "type:bugfix"}))
+	s.Equal(ObservationType(""), TypeFromTags([]string{"auth"}))
+	s.Equal(ObservationType(""), TypeFromTags(nil))
+}
+
 // TestAgentSourceConstants tests agent source type constants and validation.
 func (s *ObservationSuite) TestAgentSourceConstants() {
 	s.Equal(AgentSource("claude-code"), AgentClaude)
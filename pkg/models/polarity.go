@@ -0,0 +1,48 @@
+package models
+
+import "strings"
+
+// Polarity tells whether a memory is knowledge to apply or a known-bad
+// approach to stay away from. It is stored as a "polarity:<value>" tag, like
+// type and scope; memories without one are positive.
+type Polarity string
+
+// Memory polarities.
+const (
+	PolarityPositive Polarity = "positive"
+	PolarityCaution  Polarity = "caution"
+	PolarityDoNot    Polarity = "do_not"
+)
+
+// PolarityTagPrefix prefixes the tag carrying a memory's polarity.
+const PolarityTagPrefix = "polarity:"
+
+// IsValidPolarity reports whether p is a known polarity.
+func IsValidPolarity(p string) bool {
+	switch Polarity(p) {
+	case PolarityPositive, PolarityCaution, PolarityDoNot:
+		return true
+	}
+	return false
+}
+
+// IsWarning reports whether the polarity marks a caution or a known-bad approach.
+func (p Polarity) IsWarning() bool {
+	return p == PolarityCaution || p == PolarityDoNot
+}
+
+// Tag returns the tag recording the polarity.
+func (p Polarity) Tag() string {
+	return PolarityTagPrefix + string(p)
+}
+
+// PolarityFromTags returns the polarity recorded in tags, PolarityPositive
+// when there is none.
+func PolarityFromTags(tags []string) Polarity {
+	for _, tag := range tags {
+		if v, ok := strings.CutPrefix(tag, PolarityTagPrefix); ok && IsValidPolarity(v) {
+			return Polarity(v)
+		}
+	}
+	return PolarityPositive
+}
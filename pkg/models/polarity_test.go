@@ -0,0 +1,36 @@
+package models
+
+import "testing"
+
+func TestPolarityFromTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want Polarity
+	}{
+		{"no tags", nil, PolarityPositive},
+		{"untagged", []string{"type:bugfix", "scope:project"}, PolarityPositive},
+		{"caution", []string{"type:pitfall", "polarity:caution"}, PolarityCaution},
+		{"do not", []string{"polarity:do_not"}, PolarityDoNot},
+		{"unknown value", []string{"polarity:maybe"}, PolarityPositive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PolarityFromTags(tt.tags); got != tt.want {
+				t.Errorf("PolarityFromTags(%v) = %q, want %q", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolarityIsWarning(t *testing.T) {
+	if PolarityPositive.IsWarning() {
+		t.Error("positive must not be a warning")
+	}
+	if !PolarityCaution.IsWarning() || !PolarityDoNot.IsWarning() {
+		t.Error("caution and do_not must be warnings")
+	}
+	if got := PolarityDoNot.Tag(); got != "polarity:do_not" {
+		t.Errorf("Tag() = %q", got)
+	}
+}
@@ -57,11 +57,15 @@ const (
 	// RelationReferencedBy means source observation is referenced by target.
 	// Added in migration 077 — detector FR-36 (inverse).
 	RelationReferencedBy RelationType = "referenced_by"
+	// RelationMergedFrom means source observation absorbed the content of target
+	// (store action=merge), which is deleted afterwards.
+	// Added in migration 112.
+	RelationMergedFrom RelationType = "merged_from"
 )
 
 // AllRelationTypes is the list of all valid relation types.
 // This is the single source of truth — keep in sync with:
-//   - migration 077 CHECK constraint in internal/db/gorm/migrations.go
+//   - migration 112 CHECK constraint in internal/db/gorm/migrations.go
 //   - GORM struct tag in internal/db/gorm/models.go (ObservationRelation.RelationType)
 var AllRelationTypes = []RelationType{
 	RelationCauses,
@@ -88,6 +92,38 @@ var AllRelationTypes = []RelationType{
 	RelationPromptedBy,
 	RelationReferences,
 	RelationReferencedBy,
+	// Added in migration 112
+	RelationMergedFrom,
+}
+
+// IsValidRelationType reports whether t is one of AllRelationTypes.
+func IsValidRelationType(t RelationType) bool {
+	for _, valid := range AllRelationTypes {
+		if t == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// inverseRelationTypes maps a relation type to the type of the edge that
+// reads the same relationship from the target's side. Symmetric types map to
+// themselves; types with no well-defined inverse (causes, fixes, ...) are absent.
+var inverseRelationTypes = map[RelationType]RelationType{
+	RelationRelatesTo:    RelationRelatesTo,
+	RelationSimilarTo:    RelationSimilarTo,
+	RelationContradicts:  RelationContradicts,
+	RelationSharesTheme:  RelationSharesTheme,
+	RelationParallelCtx:  RelationParallelCtx,
+	RelationReferences:   RelationReferencedBy,
+	RelationReferencedBy: RelationReferences,
+}
+
+// InverseRelationType returns the type of the inverse edge of t, and false
+// when t has no inverse.
+func InverseRelationType(t RelationType) (RelationType, bool) {
+	inverse, ok := inverseRelationTypes[t]
+	return inverse, ok
 }
 
 // RelationDetectionSource indicates how a relationship was detected.
@@ -108,6 +144,8 @@ const (
 	DetectionSourceTypeProgression RelationDetectionSource = "type_progression"
 	// DetectionSourceCreativeAssociation means relationship was detected via consolidation association engine.
 	DetectionSourceCreativeAssociation RelationDetectionSource = "creative_association"
+	// DetectionSourceManual means relationship was created explicitly through the create_relation tool.
+	DetectionSourceManual RelationDetectionSource = "manual"
 )
 
 // ObservationRelation represents a directed relationship between two observations.
@@ -471,3 +471,31 @@ func TestNewObservationRelation(t *testing.T) {
 		t.Error("CreatedAtEpoch should be set")
 	}
 }
+
+func TestInverseRelationType(t *testing.T) {
+	tests := []struct {
+		relType RelationType
+		want    RelationType
+		wantOK  bool
+	}{
+		{RelationReferences, RelationReferencedBy, true},
+		{RelationReferencedBy, RelationReferences, true},
+		{RelationSimilarTo, RelationSimilarTo, true},
+		{RelationFixes, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := InverseRelationType(tt.relType)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("InverseRelationType(%q) = %q, %v; want %q, %v", tt.relType, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	for relType, inverse := range inverseRelationTypes {
+		if !IsValidRelationType(relType) || !IsValidRelationType(inverse) {
+			t.Errorf("inverse pair %q -> %q uses an unknown relation type", relType, inverse)
+		}
+	}
+	if IsValidRelationType("blocks") {
+		t.Error("IsValidRelationType(blocks) = true, want false")
+	}
+}
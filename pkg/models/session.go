@@ -32,6 +32,9 @@ type SDKSession struct {
 	OutcomeRecordedAt   sql.NullString `db:"outcome_recorded_at" json:"outcome_recorded_at,omitempty"`
 	UtilityPropagatedAt sql.NullTime   `db:"utility_propagated_at" json:"utility_propagated_at,omitempty"`
 	InjectionStrategy   sql.NullString `db:"injection_strategy" json:"injection_strategy,omitempty"`
+	// Name and Tags are user-assigned labels (tag_session).
+	Name                sql.NullString  `db:"name" json:"name,omitempty"`
+	Tags                JSONStringArray `db:"tags" json:"tags,omitempty"`
 	ID                  int64          `db:"id" json:"id"`
 	PromptCounter       int64          `db:"prompt_counter" json:"prompt_counter"`
 	StartedAtEpoch      int64          `db:"started_at_epoch" json:"started_at_epoch"`
@@ -59,6 +62,8 @@ func (s SDKSession) MarshalJSON() ([]byte, error) {
 		OutcomeReason       sql.NullString `json:"outcome_reason,omitempty"`
 		OutcomeRecordedAt   sql.NullString `json:"outcome_recorded_at,omitempty"`
 		InjectionStrategy   sql.NullString `json:"injection_strategy,omitempty"`
+		Name                sql.NullString `json:"name,omitempty"`
+		Tags                []string       `json:"tags,omitempty"`
 		UtilityPropagatedAt *string        `json:"utility_propagated_at,omitempty"`
 	}
 
@@ -78,6 +83,8 @@ func (s SDKSession) MarshalJSON() ([]byte, error) {
 		Outcome:           s.Outcome,
 		OutcomeReason:     s.OutcomeReason,
 		OutcomeRecordedAt: s.OutcomeRecordedAt,
+		Name:              s.Name,
+		Tags:              s.Tags,
 		InjectionStrategy: s.InjectionStrategy,
 	}
 	if s.UtilityPropagatedAt.Valid {
@@ -0,0 +1,26 @@
+package models
+
+// Visibility decides which authenticated clients a memory is returned to.
+type Visibility string
+
+// Memory visibility levels.
+const (
+	// VisibilityPrivate memories are returned only to the client (keycard,
+	// operator key or logged-in user) that created them.
+	VisibilityPrivate Visibility = "private"
+	// VisibilityTeam memories are returned to every authenticated client of
+	// the tenant. It is the default.
+	VisibilityTeam Visibility = "team"
+	// VisibilityPublic is currently the same as VisibilityTeam: nothing
+	// shares memories beyond the tenant yet. It is reserved for that.
+	VisibilityPublic Visibility = "public"
+)
+
+// IsValidVisibility reports whether v is a known visibility level.
+func IsValidVisibility(v string) bool {
+	switch Visibility(v) {
+	case VisibilityPrivate, VisibilityTeam, VisibilityPublic:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+package models
+
+import "testing"
+
+func TestIsValidVisibility(t *testing.T) {
+	for _, v := range []string{"private", "team", "public"} {
+		if !IsValidVisibility(v) {
+			t.Errorf("IsValidVisibility(%q) = false, want true", v)
+		}
+	}
+	for _, v := range []string{"", "Private", "shared"} {
+		if IsValidVisibility(v) {
+			t.Errorf("IsValidVisibility(%q) = true, want false", v)
+		}
+	}
+}
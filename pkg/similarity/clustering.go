@@ -207,6 +207,25 @@ func ExtractObservationTerms(obs *models.Observation) map[string]bool {
 	return terms
 }
 
+// ExtractTextTerms extracts meaningful terms from free text, such as a user
+// prompt, for similarity comparison.
+func ExtractTextTerms(text string) map[string]bool {
+	terms := make(map[string]bool)
+	addTerms(terms, text)
+	return terms
+}
+
+// ExtractMemoryTerms extracts meaningful terms from a memory's content and
+// tags for similarity comparison.
+func ExtractMemoryTerms(mem *models.Memory) map[string]bool {
+	terms := make(map[string]bool)
+	addTerms(terms, mem.Content)
+	for _, tag := range mem.Tags {
+		addTerms(terms, tag)
+	}
+	return terms
+}
+
 // addTerms tokenizes text and adds meaningful terms to the set.
 func addTerms(terms map[string]bool, text string) {
 	// Simple tokenization: split on non-alphanumeric, filter short words
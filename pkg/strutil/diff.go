@@ -0,0 +1,60 @@
+package strutil
+
+import "strings"
+
+// LineDiff returns a line-oriented diff of before → after in unified style:
+// unchanged lines are prefixed with "  ", removed lines with "- " and added
+// lines with "+ ". It uses a longest-common-subsequence table, which is fine
+// for memory-sized text but quadratic in the number of lines.
+func LineDiff(before, after string) string {
+	a := splitLines(before)
+	b := splitLines(after)
+
+	// lcs[i][j] = length of the LCS of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			sb.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			sb.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			sb.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		sb.WriteString("- " + a[i] + "\n")
+	}
+	for ; j < len(b); j++ {
+		sb.WriteString("+ " + b[j] + "\n")
+	}
+	return sb.String()
+}
+
+// splitLines splits s on newlines, returning nil for the empty string so an
+// empty side of a diff contributes no lines.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
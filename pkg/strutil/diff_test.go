@@ -0,0 +1,31 @@
+package strutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineDiff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   string
+	}{
+		{name: "identical", before: "a\nb", after: "a\nb", want: "  a\n  b\n"},
+		{name: "replace middle", before: "a\nb\nc", after: "a\nx\nc", want: "  a\n- b\n+ x\n  c\n"},
+		{name: "append", before: "a", after: "a\nb\n", want: "  a\n+ b\n"},
+		{name: "from empty", before: "", after: "a", want: "+ a\n"},
+		{name: "to empty", before: "a\nb", after: "", want: "- a\n- b\n"},
+		{name: "both empty", before: "", after: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, LineDiff(tt.before, tt.after))
+		})
+	}
+}
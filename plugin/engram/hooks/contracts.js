@@ -0,0 +1,180 @@
+'use strict';
+
+// Response contracts for the worker endpoints the hooks read.
+//
+// Each contract describes the JSON shape a hook relies on. decodeResponse
+// checks a parsed response against it, fills documented defaults for missing
+// optional fields and throws a ContractError naming the endpoint, contract
+// version and field path when the shape is wrong, so a server change shows up
+// as "session-start v1: rules: expected array, got object" instead of an
+// empty injection or a TypeError deep inside a hook. Fields a contract does
+// not list are passed through untouched.
+//
+// A breaking change to a response gets a new contract version here, next to
+// the old one, rather than an edit in place.
+
+// Field specs: { type, optional, default, items, fields }. type is one of
+// string, number, boolean, object or array; items is the element spec of an
+// array and fields the member specs of an object.
+const CONTRACTS = {
+  'session-start': {
+    version: 1,
+    spec: {
+      type: 'object',
+      fields: {
+        rules: { type: 'array', items: { type: 'object' }, default: [] },
+        issues: { type: 'array', items: { type: 'object' }, default: [] },
+        memories: { type: 'array', items: { type: 'object' }, default: [] },
+        generated_at: { type: 'string', optional: true },
+      },
+    },
+  },
+  'inject-preview': {
+    version: 1,
+    spec: {
+      type: 'object',
+      fields: {
+        query: { type: 'string', default: '' },
+        token_estimate: { type: 'number', default: 0 },
+        reasons: {
+          type: 'array',
+          default: [],
+          items: {
+            type: 'object',
+            fields: {
+              id: { type: 'number' },
+              section: { type: 'string', default: '' },
+              tokens: { type: 'number', default: 0 },
+              reason: { type: 'string', default: '' },
+            },
+          },
+        },
+      },
+    },
+  },
+  memory: {
+    version: 1,
+    spec: {
+      type: 'object',
+      fields: {
+        id: { type: 'number' },
+        content: { type: 'string', default: '' },
+        tags: { type: 'array', items: { type: 'string' }, default: [] },
+      },
+    },
+  },
+  'context-by-file': {
+    version: 1,
+    spec: {
+      type: 'object',
+      fields: {
+        observations: { type: 'array', items: { type: 'object' }, default: [] },
+      },
+    },
+  },
+  'context-search': {
+    version: 1,
+    spec: {
+      type: 'object',
+      fields: {
+        observations: {
+          type: 'array',
+          default: [],
+          items: {
+            type: 'object',
+            fields: {
+              id: { type: 'number' },
+              title: { type: 'string', default: '' },
+              narrative: { type: 'string', default: '' },
+              matched_files: { type: 'array', items: { type: 'string' }, default: [] },
+            },
+          },
+        },
+      },
+    },
+  },
+  'memory-triggers': {
+    version: 1,
+    spec: {
+      type: 'array',
+      items: {
+        type: 'object',
+        fields: {
+          kind: { type: 'string', default: '' },
+          observation_id: { type: 'number', optional: true },
+          blurb: { type: 'string', default: '' },
+        },
+      },
+    },
+  },
+  'retrieval-stats': {
+    version: 1,
+    spec: {
+      type: 'object',
+      fields: {
+        context_injections: { type: 'number', default: 0 },
+        search_requests: { type: 'number', default: 0 },
+      },
+    },
+  },
+};
+
+class ContractError extends Error {
+  constructor(contract, path, message) {
+    const where = path ? `${path}: ` : '';
+    super(`${contract} v${CONTRACTS[contract].version}: ${where}${message}`);
+    this.name = 'ContractError';
+    this.contract = contract;
+    this.path = path;
+  }
+}
+
+function typeOf(value) {
+  if (value === null) return 'null';
+  if (Array.isArray(value)) return 'array';
+  return typeof value;
+}
+
+function decodeValue(contract, spec, value, path) {
+  const actual = typeOf(value);
+  if (actual !== spec.type) {
+    throw new ContractError(contract, path, `expected ${spec.type}, got ${actual}`);
+  }
+  if (spec.type === 'array' && spec.items) {
+    return value.map((item, i) => decodeValue(contract, spec.items, item, `${path}[${i}]`));
+  }
+  if (spec.type === 'object' && spec.fields) {
+    const out = { ...value };
+    for (const [name, field] of Object.entries(spec.fields)) {
+      const fieldPath = path ? `${path}.${name}` : name;
+      const current = value[name];
+      if (current === undefined || current === null) {
+        if (Object.prototype.hasOwnProperty.call(field, 'default')) {
+          out[name] = Array.isArray(field.default) ? [] : field.default;
+        } else if (!field.optional) {
+          throw new ContractError(contract, fieldPath, 'missing required field');
+        }
+        continue;
+      }
+      out[name] = decodeValue(contract, field, current, fieldPath);
+    }
+    return out;
+  }
+  return value;
+}
+
+// decodeResponse validates a parsed worker response against the named
+// contract and returns it with defaults applied.
+function decodeResponse(contract, value) {
+  const entry = CONTRACTS[contract];
+  if (!entry) {
+    throw new Error(`unknown response contract ${contract}`);
+  }
+  return decodeValue(contract, entry.spec, value, '');
+}
+
+module.exports = {
+  CONTRACTS,
+  ContractError,
+  decodeResponse,
+};
@@ -0,0 +1,30 @@
+const assert = require('node:assert/strict');
+const test = require('node:test');
+
+const { ContractError, decodeResponse } = require('./contracts');
+
+test('missing optional fields get their defaults', () => {
+  const payload = decodeResponse('session-start', { rules: [{ title: 'r' }], extra: 1 });
+  assert.deepEqual(payload, { rules: [{ title: 'r' }], issues: [], memories: [], extra: 1 });
+});
+
+test('nested mismatches name the field path and contract version', () => {
+  assert.throws(
+    () => decodeResponse('inject-preview', { reasons: [{ id: 1 }, { id: '2' }] }),
+    (error) => error instanceof ContractError
+      && error.path === 'reasons[1].id'
+      && error.message === 'inject-preview v1: reasons[1].id: expected number, got string',
+  );
+});
+
+test('required fields must be present', () => {
+  assert.throws(() => decodeResponse('memory', { tags: [] }), /memory v1: id: missing required field/);
+});
+
+test('top-level shape is checked', () => {
+  assert.deepEqual(decodeResponse('memory-triggers', [{ kind: 'warning', observation_id: 4 }]), [
+    { kind: 'warning', observation_id: 4, blurb: '' },
+  ]);
+  assert.throws(() => decodeResponse('memory-triggers', { matches: [] }), /memory-triggers v1: expected array, got object/);
+  assert.throws(() => decodeResponse('no-such-contract', {}), /unknown response contract/);
+});
@@ -5,6 +5,8 @@ const crypto = require('crypto');
 const fs = require('fs');
 const path = require('path');
 
+const { decodeResponse } = require('./contracts');
+
 function getServerURL() {
   // ENGRAM_URL may include a path (e.g. http://server:37777/mcp for MCP transport).
   // Hooks use REST API endpoints at the server root (/api/...), so we extract just the origin.
@@ -65,6 +67,20 @@ function LegacyProjectID(cwd) {
   return dirName + '_' + hash.slice(0, 6);
 }
 
+/**
+ * anchorProjectID returns the id stored in dir's .engram-project anchor file,
+ * or '' when there is none. The proxy writes the anchor the first time it
+ * sees a non-git directory, so the ID survives moving the directory.
+ */
+function anchorProjectID(dir) {
+  try {
+    const anchor = JSON.parse(fs.readFileSync(path.join(dir, '.engram-project'), 'utf8'));
+    return anchor && typeof anchor.id === 'string' ? anchor.id : '';
+  } catch {
+    return '';
+  }
+}
+
 /**
  * ProjectIDWithName returns the canonical project ID for the given working directory.
  * Prefers a stable git-remote-based ID (cross-platform, cross-OS-path).
@@ -72,7 +88,8 @@ function LegacyProjectID(cwd) {
  *
  * Algorithm mirrors internal/proxy/identity.go:ResolveProjectSlug exactly:
  *   - git repo with remote: SHA-256(remoteURL + "/" + relativePath), first 8 hex chars
- *   - non-git fallback: SHA-256(absolutePath), first 6 hex chars
+ *   - non-git fallback: the id in the .engram-project anchor file, else
+ *     SHA-256(absolutePath), first 6 hex chars
  */
 function ProjectIDWithName(cwd) {
   const gitResult = getGitRemoteID(cwd);
@@ -81,12 +98,144 @@ function ProjectIDWithName(cwd) {
   }
   // Fallback: pure path-based hash for directories without a git remote.
   const resolvedPath = path.resolve(cwd || '');
+  const anchored = anchorProjectID(resolvedPath);
+  if (anchored) {
+    return anchored;
+  }
   const hash = crypto.createHash('sha256').update(resolvedPath).digest('hex');
   return hash.slice(0, 6);
 }
 
+const WORKSPACE_MARKER_FILES = [
+  'go.work',
+  'pnpm-workspace.yaml',
+  'lerna.json',
+  'nx.json',
+  'turbo.json',
+  'rush.json',
+];
+
+/**
+ * hasWorkspaceMarker reports whether dir looks like a monorepo root: one of
+ * WORKSPACE_MARKER_FILES, a package.json with "workspaces", or a Cargo.toml
+ * with a [workspace] table.
+ */
+function hasWorkspaceMarker(dir) {
+  for (const name of WORKSPACE_MARKER_FILES) {
+    if (fs.existsSync(path.join(dir, name))) return true;
+  }
+  try {
+    const pkg = JSON.parse(fs.readFileSync(path.join(dir, 'package.json'), 'utf8'));
+    if (pkg && pkg.workspaces != null) return true;
+  } catch {
+    // missing or unparseable package.json
+  }
+  try {
+    const cargo = fs.readFileSync(path.join(dir, 'Cargo.toml'), 'utf8');
+    if (cargo.split(/\r?\n/).some((line) => line.trim() === '[workspace]')) return true;
+  } catch {
+    // no Cargo.toml
+  }
+  return false;
+}
+
+/**
+ * WorkspaceIDWithName returns { workspaceID, name } for the monorepo that
+ * encloses cwd, or null when there is none. The workspace ID is the project ID
+ * the workspace root would have, so memories stored from the root with
+ * scope "workspace" are shared by every package below it.
+ *
+ * The root is the nearest ancestor inside the same git repo with a monorepo
+ * marker, falling back to the repository root. Non-git directories and the
+ * workspace root itself have no workspace.
+ *
+ * Algorithm mirrors internal/proxy/workspace.go:ResolveWorkspaceSlug exactly.
+ */
+function WorkspaceIDWithName(cwd, gitResult = getGitRemoteID(cwd)) {
+  if (!gitResult || !gitResult.relativePath) return null;
+
+  const segments = gitResult.relativePath.replace(/\\/g, '/').replace(/^\/+|\/+$/g, '').split('/');
+  let dir = path.resolve(cwd || '');
+  let rootDir = dir;
+  let rootPrefix = '';
+  for (let i = segments.length - 1; i >= 0; i--) {
+    dir = path.dirname(dir);
+    rootDir = dir;
+    rootPrefix = i > 0 ? segments.slice(0, i).join('/') + '/' : '';
+    if (hasWorkspaceMarker(dir)) break;
+  }
+
+  const hash = crypto.createHash('sha256').update(gitResult.gitRemote + '/' + rootPrefix).digest('hex');
+  return { workspaceID: hash.slice(0, 8), name: path.basename(rootDir) };
+}
+
+// Every request of one hook run carries the same W3C trace ID, so the
+// server's spans for that run group into a single trace. A TRACEPARENT set by
+// the parent process is continued; otherwise a trace ID is generated once.
+let hookTraceID = '';
+
+function traceparent() {
+  if (!hookTraceID) {
+    const inherited = /^00-([0-9a-f]{32})-[0-9a-f]{16}-[0-9a-f]{2}$/
+      .exec((process.env.TRACEPARENT || '').trim().toLowerCase());
+    hookTraceID = inherited ? inherited[1] : crypto.randomBytes(16).toString('hex');
+  }
+  return `00-${hookTraceID}-${crypto.randomBytes(8).toString('hex')}-01`;
+}
+
+// Version handshake: every request carries the plugin version, and the
+// worker answers with the oldest version it supports. A mismatch is
+// remembered for the hook run so session-start can tell the user.
+let hookVersion;
+let requiredHookVersion = '';
+
+function getHookVersion() {
+  if (hookVersion === undefined) {
+    const manifest = readJSONFile(path.join(__dirname, '..', '.claude-plugin', 'plugin.json'));
+    hookVersion = manifest && typeof manifest.version === 'string' ? manifest.version : '';
+  }
+  return hookVersion;
+}
+
+// compareVersions compares dotted versions ("6.1.0", "v6.1"), returning -1,
+// 0 or 1. Pre-release suffixes are ignored, missing components count as zero.
+function compareVersions(a, b) {
+  const parts = (v) => String(v).trim().replace(/^v/, '').split(/[-+]/)[0].split('.').map((n) => parseInt(n, 10) || 0);
+  const pa = parts(a);
+  const pb = parts(b);
+  for (let i = 0; i < Math.max(pa.length, pb.length); i++) {
+    const x = pa[i] || 0;
+    const y = pb[i] || 0;
+    if (x !== y) return x < y ? -1 : 1;
+  }
+  return 0;
+}
+
+function checkHookVersion(response) {
+  const minVersion = response.headers.get('x-engram-min-hook-version');
+  const current = getHookVersion();
+  if (!minVersion || !current || compareVersions(current, minVersion) >= 0) {
+    return;
+  }
+  if (!requiredHookVersion) {
+    console.error(`[engram] hooks ${current} are older than the server requires (${minVersion}); run /plugin update engram`);
+  }
+  requiredHookVersion = minVersion;
+}
+
+// requiredHookUpdate returns the minimum hook version the worker asked for
+// when it is newer than these hooks, and '' otherwise.
+function requiredHookUpdate() {
+  return requiredHookVersion;
+}
+
 function buildRequestHeaders(includeJsonBody = false) {
-  const headers = {};
+  // Identifies hook traffic in the server's audit log.
+  const headers = { 'X-Engram-Client': 'hook', traceparent: traceparent() };
+  const version = getHookVersion();
+  if (version) {
+    headers['X-Engram-Hook-Version'] = version;
+  }
   const token = process.env.ENGRAM_AUTH_ADMIN_TOKEN;
   if (token) {
     headers.Authorization = `Bearer ${token}`;
@@ -111,6 +260,13 @@ function resolveRequestURL(endpoint) {
   return `${base}${normalizedEndpoint}`;
 }
 
+// isDryRun reports whether ENGRAM_DRY_RUN is set. In dry-run mode hooks compute
+// and print what they would inject but record nothing, on the server or locally.
+function isDryRun() {
+  const value = (process.env.ENGRAM_DRY_RUN || '').trim().toLowerCase();
+  return value === '1' || value === 'true' || value === 'yes';
+}
+
 function getPluginDataDir() {
   const fromEngram = process.env.ENGRAM_DATA_DIR;
   if (typeof fromEngram === 'string' && fromEngram.trim() !== '') {
@@ -206,44 +362,252 @@ function writeResponse(hookName, additionalContext) {
   }
 }
 
-async function requestGet(endpoint, timeoutMs = 10000) {
-  return request('GET', endpoint, undefined, timeoutMs);
+// Worker state shared by every hook process. After a connection failure,
+// hooks skip the server for a backoff window instead of paying the connect
+// latency (or timeout) on every tool call. The window doubles with each
+// consecutive failure, up to WORKER_DOWN_MAX_MS.
+const WORKER_DOWN_BASE_MS = 5000;
+const WORKER_DOWN_MAX_MS = 60000;
+
+// Connection-level errors mean the worker is unreachable. Request timeouts
+// do not: several hooks use deliberately tight ones.
+const WORKER_DOWN_ERROR_CODES = new Set([
+  'ECONNREFUSED',
+  'ECONNRESET',
+  'EHOSTUNREACH',
+  'ENETUNREACH',
+  'ENOTFOUND',
+  'EAI_AGAIN',
+  'ETIMEDOUT',
+  'UND_ERR_CONNECT_TIMEOUT',
+]);
+
+function getWorkerStatePath() {
+  const baseDir = getPluginDataDir();
+  return baseDir ? path.join(baseDir, 'cache', 'worker-state.json') : '';
 }
 
-async function requestPost(endpoint, body, timeoutMs = 10000) {
-  return request('POST', endpoint, body, timeoutMs);
+// workerDownUntil returns the time (ms) until which the worker at serverURL
+// is considered down, or 0 when hooks should try it.
+function workerDownUntil(serverURL, now = Date.now()) {
+  const state = readJSONFile(getWorkerStatePath());
+  if (!state || state.healthy !== false || state.url !== serverURL) {
+    return 0;
+  }
+  const failures = Math.max(1, Number(state.failures) || 1);
+  const backoff = Math.min(WORKER_DOWN_BASE_MS * 2 ** (failures - 1), WORKER_DOWN_MAX_MS);
+  const until = (Number(state.checkedAt) || 0) + backoff;
+  return until > now ? until : 0;
+}
+
+// recordWorkerHealth updates the shared worker state. A healthy result is
+// only written when it changes the cached state, keeping the hot path free
+// of file writes.
+function recordWorkerHealth(serverURL, healthy, errorMessage = '') {
+  const statePath = getWorkerStatePath();
+  if (!statePath) {
+    return;
+  }
+  const previous = readJSONFile(statePath);
+  const sameURL = previous && previous.url === serverURL;
+  if (healthy && sameURL && previous.healthy === true && !previous.strikes) {
+    return;
+  }
+  writeJSONFile(statePath, {
+    url: serverURL,
+    healthy,
+    failures: healthy ? 0 : (sameURL && previous.healthy === false ? Number(previous.failures) || 0 : 0) + 1,
+    strikes: 0,
+    checkedAt: Date.now(),
+    pid: process.pid,
+    error: healthy ? '' : errorMessage,
+  });
 }
 
-async function request(method, endpoint, body, timeoutMs = 10000) {
+// Slow-worker circuit breaker. A request that times out is a strike; after
+// ENGRAM_HOOK_BREAKER_THRESHOLD consecutive strikes the worker is marked down
+// like after a connection failure, so later hooks fail instantly instead of
+// each waiting out its timeout. A strike while the worker is already marked
+// down (its backoff has expired and this was the probe) trips it again.
+// Timeouts shorter than BREAKER_MIN_TIMEOUT_MS are best-effort lookups and
+// do not count.
+const BREAKER_DEFAULT_THRESHOLD = 3;
+const BREAKER_MIN_TIMEOUT_MS = 1000;
+
+function breakerThreshold() {
+  return envPositiveInt('ENGRAM_HOOK_BREAKER_THRESHOLD', BREAKER_DEFAULT_THRESHOLD);
+}
+
+// recordWorkerStrike counts a timed-out request against serverURL.
+function recordWorkerStrike(serverURL, errorMessage) {
+  const statePath = getWorkerStatePath();
+  if (!statePath) {
+    return;
+  }
+  const previous = readJSONFile(statePath);
+  const sameURL = previous && previous.url === serverURL;
+  const strikes = (sameURL ? Number(previous.strikes) || 0 : 0) + 1;
+  if ((sameURL && previous.healthy === false) || strikes >= breakerThreshold()) {
+    recordWorkerHealth(serverURL, false, errorMessage);
+    return;
+  }
+  writeJSONFile(statePath, {
+    url: serverURL,
+    healthy: true,
+    failures: 0,
+    strikes,
+    checkedAt: Date.now(),
+    pid: process.pid,
+    error: errorMessage,
+  });
+}
+
+function isConnectionError(error) {
+  const code = (error && error.cause && error.cause.code) || (error && error.code);
+  return WORKER_DOWN_ERROR_CODES.has(code);
+}
+
+// Hook timeouts. Each request names its own timeout; ENGRAM_HOOK_TIMEOUT_MS
+// replaces it for every hook and ENGRAM_HOOK_TIMEOUT_<HOOK>_MS (e.g.
+// ENGRAM_HOOK_TIMEOUT_SESSION_START_MS) for one. The timeout bounds the whole
+// call, retries included. The hook's own timeout in hooks.json still applies
+// on top.
+let currentHookName = '';
+
+function envPositiveInt(name, fallback) {
+  const value = parseInt(process.env[name] || '', 10);
+  return Number.isFinite(value) && value > 0 ? value : fallback;
+}
+
+// hookEnvName turns a hook name into its environment suffix
+// (SessionStart -> SESSION_START).
+function hookEnvName(hookName) {
+  return hookName.replace(/([a-z0-9])([A-Z])/g, '$1_$2').toUpperCase();
+}
+
+function hookTimeoutMs(defaultMs, hookName = currentHookName) {
+  const fallback = envPositiveInt('ENGRAM_HOOK_TIMEOUT_MS', defaultMs);
+  return hookName ? envPositiveInt(`ENGRAM_HOOK_TIMEOUT_${hookEnvName(hookName)}_MS`, fallback) : fallback;
+}
+
+// GET requests are idempotent and retried ENGRAM_HOOK_GET_RETRIES times
+// (default 1, at most 5) on a reset connection or a 502/503/504 answer, after
+// a jittered exponential delay, as long as the timeout leaves room.
+const RETRY_DEFAULT = 1;
+const RETRY_MAX = 5;
+const RETRY_BASE_DELAY_MS = 100;
+const RETRYABLE_STATUSES = new Set([502, 503, 504]);
+
+function getRetries() {
+  const value = parseInt(process.env.ENGRAM_HOOK_GET_RETRIES || '', 10);
+  return Number.isFinite(value) && value >= 0 ? Math.min(value, RETRY_MAX) : RETRY_DEFAULT;
+}
+
+// retryDelayMs returns the delay before retry number attempt+1: the base
+// delay doubled per attempt, scaled by a random factor in [0.5, 1.5).
+function retryDelayMs(attempt, random = Math.random) {
+  return Math.round(RETRY_BASE_DELAY_MS * 2 ** attempt * (0.5 + random()));
+}
+
+// The request helpers take an optional response contract name (see
+// contracts.js). With one, the parsed response is checked against it and
+// returned with defaults filled in; a mismatch throws a ContractError.
+async function requestGet(endpoint, timeoutMs = 10000, contract = '') {
+  return request('GET', endpoint, undefined, timeoutMs, contract);
+}
+
+async function requestPost(endpoint, body, timeoutMs = 10000, contract = '') {
+  return request('POST', endpoint, body, timeoutMs, contract);
+}
+
+async function requestPut(endpoint, body, timeoutMs = 10000, contract = '') {
+  return request('PUT', endpoint, body, timeoutMs, contract);
+}
+
+async function request(method, endpoint, body, timeoutMs = 10000, contract = '') {
   const url = resolveRequestURL(endpoint);
+  const serverURL = new URL(url).origin;
+  const downUntil = workerDownUntil(serverURL);
+  if (downUntil) {
+    const error = new Error(
+      `engram worker at ${serverURL} unavailable (skipping for ${Math.ceil((downUntil - Date.now()) / 1000)}s)`
+    );
+    error.code = 'ENGRAM_WORKER_DOWN';
+    throw error;
+  }
+
+  const totalMs = hookTimeoutMs(timeoutMs);
+  const deadline = Date.now() + totalMs;
+  const retries = method === 'GET' ? getRetries() : 0;
+  for (let attempt = 0; ; attempt++) {
+    let result;
+    try {
+      result = await requestOnce(method, url, serverURL, body, deadline - Date.now(), totalMs);
+    } catch (error) {
+      const delay = retryDelayMs(attempt);
+      if (!error.retryable || attempt >= retries || Date.now() + delay >= deadline) {
+        throw error;
+      }
+      await new Promise((resolve) => setTimeout(resolve, delay));
+      continue;
+    }
+    return contract ? decodeResponse(contract, result) : result;
+  }
+}
+
+// requestOnce makes one attempt within timeoutMs. Errors worth retrying are
+// marked retryable; totalMs is the caller's whole budget, which decides
+// whether a timeout counts as a breaker strike.
+async function requestOnce(method, url, serverURL, body, timeoutMs, totalMs) {
   const controller = new AbortController();
   const timer = setTimeout(() => controller.abort(), timeoutMs);
 
   try {
     const headers = buildRequestHeaders(body !== undefined);
-    const response = await fetch(url, {
-      method,
-      headers,
-      body: body === undefined ? undefined : JSON.stringify(body),
-      signal: controller.signal,
-    });
+    let response;
+    try {
+      response = await fetch(url, {
+        method,
+        headers,
+        body: body === undefined ? undefined : JSON.stringify(body),
+        signal: controller.signal,
+      });
+    } catch (error) {
+      const code = (error.cause && error.cause.code) || error.code;
+      if (isConnectionError(error)) {
+        recordWorkerHealth(serverURL, false, code || error.message);
+        error.retryable = code === 'ECONNRESET';
+      } else if (controller.signal.aborted && totalMs >= BREAKER_MIN_TIMEOUT_MS) {
+        recordWorkerStrike(serverURL, `timeout after ${totalMs}ms`);
+      }
+      throw error;
+    }
+    recordWorkerHealth(serverURL, true);
+    checkHookVersion(response);
 
     const text = await response.text();
     if (!response.ok) {
-      throw new Error(`HTTP ${response.status} ${response.statusText}: ${text}`);
+      const error = new Error(`HTTP ${response.status} ${response.statusText}: ${text}`);
+      error.retryable = RETRYABLE_STATUSES.has(response.status);
+      throw error;
     }
 
     if (!text) {
       return {};
     }
 
-    return JSON.parse(text);
+    try {
+      return JSON.parse(text);
+    } catch (error) {
+      throw new Error(`${method} ${new URL(url).pathname}: invalid JSON response: ${error.message}`);
+    }
   } finally {
     clearTimeout(timer);
   }
 }
 
 async function RunHook(hookName, handler) {
+  currentHookName = hookName;
   if (isInternalHook()) {
     writeResponse(hookName);
     return;
@@ -263,6 +627,7 @@ async function RunHook(hookName, handler) {
 
   const cwd = typeof input.cwd === 'string' ? input.cwd : '';
   const gitResult = getGitRemoteID(cwd);
+  const workspace = WorkspaceIDWithName(cwd, gitResult);
 
   const context = {
     SessionID: typeof input.session_id === 'string' ? input.session_id : '',
@@ -273,6 +638,8 @@ async function RunHook(hookName, handler) {
     LegacyProject: LegacyProjectID(cwd),
     GitRemote: gitResult ? gitResult.gitRemote : '',
     RelativePath: gitResult ? gitResult.relativePath : '',
+    Workspace: workspace ? workspace.workspaceID : '',
+    WorkspaceName: workspace ? workspace.name : '',
     RawInput: rawInput,
   };
 
@@ -287,6 +654,7 @@ async function RunHook(hookName, handler) {
 }
 
 async function RunStatuslineHook(handler, offlineRenderer) {
+  currentHookName = 'Statusline';
   try {
     const rawInput = await readAllStdin();
     let input = null;
@@ -385,6 +753,56 @@ function appendSessionFile(sessionID, filePath) {
   }
 }
 
+/**
+ * Read the files recently touched in the given session (see appendSessionFile),
+ * oldest first.
+ * @param {string} sessionID - Claude session ID
+ * @returns {string[]}
+ */
+function readSessionFiles(sessionID) {
+  if (!sessionID) return [];
+  try {
+    const current = JSON.parse(fs.readFileSync(_signalPath(sessionID), 'utf8'));
+    return Array.isArray(current.files)
+      ? current.files.filter((entry) => typeof entry === 'string')
+      : [];
+  } catch {
+    return [];
+  }
+}
+
+/**
+ * List the files with uncommitted changes in the git work tree at cwd,
+ * relative to the repository root. Renames report the new path.
+ * @param {string} cwd - Working directory
+ * @param {number} limit - Maximum number of files
+ * @returns {string[]} [] outside a git repository or on error
+ */
+function gitChangedFiles(cwd, limit = 20) {
+  if (!cwd) return [];
+  try {
+    const execSync = require('child_process').execSync;
+    const out = execSync('git status --porcelain --untracked-files=normal', {
+      cwd,
+      stdio: ['ignore', 'pipe', 'ignore'],
+      timeout: 2000,
+    }).toString();
+    const files = [];
+    for (const line of out.split('\n')) {
+      // "XY path" or "XY old -> new"
+      let file = line.slice(3).trim();
+      if (file === '') continue;
+      const arrow = file.indexOf(' -> ');
+      if (arrow >= 0) file = file.slice(arrow + 4);
+      files.push(file.replace(/^"|"$/g, ''));
+      if (files.length >= limit) break;
+    }
+    return files;
+  } catch {
+    return [];
+  }
+}
+
 // --- Crash-safe session markers (gstack-insights FR-8) ---
 
 const os = require('os');
@@ -522,18 +940,33 @@ function _timeAgo(date) {
 module.exports = {
   getServerURL,
   getPluginDataDir,
+  isDryRun,
+  traceparent,
   getSessionStartCachePath,
+  getWorkerStatePath,
+  recordWorkerHealth,
+  recordWorkerStrike,
+  workerDownUntil,
+  hookTimeoutMs,
+  retryDelayMs,
   readJSONFile,
   writeJSONFile,
   ProjectIDWithName,
   LegacyProjectID,
+  WorkspaceIDWithName,
   requestGet,
   requestPost,
+  requestPut,
+  getHookVersion,
+  compareVersions,
+  requiredHookUpdate,
   RunHook,
   RunStatuslineHook,
   writeResponse,
   incrementSessionSignals,
   appendSessionFile,
+  readSessionFiles,
+  gitChangedFiles,
   createPendingMarker,
   getStaleMarkers,
   formatIssuesBlock,
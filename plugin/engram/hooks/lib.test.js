@@ -1,5 +1,6 @@
 const assert = require('node:assert/strict');
 const fs = require('node:fs');
+const net = require('node:net');
 const os = require('node:os');
 const test = require('node:test');
 const crypto = require('crypto');
@@ -163,3 +164,262 @@ test('JS git ID algorithm matches Go ResolveProjectSlug for canonical test vecto
   assert.strictEqual(jsID, expected, 'JS ID must equal independently computed SHA-256 slice');
   assert.match(jsID, /^[0-9a-f]{8}$/, 'canonical vector must produce 8 hex chars');
 });
+
+test('non-git project ID follows the .engram-project anchor after a move', (t) => {
+  const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'engram-anchor-'));
+  t.after(() => fs.rmSync(dir, { recursive: true, force: true }));
+
+  assert.strictEqual(lib.ProjectIDWithName(dir), expectedPathProjectID(path.resolve(dir)));
+
+  fs.writeFileSync(path.join(dir, '.engram-project'), JSON.stringify({ name: 'notes', id: 'a1b2c3' }));
+  assert.strictEqual(lib.ProjectIDWithName(dir), 'a1b2c3', 'the anchored ID wins over the path hash');
+});
+
+// ---------------------------------------------------------------------------
+// Workspace identity (mirrors internal/proxy/workspace.go:ResolveWorkspaceSlug)
+// ---------------------------------------------------------------------------
+
+function makeWorkspaceFixture(t) {
+  const repo = fs.mkdtempSync(path.join(os.tmpdir(), 'engram-ws-'));
+  t.after(() => fs.rmSync(repo, { recursive: true, force: true }));
+  const svc = path.join(repo, 'apps', 'svc', 'billing');
+  fs.mkdirSync(svc, { recursive: true });
+  return { repo, svc };
+}
+
+const WS_REMOTE = 'https://github.com/org/monorepo.git';
+
+test('workspace falls back to the repository root without markers', (t) => {
+  const { repo, svc } = makeWorkspaceFixture(t);
+  const ws = lib.WorkspaceIDWithName(svc, { gitRemote: WS_REMOTE, relativePath: 'apps/svc/billing/' });
+  assert.deepStrictEqual(ws, {
+    workspaceID: expectedGitProjectID(WS_REMOTE, ''),
+    name: path.basename(repo),
+  });
+});
+
+test('workspace is the nearest ancestor with a monorepo marker', (t) => {
+  const { repo, svc } = makeWorkspaceFixture(t);
+  fs.writeFileSync(path.join(repo, 'apps', 'package.json'), JSON.stringify({ workspaces: ['svc/*'] }));
+  const ws = lib.WorkspaceIDWithName(svc, { gitRemote: WS_REMOTE, relativePath: 'apps/svc/billing/' });
+  assert.deepStrictEqual(ws, {
+    workspaceID: expectedGitProjectID(WS_REMOTE, 'apps/'),
+    name: 'apps',
+  });
+});
+
+test('plain package.json is not a workspace marker', (t) => {
+  const { repo, svc } = makeWorkspaceFixture(t);
+  fs.writeFileSync(path.join(repo, 'apps', 'package.json'), JSON.stringify({ name: 'apps' }));
+  const ws = lib.WorkspaceIDWithName(svc, { gitRemote: WS_REMOTE, relativePath: 'apps/svc/billing/' });
+  assert.strictEqual(ws.workspaceID, expectedGitProjectID(WS_REMOTE, ''));
+});
+
+test('repository root and non-git directories have no workspace', () => {
+  assert.strictEqual(lib.WorkspaceIDWithName(os.tmpdir(), { gitRemote: WS_REMOTE, relativePath: '' }), null);
+  assert.strictEqual(lib.WorkspaceIDWithName(os.tmpdir(), null), null);
+});
+
+test('traceparent keeps one trace ID per hook run', () => {
+  const first = lib.traceparent();
+  const second = lib.traceparent();
+  const pattern = /^00-([0-9a-f]{32})-([0-9a-f]{16})-01$/;
+  assert.match(first, pattern);
+  assert.match(second, pattern);
+  assert.strictEqual(pattern.exec(first)[1], pattern.exec(second)[1]);
+  assert.notStrictEqual(pattern.exec(first)[2], pattern.exec(second)[2]);
+});
+
+test('compareVersions orders dotted versions', () => {
+  assert.equal(lib.compareVersions('6.0.0', '6.0.0'), 0);
+  assert.equal(lib.compareVersions('v6.0', '6.0.0'), 0);
+  assert.equal(lib.compareVersions('5.9.9', '6.0.0'), -1);
+  assert.equal(lib.compareVersions('6.10.0', '6.9.0'), 1);
+  assert.equal(lib.compareVersions('6.1.0-rc1', '6.1.0'), 0);
+});
+
+test('requests carry the plugin version and note an outdated install', async (t) => {
+  const manifest = lib.readJSONFile(path.join(__dirname, '..', '.claude-plugin', 'plugin.json'));
+  assert.equal(lib.getHookVersion(), manifest.version);
+
+  const dataDir = fs.mkdtempSync(path.join(os.tmpdir(), 'engram-hook-version-'));
+  const saved = { data: process.env.ENGRAM_DATA_DIR, url: process.env.ENGRAM_URL };
+  const savedFetch = global.fetch;
+  t.after(() => {
+    global.fetch = savedFetch;
+    for (const [key, value] of [['ENGRAM_DATA_DIR', saved.data], ['ENGRAM_URL', saved.url]]) {
+      if (value === undefined) {
+        delete process.env[key];
+      } else {
+        process.env[key] = value;
+      }
+    }
+    fs.rmSync(dataDir, { recursive: true, force: true });
+  });
+  process.env.ENGRAM_DATA_DIR = dataDir;
+  process.env.ENGRAM_URL = 'http://hook-version.test';
+
+  let sent = '';
+  const respond = (minVersion) => async (url, options) => {
+    sent = options.headers['X-Engram-Hook-Version'];
+    return new Response('{}', { status: 200, headers: { 'X-Engram-Min-Hook-Version': minVersion } });
+  };
+
+  global.fetch = respond(manifest.version);
+  await lib.requestGet('/api/version');
+  assert.equal(sent, manifest.version);
+  assert.equal(lib.requiredHookUpdate(), '');
+
+  global.fetch = respond('999.0.0');
+  await lib.requestGet('/api/version');
+  assert.equal(lib.requiredHookUpdate(), '999.0.0');
+});
+
+// freePort returns a local port nothing listens on.
+async function freePort() {
+  const server = net.createServer();
+  await new Promise((resolve) => server.listen(0, '127.0.0.1', resolve));
+  const { port } = server.address();
+  await new Promise((resolve) => server.close(resolve));
+  return port;
+}
+
+test('a refused connection makes later requests skip the worker', async (t) => {
+  const serverURL = `http://127.0.0.1:${await freePort()}`;
+  const dataDir = fs.mkdtempSync(path.join(os.tmpdir(), 'engram-worker-state-'));
+  const saved = { data: process.env.ENGRAM_DATA_DIR, url: process.env.ENGRAM_URL };
+  process.env.ENGRAM_DATA_DIR = dataDir;
+  process.env.ENGRAM_URL = serverURL;
+  t.after(() => {
+    for (const [key, value] of [['ENGRAM_DATA_DIR', saved.data], ['ENGRAM_URL', saved.url]]) {
+      if (value === undefined) {
+        delete process.env[key];
+      } else {
+        process.env[key] = value;
+      }
+    }
+    fs.rmSync(dataDir, { recursive: true, force: true });
+  });
+
+  await assert.rejects(lib.requestGet('/health', 2000), (error) => error.code !== 'ENGRAM_WORKER_DOWN');
+  const state = lib.readJSONFile(lib.getWorkerStatePath());
+  assert.equal(state.healthy, false);
+  assert.equal(state.failures, 1);
+  assert.ok(lib.workerDownUntil(serverURL) > Date.now());
+
+  await assert.rejects(lib.requestGet('/health', 2000), { code: 'ENGRAM_WORKER_DOWN' });
+  assert.equal(lib.workerDownUntil('http://127.0.0.1:2'), 0, 'state is per server URL');
+
+  lib.recordWorkerHealth(serverURL, false, 'ECONNREFUSED');
+  assert.equal(lib.readJSONFile(lib.getWorkerStatePath()).failures, 2, 'consecutive failures back off longer');
+
+  lib.recordWorkerHealth(serverURL, true);
+  assert.equal(lib.workerDownUntil(serverURL), 0);
+});
+
+// withEnv sets environment variables for the duration of a test.
+function withEnv(t, values) {
+  const saved = {};
+  for (const [key, value] of Object.entries(values)) {
+    saved[key] = process.env[key];
+    process.env[key] = value;
+  }
+  t.after(() => {
+    for (const [key, value] of Object.entries(saved)) {
+      if (value === undefined) {
+        delete process.env[key];
+      } else {
+        process.env[key] = value;
+      }
+    }
+  });
+}
+
+test('hook timeouts are configurable globally and per hook', (t) => {
+  assert.equal(lib.hookTimeoutMs(5000, 'SessionStart'), 5000);
+  withEnv(t, { ENGRAM_HOOK_TIMEOUT_MS: '2000', ENGRAM_HOOK_TIMEOUT_PRE_TOOL_USE_MS: '300' });
+  assert.equal(lib.hookTimeoutMs(5000, 'SessionStart'), 2000);
+  assert.equal(lib.hookTimeoutMs(200, 'PreToolUse'), 300);
+  process.env.ENGRAM_HOOK_TIMEOUT_MS = 'soon';
+  assert.equal(lib.hookTimeoutMs(5000, 'SessionStart'), 5000, 'invalid values are ignored');
+});
+
+test('retry delays grow exponentially with jitter', () => {
+  assert.equal(lib.retryDelayMs(0, () => 0), 50);
+  assert.equal(lib.retryDelayMs(0, () => 0.5), 100);
+  assert.equal(lib.retryDelayMs(2, () => 0.5), 400);
+  assert.ok(lib.retryDelayMs(1, () => 0.999) <= 300);
+});
+
+test('GET requests are retried on 503, POST requests are not', async (t) => {
+  const dataDir = fs.mkdtempSync(path.join(os.tmpdir(), 'engram-hook-retry-'));
+  t.after(() => fs.rmSync(dataDir, { recursive: true, force: true }));
+  withEnv(t, { ENGRAM_DATA_DIR: dataDir, ENGRAM_URL: 'http://hook-retry.test' });
+  const savedFetch = global.fetch;
+  t.after(() => {
+    global.fetch = savedFetch;
+  });
+
+  let calls = 0;
+  global.fetch = async () => {
+    calls++;
+    return calls === 1
+      ? new Response('starting', { status: 503 })
+      : new Response('{"ok":true}', { status: 200 });
+  };
+  assert.deepEqual(await lib.requestGet('/api/version', 2000), { ok: true });
+  assert.equal(calls, 2);
+
+  calls = 0;
+  await assert.rejects(lib.requestPost('/api/store', {}, 2000), /HTTP 503/);
+  assert.equal(calls, 1);
+});
+
+test('consecutive timeouts trip the circuit breaker', async (t) => {
+  const serverURL = 'http://hook-breaker.test';
+  const dataDir = fs.mkdtempSync(path.join(os.tmpdir(), 'engram-hook-breaker-'));
+  t.after(() => fs.rmSync(dataDir, { recursive: true, force: true }));
+  withEnv(t, { ENGRAM_DATA_DIR: dataDir, ENGRAM_URL: serverURL, ENGRAM_HOOK_BREAKER_THRESHOLD: '2' });
+  const savedFetch = global.fetch;
+  t.after(() => {
+    global.fetch = savedFetch;
+  });
+  global.fetch = (url, options) =>
+    new Promise((resolve, reject) => {
+      options.signal.addEventListener('abort', () => reject(new DOMException('aborted', 'AbortError')));
+    });
+
+  await assert.rejects(lib.requestGet('/api/version', 1000), { name: 'AbortError' });
+  assert.equal(lib.readJSONFile(lib.getWorkerStatePath()).strikes, 1);
+  assert.equal(lib.workerDownUntil(serverURL), 0, 'one timeout does not trip the breaker');
+
+  await assert.rejects(lib.requestGet('/api/version', 1000), { name: 'AbortError' });
+  assert.ok(lib.workerDownUntil(serverURL) > Date.now());
+  await assert.rejects(lib.requestGet('/api/version', 1000), { code: 'ENGRAM_WORKER_DOWN' });
+
+  lib.recordWorkerHealth(serverURL, true);
+  assert.equal(lib.readJSONFile(lib.getWorkerStatePath()).strikes, 0);
+});
+
+test('responses are checked against the requested contract', async (t) => {
+  const dataDir = fs.mkdtempSync(path.join(os.tmpdir(), 'engram-hook-contract-'));
+  t.after(() => fs.rmSync(dataDir, { recursive: true, force: true }));
+  withEnv(t, { ENGRAM_DATA_DIR: dataDir, ENGRAM_URL: 'http://hook-contract.test' });
+  const savedFetch = global.fetch;
+  t.after(() => {
+    global.fetch = savedFetch;
+  });
+
+  let body = '{"id":7}';
+  global.fetch = async () => new Response(body, { status: 200 });
+  assert.deepEqual(await lib.requestGet('/api/memories/7', 2000, 'memory'), { id: 7, content: '', tags: [] });
+
+  body = '{"id":7,"tags":"a,b"}';
+  await assert.rejects(lib.requestGet('/api/memories/7', 2000, 'memory'), {
+    name: 'ContractError',
+    message: 'memory v1: tags: expected array, got string',
+  });
+
+  body = '<html>proxy error</html>';
+  await assert.rejects(lib.requestGet('/api/memories/7', 2000), /GET \/api\/memories\/7: invalid JSON response/);
+});
@@ -3,10 +3,81 @@
 
 const lib = require('./lib');
 
-async function handlePostToolUse() {
+// Engram's store tool as seen by Claude Code: mcp__engram__store, or
+// mcp__plugin_engram_engram__store when installed as a plugin.
+const STORE_TOOL = /^mcp__.*engram.*__store$/;
+
+// subagentTag returns the subagent:<type> tag for a subagent type, or ''.
+function subagentTag(agentType) {
+  if (typeof agentType !== 'string') {
+    return '';
+  }
+  const name = agentType.trim().toLowerCase().replace(/\s+/g, '-');
+  return name ? `subagent:${name}` : '';
+}
+
+// storedMemoryID returns the ID of the memory a store call created, from
+// the tool response (the MCP content blocks or their text).
+function storedMemoryID(toolResponse) {
+  let blocks = toolResponse;
+  if (blocks && !Array.isArray(blocks) && Array.isArray(blocks.content)) {
+    blocks = blocks.content;
+  }
+  const texts = Array.isArray(blocks)
+    ? blocks.filter((block) => block && block.type === 'text').map((block) => block.text)
+    : [blocks];
+  for (const text of texts) {
+    if (typeof text !== 'string') {
+      continue;
+    }
+    try {
+      const parsed = JSON.parse(text);
+      if (parsed && Number.isInteger(parsed.id) && parsed.id > 0) {
+        return parsed.id;
+      }
+    } catch {
+      // Not a JSON result (e.g. an error message).
+    }
+  }
+  return 0;
+}
+
+// handlePostToolUse tags memories stored from within a subagent with the
+// subagent's type, so they can be told apart from the parent session's
+// (recall query subagent:<type>). Other tool calls are ignored.
+async function handlePostToolUse(ctx, input) {
+  const tag = subagentTag(input && input.agent_type);
+  if (!tag || !STORE_TOOL.test(input.tool_name || '')) {
+    return '';
+  }
+  const action = input.tool_input && input.tool_input.action;
+  if (action && action !== 'create') {
+    return '';
+  }
+  const id = storedMemoryID(input.tool_response);
+  if (!id) {
+    return '';
+  }
+
+  try {
+    const memory = await lib.requestGet(`/api/memories/${id}`, 3000, 'memory');
+    if (!memory.tags.includes(tag)) {
+      await lib.requestPut(`/api/memories/${id}`, { tags: [...memory.tags, tag] }, 3000, 'memory');
+    }
+  } catch (error) {
+    console.error(`[post-tool-use] Warning: failed to tag memory ${id} with ${tag}: ${error.message}`);
+  }
   return '';
 }
 
-(async () => {
-  await lib.RunHook('PostToolUse', handlePostToolUse);
-})();
+if (require.main === module) {
+  (async () => {
+    await lib.RunHook('PostToolUse', handlePostToolUse);
+  })();
+}
+
+module.exports = {
+  handlePostToolUse,
+  storedMemoryID,
+  subagentTag,
+};
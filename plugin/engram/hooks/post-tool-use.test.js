@@ -0,0 +1,63 @@
+const assert = require('node:assert/strict');
+const fs = require('node:fs');
+const os = require('node:os');
+const path = require('node:path');
+const test = require('node:test');
+
+const { handlePostToolUse, storedMemoryID, subagentTag } = require('./post-tool-use');
+
+test('subagent types become subagent: tags', () => {
+  assert.equal(subagentTag('code-reviewer'), 'subagent:code-reviewer');
+  assert.equal(subagentTag(' General Purpose '), 'subagent:general-purpose');
+  assert.equal(subagentTag(''), '');
+  assert.equal(subagentTag(undefined), '');
+});
+
+test('the stored memory ID is read from the MCP tool response', () => {
+  const text = JSON.stringify({ id: 42, message: 'Memory stored successfully' });
+  assert.equal(storedMemoryID([{ type: 'text', text }]), 42);
+  assert.equal(storedMemoryID({ content: [{ type: 'text', text }] }), 42);
+  assert.equal(storedMemoryID(text), 42);
+  assert.equal(storedMemoryID([{ type: 'text', text: 'store memory: boom' }]), 0);
+});
+
+test('memories stored by a subagent are tagged with its type', async (t) => {
+  const dataDir = fs.mkdtempSync(path.join(os.tmpdir(), 'engram-post-tool-use-'));
+  const saved = { data: process.env.ENGRAM_DATA_DIR, url: process.env.ENGRAM_URL };
+  const savedFetch = global.fetch;
+  t.after(() => {
+    global.fetch = savedFetch;
+    for (const [key, value] of [['ENGRAM_DATA_DIR', saved.data], ['ENGRAM_URL', saved.url]]) {
+      if (value === undefined) {
+        delete process.env[key];
+      } else {
+        process.env[key] = value;
+      }
+    }
+    fs.rmSync(dataDir, { recursive: true, force: true });
+  });
+  process.env.ENGRAM_DATA_DIR = dataDir;
+  process.env.ENGRAM_URL = 'http://post-tool-use.test';
+
+  const calls = [];
+  global.fetch = async (url, options) => {
+    calls.push({ method: options.method, path: new URL(url).pathname, body: options.body });
+    return new Response(JSON.stringify({ id: 7, tags: ['type:decision'] }), { status: 200 });
+  };
+
+  const input = {
+    tool_name: 'mcp__plugin_engram_engram__store',
+    tool_input: { content: 'Use pgx' },
+    tool_response: [{ type: 'text', text: '{"id":7}' }],
+    agent_type: 'code-reviewer',
+  };
+  assert.equal(await handlePostToolUse({}, input), '');
+  assert.deepEqual(calls.map((c) => `${c.method} ${c.path}`), ['GET /api/memories/7', 'PUT /api/memories/7']);
+  assert.deepEqual(JSON.parse(calls[1].body), { tags: ['type:decision', 'subagent:code-reviewer'] });
+
+  calls.length = 0;
+  await handlePostToolUse({}, { ...input, agent_type: undefined });
+  await handlePostToolUse({}, { ...input, tool_name: 'Write' });
+  await handlePostToolUse({}, { ...input, tool_input: { action: 'edit', id: 7 } });
+  assert.equal(calls.length, 0, 'only creates from within a subagent are tagged');
+});
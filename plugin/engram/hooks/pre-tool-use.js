@@ -123,9 +123,8 @@ function renderTriggerContext(toolName, filePath, warnings, contextObs) {
 async function fetchByFileContext(project, filePath) {
   const params = new URLSearchParams({ path: filePath, limit: '10' });
   if (project) params.set('project', project);
-  const result = await lib.requestGet(`/api/context/by-file?${params.toString()}`, 200);
-  const observations = Array.isArray(result.observations) ? result.observations : [];
-  return classifyObservations(observations);
+  const result = await lib.requestGet(`/api/context/by-file?${params.toString()}`, 200, 'context-by-file');
+  return classifyObservations(result.observations);
 }
 
 async function fetchTriggerContext(project, sessionID, toolName, toolInput) {
@@ -136,13 +135,12 @@ async function fetchTriggerContext(project, sessionID, toolName, toolInput) {
   }
   const filePath = extractFilePath(toolInput);
   if (filePath) normalizedInput.file_path = filePath;
-  const result = await lib.requestPost('/api/memory/triggers', {
+  const matches = await lib.requestPost('/api/memory/triggers', {
     tool: toolName,
     params: normalizedInput,
     project,
     session_id: sessionID,
-  }, 200);
-  const matches = Array.isArray(result.matches) ? result.matches : Array.isArray(result) ? result : [];
+  }, 200, 'memory-triggers');
   return classifyMatches(matches);
 }
 
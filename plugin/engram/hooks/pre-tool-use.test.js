@@ -51,11 +51,9 @@ test('Read with repeated signal returns trigger context', async () => {
       project: 'engram',
       session_id: sessionID,
     });
-    return {
-      matches: [
-        { kind: 'context', observation_id: 11, blurb: 'Auth decision context' },
-      ],
-    };
+    return [
+      { kind: 'context', observation_id: 11, blurb: 'Auth decision context' },
+    ];
   };
 
   try {
@@ -87,11 +85,9 @@ test('Bash returns warning context from trigger endpoint', async () => {
       project: 'engram',
       session_id: 'bash-1',
     });
-    return {
-      matches: [
-        { kind: 'warning', observation_id: 42, blurb: 'This command previously failed' },
-      ],
-    };
+    return [
+      { kind: 'warning', observation_id: 42, blurb: 'This command previously failed' },
+    ];
   };
 
   try {
@@ -112,11 +108,9 @@ test('Read preserves existing read_counts value for file path', async () => {
   lib.requestPost = async (endpoint, body) => {
     assert.equal(endpoint, '/api/memory/triggers');
     assert.deepEqual(body.params.read_counts, { 'internal/auth.go': 1 });
-    return {
-      matches: [
-        { kind: 'context', observation_id: 99, blurb: 'Existing count preserved' },
-      ],
-    };
+    return [
+      { kind: 'context', observation_id: 99, blurb: 'Existing count preserved' },
+    ];
   };
 
   try {
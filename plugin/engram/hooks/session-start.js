@@ -52,6 +52,16 @@ function formatBehaviorRulesBlock(rules) {
   return block;
 }
 
+// polarityMarker returns the prefix that sets cautions and known-bad
+// approaches (polarity:caution / polarity:do_not tags) apart from knowledge
+// to apply, matching the server-side rendering.
+function polarityMarker(tags) {
+  if (!Array.isArray(tags)) return '';
+  if (tags.includes('polarity:do_not')) return '⚠️ Avoid: ';
+  if (tags.includes('polarity:caution')) return '⚠️ Caution: ';
+  return '';
+}
+
 function formatMemoriesBlock(memories) {
   if (!Array.isArray(memories) || memories.length === 0) {
     return '';
@@ -65,13 +75,21 @@ function formatMemoriesBlock(memories) {
     if (!memory || typeof memory !== 'object') continue;
     const content = escapeXmlTags(getString(memory.content));
     if (content === '') continue;
-    block += `- ${content}\n`;
+    block += `- ${polarityMarker(memory.tags)}${content}\n`;
   }
 
   block += '</engram-static-memories>\n';
   return block;
 }
 
+function formatDigestBlock(digest) {
+  const text = getString(digest).trim();
+  if (text === '') {
+    return '';
+  }
+  return `<engram-digest>\n${escapeXmlTags(text)}\n</engram-digest>\n`;
+}
+
 function buildSessionStartContext(payload, project) {
   const issues = payload && Array.isArray(payload.issues) ? payload.issues : [];
   const rules = payload && Array.isArray(payload.rules) ? payload.rules : [];
@@ -89,6 +107,10 @@ function buildSessionStartContext(payload, project) {
   if (memoriesBlock) {
     blocks.push(memoriesBlock.trimEnd());
   }
+  const digestBlock = formatDigestBlock(payload && payload.digest);
+  if (digestBlock) {
+    blocks.push(digestBlock.trimEnd());
+  }
 
   return blocks.filter(Boolean).join('\n') + (blocks.length > 0 ? '\n' : '');
 }
@@ -116,12 +138,70 @@ function formatStaleCacheBanner(generatedAt) {
   return `<engram-session-start-stale>\nWARNING: Engram session-start context is stale because live fetch failed.${suffix}\n</engram-session-start-stale>\n`;
 }
 
+// formatHookUpdateBanner tells the user the worker needs newer hooks than
+// the installed plugin provides (see the version handshake in lib.js).
+function formatHookUpdateBanner(minVersion) {
+  const current = lib.getHookVersion() || 'unknown';
+  return `<engram-hooks-outdated>\nWARNING: Engram hooks ${current} are older than the server requires (${minVersion}). Some context may be missing. Ask the user to run /plugin update engram and restart Claude Code.\n</engram-hooks-outdated>\n`;
+}
+
 function formatNoCacheBanner() {
   return '<engram-session-start-unavailable>\nWARNING: Engram session-start context is unavailable and no cache is present. Continuing without injected static context.\n</engram-session-start-unavailable>\n';
 }
 
-async function fetchSessionStartPayload(project) {
-  return lib.requestGet(`/api/context/session-start?project=${encodeURIComponent(project)}`, 5000);
+const INJECTION_MODES = ['full', 'pinned', 'digest', 'none'];
+
+// injectionModeFor returns what the hook injects for a session source
+// (startup, resume, clear or compact), configured in ENGRAM_SESSION_START_MODES
+// as comma-separated source=mode pairs, e.g. "resume=pinned,compact=digest,clear=none".
+// Modes: full (issues, rules and recent memories), pinned (rules and pinned
+// memories), digest (rules and this week's digest) and none. Sources not
+// listed, and invalid modes, get full.
+function injectionModeFor(source) {
+  const wanted = getString(source).trim().toLowerCase();
+  let mode = 'full';
+  for (const pair of (process.env.ENGRAM_SESSION_START_MODES || '').split(',')) {
+    const [key, value] = pair.split('=').map((part) => part.trim().toLowerCase());
+    if (key !== wanted || !value) continue;
+    if (INJECTION_MODES.includes(value)) {
+      mode = value;
+    } else {
+      console.error(`[engram] Ignoring unknown session-start mode "${value}" for ${key}`);
+    }
+  }
+  return mode;
+}
+
+async function fetchSessionStartPayload(project, workspace, mode = 'full') {
+  let endpoint = `/api/context/session-start?project=${encodeURIComponent(project)}`;
+  if (workspace) {
+    endpoint += `&workspace=${encodeURIComponent(workspace)}`;
+  }
+  if (mode !== 'full') {
+    endpoint += `&mode=${encodeURIComponent(mode)}`;
+  }
+  return lib.requestGet(endpoint, 5000, 'session-start');
+}
+
+// reportInjectPreview prints what /api/context/inject would select for the
+// project, with the reason for each pick. Used by dry-run mode only.
+async function reportInjectPreview(project, workspace, sessionID) {
+  let endpoint = `/api/context/inject/preview?project=${encodeURIComponent(project)}&format=compact`;
+  if (workspace) {
+    endpoint += `&workspace=${encodeURIComponent(workspace)}`;
+  }
+  if (sessionID) {
+    endpoint += `&session_id=${encodeURIComponent(sessionID)}`;
+  }
+  try {
+    const preview = await lib.requestGet(endpoint, 5000, 'inject-preview');
+    console.error(`[engram] dry-run: inject preview for ${project}: ${preview.reasons.length} items, ~${preview.token_estimate} tokens, query "${preview.query}"`);
+    for (const reason of preview.reasons) {
+      console.error(`[engram] dry-run:   #${reason.id} [${reason.section}] ~${reason.tokens} tokens: ${reason.reason}`);
+    }
+  } catch (error) {
+    console.error(`[engram] dry-run: inject preview failed: ${error.message}`);
+  }
 }
 
 function buildCachedSessionStartPayload(overrides = {}) {
@@ -140,9 +220,31 @@ async function handleSessionStart(ctx, input) {
   }
 
   const project = typeof ctx.Project === 'string' ? ctx.Project : '';
+  const workspace = typeof ctx.Workspace === 'string' ? ctx.Workspace : '';
+  const sessionID = typeof ctx.SessionID === 'string' ? ctx.SessionID : '';
+  const source = getString(input && input.source);
+  const mode = injectionModeFor(source);
+
+  // Dry run: print what would be injected without recording a session,
+  // acknowledging issues or touching the local cache.
+  if (lib.isDryRun()) {
+    console.error(`[engram] dry-run: nothing will be recorded (source ${source || 'unknown'}, mode ${mode})`);
+    if (mode === 'none') {
+      return '';
+    }
+    if (project && mode === 'full') {
+      await reportInjectPreview(project, workspace, sessionID);
+    }
+    try {
+      const payload = await fetchSessionStartPayload(project, workspace, mode);
+      return buildSessionStartContext(payload, project);
+    } catch (error) {
+      console.error(`[engram] dry-run: static session-start fetch failed: ${error.message}`);
+      return formatNoCacheBanner();
+    }
+  }
 
   // Crash-safe session tracking (gstack-insights FR-8)
-  const sessionID = typeof ctx.SessionID === 'string' ? ctx.SessionID : '';
   if (sessionID) {
     lib.createPendingMarker(sessionID);
   }
@@ -173,15 +275,22 @@ async function handleSessionStart(ctx, input) {
     }, 3000).catch(() => {});
   }
 
+  if (mode === 'none') {
+    console.error(`[engram] Session-start injection disabled for source ${source}`);
+    return '';
+  }
+
   const { cachePath, payload: cachedPayload } = getSessionStartCachePayload(project);
 
   try {
-    const payload = await fetchSessionStartPayload(project);
-    cacheSessionStartPayload(project, payload);
+    const payload = await fetchSessionStartPayload(project, workspace, mode);
+    // The cache backs up full injections only; a narrowed payload would
+    // replace it with less context.
+    if (mode === 'full') {
+      cacheSessionStartPayload(project, payload);
+    }
 
-    const rules = Array.isArray(payload && payload.rules) ? payload.rules : [];
-    const issues = Array.isArray(payload && payload.issues) ? payload.issues : [];
-    const memories = Array.isArray(payload && payload.memories) ? payload.memories : [];
+    const { rules, issues, memories } = payload;
 
     if (issues.length > 0) {
       console.error(`[engram] Injecting ${issues.length} active issues for ${project}`);
@@ -196,10 +305,18 @@ async function handleSessionStart(ctx, input) {
     if (memories.length > 0) {
       console.error(`[engram] Injected ${memories.length} static memories`);
     }
+    if (payload.digest) {
+      console.error(`[engram] Injected the weekly digest (source ${source})`);
+    }
 
-    return buildSessionStartContext(payload, project);
+    const minHookVersion = lib.requiredHookUpdate();
+    const banner = minHookVersion ? formatHookUpdateBanner(minHookVersion) : '';
+    return banner + buildSessionStartContext(payload, project);
   } catch (error) {
     console.error(`[engram] Warning: static session-start fetch failed: ${error.message}`);
+    if (mode !== 'full') {
+      return '';
+    }
     if (cachedPayload) {
       console.error(`[engram] Using cached session-start payload from ${cachePath}`);
       return formatStaleCacheBanner(cachedPayload.generated_at) + buildSessionStartContext(cachedPayload, project);
@@ -218,4 +335,5 @@ if (require.main === module) {
 module.exports = {
   buildCachedSessionStartPayload,
   handleSessionStart,
+  injectionModeFor,
 };
\ No newline at end of file
@@ -8,6 +8,7 @@ const lib = require('./lib');
 const {
   handleSessionStart,
   buildCachedSessionStartPayload,
+  injectionModeFor,
 } = require('./session-start');
 
 test('handleSessionStart caches live static payload and renders issues, rules, and memories', async () => {
@@ -46,6 +47,7 @@ test('handleSessionStart caches live static payload and renders issues, rules, a
       ],
       memories: [
         { id: 31, content: 'Session-start payload is static-only in v5.' },
+        { id: 32, content: 'Vendoring the SDK broke the release build.', tags: ['polarity:do_not'] },
       ],
       generated_at: '2026-04-22T12:34:56Z',
     });
@@ -63,6 +65,7 @@ test('handleSessionStart caches live static payload and renders issues, rules, a
     assert.match(result, /Always validate API responses before use\./);
     assert.match(result, /<engram-static-memories>/);
     assert.match(result, /Session-start payload is static-only in v5\./);
+    assert.match(result, /- ⚠️ Avoid: Vendoring the SDK broke the release build\./);
     assert.ok(getCalls.some((endpoint) => endpoint.includes('/api/context/session-start?project=engram')));
     assert.ok(postCalls.some((call) => call.endpoint === '/api/issues/acknowledge'));
 
@@ -189,3 +192,177 @@ test('handleSessionStart returns no-cache banner when live fetch fails and cache
     fs.rmSync(tmpDir, { recursive: true, force: true });
   }
 });
+
+test('handleSessionStart warns when the server requires newer hooks', async () => {
+  const tmpDir = fs.mkdtempSync(path.join(os.tmpdir(), 'engram-session-start-outdated-'));
+  const originalRequestGet = lib.requestGet;
+  const originalRequestPost = lib.requestPost;
+  const originalRequiredHookUpdate = lib.requiredHookUpdate;
+  const originalEngramDataDir = process.env.ENGRAM_DATA_DIR;
+  const originalEngramURL = process.env.ENGRAM_URL;
+
+  process.env.ENGRAM_DATA_DIR = tmpDir;
+  process.env.ENGRAM_URL = 'http://example.test/mcp';
+
+  lib.requestGet = async () => buildCachedSessionStartPayload({
+    memories: [{ id: 41, content: 'Hooks talk to the worker over REST.' }],
+  });
+  lib.requestPost = async () => ({});
+  lib.requiredHookUpdate = () => '999.0.0';
+
+  try {
+    const result = await handleSessionStart({ Project: 'engram', SessionID: 'sess-outdated' }, {});
+    assert.match(result, /<engram-hooks-outdated>/);
+    assert.match(result, /requires \(999\.0\.0\)/);
+    assert.match(result, /Hooks talk to the worker over REST\./);
+  } finally {
+    lib.requestGet = originalRequestGet;
+    lib.requestPost = originalRequestPost;
+    lib.requiredHookUpdate = originalRequiredHookUpdate;
+    if (originalEngramDataDir === undefined) {
+      delete process.env.ENGRAM_DATA_DIR;
+    } else {
+      process.env.ENGRAM_DATA_DIR = originalEngramDataDir;
+    }
+    if (originalEngramURL === undefined) {
+      delete process.env.ENGRAM_URL;
+    } else {
+      process.env.ENGRAM_URL = originalEngramURL;
+    }
+    fs.rmSync(tmpDir, { recursive: true, force: true });
+  }
+});
+
+test('handleSessionStart dry-run prints the inject preview and records nothing', async () => {
+  const tmpDir = fs.mkdtempSync(path.join(os.tmpdir(), 'engram-session-start-dry-run-'));
+  const originalRequestGet = lib.requestGet;
+  const originalRequestPost = lib.requestPost;
+  const originalConsoleError = console.error;
+  const originalEngramDataDir = process.env.ENGRAM_DATA_DIR;
+  const originalEngramURL = process.env.ENGRAM_URL;
+  const originalDryRun = process.env.ENGRAM_DRY_RUN;
+
+  process.env.ENGRAM_DATA_DIR = tmpDir;
+  process.env.ENGRAM_URL = 'http://example.test/mcp';
+  process.env.ENGRAM_DRY_RUN = '1';
+
+  const getCalls = [];
+  const postCalls = [];
+  const logged = [];
+  lib.requestGet = async (endpoint) => {
+    getCalls.push(endpoint);
+    if (endpoint.startsWith('/api/context/inject/preview')) {
+      return {
+        preview: true,
+        query: 'fix auth retry',
+        token_estimate: 120,
+        reasons: [{ id: 7, section: 'relevant', reason: "full-text match for the session's last prompt", tokens: 40 }],
+      };
+    }
+    return buildCachedSessionStartPayload({
+      issues: [{ id: 11, title: 'Open issue', status: 'open', target_project: 'engram' }],
+      memories: [{ id: 31, content: 'Dry-run memory.' }],
+    });
+  };
+  lib.requestPost = async (endpoint, body) => {
+    postCalls.push({ endpoint, body });
+    return {};
+  };
+  console.error = (message) => logged.push(String(message));
+
+  try {
+    const result = await handleSessionStart({ Project: 'engram', SessionID: 'sess-dry' }, {});
+    assert.match(result, /Dry-run memory\./);
+    assert.ok(getCalls.some((endpoint) => endpoint.includes('/api/context/inject/preview?project=engram')));
+    assert.ok(getCalls.some((endpoint) => endpoint.includes('session_id=sess-dry')));
+    assert.deepEqual(postCalls, [], 'dry-run must not store timeline events or acknowledge issues');
+    assert.ok(logged.some((line) => line.includes('#7 [relevant]')));
+    assert.ok(!fs.existsSync(lib.getSessionStartCachePath('engram')), 'dry-run must not write the cache');
+    assert.ok(!fs.existsSync(path.join(os.tmpdir(), '.engram-pending-sess-dry')), 'dry-run must not create a pending marker');
+  } finally {
+    lib.requestGet = originalRequestGet;
+    lib.requestPost = originalRequestPost;
+    console.error = originalConsoleError;
+    for (const [key, value] of [
+      ['ENGRAM_DATA_DIR', originalEngramDataDir],
+      ['ENGRAM_URL', originalEngramURL],
+      ['ENGRAM_DRY_RUN', originalDryRun],
+    ]) {
+      if (value === undefined) {
+        delete process.env[key];
+      } else {
+        process.env[key] = value;
+      }
+    }
+    fs.rmSync(tmpDir, { recursive: true, force: true });
+  }
+});
+
+test('injectionModeFor reads per-source modes from ENGRAM_SESSION_START_MODES', () => {
+  const original = process.env.ENGRAM_SESSION_START_MODES;
+  process.env.ENGRAM_SESSION_START_MODES = 'resume=pinned, Compact=DIGEST,clear=none,startup=everything';
+  try {
+    assert.equal(injectionModeFor('resume'), 'pinned');
+    assert.equal(injectionModeFor('compact'), 'digest');
+    assert.equal(injectionModeFor('clear'), 'none');
+    assert.equal(injectionModeFor('startup'), 'full', 'an unknown mode falls back to full');
+    assert.equal(injectionModeFor(''), 'full');
+  } finally {
+    if (original === undefined) {
+      delete process.env.ENGRAM_SESSION_START_MODES;
+    } else {
+      process.env.ENGRAM_SESSION_START_MODES = original;
+    }
+  }
+});
+
+test('handleSessionStart injects per session source', async () => {
+  const tmpDir = fs.mkdtempSync(path.join(os.tmpdir(), 'engram-session-start-modes-'));
+  const originalRequestGet = lib.requestGet;
+  const originalRequestPost = lib.requestPost;
+  const originalEngramDataDir = process.env.ENGRAM_DATA_DIR;
+  const originalEngramURL = process.env.ENGRAM_URL;
+  const originalModes = process.env.ENGRAM_SESSION_START_MODES;
+
+  process.env.ENGRAM_DATA_DIR = tmpDir;
+  process.env.ENGRAM_URL = 'http://example.test/mcp';
+  process.env.ENGRAM_SESSION_START_MODES = 'compact=digest,clear=none';
+
+  const getCalls = [];
+  lib.requestGet = async (endpoint) => {
+    getCalls.push(endpoint);
+    return buildCachedSessionStartPayload({
+      rules: [{ id: 21, content: 'Always validate API responses before use.' }],
+      digest: '# engram — weekly digest 2026-W42\n\n## Decisions\n\n- Use pgx _(#1, 2026-10-13)_',
+    });
+  };
+  lib.requestPost = async () => ({});
+
+  try {
+    const compacted = await handleSessionStart({ Project: 'engram', SessionID: 'sess-compact' }, { source: 'compact' });
+    assert.ok(getCalls.some((endpoint) => endpoint.endsWith('&mode=digest')));
+    assert.match(compacted, /<engram-digest>\n# engram — weekly digest 2026-W42/);
+    assert.match(compacted, /<user-behavior-rules>/);
+    assert.ok(!fs.existsSync(lib.getSessionStartCachePath('engram')), 'a narrowed payload must not replace the cache');
+
+    getCalls.length = 0;
+    const cleared = await handleSessionStart({ Project: 'engram', SessionID: 'sess-clear' }, { source: 'clear' });
+    assert.equal(cleared, '');
+    assert.deepEqual(getCalls, [], 'mode none fetches nothing');
+  } finally {
+    lib.requestGet = originalRequestGet;
+    lib.requestPost = originalRequestPost;
+    for (const [key, value] of [
+      ['ENGRAM_DATA_DIR', originalEngramDataDir],
+      ['ENGRAM_URL', originalEngramURL],
+      ['ENGRAM_SESSION_START_MODES', originalModes],
+    ]) {
+      if (value === undefined) {
+        delete process.env[key];
+      } else {
+        process.env[key] = value;
+      }
+    }
+    fs.rmSync(tmpDir, { recursive: true, force: true });
+  }
+});
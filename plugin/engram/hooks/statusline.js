@@ -1,16 +1,256 @@
 #!/usr/bin/env node
 'use strict';
 
+const { spawn } = require('node:child_process');
+const os = require('node:os');
+const path = require('node:path');
+
 const lib = require('./lib');
 
-function renderStatusline() {
-  return '[engram] ○ v5 cleanup in progress';
+const BASIC_STATUS = '[engram] ○ v5 cleanup in progress';
+
+// DEFAULT_TEMPLATE is the rich layout; ENGRAM_STATUSLINE_TEMPLATE replaces it.
+// Placeholders: {context} {cost} {memory} {model} {project}.
+const DEFAULT_TEMPLATE = '[engram] {context} · {cost} · {memory}';
+
+// Context usage at or above these percentages turns yellow, then red.
+const CONTEXT_WARN_PERCENT = 60;
+const CONTEXT_CRITICAL_PERCENT = 85;
+
+// The statusline redraws often; a slow server must not stall it.
+const STATS_TIMEOUT_MS = 800;
+
+// Memory stats are rendered from a local cache. Once older than the TTL they
+// are still rendered while a detached process refreshes them; at most one
+// refresh runs per lock window.
+const STATS_CACHE_TTL_MS = 30 * 1000;
+const STATS_REFRESH_LOCK_MS = 10 * 1000;
+const REFRESH_STATS_FLAG = '--refresh-stats';
+
+const ANSI = {
+  green: '\x1b[32m',
+  yellow: '\x1b[33m',
+  red: '\x1b[31m',
+  reset: '\x1b[0m',
+};
+
+// statuslineFormat returns "rich" or "basic": a --format=<f> / --format <f>
+// argument wins over ENGRAM_STATUSLINE_FORMAT.
+function statuslineFormat(argv = process.argv.slice(2), env = process.env) {
+  let format = '';
+  for (let i = 0; i < argv.length; i++) {
+    if (argv[i].startsWith('--format=')) {
+      format = argv[i].slice('--format='.length);
+    } else if (argv[i] === '--format' && i + 1 < argv.length) {
+      format = argv[i + 1];
+    }
+  }
+  format = (format || env.ENGRAM_STATUSLINE_FORMAT || '').trim().toLowerCase();
+  return format === 'rich' ? 'rich' : 'basic';
+}
+
+function finiteNumber(value) {
+  return typeof value === 'number' && Number.isFinite(value) ? value : null;
+}
+
+// contextPercent returns the share of the context window in use (0-100), or
+// null when the input carries no context_window data. The reported
+// used_percentage wins; otherwise the current request's input tokens
+// (including cache reads and writes) are divided by the window size.
+function contextPercent(input) {
+  const cw = input && input.context_window;
+  if (!cw || typeof cw !== 'object') {
+    return null;
+  }
+  const reported = finiteNumber(cw.used_percentage);
+  if (reported !== null) {
+    return Math.max(0, Math.min(100, reported));
+  }
+  const size = finiteNumber(cw.context_window_size);
+  if (!size || size <= 0) {
+    return null;
+  }
+  let used = null;
+  const usage = cw.current_usage;
+  if (usage && typeof usage === 'object') {
+    used = ['input_tokens', 'cache_creation_input_tokens', 'cache_read_input_tokens']
+      .reduce((sum, key) => sum + (finiteNumber(usage[key]) || 0), 0);
+  } else {
+    used = finiteNumber(cw.total_input_tokens);
+  }
+  if (used === null) {
+    return null;
+  }
+  return Math.max(0, Math.min(100, (used / size) * 100));
+}
+
+function colorize(text, color, useColor) {
+  return useColor ? `${ANSI[color]}${text}${ANSI.reset}` : text;
+}
+
+function formatContext(input, useColor) {
+  const percent = contextPercent(input);
+  if (percent === null) {
+    return 'ctx –';
+  }
+  let color = 'green';
+  if (percent >= CONTEXT_CRITICAL_PERCENT) {
+    color = 'red';
+  } else if (percent >= CONTEXT_WARN_PERCENT) {
+    color = 'yellow';
+  }
+  return colorize(`ctx ${Math.round(percent)}%`, color, useColor);
+}
+
+function formatCost(input) {
+  const cost = finiteNumber(input && input.cost && input.cost.total_cost_usd);
+  return cost === null ? '$–' : `$${cost.toFixed(2)}`;
+}
+
+// formatMemory renders the memory hit rate: the share of retrievals that were
+// context injections rather than explicit searches, with both counts.
+function formatMemory(stats) {
+  if (!stats) {
+    return 'mem –';
+  }
+  const injected = finiteNumber(stats.context_injections) || 0;
+  const searched = finiteNumber(stats.search_requests) || 0;
+  const total = injected + searched;
+  if (total === 0) {
+    return 'mem 0';
+  }
+  return `mem ${Math.round((injected / total) * 100)}% (${injected} inj/${searched} srch)`;
+}
+
+// renderRich fills the template. Unknown placeholders are left as written.
+function renderRich(input, stats, options = {}) {
+  const template = options.template || DEFAULT_TEMPLATE;
+  const useColor = options.color !== false;
+  const values = {
+    context: formatContext(input, useColor),
+    cost: formatCost(input),
+    memory: formatMemory(stats),
+    model: (input && input.model && input.model.display_name) || '',
+    project: options.project || '',
+  };
+  return template.replace(/\{(\w+)\}/g, (match, key) =>
+    Object.prototype.hasOwnProperty.call(values, key) ? values[key] : match);
+}
+
+function inputCWD(input) {
+  if (!input) {
+    return '';
+  }
+  if (input.workspace && typeof input.workspace.current_dir === 'string') {
+    return input.workspace.current_dir;
+  }
+  return typeof input.cwd === 'string' ? input.cwd : '';
+}
+
+// fetchMemoryStats returns the project's retrieval counts for the last 24
+// hours, or null when the server cannot answer in time.
+async function fetchMemoryStats(project) {
+  const since = new Date(Date.now() - 24 * 60 * 60 * 1000).toISOString();
+  const query = new URLSearchParams({ project, since });
+  try {
+    return await lib.requestGet(`/api/stats/retrieval?${query}`, STATS_TIMEOUT_MS, 'retrieval-stats');
+  } catch {
+    return null;
+  }
+}
+
+// statsCachePath returns the cache file of a project's memory stats, under
+// the plugin data dir when one is set and the temp dir otherwise.
+function statsCachePath(project) {
+  const dataDir = lib.getPluginDataDir();
+  const baseDir = dataDir ? path.join(dataDir, 'cache') : os.tmpdir();
+  const safeProject = String(project).replace(/[^a-zA-Z0-9._-]/g, '_');
+  return path.join(baseDir, `engram-statusline-${safeProject}.json`);
+}
+
+function writeStatsCache(project, stats, now = Date.now()) {
+  lib.writeJSONFile(statsCachePath(project), { project, stats, fetchedAt: now });
+}
+
+// refreshMemoryStats fetches the stats and caches them. A failed fetch keeps
+// the last cached stats, so a worker pause does not blank the statusline.
+async function refreshMemoryStats(project) {
+  const stats = await fetchMemoryStats(project);
+  if (stats) {
+    writeStatsCache(project, stats);
+  }
+  return stats;
+}
+
+// spawnStatsRefresh runs refreshMemoryStats in a detached process so the
+// statusline can print and exit without waiting for the server.
+function spawnStatsRefresh(project) {
+  try {
+    const child = spawn(process.execPath, [__filename, REFRESH_STATS_FLAG, project], {
+      detached: true,
+      stdio: 'ignore',
+      env: process.env,
+    });
+    child.on('error', () => {});
+    child.unref();
+  } catch {
+    // Refreshing is best-effort; the cached stats are rendered either way.
+  }
+}
+
+// loadMemoryStats returns the project's cached stats without waiting for the
+// server, scheduling a background refresh when they are older than the TTL.
+// Only a cold cache is fetched inline.
+async function loadMemoryStats(project, options = {}) {
+  const now = options.now || Date.now();
+  const refresh = options.refresh || spawnStatsRefresh;
+  const cachePath = statsCachePath(project);
+  const cached = lib.readJSONFile(cachePath);
+  if (!cached || !cached.stats) {
+    return refreshMemoryStats(project);
+  }
+  const fresh = now - (Number(cached.fetchedAt) || 0) < STATS_CACHE_TTL_MS;
+  const refreshing = now - (Number(cached.refreshStartedAt) || 0) < STATS_REFRESH_LOCK_MS;
+  if (!fresh && !refreshing) {
+    lib.writeJSONFile(cachePath, { ...cached, refreshStartedAt: now });
+    refresh(project);
+  }
+  return cached.stats;
+}
+
+async function renderStatusline(input) {
+  if (statuslineFormat() !== 'rich') {
+    return BASIC_STATUS;
+  }
+  const cwd = inputCWD(input);
+  const project = cwd ? lib.ProjectIDWithName(cwd) : '';
+  const stats = project ? await loadMemoryStats(project) : null;
+  return renderRich(input, stats, {
+    template: process.env.ENGRAM_STATUSLINE_TEMPLATE,
+    color: !process.env.NO_COLOR,
+    project,
+  });
+}
+
+function renderOffline() {
+  return BASIC_STATUS;
 }
 
 if (require.main === module) {
-  lib.RunStatuslineHook(renderStatusline, renderStatusline);
+  const args = process.argv.slice(2);
+  if (args[0] === REFRESH_STATS_FLAG) {
+    refreshMemoryStats(args[1] || '').catch(() => {});
+  } else {
+    lib.RunStatuslineHook(renderStatusline, renderOffline);
+  }
 }
 
 module.exports = {
+  contextPercent,
+  formatMemory,
+  loadMemoryStats,
+  renderRich,
   renderStatusline,
+  statsCachePath,
+  statuslineFormat,
 };
@@ -0,0 +1,105 @@
+const assert = require('node:assert/strict');
+const fs = require('node:fs');
+const os = require('node:os');
+const path = require('node:path');
+const test = require('node:test');
+
+const lib = require('./lib');
+const {
+  contextPercent,
+  formatMemory,
+  loadMemoryStats,
+  renderRich,
+  statsCachePath,
+  statuslineFormat,
+} = require('./statusline');
+
+test('statuslineFormat prefers the --format argument over the environment', () => {
+  assert.equal(statuslineFormat([], {}), 'basic');
+  assert.equal(statuslineFormat([], { ENGRAM_STATUSLINE_FORMAT: 'rich' }), 'rich');
+  assert.equal(statuslineFormat(['--format=basic'], { ENGRAM_STATUSLINE_FORMAT: 'rich' }), 'basic');
+  assert.equal(statuslineFormat(['--format', 'rich'], {}), 'rich');
+  assert.equal(statuslineFormat(['--format=fancy'], {}), 'basic');
+});
+
+test('contextPercent uses used_percentage, then current usage over the window size', () => {
+  assert.equal(contextPercent({}), null);
+  assert.equal(contextPercent({ context_window: { used_percentage: 42 } }), 42);
+  assert.equal(contextPercent({
+    context_window: {
+      context_window_size: 200000,
+      current_usage: { input_tokens: 10000, cache_read_input_tokens: 90000, output_tokens: 5000 },
+    },
+  }), 50);
+  assert.equal(contextPercent({ context_window: { context_window_size: 1000, total_input_tokens: 250 } }), 25);
+});
+
+test('formatMemory reports the share of injected retrievals', () => {
+  assert.equal(formatMemory(null), 'mem –');
+  assert.equal(formatMemory({ context_injections: 0, search_requests: 0 }), 'mem 0');
+  assert.equal(formatMemory({ context_injections: 12, search_requests: 4 }), 'mem 75% (12 inj/4 srch)');
+});
+
+test('renderRich colors context usage by threshold', () => {
+  const stats = { context_injections: 3, search_requests: 1 };
+  const low = renderRich({ context_window: { used_percentage: 20 }, cost: { total_cost_usd: 0.4213 } }, stats);
+  assert.equal(low, '[engram] \x1b[32mctx 20%\x1b[0m · $0.42 · mem 75% (3 inj/1 srch)');
+
+  const high = renderRich({ context_window: { used_percentage: 90 } }, stats);
+  assert.match(high, /\x1b\[31mctx 90%/);
+
+  const plain = renderRich({ context_window: { used_percentage: 70 } }, null, { color: false });
+  assert.equal(plain, '[engram] ctx 70% · $– · mem –');
+});
+
+test('renderRich fills a custom template', () => {
+  const out = renderRich(
+    { model: { display_name: 'Opus' }, cost: { total_cost_usd: 1.5 } },
+    null,
+    { template: '{model} {cost} {project} {unknown}', project: 'engram', color: false }
+  );
+  assert.equal(out, 'Opus $1.50 engram {unknown}');
+});
+
+test('loadMemoryStats renders from the cache and refreshes stale stats in the background', async () => {
+  const tmpDir = fs.mkdtempSync(path.join(os.tmpdir(), 'engram-statusline-'));
+  const originalRequestGet = lib.requestGet;
+  const originalEngramDataDir = process.env.ENGRAM_DATA_DIR;
+  process.env.ENGRAM_DATA_DIR = tmpDir;
+
+  let fetches = 0;
+  lib.requestGet = async () => {
+    fetches++;
+    return { context_injections: 2, search_requests: 1 };
+  };
+  const refreshed = [];
+  const refresh = (project) => refreshed.push(project);
+
+  try {
+    // A cold cache is fetched inline and written.
+    const now = Date.now();
+    assert.deepEqual(await loadMemoryStats('engram', { now, refresh }), { context_injections: 2, search_requests: 1 });
+    assert.equal(fetches, 1);
+    assert.ok(fs.existsSync(statsCachePath('engram')), 'expected cache file to be written');
+
+    // Fresh stats come from the cache without a request.
+    await loadMemoryStats('engram', { now: now + 1000, refresh });
+    assert.equal(fetches, 1);
+    assert.deepEqual(refreshed, []);
+
+    // Stale stats are still rendered; one refresh is scheduled per lock window.
+    const stale = now + 60 * 1000;
+    assert.deepEqual(await loadMemoryStats('engram', { now: stale, refresh }), { context_injections: 2, search_requests: 1 });
+    await loadMemoryStats('engram', { now: stale + 1000, refresh });
+    assert.deepEqual(refreshed, ['engram']);
+    assert.equal(fetches, 1);
+  } finally {
+    lib.requestGet = originalRequestGet;
+    if (originalEngramDataDir === undefined) {
+      delete process.env.ENGRAM_DATA_DIR;
+    } else {
+      process.env.ENGRAM_DATA_DIR = originalEngramDataDir;
+    }
+    fs.rmSync(tmpDir, { recursive: true, force: true });
+  }
+});
@@ -3,13 +3,16 @@
 
 const lib = require('./lib');
 
-async function handleSubagentStop(ctx) {
-  console.error(`[subagent-stop] Subagent completed in project ${ctx.Project}`);
+async function handleSubagentStop(ctx, input) {
+  const agentType = typeof input.agent_type === 'string' ? input.agent_type : '';
+  console.error(`[subagent-stop] Subagent ${agentType || '(unknown type)'} completed in project ${ctx.Project}`);
 
   try {
     await lib.requestPost('/api/sessions/subagent-complete', {
       claudeSessionId: ctx.SessionID,
       project: ctx.Project,
+      agentId: typeof input.agent_id === 'string' ? input.agent_id : '',
+      agentType,
     });
   } catch (error) {
     console.error(
@@ -3,8 +3,82 @@
 
 const lib = require('./lib');
 
-async function handleUserPrompt() {
-  return '';
+// Prompt search is opt-in: v5 injects static context at session start, and
+// a per-prompt search costs a worker round trip on every prompt.
+const PROMPT_SEARCH_LIMIT = 5;
+const WORKING_FILES_LIMIT = 20;
+
+function promptSearchEnabled() {
+  const value = String(process.env.ENGRAM_PROMPT_CONTEXT || '').trim().toLowerCase();
+  return value === '1' || value === 'true';
+}
+
+function getString(value) {
+  return typeof value === 'string' ? value : '';
+}
+
+function escapeXmlTags(text) {
+  return getString(text).replace(/</g, '&lt;').replace(/>/g, '&gt;');
+}
+
+// workingFiles returns the files the user is working on: the ones edited in
+// this session (most recent first), then uncommitted changes in the work
+// tree, without duplicates.
+function workingFiles(ctx) {
+  const files = [];
+  const seen = new Set();
+  const add = (file) => {
+    if (file && !seen.has(file) && files.length < WORKING_FILES_LIMIT) {
+      seen.add(file);
+      files.push(file);
+    }
+  };
+  lib.readSessionFiles(getString(ctx.SessionID)).reverse().forEach(add);
+  lib.gitChangedFiles(getString(ctx.CWD), WORKING_FILES_LIMIT).forEach(add);
+  return files;
+}
+
+function formatPromptContext(observations) {
+  const lines = [];
+  for (const obs of observations) {
+    const text = escapeXmlTags(obs.narrative || obs.title).trim();
+    if (text === '') continue;
+    const files = obs.matched_files.length > 0
+      ? ` (touches ${obs.matched_files.map(escapeXmlTags).join(', ')})`
+      : '';
+    lines.push(`- #${obs.id}${files}: ${text}`);
+  }
+  if (lines.length === 0) {
+    return '';
+  }
+  return `<engram-prompt-context>\n# Relevant Memory\n${lines.join('\n')}\n</engram-prompt-context>\n`;
+}
+
+// handleUserPrompt searches memories relevant to the prompt when
+// ENGRAM_PROMPT_CONTEXT is set, sending the files being worked on so the
+// worker ranks memories about them first.
+async function handleUserPrompt(ctx, input) {
+  if (!promptSearchEnabled()) {
+    return '';
+  }
+  const prompt = getString(input && input.prompt).trim();
+  const project = getString(ctx.Project);
+  if (prompt === '' || project === '') {
+    return '';
+  }
+
+  try {
+    const result = await lib.requestPost(`/api/context/search?limit=${PROMPT_SEARCH_LIMIT}`, {
+      project,
+      query: prompt,
+      session_id: getString(ctx.SessionID),
+      files_being_edited: workingFiles(ctx),
+    }, 3000, 'context-search');
+    return formatPromptContext(result.observations);
+  } catch (error) {
+    console.error(`[user-prompt] Context search failed: ${error.message}`);
+    return '';
+  }
 }
 
 if (require.main === module) {
@@ -15,4 +89,6 @@ if (require.main === module) {
 
 module.exports = {
   handleUserPrompt,
+  workingFiles,
+  formatPromptContext,
 };
@@ -0,0 +1,81 @@
+const assert = require('node:assert/strict');
+const fs = require('node:fs');
+const os = require('node:os');
+const path = require('node:path');
+const test = require('node:test');
+
+const lib = require('./lib');
+const { handleUserPrompt, workingFiles } = require('./user-prompt');
+
+test('handleUserPrompt does nothing unless ENGRAM_PROMPT_CONTEXT is set', async () => {
+  const originalRequestPost = lib.requestPost;
+  const original = process.env.ENGRAM_PROMPT_CONTEXT;
+  delete process.env.ENGRAM_PROMPT_CONTEXT;
+  lib.requestPost = async () => {
+    throw new Error('requestPost should not be called');
+  };
+
+  try {
+    const result = await handleUserPrompt({ Project: 'engram', SessionID: 's1' }, { prompt: 'why is sync slow' });
+    assert.equal(result, '');
+  } finally {
+    lib.requestPost = originalRequestPost;
+    if (original === undefined) delete process.env.ENGRAM_PROMPT_CONTEXT;
+    else process.env.ENGRAM_PROMPT_CONTEXT = original;
+  }
+});
+
+test('handleUserPrompt sends working files and renders matches', async () => {
+  const originalRequestPost = lib.requestPost;
+  const originalSessionFiles = lib.readSessionFiles;
+  const originalGitFiles = lib.gitChangedFiles;
+  const original = process.env.ENGRAM_PROMPT_CONTEXT;
+  process.env.ENGRAM_PROMPT_CONTEXT = 'true';
+  lib.readSessionFiles = () => ['internal/a.go', 'internal/b.go'];
+  lib.gitChangedFiles = () => ['internal/b.go', 'README.md'];
+
+  let sent = null;
+  lib.requestPost = async (endpoint, body, timeoutMs, contract) => {
+    sent = { endpoint, body, contract };
+    return {
+      observations: [
+        { id: 7, title: 'x', narrative: 'Sync retries <fast>', matched_files: ['internal/b.go'] },
+        { id: 8, title: 'Unrelated note', narrative: '', matched_files: [] },
+      ],
+    };
+  };
+
+  try {
+    const result = await handleUserPrompt(
+      { Project: 'engram', SessionID: 's1', CWD: '/repo' },
+      { prompt: ' why is sync slow ' },
+    );
+    assert.equal(sent.endpoint, '/api/context/search?limit=5');
+    assert.equal(sent.contract, 'context-search');
+    assert.deepEqual(sent.body, {
+      project: 'engram',
+      query: 'why is sync slow',
+      session_id: 's1',
+      files_being_edited: ['internal/b.go', 'internal/a.go', 'README.md'],
+    });
+    assert.match(result, /<engram-prompt-context>/);
+    assert.match(result, /- #7 \(touches internal\/b\.go\): Sync retries &lt;fast&gt;/);
+    assert.match(result, /- #8: Unrelated note/);
+  } finally {
+    lib.requestPost = originalRequestPost;
+    lib.readSessionFiles = originalSessionFiles;
+    lib.gitChangedFiles = originalGitFiles;
+    if (original === undefined) delete process.env.ENGRAM_PROMPT_CONTEXT;
+    else process.env.ENGRAM_PROMPT_CONTEXT = original;
+  }
+});
+
+test('workingFiles reads session files recorded by pre-tool-use', () => {
+  const sessionID = `user-prompt-files-${process.pid}`;
+  lib.appendSessionFile(sessionID, 'a.go');
+  lib.appendSessionFile(sessionID, 'b.go');
+  assert.deepEqual(lib.readSessionFiles(sessionID), ['a.go', 'b.go']);
+  assert.deepEqual(workingFiles({ SessionID: sessionID, CWD: '' }), ['b.go', 'a.go']);
+  assert.deepEqual(lib.readSessionFiles(''), []);
+  try { fs.unlinkSync(path.join(os.tmpdir(), `engram-signals-${sessionID}.json`)); } catch (_) {}
+});
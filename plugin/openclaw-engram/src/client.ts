@@ -37,6 +37,11 @@ export interface ContextSearchResponse {
    * regardless of query relevance.
    */
   always_inject?: Observation[];
+  /**
+   * Memories pinned via pin_observation. Injected every turn ahead of
+   * query-matched context.
+   */
+  pinned?: Observation[];
 }
 
 export interface SessionInitResponse {
@@ -94,7 +94,11 @@ export async function handleBeforePromptBuild(
     // Render always_inject behavioral rules (appended before the main context block).
     // These are observations marked always_inject=true on the server — they contain
     // behavioral guidance that must be present in every turn regardless of query match.
-    const alwaysInjectObs = Array.isArray(response.always_inject) ? response.always_inject : [];
+    // Pinned memories are rendered in the same standing block, first.
+    const alwaysInjectObs = [
+      ...(Array.isArray(response.pinned) ? response.pinned : []),
+      ...(Array.isArray(response.always_inject) ? response.always_inject : []),
+    ];
     const alwaysInjectBlock = alwaysInjectObs.length > 0
       ? formatAlwaysInject(alwaysInjectObs)
       : '';
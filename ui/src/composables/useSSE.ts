@@ -14,6 +14,9 @@ let reconnectTimeout: number | null = null
 let countdownInterval: number | null = null
 let connectionCount = 0
 let reconnectAttempt = 0
+// ID of the last event received; sent back on reconnect so the server replays
+// anything broadcast while we were disconnected.
+let lastEventId = ''
 
 // Exponential backoff configuration
 const MIN_BACKOFF = 1000      // 1 second
@@ -57,7 +60,10 @@ export function useSSE() {
       return
     }
 
-    eventSource = new EventSource('/api/events')
+    const url = lastEventId
+      ? `/api/events?lastEventId=${encodeURIComponent(lastEventId)}`
+      : '/api/events'
+    eventSource = new EventSource(url)
 
     eventSource.onopen = () => {
       isConnected.value = true
@@ -68,6 +74,9 @@ export function useSSE() {
     }
 
     eventSource.onmessage = (event) => {
+      if (event.lastEventId) {
+        lastEventId = event.lastEventId
+      }
       try {
         const data: SSEEvent = JSON.parse(event.data)
 